@@ -1,15 +1,20 @@
 package application
 
 import (
+	"context"
 	"log"
 	"net/http"
 	"os"
+	"os/signal"
+	"strings"
+	"syscall"
 	"time"
 
 	"github.com/freitasmatheusrn/lifecycle-monitor/assets"
 	configs "github.com/freitasmatheusrn/lifecycle-monitor/configs"
 	"github.com/freitasmatheusrn/lifecycle-monitor/internal/areas"
 	authPkg "github.com/freitasmatheusrn/lifecycle-monitor/internal/auth"
+	"github.com/freitasmatheusrn/lifecycle-monitor/internal/database/postgres"
 	repo "github.com/freitasmatheusrn/lifecycle-monitor/internal/database/postgres/sqlc"
 	redisdb "github.com/freitasmatheusrn/lifecycle-monitor/internal/database/redis"
 	"github.com/freitasmatheusrn/lifecycle-monitor/internal/email/smtp"
@@ -18,10 +23,15 @@ import (
 	"github.com/freitasmatheusrn/lifecycle-monitor/internal/scheduler"
 	"github.com/freitasmatheusrn/lifecycle-monitor/internal/user"
 	"github.com/freitasmatheusrn/lifecycle-monitor/pkg/auth"
+	"github.com/freitasmatheusrn/lifecycle-monitor/pkg/i18n"
+	"github.com/freitasmatheusrn/lifecycle-monitor/pkg/notification"
+	"github.com/freitasmatheusrn/lifecycle-monitor/pkg/notification/twilio"
+	"github.com/freitasmatheusrn/lifecycle-monitor/pkg/notification/webhook"
 	"github.com/freitasmatheusrn/lifecycle-monitor/pkg/parser"
+	"github.com/freitasmatheusrn/lifecycle-monitor/pkg/requestid"
 	"github.com/freitasmatheusrn/lifecycle-monitor/pkg/rest"
 	"github.com/golang-jwt/jwt/v5"
-	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
 	echojwt "github.com/labstack/echo-jwt/v4"
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
@@ -31,8 +41,10 @@ import (
 type Application struct {
 	Config configs.Configs
 	Logger *zap.Logger
-	DB     *pgx.Conn
+	DB     *pgxpool.Pool
 	Redis  *redisdb.Client
+
+	services []backgroundService // started by Mount, stopped by Run on shutdown
 }
 
 func (app *Application) Mount() http.Handler {
@@ -46,30 +58,33 @@ func (app *Application) Mount() http.Handler {
 	e.StaticFS("/assets", assets.Files)
 	e.HTTPErrorHandler = app.CustomErrorHandler
 	e.Use(middleware.Recover())
-	e.Use(middleware.CORSWithConfig(middleware.CORSConfig{
-		AllowOrigins: []string{"http://localhost:3000"},
-		AllowMethods: []string{
-			http.MethodGet,
-			http.MethodPost,
-			http.MethodPut,
-			http.MethodDelete,
-			http.MethodOptions,
-		},
-		AllowHeaders: []string{
-			echo.HeaderOrigin,
-			echo.HeaderContentType,
-			echo.HeaderAccept,
-			echo.HeaderAuthorization,
+	// X-Request-ID (the client's or a generated one) on the response, the logs and the request
+	// context, so the worker pool jobs and SSE streams started by the request carry it too
+	e.Use(middleware.RequestIDWithConfig(middleware.RequestIDConfig{
+		RequestIDHandler: func(c echo.Context, id string) {
+			c.SetRequest(c.Request().WithContext(requestid.WithContext(c.Request().Context(), id)))
 		},
-		AllowCredentials: true,
 	}))
+	cors, err := corsConfig(app.Config.CORSAllowOrigins, app.Config.CORSAllowCredentials)
+	if err != nil {
+		app.Logger.Fatal("invalid CORS_ALLOW_ORIGINS/CORS_ALLOW_CREDENTIALS", zap.Error(err))
+	}
+	e.Use(middleware.CORSWithConfig(cors))
+	// Client IP used by the auth rate limiter and the refresh token checks; forwarding headers are only
+	// honored from TRUSTED_PROXIES, otherwise anyone could pick their own address
+	extractor, err := ipExtractor(app.Config.TrustedProxies)
+	if err != nil {
+		app.Logger.Fatal("invalid TRUSTED_PROXIES", zap.Error(err))
+	}
+	e.IPExtractor = extractor
 	e.Use(middleware.RequestLoggerWithConfig(middleware.RequestLoggerConfig{
-		LogLatency:  true,
-		LogStatus:   true,
-		LogURI:      true,
-		LogMethod:   true,
-		LogError:    true,
-		HandleError: true,
+		LogLatency:   true,
+		LogStatus:    true,
+		LogURI:       true,
+		LogMethod:    true,
+		LogError:     true,
+		LogRequestID: true,
+		HandleError:  true,
 		LogValuesFunc: func(c echo.Context, v middleware.RequestLoggerValues) error {
 
 			status := v.Status
@@ -88,6 +103,7 @@ func (app *Application) Mount() http.Handler {
 					zap.Int("status", status),
 					zap.String("uri", v.URI),
 					zap.String("method", v.Method),
+					zap.String("request_id", v.RequestID),
 				)
 				return nil
 			}
@@ -98,6 +114,7 @@ func (app *Application) Mount() http.Handler {
 					zap.Int("status", status),
 					zap.String("uri", v.URI),
 					zap.String("method", v.Method),
+					zap.String("request_id", v.RequestID),
 				)
 				return nil
 			}
@@ -107,6 +124,7 @@ func (app *Application) Mount() http.Handler {
 				zap.Int("status", status),
 				zap.String("uri", v.URI),
 				zap.String("method", v.Method),
+				zap.String("request_id", v.RequestID),
 			)
 			return nil
 		},
@@ -115,6 +133,8 @@ func (app *Application) Mount() http.Handler {
 		NewClaimsFunc: func(c echo.Context) jwt.Claims {
 			return new(auth.JWTCustomClaims)
 		},
+		// Requests authenticated by an API key (authPkg.APIKeyMiddleware) don't carry a JWT
+		Skipper:     authPkg.IsAPIKeyRequest,
 		SigningKey:  []byte(app.Config.JWTSecret),
 		TokenLookup: "header:Authorization,cookie:access_token",
 		SuccessHandler: func(c echo.Context) {
@@ -134,16 +154,20 @@ func (app *Application) Mount() http.Handler {
 		},
 	}
 	// Initialize repositories and services
-	querier := repo.New(app.DB)
+	querier := repo.New(postgres.WithQueryTimeout(app.DB, time.Duration(app.Config.DBQueryTimeout)*time.Second))
 	tokenRepo := authPkg.NewTokenRepository(app.Redis.Client)
+	apiKeyRepo := authPkg.NewAPIKeyRepository(app.Redis.Client)
 
 	// Initialize auth service
 	authService := authPkg.NewService(
 		querier,
 		tokenRepo,
+		apiKeyRepo,
 		app.Config.JWTSecret,
 		app.Config.AccessTokenExp,
 		app.Config.RefreshTokenExp,
+		authPkg.ParseAnomalyMode(app.Config.RefreshAnomalyMode),
+		app.Logger,
 	)
 
 	userService := user.NewService(querier)
@@ -153,32 +177,146 @@ func (app *Application) Mount() http.Handler {
 	authHandler := authPkg.NewHandler(authService, app.Config.AccessTokenExp, app.Config.RefreshTokenExp)
 
 	// Initialize products crawler and worker pool
-	crawler, err := products.NewCrawler(app.Config.SIEMENS_URL)
+	// Screenshot + HTML of pages whose extraction failed (disabled without CRAWL_FAILURE_CAPTURE_DIR)
+	failureCapture, err := products.NewFailureCapture(app.Config.CrawlFailureCaptureDir, app.Config.CrawlFailureCaptureMax)
+	if err != nil {
+		app.Logger.Fatal("failed to create crawl failure capture", zap.Error(err))
+	}
+
+	// Selectors of the product page; CRAWLER_SELECTORS_FILE overrides them without a deploy when the markup changes
+	selectors, err := products.LoadSelectorConfig(app.Config.CrawlerSelectorsFile)
+	if err != nil {
+		app.Logger.Fatal("failed to load crawler selectors", zap.Error(err))
+	}
+
+	crawler, err := products.NewCrawler(app.Config.SIEMENS_URL, app.Config.MaxOpenPages, failureCapture,
+		app.Config.PhaseOutTerminal, selectors, time.Duration(app.Config.CrawlTimeout)*time.Second, app.Config.CrawlerContextPoolSize, app.Logger)
 	if err != nil {
 		app.Logger.Fatal("failed to create crawler", zap.Error(err))
 	}
+	crawler.DebugMode = app.Config.CrawlerDebug
+	crawler.MaxRetries = app.Config.CrawlRetries
+	crawler.BaseBackoff = time.Duration(app.Config.CrawlRetryBackoff) * time.Second
+	crawler.UserAgents = products.ParseUserAgents(app.Config.CrawlerUserAgents)
+	crawler.ExtraHeaders, err = products.ParseExtraHeaders(app.Config.CrawlerExtraHeaders)
+	if err != nil {
+		app.Logger.Fatal("invalid CRAWLER_EXTRA_HEADERS", zap.Error(err))
+	}
 
 	workerPool := products.NewWorkerPool(crawler, querier, app.Logger, products.WorkerPoolConfig{
 		NumWorkers: 5,
 		QueueSize:  100,
+		MinDelay:   time.Duration(app.Config.CrawlMinDelay) * time.Millisecond,
+		MaxDelay:   time.Duration(app.Config.CrawlMaxDelay) * time.Millisecond,
+
+		RateLimitPerMinute: app.Config.CrawlRateLimit,
+		DedupeInFlight:     app.Config.CrawlDedupeInFlight,
+		JobTimeout:         time.Duration(app.Config.CrawlJobTimeout) * time.Second,
 	})
 
 	if err := workerPool.Start(); err != nil {
 		app.Logger.Fatal("failed to start worker pool", zap.Error(err))
 	}
+	// Stopping the pool also closes the crawler's headless browser
+	app.onShutdown("worker pool", stopWithContext(workerPool.Stop))
+
+	// Codes included/excluded from the scheduled crawl (adjustable via /admin/crawler/code-filter)
+	codeFilter, err := products.NewCodeFilter(products.CodeFilterRules{
+		Include: app.Config.CrawlIncludeCodes,
+		Exclude: app.Config.CrawlExcludeCodes,
+	})
+	if err != nil {
+		app.Logger.Fatal("invalid CRAWL_INCLUDE_CODES/CRAWL_EXCLUDE_CODES", zap.Error(err))
+	}
 
 	// Initialize products service and handler
-	productService := products.NewService(querier, workerPool, app.Config.SIEMENS_URL, app.Logger)
-	productHandler := products.NewHandler(productService)
+	productService := products.NewService(querier, app.DB, workerPool, app.Config.SIEMENS_URL, app.Logger,
+		time.Duration(app.Config.ManualCollectCooldown)*time.Second, codeFilter)
+	// Uploaded import files kept for replay (disabled without IMPORT_ARCHIVE_DIR)
+	importArchive, err := products.NewImportArchive(app.Config.ImportArchiveDir, int64(app.Config.ImportArchiveMaxSize)<<20,
+		time.Duration(app.Config.ImportArchiveRetention)*24*time.Hour, app.Logger)
+	if err != nil {
+		app.Logger.Fatal("failed to create import archive", zap.Error(err))
+	}
+	// Snapshot retention, applied by the scheduler and by POST /admin/snapshots/prune
+	snapshotRetention := products.SnapshotRetention{
+		KeepPerProduct: app.Config.SnapshotKeepPerProduct,
+		RawHTMLAfter:   time.Duration(app.Config.SnapshotRawHTMLRetention) * 24 * time.Hour,
+	}
+	productHandler := products.NewHandler(productService, app.Config.BatchGetMaxSize, app.Config.CrawlerCanaryCode,
+		app.Config.StrictPagination, importArchive, snapshotRetention)
+
+	// SMS is only available when Twilio is configured
+	var sms notification.Notification
+	if app.Config.TwilioAccountSID != "" {
+		sms = twilio.NewSMS(app.Config.TwilioNumber, twilio.InitClient(app.Config.TwilioAccountSID, app.Config.TwilioAuthToken))
+	}
+
+	// Status change routing: which channels receive each lifecycle status change.
+	// With SMS configured and no routes, discontinuations also go by SMS (only them, to avoid spam).
+	routeSpec := app.Config.LifecycleAlertRoutes
+	if sms != nil && len(app.Config.SMSAlertRecipients) > 0 && strings.TrimSpace(routeSpec) == "" {
+		routeSpec = scheduler.TerminalSMSRoute(app.Config.LifecycleAlertDefaultChannels)
+	}
+	alertRoutes, err := scheduler.ParseStatusRoutes(routeSpec, app.Config.LifecycleAlertDefaultChannels,
+		app.Config.PhaseOutTerminal)
+	if err != nil {
+		app.Logger.Fatal("invalid LIFECYCLE_ALERT_ROUTES", zap.Error(err))
+	}
 
 	// Initialize and start scheduler for lifecycle updates
-	lifecycleScheduler := scheduler.NewScheduler(workerPool, productService, app.Logger, email, app.Config.AlertRecipients)
+	lifecycleScheduler := scheduler.NewScheduler(workerPool, productService, app.Logger, email, scheduler.NotificationConfig{
+		StatusRecipients:      app.Config.StatusChangeRecipients,
+		AlertRecipients:       app.Config.AlertRecipients,
+		FallbackRecipients:    app.Config.FallbackRecipients,
+		EmptyRecipientsPolicy: scheduler.EmptyRecipientsPolicy(app.Config.EmptyRecipientsPolicy),
+		Routes:                alertRoutes,
+		SMS:                   sms,
+		SMSRecipients:         app.Config.SMSAlertRecipients,
+		Webhook:               webhook.NewWebhook(10 * time.Second),
+		WebhookURLs:           app.Config.WebhookAlertURLs,
+		Locale:                i18n.ParseLocale(app.Config.NotificationLocale),
+		DigestCron:            app.Config.LifecycleDigestCron,
+		DigestInterval:        time.Duration(app.Config.DigestInterval) * time.Hour,
+		DigestThreshold:       app.Config.DigestThreshold,
+		MinSuccessPercent:     app.Config.CollectMinSuccessPercent,
+		ReplacementDetails:    app.Config.AlertReplacementDetails,
+		LowStockCron:          app.Config.LowStockCron,
+		LowStockRecipients:    app.Config.LowStockRecipients,
+	}, scheduler.CollectionConfig{
+		Backoff: scheduler.BackoffConfig{
+			Threshold: app.Config.CrawlBackoffThreshold,
+			Base:      time.Duration(app.Config.CrawlBackoffBase) * time.Hour,
+			Max:       time.Duration(app.Config.CrawlBackoffMax) * time.Hour,
+		},
+		RunTimeout:   time.Duration(app.Config.CollectRunTimeout) * time.Minute,
+		RecrawlAfter: time.Duration(app.Config.RecrawlAfter) * time.Hour,
+		Pruning:      scheduler.PruneConfig{Cron: app.Config.SnapshotPruneCron, Retention: snapshotRetention},
+	})
 	if err := lifecycleScheduler.Start(app.Config.CronExpression); err != nil {
 		app.Logger.Fatal("failed to start scheduler", zap.Error(err))
 	}
+	// Waits for the running jobs (e.g. a lifecycle update) until the shutdown timeout
+	app.onShutdown("scheduler", func(ctx context.Context) error {
+		select {
+		case <-lifecycleScheduler.Stop().Done():
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	})
+
+	// Post-deploy smoke test of the crawler (opt-in)
+	if app.Config.CrawlerWarmup {
+		if app.Config.CrawlerCanaryCode == "" {
+			app.Logger.Warn("CRAWLER_WARMUP is enabled but CRAWLER_CANARY_CODE is empty, skipping warm-up")
+		} else {
+			go lifecycleScheduler.WarmUp(context.Background(), productService, app.Config.CrawlerCanaryCode)
+		}
+	}
 
 	// Initialize areas service and handler
-	areaService := areas.NewService(querier)
+	areaService := areas.NewService(querier, app.DB)
 	areaHandler := areas.NewHandler(areaService)
 
 	// Initialize pages handler
@@ -188,14 +326,32 @@ func (app *Application) Mount() http.Handler {
 	e.GET("/login", pageHandler.Login)
 	e.GET("/signup", pageHandler.Signup)
 
+	// Liveness and readiness probes for the orchestrator and load balancers (no auth)
+	e.GET("/health", healthHandler)
+	e.GET("/ready", readinessHandler(map[string]dependencyCheck{
+		"database": app.DB.Ping,
+		"redis":    app.Redis.HealthCheck,
+	}))
+
+	// Health of the headless browser, for monitoring (no auth, like a load balancer probe)
+	e.GET("/health/crawler", crawlerHealthHandler(crawler))
+
 	// Public API routes
-	e.POST("/signup", authHandler.Signup)
-	e.POST("/signin", authHandler.Signin)
-	e.POST("/refresh", authHandler.Refresh)
+	// Throttle of the auth routes per client IP, against credential stuffing (AUTH_RATE_LIMIT=0 disables)
+	var authRateLimit []echo.MiddlewareFunc
+	if app.Config.AuthRateLimit > 0 {
+		limiter := authPkg.NewRateLimiter(app.Redis.Client, app.Config.AuthRateLimit, time.Duration(app.Config.AuthRateWindow)*time.Second)
+		authRateLimit = append(authRateLimit, authPkg.RateLimitMiddleware(limiter, app.Logger))
+	}
+	e.POST("/signup", authHandler.Signup, authRateLimit...)
+	e.POST("/signin", authHandler.Signin, authRateLimit...)
+	e.POST("/refresh", authHandler.Refresh, authRateLimit...)
 
 	// Protected routes (JWT required)
 	protected := e.Group("")
 	protected.Use(authPkg.AutoRefreshMiddleware(authService, app.Config.AccessTokenExp, app.Config.RefreshTokenExp, app.Config.JWTSecret))
+	readOnlyRoutes := authPkg.ReadOnlyRoutes{}
+	protected.Use(authPkg.APIKeyMiddleware(authService, readOnlyRoutes))
 	protected.Use(echojwt.WithConfig(config))
 
 	// Protected page routes
@@ -218,19 +374,73 @@ func (app *Application) Mount() http.Handler {
 	// Product API routes
 	protected.POST("/products", productHandler.CreateProduct)
 	protected.GET("/products", productHandler.ListProducts)
+	protected.DELETE("/products", productHandler.DeleteProducts)
+	protected.GET("/products/archived", productHandler.ListArchivedProducts)
+	protected.GET("/products/low-stock", productHandler.ListBelowMinimum)
+	protected.GET("/products/summary", productHandler.GetInventorySummary)
+	protected.GET("/products/compare", productHandler.CompareProducts)
 	protected.POST("/products/import", productHandler.ImportSpreadsheet)
 	protected.POST("/products/import-stream", productHandler.ImportSpreadsheetSSE)
+	protected.POST("/products/import/preview", productHandler.PreviewImport)
+	protected.GET("/products/import/template", productHandler.ImportTemplate)
 	protected.GET("/products/add-stream", productHandler.AddProductsSSE)
 	protected.GET("/products/export", productHandler.ExportSpreadsheet)
+	protected.GET("/products/export.json", productHandler.ExportJSON)
+	protected.GET("/products/export.csv", productHandler.ExportCSV)
+	readOnlyRoutes.Add(protected.POST("/products/batch-get", productHandler.BatchGetProducts))
+	protected.POST("/products/bulk-status", productHandler.BulkOverrideStatus)
 	protected.GET("/products/:id", productHandler.GetProduct)
 	protected.PUT("/products/:id", productHandler.UpdateProduct)
 	protected.DELETE("/products/:id", productHandler.DeleteProduct)
+	protected.POST("/products/:id/restore", productHandler.RestoreProduct)
 	protected.GET("/products/:id/snapshots", productHandler.GetProductSnapshots)
+	protected.GET("/products/:id/history", productHandler.GetSnapshotHistory)
+	protected.GET("/products/:id/replacement-chain", productHandler.GetReplacementChain)
+	protected.POST("/products/:id/collect", productHandler.CollectProduct)
+	protected.POST("/products/:id/refresh", productHandler.RefreshProduct)
+	protected.POST("/products/:id/snooze", productHandler.SnoozeAlerts)
+	protected.DELETE("/products/:id/snooze", productHandler.UnsnoozeAlerts)
+	protected.PUT("/products/:id/expectation", productHandler.SetLifecycleExpectation)
+	protected.DELETE("/products/:id/expectation", productHandler.DeleteLifecycleExpectation)
+
+	// Lifecycle change history
+	protected.GET("/lifecycle-changes/export", productHandler.ExportLifecycleChanges)
+
+	// Admin maintenance routes (restricted to ADMIN_EMAILS)
+	admin := protected.Group("/admin")
+	admin.Use(user.RequireAdmin(app.Config.AdminEmails))
+	admin.POST("/products/rebuild-urls", productHandler.RebuildProductURLs)
+	admin.POST("/products/recrawl-unknown", productHandler.RecrawlUnknownStatus)
+	admin.POST("/crawl/retry", productHandler.RetryFailedCrawls)
+	admin.POST("/snapshots/prune", productHandler.PruneSnapshots)
+	admin.GET("/crawler/stats", productHandler.CrawlerStats)
+	admin.GET("/workers", productHandler.GetWorkers)
+	admin.POST("/workers", productHandler.ResizeWorkers)
+	admin.GET("/ingestion/stats", productHandler.IngestionStats)
+	registerRunHistory(admin, productHandler.ListCollectionRuns)
+	admin.POST("/lifecycle/run", lifecycleRunHandler(lifecycleScheduler))
+	admin.GET("/crawler/selftest", productHandler.CrawlerSelfTest)
+	admin.POST("/imports/:id/replay", productHandler.ReplayImport)
+	admin.GET("/crawler/code-filter", productHandler.CodeFilter)
+	admin.PUT("/crawler/code-filter", productHandler.UpdateCodeFilter)
+	admin.POST("/areas/:id/merge-into/:targetId", areaHandler.MergeArea)
+	admin.GET("/config", app.effectiveConfigHandler(workerPool))
+	admin.GET("/config/export", areaHandler.ExportConfig)
+	admin.POST("/config/import", areaHandler.ImportConfig)
+	admin.POST("/api-keys", authHandler.CreateAPIKey)
+	admin.GET("/api-keys", authHandler.ListAPIKeys)
+	admin.DELETE("/api-keys/:id", authHandler.RevokeAPIKey)
 
-	e.Logger.Fatal(e.Start(":" + app.Config.WebServerPort))
 	return e
 }
 
+// registerRunHistory serves the scheduled run history at /lifecycle/runs, next to the manual
+// POST /lifecycle/run, and at /collection-runs, where it was first published
+func registerRunHistory(admin *echo.Group, listRuns echo.HandlerFunc) {
+	admin.GET("/lifecycle/runs", listRuns)
+	admin.GET("/collection-runs", listRuns)
+}
+
 func (app *Application) Run(h http.Handler) error {
 	srv := &http.Server{
 		Addr:         ":" + app.Config.WebServerPort,
@@ -240,12 +450,33 @@ func (app *Application) Run(h http.Handler) error {
 		IdleTimeout:  time.Minute,
 	}
 
-	log.Printf("server has started at addr %s", app.Config.WebServerPort)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
-	// Check if TLS certificates exist for local development
-	if _, err := os.Stat("server.crt"); err == nil {
-		return srv.ListenAndServeTLS("server.crt", "server.key")
+	serveErr := make(chan error, 1)
+	go func() {
+		// Behind a TLS-terminating proxy (Dokku's nginx) or in local development TLS stays disabled
+		if app.Config.TLSEnabled {
+			log.Printf("server has started at addr %s (TLS)", app.Config.WebServerPort)
+			serveErr <- srv.ListenAndServeTLS(app.Config.TLSCertFile, app.Config.TLSKeyFile)
+			return
+		}
+		log.Printf("server has started at addr %s", app.Config.WebServerPort)
+		serveErr <- srv.ListenAndServe()
+	}()
+
+	select {
+	case err := <-serveErr:
+		// The server never started (e.g. port in use): still stop what Mount started
+		app.stopServices(time.Duration(app.Config.ShutdownTimeout) * time.Second)
+		return err
+	case <-ctx.Done():
 	}
-	// In production (Dokku), nginx handles TLS
-	return srv.ListenAndServe()
+
+	// A second signal during the shutdown kills the process
+	stop()
+	app.Logger.Info("shutdown signal received, draining connections")
+	app.shutdown(srv, time.Duration(app.Config.ShutdownDrainTimeout)*time.Second, time.Duration(app.Config.ShutdownTimeout)*time.Second)
+	app.Logger.Info("server stopped")
+	return nil
 }
@@ -0,0 +1,47 @@
+package application
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// ipExtractor decides where c.RealIP() reads the client address from. Without trusted proxies
+// it's the connection address, so X-Forwarded-For/X-Real-IP sent by the client are ignored.
+// With them, X-Forwarded-For is followed only through hops inside the listed ranges; echo's
+// default trust of loopback, link-local and private networks is turned off.
+func ipExtractor(trustedProxies []string) (echo.IPExtractor, error) {
+	var ranges []echo.TrustOption
+	for _, entry := range trustedProxies {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		cidr := entry
+		if !strings.Contains(cidr, "/") {
+			if ip := net.ParseIP(cidr); ip != nil && ip.To4() != nil {
+				cidr += "/32"
+			} else {
+				cidr += "/128"
+			}
+		}
+		_, ipNet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q: expected an IP or a CIDR range", entry)
+		}
+		ranges = append(ranges, echo.TrustIPRange(ipNet))
+	}
+
+	if len(ranges) == 0 {
+		return echo.ExtractIPDirect(), nil
+	}
+
+	options := append([]echo.TrustOption{
+		echo.TrustLoopback(false),
+		echo.TrustLinkLocal(false),
+		echo.TrustPrivateNet(false),
+	}, ranges...)
+	return echo.ExtractIPFromXFFHeader(options...), nil
+}
@@ -0,0 +1,99 @@
+package application
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	authPkg "github.com/freitasmatheusrn/lifecycle-monitor/internal/auth"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+)
+
+// countingLimiter allows limit attempts per key
+type countingLimiter struct {
+	limit    int
+	attempts map[string]int
+}
+
+func (l *countingLimiter) Allow(ctx context.Context, key string) (bool, time.Duration, error) {
+	l.attempts[key]++
+	return l.attempts[key] <= l.limit, time.Minute, nil
+}
+
+func TestIPExtractorRateLimit(t *testing.T) {
+	tests := []struct {
+		name           string
+		trustedProxies []string
+		remoteAddr     string
+		forwardedFor   []string // one per request
+		wantStatus     []int
+	}{
+		{
+			name:         "spoofed header shares the caller's bucket",
+			remoteAddr:   "203.0.113.7:5000",
+			forwardedFor: []string{"198.51.100.1", "198.51.100.2", "198.51.100.3"},
+			wantStatus:   []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests},
+		},
+		{
+			name:           "spoofed header from an untrusted peer is ignored",
+			trustedProxies: []string{"10.0.0.0/8"},
+			remoteAddr:     "203.0.113.7:5000",
+			forwardedFor:   []string{"198.51.100.1", "198.51.100.2", "198.51.100.3"},
+			wantStatus:     []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests},
+		},
+		{
+			name:           "trusted proxy forwards each client on its own",
+			trustedProxies: []string{"10.0.0.0/8"},
+			remoteAddr:     "10.0.0.2:5000",
+			forwardedFor:   []string{"198.51.100.1", "198.51.100.2", "198.51.100.3"},
+			wantStatus:     []int{http.StatusOK, http.StatusOK, http.StatusOK},
+		},
+		{
+			name:           "client prepending to the chain behind a trusted proxy",
+			trustedProxies: []string{"10.0.0.2"},
+			remoteAddr:     "10.0.0.2:5000",
+			forwardedFor:   []string{"198.51.100.1, 203.0.113.7", "198.51.100.2, 203.0.113.7", "198.51.100.3, 203.0.113.7"},
+			wantStatus:     []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			extractor, err := ipExtractor(tt.trustedProxies)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			app := &Application{Logger: zap.NewNop()}
+			e := echo.New()
+			e.HTTPErrorHandler = app.CustomErrorHandler
+			e.IPExtractor = extractor
+			limiter := &countingLimiter{limit: 2, attempts: map[string]int{}}
+			e.POST("/signin", func(c echo.Context) error {
+				return c.NoContent(http.StatusOK)
+			}, authPkg.RateLimitMiddleware(limiter, zap.NewNop()))
+
+			for i, xff := range tt.forwardedFor {
+				req := httptest.NewRequest(http.MethodPost, "/signin", nil)
+				req.RemoteAddr = tt.remoteAddr
+				req.Header.Set(echo.HeaderXForwardedFor, xff)
+				req.Header.Set(echo.HeaderXRealIP, xff)
+				rec := httptest.NewRecorder()
+				e.ServeHTTP(rec, req)
+
+				if rec.Code != tt.wantStatus[i] {
+					t.Errorf("request %d: expected status %d, got %d", i, tt.wantStatus[i], rec.Code)
+				}
+			}
+		})
+	}
+}
+
+func TestIPExtractorInvalidProxy(t *testing.T) {
+	if _, err := ipExtractor([]string{"10.0.0.0/8", "proxy.internal"}); err == nil {
+		t.Fatal("expected error for an entry that is neither an IP nor a CIDR")
+	}
+}
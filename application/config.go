@@ -0,0 +1,56 @@
+package application
+
+import (
+	"net/http"
+
+	authPkg "github.com/freitasmatheusrn/lifecycle-monitor/internal/auth"
+	"github.com/freitasmatheusrn/lifecycle-monitor/internal/products"
+	"github.com/freitasmatheusrn/lifecycle-monitor/pkg/i18n"
+	"github.com/labstack/echo/v4"
+)
+
+// EffectiveConfig is the response of GET /admin/config
+type EffectiveConfig struct {
+	Config  map[string]any `json:"config"`  // valores carregados, por variavel de ambiente, com segredos redigidos
+	Derived DerivedConfig  `json:"derived"` // valores efetivos apos defaults e correcoes aplicados pelo codigo
+}
+
+// DerivedConfig holds the values actually in use, which may differ from the raw configuration
+type DerivedConfig struct {
+	Workers            int    `json:"workers"`
+	QueueSize          int    `json:"queue_size"`
+	MaxOpenPages       int    `json:"max_open_pages"`
+	DefaultPageSize    int    `json:"default_page_size"`
+	MaxPageSize        int    `json:"max_page_size"`
+	BatchGetMaxSize    int    `json:"batch_get_max_size"`
+	RefreshAnomalyMode string `json:"refresh_anomaly_mode"`
+	NotificationLocale string `json:"notification_locale"`
+	FailureCapture     bool   `json:"failure_capture"`
+}
+
+// effectiveConfigHandler handles GET /admin/config
+// Shows what the running instance loaded, to confirm whether an env var took effect
+func (app *Application) effectiveConfigHandler(workerPool *products.WorkerPool) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		stats := workerPool.Stats()
+		derived := DerivedConfig{
+			Workers:            stats.Workers,
+			QueueSize:          stats.QueueSize,
+			MaxOpenPages:       max(app.Config.MaxOpenPages, 1),
+			DefaultPageSize:    products.DefaultPageSize,
+			MaxPageSize:        products.MaxPageSize,
+			BatchGetMaxSize:    app.Config.BatchGetMaxSize,
+			RefreshAnomalyMode: string(authPkg.ParseAnomalyMode(app.Config.RefreshAnomalyMode)),
+			NotificationLocale: string(i18n.ParseLocale(app.Config.NotificationLocale)),
+			FailureCapture:     app.Config.CrawlFailureCaptureDir != "",
+		}
+		if stats.Pages != nil {
+			derived.MaxOpenPages = stats.Pages.Max
+		}
+
+		return c.JSON(http.StatusOK, EffectiveConfig{
+			Config:  app.Config.Sanitized(),
+			Derived: derived,
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package application
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/freitasmatheusrn/lifecycle-monitor/internal/products"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+)
+
+// corsConfig builds the CORS middleware config from the configured origins.
+// Browsers reject "Access-Control-Allow-Origin: *" on credentialed requests, so a wildcard
+// together with credentials is refused here instead of failing silently in the browser.
+func corsConfig(origins []string, allowCredentials bool) (middleware.CORSConfig, error) {
+	allowOrigins := make([]string, 0, len(origins))
+	for _, origin := range origins {
+		origin = strings.TrimRight(strings.TrimSpace(origin), "/")
+		if origin == "" {
+			continue
+		}
+		if origin == "*" && allowCredentials {
+			return middleware.CORSConfig{}, errors.New(`origin "*" can't be used with credentials; list the allowed origins or disable CORS_ALLOW_CREDENTIALS`)
+		}
+		allowOrigins = append(allowOrigins, origin)
+	}
+	if len(allowOrigins) == 0 {
+		return middleware.CORSConfig{}, errors.New("no CORS origin configured")
+	}
+
+	return middleware.CORSConfig{
+		AllowOrigins: allowOrigins,
+		AllowMethods: []string{
+			http.MethodGet,
+			http.MethodPost,
+			http.MethodPut,
+			http.MethodDelete,
+			http.MethodOptions,
+		},
+		AllowHeaders: []string{
+			echo.HeaderOrigin,
+			echo.HeaderContentType,
+			echo.HeaderAccept,
+			echo.HeaderAuthorization,
+			products.StrictPaginationHeader,
+		},
+		AllowCredentials: allowCredentials,
+	}, nil
+}
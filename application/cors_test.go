@@ -0,0 +1,40 @@
+package application
+
+import "testing"
+
+func TestCorsConfig(t *testing.T) {
+	tests := []struct {
+		name        string
+		origins     []string
+		credentials bool
+		wantErr     bool
+		wantOrigins []string
+	}{
+		{name: "explicit origins with credentials", origins: []string{" https://app.example.com/ ", ""}, credentials: true, wantOrigins: []string{"https://app.example.com"}},
+		{name: "wildcard without credentials", origins: []string{"*"}, wantOrigins: []string{"*"}},
+		{name: "wildcard with credentials", origins: []string{"https://app.example.com", "*"}, credentials: true, wantErr: true},
+		{name: "no origins", origins: []string{" "}, credentials: true, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := corsConfig(tt.origins, tt.credentials)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if len(cfg.AllowOrigins) != len(tt.wantOrigins) || cfg.AllowOrigins[0] != tt.wantOrigins[0] {
+				t.Errorf("expected origins %v, got %v", tt.wantOrigins, cfg.AllowOrigins)
+			}
+			if cfg.AllowCredentials != tt.credentials {
+				t.Errorf("expected AllowCredentials=%v, got %v", tt.credentials, cfg.AllowCredentials)
+			}
+		})
+	}
+}
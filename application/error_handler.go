@@ -1,12 +1,12 @@
 package application
 
 import (
-	"fmt"
 	"log"
 	"net/http"
 	"strings"
 
 	"github.com/freitasmatheusrn/lifecycle-monitor/pkg/htmx"
+	"github.com/freitasmatheusrn/lifecycle-monitor/pkg/i18n"
 	"github.com/freitasmatheusrn/lifecycle-monitor/pkg/rest"
 	"github.com/labstack/echo/v4"
 )
@@ -18,11 +18,16 @@ func (a *Application) CustomErrorHandler(err error, c echo.Context) {
 
 	var code int
 	var message string
+	requestID := c.Response().Header().Get(echo.HeaderXRequestID)
+
+	// Mensagens sao traduzidas conforme o Accept-Language (portugues por padrao)
+	locale := i18n.FromAcceptLanguage(c.Request().Header.Get("Accept-Language"))
+	c.Response().Header().Set("Content-Language", string(locale))
 
 	if apiErr, ok := err.(*rest.ApiErr); ok {
 		code = apiErr.Code
-		message = apiErr.Message
-		log.Printf("code: %v, message: %s, causes: %v", apiErr.Code, apiErr.Message, apiErr.Causes)
+		message = apiErr.Localize(locale).Message
+		log.Printf("request_id: %s, code: %v, message: %s, causes: %v", requestID, apiErr.Code, apiErr.Message, apiErr.Causes)
 	} else if he, ok := err.(*echo.HTTPError); ok {
 		code = he.Code
 		switch he.Code {
@@ -35,10 +40,10 @@ func (a *Application) CustomErrorHandler(err error, c echo.Context) {
 				message = http.StatusText(he.Code)
 			}
 		}
-		log.Printf("code: %v, message: %s", code, message)
+		log.Printf("request_id: %s, code: %v, message: %s", requestID, code, message)
 	} else {
 		code = http.StatusInternalServerError
-		message = "Erro interno do servidor"
+		message = i18n.T(locale, "Erro interno do servidor")
 		c.Logger().Error(err)
 	}
 
@@ -50,8 +55,7 @@ func (a *Application) CustomErrorHandler(err error, c echo.Context) {
 			return
 		}
 
-		trigger := fmt.Sprintf(`{"makeToast": {"level": "danger", "message": "%s"}}`, message)
-		c.Response().Header().Set("HX-Trigger", trigger)
+		htmx.TriggerToast(c, "danger", message)
 		c.Response().Header().Set("HX-Reswap", "none")
 		c.NoContent(code)
 		return
@@ -68,9 +72,10 @@ func (a *Application) CustomErrorHandler(err error, c echo.Context) {
 
 	// JSON response for API clients
 	apiErr := &rest.ApiErr{
-		Message: message,
-		Err:     http.StatusText(code),
-		Code:    code,
+		Message:   message,
+		Err:       http.StatusText(code),
+		Code:      code,
+		RequestID: requestID,
 	}
 	c.JSON(code, apiErr)
 }
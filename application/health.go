@@ -0,0 +1,84 @@
+package application
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/freitasmatheusrn/lifecycle-monitor/internal/products"
+	"github.com/labstack/echo/v4"
+)
+
+// crawlerHealthTimeout bounds the blank page check, so a hung browser still gets an answer
+const crawlerHealthTimeout = 10 * time.Second
+
+// readinessTimeout bounds each dependency ping of GET /ready
+const readinessTimeout = 3 * time.Second
+
+// dependencyCheck pings a dependency the server needs to serve requests (database, Redis)
+type dependencyCheck func(ctx context.Context) error
+
+// Readiness is the response of GET /ready
+type Readiness struct {
+	Status string            `json:"status"` // "ready" ou "unavailable"
+	Checks map[string]string `json:"checks"` // "ok" or the error of each dependency
+}
+
+// healthHandler handles GET /health
+// Liveness: answers 200 while the process serves requests, without touching any dependency
+func healthHandler(c echo.Context) error {
+	return c.JSON(http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// readinessHandler handles GET /ready
+// Pings every dependency and answers 503 with the failing ones, so load balancers stop routing
+// traffic to an instance that lost the database or Redis
+func readinessHandler(checks map[string]dependencyCheck) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		readiness := Readiness{Status: "ready", Checks: make(map[string]string, len(checks))}
+		code := http.StatusOK
+		for name, check := range checks {
+			ctx, cancel := context.WithTimeout(c.Request().Context(), readinessTimeout)
+			err := check(ctx)
+			cancel()
+
+			if err != nil {
+				readiness.Checks[name] = err.Error()
+				readiness.Status = "unavailable"
+				code = http.StatusServiceUnavailable
+				continue
+			}
+			readiness.Checks[name] = "ok"
+		}
+
+		return c.JSON(code, readiness)
+	}
+}
+
+// CrawlerHealth is the response of GET /health/crawler
+type CrawlerHealth struct {
+	Status string                `json:"status"` // "ok" ou "unavailable"
+	Error  string                `json:"error,omitempty"`
+	Stats  products.CrawlerStats `json:"stats"`
+}
+
+// crawlerHealthHandler handles GET /health/crawler
+// Opens a blank page in the headless browser and reports the crawl counters, answering 503
+// when the browser is down so monitoring can catch it before the scheduled run
+func crawlerHealthHandler(crawler *products.Crawler) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx, cancel := context.WithTimeout(c.Request().Context(), crawlerHealthTimeout)
+		defer cancel()
+
+		health := CrawlerHealth{Status: "ok"}
+		code := http.StatusOK
+		if err := crawler.HealthCheck(ctx); err != nil {
+			health.Status = "unavailable"
+			health.Error = err.Error()
+			code = http.StatusServiceUnavailable
+		}
+		health.Stats = crawler.Stats()
+
+		return c.JSON(code, health)
+	}
+}
@@ -0,0 +1,51 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+func TestReadinessHandler(t *testing.T) {
+	up := func(ctx context.Context) error { return nil }
+	down := func(ctx context.Context) error { return errors.New("connection refused") }
+
+	tests := []struct {
+		name     string
+		checks   map[string]dependencyCheck
+		code     int
+		status   string
+		database string
+		redis    string
+	}{
+		{name: "all up", checks: map[string]dependencyCheck{"database": up, "redis": up}, code: http.StatusOK, status: "ready", database: "ok", redis: "ok"},
+		{name: "redis down", checks: map[string]dependencyCheck{"database": up, "redis": down}, code: http.StatusServiceUnavailable, status: "unavailable", database: "ok", redis: "connection refused"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			c := echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/ready", nil), rec)
+
+			if err := readinessHandler(tt.checks)(c); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if rec.Code != tt.code {
+				t.Errorf("expected %d, got %d", tt.code, rec.Code)
+			}
+
+			var readiness Readiness
+			if err := json.Unmarshal(rec.Body.Bytes(), &readiness); err != nil {
+				t.Fatalf("invalid body: %v", err)
+			}
+			if readiness.Status != tt.status || readiness.Checks["database"] != tt.database || readiness.Checks["redis"] != tt.redis {
+				t.Errorf("unexpected readiness: %+v", readiness)
+			}
+		})
+	}
+}
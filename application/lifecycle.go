@@ -0,0 +1,38 @@
+package application
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/freitasmatheusrn/lifecycle-monitor/internal/scheduler"
+	"github.com/freitasmatheusrn/lifecycle-monitor/pkg/htmx"
+	"github.com/freitasmatheusrn/lifecycle-monitor/pkg/rest"
+	"github.com/labstack/echo/v4"
+)
+
+// lifecycleRunner starts a lifecycle update run on demand (the scheduler)
+type lifecycleRunner interface {
+	RunNow() (string, error)
+}
+
+// LifecycleRunStarted is the response of POST /admin/lifecycle/run
+type LifecycleRunStarted struct {
+	RunID string `json:"run_id"`
+}
+
+// lifecycleRunHandler handles POST /admin/lifecycle/run
+// Starts the lifecycle update job without waiting for cron; 409 while a run is in progress
+func lifecycleRunHandler(runner lifecycleRunner) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		runID, err := runner.RunNow()
+		if errors.Is(err, scheduler.ErrRunInProgress) {
+			return rest.NewConflictError("ja existe uma coleta do lifecycle em andamento")
+		}
+		if err != nil {
+			return rest.NewInternalServerError("erro ao iniciar a coleta do lifecycle")
+		}
+
+		htmx.TriggerToast(c, "success", "Coleta do lifecycle iniciada")
+		return c.JSON(http.StatusAccepted, LifecycleRunStarted{RunID: runID})
+	}
+}
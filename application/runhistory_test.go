@@ -0,0 +1,25 @@
+package application
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+func TestRegisterRunHistory(t *testing.T) {
+	e := echo.New()
+	registerRunHistory(e.Group("/admin"), func(c echo.Context) error {
+		return c.String(http.StatusOK, "runs:"+c.QueryParam("limit"))
+	})
+
+	for _, path := range []string{"/admin/lifecycle/runs", "/admin/collection-runs"} {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path+"?limit=5", nil))
+
+		if rec.Code != http.StatusOK || rec.Body.String() != "runs:5" {
+			t.Errorf("%s: expected the run history, got %d %q", path, rec.Code, rec.Body.String())
+		}
+	}
+}
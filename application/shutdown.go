@@ -0,0 +1,68 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// backgroundService is something Mount started that must be stopped on shutdown
+type backgroundService struct {
+	name string
+	stop func(ctx context.Context) error
+}
+
+// onShutdown registers a service started by Mount. Services are stopped in reverse order,
+// so the ones started last (and depending on the others) stop first.
+func (app *Application) onShutdown(name string, stop func(ctx context.Context) error) {
+	app.services = append(app.services, backgroundService{name: name, stop: stop})
+}
+
+// stopWithContext adapts a blocking Stop to the shutdown deadline. On timeout the Stop keeps
+// running in the background and the shutdown moves on.
+func stopWithContext(stop func() error) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		done := make(chan error, 1)
+		go func() { done <- stop() }()
+		select {
+		case err := <-done:
+			return err
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// shutdown stops accepting requests and waits up to drain for the in-flight ones (SSE streams
+// included) before closing the remaining connections, then stops the background services.
+func (app *Application) shutdown(srv *http.Server, drain, timeout time.Duration) {
+	drainCtx, cancel := context.WithTimeout(context.Background(), drain)
+	defer cancel()
+	if err := srv.Shutdown(drainCtx); err != nil {
+		app.Logger.Warn("connections still open after the drain timeout, closing them", zap.Error(err))
+		if err := srv.Close(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			app.Logger.Error("failed to close server", zap.Error(err))
+		}
+	}
+
+	app.stopServices(timeout)
+}
+
+// stopServices stops the background services in reverse start order, all within timeout
+func (app *Application) stopServices(timeout time.Duration) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	for i := len(app.services) - 1; i >= 0; i-- {
+		service := app.services[i]
+		if err := service.stop(ctx); err != nil {
+			app.Logger.Error("failed to stop "+service.name, zap.Error(err))
+			continue
+		}
+		app.Logger.Info(service.name + " stopped")
+	}
+	app.services = nil
+}
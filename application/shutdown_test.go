@@ -0,0 +1,59 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func TestStopServices_ReverseOrder(t *testing.T) {
+	app := &Application{Logger: zap.NewNop()}
+	var stopped []string
+	for _, name := range []string{"worker pool", "scheduler"} {
+		app.onShutdown(name, func(ctx context.Context) error {
+			stopped = append(stopped, name)
+			return nil
+		})
+	}
+	app.onShutdown("failing", func(ctx context.Context) error {
+		stopped = append(stopped, "failing")
+		return errors.New("boom")
+	})
+
+	app.stopServices(time.Second)
+
+	want := []string{"failing", "scheduler", "worker pool"}
+	if len(stopped) != len(want) {
+		t.Fatalf("expected %v, got %v", want, stopped)
+	}
+	for i := range want {
+		if stopped[i] != want[i] {
+			t.Fatalf("expected %v, got %v", want, stopped)
+		}
+	}
+	if app.services != nil {
+		t.Errorf("expected the services to be cleared after stopping")
+	}
+}
+
+func TestStopWithContext_Timeout(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	stop := stopWithContext(func() error {
+		<-release
+		return nil
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := stop(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the shutdown deadline to cut a blocked Stop, got %v", err)
+	}
+
+	if err := stopWithContext(func() error { return nil })(context.Background()); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"log/slog"
 	"os"
+	"time"
 
 	application "github.com/freitasmatheusrn/lifecycle-monitor/application"
 	configs "github.com/freitasmatheusrn/lifecycle-monitor/configs"
@@ -11,12 +12,22 @@ import (
 	redisdb "github.com/freitasmatheusrn/lifecycle-monitor/internal/database/redis"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
+	"gopkg.in/natefinch/lumberjack.v2"
 )
 
 func main() {
 	config, err := configs.LoadConfig(".")
 	if err != nil {
-		panic(err)
+		slog.Error("failed to load config", "error", err)
+		os.Exit(1)
+	}
+	// Fail fast listing every missing or invalid setting, before anything is constructed
+	if err := config.Validate(); err != nil {
+		slog.Error("invalid configuration", "error", err)
+		os.Exit(1)
+	}
+	for _, warning := range config.Warnings() {
+		slog.Warn("configuration warning", "warning", warning)
 	}
 
 	// Use DATABASE_URL if available (Dokku), otherwise build from individual params
@@ -28,21 +39,36 @@ func main() {
 			"%s://%s:%s@%s:%s/%s", config.DBDriver, config.DBUser, config.DBPassword, config.DBHost, config.DBPort, config.DBName)
 	}
 
-	db, err := postgres.Init(dsn)
+	db, err := postgres.Init(dsn, postgres.PoolConfig{
+		MaxConns:        config.DBMaxConns,
+		MinConns:        config.DBMinConns,
+		MaxConnLifetime: time.Duration(config.DBMaxConnLifetime) * time.Minute,
+		MaxConnIdleTime: time.Duration(config.DBMaxConnIdleTime) * time.Minute,
+	})
 	if err != nil {
 		panic("error starting db: " + err.Error())
 	}
+	defer db.Close()
 
 	// Use REDIS_URL if available (Dokku), otherwise build from individual params
+	redisPool := redisdb.PoolConfig{
+		PoolSize:     config.RedisPoolSize,
+		MinIdleConns: config.RedisMinIdleConns,
+		DialTimeout:  time.Duration(config.RedisDialTimeout) * time.Second,
+		ReadTimeout:  time.Duration(config.RedisReadTimeout) * time.Second,
+		WriteTimeout: time.Duration(config.RedisWriteTimeout) * time.Second,
+	}
+
 	var redisClient *redisdb.Client
 	if config.RedisURL != "" {
-		redisClient, err = redisdb.NewClientFromURL(config.RedisURL)
+		redisClient, err = redisdb.NewClientFromURL(config.RedisURL, redisPool)
 	} else {
 		redisClient, err = redisdb.NewClient(redisdb.Config{
 			Host:     config.RedisHost,
 			Port:     config.RedisPort,
 			Password: config.RedisPassword,
 			DB:       config.RedisDB,
+			Pool:     redisPool,
 		})
 	}
 	if err != nil {
@@ -65,22 +91,31 @@ func main() {
 		EncodeCaller:   zapcore.ShortCallerEncoder,
 	}
 
-	// Console core: all levels (Info+) to stdout
+	// Console core: all levels (Info+) to stdout; Debug+ with CRAWLER_DEBUG so the crawler diagnostics show up
+	consoleLevel := zap.InfoLevel
+	if config.CrawlerDebug {
+		consoleLevel = zap.DebugLevel
+	}
 	consoleEncoder := zapcore.NewConsoleEncoder(encoderConfig)
 	consoleCore := zapcore.NewCore(
 		consoleEncoder,
 		zapcore.AddSync(os.Stdout),
-		zap.InfoLevel,
+		consoleLevel,
 	)
 
 	var logger *zap.Logger
 
 	// If log path is configured, add file core for Warn+ only
 	if config.LogPath != "" {
-		logFile, err := os.OpenFile(config.LogPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
-		if err != nil {
-			panic("failed to open log file: " + err.Error())
+		// Rotating writer so the file doesn't grow unbounded on long-running deployments
+		logFile := &lumberjack.Logger{
+			Filename:   config.LogPath,
+			MaxSize:    config.LogMaxSize,
+			MaxAge:     config.LogMaxAge,
+			MaxBackups: config.LogMaxBackups,
+			Compress:   config.LogCompress,
 		}
+		defer logFile.Close()
 
 		// File encoder without colors
 		fileEncoderConfig := encoderConfig
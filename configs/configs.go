@@ -1,6 +1,10 @@
 package configs
 
-import "github.com/spf13/viper"
+import (
+	"fmt"
+
+	"github.com/spf13/viper"
+)
 
 type Configs struct {
 	DatabaseURL        string `mapstructure:"DATABASE_URL"`
@@ -12,13 +16,26 @@ type Configs struct {
 	DBUser             string `mapstructure:"DB_USER"`
 	DBPassword         string `mapstructure:"DB_PASSWORD"`
 	WebServerPort      string `mapstructure:"WEB_SERVER_PORT"`
+	TLSEnabled         bool   `mapstructure:"TLS_ENABLED"`   // Serve HTTPS directly; keep off behind a TLS-terminating proxy
+	TLSCertFile        string `mapstructure:"TLS_CERT_FILE"` // Default: server.crt
+	TLSKeyFile         string `mapstructure:"TLS_KEY_FILE"`  // Default: server.key
+	ShutdownDrainTimeout int  `mapstructure:"SHUTDOWN_DRAIN_TIMEOUT"` // Seconds in-flight requests (SSE streams included) get to finish on SIGINT/SIGTERM
+	ShutdownTimeout    int    `mapstructure:"SHUTDOWN_TIMEOUT"` // Seconds to stop the scheduler and the worker pool after the drain
 	JWTSecret          string `mapstructure:"JWT_SECRET"`
 	AccessTokenExp     int    `mapstructure:"ACCESS_TOKEN_EXP"`  // Default: 900 (15 min)
 	RefreshTokenExp    int    `mapstructure:"REFRESH_TOKEN_EXP"` // Default: 604800 (7 days)
+	RefreshAnomalyMode string `mapstructure:"REFRESH_ANOMALY_MODE"` // off, log, reauth or revoke (default: log)
+	AuthRateLimit      int    `mapstructure:"AUTH_RATE_LIMIT"`  // Attempts per client IP on /signin, /signup and /refresh in each window (0 disables)
+	AuthRateWindow     int    `mapstructure:"AUTH_RATE_WINDOW"` // Seconds
 	RedisHost          string `mapstructure:"REDIS_HOST"`
 	RedisPort          string `mapstructure:"REDIS_PORT"`
 	RedisPassword      string `mapstructure:"REDIS_PASSWORD"`
 	RedisDB            int    `mapstructure:"REDIS_DB"`
+	RedisPoolSize      int    `mapstructure:"REDIS_POOL_SIZE"`      // Max connections in the Redis pool
+	RedisMinIdleConns  int    `mapstructure:"REDIS_MIN_IDLE_CONNS"` // Idle connections kept open
+	RedisDialTimeout   int    `mapstructure:"REDIS_DIAL_TIMEOUT"`   // Seconds
+	RedisReadTimeout   int    `mapstructure:"REDIS_READ_TIMEOUT"`   // Seconds
+	RedisWriteTimeout  int    `mapstructure:"REDIS_WRITE_TIMEOUT"`  // Seconds
 	TwilioAccountSID   string `mapstructure:"TWILIO_ACCOUNT_SID"`
 	TwilioAuthToken    string `mapstructure:"TWILIO_AUTH_TOKEN"`
 	TwilioApiKey       string `mapstructure:"TWILIO_API_KEY"`
@@ -32,8 +49,71 @@ type Configs struct {
 	SMTP_USER          string `mapstructure:"SMTP_USER"`
 	SMTP_PASS          string `mapstructure:"SMTP_PASS"`
 	CronExpression     string   `mapstructure:"CRON_EXPRESSION"` // Cron expression for lifecycle update job (6 fields with seconds)
+	CollectRunTimeout  int      `mapstructure:"COLLECT_RUN_TIMEOUT"` // Minutes a lifecycle update run may take; products left over go first on the next run
+	RecrawlAfter       int      `mapstructure:"RECRAWL_AFTER"` // Hours since a code's last snapshot before the scheduler crawls it again (0 crawls every code each run)
+	CollectMinSuccessPercent int `mapstructure:"COLLECT_MIN_SUCCESS_PERCENT"` // Alert when a run collects less than this % of its products, or of the previous run's total (0 disables)
+	SnapshotPruneCron  string   `mapstructure:"SNAPSHOT_PRUNE_CRON"` // Cron expression (6 fields) of the snapshot retention job (empty disables)
+	SnapshotKeepPerProduct int  `mapstructure:"SNAPSHOT_KEEP_PER_PRODUCT"` // Snapshots kept per product; older ones repeating the previous collection are deleted (0 keeps all)
+	SnapshotRawHTMLRetention int `mapstructure:"SNAPSHOT_RAW_HTML_RETENTION"` // Days the page HTML of a snapshot is kept; the row stays (0 keeps it forever)
 	LogPath            string   `mapstructure:"LOG_PATH"`        // Path to log file (e.g., "/var/log/scheduler.log")
+	LogMaxSize         int      `mapstructure:"LOG_MAX_SIZE"`    // Max size in MB before the log file is rotated
+	LogMaxAge          int      `mapstructure:"LOG_MAX_AGE"`     // Max days to keep rotated log files (0 keeps them forever)
+	LogMaxBackups      int      `mapstructure:"LOG_MAX_BACKUPS"` // Max number of rotated log files to keep (0 keeps all)
+	LogCompress        bool     `mapstructure:"LOG_COMPRESS"`    // Gzip rotated log files
 	AlertRecipients    []string `mapstructure:"ALERT_RECIPIENTS"` // Email recipients for error alerts
+	StatusChangeRecipients []string `mapstructure:"STATUS_CHANGE_RECIPIENTS"` // Email recipients for lifecycle status change reports
+	FallbackRecipients []string `mapstructure:"FALLBACK_RECIPIENTS"` // Used when a recipient list is empty and EMPTY_RECIPIENTS_POLICY is "fallback"
+	EmptyRecipientsPolicy string `mapstructure:"EMPTY_RECIPIENTS_POLICY"` // "warn" (log and drop) or "fallback"
+	LifecycleAlertRoutes string `mapstructure:"LIFECYCLE_ALERT_ROUTES"` // Channels per status or transition, e.g. "Prod. Discont.=email,sms;Active>Phase Out Announce=webhook;terminal=sms"
+	LifecycleAlertDefaultChannels []string `mapstructure:"LIFECYCLE_ALERT_DEFAULT_CHANNELS"` // Channels for status changes without a route
+	LifecycleDigestCron string  `mapstructure:"LIFECYCLE_DIGEST_CRON"` // When set, status change emails are consolidated and sent once by this cron (e.g. "0 0 8 * * *")
+	DigestInterval     int      `mapstructure:"DIGEST_INTERVAL"` // Hours; without LIFECYCLE_DIGEST_CRON, consolidate status change emails and send them every interval (0 disables)
+	DigestThreshold    int      `mapstructure:"DIGEST_THRESHOLD"` // Send the digest early once this many changes are queued (0 disables)
+	PhaseOutTerminal   bool     `mapstructure:"PHASE_OUT_TERMINAL"` // Treat "Phase Out Announce" as terminal: extract its replacement and use the "terminal" alert route
+	AlertReplacementDetails bool `mapstructure:"ALERT_REPLACEMENT_DETAILS"` // Show the successor's description and status in status change emails (crawls successors missing from the catalog)
+	LowStockCron       string   `mapstructure:"LOW_STOCK_CRON"` // Cron expression (6 fields) of the email listing products below their minimum quantity (empty disables)
+	LowStockRecipients []string `mapstructure:"LOW_STOCK_RECIPIENTS"` // Email recipients for the low stock report (empty uses STATUS_CHANGE_RECIPIENTS)
+	SMSAlertRecipients []string `mapstructure:"SMS_ALERT_RECIPIENTS"` // Phone numbers for lifecycle status change alerts (without LIFECYCLE_ALERT_ROUTES, only discontinuations)
+	WebhookAlertURLs   []string `mapstructure:"WEBHOOK_ALERT_URLS"` // Webhook URLs for lifecycle status change alerts
+	NotificationLocale string   `mapstructure:"NOTIFICATION_LOCALE"` // Language of emails and alerts ("pt" or "en")
+	BatchGetMaxSize    int      `mapstructure:"BATCH_GET_MAX_SIZE"` // Max IDs + codes accepted by POST /products/batch-get
+	StrictPagination   bool     `mapstructure:"STRICT_PAGINATION"` // Reject invalid page/page_size on GET /products instead of coercing them (X-Strict-Pagination overrides)
+	ManualCollectCooldown int   `mapstructure:"MANUAL_COLLECT_COOLDOWN"` // Seconds between manual collects of the same product (0 disables)
+	DBQueryTimeout     int      `mapstructure:"DB_QUERY_TIMEOUT"` // Seconds a single database query may run (0 disables); catalog exports are exempt and bounded only by the request
+	DBMaxConns         int      `mapstructure:"DB_MAX_CONNS"` // Max connections in the Postgres pool
+	DBMinConns         int      `mapstructure:"DB_MIN_CONNS"` // Connections kept open while idle
+	DBMaxConnLifetime  int      `mapstructure:"DB_MAX_CONN_LIFETIME"` // Minutes before a connection is recycled
+	DBMaxConnIdleTime  int      `mapstructure:"DB_MAX_CONN_IDLE_TIME"` // Minutes an idle connection is kept
+	AdminEmails        []string `mapstructure:"ADMIN_EMAILS"` // Users allowed to call the /admin maintenance routes
+	MaxOpenPages       int      `mapstructure:"MAX_OPEN_PAGES"` // Max browser pages open at once across all workers
+	CrawlMinDelay      int      `mapstructure:"CRAWL_MIN_DELAY"` // Milliseconds; random wait before each queued crawl is between this and CRAWL_MAX_DELAY
+	CrawlMaxDelay      int      `mapstructure:"CRAWL_MAX_DELAY"` // Milliseconds; single-product requests don't wait
+	CrawlRateLimit     int      `mapstructure:"CRAWL_RATE_LIMIT"` // Max crawls per minute across all workers (0 disables)
+	CrawlDedupeInFlight bool    `mapstructure:"CRAWL_DEDUPE_IN_FLIGHT"` // Jobs for a code already queued or being crawled share that crawl instead of opening the page again
+	CrawlTimeout       int      `mapstructure:"CRAWL_TIMEOUT"` // Seconds a single product crawl may take once its page is open (0 disables)
+	CrawlJobTimeout    int      `mapstructure:"CRAWL_JOB_TIMEOUT"` // Seconds a queued job may hold its worker, including the page wait and retries (0 disables)
+	CrawlRetries       int      `mapstructure:"CRAWL_RETRIES"` // Retries of a crawl after a transient failure (navigation error, page load timeout); 0 disables
+	CrawlRetryBackoff  int      `mapstructure:"CRAWL_RETRY_BACKOFF"` // Seconds before the first retry, doubled for each further one
+	CrawlerContextPoolSize int  `mapstructure:"CRAWLER_CONTEXT_POOL_SIZE"` // Isolated browser contexts (own cookies and storage) shared by the crawl workers
+	CrawlFailureCaptureDir string `mapstructure:"CRAWL_FAILURE_CAPTURE_DIR"` // Directory for screenshot + HTML of pages whose extraction failed (empty disables)
+	CrawlFailureCaptureMax int    `mapstructure:"CRAWL_FAILURE_CAPTURE_MAX"` // Captures kept in the directory; older ones are removed
+	ImportArchiveDir   string   `mapstructure:"IMPORT_ARCHIVE_DIR"` // Directory where uploaded import files are kept for POST /admin/imports/:id/replay (empty disables)
+	ImportArchiveMaxSize int    `mapstructure:"IMPORT_ARCHIVE_MAX_SIZE"` // MB; larger import files aren't archived
+	ImportArchiveRetention int  `mapstructure:"IMPORT_ARCHIVE_RETENTION"` // Days an archived import file is kept (0 keeps them forever)
+	CrawlerCanaryCode  string   `mapstructure:"CRAWLER_CANARY_CODE"` // Known-good product crawled by GET /admin/crawler/selftest
+	CrawlerSelectorsFile string `mapstructure:"CRAWLER_SELECTORS_FILE"` // JSON file with the CSS selectors tried for each field (empty uses the built-in ones)
+	CrawlerUserAgents  string   `mapstructure:"CRAWLER_USER_AGENTS"` // "|" separated user agents, one picked per browser context (empty uses built-in desktop ones, "none" keeps playwright's)
+	CrawlerExtraHeaders string  `mapstructure:"CRAWLER_EXTRA_HEADERS"` // "|" separated "Name: value" headers sent on every navigation
+	CrawlerDebug       bool     `mapstructure:"CRAWLER_DEBUG"` // Log crawler diagnostics at Debug level and save screenshot + HTML of every crawled page to /tmp
+	CrawlerWarmup      bool     `mapstructure:"CRAWLER_WARMUP"` // Crawl CRAWLER_CANARY_CODE once at startup and alert if it fails
+	CrawlBackoffThreshold int   `mapstructure:"CRAWL_BACKOFF_THRESHOLD"` // Consecutive failures before a code is skipped by the scheduler (0 disables)
+	CrawlBackoffBase   int      `mapstructure:"CRAWL_BACKOFF_BASE"` // Hours a code is skipped after reaching the threshold, doubled per further failure
+	CrawlBackoffMax    int      `mapstructure:"CRAWL_BACKOFF_MAX"` // Max hours a code is skipped
+	CrawlIncludeCodes  []string `mapstructure:"CRAWL_INCLUDE_CODES"` // Code prefixes or "re:<regex>"; when set, only matching codes are crawled by the scheduler
+	CrawlExcludeCodes  []string `mapstructure:"CRAWL_EXCLUDE_CODES"` // Code prefixes or "re:<regex>" the scheduler never crawls
+	CORSAllowOrigins   []string `mapstructure:"CORS_ALLOW_ORIGINS"` // Origins allowed by CORS; "*" can't be combined with credentials
+	CORSAllowCredentials bool   `mapstructure:"CORS_ALLOW_CREDENTIALS"` // Allow cookies/Authorization on cross-origin requests
+	TrustedProxies     []string `mapstructure:"TRUSTED_PROXIES"` // IPs or CIDR ranges of the reverse proxies whose X-Forwarded-For gives the client IP (empty uses the connection address)
 }
 
 func LoadConfig(path string) (*Configs, error) {
@@ -47,19 +127,37 @@ func LoadConfig(path string) (*Configs, error) {
 	viper.BindEnv("DATABASE_URL")
 	viper.BindEnv("REDIS_URL")
 	viper.BindEnv("PORT")
+	viper.BindEnv("TLS_ENABLED")
+	viper.BindEnv("TLS_CERT_FILE")
+	viper.BindEnv("TLS_KEY_FILE")
+	viper.BindEnv("SHUTDOWN_DRAIN_TIMEOUT")
+	viper.BindEnv("SHUTDOWN_TIMEOUT")
 	viper.BindEnv("DB_DRIVER")
 	viper.BindEnv("DB_HOST")
 	viper.BindEnv("DB_PORT")
 	viper.BindEnv("DB_USER")
 	viper.BindEnv("DB_PASSWORD")
 	viper.BindEnv("DB_NAME")
+	viper.BindEnv("DB_QUERY_TIMEOUT")
+	viper.BindEnv("DB_MAX_CONNS")
+	viper.BindEnv("DB_MIN_CONNS")
+	viper.BindEnv("DB_MAX_CONN_LIFETIME")
+	viper.BindEnv("DB_MAX_CONN_IDLE_TIME")
 	viper.BindEnv("JWT_SECRET")
 	viper.BindEnv("ACCESS_TOKEN_EXP")
 	viper.BindEnv("REFRESH_TOKEN_EXP")
+	viper.BindEnv("REFRESH_ANOMALY_MODE")
+	viper.BindEnv("AUTH_RATE_LIMIT")
+	viper.BindEnv("AUTH_RATE_WINDOW")
 	viper.BindEnv("REDIS_HOST")
 	viper.BindEnv("REDIS_PORT")
 	viper.BindEnv("REDIS_PASSWORD")
 	viper.BindEnv("REDIS_DB")
+	viper.BindEnv("REDIS_POOL_SIZE")
+	viper.BindEnv("REDIS_MIN_IDLE_CONNS")
+	viper.BindEnv("REDIS_DIAL_TIMEOUT")
+	viper.BindEnv("REDIS_READ_TIMEOUT")
+	viper.BindEnv("REDIS_WRITE_TIMEOUT")
 	viper.BindEnv("SIEMENS_URL")
 	viper.BindEnv("SMTP_HOST")
 	viper.BindEnv("SMTP_PORT")
@@ -67,29 +165,246 @@ func LoadConfig(path string) (*Configs, error) {
 	viper.BindEnv("SMTP_PASS")
 	viper.BindEnv("MAILJET_API_KEY")
 	viper.BindEnv("MAILJET_API_SECRET")
+	viper.BindEnv("TWILIO_ACCOUNT_SID")
+	viper.BindEnv("TWILIO_AUTH_TOKEN")
+	viper.BindEnv("TWILIO_NUMBER")
 	viper.BindEnv("CRON_EXPRESSION")
+	viper.BindEnv("COLLECT_RUN_TIMEOUT")
+	viper.BindEnv("RECRAWL_AFTER")
+	viper.BindEnv("COLLECT_MIN_SUCCESS_PERCENT")
+	viper.BindEnv("SNAPSHOT_PRUNE_CRON")
+	viper.BindEnv("SNAPSHOT_KEEP_PER_PRODUCT")
+	viper.BindEnv("SNAPSHOT_RAW_HTML_RETENTION")
 	viper.BindEnv("LOG_PATH")
+	viper.BindEnv("LOG_MAX_SIZE")
+	viper.BindEnv("LOG_MAX_AGE")
+	viper.BindEnv("LOG_MAX_BACKUPS")
+	viper.BindEnv("LOG_COMPRESS")
 	viper.BindEnv("WEB_SERVER_PORT")
+	viper.BindEnv("BATCH_GET_MAX_SIZE")
+	viper.BindEnv("STRICT_PAGINATION")
+	viper.BindEnv("ADMIN_EMAILS")
+	viper.BindEnv("MANUAL_COLLECT_COOLDOWN")
+	viper.BindEnv("MAX_OPEN_PAGES")
+	viper.BindEnv("CRAWL_MIN_DELAY")
+	viper.BindEnv("CRAWL_MAX_DELAY")
+	viper.BindEnv("CRAWL_RATE_LIMIT")
+	viper.BindEnv("CRAWL_DEDUPE_IN_FLIGHT")
+	viper.BindEnv("CRAWL_TIMEOUT")
+	viper.BindEnv("CRAWL_JOB_TIMEOUT")
+	viper.BindEnv("CRAWL_RETRIES")
+	viper.BindEnv("CRAWL_RETRY_BACKOFF")
+	viper.BindEnv("CRAWLER_CONTEXT_POOL_SIZE")
+	viper.BindEnv("CRAWL_FAILURE_CAPTURE_DIR")
+	viper.BindEnv("CRAWL_FAILURE_CAPTURE_MAX")
+	viper.BindEnv("IMPORT_ARCHIVE_DIR")
+	viper.BindEnv("IMPORT_ARCHIVE_MAX_SIZE")
+	viper.BindEnv("IMPORT_ARCHIVE_RETENTION")
+	viper.BindEnv("CRAWLER_CANARY_CODE")
+	viper.BindEnv("CRAWLER_SELECTORS_FILE")
+	viper.BindEnv("CRAWLER_USER_AGENTS")
+	viper.BindEnv("CRAWLER_EXTRA_HEADERS")
+	viper.BindEnv("CRAWLER_DEBUG")
+	viper.BindEnv("CRAWLER_WARMUP")
+	viper.BindEnv("CRAWL_BACKOFF_THRESHOLD")
+	viper.BindEnv("CRAWL_BACKOFF_BASE")
+	viper.BindEnv("CRAWL_BACKOFF_MAX")
+	viper.BindEnv("CRAWL_INCLUDE_CODES")
+	viper.BindEnv("CRAWL_EXCLUDE_CODES")
+	viper.BindEnv("CORS_ALLOW_ORIGINS")
+	viper.BindEnv("CORS_ALLOW_CREDENTIALS")
+	viper.BindEnv("TRUSTED_PROXIES")
+	viper.BindEnv("LIFECYCLE_ALERT_ROUTES")
+	viper.BindEnv("LIFECYCLE_ALERT_DEFAULT_CHANNELS")
+	viper.BindEnv("LIFECYCLE_DIGEST_CRON")
+	viper.BindEnv("DIGEST_INTERVAL")
+	viper.BindEnv("DIGEST_THRESHOLD")
+	viper.BindEnv("PHASE_OUT_TERMINAL")
+	viper.BindEnv("ALERT_REPLACEMENT_DETAILS")
+	viper.BindEnv("LOW_STOCK_CRON")
+	viper.BindEnv("LOW_STOCK_RECIPIENTS")
+	viper.BindEnv("SMS_ALERT_RECIPIENTS")
+	viper.BindEnv("WEBHOOK_ALERT_URLS")
+	viper.BindEnv("NOTIFICATION_LOCALE")
+
+	// Set defaults for TLS (disabled: Dokku's nginx terminates TLS)
+	viper.SetDefault("TLS_ENABLED", false)
+	viper.SetDefault("TLS_CERT_FILE", "server.crt")
+	viper.SetDefault("TLS_KEY_FILE", "server.key")
+
+	// Set defaults for graceful shutdown
+	viper.SetDefault("SHUTDOWN_DRAIN_TIMEOUT", 10) // 10 seconds
+	viper.SetDefault("SHUTDOWN_TIMEOUT", 30)       // 30 seconds
+
+	// Set default for database query timeout
+	viper.SetDefault("DB_QUERY_TIMEOUT", 15) // 15 seconds
+
+	// Set defaults for the Postgres connection pool
+	viper.SetDefault("DB_MAX_CONNS", 10)
+	viper.SetDefault("DB_MIN_CONNS", 2)
+	viper.SetDefault("DB_MAX_CONN_LIFETIME", 60)  // 1 hour
+	viper.SetDefault("DB_MAX_CONN_IDLE_TIME", 30) // 30 minutes
 
 	// Set defaults for token expiration
 	viper.SetDefault("ACCESS_TOKEN_EXP", 900)     // 15 minutes
 	viper.SetDefault("REFRESH_TOKEN_EXP", 604800) // 7 days
 
+	// Set default for refresh token anomaly detection (IP/user-agent changes are only logged)
+	viper.SetDefault("REFRESH_ANOMALY_MODE", "log")
+
+	// Set defaults for the auth routes rate limit (10 attempts per IP every 5 minutes)
+	viper.SetDefault("AUTH_RATE_LIMIT", 10)
+	viper.SetDefault("AUTH_RATE_WINDOW", 300) // 5 minutes
+
 	// Set defaults for Redis
 	viper.SetDefault("REDIS_HOST", "localhost")
 	viper.SetDefault("REDIS_PORT", "6379")
 	viper.SetDefault("REDIS_PASSWORD", "")
 	viper.SetDefault("REDIS_DB", 0)
+	viper.SetDefault("REDIS_POOL_SIZE", 10)
+	viper.SetDefault("REDIS_MIN_IDLE_CONNS", 5)
+	viper.SetDefault("REDIS_DIAL_TIMEOUT", 5)  // 5 seconds
+	viper.SetDefault("REDIS_READ_TIMEOUT", 3)  // 3 seconds
+	viper.SetDefault("REDIS_WRITE_TIMEOUT", 3) // 3 seconds
 
 	// Set default for cron expression (runs at 3:00 AM every day)
 	viper.SetDefault("CRON_EXPRESSION", "0 0 3 * * *")
 
+	// Set default timeout of each lifecycle update run
+	viper.SetDefault("COLLECT_RUN_TIMEOUT", 30) // 30 minutes
+
+	// Set default recrawl age (crawl every code on each run)
+	viper.SetDefault("RECRAWL_AFTER", 0)
+
+	// Set default for the degraded run alert
+	viper.SetDefault("COLLECT_MIN_SUCCESS_PERCENT", 80)
+
+	// Set defaults for the snapshot retention (runs at 5:00 AM; only the page HTML is dropped by default)
+	viper.SetDefault("SNAPSHOT_PRUNE_CRON", "0 0 5 * * *")
+	viper.SetDefault("SNAPSHOT_KEEP_PER_PRODUCT", 0)
+	viper.SetDefault("SNAPSHOT_RAW_HTML_RETENTION", 90) // 90 days
+
 	// Set default for log path (empty means stdout only)
 	viper.SetDefault("LOG_PATH", "")
 
+	// Set defaults for log rotation (only applies when LOG_PATH is set)
+	viper.SetDefault("LOG_MAX_SIZE", 100) // 100 MB
+	viper.SetDefault("LOG_MAX_AGE", 30)   // 30 days
+	viper.SetDefault("LOG_MAX_BACKUPS", 10)
+	viper.SetDefault("LOG_COMPRESS", true)
+
 	// Set default for alert recipients (empty means no alerts)
 	viper.SetDefault("ALERT_RECIPIENTS", []string{"freitasmatheus@lunaltas.com"})
 
+	// Set defaults for status change recipients and what to do when a recipient list is empty.
+	// There is no default address: without STATUS_CHANGE_RECIPIENTS the reports follow the policy.
+	viper.SetDefault("STATUS_CHANGE_RECIPIENTS", []string{})
+	viper.SetDefault("FALLBACK_RECIPIENTS", []string{})
+	viper.SetDefault("EMPTY_RECIPIENTS_POLICY", "warn")
+
+	// Set defaults for status change routing (every change goes by email unless a route says otherwise)
+	viper.SetDefault("LIFECYCLE_ALERT_ROUTES", "")
+	viper.SetDefault("LIFECYCLE_ALERT_DEFAULT_CHANNELS", []string{"email"})
+	viper.SetDefault("SMS_ALERT_RECIPIENTS", []string{})
+	viper.SetDefault("WEBHOOK_ALERT_URLS", []string{})
+
+	// Set defaults for the status change digest (disabled: status change emails go out after each run)
+	viper.SetDefault("LIFECYCLE_DIGEST_CRON", "")
+	viper.SetDefault("DIGEST_INTERVAL", 0)
+	viper.SetDefault("DIGEST_THRESHOLD", 0)
+
+	// Set defaults for the low stock report (7:00 AM, only sent when a product is below its minimum)
+	viper.SetDefault("LOW_STOCK_CRON", "0 0 7 * * *")
+	viper.SetDefault("LOW_STOCK_RECIPIENTS", []string{})
+
+	// Set default for phase out handling (informational, not terminal)
+	viper.SetDefault("PHASE_OUT_TERMINAL", false)
+
+	// Set default for the successor details in status change emails
+	viper.SetDefault("ALERT_REPLACEMENT_DETAILS", true)
+
+	// Set default language of emails and alerts (API errors follow the request's Accept-Language)
+	viper.SetDefault("NOTIFICATION_LOCALE", "pt")
+
+	// Set default for batch get size limit
+	viper.SetDefault("BATCH_GET_MAX_SIZE", 100)
+
+	// Set default pagination mode (lenient, as the HTMX pages expect)
+	viper.SetDefault("STRICT_PAGINATION", false)
+
+	// Set default for the manual collect cooldown (avoids crawl storms from the UI)
+	viper.SetDefault("MANUAL_COLLECT_COOLDOWN", 600) // 10 minutes
+
+	// Set default for concurrent browser pages (one per worker; lower it on hosts with little memory)
+	viper.SetDefault("MAX_OPEN_PAGES", 5)
+
+	// Set defaults for the anti-detection wait before each queued crawl
+	viper.SetDefault("CRAWL_MIN_DELAY", 500)  // 500 ms
+	viper.SetDefault("CRAWL_MAX_DELAY", 2500) // 2.5 seconds
+
+	// Set default for the global crawl rate limit (disabled)
+	viper.SetDefault("CRAWL_RATE_LIMIT", 0)
+
+	// Set default for sharing the crawl of a code among overlapping jobs (import + scheduler)
+	viper.SetDefault("CRAWL_DEDUPE_IN_FLIGHT", true)
+
+	// Set default for the per-product crawl timeout
+	viper.SetDefault("CRAWL_TIMEOUT", 90) // 90 seconds
+
+	// Set default for the whole job timeout (disabled; CRAWL_TIMEOUT still bounds each attempt)
+	viper.SetDefault("CRAWL_JOB_TIMEOUT", 0)
+
+	// Set defaults for retries of transient crawl failures
+	viper.SetDefault("CRAWL_RETRIES", 2)
+	viper.SetDefault("CRAWL_RETRY_BACKOFF", 5) // 5 seconds, then 10
+
+	// Set default for browser contexts (one per worker, so concurrent crawls don't share cookies)
+	viper.SetDefault("CRAWLER_CONTEXT_POOL_SIZE", 5)
+
+	// Set defaults for crawl failure captures (disabled unless a directory is set)
+	viper.SetDefault("CRAWL_FAILURE_CAPTURE_DIR", "")
+	viper.SetDefault("CRAWL_FAILURE_CAPTURE_MAX", 50)
+
+	// Set defaults for the import archive (disabled unless a directory is set; files hold customer data)
+	viper.SetDefault("IMPORT_ARCHIVE_DIR", "")
+	viper.SetDefault("IMPORT_ARCHIVE_MAX_SIZE", 10) // 10 MB
+	viper.SetDefault("IMPORT_ARCHIVE_RETENTION", 14) // 14 days
+
+	// Set default for crawler selectors (built-in candidates)
+	viper.SetDefault("CRAWLER_SELECTORS_FILE", "")
+
+	// Set defaults for crawler navigation (built-in desktop user agents, no extra headers)
+	viper.SetDefault("CRAWLER_USER_AGENTS", "")
+	viper.SetDefault("CRAWLER_EXTRA_HEADERS", "")
+
+	// Set default for crawler debug output (off: nothing is logged or written per crawl)
+	viper.SetDefault("CRAWLER_DEBUG", false)
+
+	// Set defaults for the failed crawl backoff (3 failed nights skip 1 night, then 2, 4, up to a week)
+	viper.SetDefault("CRAWL_BACKOFF_THRESHOLD", 3)
+	viper.SetDefault("CRAWL_BACKOFF_BASE", 24) // 24 hours
+	viper.SetDefault("CRAWL_BACKOFF_MAX", 168) // 7 days
+
+	// Set defaults for CORS (the local frontend, with credentials)
+	viper.SetDefault("CORS_ALLOW_ORIGINS", []string{"http://localhost:3000"})
+	viper.SetDefault("CORS_ALLOW_CREDENTIALS", true)
+
+	// Set default for trusted proxies (none: X-Forwarded-For is ignored, e.g. Dokku's nginx must be listed)
+	viper.SetDefault("TRUSTED_PROXIES", []string{})
+
+	// Set defaults for the scheduled crawl code filter (empty crawls every code)
+	viper.SetDefault("CRAWL_INCLUDE_CODES", []string{})
+	viper.SetDefault("CRAWL_EXCLUDE_CODES", []string{})
+
+	// Set default for the crawler self-test product (empty requires ?code= on every call)
+	viper.SetDefault("CRAWLER_CANARY_CODE", "")
+
+	// Set default for the startup crawler warm-up (opt-in)
+	viper.SetDefault("CRAWLER_WARMUP", false)
+
+	// Set default for admin users (empty means the /admin routes are closed)
+	viper.SetDefault("ADMIN_EMAILS", []string{})
+
 	// Set default port (Dokku uses PORT env var)
 	viper.SetDefault("WEB_SERVER_PORT", "5000")
 
@@ -98,7 +413,7 @@ func LoadConfig(path string) (*Configs, error) {
 
 	err := viper.Unmarshal(&cfg)
 	if err != nil {
-		panic(err)
+		return nil, fmt.Errorf("failed to decode config: %w", err)
 	}
 
 	// Use PORT env var if WEB_SERVER_PORT is not set (Dokku compatibility)
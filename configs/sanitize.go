@@ -0,0 +1,66 @@
+package configs
+
+import (
+	"net/url"
+	"reflect"
+	"strings"
+)
+
+// Redacted replaces secret values in the sanitized configuration
+const Redacted = "***"
+
+// secretKeys are redacted whenever they are set; empty values stay empty so a missing secret is still visible
+var secretKeys = map[string]bool{
+	"DB_PASSWORD":        true,
+	"JWT_SECRET":         true,
+	"REDIS_PASSWORD":     true,
+	"TWILIO_AUTH_TOKEN":  true,
+	"TWILIO_API_KEY":     true,
+	"TWILIO_API_SECRET":  true,
+	"MAILJET_API_SECRET": true,
+	"SMTP_PASS":          true,
+}
+
+// urlKeys may carry credentials in their userinfo; only the password is redacted
+var urlKeys = map[string]bool{
+	"DATABASE_URL": true,
+	"REDIS_URL":    true,
+}
+
+// Sanitized returns the configuration keyed by environment variable, with secrets redacted
+func (c Configs) Sanitized() map[string]any {
+	sanitized := make(map[string]any)
+
+	v := reflect.ValueOf(c)
+	t := v.Type()
+	for i := range t.NumField() {
+		key := t.Field(i).Tag.Get("mapstructure")
+		if key == "" {
+			continue
+		}
+		value := v.Field(i).Interface()
+
+		switch s, _ := value.(string); {
+		case secretKeys[key] && s != "":
+			value = Redacted
+		case urlKeys[key] && s != "":
+			value = redactURL(s)
+		}
+		sanitized[key] = value
+	}
+	return sanitized
+}
+
+// redactURL hides the password of a connection URL; unparseable URLs are fully redacted
+func redactURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return Redacted
+	}
+	if _, hasPassword := u.User.Password(); !hasPassword {
+		return u.String()
+	}
+	// url.UserPassword escaparia o "***"; monta o userinfo sem a senha e insere o marcador
+	u.User = url.User(u.User.Username())
+	return strings.Replace(u.String(), "@", ":"+Redacted+"@", 1)
+}
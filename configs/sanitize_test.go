@@ -0,0 +1,34 @@
+package configs
+
+import "testing"
+
+func TestSanitized(t *testing.T) {
+	cfg := Configs{
+		DatabaseURL:     "postgres://app:s3cret@db:5432/lifecycle?sslmode=disable",
+		RedisURL:        "redis://cache:6379/0",
+		DBPassword:      "s3cret",
+		JWTSecret:       "jwt",
+		SMTP_PASS:       "smtp",
+		TwilioAuthToken: "",
+		SMTP_HOST:       "smtp.example.com",
+		MaxOpenPages:    5,
+	}
+
+	sanitized := cfg.Sanitized()
+
+	expected := map[string]any{
+		"DATABASE_URL":      "postgres://app:***@db:5432/lifecycle?sslmode=disable",
+		"REDIS_URL":         "redis://cache:6379/0",
+		"DB_PASSWORD":       Redacted,
+		"JWT_SECRET":        Redacted,
+		"SMTP_PASS":         Redacted,
+		"TWILIO_AUTH_TOKEN": "",
+		"SMTP_HOST":         "smtp.example.com",
+		"MAX_OPEN_PAGES":    5,
+	}
+	for key, want := range expected {
+		if got := sanitized[key]; got != want {
+			t.Errorf("%s: expected %v, got %v", key, want, got)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package configs
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// minJWTSecretLength is the shortest accepted JWT_SECRET (256 bits for HS256)
+const minJWTSecretLength = 32
+
+// Validate checks the settings the server can't run without, reporting every problem at once
+// instead of failing later at the first request that needs them
+func (c Configs) Validate() error {
+	var problems []string
+
+	if len(c.JWTSecret) < minJWTSecretLength {
+		problems = append(problems, fmt.Sprintf("JWT_SECRET must have at least %d characters", minJWTSecretLength))
+	}
+
+	if c.DatabaseURL == "" {
+		var missing []string
+		for key, value := range map[string]string{
+			"DB_DRIVER": c.DBDriver,
+			"DB_HOST":   c.DBHost,
+			"DB_PORT":   c.DBPort,
+			"DB_USER":   c.DBUser,
+			"DB_NAME":   c.DBName,
+		} {
+			if value == "" {
+				missing = append(missing, key)
+			}
+		}
+		if len(missing) > 0 {
+			slices.Sort(missing)
+			problems = append(problems, "DATABASE_URL is not set and neither are "+strings.Join(missing, ", "))
+		}
+	}
+
+	if c.SIEMENS_URL == "" {
+		problems = append(problems, "SIEMENS_URL is required")
+	} else if u, err := url.Parse(c.SIEMENS_URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		problems = append(problems, "SIEMENS_URL must be an http(s) URL")
+	}
+
+	if c.SMTP_HOST == "" {
+		var lists []string
+		for key, recipients := range map[string][]string{
+			"ALERT_RECIPIENTS":         c.AlertRecipients,
+			"STATUS_CHANGE_RECIPIENTS": c.StatusChangeRecipients,
+			"LOW_STOCK_RECIPIENTS":     c.LowStockRecipients,
+			"FALLBACK_RECIPIENTS":      c.FallbackRecipients,
+		} {
+			if hasValue(recipients) {
+				lists = append(lists, key)
+			}
+		}
+		if len(lists) > 0 {
+			slices.Sort(lists)
+			problems = append(problems, "SMTP_HOST is required to email "+strings.Join(lists, ", "))
+		}
+	}
+
+	if c.AuthRateLimit > 0 && c.AuthRateWindow <= 0 {
+		problems = append(problems, "AUTH_RATE_WINDOW must be positive when AUTH_RATE_LIMIT is set")
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid configuration: %s", strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// Warnings lists settings that are valid but likely wrong in production. They are logged at
+// startup without stopping the server, since the same values are right for a local run.
+func (c Configs) Warnings() []string {
+	var warnings []string
+
+	// Atras do nginx do Dokku todo cliente chega com o IP do proxy
+	if c.AuthRateLimit > 0 && !hasValue(c.TrustedProxies) {
+		warnings = append(warnings, "AUTH_RATE_LIMIT counts per client IP but TRUSTED_PROXIES is empty: behind a reverse proxy every client has the proxy address and shares one limit")
+	}
+
+	return warnings
+}
+
+// hasValue reports whether a list read from the environment has a non-blank entry
+func hasValue(values []string) bool {
+	for _, v := range values {
+		if strings.TrimSpace(v) != "" {
+			return true
+		}
+	}
+	return false
+}
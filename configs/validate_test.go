@@ -0,0 +1,85 @@
+package configs
+
+import (
+	"strings"
+	"testing"
+)
+
+func validConfig() Configs {
+	return Configs{
+		JWTSecret:   strings.Repeat("k", 32),
+		DatabaseURL: "postgres://app:s3cret@db:5432/lifecycle",
+		SIEMENS_URL: "https://sieportal.siemens.com/en-ww/products-services/detail/",
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		mutate   func(c *Configs)
+		problems []string
+	}{
+		{name: "valid", mutate: func(c *Configs) {}},
+		{name: "db params instead of url", mutate: func(c *Configs) {
+			c.DatabaseURL = ""
+			c.DBDriver, c.DBHost, c.DBPort, c.DBUser, c.DBName = "postgres", "db", "5432", "app", "lifecycle"
+		}},
+		{name: "short jwt secret", mutate: func(c *Configs) { c.JWTSecret = "secret" }, problems: []string{"JWT_SECRET must have at least 32 characters"}},
+		{name: "missing db params", mutate: func(c *Configs) {
+			c.DatabaseURL = ""
+			c.DBHost, c.DBPort = "db", "5432"
+		}, problems: []string{"DATABASE_URL is not set and neither are DB_DRIVER, DB_NAME, DB_USER"}},
+		{name: "invalid siemens url", mutate: func(c *Configs) { c.SIEMENS_URL = "sieportal.siemens.com" }, problems: []string{"SIEMENS_URL must be an http(s) URL"}},
+		{name: "recipients without smtp", mutate: func(c *Configs) {
+			c.StatusChangeRecipients = []string{"ops@example.com"}
+			c.AlertRecipients = []string{" "}
+		}, problems: []string{"SMTP_HOST is required to email STATUS_CHANGE_RECIPIENTS"}},
+		{name: "rate limit without window", mutate: func(c *Configs) { c.AuthRateLimit = 10 }, problems: []string{"AUTH_RATE_WINDOW must be positive when AUTH_RATE_LIMIT is set"}},
+		{name: "every problem at once", mutate: func(c *Configs) {
+			c.JWTSecret, c.SIEMENS_URL = "", ""
+		}, problems: []string{"JWT_SECRET must have at least 32 characters", "SIEMENS_URL is required"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig()
+			tt.mutate(&cfg)
+			err := cfg.Validate()
+
+			if len(tt.problems) == 0 {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("expected problems %v, got nil", tt.problems)
+			}
+			for _, problem := range tt.problems {
+				if !strings.Contains(err.Error(), problem) {
+					t.Errorf("expected %q in %q", problem, err.Error())
+				}
+			}
+		})
+	}
+}
+
+func TestWarnings(t *testing.T) {
+	cfg := validConfig()
+	cfg.AuthRateLimit = 10
+
+	warnings := cfg.Warnings()
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "TRUSTED_PROXIES is empty") {
+		t.Errorf("expected a TRUSTED_PROXIES warning, got %v", warnings)
+	}
+
+	cfg.TrustedProxies = []string{"172.17.0.1"}
+	if warnings := cfg.Warnings(); len(warnings) != 0 {
+		t.Errorf("expected no warnings with trusted proxies, got %v", warnings)
+	}
+
+	cfg.TrustedProxies, cfg.AuthRateLimit = nil, 0
+	if warnings := cfg.Warnings(); len(warnings) != 0 {
+		t.Errorf("expected no warnings without rate limiting, got %v", warnings)
+	}
+}
@@ -31,3 +31,18 @@ type AreaWithCountOutput struct {
 	AreaOutput
 	ProductCount int64 `json:"product_count"`
 }
+
+// O que fazer com produtos da area de origem cujo codigo ja existe na area de destino
+const (
+	MergeDuplicatesFail       = "fail"        // nao mescla; retorna os codigos em conflito (padrao)
+	MergeDuplicatesKeepBoth   = "keep_both"   // move assim mesmo; a area de destino fica com os dois produtos
+	MergeDuplicatesKeepTarget = "keep_target" // arquiva o produto da origem e mantem o da destino
+)
+
+type MergeAreaOutput struct {
+	SourceID          pgtype.UUID `json:"source_id"`
+	Target            AreaOutput  `json:"target"`
+	Moved             int64       `json:"moved"`              // produtos movidos para a area de destino
+	DuplicatesRemoved int64       `json:"duplicates_removed"` // produtos da origem arquivados com keep_target
+	DuplicateCodes    []string    `json:"duplicate_codes,omitempty"`
+}
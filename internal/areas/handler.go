@@ -135,3 +135,62 @@ func (h *Handler) DeleteArea(c echo.Context) error {
 
 	return c.NoContent(http.StatusNoContent)
 }
+
+// MergeArea handles POST /admin/areas/:id/merge-into/:targetId
+// Moves every product of the area to the target area and deletes it. Codes present in both areas
+// abort the merge unless duplicates=keep_both (move anyway) or duplicates=keep_target (archive the source product)
+func (h *Handler) MergeArea(c echo.Context) error {
+	sourceID, err := parser.PgUUIDFromString(c.Param("id"))
+	if err != nil {
+		return rest.NewBadRequestError("id da area invalido")
+	}
+
+	targetID, err := parser.PgUUIDFromString(c.Param("targetId"))
+	if err != nil {
+		return rest.NewBadRequestError("id da area de destino invalido")
+	}
+
+	duplicates := c.QueryParam("duplicates")
+	switch duplicates {
+	case "":
+		duplicates = MergeDuplicatesFail
+	case MergeDuplicatesFail, MergeDuplicatesKeepBoth, MergeDuplicatesKeepTarget:
+	default:
+		return rest.NewBadRequestError("valor invalido para duplicates")
+	}
+
+	result, apiErr := h.service.MergeArea(c.Request().Context(), sourceID, targetID, duplicates)
+	if apiErr != nil {
+		return apiErr
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+// ExportConfig handles GET /admin/config/export
+// Every area as a document that POST /admin/config/import applies on another instance
+func (h *Handler) ExportConfig(c echo.Context) error {
+	document, apiErr := h.service.ExportConfig(c.Request().Context())
+	if apiErr != nil {
+		return apiErr
+	}
+
+	c.Response().Header().Set(echo.HeaderContentDisposition, `attachment; filename="lifecycle-config.json"`)
+	return c.JSON(http.StatusOK, document)
+}
+
+// ImportConfig handles POST /admin/config/import
+// Upserts the areas of the document by name and reports which were created, updated or skipped
+func (h *Handler) ImportConfig(c echo.Context) error {
+	var document ConfigDocument
+	if err := c.Bind(&document); err != nil {
+		return rest.NewUnprocessableEntity("erro ao processar dados")
+	}
+
+	result, apiErr := h.service.ImportConfig(c.Request().Context(), document)
+	if apiErr != nil {
+		return apiErr
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
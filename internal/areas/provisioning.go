@@ -0,0 +1,137 @@
+package areas
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	repo "github.com/freitasmatheusrn/lifecycle-monitor/internal/database/postgres/sqlc"
+	"github.com/freitasmatheusrn/lifecycle-monitor/pkg/rest"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// ConfigDocumentVersion is the format of the configuration document; imports of other versions are refused
+const ConfigDocumentVersion = 1
+
+// ConfigDocument is the configuration exported by GET /admin/config/export and applied by
+// POST /admin/config/import, to provision another instance. Selector profiles are not included:
+// the crawler selectors come from CRAWLER_SELECTORS_FILE, which is copied with the deploy.
+type ConfigDocument struct {
+	Version    int          `json:"version"`
+	ExportedAt time.Time    `json:"exported_at"`
+	Areas      []AreaConfig `json:"areas"`
+}
+
+// AreaConfig is an area in the configuration document; areas are matched by name
+type AreaConfig struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+// ImportConfigResult lists the area names by what the import did to them
+type ImportConfigResult struct {
+	Created []string `json:"created"`
+	Updated []string `json:"updated"`
+	Skipped []string `json:"skipped"` // ja existiam iguais
+}
+
+// ExportConfig returns every area as a configuration document
+func (s *svc) ExportConfig(ctx context.Context) (*ConfigDocument, *rest.ApiErr) {
+	areas, err := s.repo.ListAreas(ctx)
+	if err != nil {
+		return nil, s.handleDBError(err)
+	}
+
+	document := &ConfigDocument{
+		Version:    ConfigDocumentVersion,
+		ExportedAt: time.Now(),
+		Areas:      make([]AreaConfig, 0, len(areas)),
+	}
+	for _, a := range areas {
+		document.Areas = append(document.Areas, AreaConfig{Name: a.Name, Description: a.Description.String})
+	}
+	return document, nil
+}
+
+// ImportConfig upserts the areas of document by name, all in one transaction.
+// Areas missing from the document are left alone.
+func (s *svc) ImportConfig(ctx context.Context, document ConfigDocument) (*ImportConfigResult, *rest.ApiErr) {
+	if apiErr := validateConfigDocument(document); apiErr != nil {
+		return nil, apiErr
+	}
+
+	var result *ImportConfigResult
+	err := s.inTx(ctx, func(q repo.Querier) error {
+		var err error
+		result, err = importAreas(ctx, q, document.Areas)
+		return err
+	})
+	if err != nil {
+		return nil, s.handleDBError(err)
+	}
+	return result, nil
+}
+
+// validateConfigDocument rejects documents of another version and areas without name or repeated
+func validateConfigDocument(document ConfigDocument) *rest.ApiErr {
+	if document.Version != ConfigDocumentVersion {
+		return rest.NewBadRequestError("versao do documento de configuracao nao suportada")
+	}
+
+	var causes []rest.Causes
+	seen := make(map[string]bool, len(document.Areas))
+	for i, area := range document.Areas {
+		field := fmt.Sprintf("areas[%d].name", i)
+		name := strings.TrimSpace(area.Name)
+		switch {
+		case name == "":
+			causes = append(causes, rest.Causes{Field: field, Message: "nome da area e obrigatorio"})
+		case seen[name]:
+			causes = append(causes, rest.Causes{Field: field, Message: "area repetida no documento"})
+		}
+		seen[name] = true
+	}
+
+	if len(causes) > 0 {
+		return rest.NewBadRequestValidationError("documento de configuracao invalido", causes)
+	}
+	return nil
+}
+
+// importAreas creates the areas that don't exist and updates the description of those that changed
+func importAreas(ctx context.Context, q repo.Querier, areas []AreaConfig) (*ImportConfigResult, error) {
+	result := &ImportConfigResult{Created: []string{}, Updated: []string{}, Skipped: []string{}}
+
+	for _, area := range areas {
+		name := strings.TrimSpace(area.Name)
+		description := pgtype.Text{String: area.Description, Valid: area.Description != ""}
+
+		existing, err := q.FindAreaByName(ctx, name)
+		if errors.Is(err, pgx.ErrNoRows) {
+			if _, err := q.CreateArea(ctx, repo.CreateAreaParams{Name: name, Description: description}); err != nil {
+				return nil, err
+			}
+			result.Created = append(result.Created, name)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if existing.Description.String == area.Description {
+			result.Skipped = append(result.Skipped, name)
+			continue
+		}
+		// UpdateArea keeps the current value for NULL, so clearing the description needs an empty string
+		description.Valid = true
+		if _, err := q.UpdateArea(ctx, repo.UpdateAreaParams{ID: existing.ID, Description: description}); err != nil {
+			return nil, err
+		}
+		result.Updated = append(result.Updated, name)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,95 @@
+package areas
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	repo "github.com/freitasmatheusrn/lifecycle-monitor/internal/database/postgres/sqlc"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// areasQuerier keeps areas by name in memory
+type areasQuerier struct {
+	repo.Querier
+	areas   map[string]repo.Area
+	updated map[string]pgtype.Text
+}
+
+func (q *areasQuerier) FindAreaByName(ctx context.Context, name string) (repo.Area, error) {
+	area, ok := q.areas[name]
+	if !ok {
+		return repo.Area{}, pgx.ErrNoRows
+	}
+	return area, nil
+}
+
+func (q *areasQuerier) CreateArea(ctx context.Context, arg repo.CreateAreaParams) (repo.Area, error) {
+	area := repo.Area{Name: arg.Name, Description: arg.Description}
+	q.areas[arg.Name] = area
+	return area, nil
+}
+
+func (q *areasQuerier) UpdateArea(ctx context.Context, arg repo.UpdateAreaParams) (repo.Area, error) {
+	for name, area := range q.areas {
+		if area.ID == arg.ID {
+			q.updated[name] = arg.Description
+			return area, nil
+		}
+	}
+	return repo.Area{}, pgx.ErrNoRows
+}
+
+func TestImportAreas(t *testing.T) {
+	q := &areasQuerier{
+		areas: map[string]repo.Area{
+			"Cutter": {ID: pgtype.UUID{Bytes: [16]byte{1}, Valid: true}, Name: "Cutter"},
+			"HVAC":   {ID: pgtype.UUID{Bytes: [16]byte{2}, Valid: true}, Name: "HVAC", Description: pgtype.Text{String: "Ar condicionado", Valid: true}},
+		},
+		updated: map[string]pgtype.Text{},
+	}
+
+	result, err := importAreas(context.Background(), q, []AreaConfig{
+		{Name: "Cutter"},
+		{Name: " HVAC ", Description: "Climatizacao"},
+		{Name: "Utilidades", Description: "Caldeiras"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(result.Created) != 1 || result.Created[0] != "Utilidades" {
+		t.Errorf("expected Utilidades to be created, got %v", result.Created)
+	}
+	if len(result.Updated) != 1 || result.Updated[0] != "HVAC" || q.updated["HVAC"].String != "Climatizacao" {
+		t.Errorf("expected the HVAC description to be updated, got %v, %v", result.Updated, q.updated)
+	}
+	if len(result.Skipped) != 1 || result.Skipped[0] != "Cutter" {
+		t.Errorf("expected Cutter to be skipped, got %v", result.Skipped)
+	}
+	if q.areas["Utilidades"].Description.String != "Caldeiras" {
+		t.Errorf("expected the new area to keep its description, got %+v", q.areas["Utilidades"])
+	}
+}
+
+func TestValidateConfigDocument(t *testing.T) {
+	if apiErr := validateConfigDocument(ConfigDocument{Version: 2}); apiErr == nil || apiErr.Code != http.StatusBadRequest {
+		t.Errorf("expected another version to be refused, got %+v", apiErr)
+	}
+
+	apiErr := validateConfigDocument(ConfigDocument{
+		Version: ConfigDocumentVersion,
+		Areas:   []AreaConfig{{Name: "Cutter"}, {Name: " "}, {Name: "Cutter "}},
+	})
+	if apiErr == nil || len(apiErr.Causes) != 2 {
+		t.Fatalf("expected the empty and repeated names to be reported, got %+v", apiErr)
+	}
+	if apiErr.Causes[0].Field != "areas[1].name" || apiErr.Causes[1].Field != "areas[2].name" {
+		t.Errorf("unexpected causes %+v", apiErr.Causes)
+	}
+
+	if apiErr := validateConfigDocument(ConfigDocument{Version: ConfigDocumentVersion}); apiErr != nil {
+		t.Errorf("expected an empty document to be valid, got %+v", apiErr)
+	}
+}
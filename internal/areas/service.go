@@ -18,14 +18,23 @@ type Service interface {
 	GetArea(ctx context.Context, areaID pgtype.UUID) (*AreaWithCountOutput, *rest.ApiErr)
 	UpdateArea(ctx context.Context, areaID pgtype.UUID, input UpdateAreaInput) (*AreaOutput, *rest.ApiErr)
 	DeleteArea(ctx context.Context, areaID pgtype.UUID) *rest.ApiErr
+	MergeArea(ctx context.Context, sourceID, targetID pgtype.UUID, duplicates string) (*MergeAreaOutput, *rest.ApiErr)
+	ExportConfig(ctx context.Context) (*ConfigDocument, *rest.ApiErr)
+	ImportConfig(ctx context.Context, document ConfigDocument) (*ImportConfigResult, *rest.ApiErr)
+}
+
+// TxBeginner starts the transactions used by operations that must be atomic (e.g. *pgxpool.Pool)
+type TxBeginner interface {
+	Begin(ctx context.Context) (pgx.Tx, error)
 }
 
 type svc struct {
 	repo repo.Querier
+	db   TxBeginner
 }
 
-func NewService(repo repo.Querier) Service {
-	return &svc{repo: repo}
+func NewService(repo repo.Querier, db TxBeginner) Service {
+	return &svc{repo: repo, db: db}
 }
 
 func (s *svc) CreateArea(ctx context.Context, input CreateAreaInput) (*AreaOutput, *rest.ApiErr) {
@@ -138,6 +147,96 @@ func (s *svc) DeleteArea(ctx context.Context, areaID pgtype.UUID) *rest.ApiErr {
 	return nil
 }
 
+// MergeArea moves every product of sourceID to targetID and deletes sourceID, all in one transaction.
+// duplicates decides what happens to source products whose code already exists in the target.
+func (s *svc) MergeArea(ctx context.Context, sourceID, targetID pgtype.UUID, duplicates string) (*MergeAreaOutput, *rest.ApiErr) {
+	if sourceID == targetID {
+		return nil, rest.NewBadRequestError("area de origem e destino devem ser diferentes")
+	}
+
+	if _, err := s.repo.FindAreaByID(ctx, sourceID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, rest.NewNotFoundError("area nao encontrada")
+		}
+		return nil, s.handleDBError(err)
+	}
+	target, err := s.repo.FindAreaByID(ctx, targetID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, rest.NewNotFoundError("area de destino nao encontrada")
+		}
+		return nil, s.handleDBError(err)
+	}
+
+	output := &MergeAreaOutput{
+		SourceID: sourceID,
+		Target:   *toAreaOutput(target),
+	}
+
+	var conflicts []rest.Causes
+	err = s.inTx(ctx, func(q repo.Querier) error {
+		dups, err := q.ListDuplicateProductsBetweenAreas(ctx, repo.ListDuplicateProductsBetweenAreasParams{
+			SourceAreaID: sourceID,
+			TargetAreaID: targetID,
+		})
+		if err != nil {
+			return err
+		}
+
+		ids := make([]pgtype.UUID, 0, len(dups))
+		for _, dup := range dups {
+			ids = append(ids, dup.ID)
+			output.DuplicateCodes = append(output.DuplicateCodes, dup.Code)
+			conflicts = append(conflicts, rest.Causes{Field: "code", Message: dup.Code})
+		}
+
+		if len(dups) > 0 {
+			switch duplicates {
+			case MergeDuplicatesKeepBoth:
+			case MergeDuplicatesKeepTarget:
+				if output.DuplicatesRemoved, err = q.DeleteProductsByIDs(ctx, ids); err != nil {
+					return err
+				}
+			default:
+				return errMergeConflict
+			}
+		}
+
+		if output.Moved, err = q.MoveAreaProducts(ctx, repo.MoveAreaProductsParams{
+			TargetAreaID: targetID,
+			SourceAreaID: sourceID,
+		}); err != nil {
+			return err
+		}
+		return q.DeleteArea(ctx, sourceID)
+	})
+	if errors.Is(err, errMergeConflict) {
+		return nil, rest.NewBadRequestValidationError("codigos existem nas duas areas", conflicts)
+	}
+	if err != nil {
+		return nil, s.handleDBError(err)
+	}
+
+	return output, nil
+}
+
+// errMergeConflict aborts a merge that found duplicate codes with MergeDuplicatesFail
+var errMergeConflict = errors.New("duplicate codes between areas")
+
+// inTx runs fn with queries bound to a transaction, committing only when fn succeeds
+func (s *svc) inTx(ctx context.Context, fn func(q repo.Querier) error) error {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := fn(repo.New(tx)); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
 func (s *svc) handleDBError(err error) *rest.ApiErr {
 	if errors.Is(err, pgx.ErrNoRows) {
 		return rest.NewNotFoundError("recurso nao encontrado")
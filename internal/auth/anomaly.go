@@ -0,0 +1,76 @@
+package auth
+
+import (
+	"net"
+	"regexp"
+	"strings"
+)
+
+// AnomalyMode controls what happens when a refresh token is used from a client
+// that looks different from the one it was issued to
+type AnomalyMode string
+
+const (
+	AnomalyModeOff    AnomalyMode = "off"    // No checks
+	AnomalyModeLog    AnomalyMode = "log"    // Log the anomaly and allow the refresh
+	AnomalyModeReauth AnomalyMode = "reauth" // Revoke the token and require a new login
+	AnomalyModeRevoke AnomalyMode = "revoke" // Revoke the whole token family and require a new login
+)
+
+// ParseAnomalyMode converts a config value to an AnomalyMode, defaulting to log
+func ParseAnomalyMode(mode string) AnomalyMode {
+	switch AnomalyMode(strings.ToLower(strings.TrimSpace(mode))) {
+	case AnomalyModeOff:
+		return AnomalyModeOff
+	case AnomalyModeReauth:
+		return AnomalyModeReauth
+	case AnomalyModeRevoke:
+		return AnomalyModeRevoke
+	default:
+		return AnomalyModeLog
+	}
+}
+
+var versionPattern = regexp.MustCompile(`[0-9._]+`)
+
+// detectRefreshAnomaly compares the client of a refresh request with the one the token
+// was issued to and returns the reasons it looks different (empty when it doesn't).
+// Browser version bumps and IP changes inside the same network are not reported.
+func detectRefreshAnomaly(stored *TokenData, userAgent, ip string) []string {
+	var reasons []string
+
+	if stored.UserAgent != "" && normalizeUserAgent(stored.UserAgent) != normalizeUserAgent(userAgent) {
+		reasons = append(reasons, "user_agent")
+	}
+
+	if stored.IP != "" && !sameNetwork(stored.IP, ip) {
+		reasons = append(reasons, "ip")
+	}
+
+	return reasons
+}
+
+// normalizeUserAgent strips version numbers so browser updates don't count as a different client
+func normalizeUserAgent(userAgent string) string {
+	return versionPattern.ReplaceAllString(strings.ToLower(userAgent), "")
+}
+
+// sameNetwork reports whether both IPs are in the same /24 (IPv4) or /64 (IPv6) network
+func sameNetwork(a, b string) bool {
+	ipA := net.ParseIP(a)
+	ipB := net.ParseIP(b)
+	if ipA == nil || ipB == nil {
+		return a == b
+	}
+
+	if v4A, v4B := ipA.To4(), ipB.To4(); v4A != nil || v4B != nil {
+		if v4A == nil || v4B == nil {
+			return false
+		}
+		mask := net.CIDRMask(24, 32)
+		return v4A.Mask(mask).Equal(v4B.Mask(mask))
+	}
+
+	mask := net.CIDRMask(64, 128)
+	return ipA.Mask(mask).Equal(ipB.Mask(mask))
+}
@@ -0,0 +1,168 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/freitasmatheusrn/lifecycle-monitor/internal/user"
+	"github.com/freitasmatheusrn/lifecycle-monitor/pkg/auth"
+	"github.com/freitasmatheusrn/lifecycle-monitor/pkg/parser"
+	"github.com/freitasmatheusrn/lifecycle-monitor/pkg/rest"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+)
+
+// APIKeyScope limits what requests authenticated by an API key may do
+type APIKeyScope string
+
+const (
+	APIKeyScopeRead  APIKeyScope = "read"  // Only GET/HEAD requests and the routes in ReadOnlyRoutes
+	APIKeyScopeWrite APIKeyScope = "write" // Any non-admin request
+)
+
+// APIKeyPrefix starts every API key, telling them apart from JWTs in the Authorization header
+const APIKeyPrefix = "lm_"
+
+// apiKeyContextKey marks requests authenticated by an API key, so the JWT middleware is skipped
+const apiKeyContextKey = "api_key"
+
+// CreateAPIKey mints a key owned by owner. The plain key is only returned here; only its hash is stored.
+func (s *service) CreateAPIKey(ctx context.Context, input CreateAPIKeyInput, owner user.CurrentUser) (*CreateAPIKeyOutput, *rest.ApiErr) {
+	input.Name = strings.TrimSpace(input.Name)
+	if input.Name == "" {
+		return nil, rest.NewBadRequestError("nome da chave de API e obrigatorio")
+	}
+	if input.Scope == "" {
+		input.Scope = APIKeyScopeRead
+	}
+	if input.Scope != APIKeyScopeRead && input.Scope != APIKeyScopeWrite {
+		return nil, rest.NewBadRequestError("escopo de chave de API invalido, use read ou write")
+	}
+
+	secret, err := auth.GenerateRefreshToken()
+	if err != nil {
+		return nil, rest.NewInternalServerError("erro ao gerar chave de API")
+	}
+	key := APIKeyPrefix + secret
+
+	ownerID, _ := parser.PgUUIDToString(owner.ID)
+	data := APIKeyData{
+		ID:        auth.GenerateFamilyID(),
+		Name:      input.Name,
+		Scope:     input.Scope,
+		UserID:    ownerID,
+		Email:     owner.Email,
+		CreatedAt: time.Now(),
+	}
+
+	if err := s.apiKeyRepo.StoreAPIKey(ctx, auth.HashToken(key), data); err != nil {
+		s.logger.Error("failed to store api key", zap.Error(err))
+		return nil, rest.NewInternalServerError("erro ao gerar chave de API")
+	}
+
+	s.logger.Info("api key created",
+		zap.String("id", data.ID),
+		zap.String("name", data.Name),
+		zap.String("scope", string(data.Scope)),
+		zap.String("created_by", data.Email),
+	)
+	return &CreateAPIKeyOutput{APIKeyData: data, Key: key}, nil
+}
+
+// ListAPIKeys returns every active key, without the keys themselves
+func (s *service) ListAPIKeys(ctx context.Context) ([]APIKeyData, *rest.ApiErr) {
+	keys, err := s.apiKeyRepo.ListAPIKeys(ctx)
+	if err != nil {
+		s.logger.Error("failed to list api keys", zap.Error(err))
+		return nil, rest.NewInternalServerError("erro ao listar chaves de API")
+	}
+	return keys, nil
+}
+
+// RevokeAPIKey deletes a key; requests using it are rejected right away
+func (s *service) RevokeAPIKey(ctx context.Context, id string) *rest.ApiErr {
+	found, err := s.apiKeyRepo.RevokeAPIKey(ctx, id)
+	if err != nil {
+		s.logger.Error("failed to revoke api key", zap.String("id", id), zap.Error(err))
+		return rest.NewInternalServerError("erro ao revogar chave de API")
+	}
+	if !found {
+		return rest.NewNotFoundError("chave de API nao encontrada")
+	}
+
+	s.logger.Info("api key revoked", zap.String("id", id))
+	return nil
+}
+
+// AuthenticateAPIKey returns the data of a valid key
+func (s *service) AuthenticateAPIKey(ctx context.Context, key string) (*APIKeyData, *rest.ApiErr) {
+	data, err := s.apiKeyRepo.GetAPIKey(ctx, auth.HashToken(key))
+	if err != nil {
+		s.logger.Error("failed to get api key", zap.Error(err))
+		return nil, rest.NewInternalServerError("erro ao validar chave de API")
+	}
+	if data == nil {
+		return nil, rest.NewUnauthorizedRequestError("chave de API invalida ou revogada")
+	}
+	return data, nil
+}
+
+// ReadOnlyRoutes marks routes that only read even though their method isn't GET/HEAD (e.g.
+// POST /products/batch-get, whose IDs go in the body), so read scope keys may call them
+type ReadOnlyRoutes map[string]bool
+
+// Add marks route as read only and returns it. Routes are added while the server is set up,
+// before any request is served.
+func (r ReadOnlyRoutes) Add(route *echo.Route) *echo.Route {
+	r[route.Method+" "+route.Path] = true
+	return route
+}
+
+// allows reports whether a read scope key may make the request
+func (r ReadOnlyRoutes) allows(c echo.Context) bool {
+	method := c.Request().Method
+	return method == http.MethodGet || method == http.MethodHead || r[method+" "+c.Path()]
+}
+
+// APIKeyMiddleware authenticates "Authorization: Bearer lm_..." requests and enforces the key scope.
+// Other requests pass through untouched to the JWT middleware, which skips keyed ones (see IsAPIKeyRequest).
+func APIKeyMiddleware(service Service, readOnly ReadOnlyRoutes) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			key, ok := strings.CutPrefix(c.Request().Header.Get(echo.HeaderAuthorization), "Bearer ")
+			if !ok || !strings.HasPrefix(key, APIKeyPrefix) {
+				return next(c)
+			}
+
+			data, apiErr := service.AuthenticateAPIKey(c.Request().Context(), key)
+			if apiErr != nil {
+				return apiErr
+			}
+
+			if data.Scope != APIKeyScopeWrite && !readOnly.allows(c) {
+				return rest.NewForbiddenError("chave de API somente leitura")
+			}
+
+			ownerID, err := parser.PgUUIDFromString(data.UserID)
+			if err != nil {
+				return rest.NewUnauthorizedRequestError("chave de API invalida ou revogada")
+			}
+
+			c.Set(apiKeyContextKey, data)
+			user.SetCurrentUser(c, user.CurrentUser{
+				ID:       ownerID,
+				Email:    data.Email,
+				APIKeyID: data.ID,
+			})
+			return next(c)
+		}
+	}
+}
+
+// IsAPIKeyRequest reports whether APIKeyMiddleware authenticated the request; used as the JWT middleware skipper
+func IsAPIKeyRequest(c echo.Context) bool {
+	_, ok := c.Get(apiKeyContextKey).(*APIKeyData)
+	return ok
+}
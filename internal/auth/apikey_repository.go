@@ -0,0 +1,125 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	apiKeyPrefix   = "api_key:"    // api_key:<hash> -> APIKeyData
+	apiKeyIDPrefix = "api_key_id:" // api_key_id:<id> -> hash da chave, para revogar por id
+	apiKeysSetKey  = "api_keys"    // ids de todas as chaves, para listar
+)
+
+// APIKeyData is what is stored for an API key; the key itself is only kept hashed
+type APIKeyData struct {
+	ID        string      `json:"id"`
+	Name      string      `json:"name"`
+	Scope     APIKeyScope `json:"scope"`
+	UserID    string      `json:"user_id"` // admin que criou a chave; as requisicoes agem em nome dele
+	Email     string      `json:"email"`
+	CreatedAt time.Time   `json:"created_at"`
+}
+
+type APIKeyRepository interface {
+	StoreAPIKey(ctx context.Context, keyHash string, data APIKeyData) error
+	GetAPIKey(ctx context.Context, keyHash string) (*APIKeyData, error)
+	ListAPIKeys(ctx context.Context) ([]APIKeyData, error)
+	RevokeAPIKey(ctx context.Context, id string) (bool, error)
+}
+
+type apiKeyRepository struct {
+	client *redis.Client
+}
+
+func NewAPIKeyRepository(client *redis.Client) APIKeyRepository {
+	return &apiKeyRepository{client: client}
+}
+
+func (r *apiKeyRepository) StoreAPIKey(ctx context.Context, keyHash string, data APIKeyData) error {
+	jsonData, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("failed to marshal api key data: %w", err)
+	}
+
+	// Chaves nao expiram: valem ate serem revogadas
+	pipe := r.client.TxPipeline()
+	pipe.Set(ctx, apiKeyPrefix+keyHash, jsonData, 0)
+	pipe.Set(ctx, apiKeyIDPrefix+data.ID, keyHash, 0)
+	pipe.SAdd(ctx, apiKeysSetKey, data.ID)
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to store api key: %w", err)
+	}
+	return nil
+}
+
+func (r *apiKeyRepository) GetAPIKey(ctx context.Context, keyHash string) (*APIKeyData, error) {
+	data, err := r.client.Get(ctx, apiKeyPrefix+keyHash).Result()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get api key: %w", err)
+	}
+
+	var keyData APIKeyData
+	if err := json.Unmarshal([]byte(data), &keyData); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal api key data: %w", err)
+	}
+	return &keyData, nil
+}
+
+func (r *apiKeyRepository) ListAPIKeys(ctx context.Context) ([]APIKeyData, error) {
+	ids, err := r.client.SMembers(ctx, apiKeysSetKey).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list api keys: %w", err)
+	}
+
+	keys := make([]APIKeyData, 0, len(ids))
+	for _, id := range ids {
+		keyHash, err := r.client.Get(ctx, apiKeyIDPrefix+id).Result()
+		if err == redis.Nil {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to get api key %s: %w", id, err)
+		}
+
+		data, err := r.GetAPIKey(ctx, keyHash)
+		if err != nil {
+			return nil, err
+		}
+		if data != nil {
+			keys = append(keys, *data)
+		}
+	}
+
+	sort.Slice(keys, func(i, j int) bool { return keys[i].CreatedAt.Before(keys[j].CreatedAt) })
+	return keys, nil
+}
+
+// RevokeAPIKey deletes the key with id; returns false when it doesn't exist
+func (r *apiKeyRepository) RevokeAPIKey(ctx context.Context, id string) (bool, error) {
+	keyHash, err := r.client.Get(ctx, apiKeyIDPrefix+id).Result()
+	if err == redis.Nil {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to find api key: %w", err)
+	}
+
+	pipe := r.client.TxPipeline()
+	pipe.Del(ctx, apiKeyPrefix+keyHash, apiKeyIDPrefix+id)
+	pipe.SRem(ctx, apiKeysSetKey, id)
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return false, fmt.Errorf("failed to revoke api key: %w", err)
+	}
+	return true, nil
+}
@@ -41,3 +41,14 @@ type SigninResult struct {
 	User   SigninOutput
 	Tokens *TokenPair
 }
+
+type CreateAPIKeyInput struct {
+	Name  string      `json:"name"`
+	Scope APIKeyScope `json:"scope"` // read (default) or write
+}
+
+// CreateAPIKeyOutput is the only response carrying the plain key
+type CreateAPIKeyOutput struct {
+	APIKeyData
+	Key string `json:"key"`
+}
@@ -3,6 +3,7 @@ package auth
 import (
 	"net/http"
 
+	"github.com/freitasmatheusrn/lifecycle-monitor/internal/user"
 	"github.com/freitasmatheusrn/lifecycle-monitor/pkg/auth"
 	"github.com/freitasmatheusrn/lifecycle-monitor/pkg/cookie"
 	"github.com/freitasmatheusrn/lifecycle-monitor/pkg/htmx"
@@ -118,6 +119,43 @@ func (h *Handler) LogoutAll(c echo.Context) error {
 	})
 }
 
+// CreateAPIKey handles POST /admin/api-keys
+// The key is only shown in this response
+func (h *Handler) CreateAPIKey(c echo.Context) error {
+	currentUser, err := user.GetCurrentUser(c)
+	if err != nil {
+		return rest.NewUnauthorizedRequestError("usuario nao autenticado")
+	}
+
+	var input CreateAPIKeyInput
+	if err := c.Bind(&input); err != nil {
+		return rest.NewUnprocessableEntity("erro ao processar dados")
+	}
+
+	output, apiErr := h.service.CreateAPIKey(c.Request().Context(), input, currentUser)
+	if apiErr != nil {
+		return apiErr
+	}
+	return c.JSON(http.StatusCreated, output)
+}
+
+// ListAPIKeys handles GET /admin/api-keys
+func (h *Handler) ListAPIKeys(c echo.Context) error {
+	keys, apiErr := h.service.ListAPIKeys(c.Request().Context())
+	if apiErr != nil {
+		return apiErr
+	}
+	return c.JSON(http.StatusOK, keys)
+}
+
+// RevokeAPIKey handles DELETE /admin/api-keys/:id
+func (h *Handler) RevokeAPIKey(c echo.Context) error {
+	if apiErr := h.service.RevokeAPIKey(c.Request().Context(), c.Param("id")); apiErr != nil {
+		return apiErr
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
 func (h *Handler) setTokenCookies(c echo.Context, tokens *TokenPair) {
 	cookie.SetAccessTokenCookie(c, tokens.AccessToken, h.accessTokenExp)
 	cookie.SetRefreshTokenCookie(c, tokens.RefreshToken, h.refreshTokenExp)
@@ -0,0 +1,76 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/freitasmatheusrn/lifecycle-monitor/pkg/rest"
+	"github.com/labstack/echo/v4"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+const authRateLimitPrefix = "auth_rate:" // auth_rate:<rota>:<ip> -> tentativas na janela atual
+
+// RateLimiter counts attempts per key in fixed windows
+type RateLimiter interface {
+	// Allow records an attempt; when over the limit it returns false and the time until the window resets
+	Allow(ctx context.Context, key string) (bool, time.Duration, error)
+}
+
+type redisRateLimiter struct {
+	client *redis.Client
+	limit  int
+	window time.Duration
+}
+
+// NewRateLimiter allows limit attempts per key in each window, shared by every instance through Redis
+func NewRateLimiter(client *redis.Client, limit int, window time.Duration) RateLimiter {
+	return &redisRateLimiter{client: client, limit: limit, window: window}
+}
+
+func (r *redisRateLimiter) Allow(ctx context.Context, key string) (bool, time.Duration, error) {
+	redisKey := authRateLimitPrefix + key
+
+	// The window starts at the first attempt; NX keeps later attempts from extending it
+	pipe := r.client.TxPipeline()
+	count := pipe.Incr(ctx, redisKey)
+	pipe.ExpireNX(ctx, redisKey, r.window)
+	ttl := pipe.TTL(ctx, redisKey)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return false, 0, fmt.Errorf("failed to count auth attempt: %w", err)
+	}
+
+	if count.Val() > int64(r.limit) {
+		return false, ttl.Val(), nil
+	}
+	return true, 0, nil
+}
+
+// RateLimitMiddleware throttles a route per client IP, answering 429 with Retry-After once the
+// limiter refuses. Applied to the auth routes only: the automatic refresh done by
+// AutoRefreshMiddleware on every request doesn't go through them and isn't counted.
+// A Redis failure lets the request through, so an outage doesn't lock everyone out.
+func RateLimitMiddleware(limiter RateLimiter, logger *zap.Logger) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			allowed, retryAfter, err := limiter.Allow(c.Request().Context(), c.Path()+":"+c.RealIP())
+			if err != nil {
+				logger.Warn("auth rate limiter unavailable, allowing request",
+					zap.String("path", c.Path()),
+					zap.Error(err),
+				)
+				return next(c)
+			}
+			if !allowed {
+				seconds := max(1, int(math.Ceil(retryAfter.Seconds())))
+				c.Response().Header().Set("Retry-After", strconv.Itoa(seconds))
+				return rest.NewTooManyRequestsError("muitas tentativas, tente novamente mais tarde")
+			}
+			return next(c)
+		}
+	}
+}
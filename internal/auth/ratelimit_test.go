@@ -0,0 +1,76 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/freitasmatheusrn/lifecycle-monitor/pkg/rest"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+)
+
+// memoryRateLimiter allows limit attempts per key and records the keys seen
+type memoryRateLimiter struct {
+	limit    int
+	attempts map[string]int
+	err      error
+}
+
+func (m *memoryRateLimiter) Allow(ctx context.Context, key string) (bool, time.Duration, error) {
+	if m.err != nil {
+		return false, 0, m.err
+	}
+	m.attempts[key]++
+	if m.attempts[key] > m.limit {
+		return false, 90*time.Second + 500*time.Millisecond, nil
+	}
+	return true, 0, nil
+}
+
+func TestRateLimitMiddleware(t *testing.T) {
+	limiter := &memoryRateLimiter{limit: 2, attempts: map[string]int{}}
+	e := echo.New()
+	handler := RateLimitMiddleware(limiter, zap.NewNop())(func(c echo.Context) error { return c.NoContent(http.StatusOK) })
+
+	call := func(path, ip string) (*httptest.ResponseRecorder, error) {
+		req := httptest.NewRequest(http.MethodPost, path, nil)
+		req.RemoteAddr = ip + ":5000"
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath(path)
+		return rec, handler(c)
+	}
+
+	for range 2 {
+		if _, err := call("/signin", "10.0.0.1"); err != nil {
+			t.Fatalf("unexpected error within the limit: %v", err)
+		}
+	}
+
+	rec, err := call("/signin", "10.0.0.1")
+	var apiErr *rest.ApiErr
+	if !errors.As(err, &apiErr) || apiErr.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected 429 over the limit, got %v", err)
+	}
+	if got := rec.Header().Get("Retry-After"); got != "91" {
+		t.Errorf("expected Retry-After 91, got %q", got)
+	}
+
+	// Other IPs and other auth routes have their own counters
+	if _, err := call("/signin", "10.0.0.2"); err != nil {
+		t.Errorf("expected another IP to be allowed, got %v", err)
+	}
+	if _, err := call("/signup", "10.0.0.1"); err != nil {
+		t.Errorf("expected another route to be allowed, got %v", err)
+	}
+
+	// Redis down: the request goes through
+	limiter.err = errors.New("connection refused")
+	if _, err := call("/signin", "10.0.0.1"); err != nil {
+		t.Errorf("expected the limiter to fail open, got %v", err)
+	}
+}
@@ -0,0 +1,300 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	repo "github.com/freitasmatheusrn/lifecycle-monitor/internal/database/postgres/sqlc"
+	"github.com/freitasmatheusrn/lifecycle-monitor/pkg/auth"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+)
+
+// memoryTokenRepository keeps refresh tokens and their families in maps for testing
+type memoryTokenRepository struct {
+	mu              sync.Mutex
+	tokens          map[string]TokenData     // por hash
+	rotated         map[string]string        // hash -> family
+	grace           map[string]RotationGrace // hash -> par emitido
+	revokedFamilies []string
+}
+
+func newMemoryTokenRepository() *memoryTokenRepository {
+	return &memoryTokenRepository{tokens: map[string]TokenData{}, rotated: map[string]string{}, grace: map[string]RotationGrace{}}
+}
+
+func (m *memoryTokenRepository) StoreToken(ctx context.Context, tokenHash, userID, familyID, userAgent, ip string, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.tokens[tokenHash] = TokenData{UserID: userID, FamilyID: familyID, CreatedAt: time.Now(), UserAgent: userAgent, IP: ip}
+	return nil
+}
+
+func (m *memoryTokenRepository) GetToken(ctx context.Context, tokenHash string) (*TokenData, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	data, ok := m.tokens[tokenHash]
+	if !ok {
+		return nil, nil
+	}
+	return &data, nil
+}
+
+func (m *memoryTokenRepository) RevokeToken(ctx context.Context, tokenHash string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.tokens, tokenHash)
+	return nil
+}
+
+func (m *memoryTokenRepository) RevokeTokenFamily(ctx context.Context, familyID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.revokedFamilies = append(m.revokedFamilies, familyID)
+	for hash, data := range m.tokens {
+		if data.FamilyID == familyID {
+			delete(m.tokens, hash)
+		}
+	}
+	return nil
+}
+
+func (m *memoryTokenRepository) RevokeAllUserTokens(ctx context.Context, userID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for hash, data := range m.tokens {
+		if data.UserID == userID {
+			delete(m.tokens, hash)
+		}
+	}
+	return nil
+}
+
+func (m *memoryTokenRepository) IsTokenRevoked(ctx context.Context, tokenHash string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	_, ok := m.tokens[tokenHash]
+	return !ok, nil
+}
+
+func (m *memoryTokenRepository) MarkTokenRotated(ctx context.Context, tokenHash, familyID string, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.rotated[tokenHash] = familyID
+	return nil
+}
+
+func (m *memoryTokenRepository) GetRotatedTokenFamily(ctx context.Context, tokenHash string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.rotated[tokenHash], nil
+}
+
+func (m *memoryTokenRepository) StoreRotationGrace(ctx context.Context, tokenHash string, grace RotationGrace, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.grace[tokenHash] = grace
+	return nil
+}
+
+func (m *memoryTokenRepository) GetRotationGrace(ctx context.Context, tokenHash string) (*RotationGrace, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	grace, ok := m.grace[tokenHash]
+	if !ok {
+		return nil, nil
+	}
+	return &grace, nil
+}
+
+// refreshQuerier resolves any user id to the same user
+type refreshQuerier struct {
+	repo.Querier
+}
+
+func (q *refreshQuerier) FindByID(ctx context.Context, id pgtype.UUID) (repo.User, error) {
+	return repo.User{ID: id, Email: "user@example.com"}, nil
+}
+
+func TestRefreshTokensReuse(t *testing.T) {
+	tokens := newMemoryTokenRepository()
+	svc := &service{
+		repo:            &refreshQuerier{},
+		tokenRepo:       tokens,
+		jwtSecret:       "0123456789abcdef0123456789abcdef",
+		accessTokenExp:  900,
+		refreshTokenExp: 3600,
+		anomalyMode:     AnomalyModeOff,
+		logger:          zap.NewNop(),
+	}
+
+	const userID = "0b6f1a52-8f4e-4d5e-9a37-1c2d3e4f5a6b"
+	const familyID = "family-1"
+	first := "first-refresh-token"
+	tokens.StoreToken(t.Context(), auth.HashToken(first), userID, familyID, "browser", "10.0.0.1", time.Hour)
+
+	rotated, apiErr := svc.RefreshTokens(t.Context(), first, "browser", "10.0.0.1")
+	if apiErr != nil {
+		t.Fatalf("unexpected error on first refresh: %v", apiErr.Message)
+	}
+	if tokens.rotated[auth.HashToken(first)] != familyID {
+		t.Fatalf("expected the rotated token to be recorded under %q", familyID)
+	}
+
+	_, apiErr = svc.RefreshTokens(t.Context(), first, "attacker", "10.0.0.2")
+	if apiErr == nil || apiErr.Code != http.StatusUnauthorized {
+		t.Fatalf("expected unauthorized on reuse, got %v", apiErr)
+	}
+	if len(tokens.revokedFamilies) != 1 || tokens.revokedFamilies[0] != familyID {
+		t.Fatalf("expected family %q to be revoked, got %v", familyID, tokens.revokedFamilies)
+	}
+
+	// The token issued by the legitimate rotation belongs to the same chain and must be dead too
+	if _, apiErr := svc.RefreshTokens(t.Context(), rotated.RefreshToken, "browser", "10.0.0.1"); apiErr == nil {
+		t.Fatal("expected the latest token of the family to be revoked")
+	}
+}
+
+func TestRefreshTokensUnknown(t *testing.T) {
+	tokens := newMemoryTokenRepository()
+	svc := &service{repo: &refreshQuerier{}, tokenRepo: tokens, anomalyMode: AnomalyModeOff, logger: zap.NewNop()}
+
+	_, apiErr := svc.RefreshTokens(t.Context(), "never-issued", "browser", "10.0.0.1")
+	if apiErr == nil || apiErr.Code != http.StatusUnauthorized {
+		t.Fatalf("expected unauthorized, got %v", apiErr)
+	}
+	if len(tokens.revokedFamilies) != 0 {
+		t.Errorf("expected no family revocation for an unknown token, got %v", tokens.revokedFamilies)
+	}
+}
+
+func TestRefreshTokensConcurrentSameToken(t *testing.T) {
+	tokens := newMemoryTokenRepository()
+	svc := &service{
+		repo:            &refreshQuerier{},
+		tokenRepo:       tokens,
+		jwtSecret:       "0123456789abcdef0123456789abcdef",
+		accessTokenExp:  900,
+		refreshTokenExp: 3600,
+		anomalyMode:     AnomalyModeOff,
+		logger:          zap.NewNop(),
+	}
+
+	const userID = "0b6f1a52-8f4e-4d5e-9a37-1c2d3e4f5a6b"
+	const familyID = "family-1"
+	shared := "shared-refresh-token"
+	tokens.StoreToken(t.Context(), auth.HashToken(shared), userID, familyID, "browser", "10.0.0.1", time.Hour)
+
+	// A page firing two requests at once sends the same refresh cookie on both
+	const requests = 2
+	var wg sync.WaitGroup
+	pairs := make([]*TokenPair, requests)
+	errs := make([]error, requests)
+	for i := range requests {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			pair, apiErr := svc.RefreshTokens(t.Context(), shared, "browser", "10.0.0.1")
+			if apiErr != nil {
+				errs[i] = apiErr
+				return
+			}
+			pairs[i] = pair
+		}()
+	}
+	wg.Wait()
+
+	for i := range requests {
+		if errs[i] != nil {
+			t.Fatalf("request %d: unexpected error: %v", i, errs[i])
+		}
+	}
+	if len(tokens.revokedFamilies) != 0 {
+		t.Fatalf("expected no family revocation, got %v", tokens.revokedFamilies)
+	}
+	for i, pair := range pairs {
+		if _, ok := tokens.tokens[auth.HashToken(pair.RefreshToken)]; !ok {
+			t.Errorf("request %d: expected the returned refresh token to still be valid", i)
+		}
+	}
+
+	// Once the race is over, the rotated token must still be rejected from another client
+	if _, apiErr := svc.RefreshTokens(t.Context(), shared, "attacker", "10.0.0.2"); apiErr == nil {
+		t.Fatal("expected reuse from a different client to be rejected")
+	}
+}
+
+func TestRefreshTokensGraceSkipsRevokedSuccessor(t *testing.T) {
+	tokens := newMemoryTokenRepository()
+	svc := &service{
+		repo:            &refreshQuerier{},
+		tokenRepo:       tokens,
+		jwtSecret:       "0123456789abcdef0123456789abcdef",
+		accessTokenExp:  900,
+		refreshTokenExp: 3600,
+		anomalyMode:     AnomalyModeOff,
+		logger:          zap.NewNop(),
+	}
+
+	const userID = "0b6f1a52-8f4e-4d5e-9a37-1c2d3e4f5a6b"
+	first := "first-refresh-token"
+	tokens.StoreToken(t.Context(), auth.HashToken(first), userID, "family-1", "browser", "10.0.0.1", time.Hour)
+
+	rotated, apiErr := svc.RefreshTokens(t.Context(), first, "browser", "10.0.0.1")
+	if apiErr != nil {
+		t.Fatalf("unexpected error on first refresh: %v", apiErr.Message)
+	}
+	if err := svc.Logout(t.Context(), rotated.RefreshToken); err != nil {
+		t.Fatalf("unexpected logout error: %v", err)
+	}
+
+	if _, apiErr := svc.RefreshTokens(t.Context(), first, "browser", "10.0.0.1"); apiErr == nil || apiErr.Code != http.StatusUnauthorized {
+		t.Fatalf("expected unauthorized after the successor was revoked, got %v", apiErr)
+	}
+}
+
+func TestRefreshTokensGraceStoresSealedPair(t *testing.T) {
+	tokens := newMemoryTokenRepository()
+	svc := &service{
+		repo:            &refreshQuerier{},
+		tokenRepo:       tokens,
+		jwtSecret:       "0123456789abcdef0123456789abcdef",
+		accessTokenExp:  900,
+		refreshTokenExp: 3600,
+		anomalyMode:     AnomalyModeOff,
+		logger:          zap.NewNop(),
+	}
+
+	const userID = "0b6f1a52-8f4e-4d5e-9a37-1c2d3e4f5a6b"
+	first := "first-refresh-token"
+	firstHash := auth.HashToken(first)
+	tokens.StoreToken(t.Context(), firstHash, userID, "family-1", "browser", "10.0.0.1", time.Hour)
+
+	rotated, apiErr := svc.RefreshTokens(t.Context(), first, "browser", "10.0.0.1")
+	if apiErr != nil {
+		t.Fatalf("unexpected error on first refresh: %v", apiErr.Message)
+	}
+
+	grace := tokens.grace[firstHash]
+	for _, secret := range []string{rotated.AccessToken, rotated.RefreshToken} {
+		if bytes.Contains(grace.SealedPair, []byte(secret)) {
+			t.Fatal("expected the grace record not to hold the issued pair in plain text")
+		}
+	}
+	if _, err := openPair(firstHash, grace.SealedPair); err == nil {
+		t.Error("expected the stored hash not to open the sealed pair")
+	}
+
+	// The client holding the old cookie still gets the same pair back
+	pair, apiErr := svc.RefreshTokens(t.Context(), first, "browser", "10.0.0.1")
+	if apiErr != nil {
+		t.Fatalf("unexpected error within the grace window: %v", apiErr.Message)
+	}
+	if *pair != *rotated {
+		t.Errorf("expected the rotated pair %+v, got %+v", rotated, pair)
+	}
+}
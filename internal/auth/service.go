@@ -2,44 +2,61 @@ package auth
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"net/url"
 	"time"
 
 	"github.com/freitasmatheusrn/lifecycle-monitor/internal/database"
 	repo "github.com/freitasmatheusrn/lifecycle-monitor/internal/database/postgres/sqlc"
+	"github.com/freitasmatheusrn/lifecycle-monitor/internal/user"
 	"github.com/freitasmatheusrn/lifecycle-monitor/pkg/auth"
 	"github.com/freitasmatheusrn/lifecycle-monitor/pkg/parser"
 	"github.com/freitasmatheusrn/lifecycle-monitor/pkg/rest"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
 	"golang.org/x/crypto/bcrypt"
 )
 
+// refreshReuseGrace is how long a just-rotated refresh token is still answered with the
+// pair it was rotated into instead of being treated as reuse
+const refreshReuseGrace = 30 * time.Second
+
 type Service interface {
 	Signup(ctx context.Context, input SignupInput, userAgent, ip string) (*SignupResult, *rest.ApiErr)
 	Signin(ctx context.Context, credentials SigninInput, userAgent, ip string) (*SigninResult, *rest.ApiErr)
 	RefreshTokens(ctx context.Context, refreshToken, userAgent, ip string) (*TokenPair, *rest.ApiErr)
 	Logout(ctx context.Context, refreshToken string) error
 	LogoutAll(ctx context.Context, userID string) error
+	CreateAPIKey(ctx context.Context, input CreateAPIKeyInput, owner user.CurrentUser) (*CreateAPIKeyOutput, *rest.ApiErr)
+	ListAPIKeys(ctx context.Context) ([]APIKeyData, *rest.ApiErr)
+	RevokeAPIKey(ctx context.Context, id string) *rest.ApiErr
+	AuthenticateAPIKey(ctx context.Context, key string) (*APIKeyData, *rest.ApiErr)
 }
 
 type service struct {
 	repo            repo.Querier
 	tokenRepo       TokenRepository
+	apiKeyRepo      APIKeyRepository
 	jwtSecret       string
 	accessTokenExp  int
 	refreshTokenExp int
+	anomalyMode     AnomalyMode
+	logger          *zap.Logger
 }
 
-func NewService(querier repo.Querier, tokenRepo TokenRepository, jwtSecret string, accessExp, refreshExp int) Service {
+func NewService(querier repo.Querier, tokenRepo TokenRepository, apiKeyRepo APIKeyRepository, jwtSecret string, accessExp, refreshExp int, anomalyMode AnomalyMode, logger *zap.Logger) Service {
 	return &service{
 		repo:            querier,
 		tokenRepo:       tokenRepo,
+		apiKeyRepo:      apiKeyRepo,
 		jwtSecret:       jwtSecret,
 		accessTokenExp:  accessExp,
 		refreshTokenExp: refreshExp,
+		anomalyMode:     anomalyMode,
+		logger:          logger,
 	}
 }
 
@@ -121,7 +138,18 @@ func (s *service) RefreshTokens(ctx context.Context, refreshToken, userAgent, ip
 		return nil, rest.NewInternalServerError("erro ao validar token")
 	}
 	if tokenData == nil {
-		return nil, rest.NewUnauthorizedRequestError("refresh token inválido ou expirado")
+		pair, err := s.rotationGracePair(ctx, decodedToken, userAgent, ip)
+		if err != nil {
+			return nil, rest.NewInternalServerError("erro ao validar token")
+		}
+		if pair != nil {
+			return pair, nil
+		}
+		return nil, s.checkRefreshReuse(ctx, tokenHash, userAgent, ip)
+	}
+
+	if apiErr := s.checkRefreshAnomaly(ctx, tokenHash, tokenData, userAgent, ip); apiErr != nil {
+		return nil, apiErr
 	}
 
 	userID, err := parseUUID(tokenData.UserID)
@@ -134,9 +162,7 @@ func (s *service) RefreshTokens(ctx context.Context, refreshToken, userAgent, ip
 		return nil, rest.NewUnauthorizedRequestError("usuário não encontrado")
 	}
 
-	if err := s.tokenRepo.RevokeToken(ctx, tokenHash); err != nil {
-		return nil, rest.NewInternalServerError("erro ao revogar token antigo")
-	}
+	ttl := time.Duration(s.refreshTokenExp) * time.Second
 
 	newRefreshToken, err := auth.GenerateRefreshToken()
 	if err != nil {
@@ -144,7 +170,6 @@ func (s *service) RefreshTokens(ctx context.Context, refreshToken, userAgent, ip
 	}
 
 	newTokenHash := auth.HashToken(newRefreshToken)
-	ttl := time.Duration(s.refreshTokenExp) * time.Second
 
 	if err := s.tokenRepo.StoreToken(ctx, newTokenHash, tokenData.UserID, tokenData.FamilyID, userAgent, ip, ttl); err != nil {
 		return nil, rest.NewInternalServerError("erro ao armazenar novo token")
@@ -156,10 +181,151 @@ func (s *service) RefreshTokens(ctx context.Context, refreshToken, userAgent, ip
 		return nil, rest.NewInternalServerError("erro ao gerar access token")
 	}
 
-	return &TokenPair{
+	pair := TokenPair{
 		AccessToken:  accessToken,
 		RefreshToken: newRefreshToken,
-	}, nil
+	}
+
+	// The grace record must exist before the old token disappears, otherwise a request
+	// racing this one could find neither and be taken for a replay
+	sealedPair, err := sealPair(decodedToken, pair)
+	if err != nil {
+		return nil, rest.NewInternalServerError("erro ao revogar token antigo")
+	}
+	grace := RotationGrace{
+		SealedPair: sealedPair,
+		UserAgent:  userAgent,
+		IP:         ip,
+	}
+	if err := s.tokenRepo.StoreRotationGrace(ctx, tokenHash, grace, refreshReuseGrace); err != nil {
+		return nil, rest.NewInternalServerError("erro ao revogar token antigo")
+	}
+
+	// Keep the rotated hash around for as long as it would still have been valid, so a
+	// replay of it within that window is caught by checkRefreshReuse
+	if remaining := ttl - time.Since(tokenData.CreatedAt); remaining > 0 {
+		if err := s.tokenRepo.MarkTokenRotated(ctx, tokenHash, tokenData.FamilyID, remaining); err != nil {
+			return nil, rest.NewInternalServerError("erro ao revogar token antigo")
+		}
+	}
+
+	if err := s.tokenRepo.RevokeToken(ctx, tokenHash); err != nil {
+		return nil, rest.NewInternalServerError("erro ao revogar token antigo")
+	}
+
+	return &pair, nil
+}
+
+// rotationGracePair returns the pair already issued for a token rotated moments ago by
+// the same client, so concurrent requests carrying the same cookie don't look like a
+// replay. It returns nil when there is no grace record or the pair is no longer valid
+func (s *service) rotationGracePair(ctx context.Context, rotatedToken, userAgent, ip string) (*TokenPair, error) {
+	grace, err := s.tokenRepo.GetRotationGrace(ctx, auth.HashToken(rotatedToken))
+	if err != nil || grace == nil {
+		return nil, err
+	}
+	if grace.UserAgent != userAgent || grace.IP != ip {
+		return nil, nil
+	}
+
+	pair, err := openPair(rotatedToken, grace.SealedPair)
+	if err != nil {
+		return nil, err
+	}
+
+	// The successor may have been revoked since (logout, family revocation)
+	current, err := s.tokenRepo.GetToken(ctx, auth.HashToken(pair.RefreshToken))
+	if err != nil || current == nil {
+		return nil, err
+	}
+
+	return pair, nil
+}
+
+// sealPair encrypts the pair issued for a rotation with the token it replaced. Redis only
+// knows that token's hash, so the stored pair can be opened by the client holding the old
+// cookie and by no one reading the grace record.
+func sealPair(rotatedToken string, pair TokenPair) ([]byte, error) {
+	data, err := json.Marshal(pair)
+	if err != nil {
+		return nil, err
+	}
+	return auth.SealWithToken(rotatedToken, data)
+}
+
+// openPair decrypts a pair sealed by sealPair
+func openPair(rotatedToken string, sealed []byte) (*TokenPair, error) {
+	data, err := auth.OpenWithToken(rotatedToken, sealed)
+	if err != nil {
+		return nil, err
+	}
+	var pair TokenPair
+	if err := json.Unmarshal(data, &pair); err != nil {
+		return nil, err
+	}
+	return &pair, nil
+}
+
+// checkRefreshReuse handles a refresh token that is no longer stored. If it was already
+// rotated, someone is replaying an old token of the chain, so the whole family is revoked
+func (s *service) checkRefreshReuse(ctx context.Context, tokenHash, userAgent, ip string) *rest.ApiErr {
+	familyID, err := s.tokenRepo.GetRotatedTokenFamily(ctx, tokenHash)
+	if err != nil {
+		return rest.NewInternalServerError("erro ao validar token")
+	}
+	if familyID == "" {
+		return rest.NewUnauthorizedRequestError("refresh token inválido ou expirado")
+	}
+
+	s.logger.Warn("refresh token reuse detected",
+		zap.String("family_id", familyID),
+		zap.String("request_ip", ip),
+		zap.String("request_user_agent", userAgent),
+	)
+
+	if err := s.tokenRepo.RevokeTokenFamily(ctx, familyID); err != nil {
+		return rest.NewInternalServerError("erro ao revogar tokens")
+	}
+	return rest.NewUnauthorizedRequestError("refresh token reutilizado, faça login novamente")
+}
+
+// checkRefreshAnomaly applies the configured anomaly mode when the refresh request comes
+// from a client that differs from the one the token was issued to
+func (s *service) checkRefreshAnomaly(ctx context.Context, tokenHash string, tokenData *TokenData, userAgent, ip string) *rest.ApiErr {
+	if s.anomalyMode == AnomalyModeOff {
+		return nil
+	}
+
+	reasons := detectRefreshAnomaly(tokenData, userAgent, ip)
+	if len(reasons) == 0 {
+		return nil
+	}
+
+	s.logger.Warn("refresh token used from a different client",
+		zap.String("user_id", tokenData.UserID),
+		zap.String("family_id", tokenData.FamilyID),
+		zap.Strings("reasons", reasons),
+		zap.String("issued_ip", tokenData.IP),
+		zap.String("request_ip", ip),
+		zap.String("issued_user_agent", tokenData.UserAgent),
+		zap.String("request_user_agent", userAgent),
+		zap.String("mode", string(s.anomalyMode)),
+	)
+
+	switch s.anomalyMode {
+	case AnomalyModeReauth:
+		if err := s.tokenRepo.RevokeToken(ctx, tokenHash); err != nil {
+			return rest.NewInternalServerError("erro ao revogar token")
+		}
+		return rest.NewUnauthorizedRequestError("sessão iniciada em outro dispositivo, faça login novamente")
+	case AnomalyModeRevoke:
+		if err := s.tokenRepo.RevokeTokenFamily(ctx, tokenData.FamilyID); err != nil {
+			return rest.NewInternalServerError("erro ao revogar tokens")
+		}
+		return rest.NewUnauthorizedRequestError("sessão iniciada em outro dispositivo, faça login novamente")
+	default:
+		return nil
+	}
 }
 
 func (s *service) Logout(ctx context.Context, refreshToken string) error {
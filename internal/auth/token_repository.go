@@ -10,9 +10,11 @@ import (
 )
 
 const (
-	refreshTokenPrefix = "refresh_token:"
-	tokenFamilyPrefix  = "token_family:"
-	userTokensPrefix   = "user_tokens:"
+	refreshTokenPrefix  = "refresh_token:"
+	tokenFamilyPrefix   = "token_family:"
+	userTokensPrefix    = "user_tokens:"
+	rotatedTokenPrefix  = "rotated_token:"
+	rotationGracePrefix = "rotation_grace:"
 )
 
 type TokenData struct {
@@ -23,6 +25,15 @@ type TokenData struct {
 	IP        string    `json:"ip"`
 }
 
+// RotationGrace is the pair issued when a refresh token was rotated, together with the
+// client that rotated it, kept for a short while to answer concurrent refreshes. The pair
+// is sealed with the rotated token, so reading Redis doesn't hand out a live session.
+type RotationGrace struct {
+	SealedPair []byte `json:"sealed_pair"`
+	UserAgent  string `json:"user_agent"`
+	IP         string `json:"ip"`
+}
+
 type TokenRepository interface {
 	StoreToken(ctx context.Context, tokenHash, userID, familyID, userAgent, ip string, ttl time.Duration) error
 	GetToken(ctx context.Context, tokenHash string) (*TokenData, error)
@@ -30,6 +41,10 @@ type TokenRepository interface {
 	RevokeTokenFamily(ctx context.Context, familyID string) error
 	RevokeAllUserTokens(ctx context.Context, userID string) error
 	IsTokenRevoked(ctx context.Context, tokenHash string) (bool, error)
+	MarkTokenRotated(ctx context.Context, tokenHash, familyID string, ttl time.Duration) error
+	GetRotatedTokenFamily(ctx context.Context, tokenHash string) (string, error)
+	StoreRotationGrace(ctx context.Context, tokenHash string, grace RotationGrace, ttl time.Duration) error
+	GetRotationGrace(ctx context.Context, tokenHash string) (*RotationGrace, error)
 }
 
 type tokenRepository struct {
@@ -228,3 +243,62 @@ func (r *tokenRepository) IsTokenRevoked(ctx context.Context, tokenHash string)
 	}
 	return exists == 0, nil
 }
+
+// MarkTokenRotated remembers which family a rotated token belonged to, so that a later
+// attempt to use it again can be recognized as reuse
+func (r *tokenRepository) MarkTokenRotated(ctx context.Context, tokenHash, familyID string, ttl time.Duration) error {
+	key := rotatedTokenPrefix + tokenHash
+	if err := r.client.Set(ctx, key, familyID, ttl).Err(); err != nil {
+		return fmt.Errorf("failed to mark token as rotated: %w", err)
+	}
+	return nil
+}
+
+// GetRotatedTokenFamily returns the family of a previously rotated token, or an empty
+// string when the token was never rotated or the record has expired
+func (r *tokenRepository) GetRotatedTokenFamily(ctx context.Context, tokenHash string) (string, error) {
+	key := rotatedTokenPrefix + tokenHash
+	familyID, err := r.client.Get(ctx, key).Result()
+	if err == redis.Nil {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get rotated token: %w", err)
+	}
+	return familyID, nil
+}
+
+// StoreRotationGrace keeps the pair issued for a rotated token for ttl, so a request that
+// raced the rotation with the same token can be handed the same pair
+func (r *tokenRepository) StoreRotationGrace(ctx context.Context, tokenHash string, grace RotationGrace, ttl time.Duration) error {
+	jsonData, err := json.Marshal(grace)
+	if err != nil {
+		return fmt.Errorf("failed to marshal rotation grace: %w", err)
+	}
+
+	key := rotationGracePrefix + tokenHash
+	if err := r.client.Set(ctx, key, jsonData, ttl).Err(); err != nil {
+		return fmt.Errorf("failed to store rotation grace: %w", err)
+	}
+	return nil
+}
+
+// GetRotationGrace returns the pair issued for a rotated token, or nil once the grace
+// window has passed
+func (r *tokenRepository) GetRotationGrace(ctx context.Context, tokenHash string) (*RotationGrace, error) {
+	key := rotationGracePrefix + tokenHash
+	data, err := r.client.Get(ctx, key).Result()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rotation grace: %w", err)
+	}
+
+	var grace RotationGrace
+	if err := json.Unmarshal([]byte(data), &grace); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal rotation grace: %w", err)
+	}
+
+	return &grace, nil
+}
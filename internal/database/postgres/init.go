@@ -2,18 +2,59 @@ package postgres
 
 import (
 	"context"
+	"time"
 
-	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
 )
 
-func Init(conn string) (*pgx.Conn, error) {
+// PoolConfig holds the connection pool size and lifetimes. Zero values use the defaults.
+type PoolConfig struct {
+	MaxConns        int
+	MinConns        int
+	MaxConnLifetime time.Duration
+	MaxConnIdleTime time.Duration
+}
+
+// Valores padrao do pool, usados quando o campo correspondente nao e configurado
+const (
+	defaultMaxConns        = 10
+	defaultMinConns        = 2
+	defaultMaxConnLifetime = time.Hour
+	defaultMaxConnIdleTime = 30 * time.Minute
+)
+
+// apply sets the pool options on cfg, falling back to the defaults for unset values
+func (p PoolConfig) apply(cfg *pgxpool.Config) {
+	cfg.MaxConns = int32(valueOrDefault(p.MaxConns, defaultMaxConns))
+	cfg.MinConns = int32(min(valueOrDefault(p.MinConns, defaultMinConns), int(cfg.MaxConns)))
+	cfg.MaxConnLifetime = valueOrDefault(p.MaxConnLifetime, defaultMaxConnLifetime)
+	cfg.MaxConnIdleTime = valueOrDefault(p.MaxConnIdleTime, defaultMaxConnIdleTime)
+}
+
+func valueOrDefault[T int | time.Duration](value, fallback T) T {
+	if value <= 0 {
+		return fallback
+	}
+	return value
+}
+
+// Init opens the connection pool and checks the database is reachable. The pool replaces
+// broken connections on its own, so a dropped connection no longer takes the server down.
+func Init(conn string, pool PoolConfig) (*pgxpool.Pool, error) {
+	cfg, err := pgxpool.ParseConfig(conn)
+	if err != nil {
+		return nil, err
+	}
+	pool.apply(cfg)
+
 	ctx := context.Background()
-	db, err := pgx.Connect(ctx, conn)
+	db, err := pgxpool.NewWithConfig(ctx, cfg)
 	if err != nil {
 		return nil, err
 	}
 	err = db.Ping(ctx)
 	if err != nil {
+		db.Close()
 		return nil, err
 	}
 	return db, nil
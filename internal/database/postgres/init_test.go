@@ -0,0 +1,46 @@
+package postgres
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+func TestPoolConfigApply_Defaults(t *testing.T) {
+	cfg := &pgxpool.Config{}
+	PoolConfig{}.apply(cfg)
+
+	if cfg.MaxConns != 10 || cfg.MinConns != 2 {
+		t.Errorf("expected default pool 10/2, got %d/%d", cfg.MaxConns, cfg.MinConns)
+	}
+	if cfg.MaxConnLifetime != time.Hour || cfg.MaxConnIdleTime != 30*time.Minute {
+		t.Errorf("expected default lifetimes 1h/30m, got %v/%v", cfg.MaxConnLifetime, cfg.MaxConnIdleTime)
+	}
+}
+
+func TestPoolConfigApply_Overrides(t *testing.T) {
+	cfg := &pgxpool.Config{}
+	PoolConfig{
+		MaxConns:        25,
+		MinConns:        5,
+		MaxConnLifetime: 2 * time.Hour,
+		MaxConnIdleTime: time.Minute,
+	}.apply(cfg)
+
+	if cfg.MaxConns != 25 || cfg.MinConns != 5 {
+		t.Errorf("expected pool 25/5, got %d/%d", cfg.MaxConns, cfg.MinConns)
+	}
+	if cfg.MaxConnLifetime != 2*time.Hour || cfg.MaxConnIdleTime != time.Minute {
+		t.Errorf("expected lifetimes 2h/1m, got %v/%v", cfg.MaxConnLifetime, cfg.MaxConnIdleTime)
+	}
+}
+
+func TestPoolConfigApply_MinAboveMax(t *testing.T) {
+	cfg := &pgxpool.Config{}
+	PoolConfig{MaxConns: 4, MinConns: 8}.apply(cfg)
+
+	if cfg.MinConns != 4 {
+		t.Errorf("expected MinConns capped at MaxConns (4), got %d", cfg.MinConns)
+	}
+}
@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Lifecycle status is per product code, so alerts are snoozed per code as well
+CREATE TABLE lifecycle_alert_snoozes (
+    code TEXT PRIMARY KEY,
+    snoozed_until TIMESTAMP NOT NULL,
+    snoozed_status TEXT,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS lifecycle_alert_snoozes;
+-- +goose StatementEnd
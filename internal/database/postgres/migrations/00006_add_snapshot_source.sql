@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+-- source: caminho que gravou o snapshot (scheduler, manual, import, ...)
+-- run_id: execucao do scheduler que gerou o snapshot, quando aplicavel
+ALTER TABLE product_snapshots
+    ADD COLUMN source TEXT,
+    ADD COLUMN run_id UUID;
+
+CREATE INDEX idx_product_snapshots_run_id ON product_snapshots(run_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_product_snapshots_run_id;
+ALTER TABLE product_snapshots
+    DROP COLUMN IF EXISTS run_id,
+    DROP COLUMN IF EXISTS source;
+-- +goose StatementEnd
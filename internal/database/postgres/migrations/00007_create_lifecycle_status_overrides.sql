@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Manual corrections of the crawled lifecycle status, kept until a crawl returns the same status
+CREATE TABLE lifecycle_status_overrides (
+    code TEXT PRIMARY KEY,
+    status TEXT NOT NULL,
+    previous_status TEXT,
+    reason TEXT,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS lifecycle_status_overrides;
+-- +goose StatementEnd
@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Consecutive crawl failures per product code, used to back off codes that keep failing.
+-- The row is removed on the next successful crawl.
+CREATE TABLE crawl_failures (
+    code TEXT PRIMARY KEY,
+    consecutive_failures INTEGER NOT NULL DEFAULT 1,
+    last_error TEXT,
+    last_failed_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS crawl_failures;
+-- +goose StatementEnd
@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Last change of each product, used to tell whether the export changed since the last download
+ALTER TABLE products ADD COLUMN updated_at TIMESTAMP NOT NULL DEFAULT NOW();
+UPDATE products SET updated_at = created_at;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE products DROP COLUMN IF EXISTS updated_at;
+-- +goose StatementEnd
@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Lifecycle status changes waiting for the daily digest email (LIFECYCLE_DIGEST_CRON).
+-- Rows are removed once the digest with them is sent.
+CREATE TABLE lifecycle_digest_changes (
+    id BIGSERIAL PRIMARY KEY,
+    code TEXT NOT NULL,
+    old_status TEXT NOT NULL,
+    new_status TEXT NOT NULL,
+    detected_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS lifecycle_digest_changes;
+-- +goose StatementEnd
@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+-- History of the scheduled lifecycle update runs. id is the run_id written in the snapshots.
+-- uncollected_codes lists the products left out when the run timed out; they go first on the next run.
+CREATE TABLE collection_runs (
+    id UUID PRIMARY KEY,
+    started_at TIMESTAMP NOT NULL,
+    finished_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    total INTEGER NOT NULL,
+    succeeded INTEGER NOT NULL,
+    failed INTEGER NOT NULL,
+    uncollected_codes TEXT[] NOT NULL DEFAULT '{}',
+    timed_out BOOLEAN NOT NULL DEFAULT FALSE
+);
+
+CREATE INDEX idx_collection_runs_started_at ON collection_runs(started_at DESC);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS collection_runs;
+-- +goose StatementEnd
@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Lifecycle the supplier told us to expect for critical parts, compared against every crawl
+CREATE TABLE lifecycle_expectations (
+    code TEXT PRIMARY KEY,
+    expected_status TEXT,
+    expected_eol_date DATE,
+    notes TEXT,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS lifecycle_expectations;
+-- +goose StatementEnd
@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Product family and datasheet link read from the product page on each crawl
+ALTER TABLE products ADD COLUMN family TEXT;
+ALTER TABLE products ADD COLUMN datasheet_url TEXT;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE products DROP COLUMN IF EXISTS datasheet_url;
+ALTER TABLE products DROP COLUMN IF EXISTS family;
+-- +goose StatementEnd
@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Lifecycle status changes found by each run, for the run history
+ALTER TABLE collection_runs ADD COLUMN status_changes INTEGER NOT NULL DEFAULT 0;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE collection_runs DROP COLUMN IF EXISTS status_changes;
+-- +goose StatementEnd
@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Removed products are archived instead of deleted, keeping their snapshot history for audits
+ALTER TABLE products ADD COLUMN deleted_at TIMESTAMP;
+CREATE INDEX idx_products_deleted_at ON products(deleted_at) WHERE deleted_at IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_products_deleted_at;
+ALTER TABLE products DROP COLUMN IF EXISTS deleted_at;
+-- +goose StatementEnd
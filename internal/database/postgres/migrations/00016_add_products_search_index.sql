@@ -0,0 +1,14 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Full-text search (?fuzzy=true) over code, description, manufacturer and SAP codes. The 'simple'
+-- configuration skips stemming so part numbers are indexed as written. The expression must match
+-- the one used by the Ranked search queries for the planner to pick the index.
+CREATE INDEX idx_products_search ON products USING GIN (
+    to_tsvector('simple', code || ' ' || COALESCE(description, '') || ' ' || COALESCE(manufacturer_code, '') || ' ' || COALESCE(sap_code, ''))
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_products_search;
+-- +goose StatementEnd
@@ -12,7 +12,7 @@ import (
 )
 
 const countProductsByArea = `-- name: CountProductsByArea :one
-SELECT COUNT(*) FROM products WHERE area_id = $1
+SELECT COUNT(*) FROM products WHERE area_id = $1 AND deleted_at IS NULL
 `
 
 func (q *Queries) CountProductsByArea(ctx context.Context, areaID pgtype.UUID) (int64, error) {
@@ -115,6 +115,69 @@ func (q *Queries) ListAreas(ctx context.Context) ([]Area, error) {
 	return items, nil
 }
 
+const listDuplicateProductsBetweenAreas = `-- name: ListDuplicateProductsBetweenAreas :many
+SELECT s.id, s.code
+FROM products s
+WHERE s.area_id = $1
+  AND s.deleted_at IS NULL
+  AND EXISTS (
+    SELECT 1 FROM products t
+    WHERE t.area_id = $2 AND t.code = s.code AND t.deleted_at IS NULL
+  )
+ORDER BY s.code
+`
+
+type ListDuplicateProductsBetweenAreasParams struct {
+	SourceAreaID pgtype.UUID `json:"source_area_id"`
+	TargetAreaID pgtype.UUID `json:"target_area_id"`
+}
+
+type ListDuplicateProductsBetweenAreasRow struct {
+	ID   pgtype.UUID `json:"id"`
+	Code string      `json:"code"`
+}
+
+// Produtos da area de origem cujo codigo ja existe na area de destino
+func (q *Queries) ListDuplicateProductsBetweenAreas(ctx context.Context, arg ListDuplicateProductsBetweenAreasParams) ([]ListDuplicateProductsBetweenAreasRow, error) {
+	rows, err := q.db.Query(ctx, listDuplicateProductsBetweenAreas, arg.SourceAreaID, arg.TargetAreaID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListDuplicateProductsBetweenAreasRow
+	for rows.Next() {
+		var i ListDuplicateProductsBetweenAreasRow
+		if err := rows.Scan(&i.ID, &i.Code); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const moveAreaProducts = `-- name: MoveAreaProducts :execrows
+UPDATE products
+SET area_id = $1,
+    updated_at = NOW()
+WHERE area_id = $2
+`
+
+type MoveAreaProductsParams struct {
+	TargetAreaID pgtype.UUID `json:"target_area_id"`
+	SourceAreaID pgtype.UUID `json:"source_area_id"`
+}
+
+func (q *Queries) MoveAreaProducts(ctx context.Context, arg MoveAreaProductsParams) (int64, error) {
+	result, err := q.db.Exec(ctx, moveAreaProducts, arg.TargetAreaID, arg.SourceAreaID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const updateArea = `-- name: UpdateArea :one
 UPDATE areas
 SET
@@ -15,6 +15,59 @@ type Area struct {
 	CreatedAt   pgtype.Timestamp `json:"created_at"`
 }
 
+type CollectionRun struct {
+	ID               pgtype.UUID      `json:"id"`
+	StartedAt        pgtype.Timestamp `json:"started_at"`
+	FinishedAt       pgtype.Timestamp `json:"finished_at"`
+	Total            int32            `json:"total"`
+	Succeeded        int32            `json:"succeeded"`
+	Failed           int32            `json:"failed"`
+	UncollectedCodes []string         `json:"uncollected_codes"`
+	TimedOut         bool             `json:"timed_out"`
+	StatusChanges    int32            `json:"status_changes"`
+}
+
+type CrawlFailure struct {
+	Code                string           `json:"code"`
+	ConsecutiveFailures int32            `json:"consecutive_failures"`
+	LastError           pgtype.Text      `json:"last_error"`
+	LastFailedAt        pgtype.Timestamp `json:"last_failed_at"`
+}
+
+type LifecycleAlertSnooze struct {
+	Code          string           `json:"code"`
+	SnoozedUntil  pgtype.Timestamp `json:"snoozed_until"`
+	SnoozedStatus pgtype.Text      `json:"snoozed_status"`
+	CreatedBy     pgtype.UUID      `json:"created_by"`
+	CreatedAt     pgtype.Timestamp `json:"created_at"`
+}
+
+type LifecycleDigestChange struct {
+	ID         int64            `json:"id"`
+	Code       string           `json:"code"`
+	OldStatus  string           `json:"old_status"`
+	NewStatus  string           `json:"new_status"`
+	DetectedAt pgtype.Timestamp `json:"detected_at"`
+}
+
+type LifecycleExpectation struct {
+	Code            string           `json:"code"`
+	ExpectedStatus  pgtype.Text      `json:"expected_status"`
+	ExpectedEolDate pgtype.Date      `json:"expected_eol_date"`
+	Notes           pgtype.Text      `json:"notes"`
+	CreatedBy       pgtype.UUID      `json:"created_by"`
+	CreatedAt       pgtype.Timestamp `json:"created_at"`
+}
+
+type LifecycleStatusOverride struct {
+	Code           string           `json:"code"`
+	Status         string           `json:"status"`
+	PreviousStatus pgtype.Text      `json:"previous_status"`
+	Reason         pgtype.Text      `json:"reason"`
+	CreatedBy      pgtype.UUID      `json:"created_by"`
+	CreatedAt      pgtype.Timestamp `json:"created_at"`
+}
+
 type Product struct {
 	ID               pgtype.UUID      `json:"id"`
 	Code             string           `json:"code"`
@@ -31,6 +84,10 @@ type Product struct {
 	InventoryStatus  pgtype.Text      `json:"inventory_status"`
 	LifecycleStatus  pgtype.Text      `json:"lifecycle_status"`
 	CreatedAt        pgtype.Timestamp `json:"created_at"`
+	UpdatedAt        pgtype.Timestamp `json:"updated_at"`
+	Family           pgtype.Text      `json:"family"`
+	DatasheetUrl     pgtype.Text      `json:"datasheet_url"`
+	DeletedAt        pgtype.Timestamp `json:"deleted_at"`
 }
 
 type ProductSnapshot struct {
@@ -40,6 +97,8 @@ type ProductSnapshot struct {
 	Status      pgtype.Text      `json:"status"`
 	RawHtml     pgtype.Text      `json:"raw_html"`
 	CollectedAt pgtype.Timestamp `json:"collected_at"`
+	Source      pgtype.Text      `json:"source"`
+	RunID       pgtype.UUID      `json:"run_id"`
 }
 
 type User struct {
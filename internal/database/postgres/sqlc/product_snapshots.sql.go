@@ -11,6 +11,22 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const clearSnapshotRawHTML = `-- name: ClearSnapshotRawHTML :execrows
+UPDATE product_snapshots
+SET raw_html = NULL
+WHERE collected_at < $1::timestamp
+AND raw_html IS NOT NULL
+`
+
+// Descarta o HTML dos snapshots antigos; status e descricao continuam no historico
+func (q *Queries) ClearSnapshotRawHTML(ctx context.Context, collectedBefore pgtype.Timestamp) (int64, error) {
+	result, err := q.db.Exec(ctx, clearSnapshotRawHTML, collectedBefore)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const countProductSnapshots = `-- name: CountProductSnapshots :one
 SELECT COUNT(*) FROM product_snapshots WHERE product_id = $1
 `
@@ -23,9 +39,9 @@ func (q *Queries) CountProductSnapshots(ctx context.Context, productID pgtype.UU
 }
 
 const createSnapshot = `-- name: CreateSnapshot :one
-INSERT INTO product_snapshots (product_id, description, status, raw_html)
-VALUES ($1, $2, $3, $4)
-RETURNING id, product_id, description, status, raw_html, collected_at
+INSERT INTO product_snapshots (product_id, description, status, raw_html, source, run_id)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING id, product_id, description, status, raw_html, collected_at, source, run_id
 `
 
 type CreateSnapshotParams struct {
@@ -33,6 +49,8 @@ type CreateSnapshotParams struct {
 	Description string      `json:"description"`
 	Status      pgtype.Text `json:"status"`
 	RawHtml     pgtype.Text `json:"raw_html"`
+	Source      pgtype.Text `json:"source"`
+	RunID       pgtype.UUID `json:"run_id"`
 }
 
 func (q *Queries) CreateSnapshot(ctx context.Context, arg CreateSnapshotParams) (ProductSnapshot, error) {
@@ -41,6 +59,8 @@ func (q *Queries) CreateSnapshot(ctx context.Context, arg CreateSnapshotParams)
 		arg.Description,
 		arg.Status,
 		arg.RawHtml,
+		arg.Source,
+		arg.RunID,
 	)
 	var i ProductSnapshot
 	err := row.Scan(
@@ -50,6 +70,8 @@ func (q *Queries) CreateSnapshot(ctx context.Context, arg CreateSnapshotParams)
 		&i.Status,
 		&i.RawHtml,
 		&i.CollectedAt,
+		&i.Source,
+		&i.RunID,
 	)
 	return i, err
 }
@@ -70,8 +92,34 @@ func (q *Queries) DeleteOldSnapshots(ctx context.Context, arg DeleteOldSnapshots
 	return err
 }
 
+const deleteRedundantSnapshots = `-- name: DeleteRedundantSnapshots :execrows
+DELETE FROM product_snapshots
+WHERE id IN (
+    SELECT ranked.id FROM (
+        SELECT id,
+               ROW_NUMBER() OVER (PARTITION BY product_id ORDER BY collected_at DESC) AS position,
+               status IS NOT DISTINCT FROM LAG(status) OVER previous
+                   AND description = LAG(description) OVER previous AS unchanged
+        FROM product_snapshots
+        WINDOW previous AS (PARTITION BY product_id ORDER BY collected_at)
+    ) ranked
+    WHERE ranked.position > $1::int
+    AND ranked.unchanged
+)
+`
+
+// Mantem os keep_per_product snapshots mais recentes de cada produto. Dos mais antigos so apaga os
+// que repetem status e descricao da coleta anterior, assim as transicoes continuam no historico.
+func (q *Queries) DeleteRedundantSnapshots(ctx context.Context, keepPerProduct int32) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteRedundantSnapshots, keepPerProduct)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const findSnapshotByID = `-- name: FindSnapshotByID :one
-SELECT id, product_id, description, status, raw_html, collected_at FROM product_snapshots WHERE id = $1
+SELECT id, product_id, description, status, raw_html, collected_at, source, run_id FROM product_snapshots WHERE id = $1
 `
 
 func (q *Queries) FindSnapshotByID(ctx context.Context, id pgtype.UUID) (ProductSnapshot, error) {
@@ -84,12 +132,14 @@ func (q *Queries) FindSnapshotByID(ctx context.Context, id pgtype.UUID) (Product
 		&i.Status,
 		&i.RawHtml,
 		&i.CollectedAt,
+		&i.Source,
+		&i.RunID,
 	)
 	return i, err
 }
 
 const getLatestSnapshot = `-- name: GetLatestSnapshot :one
-SELECT id, product_id, description, status, raw_html, collected_at FROM product_snapshots
+SELECT id, product_id, description, status, raw_html, collected_at, source, run_id FROM product_snapshots
 WHERE product_id = $1
 ORDER BY collected_at DESC
 LIMIT 1
@@ -105,6 +155,32 @@ func (q *Queries) GetLatestSnapshot(ctx context.Context, productID pgtype.UUID)
 		&i.Status,
 		&i.RawHtml,
 		&i.CollectedAt,
+		&i.Source,
+		&i.RunID,
+	)
+	return i, err
+}
+
+const getLatestSnapshotByCode = `-- name: GetLatestSnapshotByCode :one
+SELECT s.id, s.product_id, s.description, s.status, s.raw_html, s.collected_at, s.source, s.run_id FROM product_snapshots s
+JOIN products p ON p.id = s.product_id
+WHERE p.code = $1
+ORDER BY s.collected_at DESC
+LIMIT 1
+`
+
+func (q *Queries) GetLatestSnapshotByCode(ctx context.Context, code string) (ProductSnapshot, error) {
+	row := q.db.QueryRow(ctx, getLatestSnapshotByCode, code)
+	var i ProductSnapshot
+	err := row.Scan(
+		&i.ID,
+		&i.ProductID,
+		&i.Description,
+		&i.Status,
+		&i.RawHtml,
+		&i.CollectedAt,
+		&i.Source,
+		&i.RunID,
 	)
 	return i, err
 }
@@ -142,8 +218,173 @@ func (q *Queries) GetSnapshotStatusHistory(ctx context.Context, productID pgtype
 	return items, nil
 }
 
+const hasSnapshotForCode = `-- name: HasSnapshotForCode :one
+SELECT EXISTS (
+    SELECT 1 FROM product_snapshots s
+    JOIN products p ON p.id = s.product_id
+    WHERE p.code = $1
+) AS has_snapshot
+`
+
+// Se o codigo ja foi coletado em alguma area (snapshots sao por produto, o codigo se repete entre areas)
+func (q *Queries) HasSnapshotForCode(ctx context.Context, code string) (bool, error) {
+	row := q.db.QueryRow(ctx, hasSnapshotForCode, code)
+	var has_snapshot bool
+	err := row.Scan(&has_snapshot)
+	return has_snapshot, err
+}
+
+const listLatestSnapshotsByCodes = `-- name: ListLatestSnapshotsByCodes :many
+SELECT DISTINCT ON (p.code) p.code, s.id, s.product_id, s.description, s.status, s.collected_at, s.source, s.run_id
+FROM product_snapshots s
+JOIN products p ON p.id = s.product_id
+WHERE p.code = ANY($1::text[])
+AND p.deleted_at IS NULL
+ORDER BY p.code, s.collected_at DESC
+`
+
+type ListLatestSnapshotsByCodesRow struct {
+	Code        string           `json:"code"`
+	ID          pgtype.UUID      `json:"id"`
+	ProductID   pgtype.UUID      `json:"product_id"`
+	Description string           `json:"description"`
+	Status      pgtype.Text      `json:"status"`
+	CollectedAt pgtype.Timestamp `json:"collected_at"`
+	Source      pgtype.Text      `json:"source"`
+	RunID       pgtype.UUID      `json:"run_id"`
+}
+
+// Ultimo snapshot de cada codigo entre todas as areas (o status e do codigo); sem o raw_html
+func (q *Queries) ListLatestSnapshotsByCodes(ctx context.Context, codes []string) ([]ListLatestSnapshotsByCodesRow, error) {
+	rows, err := q.db.Query(ctx, listLatestSnapshotsByCodes, codes)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListLatestSnapshotsByCodesRow
+	for rows.Next() {
+		var i ListLatestSnapshotsByCodesRow
+		if err := rows.Scan(
+			&i.Code,
+			&i.ID,
+			&i.ProductID,
+			&i.Description,
+			&i.Status,
+			&i.CollectedAt,
+			&i.Source,
+			&i.RunID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listLatestSnapshotsByProductIDs = `-- name: ListLatestSnapshotsByProductIDs :many
+SELECT DISTINCT ON (product_id) id, product_id, description, status, raw_html, collected_at, source, run_id
+FROM product_snapshots
+WHERE product_id = ANY($1::uuid[])
+ORDER BY product_id, collected_at DESC
+`
+
+func (q *Queries) ListLatestSnapshotsByProductIDs(ctx context.Context, productIds []pgtype.UUID) ([]ProductSnapshot, error) {
+	rows, err := q.db.Query(ctx, listLatestSnapshotsByProductIDs, productIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ProductSnapshot
+	for rows.Next() {
+		var i ProductSnapshot
+		if err := rows.Scan(
+			&i.ID,
+			&i.ProductID,
+			&i.Description,
+			&i.Status,
+			&i.RawHtml,
+			&i.CollectedAt,
+			&i.Source,
+			&i.RunID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listLifecycleTransitions = `-- name: ListLifecycleTransitions :many
+SELECT t.code, t.description, a.name AS area_name, t.old_status::text AS old_status, t.new_status, t.changed_at, t.replacement_url
+FROM (
+    SELECT p.code, p.description, p.area_id, p.replacement_url,
+           s.status AS new_status,
+           s.collected_at AS changed_at,
+           LAG(s.status) OVER (PARTITION BY s.product_id ORDER BY s.collected_at) AS old_status
+    FROM product_snapshots s
+    JOIN products p ON p.id = s.product_id
+    WHERE s.status IS NOT NULL
+    AND s.collected_at < $1::timestamp
+) t
+LEFT JOIN areas a ON a.id = t.area_id
+WHERE t.old_status IS NOT NULL
+AND t.old_status <> t.new_status
+AND t.changed_at >= $2::timestamp
+ORDER BY t.changed_at ASC, t.code ASC
+`
+
+type ListLifecycleTransitionsParams struct {
+	ToDate   pgtype.Timestamp `json:"to_date"`
+	FromDate pgtype.Timestamp `json:"from_date"`
+}
+
+type ListLifecycleTransitionsRow struct {
+	Code           string           `json:"code"`
+	Description    pgtype.Text      `json:"description"`
+	AreaName       pgtype.Text      `json:"area_name"`
+	OldStatus      string           `json:"old_status"`
+	NewStatus      pgtype.Text      `json:"new_status"`
+	ChangedAt      pgtype.Timestamp `json:"changed_at"`
+	ReplacementUrl pgtype.Text      `json:"replacement_url"`
+}
+
+// Transicoes de status derivadas dos snapshots: cada snapshot cujo status difere do anterior do mesmo produto
+func (q *Queries) ListLifecycleTransitions(ctx context.Context, arg ListLifecycleTransitionsParams) ([]ListLifecycleTransitionsRow, error) {
+	rows, err := q.db.Query(ctx, listLifecycleTransitions, arg.ToDate, arg.FromDate)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListLifecycleTransitionsRow
+	for rows.Next() {
+		var i ListLifecycleTransitionsRow
+		if err := rows.Scan(
+			&i.Code,
+			&i.Description,
+			&i.AreaName,
+			&i.OldStatus,
+			&i.NewStatus,
+			&i.ChangedAt,
+			&i.ReplacementUrl,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listProductSnapshots = `-- name: ListProductSnapshots :many
-SELECT id, product_id, description, status, raw_html, collected_at FROM product_snapshots
+SELECT id, product_id, description, status, raw_html, collected_at, source, run_id FROM product_snapshots
 WHERE product_id = $1
 ORDER BY collected_at DESC
 `
@@ -164,6 +405,8 @@ func (q *Queries) ListProductSnapshots(ctx context.Context, productID pgtype.UUI
 			&i.Status,
 			&i.RawHtml,
 			&i.CollectedAt,
+			&i.Source,
+			&i.RunID,
 		); err != nil {
 			return nil, err
 		}
@@ -176,7 +419,7 @@ func (q *Queries) ListProductSnapshots(ctx context.Context, productID pgtype.UUI
 }
 
 const listSnapshotsByDateRange = `-- name: ListSnapshotsByDateRange :many
-SELECT id, product_id, description, status, raw_html, collected_at FROM product_snapshots
+SELECT id, product_id, description, status, raw_html, collected_at, source, run_id FROM product_snapshots
 WHERE product_id = $1
 AND collected_at BETWEEN $2 AND $3
 ORDER BY collected_at DESC
@@ -204,6 +447,8 @@ func (q *Queries) ListSnapshotsByDateRange(ctx context.Context, arg ListSnapshot
 			&i.Status,
 			&i.RawHtml,
 			&i.CollectedAt,
+			&i.Source,
+			&i.RunID,
 		); err != nil {
 			return nil, err
 		}
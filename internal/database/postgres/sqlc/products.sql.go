@@ -12,7 +12,7 @@ import (
 )
 
 const countProducts = `-- name: CountProducts :one
-SELECT COUNT(*) FROM products
+SELECT COUNT(*) FROM products WHERE deleted_at IS NULL
 `
 
 func (q *Queries) CountProducts(ctx context.Context) (int64, error) {
@@ -26,6 +26,7 @@ const countProductsByAreaAndSearch = `-- name: CountProductsByAreaAndSearch :one
 SELECT COUNT(*)
 FROM products p
 WHERE p.area_id = $1
+  AND p.deleted_at IS NULL
   AND (p.code ILIKE '%' || $2::text || '%'
    OR p.description ILIKE '%' || $2::text || '%'
    OR p.sap_code ILIKE '%' || $2::text || '%'
@@ -59,6 +60,7 @@ SELECT
 FROM (
     SELECT DISTINCT code, lifecycle_status
     FROM products
+    WHERE deleted_at IS NULL
 ) p
 `
 
@@ -78,11 +80,12 @@ func (q *Queries) CountProductsByLifecycleStatus(ctx context.Context) (CountProd
 const countProductsBySearch = `-- name: CountProductsBySearch :one
 SELECT COUNT(*)
 FROM products p
-WHERE p.code ILIKE '%' || $1::text || '%'
+WHERE p.deleted_at IS NULL
+  AND (p.code ILIKE '%' || $1::text || '%'
    OR p.description ILIKE '%' || $1::text || '%'
    OR p.sap_code ILIKE '%' || $1::text || '%'
    OR p.manufacturer_code ILIKE '%' || $1::text || '%'
-   OR p.lifecycle_status ILIKE '%' || $1::text || '%'
+   OR p.lifecycle_status ILIKE '%' || $1::text || '%')
 `
 
 func (q *Queries) CountProductsBySearch(ctx context.Context, search string) (int64, error) {
@@ -93,7 +96,7 @@ func (q *Queries) CountProductsBySearch(ctx context.Context, search string) (int
 }
 
 const countProductsInArea = `-- name: CountProductsInArea :one
-SELECT COUNT(*) FROM products WHERE area_id = $1
+SELECT COUNT(*) FROM products WHERE area_id = $1 AND deleted_at IS NULL
 `
 
 func (q *Queries) CountProductsInArea(ctx context.Context, areaID pgtype.UUID) (int64, error) {
@@ -104,7 +107,7 @@ func (q *Queries) CountProductsInArea(ctx context.Context, areaID pgtype.UUID) (
 }
 
 const countUniqueProducts = `-- name: CountUniqueProducts :one
-SELECT COUNT(DISTINCT code) FROM products
+SELECT COUNT(DISTINCT code) FROM products WHERE deleted_at IS NULL
 `
 
 func (q *Queries) CountUniqueProducts(ctx context.Context) (int64, error) {
@@ -114,10 +117,31 @@ func (q *Queries) CountUniqueProducts(ctx context.Context) (int64, error) {
 	return count, err
 }
 
+const countUniqueProductsByAreaAndFullText = `-- name: CountUniqueProductsByAreaAndFullText :one
+SELECT COUNT(DISTINCT p.code)
+FROM products p
+WHERE p.area_id = $1
+  AND p.deleted_at IS NULL
+  AND to_tsvector('simple', p.code || ' ' || COALESCE(p.description, '') || ' ' || COALESCE(p.manufacturer_code, '') || ' ' || COALESCE(p.sap_code, '')) @@ plainto_tsquery('simple', $2::text)
+`
+
+type CountUniqueProductsByAreaAndFullTextParams struct {
+	AreaID pgtype.UUID `json:"area_id"`
+	Search string      `json:"search"`
+}
+
+func (q *Queries) CountUniqueProductsByAreaAndFullText(ctx context.Context, arg CountUniqueProductsByAreaAndFullTextParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countUniqueProductsByAreaAndFullText, arg.AreaID, arg.Search)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const countUniqueProductsByAreaAndSearch = `-- name: CountUniqueProductsByAreaAndSearch :one
 SELECT COUNT(DISTINCT p.code)
 FROM products p
 WHERE p.area_id = $1
+  AND p.deleted_at IS NULL
   AND (p.code ILIKE '%' || $2::text || '%'
    OR p.description ILIKE '%' || $2::text || '%'
    OR p.sap_code ILIKE '%' || $2::text || '%'
@@ -137,14 +161,29 @@ func (q *Queries) CountUniqueProductsByAreaAndSearch(ctx context.Context, arg Co
 	return count, err
 }
 
+const countUniqueProductsByFullText = `-- name: CountUniqueProductsByFullText :one
+SELECT COUNT(DISTINCT p.code)
+FROM products p
+WHERE p.deleted_at IS NULL
+  AND to_tsvector('simple', p.code || ' ' || COALESCE(p.description, '') || ' ' || COALESCE(p.manufacturer_code, '') || ' ' || COALESCE(p.sap_code, '')) @@ plainto_tsquery('simple', $1::text)
+`
+
+func (q *Queries) CountUniqueProductsByFullText(ctx context.Context, search string) (int64, error) {
+	row := q.db.QueryRow(ctx, countUniqueProductsByFullText, search)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const countUniqueProductsBySearch = `-- name: CountUniqueProductsBySearch :one
 SELECT COUNT(DISTINCT code)
 FROM products p
-WHERE p.code ILIKE '%' || $1::text || '%'
+WHERE p.deleted_at IS NULL
+  AND (p.code ILIKE '%' || $1::text || '%'
    OR p.description ILIKE '%' || $1::text || '%'
    OR p.sap_code ILIKE '%' || $1::text || '%'
    OR p.manufacturer_code ILIKE '%' || $1::text || '%'
-   OR p.lifecycle_status ILIKE '%' || $1::text || '%'
+   OR p.lifecycle_status ILIKE '%' || $1::text || '%')
 `
 
 func (q *Queries) CountUniqueProductsBySearch(ctx context.Context, search string) (int64, error) {
@@ -154,8 +193,35 @@ func (q *Queries) CountUniqueProductsBySearch(ctx context.Context, search string
 	return count, err
 }
 
+const countUniqueProductsByStatus = `-- name: CountUniqueProductsByStatus :one
+SELECT COUNT(DISTINCT p.code)
+FROM products p
+WHERE p.deleted_at IS NULL
+  AND p.lifecycle_status = ANY($1::text[])
+  AND ($2::uuid IS NULL OR p.area_id = $2::uuid)
+  AND ($3::text IS NULL
+   OR p.code ILIKE '%' || $3::text || '%'
+   OR p.description ILIKE '%' || $3::text || '%'
+   OR p.sap_code ILIKE '%' || $3::text || '%'
+   OR p.manufacturer_code ILIKE '%' || $3::text || '%'
+   OR p.lifecycle_status ILIKE '%' || $3::text || '%')
+`
+
+type CountUniqueProductsByStatusParams struct {
+	Statuses []string    `json:"statuses"`
+	AreaID   pgtype.UUID `json:"area_id"`
+	Search   pgtype.Text `json:"search"`
+}
+
+func (q *Queries) CountUniqueProductsByStatus(ctx context.Context, arg CountUniqueProductsByStatusParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countUniqueProductsByStatus, arg.Statuses, arg.AreaID, arg.Search)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const countUniqueProductsInArea = `-- name: CountUniqueProductsInArea :one
-SELECT COUNT(DISTINCT code) FROM products WHERE area_id = $1
+SELECT COUNT(DISTINCT code) FROM products WHERE area_id = $1 AND deleted_at IS NULL
 `
 
 func (q *Queries) CountUniqueProductsInArea(ctx context.Context, areaID pgtype.UUID) (int64, error) {
@@ -171,7 +237,7 @@ INSERT INTO products (
     quantity, sap_code, observations, min_quantity, max_quantity, inventory_status
 )
 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
-RETURNING id, code, url, area_id, description, manufacturer_code, quantity, replacement_url, sap_code, observations, min_quantity, max_quantity, inventory_status, lifecycle_status, created_at
+RETURNING id, code, url, area_id, description, manufacturer_code, quantity, replacement_url, sap_code, observations, min_quantity, max_quantity, inventory_status, lifecycle_status, created_at, updated_at, family, datasheet_url, deleted_at
 `
 
 type CreateProductParams struct {
@@ -219,12 +285,19 @@ func (q *Queries) CreateProduct(ctx context.Context, arg CreateProductParams) (P
 		&i.InventoryStatus,
 		&i.LifecycleStatus,
 		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Family,
+		&i.DatasheetUrl,
+		&i.DeletedAt,
 	)
 	return i, err
 }
 
 const deleteProduct = `-- name: DeleteProduct :exec
-DELETE FROM products WHERE id = $1
+UPDATE products
+SET deleted_at = NOW(),
+    updated_at = NOW()
+WHERE id = $1 AND deleted_at IS NULL
 `
 
 func (q *Queries) DeleteProduct(ctx context.Context, id pgtype.UUID) error {
@@ -232,11 +305,54 @@ func (q *Queries) DeleteProduct(ctx context.Context, id pgtype.UUID) error {
 	return err
 }
 
+const deleteProductsByIDs = `-- name: DeleteProductsByIDs :execrows
+UPDATE products
+SET deleted_at = NOW(),
+    updated_at = NOW()
+WHERE id = ANY($1::uuid[]) AND deleted_at IS NULL
+`
+
+func (q *Queries) DeleteProductsByIDs(ctx context.Context, ids []pgtype.UUID) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteProductsByIDs, ids)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const deleteProductsReturningIDs = `-- name: DeleteProductsReturningIDs :many
+UPDATE products
+SET deleted_at = NOW(),
+    updated_at = NOW()
+WHERE id = ANY($1::uuid[]) AND deleted_at IS NULL
+RETURNING id
+`
+
+func (q *Queries) DeleteProductsReturningIDs(ctx context.Context, ids []pgtype.UUID) ([]pgtype.UUID, error) {
+	rows, err := q.db.Query(ctx, deleteProductsReturningIDs, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []pgtype.UUID
+	for rows.Next() {
+		var id pgtype.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const findProductByCode = `-- name: FindProductByCode :one
-SELECT p.id, p.code, p.url, p.area_id, p.description, p.manufacturer_code, p.quantity, p.replacement_url, p.sap_code, p.observations, p.min_quantity, p.max_quantity, p.inventory_status, p.lifecycle_status, p.created_at, a.name as area_name
+SELECT p.id, p.code, p.url, p.area_id, p.description, p.manufacturer_code, p.quantity, p.replacement_url, p.sap_code, p.observations, p.min_quantity, p.max_quantity, p.inventory_status, p.lifecycle_status, p.created_at, p.updated_at, p.family, p.datasheet_url, p.deleted_at, a.name as area_name
 FROM products p
 LEFT JOIN areas a ON p.area_id = a.id
-WHERE p.code = $1
+WHERE p.code = $1 AND p.deleted_at IS NULL
 `
 
 type FindProductByCodeRow struct {
@@ -255,6 +371,10 @@ type FindProductByCodeRow struct {
 	InventoryStatus  pgtype.Text      `json:"inventory_status"`
 	LifecycleStatus  pgtype.Text      `json:"lifecycle_status"`
 	CreatedAt        pgtype.Timestamp `json:"created_at"`
+	UpdatedAt        pgtype.Timestamp `json:"updated_at"`
+	Family           pgtype.Text      `json:"family"`
+	DatasheetUrl     pgtype.Text      `json:"datasheet_url"`
+	DeletedAt        pgtype.Timestamp `json:"deleted_at"`
 	AreaName         pgtype.Text      `json:"area_name"`
 }
 
@@ -277,16 +397,21 @@ func (q *Queries) FindProductByCode(ctx context.Context, code string) (FindProdu
 		&i.InventoryStatus,
 		&i.LifecycleStatus,
 		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Family,
+		&i.DatasheetUrl,
+		&i.DeletedAt,
 		&i.AreaName,
 	)
 	return i, err
 }
 
 const findProductByCodeAndArea = `-- name: FindProductByCodeAndArea :one
-SELECT p.id, p.code, p.url, p.area_id, p.description, p.manufacturer_code, p.quantity, p.replacement_url, p.sap_code, p.observations, p.min_quantity, p.max_quantity, p.inventory_status, p.lifecycle_status, p.created_at, a.name as area_name
+SELECT p.id, p.code, p.url, p.area_id, p.description, p.manufacturer_code, p.quantity, p.replacement_url, p.sap_code, p.observations, p.min_quantity, p.max_quantity, p.inventory_status, p.lifecycle_status, p.created_at, p.updated_at, p.family, p.datasheet_url, p.deleted_at, a.name as area_name
 FROM products p
 LEFT JOIN areas a ON p.area_id = a.id
 WHERE p.code = $1 AND (p.area_id = $2 OR ($2 IS NULL AND p.area_id IS NULL))
+  AND p.deleted_at IS NULL
 `
 
 type FindProductByCodeAndAreaParams struct {
@@ -310,6 +435,10 @@ type FindProductByCodeAndAreaRow struct {
 	InventoryStatus  pgtype.Text      `json:"inventory_status"`
 	LifecycleStatus  pgtype.Text      `json:"lifecycle_status"`
 	CreatedAt        pgtype.Timestamp `json:"created_at"`
+	UpdatedAt        pgtype.Timestamp `json:"updated_at"`
+	Family           pgtype.Text      `json:"family"`
+	DatasheetUrl     pgtype.Text      `json:"datasheet_url"`
+	DeletedAt        pgtype.Timestamp `json:"deleted_at"`
 	AreaName         pgtype.Text      `json:"area_name"`
 }
 
@@ -332,16 +461,20 @@ func (q *Queries) FindProductByCodeAndArea(ctx context.Context, arg FindProductB
 		&i.InventoryStatus,
 		&i.LifecycleStatus,
 		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Family,
+		&i.DatasheetUrl,
+		&i.DeletedAt,
 		&i.AreaName,
 	)
 	return i, err
 }
 
 const findProductByID = `-- name: FindProductByID :one
-SELECT p.id, p.code, p.url, p.area_id, p.description, p.manufacturer_code, p.quantity, p.replacement_url, p.sap_code, p.observations, p.min_quantity, p.max_quantity, p.inventory_status, p.lifecycle_status, p.created_at, a.name as area_name
+SELECT p.id, p.code, p.url, p.area_id, p.description, p.manufacturer_code, p.quantity, p.replacement_url, p.sap_code, p.observations, p.min_quantity, p.max_quantity, p.inventory_status, p.lifecycle_status, p.created_at, p.updated_at, p.family, p.datasheet_url, p.deleted_at, a.name as area_name
 FROM products p
 LEFT JOIN areas a ON p.area_id = a.id
-WHERE p.id = $1
+WHERE p.id = $1 AND p.deleted_at IS NULL
 `
 
 type FindProductByIDRow struct {
@@ -360,6 +493,10 @@ type FindProductByIDRow struct {
 	InventoryStatus  pgtype.Text      `json:"inventory_status"`
 	LifecycleStatus  pgtype.Text      `json:"lifecycle_status"`
 	CreatedAt        pgtype.Timestamp `json:"created_at"`
+	UpdatedAt        pgtype.Timestamp `json:"updated_at"`
+	Family           pgtype.Text      `json:"family"`
+	DatasheetUrl     pgtype.Text      `json:"datasheet_url"`
+	DeletedAt        pgtype.Timestamp `json:"deleted_at"`
 	AreaName         pgtype.Text      `json:"area_name"`
 }
 
@@ -382,14 +519,228 @@ func (q *Queries) FindProductByID(ctx context.Context, id pgtype.UUID) (FindProd
 		&i.InventoryStatus,
 		&i.LifecycleStatus,
 		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Family,
+		&i.DatasheetUrl,
+		&i.DeletedAt,
 		&i.AreaName,
 	)
 	return i, err
 }
 
+const findProductsByCodes = `-- name: FindProductsByCodes :many
+SELECT p.id, p.code, p.url, p.area_id, p.description, p.manufacturer_code, p.quantity, p.replacement_url, p.sap_code, p.observations, p.min_quantity, p.max_quantity, p.inventory_status, p.lifecycle_status, p.created_at, p.updated_at, p.family, p.datasheet_url, p.deleted_at, a.name as area_name
+FROM products p
+LEFT JOIN areas a ON p.area_id = a.id
+WHERE p.code = ANY($1::text[])
+  AND p.deleted_at IS NULL
+ORDER BY p.code, p.created_at ASC
+`
+
+type FindProductsByCodesRow struct {
+	ID               pgtype.UUID      `json:"id"`
+	Code             string           `json:"code"`
+	Url              string           `json:"url"`
+	AreaID           pgtype.UUID      `json:"area_id"`
+	Description      pgtype.Text      `json:"description"`
+	ManufacturerCode pgtype.Text      `json:"manufacturer_code"`
+	Quantity         pgtype.Int4      `json:"quantity"`
+	ReplacementUrl   pgtype.Text      `json:"replacement_url"`
+	SapCode          pgtype.Text      `json:"sap_code"`
+	Observations     pgtype.Text      `json:"observations"`
+	MinQuantity      pgtype.Int4      `json:"min_quantity"`
+	MaxQuantity      pgtype.Int4      `json:"max_quantity"`
+	InventoryStatus  pgtype.Text      `json:"inventory_status"`
+	LifecycleStatus  pgtype.Text      `json:"lifecycle_status"`
+	CreatedAt        pgtype.Timestamp `json:"created_at"`
+	UpdatedAt        pgtype.Timestamp `json:"updated_at"`
+	Family           pgtype.Text      `json:"family"`
+	DatasheetUrl     pgtype.Text      `json:"datasheet_url"`
+	DeletedAt        pgtype.Timestamp `json:"deleted_at"`
+	AreaName         pgtype.Text      `json:"area_name"`
+}
+
+func (q *Queries) FindProductsByCodes(ctx context.Context, codes []string) ([]FindProductsByCodesRow, error) {
+	rows, err := q.db.Query(ctx, findProductsByCodes, codes)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []FindProductsByCodesRow
+	for rows.Next() {
+		var i FindProductsByCodesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Code,
+			&i.Url,
+			&i.AreaID,
+			&i.Description,
+			&i.ManufacturerCode,
+			&i.Quantity,
+			&i.ReplacementUrl,
+			&i.SapCode,
+			&i.Observations,
+			&i.MinQuantity,
+			&i.MaxQuantity,
+			&i.InventoryStatus,
+			&i.LifecycleStatus,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Family,
+			&i.DatasheetUrl,
+			&i.DeletedAt,
+			&i.AreaName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const findProductsByIDs = `-- name: FindProductsByIDs :many
+SELECT p.id, p.code, p.url, p.area_id, p.description, p.manufacturer_code, p.quantity, p.replacement_url, p.sap_code, p.observations, p.min_quantity, p.max_quantity, p.inventory_status, p.lifecycle_status, p.created_at, p.updated_at, p.family, p.datasheet_url, p.deleted_at, a.name as area_name
+FROM products p
+LEFT JOIN areas a ON p.area_id = a.id
+WHERE p.id = ANY($1::uuid[])
+  AND p.deleted_at IS NULL
+ORDER BY p.code, p.created_at ASC
+`
+
+type FindProductsByIDsRow struct {
+	ID               pgtype.UUID      `json:"id"`
+	Code             string           `json:"code"`
+	Url              string           `json:"url"`
+	AreaID           pgtype.UUID      `json:"area_id"`
+	Description      pgtype.Text      `json:"description"`
+	ManufacturerCode pgtype.Text      `json:"manufacturer_code"`
+	Quantity         pgtype.Int4      `json:"quantity"`
+	ReplacementUrl   pgtype.Text      `json:"replacement_url"`
+	SapCode          pgtype.Text      `json:"sap_code"`
+	Observations     pgtype.Text      `json:"observations"`
+	MinQuantity      pgtype.Int4      `json:"min_quantity"`
+	MaxQuantity      pgtype.Int4      `json:"max_quantity"`
+	InventoryStatus  pgtype.Text      `json:"inventory_status"`
+	LifecycleStatus  pgtype.Text      `json:"lifecycle_status"`
+	CreatedAt        pgtype.Timestamp `json:"created_at"`
+	UpdatedAt        pgtype.Timestamp `json:"updated_at"`
+	Family           pgtype.Text      `json:"family"`
+	DatasheetUrl     pgtype.Text      `json:"datasheet_url"`
+	DeletedAt        pgtype.Timestamp `json:"deleted_at"`
+	AreaName         pgtype.Text      `json:"area_name"`
+}
+
+func (q *Queries) FindProductsByIDs(ctx context.Context, ids []pgtype.UUID) ([]FindProductsByIDsRow, error) {
+	rows, err := q.db.Query(ctx, findProductsByIDs, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []FindProductsByIDsRow
+	for rows.Next() {
+		var i FindProductsByIDsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Code,
+			&i.Url,
+			&i.AreaID,
+			&i.Description,
+			&i.ManufacturerCode,
+			&i.Quantity,
+			&i.ReplacementUrl,
+			&i.SapCode,
+			&i.Observations,
+			&i.MinQuantity,
+			&i.MaxQuantity,
+			&i.InventoryStatus,
+			&i.LifecycleStatus,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Family,
+			&i.DatasheetUrl,
+			&i.DeletedAt,
+			&i.AreaName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getInventorySummary = `-- name: GetInventorySummary :one
+SELECT
+    COUNT(DISTINCT code) AS total_products,
+    COALESCE(SUM(COALESCE(quantity, 0)), 0)::BIGINT AS total_quantity,
+    COUNT(DISTINCT code) FILTER (WHERE lifecycle_status = 'Active Product') AS active_count,
+    COUNT(DISTINCT code) FILTER (
+        WHERE lifecycle_status IN (
+            'Prod. Cancellation',
+            'End Prod.Lifecycl.',
+            'Prod. Discont.'
+        )
+    ) AS discontinued_count,
+    COUNT(DISTINCT code) FILTER (WHERE lifecycle_status = 'Phase Out Announce') AS phase_out_count
+FROM products
+WHERE deleted_at IS NULL
+AND ($1::uuid IS NULL OR area_id = $1::uuid)
+`
+
+type GetInventorySummaryRow struct {
+	TotalProducts     int64 `json:"total_products"`
+	TotalQuantity     int64 `json:"total_quantity"`
+	ActiveCount       int64 `json:"active_count"`
+	DiscontinuedCount int64 `json:"discontinued_count"`
+	PhaseOutCount     int64 `json:"phase_out_count"`
+}
+
+// Totais do inventario por codigo; com area_id, apenas os produtos da area (mesmo filtro da listagem)
+func (q *Queries) GetInventorySummary(ctx context.Context, areaID pgtype.UUID) (GetInventorySummaryRow, error) {
+	row := q.db.QueryRow(ctx, getInventorySummary, areaID)
+	var i GetInventorySummaryRow
+	err := row.Scan(
+		&i.TotalProducts,
+		&i.TotalQuantity,
+		&i.ActiveCount,
+		&i.DiscontinuedCount,
+		&i.PhaseOutCount,
+	)
+	return i, err
+}
+
+const getProductsExportVersion = `-- name: GetProductsExportVersion :one
+SELECT
+    COALESCE(MAX(p.updated_at), 'epoch'::timestamp)::timestamp AS last_updated,
+    COUNT(*) AS product_count,
+    COALESCE((SELECT md5(string_agg(a.id::text || ':' || a.name, ',' ORDER BY a.id)) FROM areas a), '')::text AS areas_hash
+FROM products p
+WHERE p.deleted_at IS NULL
+`
+
+type GetProductsExportVersionRow struct {
+	LastUpdated  pgtype.Timestamp `json:"last_updated"`
+	ProductCount int64            `json:"product_count"`
+	AreasHash    string           `json:"areas_hash"`
+}
+
+// Versao da exportacao: muda quando algum produto e alterado, criado ou removido, ou uma area e renomeada
+func (q *Queries) GetProductsExportVersion(ctx context.Context) (GetProductsExportVersionRow, error) {
+	row := q.db.QueryRow(ctx, getProductsExportVersion)
+	var i GetProductsExportVersionRow
+	err := row.Scan(&i.LastUpdated, &i.ProductCount, &i.AreasHash)
+	return i, err
+}
+
 const listAllProductsToCollect = `-- name: ListAllProductsToCollect :many
 SELECT p.id, p.code, p.url, p.created_at
 FROM products p
+WHERE p.deleted_at IS NULL
 ORDER BY p.created_at ASC
 `
 
@@ -425,11 +776,131 @@ func (q *Queries) ListAllProductsToCollect(ctx context.Context) ([]ListAllProduc
 	return items, nil
 }
 
+const listArchivedProducts = `-- name: ListArchivedProducts :many
+SELECT p.id, p.code, p.url, p.area_id, p.description, p.manufacturer_code, p.quantity, p.replacement_url, p.sap_code, p.observations, p.min_quantity, p.max_quantity, p.inventory_status, p.lifecycle_status, p.created_at, p.updated_at, p.family, p.datasheet_url, p.deleted_at, a.name as area_name
+FROM products p
+LEFT JOIN areas a ON p.area_id = a.id
+WHERE p.deleted_at IS NOT NULL
+ORDER BY p.deleted_at DESC
+`
+
+type ListArchivedProductsRow struct {
+	ID               pgtype.UUID      `json:"id"`
+	Code             string           `json:"code"`
+	Url              string           `json:"url"`
+	AreaID           pgtype.UUID      `json:"area_id"`
+	Description      pgtype.Text      `json:"description"`
+	ManufacturerCode pgtype.Text      `json:"manufacturer_code"`
+	Quantity         pgtype.Int4      `json:"quantity"`
+	ReplacementUrl   pgtype.Text      `json:"replacement_url"`
+	SapCode          pgtype.Text      `json:"sap_code"`
+	Observations     pgtype.Text      `json:"observations"`
+	MinQuantity      pgtype.Int4      `json:"min_quantity"`
+	MaxQuantity      pgtype.Int4      `json:"max_quantity"`
+	InventoryStatus  pgtype.Text      `json:"inventory_status"`
+	LifecycleStatus  pgtype.Text      `json:"lifecycle_status"`
+	CreatedAt        pgtype.Timestamp `json:"created_at"`
+	UpdatedAt        pgtype.Timestamp `json:"updated_at"`
+	Family           pgtype.Text      `json:"family"`
+	DatasheetUrl     pgtype.Text      `json:"datasheet_url"`
+	DeletedAt        pgtype.Timestamp `json:"deleted_at"`
+	AreaName         pgtype.Text      `json:"area_name"`
+}
+
+// Produtos removidos (arquivados), mantidos com o historico de snapshots para auditoria
+func (q *Queries) ListArchivedProducts(ctx context.Context) ([]ListArchivedProductsRow, error) {
+	rows, err := q.db.Query(ctx, listArchivedProducts)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListArchivedProductsRow
+	for rows.Next() {
+		var i ListArchivedProductsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Code,
+			&i.Url,
+			&i.AreaID,
+			&i.Description,
+			&i.ManufacturerCode,
+			&i.Quantity,
+			&i.ReplacementUrl,
+			&i.SapCode,
+			&i.Observations,
+			&i.MinQuantity,
+			&i.MaxQuantity,
+			&i.InventoryStatus,
+			&i.LifecycleStatus,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Family,
+			&i.DatasheetUrl,
+			&i.DeletedAt,
+			&i.AreaName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listInventoryByArea = `-- name: ListInventoryByArea :many
+SELECT
+    a.id AS area_id,
+    a.name AS area_name,
+    COUNT(DISTINCT p.code) AS total_products,
+    COALESCE(SUM(COALESCE(p.quantity, 0)), 0)::BIGINT AS total_quantity
+FROM areas a
+JOIN products p ON p.area_id = a.id AND p.deleted_at IS NULL
+WHERE $1::uuid IS NULL OR a.id = $1::uuid
+GROUP BY a.id, a.name
+ORDER BY a.name
+`
+
+type ListInventoryByAreaRow struct {
+	AreaID        pgtype.UUID `json:"area_id"`
+	AreaName      string      `json:"area_name"`
+	TotalProducts int64       `json:"total_products"`
+	TotalQuantity int64       `json:"total_quantity"`
+}
+
+// Totais por area; produtos sem area entram apenas no total geral
+func (q *Queries) ListInventoryByArea(ctx context.Context, areaID pgtype.UUID) ([]ListInventoryByAreaRow, error) {
+	rows, err := q.db.Query(ctx, listInventoryByArea, areaID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListInventoryByAreaRow
+	for rows.Next() {
+		var i ListInventoryByAreaRow
+		if err := rows.Scan(
+			&i.AreaID,
+			&i.AreaName,
+			&i.TotalProducts,
+			&i.TotalQuantity,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listProducts = `-- name: ListProducts :many
-SELECT id, code, url, area_id, description, manufacturer_code, quantity, replacement_url, sap_code, observations, min_quantity, max_quantity, inventory_status, lifecycle_status, created_at, area_name FROM (
-    SELECT p.id, p.code, p.url, p.area_id, p.description, p.manufacturer_code, p.quantity, p.replacement_url, p.sap_code, p.observations, p.min_quantity, p.max_quantity, p.inventory_status, p.lifecycle_status, p.created_at, a.name as area_name
+SELECT id, code, url, area_id, description, manufacturer_code, quantity, replacement_url, sap_code, observations, min_quantity, max_quantity, inventory_status, lifecycle_status, created_at, updated_at, family, datasheet_url, deleted_at, area_name FROM (
+    SELECT p.id, p.code, p.url, p.area_id, p.description, p.manufacturer_code, p.quantity, p.replacement_url, p.sap_code, p.observations, p.min_quantity, p.max_quantity, p.inventory_status, p.lifecycle_status, p.created_at, p.updated_at, p.family, p.datasheet_url, p.deleted_at, a.name as area_name
     FROM products p
     LEFT JOIN areas a ON p.area_id = a.id
+    WHERE p.deleted_at IS NULL
     ORDER BY p.code, p.created_at DESC
 ) sub
 ORDER BY lifecycle_status DESC, created_at DESC
@@ -451,6 +922,10 @@ type ListProductsRow struct {
 	InventoryStatus  pgtype.Text      `json:"inventory_status"`
 	LifecycleStatus  pgtype.Text      `json:"lifecycle_status"`
 	CreatedAt        pgtype.Timestamp `json:"created_at"`
+	UpdatedAt        pgtype.Timestamp `json:"updated_at"`
+	Family           pgtype.Text      `json:"family"`
+	DatasheetUrl     pgtype.Text      `json:"datasheet_url"`
+	DeletedAt        pgtype.Timestamp `json:"deleted_at"`
 	AreaName         pgtype.Text      `json:"area_name"`
 }
 
@@ -479,6 +954,85 @@ func (q *Queries) ListProducts(ctx context.Context) ([]ListProductsRow, error) {
 			&i.InventoryStatus,
 			&i.LifecycleStatus,
 			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Family,
+			&i.DatasheetUrl,
+			&i.DeletedAt,
+			&i.AreaName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listProductsBelowMinimum = `-- name: ListProductsBelowMinimum :many
+SELECT p.id, p.code, p.url, p.area_id, p.description, p.manufacturer_code, p.quantity, p.replacement_url, p.sap_code, p.observations, p.min_quantity, p.max_quantity, p.inventory_status, p.lifecycle_status, p.created_at, p.updated_at, p.family, p.datasheet_url, p.deleted_at, a.name as area_name
+FROM products p
+LEFT JOIN areas a ON p.area_id = a.id
+WHERE p.deleted_at IS NULL
+AND p.min_quantity > 0
+AND COALESCE(p.quantity, 0) < p.min_quantity
+ORDER BY a.name, p.code
+`
+
+type ListProductsBelowMinimumRow struct {
+	ID               pgtype.UUID      `json:"id"`
+	Code             string           `json:"code"`
+	Url              string           `json:"url"`
+	AreaID           pgtype.UUID      `json:"area_id"`
+	Description      pgtype.Text      `json:"description"`
+	ManufacturerCode pgtype.Text      `json:"manufacturer_code"`
+	Quantity         pgtype.Int4      `json:"quantity"`
+	ReplacementUrl   pgtype.Text      `json:"replacement_url"`
+	SapCode          pgtype.Text      `json:"sap_code"`
+	Observations     pgtype.Text      `json:"observations"`
+	MinQuantity      pgtype.Int4      `json:"min_quantity"`
+	MaxQuantity      pgtype.Int4      `json:"max_quantity"`
+	InventoryStatus  pgtype.Text      `json:"inventory_status"`
+	LifecycleStatus  pgtype.Text      `json:"lifecycle_status"`
+	CreatedAt        pgtype.Timestamp `json:"created_at"`
+	UpdatedAt        pgtype.Timestamp `json:"updated_at"`
+	Family           pgtype.Text      `json:"family"`
+	DatasheetUrl     pgtype.Text      `json:"datasheet_url"`
+	DeletedAt        pgtype.Timestamp `json:"deleted_at"`
+	AreaName         pgtype.Text      `json:"area_name"`
+}
+
+// Estoque baixo por area: quantidade e minimo sao da area de cada produto; minimo zero nao alerta
+func (q *Queries) ListProductsBelowMinimum(ctx context.Context) ([]ListProductsBelowMinimumRow, error) {
+	rows, err := q.db.Query(ctx, listProductsBelowMinimum)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListProductsBelowMinimumRow
+	for rows.Next() {
+		var i ListProductsBelowMinimumRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Code,
+			&i.Url,
+			&i.AreaID,
+			&i.Description,
+			&i.ManufacturerCode,
+			&i.Quantity,
+			&i.ReplacementUrl,
+			&i.SapCode,
+			&i.Observations,
+			&i.MinQuantity,
+			&i.MaxQuantity,
+			&i.InventoryStatus,
+			&i.LifecycleStatus,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Family,
+			&i.DatasheetUrl,
+			&i.DeletedAt,
 			&i.AreaName,
 		); err != nil {
 			return nil, err
@@ -492,10 +1046,10 @@ func (q *Queries) ListProducts(ctx context.Context) ([]ListProductsRow, error) {
 }
 
 const listProductsByArea = `-- name: ListProductsByArea :many
-SELECT p.id, p.code, p.url, p.area_id, p.description, p.manufacturer_code, p.quantity, p.replacement_url, p.sap_code, p.observations, p.min_quantity, p.max_quantity, p.inventory_status, p.lifecycle_status, p.created_at, a.name as area_name
+SELECT p.id, p.code, p.url, p.area_id, p.description, p.manufacturer_code, p.quantity, p.replacement_url, p.sap_code, p.observations, p.min_quantity, p.max_quantity, p.inventory_status, p.lifecycle_status, p.created_at, p.updated_at, p.family, p.datasheet_url, p.deleted_at, a.name as area_name
 FROM products p
 LEFT JOIN areas a ON p.area_id = a.id
-WHERE p.area_id = $1
+WHERE p.area_id = $1 AND p.deleted_at IS NULL
 ORDER BY p.lifecycle_status DESC, p.created_at DESC
 `
 
@@ -515,6 +1069,10 @@ type ListProductsByAreaRow struct {
 	InventoryStatus  pgtype.Text      `json:"inventory_status"`
 	LifecycleStatus  pgtype.Text      `json:"lifecycle_status"`
 	CreatedAt        pgtype.Timestamp `json:"created_at"`
+	UpdatedAt        pgtype.Timestamp `json:"updated_at"`
+	Family           pgtype.Text      `json:"family"`
+	DatasheetUrl     pgtype.Text      `json:"datasheet_url"`
+	DeletedAt        pgtype.Timestamp `json:"deleted_at"`
 	AreaName         pgtype.Text      `json:"area_name"`
 }
 
@@ -543,6 +1101,10 @@ func (q *Queries) ListProductsByArea(ctx context.Context, areaID pgtype.UUID) ([
 			&i.InventoryStatus,
 			&i.LifecycleStatus,
 			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Family,
+			&i.DatasheetUrl,
+			&i.DeletedAt,
 			&i.AreaName,
 		); err != nil {
 			return nil, err
@@ -556,10 +1118,10 @@ func (q *Queries) ListProductsByArea(ctx context.Context, areaID pgtype.UUID) ([
 }
 
 const listProductsByAreaPaginated = `-- name: ListProductsByAreaPaginated :many
-SELECT p.id, p.code, p.url, p.area_id, p.description, p.manufacturer_code, p.quantity, p.replacement_url, p.sap_code, p.observations, p.min_quantity, p.max_quantity, p.inventory_status, p.lifecycle_status, p.created_at, a.name as area_name
+SELECT p.id, p.code, p.url, p.area_id, p.description, p.manufacturer_code, p.quantity, p.replacement_url, p.sap_code, p.observations, p.min_quantity, p.max_quantity, p.inventory_status, p.lifecycle_status, p.created_at, p.updated_at, p.family, p.datasheet_url, p.deleted_at, a.name as area_name
 FROM products p
 LEFT JOIN areas a ON p.area_id = a.id
-WHERE p.area_id = $1
+WHERE p.area_id = $1 AND p.deleted_at IS NULL
 ORDER BY p.lifecycle_status DESC, p.created_at DESC
 LIMIT $2 OFFSET $3
 `
@@ -586,6 +1148,10 @@ type ListProductsByAreaPaginatedRow struct {
 	InventoryStatus  pgtype.Text      `json:"inventory_status"`
 	LifecycleStatus  pgtype.Text      `json:"lifecycle_status"`
 	CreatedAt        pgtype.Timestamp `json:"created_at"`
+	UpdatedAt        pgtype.Timestamp `json:"updated_at"`
+	Family           pgtype.Text      `json:"family"`
+	DatasheetUrl     pgtype.Text      `json:"datasheet_url"`
+	DeletedAt        pgtype.Timestamp `json:"deleted_at"`
 	AreaName         pgtype.Text      `json:"area_name"`
 }
 
@@ -614,6 +1180,10 @@ func (q *Queries) ListProductsByAreaPaginated(ctx context.Context, arg ListProdu
 			&i.InventoryStatus,
 			&i.LifecycleStatus,
 			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Family,
+			&i.DatasheetUrl,
+			&i.DeletedAt,
 			&i.AreaName,
 		); err != nil {
 			return nil, err
@@ -626,11 +1196,122 @@ func (q *Queries) ListProductsByAreaPaginated(ctx context.Context, arg ListProdu
 	return items, nil
 }
 
+const listProductsForExport = `-- name: ListProductsForExport :many
+SELECT p.id, p.code, p.url, p.area_id, p.description, p.manufacturer_code, p.quantity, p.replacement_url, p.sap_code, p.observations, p.min_quantity, p.max_quantity, p.inventory_status, p.lifecycle_status, p.created_at, p.updated_at, p.family, p.datasheet_url, p.deleted_at, a.name as area_name,
+    s.id as snapshot_id,
+    s.description as snapshot_description,
+    s.status as snapshot_status,
+    s.collected_at as snapshot_collected_at
+FROM products p
+LEFT JOIN areas a ON p.area_id = a.id
+LEFT JOIN LATERAL (
+    SELECT ps.id, ps.description, ps.status, ps.collected_at
+    FROM product_snapshots ps
+    WHERE ps.product_id = p.id
+    ORDER BY ps.collected_at DESC
+    LIMIT 1
+) s ON true
+WHERE p.deleted_at IS NULL
+  AND ($1::uuid IS NULL OR p.area_id = $1::uuid)
+  AND ($2::text = ''
+   OR p.code ILIKE '%' || $2::text || '%'
+   OR p.description ILIKE '%' || $2::text || '%'
+   OR p.sap_code ILIKE '%' || $2::text || '%')
+  AND p.id > $3
+ORDER BY p.id
+LIMIT $4
+`
+
+type ListProductsForExportParams struct {
+	AreaID  pgtype.UUID `json:"area_id"`
+	Search  string      `json:"search"`
+	AfterID pgtype.UUID `json:"after_id"`
+	Limit   int32       `json:"limit"`
+}
+
+type ListProductsForExportRow struct {
+	ID                  pgtype.UUID      `json:"id"`
+	Code                string           `json:"code"`
+	Url                 string           `json:"url"`
+	AreaID              pgtype.UUID      `json:"area_id"`
+	Description         pgtype.Text      `json:"description"`
+	ManufacturerCode    pgtype.Text      `json:"manufacturer_code"`
+	Quantity            pgtype.Int4      `json:"quantity"`
+	ReplacementUrl      pgtype.Text      `json:"replacement_url"`
+	SapCode             pgtype.Text      `json:"sap_code"`
+	Observations        pgtype.Text      `json:"observations"`
+	MinQuantity         pgtype.Int4      `json:"min_quantity"`
+	MaxQuantity         pgtype.Int4      `json:"max_quantity"`
+	InventoryStatus     pgtype.Text      `json:"inventory_status"`
+	LifecycleStatus     pgtype.Text      `json:"lifecycle_status"`
+	CreatedAt           pgtype.Timestamp `json:"created_at"`
+	UpdatedAt           pgtype.Timestamp `json:"updated_at"`
+	Family              pgtype.Text      `json:"family"`
+	DatasheetUrl        pgtype.Text      `json:"datasheet_url"`
+	DeletedAt           pgtype.Timestamp `json:"deleted_at"`
+	AreaName            pgtype.Text      `json:"area_name"`
+	SnapshotID          pgtype.UUID      `json:"snapshot_id"`
+	SnapshotDescription pgtype.Text      `json:"snapshot_description"`
+	SnapshotStatus      pgtype.Text      `json:"snapshot_status"`
+	SnapshotCollectedAt pgtype.Timestamp `json:"snapshot_collected_at"`
+}
+
+func (q *Queries) ListProductsForExport(ctx context.Context, arg ListProductsForExportParams) ([]ListProductsForExportRow, error) {
+	rows, err := q.db.Query(ctx, listProductsForExport,
+		arg.AreaID,
+		arg.Search,
+		arg.AfterID,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListProductsForExportRow
+	for rows.Next() {
+		var i ListProductsForExportRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Code,
+			&i.Url,
+			&i.AreaID,
+			&i.Description,
+			&i.ManufacturerCode,
+			&i.Quantity,
+			&i.ReplacementUrl,
+			&i.SapCode,
+			&i.Observations,
+			&i.MinQuantity,
+			&i.MaxQuantity,
+			&i.InventoryStatus,
+			&i.LifecycleStatus,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Family,
+			&i.DatasheetUrl,
+			&i.DeletedAt,
+			&i.AreaName,
+			&i.SnapshotID,
+			&i.SnapshotDescription,
+			&i.SnapshotStatus,
+			&i.SnapshotCollectedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listProductsPaginated = `-- name: ListProductsPaginated :many
-SELECT id, code, url, area_id, description, manufacturer_code, quantity, replacement_url, sap_code, observations, min_quantity, max_quantity, inventory_status, lifecycle_status, created_at, area_name FROM (
-    SELECT p.id, p.code, p.url, p.area_id, p.description, p.manufacturer_code, p.quantity, p.replacement_url, p.sap_code, p.observations, p.min_quantity, p.max_quantity, p.inventory_status, p.lifecycle_status, p.created_at, a.name as area_name
+SELECT id, code, url, area_id, description, manufacturer_code, quantity, replacement_url, sap_code, observations, min_quantity, max_quantity, inventory_status, lifecycle_status, created_at, updated_at, family, datasheet_url, deleted_at, area_name FROM (
+    SELECT p.id, p.code, p.url, p.area_id, p.description, p.manufacturer_code, p.quantity, p.replacement_url, p.sap_code, p.observations, p.min_quantity, p.max_quantity, p.inventory_status, p.lifecycle_status, p.created_at, p.updated_at, p.family, p.datasheet_url, p.deleted_at, a.name as area_name
     FROM products p
     LEFT JOIN areas a ON p.area_id = a.id
+    WHERE p.deleted_at IS NULL
     ORDER BY p.code, p.created_at DESC
 ) sub
 ORDER BY lifecycle_status DESC, created_at DESC
@@ -658,6 +1339,10 @@ type ListProductsPaginatedRow struct {
 	InventoryStatus  pgtype.Text      `json:"inventory_status"`
 	LifecycleStatus  pgtype.Text      `json:"lifecycle_status"`
 	CreatedAt        pgtype.Timestamp `json:"created_at"`
+	UpdatedAt        pgtype.Timestamp `json:"updated_at"`
+	Family           pgtype.Text      `json:"family"`
+	DatasheetUrl     pgtype.Text      `json:"datasheet_url"`
+	DeletedAt        pgtype.Timestamp `json:"deleted_at"`
 	AreaName         pgtype.Text      `json:"area_name"`
 }
 
@@ -686,6 +1371,10 @@ func (q *Queries) ListProductsPaginated(ctx context.Context, arg ListProductsPag
 			&i.InventoryStatus,
 			&i.LifecycleStatus,
 			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Family,
+			&i.DatasheetUrl,
+			&i.DeletedAt,
 			&i.AreaName,
 		); err != nil {
 			return nil, err
@@ -698,28 +1387,203 @@ func (q *Queries) ListProductsPaginated(ctx context.Context, arg ListProductsPag
 	return items, nil
 }
 
+const listStaleProductCodesToCollect = `-- name: ListStaleProductCodesToCollect :many
+SELECT DISTINCT ON (p.code) p.id, p.code, p.url
+FROM products p
+WHERE p.deleted_at IS NULL
+  AND NOT EXISTS (
+    SELECT 1 FROM product_snapshots s
+    JOIN products sp ON sp.id = s.product_id
+    WHERE sp.code = p.code AND s.collected_at >= $1::timestamp
+)
+ORDER BY p.code, p.created_at ASC
+`
+
+type ListStaleProductCodesToCollectRow struct {
+	ID   pgtype.UUID `json:"id"`
+	Code string      `json:"code"`
+	Url  string      `json:"url"`
+}
+
+// Codigos sem nenhuma coleta (de qualquer produto com o codigo) desde collected_before
+func (q *Queries) ListStaleProductCodesToCollect(ctx context.Context, collectedBefore pgtype.Timestamp) ([]ListStaleProductCodesToCollectRow, error) {
+	rows, err := q.db.Query(ctx, listStaleProductCodesToCollect, collectedBefore)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListStaleProductCodesToCollectRow
+	for rows.Next() {
+		var i ListStaleProductCodesToCollectRow
+		if err := rows.Scan(&i.ID, &i.Code, &i.Url); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listUniqueProductCodesToCollect = `-- name: ListUniqueProductCodesToCollect :many
 SELECT DISTINCT ON (p.code) p.id, p.code, p.url
 FROM products p
+WHERE p.deleted_at IS NULL
 ORDER BY p.code, p.created_at ASC
 `
 
-type ListUniqueProductCodesToCollectRow struct {
-	ID   pgtype.UUID `json:"id"`
-	Code string      `json:"code"`
-	Url  string      `json:"url"`
+type ListUniqueProductCodesToCollectRow struct {
+	ID   pgtype.UUID `json:"id"`
+	Code string      `json:"code"`
+	Url  string      `json:"url"`
+}
+
+func (q *Queries) ListUniqueProductCodesToCollect(ctx context.Context) ([]ListUniqueProductCodesToCollectRow, error) {
+	rows, err := q.db.Query(ctx, listUniqueProductCodesToCollect)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListUniqueProductCodesToCollectRow
+	for rows.Next() {
+		var i ListUniqueProductCodesToCollectRow
+		if err := rows.Scan(&i.ID, &i.Code, &i.Url); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUniqueProductCodesWithUnknownStatus = `-- name: ListUniqueProductCodesWithUnknownStatus :many
+SELECT DISTINCT ON (p.code) p.id, p.code, p.url
+FROM products p
+WHERE p.deleted_at IS NULL
+  AND (COALESCE(p.lifecycle_status, '') = ''
+   OR NOT EXISTS (SELECT 1 FROM product_snapshots s WHERE s.product_id = p.id))
+ORDER BY p.code, p.created_at ASC
+`
+
+type ListUniqueProductCodesWithUnknownStatusRow struct {
+	ID   pgtype.UUID `json:"id"`
+	Code string      `json:"code"`
+	Url  string      `json:"url"`
+}
+
+// Codigos sem status de ciclo de vida ou com algum produto nunca coletado (sem snapshot)
+func (q *Queries) ListUniqueProductCodesWithUnknownStatus(ctx context.Context) ([]ListUniqueProductCodesWithUnknownStatusRow, error) {
+	rows, err := q.db.Query(ctx, listUniqueProductCodesWithUnknownStatus)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListUniqueProductCodesWithUnknownStatusRow
+	for rows.Next() {
+		var i ListUniqueProductCodesWithUnknownStatusRow
+		if err := rows.Scan(&i.ID, &i.Code, &i.Url); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUniqueProductsByAreaPaginated = `-- name: ListUniqueProductsByAreaPaginated :many
+WITH product_aggregates AS (
+    SELECT
+        p.code,
+        MIN(p.description) as description,
+        MIN(p.lifecycle_status) as lifecycle_status,
+        MIN(p.replacement_url) as replacement_url,
+        MIN(p.family) as family,
+        MIN(p.datasheet_url) as datasheet_url,
+        MIN(p.url) as url,
+        MIN(p.manufacturer_code) as manufacturer_code,
+        MIN(p.sap_code) as sap_code,
+        SUM(COALESCE(p.quantity, 0))::INTEGER as total_quantity,
+        MIN(p.created_at) as created_at,
+        json_agg(
+            json_build_object(
+                'area_id', p2.area_id,
+                'area_name', a.name,
+                'quantity', COALESCE(p2.quantity, 0)
+            ) ORDER BY a.name
+        ) FILTER (WHERE p2.area_id IS NOT NULL) as quantity_by_area
+    FROM products p
+    INNER JOIN products p2 ON p.code = p2.code AND p2.deleted_at IS NULL
+    LEFT JOIN areas a ON p2.area_id = a.id
+    WHERE p.area_id = $1 AND p.deleted_at IS NULL
+    GROUP BY p.code
+)
+SELECT code, description, lifecycle_status, replacement_url, family, datasheet_url, url, manufacturer_code, sap_code, total_quantity, created_at, quantity_by_area FROM product_aggregates
+ORDER BY
+    CASE WHEN $2::text = 'code' AND $3::text = 'asc' THEN code END ASC,
+    CASE WHEN $2::text = 'code' AND $3::text = 'desc' THEN code END DESC,
+    CASE WHEN $2::text = 'description' AND $3::text = 'asc' THEN description END ASC,
+    CASE WHEN $2::text = 'description' AND $3::text = 'desc' THEN description END DESC,
+    CASE WHEN $2::text = 'created_at' AND $3::text = 'asc' THEN created_at END ASC,
+    CASE WHEN $2::text = 'created_at' AND $3::text = 'desc' THEN created_at END DESC,
+    CASE WHEN $2::text = 'total_quantity' AND $3::text = 'asc' THEN total_quantity END ASC,
+    CASE WHEN $2::text = 'total_quantity' AND $3::text = 'desc' THEN total_quantity END DESC,
+    CASE WHEN $2::text = 'lifecycle_status' AND $3::text = 'asc' THEN lifecycle_status END ASC,
+    CASE WHEN $2::text = 'lifecycle_status' AND $3::text = 'desc' THEN lifecycle_status END DESC,
+    code ASC
+LIMIT $4 OFFSET $5
+`
+
+type ListUniqueProductsByAreaPaginatedParams struct {
+	AreaID    pgtype.UUID `json:"area_id"`
+	SortBy    string      `json:"sort_by"`
+	SortOrder string      `json:"sort_order"`
+	Limit     int32       `json:"limit"`
+	Offset    int32       `json:"offset"`
 }
 
-func (q *Queries) ListUniqueProductCodesToCollect(ctx context.Context) ([]ListUniqueProductCodesToCollectRow, error) {
-	rows, err := q.db.Query(ctx, listUniqueProductCodesToCollect)
+type ListUniqueProductsByAreaPaginatedRow struct {
+	Code             string      `json:"code"`
+	Description      interface{} `json:"description"`
+	LifecycleStatus  interface{} `json:"lifecycle_status"`
+	ReplacementUrl   interface{} `json:"replacement_url"`
+	Family           interface{} `json:"family"`
+	DatasheetUrl     interface{} `json:"datasheet_url"`
+	Url              interface{} `json:"url"`
+	ManufacturerCode interface{} `json:"manufacturer_code"`
+	SapCode          interface{} `json:"sap_code"`
+	TotalQuantity    int32       `json:"total_quantity"`
+	CreatedAt        interface{} `json:"created_at"`
+	QuantityByArea   []byte      `json:"quantity_by_area"`
+}
+
+func (q *Queries) ListUniqueProductsByAreaPaginated(ctx context.Context, arg ListUniqueProductsByAreaPaginatedParams) ([]ListUniqueProductsByAreaPaginatedRow, error) {
+	rows, err := q.db.Query(ctx, listUniqueProductsByAreaPaginated, arg.AreaID, arg.SortBy, arg.SortOrder, arg.Limit, arg.Offset)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []ListUniqueProductCodesToCollectRow
+	var items []ListUniqueProductsByAreaPaginatedRow
 	for rows.Next() {
-		var i ListUniqueProductCodesToCollectRow
-		if err := rows.Scan(&i.ID, &i.Code, &i.Url); err != nil {
+		var i ListUniqueProductsByAreaPaginatedRow
+		if err := rows.Scan(
+			&i.Code,
+			&i.Description,
+			&i.LifecycleStatus,
+			&i.ReplacementUrl,
+			&i.Family,
+			&i.DatasheetUrl,
+			&i.Url,
+			&i.ManufacturerCode,
+			&i.SapCode,
+			&i.TotalQuantity,
+			&i.CreatedAt,
+			&i.QuantityByArea,
+		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -730,13 +1594,15 @@ func (q *Queries) ListUniqueProductCodesToCollect(ctx context.Context) ([]ListUn
 	return items, nil
 }
 
-const listUniqueProductsByAreaPaginated = `-- name: ListUniqueProductsByAreaPaginated :many
+const listUniqueProductsByStatusPaginated = `-- name: ListUniqueProductsByStatusPaginated :many
 WITH product_aggregates AS (
     SELECT
         p.code,
         MIN(p.description) as description,
         MIN(p.lifecycle_status) as lifecycle_status,
         MIN(p.replacement_url) as replacement_url,
+        MIN(p.family) as family,
+        MIN(p.datasheet_url) as datasheet_url,
         MIN(p.url) as url,
         MIN(p.manufacturer_code) as manufacturer_code,
         MIN(p.sap_code) as sap_code,
@@ -750,27 +1616,53 @@ WITH product_aggregates AS (
             ) ORDER BY a.name
         ) FILTER (WHERE p2.area_id IS NOT NULL) as quantity_by_area
     FROM products p
-    INNER JOIN products p2 ON p.code = p2.code
+    INNER JOIN products p2 ON p.code = p2.code AND p2.deleted_at IS NULL
+        AND ($1::uuid IS NOT NULL OR p2.id = p.id)
     LEFT JOIN areas a ON p2.area_id = a.id
-    WHERE p.area_id = $3
+    WHERE p.deleted_at IS NULL
+      AND ($1::uuid IS NULL OR p.area_id = $1::uuid)
     GROUP BY p.code
 )
-SELECT code, description, lifecycle_status, replacement_url, url, manufacturer_code, sap_code, total_quantity, created_at, quantity_by_area FROM product_aggregates
-ORDER BY lifecycle_status DESC, created_at DESC
-LIMIT $2 OFFSET $1
+SELECT code, description, lifecycle_status, replacement_url, family, datasheet_url, url, manufacturer_code, sap_code, total_quantity, created_at, quantity_by_area FROM product_aggregates
+WHERE lifecycle_status = ANY($2::text[])
+  AND ($3::text IS NULL
+   OR code ILIKE '%' || $3::text || '%'
+   OR description ILIKE '%' || $3::text || '%'
+   OR sap_code ILIKE '%' || $3::text || '%'
+   OR manufacturer_code ILIKE '%' || $3::text || '%'
+   OR lifecycle_status ILIKE '%' || $3::text || '%')
+ORDER BY
+    CASE WHEN $4::text = 'code' AND $5::text = 'asc' THEN code END ASC,
+    CASE WHEN $4::text = 'code' AND $5::text = 'desc' THEN code END DESC,
+    CASE WHEN $4::text = 'description' AND $5::text = 'asc' THEN description END ASC,
+    CASE WHEN $4::text = 'description' AND $5::text = 'desc' THEN description END DESC,
+    CASE WHEN $4::text = 'created_at' AND $5::text = 'asc' THEN created_at END ASC,
+    CASE WHEN $4::text = 'created_at' AND $5::text = 'desc' THEN created_at END DESC,
+    CASE WHEN $4::text = 'total_quantity' AND $5::text = 'asc' THEN total_quantity END ASC,
+    CASE WHEN $4::text = 'total_quantity' AND $5::text = 'desc' THEN total_quantity END DESC,
+    CASE WHEN $4::text = 'lifecycle_status' AND $5::text = 'asc' THEN lifecycle_status END ASC,
+    CASE WHEN $4::text = 'lifecycle_status' AND $5::text = 'desc' THEN lifecycle_status END DESC,
+    code ASC
+LIMIT $6 OFFSET $7
 `
 
-type ListUniqueProductsByAreaPaginatedParams struct {
-	Offset int32       `json:"offset"`
-	Limit  int32       `json:"limit"`
-	AreaID pgtype.UUID `json:"area_id"`
+type ListUniqueProductsByStatusPaginatedParams struct {
+	AreaID    pgtype.UUID `json:"area_id"`
+	Statuses  []string    `json:"statuses"`
+	Search    pgtype.Text `json:"search"`
+	SortBy    string      `json:"sort_by"`
+	SortOrder string      `json:"sort_order"`
+	Limit     int32       `json:"limit"`
+	Offset    int32       `json:"offset"`
 }
 
-type ListUniqueProductsByAreaPaginatedRow struct {
+type ListUniqueProductsByStatusPaginatedRow struct {
 	Code             string      `json:"code"`
 	Description      interface{} `json:"description"`
 	LifecycleStatus  interface{} `json:"lifecycle_status"`
 	ReplacementUrl   interface{} `json:"replacement_url"`
+	Family           interface{} `json:"family"`
+	DatasheetUrl     interface{} `json:"datasheet_url"`
 	Url              interface{} `json:"url"`
 	ManufacturerCode interface{} `json:"manufacturer_code"`
 	SapCode          interface{} `json:"sap_code"`
@@ -779,20 +1671,32 @@ type ListUniqueProductsByAreaPaginatedRow struct {
 	QuantityByArea   []byte      `json:"quantity_by_area"`
 }
 
-func (q *Queries) ListUniqueProductsByAreaPaginated(ctx context.Context, arg ListUniqueProductsByAreaPaginatedParams) ([]ListUniqueProductsByAreaPaginatedRow, error) {
-	rows, err := q.db.Query(ctx, listUniqueProductsByAreaPaginated, arg.Offset, arg.Limit, arg.AreaID)
+// Listagem com filtro lifecycle_status; area_id e search sao opcionais. Com area, o p2 traz as
+// outras areas do codigo para quantity_by_area; sem area, p2 e a propria linha
+func (q *Queries) ListUniqueProductsByStatusPaginated(ctx context.Context, arg ListUniqueProductsByStatusPaginatedParams) ([]ListUniqueProductsByStatusPaginatedRow, error) {
+	rows, err := q.db.Query(ctx, listUniqueProductsByStatusPaginated,
+		arg.AreaID,
+		arg.Statuses,
+		arg.Search,
+		arg.SortBy,
+		arg.SortOrder,
+		arg.Limit,
+		arg.Offset,
+	)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []ListUniqueProductsByAreaPaginatedRow
+	var items []ListUniqueProductsByStatusPaginatedRow
 	for rows.Next() {
-		var i ListUniqueProductsByAreaPaginatedRow
+		var i ListUniqueProductsByStatusPaginatedRow
 		if err := rows.Scan(
 			&i.Code,
 			&i.Description,
 			&i.LifecycleStatus,
 			&i.ReplacementUrl,
+			&i.Family,
+			&i.DatasheetUrl,
 			&i.Url,
 			&i.ManufacturerCode,
 			&i.SapCode,
@@ -817,6 +1721,8 @@ WITH product_aggregates AS (
         MIN(p.description) as description,
         MIN(p.lifecycle_status) as lifecycle_status,
         MIN(p.replacement_url) as replacement_url,
+        MIN(p.family) as family,
+        MIN(p.datasheet_url) as datasheet_url,
         MIN(p.url) as url,
         MIN(p.manufacturer_code) as manufacturer_code,
         MIN(p.sap_code) as sap_code,
@@ -831,16 +1737,30 @@ WITH product_aggregates AS (
         ) FILTER (WHERE p.area_id IS NOT NULL) as quantity_by_area
     FROM products p
     LEFT JOIN areas a ON p.area_id = a.id
+    WHERE p.deleted_at IS NULL
     GROUP BY p.code
 )
-SELECT code, description, lifecycle_status, replacement_url, url, manufacturer_code, sap_code, total_quantity, created_at, quantity_by_area FROM product_aggregates
-ORDER BY lifecycle_status DESC, created_at DESC
-LIMIT $1 OFFSET $2
+SELECT code, description, lifecycle_status, replacement_url, family, datasheet_url, url, manufacturer_code, sap_code, total_quantity, created_at, quantity_by_area FROM product_aggregates
+ORDER BY
+    CASE WHEN $1::text = 'code' AND $2::text = 'asc' THEN code END ASC,
+    CASE WHEN $1::text = 'code' AND $2::text = 'desc' THEN code END DESC,
+    CASE WHEN $1::text = 'description' AND $2::text = 'asc' THEN description END ASC,
+    CASE WHEN $1::text = 'description' AND $2::text = 'desc' THEN description END DESC,
+    CASE WHEN $1::text = 'created_at' AND $2::text = 'asc' THEN created_at END ASC,
+    CASE WHEN $1::text = 'created_at' AND $2::text = 'desc' THEN created_at END DESC,
+    CASE WHEN $1::text = 'total_quantity' AND $2::text = 'asc' THEN total_quantity END ASC,
+    CASE WHEN $1::text = 'total_quantity' AND $2::text = 'desc' THEN total_quantity END DESC,
+    CASE WHEN $1::text = 'lifecycle_status' AND $2::text = 'asc' THEN lifecycle_status END ASC,
+    CASE WHEN $1::text = 'lifecycle_status' AND $2::text = 'desc' THEN lifecycle_status END DESC,
+    code ASC
+LIMIT $3 OFFSET $4
 `
 
 type ListUniqueProductsPaginatedParams struct {
-	Limit  int32 `json:"limit"`
-	Offset int32 `json:"offset"`
+	SortBy    string `json:"sort_by"`
+	SortOrder string `json:"sort_order"`
+	Limit     int32  `json:"limit"`
+	Offset    int32  `json:"offset"`
 }
 
 type ListUniqueProductsPaginatedRow struct {
@@ -848,6 +1768,8 @@ type ListUniqueProductsPaginatedRow struct {
 	Description      interface{} `json:"description"`
 	LifecycleStatus  interface{} `json:"lifecycle_status"`
 	ReplacementUrl   interface{} `json:"replacement_url"`
+	Family           interface{} `json:"family"`
+	DatasheetUrl     interface{} `json:"datasheet_url"`
 	Url              interface{} `json:"url"`
 	ManufacturerCode interface{} `json:"manufacturer_code"`
 	SapCode          interface{} `json:"sap_code"`
@@ -857,7 +1779,7 @@ type ListUniqueProductsPaginatedRow struct {
 }
 
 func (q *Queries) ListUniqueProductsPaginated(ctx context.Context, arg ListUniqueProductsPaginatedParams) ([]ListUniqueProductsPaginatedRow, error) {
-	rows, err := q.db.Query(ctx, listUniqueProductsPaginated, arg.Limit, arg.Offset)
+	rows, err := q.db.Query(ctx, listUniqueProductsPaginated, arg.SortBy, arg.SortOrder, arg.Limit, arg.Offset)
 	if err != nil {
 		return nil, err
 	}
@@ -870,6 +1792,8 @@ func (q *Queries) ListUniqueProductsPaginated(ctx context.Context, arg ListUniqu
 			&i.Description,
 			&i.LifecycleStatus,
 			&i.ReplacementUrl,
+			&i.Family,
+			&i.DatasheetUrl,
 			&i.Url,
 			&i.ManufacturerCode,
 			&i.SapCode,
@@ -887,15 +1811,46 @@ func (q *Queries) ListUniqueProductsPaginated(ctx context.Context, arg ListUniqu
 	return items, nil
 }
 
+const rebuildProductURLs = `-- name: RebuildProductURLs :execrows
+UPDATE products
+SET url = $1::text || '/' || code,
+    updated_at = NOW()
+WHERE url <> $1::text || '/' || code
+`
+
+func (q *Queries) RebuildProductURLs(ctx context.Context, baseUrl string) (int64, error) {
+	result, err := q.db.Exec(ctx, rebuildProductURLs, baseUrl)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const restoreProduct = `-- name: RestoreProduct :execrows
+UPDATE products
+SET deleted_at = NULL,
+    updated_at = NOW()
+WHERE id = $1 AND deleted_at IS NOT NULL
+`
+
+func (q *Queries) RestoreProduct(ctx context.Context, id pgtype.UUID) (int64, error) {
+	result, err := q.db.Exec(ctx, restoreProduct, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const searchProducts = `-- name: SearchProducts :many
-SELECT p.id, p.code, p.url, p.area_id, p.description, p.manufacturer_code, p.quantity, p.replacement_url, p.sap_code, p.observations, p.min_quantity, p.max_quantity, p.inventory_status, p.lifecycle_status, p.created_at, a.name as area_name
+SELECT p.id, p.code, p.url, p.area_id, p.description, p.manufacturer_code, p.quantity, p.replacement_url, p.sap_code, p.observations, p.min_quantity, p.max_quantity, p.inventory_status, p.lifecycle_status, p.created_at, p.updated_at, p.family, p.datasheet_url, p.deleted_at, a.name as area_name
 FROM products p
 LEFT JOIN areas a ON p.area_id = a.id
-WHERE p.code ILIKE '%' || $1::text || '%'
+WHERE p.deleted_at IS NULL
+  AND (p.code ILIKE '%' || $1::text || '%'
    OR p.description ILIKE '%' || $1::text || '%'
    OR p.sap_code ILIKE '%' || $1::text || '%'
    OR p.manufacturer_code ILIKE '%' || $1::text || '%'
-   OR p.lifecycle_status ILIKE '%' || $1::text || '%'
+   OR p.lifecycle_status ILIKE '%' || $1::text || '%')
 ORDER BY p.lifecycle_status DESC, p.created_at DESC
 LIMIT $3 OFFSET $2
 `
@@ -922,6 +1877,10 @@ type SearchProductsRow struct {
 	InventoryStatus  pgtype.Text      `json:"inventory_status"`
 	LifecycleStatus  pgtype.Text      `json:"lifecycle_status"`
 	CreatedAt        pgtype.Timestamp `json:"created_at"`
+	UpdatedAt        pgtype.Timestamp `json:"updated_at"`
+	Family           pgtype.Text      `json:"family"`
+	DatasheetUrl     pgtype.Text      `json:"datasheet_url"`
+	DeletedAt        pgtype.Timestamp `json:"deleted_at"`
 	AreaName         pgtype.Text      `json:"area_name"`
 }
 
@@ -950,6 +1909,10 @@ func (q *Queries) SearchProducts(ctx context.Context, arg SearchProductsParams)
 			&i.InventoryStatus,
 			&i.LifecycleStatus,
 			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Family,
+			&i.DatasheetUrl,
+			&i.DeletedAt,
 			&i.AreaName,
 		); err != nil {
 			return nil, err
@@ -963,10 +1926,11 @@ func (q *Queries) SearchProducts(ctx context.Context, arg SearchProductsParams)
 }
 
 const searchProductsByArea = `-- name: SearchProductsByArea :many
-SELECT p.id, p.code, p.url, p.area_id, p.description, p.manufacturer_code, p.quantity, p.replacement_url, p.sap_code, p.observations, p.min_quantity, p.max_quantity, p.inventory_status, p.lifecycle_status, p.created_at, a.name as area_name
+SELECT p.id, p.code, p.url, p.area_id, p.description, p.manufacturer_code, p.quantity, p.replacement_url, p.sap_code, p.observations, p.min_quantity, p.max_quantity, p.inventory_status, p.lifecycle_status, p.created_at, p.updated_at, p.family, p.datasheet_url, p.deleted_at, a.name as area_name
 FROM products p
 LEFT JOIN areas a ON p.area_id = a.id
 WHERE p.area_id = $1
+  AND p.deleted_at IS NULL
   AND (p.code ILIKE '%' || $2::text || '%'
    OR p.description ILIKE '%' || $2::text || '%'
    OR p.sap_code ILIKE '%' || $2::text || '%'
@@ -999,6 +1963,10 @@ type SearchProductsByAreaRow struct {
 	InventoryStatus  pgtype.Text      `json:"inventory_status"`
 	LifecycleStatus  pgtype.Text      `json:"lifecycle_status"`
 	CreatedAt        pgtype.Timestamp `json:"created_at"`
+	UpdatedAt        pgtype.Timestamp `json:"updated_at"`
+	Family           pgtype.Text      `json:"family"`
+	DatasheetUrl     pgtype.Text      `json:"datasheet_url"`
+	DeletedAt        pgtype.Timestamp `json:"deleted_at"`
 	AreaName         pgtype.Text      `json:"area_name"`
 }
 
@@ -1032,6 +2000,10 @@ func (q *Queries) SearchProductsByArea(ctx context.Context, arg SearchProductsBy
 			&i.InventoryStatus,
 			&i.LifecycleStatus,
 			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Family,
+			&i.DatasheetUrl,
+			&i.DeletedAt,
 			&i.AreaName,
 		); err != nil {
 			return nil, err
@@ -1051,6 +2023,8 @@ WITH product_aggregates AS (
         MIN(p.description) as description,
         MIN(p.lifecycle_status) as lifecycle_status,
         MIN(p.replacement_url) as replacement_url,
+        MIN(p.family) as family,
+        MIN(p.datasheet_url) as datasheet_url,
         MIN(p.url) as url,
         MIN(p.manufacturer_code) as manufacturer_code,
         MIN(p.sap_code) as sap_code,
@@ -1064,26 +2038,39 @@ WITH product_aggregates AS (
             ) ORDER BY a.name
         ) FILTER (WHERE p2.area_id IS NOT NULL) as quantity_by_area
     FROM products p
-    INNER JOIN products p2 ON p.code = p2.code
+    INNER JOIN products p2 ON p.code = p2.code AND p2.deleted_at IS NULL
     LEFT JOIN areas a ON p2.area_id = a.id
-    WHERE p.area_id = $4
+    WHERE p.area_id = $1 AND p.deleted_at IS NULL
     GROUP BY p.code
 )
-SELECT code, description, lifecycle_status, replacement_url, url, manufacturer_code, sap_code, total_quantity, created_at, quantity_by_area FROM product_aggregates
-WHERE code ILIKE '%' || $1::text || '%'
-   OR description ILIKE '%' || $1::text || '%'
-   OR sap_code ILIKE '%' || $1::text || '%'
-   OR manufacturer_code ILIKE '%' || $1::text || '%'
-   OR lifecycle_status ILIKE '%' || $1::text || '%'
-ORDER BY lifecycle_status DESC, created_at DESC
-LIMIT $3 OFFSET $2
+SELECT code, description, lifecycle_status, replacement_url, family, datasheet_url, url, manufacturer_code, sap_code, total_quantity, created_at, quantity_by_area FROM product_aggregates
+WHERE code ILIKE '%' || $2::text || '%'
+   OR description ILIKE '%' || $2::text || '%'
+   OR sap_code ILIKE '%' || $2::text || '%'
+   OR manufacturer_code ILIKE '%' || $2::text || '%'
+   OR lifecycle_status ILIKE '%' || $2::text || '%'
+ORDER BY
+    CASE WHEN $3::text = 'code' AND $4::text = 'asc' THEN code END ASC,
+    CASE WHEN $3::text = 'code' AND $4::text = 'desc' THEN code END DESC,
+    CASE WHEN $3::text = 'description' AND $4::text = 'asc' THEN description END ASC,
+    CASE WHEN $3::text = 'description' AND $4::text = 'desc' THEN description END DESC,
+    CASE WHEN $3::text = 'created_at' AND $4::text = 'asc' THEN created_at END ASC,
+    CASE WHEN $3::text = 'created_at' AND $4::text = 'desc' THEN created_at END DESC,
+    CASE WHEN $3::text = 'total_quantity' AND $4::text = 'asc' THEN total_quantity END ASC,
+    CASE WHEN $3::text = 'total_quantity' AND $4::text = 'desc' THEN total_quantity END DESC,
+    CASE WHEN $3::text = 'lifecycle_status' AND $4::text = 'asc' THEN lifecycle_status END ASC,
+    CASE WHEN $3::text = 'lifecycle_status' AND $4::text = 'desc' THEN lifecycle_status END DESC,
+    code ASC
+LIMIT $5 OFFSET $6
 `
 
 type SearchUniqueProductsByAreaPaginatedParams struct {
-	Search string      `json:"search"`
-	Offset int32       `json:"offset"`
-	Limit  int32       `json:"limit"`
-	AreaID pgtype.UUID `json:"area_id"`
+	AreaID    pgtype.UUID `json:"area_id"`
+	Search    string      `json:"search"`
+	SortBy    string      `json:"sort_by"`
+	SortOrder string      `json:"sort_order"`
+	Limit     int32       `json:"limit"`
+	Offset    int32       `json:"offset"`
 }
 
 type SearchUniqueProductsByAreaPaginatedRow struct {
@@ -1091,6 +2078,8 @@ type SearchUniqueProductsByAreaPaginatedRow struct {
 	Description      interface{} `json:"description"`
 	LifecycleStatus  interface{} `json:"lifecycle_status"`
 	ReplacementUrl   interface{} `json:"replacement_url"`
+	Family           interface{} `json:"family"`
+	DatasheetUrl     interface{} `json:"datasheet_url"`
 	Url              interface{} `json:"url"`
 	ManufacturerCode interface{} `json:"manufacturer_code"`
 	SapCode          interface{} `json:"sap_code"`
@@ -1100,12 +2089,7 @@ type SearchUniqueProductsByAreaPaginatedRow struct {
 }
 
 func (q *Queries) SearchUniqueProductsByAreaPaginated(ctx context.Context, arg SearchUniqueProductsByAreaPaginatedParams) ([]SearchUniqueProductsByAreaPaginatedRow, error) {
-	rows, err := q.db.Query(ctx, searchUniqueProductsByAreaPaginated,
-		arg.Search,
-		arg.Offset,
-		arg.Limit,
-		arg.AreaID,
-	)
+	rows, err := q.db.Query(ctx, searchUniqueProductsByAreaPaginated, arg.AreaID, arg.Search, arg.SortBy, arg.SortOrder, arg.Limit, arg.Offset)
 	if err != nil {
 		return nil, err
 	}
@@ -1118,6 +2102,98 @@ func (q *Queries) SearchUniqueProductsByAreaPaginated(ctx context.Context, arg S
 			&i.Description,
 			&i.LifecycleStatus,
 			&i.ReplacementUrl,
+			&i.Family,
+			&i.DatasheetUrl,
+			&i.Url,
+			&i.ManufacturerCode,
+			&i.SapCode,
+			&i.TotalQuantity,
+			&i.CreatedAt,
+			&i.QuantityByArea,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const searchUniqueProductsByAreaRankedPaginated = `-- name: SearchUniqueProductsByAreaRankedPaginated :many
+WITH product_aggregates AS (
+    SELECT
+        p.code,
+        MIN(p.description) as description,
+        MIN(p.lifecycle_status) as lifecycle_status,
+        MIN(p.replacement_url) as replacement_url,
+        MIN(p.family) as family,
+        MIN(p.datasheet_url) as datasheet_url,
+        MIN(p.url) as url,
+        MIN(p.manufacturer_code) as manufacturer_code,
+        MIN(p.sap_code) as sap_code,
+        SUM(COALESCE(p.quantity, 0))::INTEGER as total_quantity,
+        MIN(p.created_at) as created_at,
+        json_agg(
+            json_build_object(
+                'area_id', p2.area_id,
+                'area_name', a.name,
+                'quantity', COALESCE(p2.quantity, 0)
+            ) ORDER BY a.name
+        ) FILTER (WHERE p2.area_id IS NOT NULL) as quantity_by_area,
+        MAX(ts_rank(to_tsvector('simple', p.code || ' ' || COALESCE(p.description, '') || ' ' || COALESCE(p.manufacturer_code, '') || ' ' || COALESCE(p.sap_code, '')), plainto_tsquery('simple', $1::text))) as rank
+    FROM products p
+    INNER JOIN products p2 ON p.code = p2.code AND p2.deleted_at IS NULL
+    LEFT JOIN areas a ON p2.area_id = a.id
+    WHERE p.area_id = $2 AND p.deleted_at IS NULL
+      AND to_tsvector('simple', p.code || ' ' || COALESCE(p.description, '') || ' ' || COALESCE(p.manufacturer_code, '') || ' ' || COALESCE(p.sap_code, '')) @@ plainto_tsquery('simple', $1::text)
+    GROUP BY p.code
+)
+SELECT code, description, lifecycle_status, replacement_url, family, datasheet_url, url, manufacturer_code, sap_code, total_quantity, created_at, quantity_by_area FROM product_aggregates
+ORDER BY rank DESC, code
+LIMIT $3 OFFSET $4
+`
+
+type SearchUniqueProductsByAreaRankedPaginatedParams struct {
+	Search string      `json:"search"`
+	AreaID pgtype.UUID `json:"area_id"`
+	Limit  int32       `json:"limit"`
+	Offset int32       `json:"offset"`
+}
+
+type SearchUniqueProductsByAreaRankedPaginatedRow struct {
+	Code             string      `json:"code"`
+	Description      interface{} `json:"description"`
+	LifecycleStatus  interface{} `json:"lifecycle_status"`
+	ReplacementUrl   interface{} `json:"replacement_url"`
+	Family           interface{} `json:"family"`
+	DatasheetUrl     interface{} `json:"datasheet_url"`
+	Url              interface{} `json:"url"`
+	ManufacturerCode interface{} `json:"manufacturer_code"`
+	SapCode          interface{} `json:"sap_code"`
+	TotalQuantity    int32       `json:"total_quantity"`
+	CreatedAt        interface{} `json:"created_at"`
+	QuantityByArea   []byte      `json:"quantity_by_area"`
+}
+
+// Busca textual (?fuzzy=true) na area, ordenada por relevancia
+func (q *Queries) SearchUniqueProductsByAreaRankedPaginated(ctx context.Context, arg SearchUniqueProductsByAreaRankedPaginatedParams) ([]SearchUniqueProductsByAreaRankedPaginatedRow, error) {
+	rows, err := q.db.Query(ctx, searchUniqueProductsByAreaRankedPaginated, arg.Search, arg.AreaID, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SearchUniqueProductsByAreaRankedPaginatedRow
+	for rows.Next() {
+		var i SearchUniqueProductsByAreaRankedPaginatedRow
+		if err := rows.Scan(
+			&i.Code,
+			&i.Description,
+			&i.LifecycleStatus,
+			&i.ReplacementUrl,
+			&i.Family,
+			&i.DatasheetUrl,
 			&i.Url,
 			&i.ManufacturerCode,
 			&i.SapCode,
@@ -1142,6 +2218,8 @@ WITH product_aggregates AS (
         MIN(p.description) as description,
         MIN(p.lifecycle_status) as lifecycle_status,
         MIN(p.replacement_url) as replacement_url,
+        MIN(p.family) as family,
+        MIN(p.datasheet_url) as datasheet_url,
         MIN(p.url) as url,
         MIN(p.manufacturer_code) as manufacturer_code,
         MIN(p.sap_code) as sap_code,
@@ -1156,22 +2234,36 @@ WITH product_aggregates AS (
         ) FILTER (WHERE p.area_id IS NOT NULL) as quantity_by_area
     FROM products p
     LEFT JOIN areas a ON p.area_id = a.id
+    WHERE p.deleted_at IS NULL
     GROUP BY p.code
 )
-SELECT code, description, lifecycle_status, replacement_url, url, manufacturer_code, sap_code, total_quantity, created_at, quantity_by_area FROM product_aggregates
+SELECT code, description, lifecycle_status, replacement_url, family, datasheet_url, url, manufacturer_code, sap_code, total_quantity, created_at, quantity_by_area FROM product_aggregates
 WHERE code ILIKE '%' || $1::text || '%'
    OR description ILIKE '%' || $1::text || '%'
    OR sap_code ILIKE '%' || $1::text || '%'
    OR manufacturer_code ILIKE '%' || $1::text || '%'
    OR lifecycle_status ILIKE '%' || $1::text || '%'
-ORDER BY lifecycle_status DESC, created_at DESC
-LIMIT $3 OFFSET $2
+ORDER BY
+    CASE WHEN $2::text = 'code' AND $3::text = 'asc' THEN code END ASC,
+    CASE WHEN $2::text = 'code' AND $3::text = 'desc' THEN code END DESC,
+    CASE WHEN $2::text = 'description' AND $3::text = 'asc' THEN description END ASC,
+    CASE WHEN $2::text = 'description' AND $3::text = 'desc' THEN description END DESC,
+    CASE WHEN $2::text = 'created_at' AND $3::text = 'asc' THEN created_at END ASC,
+    CASE WHEN $2::text = 'created_at' AND $3::text = 'desc' THEN created_at END DESC,
+    CASE WHEN $2::text = 'total_quantity' AND $3::text = 'asc' THEN total_quantity END ASC,
+    CASE WHEN $2::text = 'total_quantity' AND $3::text = 'desc' THEN total_quantity END DESC,
+    CASE WHEN $2::text = 'lifecycle_status' AND $3::text = 'asc' THEN lifecycle_status END ASC,
+    CASE WHEN $2::text = 'lifecycle_status' AND $3::text = 'desc' THEN lifecycle_status END DESC,
+    code ASC
+LIMIT $4 OFFSET $5
 `
 
 type SearchUniqueProductsPaginatedParams struct {
-	Search string `json:"search"`
-	Offset int32  `json:"offset"`
-	Limit  int32  `json:"limit"`
+	Search    string `json:"search"`
+	SortBy    string `json:"sort_by"`
+	SortOrder string `json:"sort_order"`
+	Limit     int32  `json:"limit"`
+	Offset    int32  `json:"offset"`
 }
 
 type SearchUniqueProductsPaginatedRow struct {
@@ -1179,6 +2271,8 @@ type SearchUniqueProductsPaginatedRow struct {
 	Description      interface{} `json:"description"`
 	LifecycleStatus  interface{} `json:"lifecycle_status"`
 	ReplacementUrl   interface{} `json:"replacement_url"`
+	Family           interface{} `json:"family"`
+	DatasheetUrl     interface{} `json:"datasheet_url"`
 	Url              interface{} `json:"url"`
 	ManufacturerCode interface{} `json:"manufacturer_code"`
 	SapCode          interface{} `json:"sap_code"`
@@ -1188,7 +2282,7 @@ type SearchUniqueProductsPaginatedRow struct {
 }
 
 func (q *Queries) SearchUniqueProductsPaginated(ctx context.Context, arg SearchUniqueProductsPaginatedParams) ([]SearchUniqueProductsPaginatedRow, error) {
-	rows, err := q.db.Query(ctx, searchUniqueProductsPaginated, arg.Search, arg.Offset, arg.Limit)
+	rows, err := q.db.Query(ctx, searchUniqueProductsPaginated, arg.Search, arg.SortBy, arg.SortOrder, arg.Limit, arg.Offset)
 	if err != nil {
 		return nil, err
 	}
@@ -1201,6 +2295,96 @@ func (q *Queries) SearchUniqueProductsPaginated(ctx context.Context, arg SearchU
 			&i.Description,
 			&i.LifecycleStatus,
 			&i.ReplacementUrl,
+			&i.Family,
+			&i.DatasheetUrl,
+			&i.Url,
+			&i.ManufacturerCode,
+			&i.SapCode,
+			&i.TotalQuantity,
+			&i.CreatedAt,
+			&i.QuantityByArea,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const searchUniqueProductsRankedPaginated = `-- name: SearchUniqueProductsRankedPaginated :many
+WITH product_aggregates AS (
+    SELECT
+        p.code,
+        MIN(p.description) as description,
+        MIN(p.lifecycle_status) as lifecycle_status,
+        MIN(p.replacement_url) as replacement_url,
+        MIN(p.family) as family,
+        MIN(p.datasheet_url) as datasheet_url,
+        MIN(p.url) as url,
+        MIN(p.manufacturer_code) as manufacturer_code,
+        MIN(p.sap_code) as sap_code,
+        SUM(COALESCE(p.quantity, 0))::INTEGER as total_quantity,
+        MIN(p.created_at) as created_at,
+        json_agg(
+            json_build_object(
+                'area_id', p.area_id,
+                'area_name', a.name,
+                'quantity', COALESCE(p.quantity, 0)
+            ) ORDER BY a.name
+        ) FILTER (WHERE p.area_id IS NOT NULL) as quantity_by_area,
+        MAX(ts_rank(to_tsvector('simple', p.code || ' ' || COALESCE(p.description, '') || ' ' || COALESCE(p.manufacturer_code, '') || ' ' || COALESCE(p.sap_code, '')), plainto_tsquery('simple', $1::text))) as rank
+    FROM products p
+    LEFT JOIN areas a ON p.area_id = a.id
+    WHERE p.deleted_at IS NULL
+      AND to_tsvector('simple', p.code || ' ' || COALESCE(p.description, '') || ' ' || COALESCE(p.manufacturer_code, '') || ' ' || COALESCE(p.sap_code, '')) @@ plainto_tsquery('simple', $1::text)
+    GROUP BY p.code
+)
+SELECT code, description, lifecycle_status, replacement_url, family, datasheet_url, url, manufacturer_code, sap_code, total_quantity, created_at, quantity_by_area FROM product_aggregates
+ORDER BY rank DESC, code
+LIMIT $2 OFFSET $3
+`
+
+type SearchUniqueProductsRankedPaginatedParams struct {
+	Search string `json:"search"`
+	Limit  int32  `json:"limit"`
+	Offset int32  `json:"offset"`
+}
+
+type SearchUniqueProductsRankedPaginatedRow struct {
+	Code             string      `json:"code"`
+	Description      interface{} `json:"description"`
+	LifecycleStatus  interface{} `json:"lifecycle_status"`
+	ReplacementUrl   interface{} `json:"replacement_url"`
+	Family           interface{} `json:"family"`
+	DatasheetUrl     interface{} `json:"datasheet_url"`
+	Url              interface{} `json:"url"`
+	ManufacturerCode interface{} `json:"manufacturer_code"`
+	SapCode          interface{} `json:"sap_code"`
+	TotalQuantity    int32       `json:"total_quantity"`
+	CreatedAt        interface{} `json:"created_at"`
+	QuantityByArea   []byte      `json:"quantity_by_area"`
+}
+
+// Busca textual (?fuzzy=true) ordenada por relevancia; a expressao do tsvector e a do idx_products_search
+func (q *Queries) SearchUniqueProductsRankedPaginated(ctx context.Context, arg SearchUniqueProductsRankedPaginatedParams) ([]SearchUniqueProductsRankedPaginatedRow, error) {
+	rows, err := q.db.Query(ctx, searchUniqueProductsRankedPaginated, arg.Search, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SearchUniqueProductsRankedPaginatedRow
+	for rows.Next() {
+		var i SearchUniqueProductsRankedPaginatedRow
+		if err := rows.Scan(
+			&i.Code,
+			&i.Description,
+			&i.LifecycleStatus,
+			&i.ReplacementUrl,
+			&i.Family,
+			&i.DatasheetUrl,
 			&i.Url,
 			&i.ManufacturerCode,
 			&i.SapCode,
@@ -1218,6 +2402,27 @@ func (q *Queries) SearchUniqueProductsPaginated(ctx context.Context, arg SearchU
 	return items, nil
 }
 
+const setProductLifecycleStatus = `-- name: SetProductLifecycleStatus :execrows
+UPDATE products
+SET lifecycle_status = $1,
+    updated_at = NOW()
+WHERE code = $2
+`
+
+type SetProductLifecycleStatusParams struct {
+	LifecycleStatus pgtype.Text `json:"lifecycle_status"`
+	Code            string      `json:"code"`
+}
+
+// Correcao manual do status; ao contrario de UpdateProductLifecycleStatus, sempre sobrescreve
+func (q *Queries) SetProductLifecycleStatus(ctx context.Context, arg SetProductLifecycleStatusParams) (int64, error) {
+	result, err := q.db.Exec(ctx, setProductLifecycleStatus, arg.LifecycleStatus, arg.Code)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const updateProduct = `-- name: UpdateProduct :one
 UPDATE products
 SET
@@ -1231,9 +2436,10 @@ SET
     observations = COALESCE($8, observations),
     min_quantity = COALESCE($9, min_quantity),
     max_quantity = COALESCE($10, max_quantity),
-    inventory_status = COALESCE($11, inventory_status)
-WHERE id = $12
-RETURNING id, code, url, area_id, description, manufacturer_code, quantity, replacement_url, sap_code, observations, min_quantity, max_quantity, inventory_status, lifecycle_status, created_at
+    inventory_status = COALESCE($11, inventory_status),
+    updated_at = NOW()
+WHERE id = $12 AND deleted_at IS NULL
+RETURNING id, code, url, area_id, description, manufacturer_code, quantity, replacement_url, sap_code, observations, min_quantity, max_quantity, inventory_status, lifecycle_status, created_at, updated_at, family, datasheet_url, deleted_at
 `
 
 type UpdateProductParams struct {
@@ -1283,16 +2489,46 @@ func (q *Queries) UpdateProduct(ctx context.Context, arg UpdateProductParams) (P
 		&i.InventoryStatus,
 		&i.LifecycleStatus,
 		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Family,
+		&i.DatasheetUrl,
+		&i.DeletedAt,
 	)
 	return i, err
 }
 
+const updateProductDetails = `-- name: UpdateProductDetails :exec
+UPDATE products
+SET
+    family = COALESCE($1, family),
+    datasheet_url = COALESCE($2, datasheet_url),
+    updated_at = NOW()
+WHERE code = $3
+  AND (family IS DISTINCT FROM COALESCE($1, family)
+   OR datasheet_url IS DISTINCT FROM COALESCE($2, datasheet_url))
+`
+
+type UpdateProductDetailsParams struct {
+	Family       pgtype.Text `json:"family"`
+	DatasheetUrl pgtype.Text `json:"datasheet_url"`
+	Code         string      `json:"code"`
+}
+
+// Family and datasheet read from the product page; empty values keep what was stored
+func (q *Queries) UpdateProductDetails(ctx context.Context, arg UpdateProductDetailsParams) error {
+	_, err := q.db.Exec(ctx, updateProductDetails, arg.Family, arg.DatasheetUrl, arg.Code)
+	return err
+}
+
 const updateProductLifecycleStatus = `-- name: UpdateProductLifecycleStatus :exec
 UPDATE products
 SET
     lifecycle_status = COALESCE($1, lifecycle_status),
-    replacement_url = COALESCE($2, replacement_url)
+    replacement_url = COALESCE($2, replacement_url),
+    updated_at = NOW()
 WHERE code = $3
+  AND (lifecycle_status IS DISTINCT FROM COALESCE($1, lifecycle_status)
+   OR replacement_url IS DISTINCT FROM COALESCE($2, replacement_url))
 `
 
 type UpdateProductLifecycleStatusParams struct {
@@ -1301,6 +2537,7 @@ type UpdateProductLifecycleStatusParams struct {
 	Code            string      `json:"code"`
 }
 
+// Only touches rows that actually change, so updated_at reflects real changes and not every crawl
 func (q *Queries) UpdateProductLifecycleStatus(ctx context.Context, arg UpdateProductLifecycleStatusParams) error {
 	_, err := q.db.Exec(ctx, updateProductLifecycleStatus, arg.LifecycleStatus, arg.ReplacementUrl, arg.Code)
 	return err
@@ -11,6 +11,8 @@ import (
 )
 
 type Querier interface {
+	// Descarta o HTML dos snapshots antigos; status e descricao continuam no historico
+	ClearSnapshotRawHTML(ctx context.Context, collectedBefore pgtype.Timestamp) (int64, error)
 	CountProductSnapshots(ctx context.Context, productID pgtype.UUID) (int64, error)
 	CountProducts(ctx context.Context) (int64, error)
 	CountProductsByArea(ctx context.Context, areaID pgtype.UUID) (int64, error)
@@ -19,47 +21,116 @@ type Querier interface {
 	CountProductsBySearch(ctx context.Context, search string) (int64, error)
 	CountProductsInArea(ctx context.Context, areaID pgtype.UUID) (int64, error)
 	CountUniqueProducts(ctx context.Context) (int64, error)
+	CountUniqueProductsByAreaAndFullText(ctx context.Context, arg CountUniqueProductsByAreaAndFullTextParams) (int64, error)
 	CountUniqueProductsByAreaAndSearch(ctx context.Context, arg CountUniqueProductsByAreaAndSearchParams) (int64, error)
+	CountUniqueProductsByFullText(ctx context.Context, search string) (int64, error)
 	CountUniqueProductsBySearch(ctx context.Context, search string) (int64, error)
+	CountUniqueProductsByStatus(ctx context.Context, arg CountUniqueProductsByStatusParams) (int64, error)
 	CountUniqueProductsInArea(ctx context.Context, areaID pgtype.UUID) (int64, error)
 	CreateArea(ctx context.Context, arg CreateAreaParams) (Area, error)
+	CreateCollectionRun(ctx context.Context, arg CreateCollectionRunParams) error
 	CreateProduct(ctx context.Context, arg CreateProductParams) (Product, error)
 	CreateSnapshot(ctx context.Context, arg CreateSnapshotParams) (ProductSnapshot, error)
 	CreateUser(ctx context.Context, arg CreateUserParams) (User, error)
 	DeleteArea(ctx context.Context, id pgtype.UUID) error
+	DeleteCrawlFailure(ctx context.Context, code string) error
+	// Removes the changes already sent; the ones queued while the digest was being sent stay
+	DeleteDigestChangesThrough(ctx context.Context, id int64) (int64, error)
+	DeleteLifecycleAlertSnooze(ctx context.Context, code string) (int64, error)
+	DeleteLifecycleExpectation(ctx context.Context, code string) (int64, error)
+	DeleteLifecycleStatusOverride(ctx context.Context, code string) (int64, error)
 	DeleteOldSnapshots(ctx context.Context, arg DeleteOldSnapshotsParams) error
 	DeleteProduct(ctx context.Context, id pgtype.UUID) error
+	DeleteProductsByIDs(ctx context.Context, ids []pgtype.UUID) (int64, error)
+	DeleteProductsReturningIDs(ctx context.Context, ids []pgtype.UUID) ([]pgtype.UUID, error)
+	// Mantem os keep_per_product snapshots mais recentes de cada produto. Dos mais antigos so apaga os
+	// que repetem status e descricao da coleta anterior, assim as transicoes continuam no historico.
+	DeleteRedundantSnapshots(ctx context.Context, keepPerProduct int32) (int64, error)
 	DeleteUser(ctx context.Context, id pgtype.UUID) error
+	FindActiveLifecycleAlertSnooze(ctx context.Context, code string) (LifecycleAlertSnooze, error)
 	FindAreaByID(ctx context.Context, id pgtype.UUID) (Area, error)
 	FindAreaByName(ctx context.Context, name string) (Area, error)
 	FindByEmail(ctx context.Context, email string) (User, error)
 	FindByID(ctx context.Context, id pgtype.UUID) (User, error)
+	FindLifecycleStatusOverride(ctx context.Context, code string) (LifecycleStatusOverride, error)
 	FindProductByCode(ctx context.Context, code string) (FindProductByCodeRow, error)
 	FindProductByCodeAndArea(ctx context.Context, arg FindProductByCodeAndAreaParams) (FindProductByCodeAndAreaRow, error)
 	FindProductByID(ctx context.Context, id pgtype.UUID) (FindProductByIDRow, error)
+	FindProductsByCodes(ctx context.Context, codes []string) ([]FindProductsByCodesRow, error)
+	FindProductsByIDs(ctx context.Context, ids []pgtype.UUID) ([]FindProductsByIDsRow, error)
 	FindSnapshotByID(ctx context.Context, id pgtype.UUID) (ProductSnapshot, error)
+	// Totais do inventario por codigo; com area_id, apenas os produtos da area (mesmo filtro da listagem)
+	GetInventorySummary(ctx context.Context, areaID pgtype.UUID) (GetInventorySummaryRow, error)
+	GetLatestCollectionRun(ctx context.Context) (CollectionRun, error)
 	GetLatestSnapshot(ctx context.Context, productID pgtype.UUID) (ProductSnapshot, error)
+	GetLatestSnapshotByCode(ctx context.Context, code string) (ProductSnapshot, error)
+	// Versao da exportacao: muda quando algum produto e alterado, criado ou removido, ou uma area e renomeada
+	GetProductsExportVersion(ctx context.Context) (GetProductsExportVersionRow, error)
 	GetSnapshotStatusHistory(ctx context.Context, productID pgtype.UUID) ([]GetSnapshotStatusHistoryRow, error)
+	// Se o codigo ja foi coletado em alguma area (snapshots sao por produto, o codigo se repete entre areas)
+	HasSnapshotForCode(ctx context.Context, code string) (bool, error)
 	ListAllProductsToCollect(ctx context.Context) ([]ListAllProductsToCollectRow, error)
+	// Produtos removidos (arquivados), mantidos com o historico de snapshots para auditoria
+	ListArchivedProducts(ctx context.Context) ([]ListArchivedProductsRow, error)
 	ListAreas(ctx context.Context) ([]Area, error)
+	ListCollectionRuns(ctx context.Context, limit int32) ([]CollectionRun, error)
+	ListCrawlFailures(ctx context.Context) ([]CrawlFailure, error)
+	ListDigestChanges(ctx context.Context) ([]LifecycleDigestChange, error)
+	// Produtos da area de origem cujo codigo ja existe na area de destino
+	ListDuplicateProductsBetweenAreas(ctx context.Context, arg ListDuplicateProductsBetweenAreasParams) ([]ListDuplicateProductsBetweenAreasRow, error)
+	// Totais por area; produtos sem area entram apenas no total geral
+	ListInventoryByArea(ctx context.Context, areaID pgtype.UUID) ([]ListInventoryByAreaRow, error)
+	// Ultimo snapshot de cada codigo entre todas as areas (o status e do codigo); sem o raw_html
+	ListLatestSnapshotsByCodes(ctx context.Context, codes []string) ([]ListLatestSnapshotsByCodesRow, error)
+	ListLatestSnapshotsByProductIDs(ctx context.Context, productIds []pgtype.UUID) ([]ProductSnapshot, error)
+	// Transicoes de status derivadas dos snapshots: cada snapshot cujo status difere do anterior do mesmo produto
+	ListLifecycleExpectations(ctx context.Context) ([]LifecycleExpectation, error)
+	ListLifecycleTransitions(ctx context.Context, arg ListLifecycleTransitionsParams) ([]ListLifecycleTransitionsRow, error)
 	ListProductSnapshots(ctx context.Context, productID pgtype.UUID) ([]ProductSnapshot, error)
 	ListProducts(ctx context.Context) ([]ListProductsRow, error)
+	// Estoque baixo por area: quantidade e minimo sao da area de cada produto; minimo zero nao alerta
+	ListProductsBelowMinimum(ctx context.Context) ([]ListProductsBelowMinimumRow, error)
 	ListProductsByArea(ctx context.Context, areaID pgtype.UUID) ([]ListProductsByAreaRow, error)
 	ListProductsByAreaPaginated(ctx context.Context, arg ListProductsByAreaPaginatedParams) ([]ListProductsByAreaPaginatedRow, error)
+	ListProductsForExport(ctx context.Context, arg ListProductsForExportParams) ([]ListProductsForExportRow, error)
 	ListProductsPaginated(ctx context.Context, arg ListProductsPaginatedParams) ([]ListProductsPaginatedRow, error)
 	ListSnapshotsByDateRange(ctx context.Context, arg ListSnapshotsByDateRangeParams) ([]ProductSnapshot, error)
+	// Codigos sem nenhuma coleta (de qualquer produto com o codigo) desde collected_before
+	ListStaleProductCodesToCollect(ctx context.Context, collectedBefore pgtype.Timestamp) ([]ListStaleProductCodesToCollectRow, error)
 	ListUniqueProductCodesToCollect(ctx context.Context) ([]ListUniqueProductCodesToCollectRow, error)
+	// Codigos sem status de ciclo de vida ou com algum produto nunca coletado (sem snapshot)
+	ListUniqueProductCodesWithUnknownStatus(ctx context.Context) ([]ListUniqueProductCodesWithUnknownStatusRow, error)
 	ListUniqueProductsByAreaPaginated(ctx context.Context, arg ListUniqueProductsByAreaPaginatedParams) ([]ListUniqueProductsByAreaPaginatedRow, error)
+	// Listagem com filtro lifecycle_status; area_id e search sao opcionais. Com area, o p2 traz as
+	// outras areas do codigo para quantity_by_area; sem area, p2 e a propria linha
+	ListUniqueProductsByStatusPaginated(ctx context.Context, arg ListUniqueProductsByStatusPaginatedParams) ([]ListUniqueProductsByStatusPaginatedRow, error)
 	ListUniqueProductsPaginated(ctx context.Context, arg ListUniqueProductsPaginatedParams) ([]ListUniqueProductsPaginatedRow, error)
 	ListUsers(ctx context.Context) ([]User, error)
+	MoveAreaProducts(ctx context.Context, arg MoveAreaProductsParams) (int64, error)
+	QueueDigestChange(ctx context.Context, arg QueueDigestChangeParams) error
+	RebuildProductURLs(ctx context.Context, baseUrl string) (int64, error)
+	RecordCrawlFailure(ctx context.Context, arg RecordCrawlFailureParams) (CrawlFailure, error)
+	RestoreProduct(ctx context.Context, id pgtype.UUID) (int64, error)
 	SearchProducts(ctx context.Context, arg SearchProductsParams) ([]SearchProductsRow, error)
 	SearchProductsByArea(ctx context.Context, arg SearchProductsByAreaParams) ([]SearchProductsByAreaRow, error)
 	SearchUniqueProductsByAreaPaginated(ctx context.Context, arg SearchUniqueProductsByAreaPaginatedParams) ([]SearchUniqueProductsByAreaPaginatedRow, error)
+	// Busca textual (?fuzzy=true) na area, ordenada por relevancia
+	SearchUniqueProductsByAreaRankedPaginated(ctx context.Context, arg SearchUniqueProductsByAreaRankedPaginatedParams) ([]SearchUniqueProductsByAreaRankedPaginatedRow, error)
 	SearchUniqueProductsPaginated(ctx context.Context, arg SearchUniqueProductsPaginatedParams) ([]SearchUniqueProductsPaginatedRow, error)
+	// Busca textual (?fuzzy=true) ordenada por relevancia; a expressao do tsvector e a do idx_products_search
+	SearchUniqueProductsRankedPaginated(ctx context.Context, arg SearchUniqueProductsRankedPaginatedParams) ([]SearchUniqueProductsRankedPaginatedRow, error)
+	// Correcao manual do status; ao contrario de UpdateProductLifecycleStatus, sempre sobrescreve
+	SetProductLifecycleStatus(ctx context.Context, arg SetProductLifecycleStatusParams) (int64, error)
 	UpdateArea(ctx context.Context, arg UpdateAreaParams) (Area, error)
 	UpdateProduct(ctx context.Context, arg UpdateProductParams) (Product, error)
+	// Only touches rows that actually change, so updated_at reflects real changes and not every crawl
+	// Family and datasheet read from the product page; empty values keep what was stored
+	UpdateProductDetails(ctx context.Context, arg UpdateProductDetailsParams) error
 	UpdateProductLifecycleStatus(ctx context.Context, arg UpdateProductLifecycleStatusParams) error
 	UpdateUser(ctx context.Context, arg UpdateUserParams) (User, error)
+	UpsertLifecycleAlertSnooze(ctx context.Context, arg UpsertLifecycleAlertSnoozeParams) (LifecycleAlertSnooze, error)
+	UpsertLifecycleExpectation(ctx context.Context, arg UpsertLifecycleExpectationParams) (LifecycleExpectation, error)
+	UpsertLifecycleStatusOverride(ctx context.Context, arg UpsertLifecycleStatusOverrideParams) (LifecycleStatusOverride, error)
 }
 
 var _ Querier = (*Queries)(nil)
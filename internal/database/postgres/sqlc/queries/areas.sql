@@ -24,4 +24,22 @@ RETURNING *;
 DELETE FROM areas WHERE id = $1;
 
 -- name: CountProductsByArea :one
-SELECT COUNT(*) FROM products WHERE area_id = $1;
+SELECT COUNT(*) FROM products WHERE area_id = $1 AND deleted_at IS NULL;
+
+-- name: ListDuplicateProductsBetweenAreas :many
+-- Produtos da area de origem cujo codigo ja existe na area de destino
+SELECT s.id, s.code
+FROM products s
+WHERE s.area_id = sqlc.arg('source_area_id')
+  AND s.deleted_at IS NULL
+  AND EXISTS (
+    SELECT 1 FROM products t
+    WHERE t.area_id = sqlc.arg('target_area_id') AND t.code = s.code AND t.deleted_at IS NULL
+  )
+ORDER BY s.code;
+
+-- name: MoveAreaProducts :execrows
+UPDATE products
+SET area_id = sqlc.arg('target_area_id'),
+    updated_at = NOW()
+WHERE area_id = sqlc.arg('source_area_id');
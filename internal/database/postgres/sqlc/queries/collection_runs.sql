@@ -0,0 +1,13 @@
+-- name: CreateCollectionRun :exec
+INSERT INTO collection_runs (id, started_at, total, succeeded, failed, uncollected_codes, timed_out, status_changes)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8);
+
+-- name: GetLatestCollectionRun :one
+SELECT * FROM collection_runs
+ORDER BY started_at DESC
+LIMIT 1;
+
+-- name: ListCollectionRuns :many
+SELECT * FROM collection_runs
+ORDER BY started_at DESC
+LIMIT $1;
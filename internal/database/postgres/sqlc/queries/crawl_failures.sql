@@ -0,0 +1,15 @@
+-- name: RecordCrawlFailure :one
+INSERT INTO crawl_failures (code, last_error)
+VALUES ($1, $2)
+ON CONFLICT (code) DO UPDATE
+SET consecutive_failures = crawl_failures.consecutive_failures + 1,
+    last_error = EXCLUDED.last_error,
+    last_failed_at = NOW()
+RETURNING *;
+
+-- name: ListCrawlFailures :many
+SELECT * FROM crawl_failures
+ORDER BY code;
+
+-- name: DeleteCrawlFailure :exec
+DELETE FROM crawl_failures WHERE code = $1;
@@ -0,0 +1,16 @@
+-- name: UpsertLifecycleAlertSnooze :one
+INSERT INTO lifecycle_alert_snoozes (code, snoozed_until, snoozed_status, created_by)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (code) DO UPDATE
+SET snoozed_until = EXCLUDED.snoozed_until,
+    snoozed_status = EXCLUDED.snoozed_status,
+    created_by = EXCLUDED.created_by,
+    created_at = NOW()
+RETURNING *;
+
+-- name: FindActiveLifecycleAlertSnooze :one
+SELECT * FROM lifecycle_alert_snoozes
+WHERE code = $1 AND snoozed_until > NOW();
+
+-- name: DeleteLifecycleAlertSnooze :execrows
+DELETE FROM lifecycle_alert_snoozes WHERE code = $1;
@@ -0,0 +1,11 @@
+-- name: QueueDigestChange :exec
+INSERT INTO lifecycle_digest_changes (code, old_status, new_status)
+VALUES ($1, $2, $3);
+
+-- name: ListDigestChanges :many
+SELECT * FROM lifecycle_digest_changes
+ORDER BY id;
+
+-- name: DeleteDigestChangesThrough :execrows
+-- Removes the changes already sent; the ones queued while the digest was being sent stay
+DELETE FROM lifecycle_digest_changes WHERE id <= $1;
@@ -0,0 +1,17 @@
+-- name: UpsertLifecycleExpectation :one
+INSERT INTO lifecycle_expectations (code, expected_status, expected_eol_date, notes, created_by)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (code) DO UPDATE
+SET expected_status = EXCLUDED.expected_status,
+    expected_eol_date = EXCLUDED.expected_eol_date,
+    notes = EXCLUDED.notes,
+    created_by = EXCLUDED.created_by,
+    created_at = NOW()
+RETURNING *;
+
+-- name: ListLifecycleExpectations :many
+SELECT * FROM lifecycle_expectations
+ORDER BY code;
+
+-- name: DeleteLifecycleExpectation :execrows
+DELETE FROM lifecycle_expectations WHERE code = $1;
@@ -0,0 +1,17 @@
+-- name: UpsertLifecycleStatusOverride :one
+INSERT INTO lifecycle_status_overrides (code, status, previous_status, reason, created_by)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (code) DO UPDATE
+SET status = EXCLUDED.status,
+    previous_status = EXCLUDED.previous_status,
+    reason = EXCLUDED.reason,
+    created_by = EXCLUDED.created_by,
+    created_at = NOW()
+RETURNING *;
+
+-- name: FindLifecycleStatusOverride :one
+SELECT * FROM lifecycle_status_overrides
+WHERE code = $1;
+
+-- name: DeleteLifecycleStatusOverride :execrows
+DELETE FROM lifecycle_status_overrides WHERE code = $1;
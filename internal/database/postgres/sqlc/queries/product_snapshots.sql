@@ -1,6 +1,6 @@
 -- name: CreateSnapshot :one
-INSERT INTO product_snapshots (product_id, description, status, raw_html)
-VALUES ($1, $2, $3, $4)
+INSERT INTO product_snapshots (product_id, description, status, raw_html, source, run_id)
+VALUES ($1, $2, $3, $4, $5, $6)
 RETURNING *;
 
 -- name: FindSnapshotByID :one
@@ -17,6 +17,36 @@ WHERE product_id = $1
 ORDER BY collected_at DESC
 LIMIT 1;
 
+-- name: HasSnapshotForCode :one
+-- Se o codigo ja foi coletado em alguma area (snapshots sao por produto, o codigo se repete entre areas)
+SELECT EXISTS (
+    SELECT 1 FROM product_snapshots s
+    JOIN products p ON p.id = s.product_id
+    WHERE p.code = $1
+) AS has_snapshot;
+
+-- name: GetLatestSnapshotByCode :one
+SELECT s.* FROM product_snapshots s
+JOIN products p ON p.id = s.product_id
+WHERE p.code = $1
+ORDER BY s.collected_at DESC
+LIMIT 1;
+
+-- name: ListLatestSnapshotsByProductIDs :many
+SELECT DISTINCT ON (product_id) *
+FROM product_snapshots
+WHERE product_id = ANY(sqlc.arg('product_ids')::uuid[])
+ORDER BY product_id, collected_at DESC;
+
+-- name: ListLatestSnapshotsByCodes :many
+-- Ultimo snapshot de cada codigo entre todas as areas (o status e do codigo); sem o raw_html
+SELECT DISTINCT ON (p.code) p.code, s.id, s.product_id, s.description, s.status, s.collected_at, s.source, s.run_id
+FROM product_snapshots s
+JOIN products p ON p.id = s.product_id
+WHERE p.code = ANY(sqlc.arg('codes')::text[])
+AND p.deleted_at IS NULL
+ORDER BY p.code, s.collected_at DESC;
+
 -- name: ListSnapshotsByDateRange :many
 SELECT * FROM product_snapshots
 WHERE product_id = $1
@@ -28,6 +58,30 @@ DELETE FROM product_snapshots
 WHERE product_id = $1
 AND collected_at < $2;
 
+-- name: ClearSnapshotRawHTML :execrows
+-- Descarta o HTML dos snapshots antigos; status e descricao continuam no historico
+UPDATE product_snapshots
+SET raw_html = NULL
+WHERE collected_at < sqlc.arg('collected_before')::timestamp
+AND raw_html IS NOT NULL;
+
+-- name: DeleteRedundantSnapshots :execrows
+-- Mantem os keep_per_product snapshots mais recentes de cada produto. Dos mais antigos so apaga os
+-- que repetem status e descricao da coleta anterior, assim as transicoes continuam no historico.
+DELETE FROM product_snapshots
+WHERE id IN (
+    SELECT ranked.id FROM (
+        SELECT id,
+               ROW_NUMBER() OVER (PARTITION BY product_id ORDER BY collected_at DESC) AS position,
+               status IS NOT DISTINCT FROM LAG(status) OVER previous
+                   AND description = LAG(description) OVER previous AS unchanged
+        FROM product_snapshots
+        WINDOW previous AS (PARTITION BY product_id ORDER BY collected_at)
+    ) ranked
+    WHERE ranked.position > sqlc.arg('keep_per_product')::int
+    AND ranked.unchanged
+);
+
 -- name: CountProductSnapshots :one
 SELECT COUNT(*) FROM product_snapshots WHERE product_id = $1;
 
@@ -36,3 +90,22 @@ SELECT id, status, collected_at
 FROM product_snapshots
 WHERE product_id = $1
 ORDER BY collected_at ASC;
+
+-- name: ListLifecycleTransitions :many
+-- Transicoes de status derivadas dos snapshots: cada snapshot cujo status difere do anterior do mesmo produto
+SELECT t.code, t.description, a.name AS area_name, t.old_status::text AS old_status, t.new_status, t.changed_at, t.replacement_url
+FROM (
+    SELECT p.code, p.description, p.area_id, p.replacement_url,
+           s.status AS new_status,
+           s.collected_at AS changed_at,
+           LAG(s.status) OVER (PARTITION BY s.product_id ORDER BY s.collected_at) AS old_status
+    FROM product_snapshots s
+    JOIN products p ON p.id = s.product_id
+    WHERE s.status IS NOT NULL
+    AND s.collected_at < sqlc.arg('to_date')::timestamp
+) t
+LEFT JOIN areas a ON a.id = t.area_id
+WHERE t.old_status IS NOT NULL
+AND t.old_status <> t.new_status
+AND t.changed_at >= sqlc.arg('from_date')::timestamp
+ORDER BY t.changed_at ASC, t.code ASC;
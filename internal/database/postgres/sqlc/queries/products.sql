@@ -10,25 +10,43 @@ RETURNING *;
 SELECT p.*, a.name as area_name
 FROM products p
 LEFT JOIN areas a ON p.area_id = a.id
-WHERE p.id = $1;
+WHERE p.id = $1 AND p.deleted_at IS NULL;
 
 -- name: FindProductByCode :one
 SELECT p.*, a.name as area_name
 FROM products p
 LEFT JOIN areas a ON p.area_id = a.id
-WHERE p.code = $1;
+WHERE p.code = $1 AND p.deleted_at IS NULL;
+
+-- name: FindProductsByIDs :many
+SELECT p.*, a.name as area_name
+FROM products p
+LEFT JOIN areas a ON p.area_id = a.id
+WHERE p.id = ANY(sqlc.arg('ids')::uuid[])
+  AND p.deleted_at IS NULL
+ORDER BY p.code, p.created_at ASC;
+
+-- name: FindProductsByCodes :many
+SELECT p.*, a.name as area_name
+FROM products p
+LEFT JOIN areas a ON p.area_id = a.id
+WHERE p.code = ANY(sqlc.arg('codes')::text[])
+  AND p.deleted_at IS NULL
+ORDER BY p.code, p.created_at ASC;
 
 -- name: FindProductByCodeAndArea :one
 SELECT p.*, a.name as area_name
 FROM products p
 LEFT JOIN areas a ON p.area_id = a.id
-WHERE p.code = $1 AND (p.area_id = $2 OR ($2 IS NULL AND p.area_id IS NULL));
+WHERE p.code = $1 AND (p.area_id = $2 OR ($2 IS NULL AND p.area_id IS NULL))
+  AND p.deleted_at IS NULL;
 
 -- name: ListProducts :many
 SELECT * FROM (
     SELECT p.*, a.name as area_name
     FROM products p
     LEFT JOIN areas a ON p.area_id = a.id
+    WHERE p.deleted_at IS NULL
     ORDER BY p.code, p.created_at DESC
 ) sub
 ORDER BY lifecycle_status DESC, created_at DESC;
@@ -38,6 +56,7 @@ SELECT * FROM (
     SELECT p.*, a.name as area_name
     FROM products p
     LEFT JOIN areas a ON p.area_id = a.id
+    WHERE p.deleted_at IS NULL
     ORDER BY p.code, p.created_at DESC
 ) sub
 ORDER BY lifecycle_status DESC, created_at DESC
@@ -47,14 +66,14 @@ LIMIT $1 OFFSET $2;
 SELECT p.*, a.name as area_name
 FROM products p
 LEFT JOIN areas a ON p.area_id = a.id
-WHERE p.area_id = $1
+WHERE p.area_id = $1 AND p.deleted_at IS NULL
 ORDER BY p.lifecycle_status DESC, p.created_at DESC;
 
 -- name: ListProductsByAreaPaginated :many
 SELECT p.*, a.name as area_name
 FROM products p
 LEFT JOIN areas a ON p.area_id = a.id
-WHERE p.area_id = $1
+WHERE p.area_id = $1 AND p.deleted_at IS NULL
 ORDER BY p.lifecycle_status DESC, p.created_at DESC
 LIMIT $2 OFFSET $3;
 
@@ -62,11 +81,12 @@ LIMIT $2 OFFSET $3;
 SELECT p.*, a.name as area_name
 FROM products p
 LEFT JOIN areas a ON p.area_id = a.id
-WHERE p.code ILIKE '%' || sqlc.arg('search')::text || '%'
+WHERE p.deleted_at IS NULL
+  AND (p.code ILIKE '%' || sqlc.arg('search')::text || '%'
    OR p.description ILIKE '%' || sqlc.arg('search')::text || '%'
    OR p.sap_code ILIKE '%' || sqlc.arg('search')::text || '%'
    OR p.manufacturer_code ILIKE '%' || sqlc.arg('search')::text || '%'
-   OR p.lifecycle_status ILIKE '%' || sqlc.arg('search')::text || '%'
+   OR p.lifecycle_status ILIKE '%' || sqlc.arg('search')::text || '%')
 ORDER BY p.lifecycle_status DESC, p.created_at DESC
 LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
 
@@ -75,6 +95,7 @@ SELECT p.*, a.name as area_name
 FROM products p
 LEFT JOIN areas a ON p.area_id = a.id
 WHERE p.area_id = sqlc.arg('area_id')
+  AND p.deleted_at IS NULL
   AND (p.code ILIKE '%' || sqlc.arg('search')::text || '%'
    OR p.description ILIKE '%' || sqlc.arg('search')::text || '%'
    OR p.sap_code ILIKE '%' || sqlc.arg('search')::text || '%'
@@ -84,24 +105,26 @@ ORDER BY p.lifecycle_status DESC, p.created_at DESC
 LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
 
 -- name: CountProducts :one
-SELECT COUNT(*) FROM products;
+SELECT COUNT(*) FROM products WHERE deleted_at IS NULL;
 
 -- name: CountProductsInArea :one
-SELECT COUNT(*) FROM products WHERE area_id = $1;
+SELECT COUNT(*) FROM products WHERE area_id = $1 AND deleted_at IS NULL;
 
 -- name: CountProductsBySearch :one
 SELECT COUNT(*)
 FROM products p
-WHERE p.code ILIKE '%' || sqlc.arg('search')::text || '%'
+WHERE p.deleted_at IS NULL
+  AND (p.code ILIKE '%' || sqlc.arg('search')::text || '%'
    OR p.description ILIKE '%' || sqlc.arg('search')::text || '%'
    OR p.sap_code ILIKE '%' || sqlc.arg('search')::text || '%'
    OR p.manufacturer_code ILIKE '%' || sqlc.arg('search')::text || '%'
-   OR p.lifecycle_status ILIKE '%' || sqlc.arg('search')::text || '%';
+   OR p.lifecycle_status ILIKE '%' || sqlc.arg('search')::text || '%');
 
 -- name: CountProductsByAreaAndSearch :one
 SELECT COUNT(*)
 FROM products p
 WHERE p.area_id = sqlc.arg('area_id')
+  AND p.deleted_at IS NULL
   AND (p.code ILIKE '%' || sqlc.arg('search')::text || '%'
    OR p.description ILIKE '%' || sqlc.arg('search')::text || '%'
    OR p.sap_code ILIKE '%' || sqlc.arg('search')::text || '%'
@@ -109,7 +132,78 @@ WHERE p.area_id = sqlc.arg('area_id')
    OR p.lifecycle_status ILIKE '%' || sqlc.arg('search')::text || '%');
 
 -- name: DeleteProduct :exec
-DELETE FROM products WHERE id = $1;
+UPDATE products
+SET deleted_at = NOW(),
+    updated_at = NOW()
+WHERE id = $1 AND deleted_at IS NULL;
+
+-- name: RestoreProduct :execrows
+UPDATE products
+SET deleted_at = NULL,
+    updated_at = NOW()
+WHERE id = $1 AND deleted_at IS NOT NULL;
+
+-- name: ListArchivedProducts :many
+-- Produtos removidos (arquivados), mantidos com o historico de snapshots para auditoria
+SELECT p.*, a.name as area_name
+FROM products p
+LEFT JOIN areas a ON p.area_id = a.id
+WHERE p.deleted_at IS NOT NULL
+ORDER BY p.deleted_at DESC;
+
+-- name: ListProductsBelowMinimum :many
+-- Estoque baixo por area: quantidade e minimo sao da area de cada produto; minimo zero nao alerta
+SELECT p.*, a.name as area_name
+FROM products p
+LEFT JOIN areas a ON p.area_id = a.id
+WHERE p.deleted_at IS NULL
+AND p.min_quantity > 0
+AND COALESCE(p.quantity, 0) < p.min_quantity
+ORDER BY a.name, p.code;
+
+-- name: GetInventorySummary :one
+-- Totais do inventario por codigo; com area_id, apenas os produtos da area (mesmo filtro da listagem)
+SELECT
+    COUNT(DISTINCT code) AS total_products,
+    COALESCE(SUM(COALESCE(quantity, 0)), 0)::BIGINT AS total_quantity,
+    COUNT(DISTINCT code) FILTER (WHERE lifecycle_status = 'Active Product') AS active_count,
+    COUNT(DISTINCT code) FILTER (
+        WHERE lifecycle_status IN (
+            'Prod. Cancellation',
+            'End Prod.Lifecycl.',
+            'Prod. Discont.'
+        )
+    ) AS discontinued_count,
+    COUNT(DISTINCT code) FILTER (WHERE lifecycle_status = 'Phase Out Announce') AS phase_out_count
+FROM products
+WHERE deleted_at IS NULL
+AND (sqlc.narg('area_id')::uuid IS NULL OR area_id = sqlc.narg('area_id')::uuid);
+
+-- name: ListInventoryByArea :many
+-- Totais por area; produtos sem area entram apenas no total geral
+SELECT
+    a.id AS area_id,
+    a.name AS area_name,
+    COUNT(DISTINCT p.code) AS total_products,
+    COALESCE(SUM(COALESCE(p.quantity, 0)), 0)::BIGINT AS total_quantity
+FROM areas a
+JOIN products p ON p.area_id = a.id AND p.deleted_at IS NULL
+WHERE sqlc.narg('area_id')::uuid IS NULL OR a.id = sqlc.narg('area_id')::uuid
+GROUP BY a.id, a.name
+ORDER BY a.name;
+
+-- name: DeleteProductsByIDs :execrows
+UPDATE products
+SET deleted_at = NOW(),
+    updated_at = NOW()
+WHERE id = ANY(sqlc.arg('ids')::uuid[]) AND deleted_at IS NULL;
+
+-- name: DeleteProductsReturningIDs :many
+UPDATE products
+SET deleted_at = NOW(),
+    updated_at = NOW()
+WHERE id = ANY(sqlc.arg('ids')::uuid[]) AND deleted_at IS NULL
+RETURNING id;
 
 -- name: UpdateProduct :one
 UPDATE products
@@ -124,20 +218,75 @@ SET
     observations = COALESCE(sqlc.narg('observations'), observations),
     min_quantity = COALESCE(sqlc.narg('min_quantity'), min_quantity),
     max_quantity = COALESCE(sqlc.narg('max_quantity'), max_quantity),
-    inventory_status = COALESCE(sqlc.narg('inventory_status'), inventory_status)
-WHERE id = sqlc.arg('id')
+    inventory_status = COALESCE(sqlc.narg('inventory_status'), inventory_status),
+    updated_at = NOW()
+WHERE id = sqlc.arg('id') AND deleted_at IS NULL
 RETURNING *;
 
+-- name: ListProductsForExport :many
+SELECT p.*, a.name as area_name,
+    s.id as snapshot_id,
+    s.description as snapshot_description,
+    s.status as snapshot_status,
+    s.collected_at as snapshot_collected_at
+FROM products p
+LEFT JOIN areas a ON p.area_id = a.id
+LEFT JOIN LATERAL (
+    SELECT ps.id, ps.description, ps.status, ps.collected_at
+    FROM product_snapshots ps
+    WHERE ps.product_id = p.id
+    ORDER BY ps.collected_at DESC
+    LIMIT 1
+) s ON true
+WHERE p.deleted_at IS NULL
+  AND (sqlc.narg('area_id')::uuid IS NULL OR p.area_id = sqlc.narg('area_id')::uuid)
+  AND (sqlc.arg('search')::text = ''
+   OR p.code ILIKE '%' || sqlc.arg('search')::text || '%'
+   OR p.description ILIKE '%' || sqlc.arg('search')::text || '%'
+   OR p.sap_code ILIKE '%' || sqlc.arg('search')::text || '%')
+  AND p.id > sqlc.arg('after_id')
+ORDER BY p.id
+LIMIT sqlc.arg('limit');
+
+-- name: RebuildProductURLs :execrows
+UPDATE products
+SET url = sqlc.arg('base_url')::text || '/' || code,
+    updated_at = NOW()
+WHERE url <> sqlc.arg('base_url')::text || '/' || code;
+
 -- name: ListAllProductsToCollect :many
 SELECT p.id, p.code, p.url, p.created_at
 FROM products p
+WHERE p.deleted_at IS NULL
 ORDER BY p.created_at ASC;
 
+-- name: UpdateProductDetails :exec
+-- Family and datasheet read from the product page; empty values keep what was stored
+UPDATE products
+SET
+    family = COALESCE(sqlc.narg('family'), family),
+    datasheet_url = COALESCE(sqlc.narg('datasheet_url'), datasheet_url),
+    updated_at = NOW()
+WHERE code = sqlc.arg('code')
+  AND (family IS DISTINCT FROM COALESCE(sqlc.narg('family'), family)
+   OR datasheet_url IS DISTINCT FROM COALESCE(sqlc.narg('datasheet_url'), datasheet_url));
+
 -- name: UpdateProductLifecycleStatus :exec
+-- Only touches rows that actually change, so updated_at reflects real changes and not every crawl
 UPDATE products
 SET
     lifecycle_status = COALESCE(sqlc.narg('lifecycle_status'), lifecycle_status),
-    replacement_url = COALESCE(sqlc.narg('replacement_url'), replacement_url)
+    replacement_url = COALESCE(sqlc.narg('replacement_url'), replacement_url),
+    updated_at = NOW()
+WHERE code = sqlc.arg('code')
+  AND (lifecycle_status IS DISTINCT FROM COALESCE(sqlc.narg('lifecycle_status'), lifecycle_status)
+   OR replacement_url IS DISTINCT FROM COALESCE(sqlc.narg('replacement_url'), replacement_url));
+
+-- name: SetProductLifecycleStatus :execrows
+-- Correcao manual do status; ao contrario de UpdateProductLifecycleStatus, sempre sobrescreve
+UPDATE products
+SET lifecycle_status = sqlc.arg('lifecycle_status'),
+    updated_at = NOW()
 WHERE code = sqlc.arg('code');
 
 -- name: CountProductsByLifecycleStatus :one
@@ -154,6 +303,7 @@ SELECT
 FROM (
     SELECT DISTINCT code, lifecycle_status
     FROM products
+    WHERE deleted_at IS NULL
 ) p;
 
 -- name: ListUniqueProductsPaginated :many
@@ -163,6 +313,8 @@ WITH product_aggregates AS (
         MIN(p.description) as description,
         MIN(p.lifecycle_status) as lifecycle_status,
         MIN(p.replacement_url) as replacement_url,
+        MIN(p.family) as family,
+        MIN(p.datasheet_url) as datasheet_url,
         MIN(p.url) as url,
         MIN(p.manufacturer_code) as manufacturer_code,
         MIN(p.sap_code) as sap_code,
@@ -177,14 +329,26 @@ WITH product_aggregates AS (
         ) FILTER (WHERE p.area_id IS NOT NULL) as quantity_by_area
     FROM products p
     LEFT JOIN areas a ON p.area_id = a.id
+    WHERE p.deleted_at IS NULL
     GROUP BY p.code
 )
 SELECT * FROM product_aggregates
-ORDER BY lifecycle_status DESC, created_at DESC
-LIMIT $1 OFFSET $2;
+ORDER BY
+    CASE WHEN sqlc.arg('sort_by')::text = 'code' AND sqlc.arg('sort_order')::text = 'asc' THEN code END ASC,
+    CASE WHEN sqlc.arg('sort_by')::text = 'code' AND sqlc.arg('sort_order')::text = 'desc' THEN code END DESC,
+    CASE WHEN sqlc.arg('sort_by')::text = 'description' AND sqlc.arg('sort_order')::text = 'asc' THEN description END ASC,
+    CASE WHEN sqlc.arg('sort_by')::text = 'description' AND sqlc.arg('sort_order')::text = 'desc' THEN description END DESC,
+    CASE WHEN sqlc.arg('sort_by')::text = 'created_at' AND sqlc.arg('sort_order')::text = 'asc' THEN created_at END ASC,
+    CASE WHEN sqlc.arg('sort_by')::text = 'created_at' AND sqlc.arg('sort_order')::text = 'desc' THEN created_at END DESC,
+    CASE WHEN sqlc.arg('sort_by')::text = 'total_quantity' AND sqlc.arg('sort_order')::text = 'asc' THEN total_quantity END ASC,
+    CASE WHEN sqlc.arg('sort_by')::text = 'total_quantity' AND sqlc.arg('sort_order')::text = 'desc' THEN total_quantity END DESC,
+    CASE WHEN sqlc.arg('sort_by')::text = 'lifecycle_status' AND sqlc.arg('sort_order')::text = 'asc' THEN lifecycle_status END ASC,
+    CASE WHEN sqlc.arg('sort_by')::text = 'lifecycle_status' AND sqlc.arg('sort_order')::text = 'desc' THEN lifecycle_status END DESC,
+    code ASC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
 
 -- name: CountUniqueProducts :one
-SELECT COUNT(DISTINCT code) FROM products;
+SELECT COUNT(DISTINCT code) FROM products WHERE deleted_at IS NULL;
 
 -- name: SearchUniqueProductsPaginated :many
 WITH product_aggregates AS (
@@ -193,6 +357,8 @@ WITH product_aggregates AS (
         MIN(p.description) as description,
         MIN(p.lifecycle_status) as lifecycle_status,
         MIN(p.replacement_url) as replacement_url,
+        MIN(p.family) as family,
+        MIN(p.datasheet_url) as datasheet_url,
         MIN(p.url) as url,
         MIN(p.manufacturer_code) as manufacturer_code,
         MIN(p.sap_code) as sap_code,
@@ -207,6 +373,7 @@ WITH product_aggregates AS (
         ) FILTER (WHERE p.area_id IS NOT NULL) as quantity_by_area
     FROM products p
     LEFT JOIN areas a ON p.area_id = a.id
+    WHERE p.deleted_at IS NULL
     GROUP BY p.code
 )
 SELECT * FROM product_aggregates
@@ -215,17 +382,68 @@ WHERE code ILIKE '%' || sqlc.arg('search')::text || '%'
    OR sap_code ILIKE '%' || sqlc.arg('search')::text || '%'
    OR manufacturer_code ILIKE '%' || sqlc.arg('search')::text || '%'
    OR lifecycle_status ILIKE '%' || sqlc.arg('search')::text || '%'
-ORDER BY lifecycle_status DESC, created_at DESC
+ORDER BY
+    CASE WHEN sqlc.arg('sort_by')::text = 'code' AND sqlc.arg('sort_order')::text = 'asc' THEN code END ASC,
+    CASE WHEN sqlc.arg('sort_by')::text = 'code' AND sqlc.arg('sort_order')::text = 'desc' THEN code END DESC,
+    CASE WHEN sqlc.arg('sort_by')::text = 'description' AND sqlc.arg('sort_order')::text = 'asc' THEN description END ASC,
+    CASE WHEN sqlc.arg('sort_by')::text = 'description' AND sqlc.arg('sort_order')::text = 'desc' THEN description END DESC,
+    CASE WHEN sqlc.arg('sort_by')::text = 'created_at' AND sqlc.arg('sort_order')::text = 'asc' THEN created_at END ASC,
+    CASE WHEN sqlc.arg('sort_by')::text = 'created_at' AND sqlc.arg('sort_order')::text = 'desc' THEN created_at END DESC,
+    CASE WHEN sqlc.arg('sort_by')::text = 'total_quantity' AND sqlc.arg('sort_order')::text = 'asc' THEN total_quantity END ASC,
+    CASE WHEN sqlc.arg('sort_by')::text = 'total_quantity' AND sqlc.arg('sort_order')::text = 'desc' THEN total_quantity END DESC,
+    CASE WHEN sqlc.arg('sort_by')::text = 'lifecycle_status' AND sqlc.arg('sort_order')::text = 'asc' THEN lifecycle_status END ASC,
+    CASE WHEN sqlc.arg('sort_by')::text = 'lifecycle_status' AND sqlc.arg('sort_order')::text = 'desc' THEN lifecycle_status END DESC,
+    code ASC
 LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
 
 -- name: CountUniqueProductsBySearch :one
 SELECT COUNT(DISTINCT code)
 FROM products p
-WHERE p.code ILIKE '%' || sqlc.arg('search')::text || '%'
+WHERE p.deleted_at IS NULL
+  AND (p.code ILIKE '%' || sqlc.arg('search')::text || '%'
    OR p.description ILIKE '%' || sqlc.arg('search')::text || '%'
    OR p.sap_code ILIKE '%' || sqlc.arg('search')::text || '%'
    OR p.manufacturer_code ILIKE '%' || sqlc.arg('search')::text || '%'
-   OR p.lifecycle_status ILIKE '%' || sqlc.arg('search')::text || '%';
+   OR p.lifecycle_status ILIKE '%' || sqlc.arg('search')::text || '%');
+
+-- name: SearchUniqueProductsRankedPaginated :many
+-- Busca textual (?fuzzy=true) ordenada por relevancia; a expressao do tsvector e a do idx_products_search
+WITH product_aggregates AS (
+    SELECT
+        p.code,
+        MIN(p.description) as description,
+        MIN(p.lifecycle_status) as lifecycle_status,
+        MIN(p.replacement_url) as replacement_url,
+        MIN(p.family) as family,
+        MIN(p.datasheet_url) as datasheet_url,
+        MIN(p.url) as url,
+        MIN(p.manufacturer_code) as manufacturer_code,
+        MIN(p.sap_code) as sap_code,
+        SUM(COALESCE(p.quantity, 0))::INTEGER as total_quantity,
+        MIN(p.created_at) as created_at,
+        json_agg(
+            json_build_object(
+                'area_id', p.area_id,
+                'area_name', a.name,
+                'quantity', COALESCE(p.quantity, 0)
+            ) ORDER BY a.name
+        ) FILTER (WHERE p.area_id IS NOT NULL) as quantity_by_area,
+        MAX(ts_rank(to_tsvector('simple', p.code || ' ' || COALESCE(p.description, '') || ' ' || COALESCE(p.manufacturer_code, '') || ' ' || COALESCE(p.sap_code, '')), plainto_tsquery('simple', sqlc.arg('search')::text))) as rank
+    FROM products p
+    LEFT JOIN areas a ON p.area_id = a.id
+    WHERE p.deleted_at IS NULL
+      AND to_tsvector('simple', p.code || ' ' || COALESCE(p.description, '') || ' ' || COALESCE(p.manufacturer_code, '') || ' ' || COALESCE(p.sap_code, '')) @@ plainto_tsquery('simple', sqlc.arg('search')::text)
+    GROUP BY p.code
+)
+SELECT code, description, lifecycle_status, replacement_url, family, datasheet_url, url, manufacturer_code, sap_code, total_quantity, created_at, quantity_by_area FROM product_aggregates
+ORDER BY rank DESC, code
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: CountUniqueProductsByFullText :one
+SELECT COUNT(DISTINCT p.code)
+FROM products p
+WHERE p.deleted_at IS NULL
+  AND to_tsvector('simple', p.code || ' ' || COALESCE(p.description, '') || ' ' || COALESCE(p.manufacturer_code, '') || ' ' || COALESCE(p.sap_code, '')) @@ plainto_tsquery('simple', sqlc.arg('search')::text);
 
 -- name: ListUniqueProductsByAreaPaginated :many
 WITH product_aggregates AS (
@@ -234,6 +452,8 @@ WITH product_aggregates AS (
         MIN(p.description) as description,
         MIN(p.lifecycle_status) as lifecycle_status,
         MIN(p.replacement_url) as replacement_url,
+        MIN(p.family) as family,
+        MIN(p.datasheet_url) as datasheet_url,
         MIN(p.url) as url,
         MIN(p.manufacturer_code) as manufacturer_code,
         MIN(p.sap_code) as sap_code,
@@ -247,17 +467,28 @@ WITH product_aggregates AS (
             ) ORDER BY a.name
         ) FILTER (WHERE p2.area_id IS NOT NULL) as quantity_by_area
     FROM products p
-    INNER JOIN products p2 ON p.code = p2.code
+    INNER JOIN products p2 ON p.code = p2.code AND p2.deleted_at IS NULL
     LEFT JOIN areas a ON p2.area_id = a.id
-    WHERE p.area_id = sqlc.arg('area_id')
+    WHERE p.area_id = sqlc.arg('area_id') AND p.deleted_at IS NULL
     GROUP BY p.code
 )
 SELECT * FROM product_aggregates
-ORDER BY lifecycle_status DESC, created_at DESC
+ORDER BY
+    CASE WHEN sqlc.arg('sort_by')::text = 'code' AND sqlc.arg('sort_order')::text = 'asc' THEN code END ASC,
+    CASE WHEN sqlc.arg('sort_by')::text = 'code' AND sqlc.arg('sort_order')::text = 'desc' THEN code END DESC,
+    CASE WHEN sqlc.arg('sort_by')::text = 'description' AND sqlc.arg('sort_order')::text = 'asc' THEN description END ASC,
+    CASE WHEN sqlc.arg('sort_by')::text = 'description' AND sqlc.arg('sort_order')::text = 'desc' THEN description END DESC,
+    CASE WHEN sqlc.arg('sort_by')::text = 'created_at' AND sqlc.arg('sort_order')::text = 'asc' THEN created_at END ASC,
+    CASE WHEN sqlc.arg('sort_by')::text = 'created_at' AND sqlc.arg('sort_order')::text = 'desc' THEN created_at END DESC,
+    CASE WHEN sqlc.arg('sort_by')::text = 'total_quantity' AND sqlc.arg('sort_order')::text = 'asc' THEN total_quantity END ASC,
+    CASE WHEN sqlc.arg('sort_by')::text = 'total_quantity' AND sqlc.arg('sort_order')::text = 'desc' THEN total_quantity END DESC,
+    CASE WHEN sqlc.arg('sort_by')::text = 'lifecycle_status' AND sqlc.arg('sort_order')::text = 'asc' THEN lifecycle_status END ASC,
+    CASE WHEN sqlc.arg('sort_by')::text = 'lifecycle_status' AND sqlc.arg('sort_order')::text = 'desc' THEN lifecycle_status END DESC,
+    code ASC
 LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
 
 -- name: CountUniqueProductsInArea :one
-SELECT COUNT(DISTINCT code) FROM products WHERE area_id = $1;
+SELECT COUNT(DISTINCT code) FROM products WHERE area_id = $1 AND deleted_at IS NULL;
 
 -- name: SearchUniqueProductsByAreaPaginated :many
 WITH product_aggregates AS (
@@ -266,6 +497,8 @@ WITH product_aggregates AS (
         MIN(p.description) as description,
         MIN(p.lifecycle_status) as lifecycle_status,
         MIN(p.replacement_url) as replacement_url,
+        MIN(p.family) as family,
+        MIN(p.datasheet_url) as datasheet_url,
         MIN(p.url) as url,
         MIN(p.manufacturer_code) as manufacturer_code,
         MIN(p.sap_code) as sap_code,
@@ -279,9 +512,9 @@ WITH product_aggregates AS (
             ) ORDER BY a.name
         ) FILTER (WHERE p2.area_id IS NOT NULL) as quantity_by_area
     FROM products p
-    INNER JOIN products p2 ON p.code = p2.code
+    INNER JOIN products p2 ON p.code = p2.code AND p2.deleted_at IS NULL
     LEFT JOIN areas a ON p2.area_id = a.id
-    WHERE p.area_id = sqlc.arg('area_id')
+    WHERE p.area_id = sqlc.arg('area_id') AND p.deleted_at IS NULL
     GROUP BY p.code
 )
 SELECT * FROM product_aggregates
@@ -290,21 +523,171 @@ WHERE code ILIKE '%' || sqlc.arg('search')::text || '%'
    OR sap_code ILIKE '%' || sqlc.arg('search')::text || '%'
    OR manufacturer_code ILIKE '%' || sqlc.arg('search')::text || '%'
    OR lifecycle_status ILIKE '%' || sqlc.arg('search')::text || '%'
-ORDER BY lifecycle_status DESC, created_at DESC
+ORDER BY
+    CASE WHEN sqlc.arg('sort_by')::text = 'code' AND sqlc.arg('sort_order')::text = 'asc' THEN code END ASC,
+    CASE WHEN sqlc.arg('sort_by')::text = 'code' AND sqlc.arg('sort_order')::text = 'desc' THEN code END DESC,
+    CASE WHEN sqlc.arg('sort_by')::text = 'description' AND sqlc.arg('sort_order')::text = 'asc' THEN description END ASC,
+    CASE WHEN sqlc.arg('sort_by')::text = 'description' AND sqlc.arg('sort_order')::text = 'desc' THEN description END DESC,
+    CASE WHEN sqlc.arg('sort_by')::text = 'created_at' AND sqlc.arg('sort_order')::text = 'asc' THEN created_at END ASC,
+    CASE WHEN sqlc.arg('sort_by')::text = 'created_at' AND sqlc.arg('sort_order')::text = 'desc' THEN created_at END DESC,
+    CASE WHEN sqlc.arg('sort_by')::text = 'total_quantity' AND sqlc.arg('sort_order')::text = 'asc' THEN total_quantity END ASC,
+    CASE WHEN sqlc.arg('sort_by')::text = 'total_quantity' AND sqlc.arg('sort_order')::text = 'desc' THEN total_quantity END DESC,
+    CASE WHEN sqlc.arg('sort_by')::text = 'lifecycle_status' AND sqlc.arg('sort_order')::text = 'asc' THEN lifecycle_status END ASC,
+    CASE WHEN sqlc.arg('sort_by')::text = 'lifecycle_status' AND sqlc.arg('sort_order')::text = 'desc' THEN lifecycle_status END DESC,
+    code ASC
 LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
 
 -- name: CountUniqueProductsByAreaAndSearch :one
 SELECT COUNT(DISTINCT p.code)
 FROM products p
 WHERE p.area_id = sqlc.arg('area_id')
+  AND p.deleted_at IS NULL
   AND (p.code ILIKE '%' || sqlc.arg('search')::text || '%'
    OR p.description ILIKE '%' || sqlc.arg('search')::text || '%'
    OR p.sap_code ILIKE '%' || sqlc.arg('search')::text || '%'
    OR p.manufacturer_code ILIKE '%' || sqlc.arg('search')::text || '%'
    OR p.lifecycle_status ILIKE '%' || sqlc.arg('search')::text || '%');
 
+-- name: SearchUniqueProductsByAreaRankedPaginated :many
+-- Busca textual (?fuzzy=true) na area, ordenada por relevancia
+WITH product_aggregates AS (
+    SELECT
+        p.code,
+        MIN(p.description) as description,
+        MIN(p.lifecycle_status) as lifecycle_status,
+        MIN(p.replacement_url) as replacement_url,
+        MIN(p.family) as family,
+        MIN(p.datasheet_url) as datasheet_url,
+        MIN(p.url) as url,
+        MIN(p.manufacturer_code) as manufacturer_code,
+        MIN(p.sap_code) as sap_code,
+        SUM(COALESCE(p.quantity, 0))::INTEGER as total_quantity,
+        MIN(p.created_at) as created_at,
+        json_agg(
+            json_build_object(
+                'area_id', p2.area_id,
+                'area_name', a.name,
+                'quantity', COALESCE(p2.quantity, 0)
+            ) ORDER BY a.name
+        ) FILTER (WHERE p2.area_id IS NOT NULL) as quantity_by_area,
+        MAX(ts_rank(to_tsvector('simple', p.code || ' ' || COALESCE(p.description, '') || ' ' || COALESCE(p.manufacturer_code, '') || ' ' || COALESCE(p.sap_code, '')), plainto_tsquery('simple', sqlc.arg('search')::text))) as rank
+    FROM products p
+    INNER JOIN products p2 ON p.code = p2.code AND p2.deleted_at IS NULL
+    LEFT JOIN areas a ON p2.area_id = a.id
+    WHERE p.area_id = sqlc.arg('area_id') AND p.deleted_at IS NULL
+      AND to_tsvector('simple', p.code || ' ' || COALESCE(p.description, '') || ' ' || COALESCE(p.manufacturer_code, '') || ' ' || COALESCE(p.sap_code, '')) @@ plainto_tsquery('simple', sqlc.arg('search')::text)
+    GROUP BY p.code
+)
+SELECT code, description, lifecycle_status, replacement_url, family, datasheet_url, url, manufacturer_code, sap_code, total_quantity, created_at, quantity_by_area FROM product_aggregates
+ORDER BY rank DESC, code
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: CountUniqueProductsByAreaAndFullText :one
+SELECT COUNT(DISTINCT p.code)
+FROM products p
+WHERE p.area_id = sqlc.arg('area_id')
+  AND p.deleted_at IS NULL
+  AND to_tsvector('simple', p.code || ' ' || COALESCE(p.description, '') || ' ' || COALESCE(p.manufacturer_code, '') || ' ' || COALESCE(p.sap_code, '')) @@ plainto_tsquery('simple', sqlc.arg('search')::text);
+
+-- name: ListUniqueProductsByStatusPaginated :many
+-- Listagem com filtro lifecycle_status; area_id e search sao opcionais. Com area, o p2 traz as
+-- outras areas do codigo para quantity_by_area; sem area, p2 e a propria linha
+WITH product_aggregates AS (
+    SELECT
+        p.code,
+        MIN(p.description) as description,
+        MIN(p.lifecycle_status) as lifecycle_status,
+        MIN(p.replacement_url) as replacement_url,
+        MIN(p.family) as family,
+        MIN(p.datasheet_url) as datasheet_url,
+        MIN(p.url) as url,
+        MIN(p.manufacturer_code) as manufacturer_code,
+        MIN(p.sap_code) as sap_code,
+        SUM(COALESCE(p.quantity, 0))::INTEGER as total_quantity,
+        MIN(p.created_at) as created_at,
+        json_agg(
+            json_build_object(
+                'area_id', p2.area_id,
+                'area_name', a.name,
+                'quantity', COALESCE(p2.quantity, 0)
+            ) ORDER BY a.name
+        ) FILTER (WHERE p2.area_id IS NOT NULL) as quantity_by_area
+    FROM products p
+    INNER JOIN products p2 ON p.code = p2.code AND p2.deleted_at IS NULL
+        AND (sqlc.narg('area_id')::uuid IS NOT NULL OR p2.id = p.id)
+    LEFT JOIN areas a ON p2.area_id = a.id
+    WHERE p.deleted_at IS NULL
+      AND (sqlc.narg('area_id')::uuid IS NULL OR p.area_id = sqlc.narg('area_id')::uuid)
+    GROUP BY p.code
+)
+SELECT * FROM product_aggregates
+WHERE lifecycle_status = ANY(sqlc.arg('statuses')::text[])
+  AND (sqlc.narg('search')::text IS NULL
+   OR code ILIKE '%' || sqlc.narg('search')::text || '%'
+   OR description ILIKE '%' || sqlc.narg('search')::text || '%'
+   OR sap_code ILIKE '%' || sqlc.narg('search')::text || '%'
+   OR manufacturer_code ILIKE '%' || sqlc.narg('search')::text || '%'
+   OR lifecycle_status ILIKE '%' || sqlc.narg('search')::text || '%')
+ORDER BY
+    CASE WHEN sqlc.arg('sort_by')::text = 'code' AND sqlc.arg('sort_order')::text = 'asc' THEN code END ASC,
+    CASE WHEN sqlc.arg('sort_by')::text = 'code' AND sqlc.arg('sort_order')::text = 'desc' THEN code END DESC,
+    CASE WHEN sqlc.arg('sort_by')::text = 'description' AND sqlc.arg('sort_order')::text = 'asc' THEN description END ASC,
+    CASE WHEN sqlc.arg('sort_by')::text = 'description' AND sqlc.arg('sort_order')::text = 'desc' THEN description END DESC,
+    CASE WHEN sqlc.arg('sort_by')::text = 'created_at' AND sqlc.arg('sort_order')::text = 'asc' THEN created_at END ASC,
+    CASE WHEN sqlc.arg('sort_by')::text = 'created_at' AND sqlc.arg('sort_order')::text = 'desc' THEN created_at END DESC,
+    CASE WHEN sqlc.arg('sort_by')::text = 'total_quantity' AND sqlc.arg('sort_order')::text = 'asc' THEN total_quantity END ASC,
+    CASE WHEN sqlc.arg('sort_by')::text = 'total_quantity' AND sqlc.arg('sort_order')::text = 'desc' THEN total_quantity END DESC,
+    CASE WHEN sqlc.arg('sort_by')::text = 'lifecycle_status' AND sqlc.arg('sort_order')::text = 'asc' THEN lifecycle_status END ASC,
+    CASE WHEN sqlc.arg('sort_by')::text = 'lifecycle_status' AND sqlc.arg('sort_order')::text = 'desc' THEN lifecycle_status END DESC,
+    code ASC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: CountUniqueProductsByStatus :one
+SELECT COUNT(DISTINCT p.code)
+FROM products p
+WHERE p.deleted_at IS NULL
+  AND p.lifecycle_status = ANY(sqlc.arg('statuses')::text[])
+  AND (sqlc.narg('area_id')::uuid IS NULL OR p.area_id = sqlc.narg('area_id')::uuid)
+  AND (sqlc.narg('search')::text IS NULL
+   OR p.code ILIKE '%' || sqlc.narg('search')::text || '%'
+   OR p.description ILIKE '%' || sqlc.narg('search')::text || '%'
+   OR p.sap_code ILIKE '%' || sqlc.narg('search')::text || '%'
+   OR p.manufacturer_code ILIKE '%' || sqlc.narg('search')::text || '%'
+   OR p.lifecycle_status ILIKE '%' || sqlc.narg('search')::text || '%');
+
+-- name: ListStaleProductCodesToCollect :many
+-- Codigos sem nenhuma coleta (de qualquer produto com o codigo) desde collected_before
+SELECT DISTINCT ON (p.code) p.id, p.code, p.url
+FROM products p
+WHERE p.deleted_at IS NULL
+  AND NOT EXISTS (
+    SELECT 1 FROM product_snapshots s
+    JOIN products sp ON sp.id = s.product_id
+    WHERE sp.code = p.code AND s.collected_at >= sqlc.arg('collected_before')::timestamp
+)
+ORDER BY p.code, p.created_at ASC;
+
 -- name: ListUniqueProductCodesToCollect :many
 SELECT DISTINCT ON (p.code) p.id, p.code, p.url
 FROM products p
+WHERE p.deleted_at IS NULL
 ORDER BY p.code, p.created_at ASC;
 
+-- name: ListUniqueProductCodesWithUnknownStatus :many
+-- Codigos sem status de ciclo de vida ou com algum produto nunca coletado (sem snapshot)
+SELECT DISTINCT ON (p.code) p.id, p.code, p.url
+FROM products p
+WHERE p.deleted_at IS NULL
+  AND (COALESCE(p.lifecycle_status, '') = ''
+   OR NOT EXISTS (SELECT 1 FROM product_snapshots s WHERE s.product_id = p.id))
+ORDER BY p.code, p.created_at ASC;
+
+
+-- name: GetProductsExportVersion :one
+-- Versao da exportacao: muda quando algum produto e alterado, criado ou removido, ou uma area e renomeada
+SELECT
+    COALESCE(MAX(p.updated_at), 'epoch'::timestamp)::timestamp AS last_updated,
+    COUNT(*) AS product_count,
+    COALESCE((SELECT md5(string_agg(a.id::text || ':' || a.name, ',' ORDER BY a.id)) FROM areas a), '')::text AS areas_hash
+FROM products p
+WHERE p.deleted_at IS NULL;
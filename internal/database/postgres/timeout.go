@@ -0,0 +1,87 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	repo "github.com/freitasmatheusrn/lifecycle-monitor/internal/database/postgres/sqlc"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// WithQueryTimeout wraps db so every query runs with a context bounded by timeout,
+// on top of whatever deadline the caller's context already has. This keeps a slow
+// query from hanging until the request or cron context expires. Queries on a context
+// marked by WithoutQueryTimeout only keep the caller's deadline.
+// A timeout <= 0 returns db unchanged.
+func WithQueryTimeout(db repo.DBTX, timeout time.Duration) repo.DBTX {
+	if timeout <= 0 {
+		return db
+	}
+	return &timeoutDB{db: db, timeout: timeout}
+}
+
+type noQueryTimeoutKey struct{}
+
+// WithoutQueryTimeout marks ctx for bulk reads, such as the catalog exports, whose queries read
+// every product and can't fit the per query timeout. They stay bounded by ctx itself, so the
+// request being canceled still ends them.
+func WithoutQueryTimeout(ctx context.Context) context.Context {
+	return context.WithValue(ctx, noQueryTimeoutKey{}, true)
+}
+
+// queryContext bounds ctx by the query timeout unless it was marked by WithoutQueryTimeout
+func (t *timeoutDB) queryContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if exempt, _ := ctx.Value(noQueryTimeoutKey{}).(bool); exempt {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, t.timeout)
+}
+
+type timeoutDB struct {
+	db      repo.DBTX
+	timeout time.Duration
+}
+
+func (t *timeoutDB) Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error) {
+	ctx, cancel := t.queryContext(ctx)
+	defer cancel()
+	return t.db.Exec(ctx, sql, args...)
+}
+
+// Query keeps the timeout context alive until the rows are closed, since they are read after Query returns
+func (t *timeoutDB) Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error) {
+	ctx, cancel := t.queryContext(ctx)
+	rows, err := t.db.Query(ctx, sql, args...)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	return &timeoutRows{Rows: rows, cancel: cancel}, nil
+}
+
+// QueryRow keeps the timeout context alive until the row is scanned
+func (t *timeoutDB) QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row {
+	ctx, cancel := t.queryContext(ctx)
+	return &timeoutRow{row: t.db.QueryRow(ctx, sql, args...), cancel: cancel}
+}
+
+type timeoutRows struct {
+	pgx.Rows
+	cancel context.CancelFunc
+}
+
+func (r *timeoutRows) Close() {
+	r.Rows.Close()
+	r.cancel()
+}
+
+type timeoutRow struct {
+	row    pgx.Row
+	cancel context.CancelFunc
+}
+
+func (r *timeoutRow) Scan(dest ...interface{}) error {
+	defer r.cancel()
+	return r.row.Scan(dest...)
+}
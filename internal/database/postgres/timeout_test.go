@@ -0,0 +1,138 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// MockDBTX records the context each query was called with
+type MockDBTX struct {
+	ctx context.Context
+}
+
+func (m *MockDBTX) Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error) {
+	m.ctx = ctx
+	return pgconn.CommandTag{}, nil
+}
+
+func (m *MockDBTX) Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error) {
+	m.ctx = ctx
+	return &MockRows{}, nil
+}
+
+func (m *MockDBTX) QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row {
+	m.ctx = ctx
+	return &MockRow{}
+}
+
+// MockRows implements only Close; the embedded interface covers the rest
+type MockRows struct {
+	pgx.Rows
+}
+
+func (m *MockRows) Close() {}
+
+type MockRow struct{}
+
+func (m *MockRow) Scan(dest ...interface{}) error { return nil }
+
+func TestWithQueryTimeout_Disabled(t *testing.T) {
+	db := &MockDBTX{}
+	if WithQueryTimeout(db, 0) != db {
+		t.Error("expected db to be returned unchanged when timeout is disabled")
+	}
+}
+
+func TestWithQueryTimeout_Exec(t *testing.T) {
+	db := &MockDBTX{}
+	wrapped := WithQueryTimeout(db, time.Minute)
+
+	if _, err := wrapped.Exec(context.Background(), "SELECT 1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, ok := db.ctx.Deadline(); !ok {
+		t.Error("expected query context to have a deadline")
+	}
+	if db.ctx.Err() == nil {
+		t.Error("expected query context to be cancelled after Exec returns")
+	}
+}
+
+func TestWithQueryTimeout_QueryCancelsOnClose(t *testing.T) {
+	db := &MockDBTX{}
+	wrapped := WithQueryTimeout(db, time.Minute)
+
+	rows, err := wrapped.Query(context.Background(), "SELECT 1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if db.ctx.Err() != nil {
+		t.Fatal("query context cancelled before rows were closed")
+	}
+
+	rows.Close()
+
+	if db.ctx.Err() == nil {
+		t.Error("expected query context to be cancelled after rows are closed")
+	}
+}
+
+func TestWithQueryTimeout_QueryRowCancelsOnScan(t *testing.T) {
+	db := &MockDBTX{}
+	wrapped := WithQueryTimeout(db, time.Minute)
+
+	row := wrapped.QueryRow(context.Background(), "SELECT 1")
+	if db.ctx.Err() != nil {
+		t.Fatal("query context cancelled before row was scanned")
+	}
+
+	if err := row.Scan(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if db.ctx.Err() == nil {
+		t.Error("expected query context to be cancelled after scan")
+	}
+}
+
+func TestWithQueryTimeout_KeepsShorterCallerDeadline(t *testing.T) {
+	db := &MockDBTX{}
+	wrapped := WithQueryTimeout(db, time.Hour)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	callerDeadline, _ := ctx.Deadline()
+
+	row := wrapped.QueryRow(ctx, "SELECT 1")
+	defer row.Scan()
+
+	deadline, ok := db.ctx.Deadline()
+	if !ok || deadline.After(callerDeadline) {
+		t.Errorf("expected caller deadline %v to be kept, got %v", callerDeadline, deadline)
+	}
+}
+
+func TestWithQueryTimeout_BulkQueryKeepsOnlyCallerDeadline(t *testing.T) {
+	db := &MockDBTX{}
+	wrapped := WithQueryTimeout(db, time.Millisecond)
+
+	rows, err := wrapped.Query(WithoutQueryTimeout(context.Background()), "SELECT * FROM products")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, ok := db.ctx.Deadline(); ok {
+		t.Error("expected a bulk query to run without the query timeout")
+	}
+
+	rows.Close()
+	if db.ctx.Err() == nil {
+		t.Error("expected query context to be cancelled after rows are closed")
+	}
+}
@@ -17,19 +17,52 @@ type Config struct {
 	Port     string
 	Password string
 	DB       int
+	Pool     PoolConfig
+}
+
+// PoolConfig holds the connection pool size and timeouts. Zero values use the defaults.
+type PoolConfig struct {
+	PoolSize     int
+	MinIdleConns int
+	DialTimeout  time.Duration
+	ReadTimeout  time.Duration
+	WriteTimeout time.Duration
+}
+
+// Valores padrao do pool, usados quando o campo correspondente nao e configurado
+const (
+	defaultPoolSize     = 10
+	defaultMinIdleConns = 5
+	defaultDialTimeout  = 5 * time.Second
+	defaultReadTimeout  = 3 * time.Second
+	defaultWriteTimeout = 3 * time.Second
+)
+
+// apply sets the pool options on opt, falling back to the defaults for unset values
+func (p PoolConfig) apply(opt *redis.Options) {
+	opt.PoolSize = valueOrDefault(p.PoolSize, defaultPoolSize)
+	opt.MinIdleConns = valueOrDefault(p.MinIdleConns, defaultMinIdleConns)
+	opt.DialTimeout = valueOrDefault(p.DialTimeout, defaultDialTimeout)
+	opt.ReadTimeout = valueOrDefault(p.ReadTimeout, defaultReadTimeout)
+	opt.WriteTimeout = valueOrDefault(p.WriteTimeout, defaultWriteTimeout)
+}
+
+func valueOrDefault[T int | time.Duration](value, fallback T) T {
+	if value <= 0 {
+		return fallback
+	}
+	return value
 }
 
 func NewClient(cfg Config) (*Client, error) {
-	client := redis.NewClient(&redis.Options{
-		Addr:         fmt.Sprintf("%s:%s", cfg.Host, cfg.Port),
-		Password:     cfg.Password,
-		DB:           cfg.DB,
-		DialTimeout:  5 * time.Second,
-		ReadTimeout:  3 * time.Second,
-		WriteTimeout: 3 * time.Second,
-		PoolSize:     10,
-		MinIdleConns: 5,
-	})
+	opt := &redis.Options{
+		Addr:     fmt.Sprintf("%s:%s", cfg.Host, cfg.Port),
+		Password: cfg.Password,
+		DB:       cfg.DB,
+	}
+	cfg.Pool.apply(opt)
+
+	client := redis.NewClient(opt)
 
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
@@ -42,17 +75,13 @@ func NewClient(cfg Config) (*Client, error) {
 }
 
 // NewClientFromURL creates a Redis client from a URL (e.g., redis://:password@host:port)
-func NewClientFromURL(redisURL string) (*Client, error) {
+func NewClientFromURL(redisURL string, pool PoolConfig) (*Client, error) {
 	opt, err := redis.ParseURL(redisURL)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse redis URL: %w", err)
 	}
 
-	opt.DialTimeout = 5 * time.Second
-	opt.ReadTimeout = 3 * time.Second
-	opt.WriteTimeout = 3 * time.Second
-	opt.PoolSize = 10
-	opt.MinIdleConns = 5
+	pool.apply(opt)
 
 	client := redis.NewClient(opt)
 
@@ -0,0 +1,38 @@
+package redis
+
+import (
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+func TestPoolConfigApply_Defaults(t *testing.T) {
+	opt := &redis.Options{}
+	PoolConfig{}.apply(opt)
+
+	if opt.PoolSize != 10 || opt.MinIdleConns != 5 {
+		t.Errorf("expected default pool 10/5, got %d/%d", opt.PoolSize, opt.MinIdleConns)
+	}
+	if opt.DialTimeout != 5*time.Second || opt.ReadTimeout != 3*time.Second || opt.WriteTimeout != 3*time.Second {
+		t.Errorf("expected default timeouts 5s/3s/3s, got %v/%v/%v", opt.DialTimeout, opt.ReadTimeout, opt.WriteTimeout)
+	}
+}
+
+func TestPoolConfigApply_Overrides(t *testing.T) {
+	opt := &redis.Options{}
+	PoolConfig{
+		PoolSize:     50,
+		MinIdleConns: 10,
+		DialTimeout:  time.Second,
+		ReadTimeout:  2 * time.Second,
+		WriteTimeout: 4 * time.Second,
+	}.apply(opt)
+
+	if opt.PoolSize != 50 || opt.MinIdleConns != 10 {
+		t.Errorf("expected pool 50/10, got %d/%d", opt.PoolSize, opt.MinIdleConns)
+	}
+	if opt.DialTimeout != time.Second || opt.ReadTimeout != 2*time.Second || opt.WriteTimeout != 4*time.Second {
+		t.Errorf("expected timeouts 1s/2s/4s, got %v/%v/%v", opt.DialTimeout, opt.ReadTimeout, opt.WriteTimeout)
+	}
+}
@@ -0,0 +1,113 @@
+package products
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/playwright-community/playwright-go"
+)
+
+// unsafeFileChars are replaced in product codes used as file names
+var unsafeFileChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// FailureCapture saves a screenshot and the HTML of product pages whose extraction failed,
+// keeping only the most recent captures. A nil *FailureCapture captures nothing.
+type FailureCapture struct {
+	dir         string
+	maxCaptures int // capturas mantidas no diretorio; as mais antigas sao removidas
+	mu          sync.Mutex
+}
+
+// NewFailureCapture stores captures in dir, keeping at most maxCaptures of them.
+// An empty dir disables capturing and returns nil.
+func NewFailureCapture(dir string, maxCaptures int) (*FailureCapture, error) {
+	if dir == "" {
+		return nil, nil
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("could not create capture dir: %w", err)
+	}
+	return &FailureCapture{dir: dir, maxCaptures: max(maxCaptures, 1)}, nil
+}
+
+// Save writes <code>_<timestamp>.png and .html for page and returns their path without extension
+func (f *FailureCapture) Save(page playwright.Page, productCode, html string) (string, error) {
+	return f.save(productCode, time.Now(), html, func(path string) error {
+		_, err := page.Screenshot(playwright.PageScreenshotOptions{
+			Path:     playwright.String(path),
+			FullPage: playwright.Bool(true),
+		})
+		return err
+	})
+}
+
+func (f *FailureCapture) save(productCode string, at time.Time, html string, screenshot func(path string) error) (string, error) {
+	if f == nil {
+		return "", nil
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	name := unsafeFileChars.ReplaceAllString(productCode, "_") + "_" + at.UTC().Format("20060102T150405.000")
+	base := filepath.Join(f.dir, name)
+
+	if err := os.WriteFile(base+".html", []byte(html), 0o644); err != nil {
+		return "", fmt.Errorf("could not save html: %w", err)
+	}
+	if err := screenshot(base + ".png"); err != nil {
+		return base, fmt.Errorf("could not save screenshot: %w", err)
+	}
+
+	f.prune()
+	return base, nil
+}
+
+// prune removes the oldest captures beyond maxCaptures; a capture is the .png and .html with the same name
+func (f *FailureCapture) prune() {
+	entries, err := os.ReadDir(f.dir)
+	if err != nil {
+		return
+	}
+
+	modTimes := make(map[string]time.Time)
+	for _, entry := range entries {
+		ext := filepath.Ext(entry.Name())
+		if entry.IsDir() || (ext != ".png" && ext != ".html") {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		name := strings.TrimSuffix(entry.Name(), ext)
+		if info.ModTime().After(modTimes[name]) {
+			modTimes[name] = info.ModTime()
+		}
+	}
+	if len(modTimes) <= f.maxCaptures {
+		return
+	}
+
+	names := make([]string, 0, len(modTimes))
+	for name := range modTimes {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if !modTimes[names[i]].Equal(modTimes[names[j]]) {
+			return modTimes[names[i]].Before(modTimes[names[j]])
+		}
+		return names[i] < names[j]
+	})
+
+	for _, name := range names[:len(names)-f.maxCaptures] {
+		os.Remove(filepath.Join(f.dir, name+".png"))
+		os.Remove(filepath.Join(f.dir, name+".html"))
+	}
+}
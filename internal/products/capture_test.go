@@ -0,0 +1,66 @@
+package products
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFailureCapture_SaveAndPrune(t *testing.T) {
+	dir := t.TempDir()
+	capture, err := NewFailureCapture(dir, 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	screenshot := func(path string) error { return os.WriteFile(path, []byte("png"), 0o644) }
+	start := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	var saved []string
+	for i := range 3 {
+		at := start.Add(time.Duration(i) * time.Minute)
+		base, err := capture.save("6ES7 214/1AG40", at, "<html></html>", screenshot)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		// mtime drives retention; make the order explicit instead of relying on the clock
+		for _, ext := range []string{".png", ".html"} {
+			if err := os.Chtimes(base+ext, at, at); err != nil {
+				t.Fatal(err)
+			}
+		}
+		saved = append(saved, base)
+	}
+
+	if filepath.Base(saved[0]) != "6ES7_214_1AG40_20260301T100000.000" {
+		t.Errorf("unexpected capture name %q", filepath.Base(saved[0]))
+	}
+
+	// The third save prunes before its mtime is set, so prune once more with final mtimes
+	capture.prune()
+
+	if _, err := os.Stat(saved[0] + ".png"); !os.IsNotExist(err) {
+		t.Errorf("expected oldest capture to be removed")
+	}
+	for _, base := range saved[1:] {
+		for _, ext := range []string{".png", ".html"} {
+			if _, err := os.Stat(base + ext); err != nil {
+				t.Errorf("expected %s%s to be kept: %v", base, ext, err)
+			}
+		}
+	}
+}
+
+func TestFailureCapture_Disabled(t *testing.T) {
+	capture, err := NewFailureCapture("", 10)
+	if err != nil || capture != nil {
+		t.Fatalf("expected nil capture without dir, got %v, %v", capture, err)
+	}
+
+	called := false
+	base, err := capture.save("6ES7", time.Now(), "", func(string) error { called = true; return nil })
+	if base != "" || err != nil || called {
+		t.Errorf("expected nil capture to do nothing")
+	}
+}
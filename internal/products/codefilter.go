@@ -0,0 +1,140 @@
+package products
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// regexPrefix marks a code pattern as a regular expression; other patterns match by prefix
+const regexPrefix = "re:"
+
+// CodeFilterRules are the include/exclude patterns of a CodeFilter.
+// A pattern is a code prefix ("6ES7") or a regex prefixed with "re:" ("re:^6ES7[0-9]{3}-1").
+type CodeFilterRules struct {
+	Include []string `json:"include"` // when not empty, only matching codes are crawled
+	Exclude []string `json:"exclude"` // matching codes are never crawled
+}
+
+// CodeFilter decides which product codes the scheduler crawls.
+// The rules can be replaced at runtime; the zero value (and nil) allows every code.
+type CodeFilter struct {
+	mu      sync.RWMutex
+	rules   CodeFilterRules
+	include []codePattern
+	exclude []codePattern
+}
+
+type codePattern struct {
+	prefix string
+	regex  *regexp.Regexp
+}
+
+func (p codePattern) match(code string) bool {
+	if p.regex != nil {
+		return p.regex.MatchString(code)
+	}
+	return strings.HasPrefix(code, p.prefix)
+}
+
+// NewCodeFilter creates a filter with the given rules, failing on invalid regexes
+func NewCodeFilter(rules CodeFilterRules) (*CodeFilter, error) {
+	f := &CodeFilter{}
+	if err := f.SetRules(rules); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// SetRules replaces the rules; on error the previous rules are kept
+func (f *CodeFilter) SetRules(rules CodeFilterRules) error {
+	include, err := compileCodePatterns(rules.Include)
+	if err != nil {
+		return err
+	}
+	exclude, err := compileCodePatterns(rules.Exclude)
+	if err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.rules = CodeFilterRules{Include: patternsOf(include), Exclude: patternsOf(exclude)}
+	f.include = include
+	f.exclude = exclude
+	return nil
+}
+
+// Rules returns the current rules
+func (f *CodeFilter) Rules() CodeFilterRules {
+	if f == nil {
+		return CodeFilterRules{Include: []string{}, Exclude: []string{}}
+	}
+
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.rules
+}
+
+// Allows reports whether code should be crawled: not excluded and, with an include list, included
+func (f *CodeFilter) Allows(code string) bool {
+	if f == nil {
+		return true
+	}
+
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	for _, p := range f.exclude {
+		if p.match(code) {
+			return false
+		}
+	}
+	if len(f.include) == 0 {
+		return true
+	}
+	for _, p := range f.include {
+		if p.match(code) {
+			return true
+		}
+	}
+	return false
+}
+
+func compileCodePatterns(patterns []string) ([]codePattern, error) {
+	compiled := make([]codePattern, 0, len(patterns))
+	for _, pattern := range patterns {
+		pattern = strings.TrimSpace(pattern)
+		if pattern == "" {
+			continue
+		}
+
+		expr, isRegex := strings.CutPrefix(pattern, regexPrefix)
+		if !isRegex {
+			compiled = append(compiled, codePattern{prefix: pattern})
+			continue
+		}
+
+		regex, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, fmt.Errorf("%q: %w", pattern, err)
+		}
+		compiled = append(compiled, codePattern{regex: regex})
+	}
+	return compiled, nil
+}
+
+// patternsOf returns the patterns back in their config form
+func patternsOf(compiled []codePattern) []string {
+	patterns := make([]string, 0, len(compiled))
+	for _, p := range compiled {
+		if p.regex != nil {
+			patterns = append(patterns, regexPrefix+p.regex.String())
+		} else {
+			patterns = append(patterns, p.prefix)
+		}
+	}
+	return patterns
+}
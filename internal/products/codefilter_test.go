@@ -0,0 +1,59 @@
+package products
+
+import "testing"
+
+func TestCodeFilter_Allows(t *testing.T) {
+	filter, err := NewCodeFilter(CodeFilterRules{
+		Include: []string{"6ES7", "re:^6AV2[0-9]{3}-"},
+		Exclude: []string{" 6ES7 214", ""},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		code string
+		want bool
+	}{
+		{"6ES7 215-1AG40-0XB0", true},
+		{"6ES7 214-1AG40-0XB0", false}, // exclude wins over include
+		{"6AV2123-2GB03-0AX0", true},
+		{"6AV21-2GB03", false},
+		{"3RT2015-1BB41", false}, // not in the include list
+	}
+	for _, tt := range tests {
+		if got := filter.Allows(tt.code); got != tt.want {
+			t.Errorf("Allows(%q) = %v, want %v", tt.code, got, tt.want)
+		}
+	}
+
+	rules := filter.Rules()
+	if len(rules.Exclude) != 1 || rules.Exclude[0] != "6ES7 214" {
+		t.Errorf("expected trimmed exclude patterns without blanks, got %v", rules.Exclude)
+	}
+}
+
+func TestCodeFilter_EmptyAllowsEverything(t *testing.T) {
+	var nilFilter *CodeFilter
+	if !nilFilter.Allows("6ES7 214-1AG40-0XB0") || !(&CodeFilter{}).Allows("6ES7 214-1AG40-0XB0") {
+		t.Error("expected nil and zero filters to allow every code")
+	}
+}
+
+func TestCodeFilter_SetRulesKeepsPreviousOnError(t *testing.T) {
+	filter, err := NewCodeFilter(CodeFilterRules{Exclude: []string{"6ES5"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := filter.SetRules(CodeFilterRules{Include: []string{"re:[6ES7"}}); err == nil {
+		t.Fatal("expected error for invalid regex")
+	}
+
+	if filter.Allows("6ES5 100") {
+		t.Error("expected previous exclude rules to stay in effect")
+	}
+	if !filter.Allows("6ES7 214") {
+		t.Error("expected failed update not to add an include list")
+	}
+}
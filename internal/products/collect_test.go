@@ -0,0 +1,99 @@
+package products
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	repo "github.com/freitasmatheusrn/lifecycle-monitor/internal/database/postgres/sqlc"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+)
+
+// collectQuerier has one product with the given lifecycle status, whose latest snapshot was
+// collected at collectedAt
+type collectQuerier struct {
+	MockQuerier
+	product     repo.FindProductByIDRow
+	collectedAt time.Time
+}
+
+func (q *collectQuerier) FindProductByID(ctx context.Context, id pgtype.UUID) (repo.FindProductByIDRow, error) {
+	if id != q.product.ID {
+		return repo.FindProductByIDRow{}, pgx.ErrNoRows
+	}
+	return q.product, nil
+}
+
+func (q *collectQuerier) FindProductByCode(ctx context.Context, code string) (repo.FindProductByCodeRow, error) {
+	return repo.FindProductByCodeRow(q.product), nil
+}
+
+func (q *collectQuerier) GetLatestSnapshot(ctx context.Context, productID pgtype.UUID) (repo.ProductSnapshot, error) {
+	return repo.ProductSnapshot{ProductID: productID, CollectedAt: pgtype.Timestamp{Time: q.collectedAt, Valid: true}}, nil
+}
+
+func (q *collectQuerier) DeleteCrawlFailure(ctx context.Context, code string) error { return nil }
+
+func (q *collectQuerier) FindLifecycleStatusOverride(ctx context.Context, code string) (repo.LifecycleStatusOverride, error) {
+	return repo.LifecycleStatusOverride{}, pgx.ErrNoRows
+}
+
+func (q *collectQuerier) FindActiveLifecycleAlertSnooze(ctx context.Context, code string) (repo.LifecycleAlertSnooze, error) {
+	return repo.LifecycleAlertSnooze{}, pgx.ErrNoRows
+}
+
+func (q *collectQuerier) DeleteLifecycleAlertSnooze(ctx context.Context, code string) (int64, error) {
+	return 0, nil
+}
+
+func TestCollectProduct_StatusChange(t *testing.T) {
+	productID := pgtype.UUID{Bytes: [16]byte{1}, Valid: true}
+	querier := &collectQuerier{
+		product: repo.FindProductByIDRow{
+			ID:              productID,
+			Code:            "6ES7214-1AG40-0XB0",
+			LifecycleStatus: pgtype.Text{String: "Active Product", Valid: true},
+		},
+		collectedAt: time.Now().Add(-time.Minute),
+	}
+	collector := &MockPageCollector{data: &CrawledData{Status: "Phase Out Announce"}}
+	service := &svc{
+		repo:            querier,
+		logger:          zap.NewNop(),
+		workerPool:      newTestWorkerPool(t, collector, querier),
+		collectCooldown: 10 * time.Minute,
+	}
+
+	// Within the cooldown nothing is crawled and there is no change to report
+	cached, apiErr := service.CollectProduct(t.Context(), productID, false)
+	if apiErr != nil {
+		t.Fatalf("unexpected error: %v", apiErr.Message)
+	}
+	if !cached.Cached || cached.StatusChange != nil || collector.calls.Load() != 0 {
+		t.Fatalf("expected a cached result without crawling, got %+v", cached)
+	}
+
+	collected, apiErr := service.CollectProduct(t.Context(), productID, true)
+	if apiErr != nil {
+		t.Fatalf("unexpected error: %v", apiErr.Message)
+	}
+	if collected.Cached {
+		t.Error("expected a fresh collect with force")
+	}
+	want := CollectStatusChange{OldStatus: "Active Product", NewStatus: "Phase Out Announce"}
+	if collected.StatusChange == nil || *collected.StatusChange != want {
+		t.Errorf("expected status change %+v, got %+v", want, collected.StatusChange)
+	}
+
+	// Same status on the next crawl: no change reported
+	querier.product.LifecycleStatus = pgtype.Text{String: "Phase Out Announce", Valid: true}
+	collected, apiErr = service.CollectProduct(t.Context(), productID, true)
+	if apiErr != nil {
+		t.Fatalf("unexpected error: %v", apiErr.Message)
+	}
+	if collected.StatusChange != nil {
+		t.Errorf("expected no status change, got %+v", collected.StatusChange)
+	}
+}
@@ -0,0 +1,66 @@
+package products
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/freitasmatheusrn/lifecycle-monitor/pkg/rest"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// CompareProducts returns two products side by side, each with its latest snapshot, and the fields
+// whose values differ. Used to check an obsolete product against its successor; 404 when either is missing.
+func (s *svc) CompareProducts(ctx context.Context, idA, idB pgtype.UUID) (*ProductComparison, *rest.ApiErr) {
+	a, apiErr := s.GetProduct(ctx, idA)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	b, apiErr := s.GetProduct(ctx, idB)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+
+	return &ProductComparison{
+		A:               *a,
+		B:               *b,
+		DifferentFields: comparedFields(*a, *b),
+	}, nil
+}
+
+// comparedFields lists the fields that differ between two products; a missing snapshot reads as empty
+func comparedFields(a, b ProductWithSnapshotOutput) []ComparedField {
+	snapshotA, snapshotB := a.LatestSnapshot, b.LatestSnapshot
+	if snapshotA == nil {
+		snapshotA = &SnapshotOutput{}
+	}
+	if snapshotB == nil {
+		snapshotB = &SnapshotOutput{}
+	}
+
+	fields := []ComparedField{
+		{"code", a.Product.Code, b.Product.Code},
+		{"area_name", a.Product.AreaName, b.Product.AreaName},
+		{"description", a.Product.Description, b.Product.Description},
+		{"manufacturer_code", a.Product.ManufacturerCode, b.Product.ManufacturerCode},
+		{"family", a.Product.Family, b.Product.Family},
+		{"datasheet_url", a.Product.DatasheetURL, b.Product.DatasheetURL},
+		{"sap_code", a.Product.SAPCode, b.Product.SAPCode},
+		{"observations", a.Product.Observations, b.Product.Observations},
+		{"quantity", strconv.Itoa(a.Product.Quantity), strconv.Itoa(b.Product.Quantity)},
+		{"min_quantity", strconv.Itoa(a.Product.MinQuantity), strconv.Itoa(b.Product.MinQuantity)},
+		{"max_quantity", strconv.Itoa(a.Product.MaxQuantity), strconv.Itoa(b.Product.MaxQuantity)},
+		{"inventory_status", a.Product.InventoryStatus, b.Product.InventoryStatus},
+		{"lifecycle_status", a.Product.LifeCycleStatus, b.Product.LifeCycleStatus},
+		{"replacement_url", a.Product.ReplacementURL, b.Product.ReplacementURL},
+		{"latest_snapshot.status", snapshotA.Status, snapshotB.Status},
+		{"latest_snapshot.description", snapshotA.Description, snapshotB.Description},
+	}
+
+	different := make([]ComparedField, 0, len(fields))
+	for _, field := range fields {
+		if field.A != field.B {
+			different = append(different, field)
+		}
+	}
+	return different
+}
@@ -0,0 +1,56 @@
+package products
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+)
+
+func TestComparedFields(t *testing.T) {
+	obsolete := ProductWithSnapshotOutput{
+		Product: ProductOutput{Code: "6ES7214-1AG40-0XB0", AreaName: "Linha 1", Description: "CPU 1214C",
+			Family: "S7-1200", Quantity: 2, LifeCycleStatus: StatusDiscontinued, ReplacementURL: "6ES7214-1AG40-0XB1"},
+		LatestSnapshot: &SnapshotOutput{Status: StatusDiscontinued, Description: "CPU 1214C"},
+	}
+	successor := ProductWithSnapshotOutput{
+		Product: ProductOutput{Code: "6ES7214-1AG40-0XB1", AreaName: "Linha 1", Description: "CPU 1214C",
+			Family: "S7-1200", Quantity: 0, LifeCycleStatus: StatusActive},
+	}
+
+	fields := comparedFields(obsolete, successor)
+
+	expected := []ComparedField{
+		{"code", "6ES7214-1AG40-0XB0", "6ES7214-1AG40-0XB1"},
+		{"quantity", "2", "0"},
+		{"lifecycle_status", StatusDiscontinued, StatusActive},
+		{"replacement_url", "6ES7214-1AG40-0XB1", ""},
+		{"latest_snapshot.status", StatusDiscontinued, ""},
+		{"latest_snapshot.description", "CPU 1214C", ""},
+	}
+	if len(fields) != len(expected) {
+		t.Fatalf("expected %d different fields, got %+v", len(expected), fields)
+	}
+	for i, field := range fields {
+		if field != expected[i] {
+			t.Errorf("field %d: expected %+v, got %+v", i, expected[i], field)
+		}
+	}
+
+	if same := comparedFields(obsolete, obsolete); len(same) != 0 {
+		t.Errorf("expected no differences comparing a product with itself, got %+v", same)
+	}
+}
+
+func TestCompareProducts_NotFound(t *testing.T) {
+	productID := pgtype.UUID{Bytes: [16]byte{1}, Valid: true}
+	querier := &collectQuerier{}
+	querier.product.ID = productID
+	service := &svc{repo: querier, logger: zap.NewNop()}
+
+	_, apiErr := service.CompareProducts(t.Context(), productID, pgtype.UUID{Bytes: [16]byte{2}, Valid: true})
+	if apiErr == nil || apiErr.Code != http.StatusNotFound {
+		t.Errorf("expected 404 when one product is missing, got %+v", apiErr)
+	}
+}
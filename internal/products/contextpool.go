@@ -0,0 +1,62 @@
+package products
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/playwright-community/playwright-go"
+)
+
+// contextPool hands out isolated browser contexts, one per collect at a time, so concurrent
+// workers don't share cookies, storage or cache. Callers block until a context is free or
+// their own context is done.
+type contextPool struct {
+	contexts []playwright.BrowserContext // todos os contextos criados, fechados em close
+	free     chan playwright.BrowserContext
+}
+
+// newContextPool creates size contexts with newContext; on error the ones already created are closed
+func newContextPool(size int, newContext func() (playwright.BrowserContext, error)) (*contextPool, error) {
+	if size <= 0 {
+		size = 1
+	}
+
+	p := &contextPool{free: make(chan playwright.BrowserContext, size)}
+	for range size {
+		bc, err := newContext()
+		if err != nil {
+			p.close()
+			return nil, fmt.Errorf("could not create browser context: %w", err)
+		}
+		p.contexts = append(p.contexts, bc)
+		p.free <- bc
+	}
+	return p, nil
+}
+
+// Acquire waits for a free browser context. Every successful Acquire must be paired with Release.
+func (p *contextPool) Acquire(ctx context.Context) (playwright.BrowserContext, error) {
+	select {
+	case bc := <-p.free:
+		return bc, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (p *contextPool) Release(bc playwright.BrowserContext) {
+	p.free <- bc
+}
+
+// close closes every context, including the ones still in use, and returns the errors joined
+func (p *contextPool) close() error {
+	var errs []error
+	for _, bc := range p.contexts {
+		if err := bc.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	p.contexts = nil
+	return errors.Join(errs...)
+}
@@ -0,0 +1,80 @@
+package products
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/playwright-community/playwright-go"
+)
+
+// fakeBrowserContext only records whether it was closed
+type fakeBrowserContext struct {
+	playwright.BrowserContext
+	closed bool
+}
+
+func (f *fakeBrowserContext) Close(options ...playwright.BrowserContextCloseOptions) error {
+	f.closed = true
+	return nil
+}
+
+func TestContextPool_AcquireWaitsForRelease(t *testing.T) {
+	var created []*fakeBrowserContext
+	pool, err := newContextPool(2, func() (playwright.BrowserContext, error) {
+		bc := &fakeBrowserContext{}
+		created = append(created, bc)
+		return bc, nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	first, _ := pool.Acquire(context.Background())
+	second, _ := pool.Acquire(context.Background())
+	if first == second {
+		t.Fatalf("expected two distinct contexts")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := pool.Acquire(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the third acquire to wait until the deadline, got %v", err)
+	}
+
+	pool.Release(first)
+	if bc, err := pool.Acquire(context.Background()); err != nil || bc != first {
+		t.Fatalf("expected the released context back, got %v, %v", bc, err)
+	}
+
+	// Contexts still in use are closed too
+	if err := pool.close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for i, bc := range created {
+		if !bc.closed {
+			t.Errorf("context %d was not closed", i)
+		}
+	}
+}
+
+func TestContextPool_CreationErrorClosesCreated(t *testing.T) {
+	var created []*fakeBrowserContext
+	_, err := newContextPool(3, func() (playwright.BrowserContext, error) {
+		if len(created) == 2 {
+			return nil, errors.New("browser closed")
+		}
+		bc := &fakeBrowserContext{}
+		created = append(created, bc)
+		return bc, nil
+	})
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	for i, bc := range created {
+		if !bc.closed {
+			t.Errorf("context %d was not closed", i)
+		}
+	}
+}
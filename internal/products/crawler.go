@@ -1,25 +1,60 @@
 package products
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"strings"
 	"sync"
+	"time"
 
+	"github.com/freitasmatheusrn/lifecycle-monitor/pkg/requestid"
 	"github.com/playwright-community/playwright-go"
+	"go.uber.org/zap"
 )
 
 type Crawler struct {
-	pw         *playwright.Playwright
-	browser    playwright.Browser
-	baseURL    string
-	mu         sync.Mutex
-	isRunning  bool
+	pw               *playwright.Playwright
+	browser          playwright.Browser
+	contexts         *contextPool // contextos isolados do browser, um por coleta em andamento
+	contextPoolSize  int
+	baseURL          string
+	mu               sync.Mutex
+	isRunning        bool
+	stats            selectorStats
+	counters         crawlCounters   // coletas com sucesso e com falha, para o health check
+	pages            *pageLimiter    // limita paginas abertas ao mesmo tempo, independente do numero de workers
+	capture          *FailureCapture // screenshot e HTML das paginas cuja extracao falhou (nil desativa)
+	phaseOutTerminal bool            // extrai o substituto tambem de produtos em phase out
+	selectors        SelectorConfig
+	crawlTimeout     time.Duration // limite de cada coleta quando o ctx do chamador nao tem deadline (0 desativa)
+	logger           *zap.Logger
+	DebugMode        bool              // loga o diagnostico de cada coleta em Debug e salva screenshot/HTML em /tmp/debug_<codigo>
+	UserAgents       []string          // cada contexto do browser usa um deles, sorteado; vazio mantem o do playwright
+	ExtraHeaders     map[string]string // headers HTTP enviados em toda navegacao
+	MaxRetries       int               // novas tentativas de uma coleta apos falha transitoria (0 desativa)
+	BaseBackoff      time.Duration     // espera antes da primeira nova tentativa, dobrada a cada uma
 }
 
-func NewCrawler(baseURL string) (*Crawler, error) {
+// NewCrawler creates a crawler that keeps at most maxPages browser pages open at once.
+// When capture is not nil, pages whose extraction fails are saved for diagnosis.
+// phaseOutTerminal also extracts the replacement code of phase out products.
+// selectors are the candidates tried for each field; empty fields use the defaults.
+// crawlTimeout bounds each collect whose context has no deadline of its own.
+// contextPoolSize is how many isolated browser contexts (own cookies and storage) are shared by the workers.
+func NewCrawler(baseURL string, maxPages int, capture *FailureCapture, phaseOutTerminal bool, selectors SelectorConfig, crawlTimeout time.Duration, contextPoolSize int, logger *zap.Logger) (*Crawler, error) {
+	if logger == nil {
+		logger = zap.NewNop()
+	}
 	return &Crawler{
-		baseURL: baseURL,
+		baseURL:          baseURL,
+		pages:            newPageLimiter(maxPages),
+		capture:          capture,
+		phaseOutTerminal: phaseOutTerminal,
+		selectors:        selectors.withDefaults(),
+		crawlTimeout:     crawlTimeout,
+		contextPoolSize:  contextPoolSize,
+		logger:           logger,
 	}, nil
 }
 
@@ -44,7 +79,17 @@ func (c *Crawler) Start() error {
 		c.pw.Stop()
 		return fmt.Errorf("could not launch browser: %w", err)
 	}
+
+	contexts, err := newContextPool(c.contextPoolSize, func() (playwright.BrowserContext, error) {
+		return browser.NewContext(c.contextOptions())
+	})
+	if err != nil {
+		browser.Close()
+		c.pw.Stop()
+		return err
+	}
 	c.browser = browser
+	c.contexts = contexts
 	c.isRunning = true
 
 	return nil
@@ -58,6 +103,14 @@ func (c *Crawler) Stop() error {
 		return nil
 	}
 
+	// Contexts first, so their pages are closed before the browser goes away
+	if c.contexts != nil {
+		if err := c.contexts.close(); err != nil {
+			return fmt.Errorf("could not close browser contexts: %w", err)
+		}
+		c.contexts = nil
+	}
+
 	if c.browser != nil {
 		if err := c.browser.Close(); err != nil {
 			return fmt.Errorf("could not close browser: %w", err)
@@ -74,205 +127,140 @@ func (c *Crawler) Stop() error {
 	return nil
 }
 
-func (c *Crawler) Collect(productCode string) (*CrawledData, error) {
+// collectOnce crawls the product page, waiting for a free page slot and browser context first.
+// Returns ctx's error if it is done before a slot frees up. Once the page is open, a ctx without
+// deadline gets the crawl timeout, and a done ctx closes the page so a hung step returns right away.
+func (c *Crawler) collectOnce(ctx context.Context, productCode string) (*CrawledData, error) {
 	c.mu.Lock()
 	if !c.isRunning {
 		c.mu.Unlock()
 		return nil, fmt.Errorf("crawler is not running")
 	}
+	contexts := c.contexts
 	c.mu.Unlock()
 
-	page, err := c.browser.NewPage()
+	if err := c.pages.Acquire(ctx); err != nil {
+		return nil, fmt.Errorf("waiting for a free browser page: %w", err)
+	}
+	defer c.pages.Release()
+
+	browserContext, err := contexts.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("waiting for a free browser context: %w", err)
+	}
+	defer contexts.Release(browserContext)
+
+	// The timeout starts once there is a page, so waiting for a slot or context doesn't count against it
+	if _, ok := ctx.Deadline(); !ok && c.crawlTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.crawlTimeout)
+		defer cancel()
+	}
+
+	page, err := browserContext.NewPage()
 	if err != nil {
 		return nil, fmt.Errorf("could not create page: %w", err)
 	}
 	defer page.Close()
 
+	// Playwright calls don't take a context: closing the page makes a blocked call fail,
+	// and the default timeout keeps its own waits within the deadline
+	stopClosing := context.AfterFunc(ctx, func() { page.Close() })
+	defer stopClosing()
+	if deadline, ok := ctx.Deadline(); ok {
+		page.SetDefaultTimeout(float64(time.Until(deadline).Milliseconds()))
+	}
+
 	url := fmt.Sprintf("%s/%s", c.baseURL, productCode)
 
-	if _, err := page.Goto(url, playwright.PageGotoOptions{
+	response, err := page.Goto(url, playwright.PageGotoOptions{
 		WaitUntil: playwright.WaitUntilStateNetworkidle,
-	}); err != nil {
-		return nil, fmt.Errorf("could not navigate to %s: %w", url, err)
+	})
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, fmt.Errorf("crawl of %s canceled during navigation: %w", productCode, ctxErr)
+		}
+		return nil, transientError{fmt.Errorf("could not navigate to %s: %w", url, err)}
+	}
+	if response != nil && isNotFoundStatus(response.Status()) {
+		return nil, fmt.Errorf("%w: %s (status %d)", ErrProductNotFound, productCode, response.Status())
 	}
 
 	// Wait for product content to load
 	if err := page.WaitForLoadState(playwright.PageWaitForLoadStateOptions{
 		State: playwright.LoadStateNetworkidle,
 	}); err != nil {
-		return nil, fmt.Errorf("timeout waiting for page load: %w", err)
-	}
-
-	// ===== DEBUG START =====
-	fmt.Printf("DEBUG: Requested URL: %s\n", url)
-	fmt.Printf("DEBUG: Final URL after navigation: %s\n", page.URL())
-
-	// Save screenshot to see what the page looks like
-	screenshotPath := fmt.Sprintf("/tmp/debug_%s.png", productCode)
-	if _, err := page.Screenshot(playwright.PageScreenshotOptions{
-		Path:     playwright.String(screenshotPath),
-		FullPage: playwright.Bool(true),
-	}); err != nil {
-		fmt.Printf("DEBUG: Failed to save screenshot: %v\n", err)
-	} else {
-		fmt.Printf("DEBUG: Screenshot saved to %s\n", screenshotPath)
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, fmt.Errorf("crawl of %s canceled waiting for page load: %w", productCode, ctxErr)
+		}
+		return nil, transientError{fmt.Errorf("timeout waiting for page load: %w", err)}
 	}
 
-	// Save HTML to inspect the actual structure
-	debugHTML, _ := page.Content()
-	htmlPath := fmt.Sprintf("/tmp/debug_%s.html", productCode)
-	if err := os.WriteFile(htmlPath, []byte(debugHTML), 0644); err != nil {
-		fmt.Printf("DEBUG: Failed to save HTML: %v\n", err)
-	} else {
-		fmt.Printf("DEBUG: HTML saved to %s (length: %d)\n", htmlPath, len(debugHTML))
+	if c.DebugMode {
+		c.debugPage(page, productCode, url)
 	}
 
-	// Check if main selectors exist
-	count1, _ := page.Locator("p.intro-section__content-headline-details--alternative").Count()
-	count2, _ := page.Locator(".intro-section__content-headline-details p").Count()
-	fmt.Printf("DEBUG: Selector 1 (p.intro-section__content-headline-details--alternative) count: %d\n", count1)
-	fmt.Printf("DEBUG: Selector 2 (.intro-section__content-headline-details p) count: %d\n", count2)
-
-	// Try broader selectors to understand page structure
-	introSection, _ := page.Locator(".intro-section").Count()
-	allIntroP, _ := page.Locator(".intro-section p").Count()
-	fmt.Printf("DEBUG: .intro-section elements: %d\n", introSection)
-	fmt.Printf("DEBUG: .intro-section p elements: %d\n", allIntroP)
-	// ===== DEBUG END =====
-
 	// Extract product data using selectors
-	data := &CrawledData{}
+	data := &CrawledData{Matches: make(map[string]FieldMatch)}
+	reader := playwrightReader{ctx: ctx, page: page}
 
 	// Extract product description from the intro section headline
-	// Wait for the element to be visible (Angular apps need time to render)
-	descElement := page.Locator("p.intro-section__content-headline-details--alternative")
-	if err := descElement.First().WaitFor(playwright.LocatorWaitForOptions{
-		State:   playwright.WaitForSelectorStateVisible,
-		Timeout: playwright.Float(10000),
-	}); err == nil {
-		desc, err := descElement.First().TextContent()
-		if err == nil {
-			data.Description = strings.TrimSpace(desc)
-		}
-	}
-
-	// Fallback: try alternative selector if main one didn't work
-	if data.Description == "" {
-		// Try getting from the headline details div
-		altElement := page.Locator(".intro-section__content-headline-details p")
-		if err := altElement.First().WaitFor(playwright.LocatorWaitForOptions{
-			State:   playwright.WaitForSelectorStateVisible,
-			Timeout: playwright.Float(5000),
-		}); err == nil {
-			desc, err := altElement.First().TextContent()
-			if err == nil {
-				data.Description = strings.TrimSpace(desc)
-			}
-		}
-	}
-
-	// Extract product lifecycle status
-	// The structure is: div.product-metadata-item containing p.product-metadata-item__label with "Product lifecycle"
-	// and the value is in the sibling div.product-metadata-item__label-wrapper > p
-	lifecycleItems := page.Locator("div.product-metadata-item")
-	if count, _ := lifecycleItems.Count(); count > 0 {
-		for i := 0; i < count; i++ {
-			item := lifecycleItems.Nth(i)
-			labelElement := item.Locator("p.product-metadata-item__label")
-			if labelCount, _ := labelElement.Count(); labelCount > 0 {
-				label, err := labelElement.First().TextContent()
-				if err == nil && strings.Contains(strings.ToLower(label), "product lifecycle") {
-					// Found the lifecycle item, now get the value
-					valueElement := item.Locator("div.product-metadata-item__label-wrapper p")
-					if valueCount, _ := valueElement.Count(); valueCount > 0 {
-						value, err := valueElement.First().TextContent()
-						if err == nil {
-							// Clean the text (remove icon text and extra whitespace)
-							data.Status = cleanLifecycleStatus(value)
-							break
-						}
-					}
-				}
-			}
-		}
-	}
+	// Each candidate waits for its element to be visible (Angular apps need time to render)
+	descMatch := extractField(reader, c.selectors.Description)
+	data.Description = descMatch.Value
+	data.Matches[FieldDescription] = descMatch
+	c.stats.record(FieldDescription, descMatch)
+
+	// Extract product lifecycle status from the labeled metadata item
+	statusMatch := extractStatus(reader, c.selectors.Status)
+	data.Status = statusMatch.Value
+	data.Matches[FieldStatus] = statusMatch
+	c.stats.record(FieldStatus, statusMatch)
 
 	// Extract replacement product code if status indicates product is being discontinued
 	// Status values that have successor: "Prod. Cancellation", "End Prod.Lifecycl.", "Prod. Discont."
-	fmt.Printf("DEBUG [ReplacementCode]: Status extracted = '%s'\n", data.Status)
-	fmt.Printf("DEBUG [ReplacementCode]: Status trimmed = '%s'\n", strings.TrimSpace(data.Status))
-	fmt.Printf("DEBUG [ReplacementCode]: Status bytes = %v\n", []byte(strings.TrimSpace(data.Status)))
-
-	statusTrimmed := strings.TrimSpace(data.Status)
-	conditionProdCancellation := statusTrimmed == "Prod. Cancellation"
-	conditionEndLifecycle := statusTrimmed == "End Prod.Lifecycl."
-	conditionProdDiscont := statusTrimmed == "Prod. Discont."
-	fmt.Printf("DEBUG [ReplacementCode]: Condition (Prod. Cancellation): %v\n", conditionProdCancellation)
-	fmt.Printf("DEBUG [ReplacementCode]: Condition (End Prod.Lifecycl.): %v\n", conditionEndLifecycle)
-	fmt.Printf("DEBUG [ReplacementCode]: Condition (Prod. Discont.): %v\n", conditionProdDiscont)
-
-	if conditionProdCancellation || conditionEndLifecycle || conditionProdDiscont {
-		fmt.Printf("DEBUG [ReplacementCode]: Entered replacement code extraction block\n")
-
-		// Check how many elements each selector finds
-		successorCount, _ := page.Locator("sie-ui-richtext .primary-label").Count()
-		fmt.Printf("DEBUG [ReplacementCode]: 'sie-ui-richtext .primary-label' count: %d\n", successorCount)
-
-		// Try alternative selectors to understand the structure
-		alt1, _ := page.Locator("sie-ui-richtext").Count()
-		alt2, _ := page.Locator(".primary-label").Count()
-		alt3, _ := page.Locator("sie-ui-link").Count()
-		alt4, _ := page.Locator("sie-ui-link .primary-label").Count()
-		alt5, _ := page.Locator("[class*='successor']").Count()
-		alt6, _ := page.Locator("[class*='replacement']").Count()
-		fmt.Printf("DEBUG [ReplacementCode]: 'sie-ui-richtext' count: %d\n", alt1)
-		fmt.Printf("DEBUG [ReplacementCode]: '.primary-label' count: %d\n", alt2)
-		fmt.Printf("DEBUG [ReplacementCode]: 'sie-ui-link' count: %d\n", alt3)
-		fmt.Printf("DEBUG [ReplacementCode]: 'sie-ui-link .primary-label' count: %d\n", alt4)
-		fmt.Printf("DEBUG [ReplacementCode]: '[class*=successor]' count: %d\n", alt5)
-		fmt.Printf("DEBUG [ReplacementCode]: '[class*=replacement]' count: %d\n", alt6)
+	// (and "Phase Out Announce" when phase out is configured as terminal)
+	terminal := IsTerminalStatus(strings.TrimSpace(data.Status), c.phaseOutTerminal)
 
+	if terminal {
 		// Look for the successor link in the richtext element
 		// The structure is: sie-ui-richtext containing sie-ui-link with a .primary-label div
-		successorElement := page.Locator("sie-ui-richtext .primary-label")
-		if err := successorElement.First().WaitFor(playwright.LocatorWaitForOptions{
-			State:   playwright.WaitForSelectorStateVisible,
-			Timeout: playwright.Float(5000),
-		}); err == nil {
-			fmt.Printf("DEBUG [ReplacementCode]: WaitFor succeeded, element is visible\n")
-			// Try to get the title attribute first (more reliable)
-			if title, err := successorElement.First().GetAttribute("title"); err == nil && title != "" {
-				fmt.Printf("DEBUG [ReplacementCode]: Got title attribute = '%s'\n", title)
-				data.ReplacementCode = strings.TrimSpace(title)
-			} else {
-				fmt.Printf("DEBUG [ReplacementCode]: Title attribute empty or error, trying TextContent\n")
-				// Fallback to text content
-				if text, err := successorElement.First().TextContent(); err == nil {
-					fmt.Printf("DEBUG [ReplacementCode]: Got text content = '%s'\n", text)
-					data.ReplacementCode = strings.TrimSpace(text)
-				} else {
-					fmt.Printf("DEBUG [ReplacementCode]: TextContent error: %v\n", err)
-				}
-			}
-		} else {
-			fmt.Printf("DEBUG [ReplacementCode]: WaitFor FAILED: %v\n", err)
-
-			// Try to get any text from sie-ui-richtext even if not visible
-			richtextElement := page.Locator("sie-ui-richtext")
-			if richtextCount, _ := richtextElement.Count(); richtextCount > 0 {
-				for i := 0; i < richtextCount; i++ {
-					text, _ := richtextElement.Nth(i).TextContent()
-					html, _ := richtextElement.Nth(i).InnerHTML()
-					fmt.Printf("DEBUG [ReplacementCode]: sie-ui-richtext[%d] text = '%s'\n", i, text)
-					fmt.Printf("DEBUG [ReplacementCode]: sie-ui-richtext[%d] innerHTML = '%s'\n", i, html)
-				}
-			}
-		}
-	} else {
-		fmt.Printf("DEBUG [ReplacementCode]: Status does NOT match cancellation conditions, skipping replacement extraction\n")
+		replacementMatch := extractField(reader, c.selectors.Replacement)
+		data.ReplacementCode = replacementMatch.Value
+		data.Matches[FieldReplacementCode] = replacementMatch
+		c.stats.record(FieldReplacementCode, replacementMatch)
+	}
+
+	// Family and datasheet are informative: a page without them is still a valid crawl
+	familyMatch := extractField(reader, c.selectors.Family)
+	data.Family = familyMatch.Value
+	data.Matches[FieldFamily] = familyMatch
+	c.stats.record(FieldFamily, familyMatch)
+
+	datasheetMatch := extractField(reader, c.selectors.Datasheet)
+	if datasheetMatch.Value != "" {
+		datasheetMatch.Value = resolveLink(page.URL(), datasheetMatch.Value)
 	}
+	data.DatasheetURL = datasheetMatch.Value
+	data.Matches[FieldDatasheetURL] = datasheetMatch
+	c.stats.record(FieldDatasheetURL, datasheetMatch)
 
-	fmt.Printf("DEBUG [ReplacementCode]: Final ReplacementCode = '%s'\n", data.ReplacementCode)
+	// Extraction stops reading once ctx is done; don't mistake that for missing fields
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("crawl of %s canceled during extraction: %w", productCode, err)
+	}
+
+	if c.DebugMode {
+		c.logger.Debug("crawler: replacement code",
+			zap.String("code", productCode),
+			requestid.Field(requestid.FromContext(ctx)),
+			zap.String("status", data.Status),
+			zap.ByteString("status_bytes", []byte(strings.TrimSpace(data.Status))),
+			zap.Bool("terminal", terminal),
+			zap.String("selector", data.Matches[FieldReplacementCode].Selector),
+			zap.String("replacement_code", data.ReplacementCode),
+		)
+	}
 
 	// Get raw HTML for debugging/archival
 	html, err := page.Content()
@@ -282,12 +270,76 @@ func (c *Crawler) Collect(productCode string) (*CrawledData, error) {
 
 	// Validate we got at least the description
 	if data.Description == "" {
-		return nil, fmt.Errorf("could not extract product description for code %s", productCode)
+		// A page without description may just be the site saying the code doesn't exist
+		if reason, ok := c.notFoundReason(page, productCode); ok {
+			return nil, fmt.Errorf("%w: %s (%s)", ErrProductNotFound, productCode, reason)
+		}
+
+		err := fmt.Errorf("could not extract product description for code %s", productCode)
+		if c.capture == nil {
+			return nil, err
+		}
+		saved, captureErr := c.capture.Save(page, productCode, data.RawHTML)
+		if captureErr != nil {
+			return nil, fmt.Errorf("%w (capture failed: %v)", err, captureErr)
+		}
+		return nil, fmt.Errorf("%w (page saved to %s.png/.html)", err, saved)
 	}
 
 	return data, nil
 }
 
+// debugPage logs where the navigation ended and how many elements the main selectors find, and saves
+// a screenshot and the HTML of the page to /tmp/debug_<code>.png/.html. Only used in DebugMode.
+func (c *Crawler) debugPage(page playwright.Page, productCode, url string) {
+	logger := c.logger.With(zap.String("code", productCode))
+
+	// Quantos elementos os seletores de descricao e status configurados encontram
+	var counts []zap.Field
+	for _, candidate := range c.selectors.Description {
+		count, _ := page.Locator(candidate.Selector).Count()
+		counts = append(counts, zap.Int(candidate.Selector, count))
+	}
+	for _, candidate := range c.selectors.Status {
+		count, _ := page.Locator(candidate.Item).Count()
+		counts = append(counts, zap.Int(candidate.Item, count))
+	}
+	logger.Debug("crawler: page loaded",
+		zap.String("requested_url", url),
+		zap.String("final_url", page.URL()),
+		zap.Dict("selector_counts", counts...),
+	)
+
+	screenshotPath := fmt.Sprintf("/tmp/debug_%s.png", productCode)
+	if _, err := page.Screenshot(playwright.PageScreenshotOptions{
+		Path:     playwright.String(screenshotPath),
+		FullPage: playwright.Bool(true),
+	}); err != nil {
+		logger.Debug("crawler: failed to save screenshot", zap.Error(err))
+	} else {
+		logger.Debug("crawler: screenshot saved", zap.String("path", screenshotPath))
+	}
+
+	html, _ := page.Content()
+	htmlPath := fmt.Sprintf("/tmp/debug_%s.html", productCode)
+	if err := os.WriteFile(htmlPath, []byte(html), 0644); err != nil {
+		logger.Debug("crawler: failed to save html", zap.Error(err))
+	} else {
+		logger.Debug("crawler: html saved", zap.String("path", htmlPath), zap.Int("length", len(html)))
+	}
+}
+
+// SelectorStats returns how many times each selector candidate matched per field since start.
+// An empty candidate name counts extractions where no candidate matched.
+func (c *Crawler) SelectorStats() map[string]map[string]int64 {
+	return c.stats.snapshot()
+}
+
+// PageStats returns how many browser pages are open and how many collects are waiting for one
+func (c *Crawler) PageStats() PageStats {
+	return c.pages.Stats()
+}
+
 func cleanLifecycleStatus(status string) string {
 	status = strings.TrimSpace(status)
 	// The status text may contain extra content from icons or tooltips
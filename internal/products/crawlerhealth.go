@@ -0,0 +1,97 @@
+package products
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/playwright-community/playwright-go"
+)
+
+// CrawlerStats are the crawler's counters since start, for monitoring the browser
+type CrawlerStats struct {
+	Running     bool       `json:"running"`
+	TotalCrawls int64      `json:"total_crawls"`
+	Successes   int64      `json:"successes"`
+	Failures    int64      `json:"failures"` // inclui produtos nao encontrados
+	LastSuccess *time.Time `json:"last_success,omitempty"`
+}
+
+// crawlCounters counts the collects (retries included in one) and when the last one succeeded
+type crawlCounters struct {
+	successes   atomic.Int64
+	failures    atomic.Int64
+	lastSuccess atomic.Int64 // UnixNano; zero antes do primeiro sucesso
+}
+
+func (c *crawlCounters) record(err error, at time.Time) {
+	if err != nil {
+		c.failures.Add(1)
+		return
+	}
+	c.successes.Add(1)
+	c.lastSuccess.Store(at.UnixNano())
+}
+
+// Stats returns the crawl counters and whether the browser is started
+func (c *Crawler) Stats() CrawlerStats {
+	c.mu.Lock()
+	running := c.isRunning
+	c.mu.Unlock()
+
+	stats := CrawlerStats{
+		Running:   running,
+		Successes: c.counters.successes.Load(),
+		Failures:  c.counters.failures.Load(),
+	}
+	stats.TotalCrawls = stats.Successes + stats.Failures
+	if last := c.counters.lastSuccess.Load(); last != 0 {
+		at := time.Unix(0, last)
+		stats.LastSuccess = &at
+	}
+	return stats
+}
+
+// HealthCheck opens a blank page in a browser context of its own and closes it, so a dead or
+// hung browser is detected without waiting for the pooled contexts used by the crawls.
+// Returns ctx's error if the browser doesn't answer before ctx is done.
+func (c *Crawler) HealthCheck(ctx context.Context) error {
+	c.mu.Lock()
+	running := c.isRunning
+	browser := c.browser
+	c.mu.Unlock()
+
+	if !running || browser == nil {
+		return errors.New("crawler is not running")
+	}
+	if !browser.IsConnected() {
+		return errors.New("browser is disconnected")
+	}
+
+	// Playwright calls don't take a context: a hung browser is abandoned when ctx is done
+	done := make(chan error, 1)
+	go func() { done <- openBlankPage(browser) }()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return fmt.Errorf("browser did not answer: %w", ctx.Err())
+	}
+}
+
+func openBlankPage(browser playwright.Browser) error {
+	page, err := browser.NewPage()
+	if err != nil {
+		return fmt.Errorf("could not create page: %w", err)
+	}
+	// NewPage cria um contexto so para a pagina; fechar a pagina fecha o contexto
+	defer page.Close()
+
+	if _, err := page.Goto("about:blank"); err != nil {
+		return fmt.Errorf("could not open blank page: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,35 @@
+package products
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCrawlerStats_CountsCollects(t *testing.T) {
+	c := &Crawler{}
+	if stats := c.Stats(); stats.TotalCrawls != 0 || stats.LastSuccess != nil || stats.Running {
+		t.Fatalf("expected empty stats before any crawl, got %+v", stats)
+	}
+
+	at := time.Date(2026, 3, 1, 3, 0, 0, 0, time.UTC)
+	c.counters.record(nil, at)
+	c.counters.record(errors.New("timeout"), at.Add(time.Minute))
+	c.counters.record(ErrProductNotFound, at.Add(2*time.Minute))
+
+	stats := c.Stats()
+	if stats.TotalCrawls != 3 || stats.Successes != 1 || stats.Failures != 2 {
+		t.Errorf("unexpected counters: %+v", stats)
+	}
+	if stats.LastSuccess == nil || !stats.LastSuccess.Equal(at) {
+		t.Errorf("expected the last success at %v, got %v", at, stats.LastSuccess)
+	}
+}
+
+func TestCrawlerHealthCheck_NotRunning(t *testing.T) {
+	c := &Crawler{}
+	if err := c.HealthCheck(context.Background()); err == nil {
+		t.Error("expected a stopped crawler to be unhealthy")
+	}
+}
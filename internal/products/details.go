@@ -0,0 +1,38 @@
+package products
+
+import (
+	"context"
+
+	repo "github.com/freitasmatheusrn/lifecycle-monitor/internal/database/postgres/sqlc"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+)
+
+// saveProductDetails stores the family and datasheet link read from the product page.
+// Fields the page didn't have keep the stored value; failures are only logged, like the
+// lifecycle status update they follow.
+func saveProductDetails(ctx context.Context, q repo.Querier, logger *zap.Logger, code string, data *CrawledData) {
+	if data.Family == "" && data.DatasheetURL == "" {
+		return
+	}
+
+	var family, datasheetURL pgtype.Text
+	if data.Family != "" {
+		family = pgtype.Text{String: data.Family, Valid: true}
+	}
+	if data.DatasheetURL != "" {
+		datasheetURL = pgtype.Text{String: data.DatasheetURL, Valid: true}
+	}
+
+	err := q.UpdateProductDetails(ctx, repo.UpdateProductDetailsParams{
+		Family:       family,
+		DatasheetUrl: datasheetURL,
+		Code:         code,
+	})
+	if err != nil {
+		logger.Warn("failed to update product details",
+			zap.String("code", code),
+			zap.Error(err),
+		)
+	}
+}
@@ -41,10 +41,39 @@ type AddProductsInput struct {
 }
 
 type ListProductsInput struct {
-	Page     int         `query:"page"`
-	PageSize int         `query:"page_size"`
-	Search   string      `query:"search"`
-	AreaID   pgtype.UUID `query:"area_id"`
+	Page            int         `query:"page"`
+	PageSize        int         `query:"page_size"`
+	Search          string      `query:"search"`
+	AreaID          pgtype.UUID `query:"area_id"`
+	LifecycleStatus string      `query:"lifecycle_status"` // active, phase_out or discontinued (StatusFilter*)
+	SortBy          string      `query:"sort_by"`          // code, description, created_at, total_quantity or lifecycle_status
+	SortOrder       string      `query:"sort_order"`       // asc or desc; defaults to created_at desc
+	IncludeSnapshot bool        `query:"include_snapshot"` // loads the latest snapshot of each code in one query
+	Fuzzy           bool        `query:"fuzzy"`            // full-text search ordered by relevance instead of substring matching
+}
+
+type BatchGetProductsInput struct {
+	IDs   []string `json:"ids"`
+	Codes []string `json:"codes"`
+}
+
+// BulkStatusInput manually sets the lifecycle status of several product codes
+type BulkStatusInput struct {
+	Codes  []string    `json:"codes"`
+	Status string      `json:"status"`
+	Reason string      `json:"reason"`
+	UserID pgtype.UUID `json:"-"`
+}
+
+// SnoozeAlertsInput silences lifecycle alerts for a product until the end of the given day
+type SnoozeAlertsInput struct {
+	Until  string      `json:"until"` // YYYY-MM-DD
+	UserID pgtype.UUID `json:"-"`
+}
+
+type ExportProductsInput struct {
+	AreaID pgtype.UUID
+	Search string
 }
 
 // Output DTOs
@@ -59,6 +88,8 @@ type ProductOutput struct {
 	ManufacturerCode string      `json:"manufacturer_code,omitempty"`
 	Quantity         int         `json:"quantity"`
 	ReplacementURL   string      `json:"replacement_url,omitempty"`
+	Family           string      `json:"family,omitempty"`
+	DatasheetURL     string      `json:"datasheet_url,omitempty"`
 	SAPCode          string      `json:"sap_code,omitempty"`
 	Observations     string      `json:"observations,omitempty"`
 	MinQuantity      int         `json:"min_quantity"`
@@ -70,6 +101,12 @@ type ProductOutput struct {
 	QuantityByArea   []AreaQuantity `json:"quantity_by_area,omitempty"`
 }
 
+// ArchivedProductOutput is a removed product, kept with its snapshot history
+type ArchivedProductOutput struct {
+	ProductOutput
+	ArchivedAt time.Time `json:"archived_at"`
+}
+
 type AreaQuantity struct {
 	AreaID   string `json:"area_id"`
 	AreaName string `json:"area_name"`
@@ -82,11 +119,29 @@ type SnapshotOutput struct {
 	Description string      `json:"description"`
 	Status      string      `json:"status"`
 	CollectedAt time.Time   `json:"collected_at"`
+	Source      string      `json:"source,omitempty"`
+	RunID       pgtype.UUID `json:"run_id,omitempty"`
+}
+
+// SnapshotDiff is a change between two consecutive collections of a product. The first collection
+// has no previous one and opens the timeline with empty Previous fields.
+type SnapshotDiff struct {
+	SnapshotID          pgtype.UUID `json:"snapshot_id"`
+	CollectedAt         time.Time   `json:"collected_at"`
+	PreviousCollectedAt *time.Time  `json:"previous_collected_at,omitempty"`
+	Source              string      `json:"source,omitempty"`
+	StatusChanged       bool        `json:"status_changed"`
+	PreviousStatus      string      `json:"previous_status,omitempty"`
+	Status              string      `json:"status"`
+	DescriptionChanged  bool        `json:"description_changed"`
+	PreviousDescription string      `json:"previous_description,omitempty"`
+	Description         string      `json:"description"`
 }
 
 type ProductWithSnapshotOutput struct {
 	Product        ProductOutput   `json:"product"`
 	LatestSnapshot *SnapshotOutput `json:"latest_snapshot,omitempty"`
+	SnapshotError  string          `json:"snapshot_error,omitempty"` // set when the latest snapshot could not be loaded
 }
 
 type PaginatedProductsOutput struct {
@@ -100,6 +155,113 @@ type PaginatedProductsOutput struct {
 	PhaseOutCount     int64                       `json:"phase_out_count"`
 }
 
+type BatchGetProductsOutput struct {
+	Products []ProductWithSnapshotOutput `json:"products"`
+	NotFound []string                    `json:"not_found"`
+}
+
+type CollectProductOutput struct {
+	Product        ProductOutput        `json:"product"`
+	LatestSnapshot *SnapshotOutput      `json:"latest_snapshot,omitempty"`
+	Cached         bool                 `json:"cached"` // true quando a coleta foi ignorada por estar dentro do intervalo minimo
+	Message        string               `json:"message,omitempty"`
+	StatusChange   *CollectStatusChange `json:"status_change,omitempty"` // ausente quando o status nao mudou ou a coleta foi ignorada
+}
+
+// CollectStatusChange is the lifecycle status change caused by a manual collect
+type CollectStatusChange struct {
+	OldStatus    string `json:"old_status"`
+	NewStatus    string `json:"new_status"`
+	AlertSnoozed bool   `json:"alert_snoozed,omitempty"` // a mudanca foi gravada sem notificar (alertas silenciados)
+}
+
+// RefreshProductOutput is the response of POST /products/:id/refresh
+type RefreshProductOutput struct {
+	Snapshot      *SnapshotOutput `json:"snapshot"`
+	StatusChanged bool            `json:"status_changed"`
+	OldStatus     string          `json:"old_status,omitempty"`
+	NewStatus     string          `json:"new_status,omitempty"`
+	AlertSnoozed  bool            `json:"alert_snoozed,omitempty"` // a mudanca foi gravada sem notificar (alertas silenciados)
+}
+
+// InventorySummary is the response of GET /products/summary. Products are counted by code, like
+// the listing; with an area filter every total is restricted to that area.
+type InventorySummary struct {
+	TotalProducts     int64           `json:"total_products"`
+	TotalQuantity     int64           `json:"total_quantity"`
+	ActiveCount       int64           `json:"active_count"`
+	DiscontinuedCount int64           `json:"discontinued_count"`
+	PhaseOutCount     int64           `json:"phase_out_count"`
+	Areas             []AreaInventory `json:"areas"`
+}
+
+// AreaInventory is the share of one area in the inventory summary
+type AreaInventory struct {
+	AreaID        pgtype.UUID `json:"area_id"`
+	AreaName      string      `json:"area_name"`
+	TotalProducts int64       `json:"total_products"`
+	TotalQuantity int64       `json:"total_quantity"`
+}
+
+// ProductComparison is the response of GET /products/compare
+type ProductComparison struct {
+	A               ProductWithSnapshotOutput `json:"a"`
+	B               ProductWithSnapshotOutput `json:"b"`
+	DifferentFields []ComparedField           `json:"different_fields"`
+}
+
+// ComparedField is a field whose value differs between the two compared products
+type ComparedField struct {
+	Field string `json:"field"`
+	A     string `json:"a"`
+	B     string `json:"b"`
+}
+
+type SnoozeOutput struct {
+	Code          string    `json:"code"`
+	SnoozedUntil  time.Time `json:"snoozed_until"`
+	SnoozedStatus string    `json:"snoozed_status,omitempty"` // status reconhecido; transicoes para outros status voltam a alertar
+}
+
+// BulkStatusResult lists the codes whose status was overridden
+type BulkStatusResult struct {
+	Status   string   `json:"status"`
+	Updated  []string `json:"updated"`
+	NotFound []string `json:"not_found,omitempty"`
+}
+
+// ExportVersion identifies the current content of the products export, for HTTP caching
+type ExportVersion struct {
+	ETag         string    // Changes on any product edit, creation or removal and on area renames
+	LastModified time.Time // Latest product change; removals don't move it, so prefer the ETag
+}
+
+type RebuildURLsResult struct {
+	BaseURL string `json:"base_url"`
+	Updated int64  `json:"updated"`
+}
+
+// ReplacementChainOutput is the response of GET /products/:id/replacement-chain
+type ReplacementChainOutput struct {
+	Code        string   `json:"code"`
+	Chain       []string `json:"chain"`                  // sucessores em ordem, do primeiro ao ultimo seguido
+	FinalActive string   `json:"final_active,omitempty"` // vazio quando a cadeia termina sem um sucessor ativo
+}
+
+// PruneSnapshotsResult is the response of POST /admin/snapshots/prune
+type PruneSnapshotsResult struct {
+	KeepPerProduct   int        `json:"keep_per_product"` // zero when snapshot rows weren't deleted
+	DeletedSnapshots int64      `json:"deleted_snapshots"`
+	RawHTMLBefore    *time.Time `json:"raw_html_before,omitempty"` // snapshots collected before it had the HTML dropped
+	ClearedRawHTML   int64      `json:"cleared_raw_html"`
+}
+
+// PruneSnapshotsInput overrides the configured snapshot retention for one manual prune
+type PruneSnapshotsInput struct {
+	KeepPerProduct       *int `json:"keep_per_product"`
+	RawHTMLRetentionDays *int `json:"raw_html_retention_days"`
+}
+
 type AddProductsResult struct {
 	Added    []ProductOutput `json:"added"`
 	Existing []ProductOutput `json:"existing"`
@@ -111,19 +273,122 @@ type FailedProduct struct {
 	Reason string `json:"reason"`
 }
 
+// DeleteProductsResult is the outcome of a bulk delete; Failed has the ids that weren't found
+type DeleteProductsResult struct {
+	Deleted int             `json:"deleted"`
+	Failed  []FailedProduct `json:"failed"`
+}
+
 // Import DTOs
 
 type ImportInput struct {
+	File    io.Reader
+	Format  ImportFormat // Excel when empty
+	AreaID  pgtype.UUID
+	Collect bool // Crawl newly created products right away; when false they are picked up by the scheduler
+	Diff    bool // Include the field-level changes of each updated product in the result
+
+	// What to do with a code that is not in the target area but exists in another one
+	// (CrossAreaCreate, CrossAreaWarn or CrossAreaMove); empty means CrossAreaCreate
+	CrossArea string
+
+	Columns      *ColumnMapping // Column of each field; nil uses the fixed layout (B=Área, C=Descrição, ...)
+	DetectHeader bool           // Without Columns, map the columns by the header names in rows 1/2
+	DryRun       bool           // Validate and report without creating or updating products (ImportFromSpreadsheet only)
+	Atomic       bool           // Write all rows in one transaction, rolled back if any row fails (ImportFromSpreadsheet only)
+}
+
+// Politica para codigos encontrados em outra area durante a importacao
+const (
+	CrossAreaCreate = "create" // cria o produto na area de destino (comportamento original)
+	CrossAreaWarn   = "warn"   // nao cria; apenas reporta a correspondencia
+	CrossAreaMove   = "move"   // move o produto existente para a area de destino e atualiza seus campos
+)
+
+// Acao tomada em uma correspondencia em outra area
+const (
+	CrossAreaActionCreated = "created"
+	CrossAreaActionSkipped = "skipped"
+	CrossAreaActionMoved   = "moved"
+)
+
+type ImportPreviewInput struct {
 	File   io.Reader
+	Format ImportFormat // Excel when empty
 	AreaID pgtype.UUID
+	Limit  int // Number of data rows to preview
+
+	Columns      *ColumnMapping // Same column layout options as ImportInput
+	DetectHeader bool
+}
+
+type ImportPreviewRow struct {
+	Row              int         `json:"row"`
+	AreaName         string      `json:"area_name,omitempty"`
+	AreaID           pgtype.UUID `json:"area_id,omitempty"`
+	AreaResolution   string      `json:"area_resolution"` // "form", "found", "not_found" or "none"
+	Description      string      `json:"description,omitempty"`
+	ManufacturerCode string      `json:"manufacturer_code"`
+	Quantity         int         `json:"quantity"`
+	SAPCode          string      `json:"sap_code,omitempty"`
+	Observations     string      `json:"observations,omitempty"`
+	MinQuantity      int         `json:"min_quantity"`
+	MaxQuantity      int         `json:"max_quantity"`
+	InventoryStatus  string      `json:"inventory_status,omitempty"`
+	Action           string      `json:"action"` // "create" or "update"
+	Warnings         []string    `json:"warnings,omitempty"`
+}
+
+type ImportPreviewResult struct {
+	TotalRows int                `json:"total_rows"` // Data rows found in the whole spreadsheet
+	Rows      []ImportPreviewRow `json:"rows"`
 }
 
 type ImportResult struct {
-	Created  int             `json:"created"`
-	Updated  int             `json:"updated"`
-	Failed   int             `json:"failed"`
-	Errors   []ImportError   `json:"errors,omitempty"`
-	Products []ProductOutput `json:"products,omitempty"`
+	Created   int             `json:"created"`
+	Updated   int             `json:"updated"`   // existing products with at least one field changed
+	Unchanged int             `json:"unchanged"` // existing products whose row had the same values
+	Skipped   int             `json:"skipped"`   // rows not imported because of the cross-area policy
+	Failed    int             `json:"failed"`
+	Errors    []ImportError   `json:"errors,omitempty"`
+	Products  []ProductOutput `json:"products,omitempty"`
+	Changes   []ImportChange  `json:"changes,omitempty"` // only with ImportInput.Diff
+
+	CrossArea []ImportCrossAreaMatch `json:"cross_area,omitempty"` // rows whose code exists only in other areas
+
+	DryRun     bool `json:"dry_run,omitempty"`     // nothing was written; the counts are what the import would do
+	RolledBack bool `json:"rolled_back,omitempty"` // atomic import undone because a row failed; Errors has the row
+}
+
+// ImportCrossAreaMatch is a row whose code was not found in the target area but exists in others
+type ImportCrossAreaMatch struct {
+	Row          int                 `json:"row"`
+	Code         string              `json:"code"`
+	TargetAreaID pgtype.UUID         `json:"target_area_id"`
+	Matches      []ImportAreaProduct `json:"matches"`
+	Action       string              `json:"action"`           // created, skipped or moved
+	Reason       string              `json:"reason,omitempty"` // why a move was not done
+}
+
+// ImportAreaProduct is an existing product with the same code in another area
+type ImportAreaProduct struct {
+	ProductID pgtype.UUID `json:"product_id"`
+	AreaID    pgtype.UUID `json:"area_id"`
+	AreaName  string      `json:"area_name,omitempty"`
+}
+
+// ImportChange lists the fields an import changed on an existing product
+type ImportChange struct {
+	Row       int                 `json:"row"`
+	Code      string              `json:"code"`
+	ProductID pgtype.UUID         `json:"product_id"`
+	Fields    []ImportFieldChange `json:"fields"`
+}
+
+type ImportFieldChange struct {
+	Field string `json:"field"`
+	Old   string `json:"old"`
+	New   string `json:"new"`
 }
 
 type ImportError struct {
@@ -156,6 +421,43 @@ type ProgressEvent struct {
 // ProgressCallback is called for each progress update during product addition
 type ProgressCallback func(event ProgressEvent)
 
+// RecrawlResult summarizes a recrawl of products with unknown lifecycle status
+type RecrawlResult struct {
+	Total        int `json:"total"`
+	Updated      int `json:"updated"`       // status encontrado na coleta
+	StillUnknown int `json:"still_unknown"` // coletado, mas a pagina continua sem status
+	Failed       int `json:"failed"`
+}
+
+// Resultado do self-test do crawler
+const (
+	SelfTestPass = "PASS"
+	SelfTestFail = "FAIL"
+)
+
+// CrawlerSelfTestResult is the outcome of crawling the canary product without persisting it
+type CrawlerSelfTestResult struct {
+	Result          string                `json:"result"` // PASS or FAIL
+	Code            string                `json:"code"`
+	DurationMs      int64                 `json:"duration_ms"`
+	Description     string                `json:"description,omitempty"`
+	LifecycleStatus string                `json:"lifecycle_status,omitempty"`
+	ReplacementCode string                `json:"replacement_code,omitempty"`
+	Matches         map[string]FieldMatch `json:"matches,omitempty"`
+	Failures        []string              `json:"failures,omitempty"` // motivos do FAIL
+	Warnings        []string              `json:"warnings,omitempty"` // campos encontrados so por seletores alternativos
+}
+
+// RecrawlProgressEvent represents an SSE event for recrawl progress
+type RecrawlProgressEvent struct {
+	Type    ProgressEventType `json:"type"`
+	Code    string            `json:"code,omitempty"`
+	Index   int               `json:"index"`
+	Total   int               `json:"total"`
+	Message string            `json:"message,omitempty"`
+	Result  *RecrawlResult    `json:"result,omitempty"`
+}
+
 // Import Progress Event Types
 type ImportProgressEventType string
 
@@ -188,18 +490,41 @@ type ImportProgressCallback func(event ImportProgressEvent)
 
 // Crawler DTOs
 
+// Origem dos snapshots, gravada em product_snapshots.source
+const (
+	SnapshotSourceScheduler  = "scheduler"   // coleta noturna
+	SnapshotSourceManual     = "manual"      // POST /products/:id/collect
+	SnapshotSourceAdd        = "add"         // adicao de produtos
+	SnapshotSourceImport     = "import"      // coleta apos importacao de planilha
+	SnapshotSourceRecrawl    = "recrawl"     // recoleta de produtos sem status
+	SnapshotSourceCollectAll = "collect_all" // WorkerPool.CollectAll
+	SnapshotSourceRetry      = "retry"       // POST /admin/crawl/retry (fila de falhas)
+)
+
 type CrawlerJob struct {
 	ProductID   pgtype.UUID
 	ProductCode string
 	ProductURL  string
-	jobID       string // ID interno para jobs síncronos (SubmitAndWait)
+	Source      string      // origem gravada no snapshot (SnapshotSource*)
+	RunID       pgtype.UUID // execucao do scheduler que gerou o job, quando aplicavel
+	RequestID   string      // X-Request-ID da requisicao que gerou o job, para correlacionar os logs
+	jobID       string      // ID interno para jobs síncronos (SubmitAndWait)
+	skipDelay   bool        // coleta sem a espera anti-deteccao (SubmitAndWait)
+}
+
+// snapshotSource returns the job source as a nullable column value
+func (j CrawlerJob) snapshotSource() pgtype.Text {
+	return pgtype.Text{String: j.Source, Valid: j.Source != ""}
 }
 
 type CrawledData struct {
 	Description     string
 	Status          string
 	ReplacementCode string
+	Family          string // Familia do produto, vazia quando a pagina nao informa
+	DatasheetURL    string // Link absoluto da folha de dados
 	RawHTML         string
+	Matches         map[string]FieldMatch // Selector candidate that produced each field
 }
 
 // LifecycleStatusChange represents a change in product lifecycle status
@@ -207,4 +532,6 @@ type LifecycleStatusChange struct {
 	ProductCode string
 	OldStatus   string
 	NewStatus   string
+	Snoozed     bool                // alertas silenciados para o produto; a mudanca e gravada mas nao notificada
+	Replacement *ReplacementDetails // sucessor do produto, preenchido so para as notificacoes
 }
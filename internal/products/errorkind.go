@@ -0,0 +1,42 @@
+package products
+
+import (
+	"context"
+	"errors"
+
+	"github.com/playwright-community/playwright-go"
+)
+
+// ErrorKind classifies why a crawl failed, so callers can report a reason and decide whether to retry
+type ErrorKind string
+
+const (
+	KindNone       ErrorKind = ""           // coleta sem erro
+	KindTimeout    ErrorKind = "timeout"    // tempo da coleta ou do job esgotado
+	KindNotFound   ErrorKind = "not_found"  // o site nao conhece o codigo
+	KindNavigation ErrorKind = "navigation" // falha ao abrir ou carregar a pagina
+	KindCanceled   ErrorKind = "canceled"   // pool parando ou chamador desistiu; nao e culpa do codigo
+	KindUnknown    ErrorKind = "unknown"
+)
+
+// ErrJobTimeout is returned for a job that didn't finish within the worker pool's JobTimeout
+var ErrJobTimeout = errors.New("crawl job timed out")
+
+// classifyCrawlError returns the kind of a crawl error; KindNone for nil
+func classifyCrawlError(err error) ErrorKind {
+	var transient transientError
+	switch {
+	case err == nil:
+		return KindNone
+	case errors.Is(err, ErrProductNotFound):
+		return KindNotFound
+	case errors.Is(err, ErrJobTimeout), errors.Is(err, context.DeadlineExceeded), errors.Is(err, playwright.ErrTimeout):
+		return KindTimeout
+	case errors.Is(err, context.Canceled):
+		return KindCanceled
+	case errors.As(err, &transient):
+		return KindNavigation
+	default:
+		return KindUnknown
+	}
+}
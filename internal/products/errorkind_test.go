@@ -0,0 +1,33 @@
+package products
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/playwright-community/playwright-go"
+)
+
+func TestClassifyCrawlError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected ErrorKind
+	}{
+		{name: "no error", err: nil, expected: KindNone},
+		{name: "not found", err: fmt.Errorf("%w: 6ES7999 (status 404)", ErrProductNotFound), expected: KindNotFound},
+		{name: "job timeout", err: fmt.Errorf("%w after 1m0s: %w", ErrJobTimeout, context.Canceled), expected: KindTimeout},
+		{name: "crawl timeout", err: fmt.Errorf("crawl of X canceled during navigation: %w", context.DeadlineExceeded), expected: KindTimeout},
+		{name: "playwright timeout", err: transientError{fmt.Errorf("timeout waiting for page load: %w", playwright.ErrTimeout)}, expected: KindTimeout},
+		{name: "pool stopped", err: fmt.Errorf("worker pool stopped: %w", context.Canceled), expected: KindCanceled},
+		{name: "navigation", err: transientError{errors.New("could not navigate")}, expected: KindNavigation},
+		{name: "other", err: errors.New("could not create page"), expected: KindUnknown},
+	}
+
+	for _, tt := range tests {
+		if got := classifyCrawlError(tt.err); got != tt.expected {
+			t.Errorf("%s: expected %q, got %q", tt.name, tt.expected, got)
+		}
+	}
+}
@@ -0,0 +1,191 @@
+package products
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	repo "github.com/freitasmatheusrn/lifecycle-monitor/internal/database/postgres/sqlc"
+	"github.com/freitasmatheusrn/lifecycle-monitor/pkg/rest"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+)
+
+// LifecycleExpectationInput sets what the supplier told us to expect for a product's code.
+// At least one of ExpectedStatus and ExpectedEOL is required.
+type LifecycleExpectationInput struct {
+	ExpectedStatus string      `json:"expected_status"`
+	ExpectedEOL    string      `json:"expected_eol"` // YYYY-MM-DD
+	Notes          string      `json:"notes"`
+	UserID         pgtype.UUID `json:"-"`
+}
+
+type LifecycleExpectationOutput struct {
+	Code           string `json:"code"`
+	ExpectedStatus string `json:"expected_status,omitempty"`
+	ExpectedEOL    string `json:"expected_eol,omitempty"`
+	Notes          string `json:"notes,omitempty"`
+}
+
+// Tipos de divergencia entre o lifecycle esperado e o coletado
+const (
+	DriftStatusAhead    = "status_ahead"      // status coletado mais avancado que o esperado
+	DriftEarlyEndOfLife = "early_end_of_life" // descontinuado antes da data de fim de vida esperada
+	DriftActivePastEOL  = "active_past_eol"   // ainda ativo depois da data de fim de vida esperada
+)
+
+// driftReasons are the messages of each drift kind, translated by the report
+var driftReasons = map[string]string{
+	DriftStatusAhead:    "Status mais avançado que o esperado",
+	DriftEarlyEndOfLife: "Descontinuado antes da data esperada",
+	DriftActivePastEOL:  "Ainda ativo após a data esperada de fim de vida",
+}
+
+// ExpectationDrift is a crawl whose lifecycle diverges from the expectation of its code
+type ExpectationDrift struct {
+	ProductCode    string
+	Kind           string
+	Status         string // status coletado
+	ExpectedStatus string
+	ExpectedEOL    string // YYYY-MM-DD, vazio sem data esperada
+}
+
+// Reason describes the drift in Portuguese, as a message key of the notifications
+func (d ExpectationDrift) Reason() string {
+	return driftReasons[d.Kind]
+}
+
+// statusOrder ranks the lifecycle statuses from active to discontinued
+var statusOrder = map[string]int{
+	StatusActive:       0,
+	StatusPhaseOut:     1,
+	StatusCancellation: 2,
+	StatusEndLifecycle: 3,
+	StatusDiscontinued: 4,
+}
+
+// CheckExpectation compares the crawled status of a code with its expectation at now.
+// Returns nil when they agree or when the status is empty or unknown.
+func CheckExpectation(expectation repo.LifecycleExpectation, status string, now time.Time) *ExpectationDrift {
+	status = strings.TrimSpace(status)
+	rank, known := statusOrder[status]
+	if !known {
+		return nil
+	}
+
+	drift := &ExpectationDrift{
+		ProductCode:    expectation.Code,
+		Status:         status,
+		ExpectedStatus: expectation.ExpectedStatus.String,
+	}
+
+	if expectation.ExpectedEolDate.Valid {
+		eol := expectation.ExpectedEolDate.Time
+		drift.ExpectedEOL = eol.Format("2006-01-02")
+
+		// A data esperada e um dia inteiro: so passou depois do fim dele
+		today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+		switch {
+		case today.Before(eol) && IsTerminalStatus(status, false):
+			drift.Kind = DriftEarlyEndOfLife
+			return drift
+		case today.After(eol) && status == StatusActive:
+			drift.Kind = DriftActivePastEOL
+			return drift
+		}
+	}
+
+	if expected, ok := statusOrder[expectation.ExpectedStatus.String]; ok && rank > expected {
+		drift.Kind = DriftStatusAhead
+		return drift
+	}
+
+	return nil
+}
+
+// SetLifecycleExpectation stores the expected lifecycle of the product's code, replacing the previous one
+func (s *svc) SetLifecycleExpectation(ctx context.Context, productID pgtype.UUID, input LifecycleExpectationInput) (*LifecycleExpectationOutput, *rest.ApiErr) {
+	var expectedStatus pgtype.Text
+	if status := strings.TrimSpace(input.ExpectedStatus); status != "" {
+		if _, ok := statusOrder[status]; !ok {
+			return nil, rest.NewBadRequestError("status esperado invalido")
+		}
+		expectedStatus = pgtype.Text{String: status, Valid: true}
+	}
+
+	var expectedEOL pgtype.Date
+	if eol := strings.TrimSpace(input.ExpectedEOL); eol != "" {
+		day, err := time.Parse("2006-01-02", eol)
+		if err != nil {
+			return nil, rest.NewBadRequestError("data invalida, use o formato AAAA-MM-DD")
+		}
+		expectedEOL = pgtype.Date{Time: day, Valid: true}
+	}
+
+	if !expectedStatus.Valid && !expectedEOL.Valid {
+		return nil, rest.NewBadRequestError("informe o status ou a data de fim de vida esperados")
+	}
+
+	var notes pgtype.Text
+	if n := strings.TrimSpace(input.Notes); n != "" {
+		notes = pgtype.Text{String: n, Valid: true}
+	}
+
+	product, err := s.repo.FindProductByID(ctx, productID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, rest.NewNotFoundError("produto nao encontrado")
+		}
+		return nil, s.handleDBError(err)
+	}
+
+	expectation, err := s.repo.UpsertLifecycleExpectation(ctx, repo.UpsertLifecycleExpectationParams{
+		Code:            product.Code,
+		ExpectedStatus:  expectedStatus,
+		ExpectedEolDate: expectedEOL,
+		Notes:           notes,
+		CreatedBy:       input.UserID,
+	})
+	if err != nil {
+		return nil, s.handleDBError(err)
+	}
+
+	s.logger.Info("lifecycle expectation set",
+		zap.String("code", expectation.Code),
+		zap.String("expected_status", expectation.ExpectedStatus.String),
+		zap.Bool("expected_eol", expectation.ExpectedEolDate.Valid),
+	)
+
+	output := &LifecycleExpectationOutput{
+		Code:           expectation.Code,
+		ExpectedStatus: expectation.ExpectedStatus.String,
+		Notes:          expectation.Notes.String,
+	}
+	if expectation.ExpectedEolDate.Valid {
+		output.ExpectedEOL = expectation.ExpectedEolDate.Time.Format("2006-01-02")
+	}
+	return output, nil
+}
+
+// DeleteLifecycleExpectation removes the expected lifecycle of the product's code, if any
+func (s *svc) DeleteLifecycleExpectation(ctx context.Context, productID pgtype.UUID) *rest.ApiErr {
+	product, err := s.repo.FindProductByID(ctx, productID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return rest.NewNotFoundError("produto nao encontrado")
+		}
+		return s.handleDBError(err)
+	}
+
+	if _, err := s.repo.DeleteLifecycleExpectation(ctx, product.Code); err != nil {
+		return s.handleDBError(err)
+	}
+	return nil
+}
+
+// ListLifecycleExpectations returns every expectation, for the scheduler to compare with its crawls
+func (s *svc) ListLifecycleExpectations(ctx context.Context) ([]repo.LifecycleExpectation, error) {
+	return s.repo.ListLifecycleExpectations(ctx)
+}
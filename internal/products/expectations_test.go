@@ -0,0 +1,54 @@
+package products
+
+import (
+	"testing"
+	"time"
+
+	repo "github.com/freitasmatheusrn/lifecycle-monitor/internal/database/postgres/sqlc"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func TestCheckExpectation(t *testing.T) {
+	now := time.Date(2026, 6, 15, 14, 0, 0, 0, time.UTC)
+	eol := func(day string) pgtype.Date {
+		d, _ := time.Parse("2006-01-02", day)
+		return pgtype.Date{Time: d, Valid: true}
+	}
+	status := func(s string) pgtype.Text { return pgtype.Text{String: s, Valid: true} }
+
+	tests := []struct {
+		name        string
+		expectation repo.LifecycleExpectation
+		crawled     string
+		kind        string // vazio quando nao ha divergencia
+	}{
+		{name: "status as expected", expectation: repo.LifecycleExpectation{ExpectedStatus: status(StatusPhaseOut)}, crawled: StatusPhaseOut},
+		{name: "status behind expected", expectation: repo.LifecycleExpectation{ExpectedStatus: status(StatusPhaseOut)}, crawled: StatusActive},
+		{name: "status ahead of expected", expectation: repo.LifecycleExpectation{ExpectedStatus: status(StatusActive)}, crawled: StatusCancellation, kind: DriftStatusAhead},
+		{name: "discontinued before eol", expectation: repo.LifecycleExpectation{ExpectedEolDate: eol("2027-01-01")}, crawled: StatusDiscontinued, kind: DriftEarlyEndOfLife},
+		{name: "phase out before eol", expectation: repo.LifecycleExpectation{ExpectedEolDate: eol("2027-01-01")}, crawled: StatusPhaseOut},
+		{name: "active on the eol day", expectation: repo.LifecycleExpectation{ExpectedEolDate: eol("2026-06-15")}, crawled: StatusActive},
+		{name: "active past eol", expectation: repo.LifecycleExpectation{ExpectedEolDate: eol("2026-06-14")}, crawled: StatusActive, kind: DriftActivePastEOL},
+		{name: "unknown crawled status", expectation: repo.LifecycleExpectation{ExpectedStatus: status(StatusActive)}, crawled: "Unknown"},
+		{name: "empty crawled status", expectation: repo.LifecycleExpectation{ExpectedEolDate: eol("2026-01-01")}, crawled: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.expectation.Code = "6ES7"
+			drift := CheckExpectation(tt.expectation, tt.crawled, now)
+			if tt.kind == "" {
+				if drift != nil {
+					t.Fatalf("expected no drift, got %+v", drift)
+				}
+				return
+			}
+			if drift == nil || drift.Kind != tt.kind {
+				t.Fatalf("expected %s drift, got %+v", tt.kind, drift)
+			}
+			if drift.ProductCode != "6ES7" || drift.Reason() == "" {
+				t.Errorf("unexpected drift %+v", drift)
+			}
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package products
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"time"
+
+	repo "github.com/freitasmatheusrn/lifecycle-monitor/internal/database/postgres/sqlc"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// utf8BOM leads the CSV export so Excel reads accented descriptions as UTF-8
+const utf8BOM = "\ufeff"
+
+// csvExportHeader are the columns of GET /products/export.csv?layout=full
+var csvExportHeader = []string{
+	"codigo",
+	"descricao",
+	"area",
+	"codigo_sap",
+	"codigo_fabricante",
+	"quantidade",
+	"quantidade_min",
+	"quantidade_max",
+	"status_estoque",
+	"lifecycle_status",
+	"url_substituto",
+	"url",
+	"ultima_coleta",
+	"observacoes",
+}
+
+// csvExportRecord lays out a product in the columns of csvExportHeader
+func csvExportRecord(p ProductWithSnapshotOutput) []string {
+	var collectedAt string
+	if p.LatestSnapshot != nil && !p.LatestSnapshot.CollectedAt.IsZero() {
+		collectedAt = p.LatestSnapshot.CollectedAt.Format(time.RFC3339)
+	}
+
+	return []string{
+		p.Product.Code,
+		p.Product.Description,
+		p.Product.AreaName,
+		p.Product.SAPCode,
+		p.Product.ManufacturerCode,
+		strconv.Itoa(p.Product.Quantity),
+		strconv.Itoa(p.Product.MinQuantity),
+		strconv.Itoa(p.Product.MaxQuantity),
+		p.Product.InventoryStatus,
+		p.Product.LifeCycleStatus,
+		p.Product.ReplacementURL,
+		p.Product.URL,
+		collectedAt,
+		p.Product.Observations,
+	}
+}
+
+// exportColumns are the headers of columns B-J of the products spreadsheet, the layout the import reads
+var exportColumns = []string{
+	"Área",
+	"Descrição",
+	"Código Fabricante",
+	"Qtd (un)",
+	"Código SAP",
+	"Obs.",
+	"MIN (un)",
+	"MAX (un)",
+	"STATUS",
+}
+
+// writeProductsCSV writes the products in the spreadsheet export layout: an empty row 1, the
+// exportColumns header in row 2 starting at column B and one product per row from row 3
+func writeProductsCSV(w io.Writer, products []repo.ListProductsRow) error {
+	if _, err := io.WriteString(w, utf8BOM); err != nil {
+		return err
+	}
+
+	cw := csv.NewWriter(w)
+	cw.Write(make([]string, len(exportColumns)+1))
+	cw.Write(append([]string{""}, exportColumns...))
+
+	for _, p := range products {
+		cw.Write([]string{
+			"",
+			p.AreaName.String,
+			p.Description.String,
+			p.Code,
+			csvQuantity(p.Quantity),
+			p.SapCode.String,
+			computeExportObservations(p),
+			csvQuantity(p.MinQuantity),
+			csvQuantity(p.MaxQuantity),
+			p.InventoryStatus.String,
+		})
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// csvQuantity writes a quantity like the spreadsheet: 0 when set to zero, blank when never informed
+func csvQuantity(qty pgtype.Int4) string {
+	if !qty.Valid {
+		return ""
+	}
+	return strconv.Itoa(int(qty.Int32))
+}
@@ -0,0 +1,104 @@
+package products
+
+import (
+	"bytes"
+	"encoding/csv"
+	"testing"
+	"time"
+
+	repo "github.com/freitasmatheusrn/lifecycle-monitor/internal/database/postgres/sqlc"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func TestCSVExportRecord(t *testing.T) {
+	product := ProductWithSnapshotOutput{
+		Product: ProductOutput{
+			Code:            "6ES7 214-1AG40-0XB0",
+			Description:     `CPU 1214C, DC/DC/DC "compacta"`,
+			AreaName:        "Cutter",
+			Quantity:        2,
+			LifeCycleStatus: StatusDiscontinued,
+			ReplacementURL:  "https://example.com/6ES7 214-1AG50-0XB0",
+		},
+		LatestSnapshot: &SnapshotOutput{CollectedAt: time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)},
+	}
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Write(csvExportHeader)
+	w.Write(csvExportRecord(product))
+	w.Write(csvExportRecord(ProductWithSnapshotOutput{Product: ProductOutput{Code: "6ES7"}}))
+	w.Flush()
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("export should be valid CSV: %v", err)
+	}
+	if len(records) != 3 {
+		t.Fatalf("expected header and 2 rows, got %d", len(records))
+	}
+
+	row := make(map[string]string)
+	for i, column := range records[0] {
+		row[column] = records[1][i]
+	}
+	if row["descricao"] != product.Product.Description {
+		t.Errorf("description should survive quoting, got %q", row["descricao"])
+	}
+	if row["lifecycle_status"] != StatusDiscontinued || row["url_substituto"] != product.Product.ReplacementURL {
+		t.Errorf("expected lifecycle and replacement columns, got %v", row)
+	}
+	if row["quantidade"] != "2" || row["ultima_coleta"] != "2026-03-01T10:00:00Z" {
+		t.Errorf("unexpected quantity or collect time, got %v", row)
+	}
+	if records[2][12] != "" {
+		t.Errorf("product without snapshot should have an empty last collect, got %q", records[2][12])
+	}
+}
+
+func TestWriteProductsCSV_ImportsBack(t *testing.T) {
+	products := []repo.ListProductsRow{
+		{
+			Code:            "6ES7214-1AG40-0XB0",
+			AreaName:        pgtype.Text{String: "Cutter", Valid: true},
+			Description:     pgtype.Text{String: "CPU 1214C, DC/DC/DC", Valid: true},
+			Quantity:        pgtype.Int4{Int32: 0, Valid: true},
+			MinQuantity:     pgtype.Int4{Int32: 2, Valid: true},
+			LifecycleStatus: pgtype.Text{String: "Prod. Discont.", Valid: true},
+			ReplacementUrl:  pgtype.Text{String: "6ES7214-1AG50-0XB0", Valid: true},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := writeProductsCSV(&buf, products); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The import reads the file back with the same row and column layout as the spreadsheet
+	rows, apiErr := readCSVRows(&buf)
+	if apiErr != nil {
+		t.Fatalf("unexpected error reading back: %v", apiErr.Message)
+	}
+	if len(rows) != 3 {
+		t.Fatalf("expected empty row, header and 1 product, got %q", rows)
+	}
+	if got := importRowValue(rows[1], 1); got != "Área" {
+		t.Errorf("expected header to start at column B, got %q", got)
+	}
+
+	row := rows[2]
+	expected := map[int]string{
+		1: "Cutter",
+		2: "CPU 1214C, DC/DC/DC",
+		3: "6ES7214-1AG40-0XB0",
+		4: "0", // zero is written
+		6: "Produto obsoleto, substituir por 6ES7214-1AG50-0XB0",
+		7: "2",
+		8: "", // never informed stays blank
+	}
+	for col, value := range expected {
+		if got := importRowValue(row, col); got != value {
+			t.Errorf("column %c: expected %q, got %q", 'A'+col, value, got)
+		}
+	}
+}
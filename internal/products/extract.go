@@ -0,0 +1,259 @@
+package products
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/playwright-community/playwright-go"
+)
+
+// SelectorCandidate is one way of reading a field from the product page.
+// Candidates are tried in order and the first non-empty value wins.
+type SelectorCandidate struct {
+	Name       string  `json:"name"`       // Identifies the candidate in stats and logs (e.g. "description.headline")
+	Selector   string  `json:"selector"`   // CSS selector; the first matching element is used
+	Attribute  string  `json:"attribute"`  // Attribute to read instead of the text content (empty reads the text)
+	Timeout    float64 `json:"timeout_ms"` // Milliseconds to wait for the element to become visible
+	Confidence float64 `json:"confidence"` // How much we trust this candidate (1 for the primary selector)
+}
+
+// StatusCandidate is one way of reading the lifecycle status: among the elements matching Item,
+// the one whose Label contains LabelText (case-insensitive) holds the status in Value
+type StatusCandidate struct {
+	Name       string  `json:"name"`
+	Item       string  `json:"item"`       // CSS selector of each metadata item
+	Label      string  `json:"label"`      // CSS selector of the label, inside the item
+	LabelText  string  `json:"label_text"` // Text identifying the lifecycle item
+	Value      string  `json:"value"`      // CSS selector of the status, inside the item
+	Confidence float64 `json:"confidence"`
+}
+
+// FieldMatch is the value extracted for a field and the candidate that produced it
+type FieldMatch struct {
+	Value      string  `json:"value"`
+	Selector   string  `json:"selector"` // Name of the matching candidate, empty when none matched
+	Confidence float64 `json:"confidence"`
+	Fallback   bool    `json:"fallback"` // true when a candidate other than the first matched
+}
+
+// Campos extraidos via lista de candidatos
+const (
+	FieldDescription     = "description"
+	FieldStatus          = "status"
+	FieldReplacementCode = "replacement_code"
+	FieldFamily          = "family"
+	FieldDatasheetURL    = "datasheet_url"
+)
+
+// Candidatos padrao, em ordem de preferencia
+var (
+	descriptionCandidates = []SelectorCandidate{
+		{Name: "description.headline", Selector: "p.intro-section__content-headline-details--alternative", Timeout: 10000, Confidence: 1},
+		{Name: "description.headline-details", Selector: ".intro-section__content-headline-details p", Timeout: 5000, Confidence: 0.8},
+		{Name: "description.intro-section", Selector: ".intro-section p", Timeout: 2000, Confidence: 0.5},
+	}
+
+	// The structure is: div.product-metadata-item containing p.product-metadata-item__label with "Product lifecycle"
+	// and the value is in the sibling div.product-metadata-item__label-wrapper > p
+	statusCandidates = []StatusCandidate{
+		{Name: "status.metadata-item", Item: "div.product-metadata-item", Label: "p.product-metadata-item__label", LabelText: "product lifecycle", Value: "div.product-metadata-item__label-wrapper p", Confidence: 1},
+	}
+
+	replacementCandidates = []SelectorCandidate{
+		{Name: "replacement.richtext-title", Selector: "sie-ui-richtext .primary-label", Attribute: "title", Timeout: 5000, Confidence: 1},
+		{Name: "replacement.richtext-text", Selector: "sie-ui-richtext .primary-label", Timeout: 1000, Confidence: 0.9},
+		{Name: "replacement.link-text", Selector: "sie-ui-link .primary-label", Timeout: 1000, Confidence: 0.6},
+	}
+
+	// Family and datasheet are optional on the page, so the waits are short
+	familyCandidates = []SelectorCandidate{
+		{Name: "family.breadcrumb", Selector: "sie-ui-breadcrumb li:nth-last-child(2)", Timeout: 2000, Confidence: 1},
+		{Name: "family.meta", Selector: "meta[name='product-family']", Attribute: "content", Timeout: 500, Confidence: 0.8},
+	}
+
+	datasheetCandidates = []SelectorCandidate{
+		{Name: "datasheet.link", Selector: "a[href*='datasheet']", Attribute: "href", Timeout: 2000, Confidence: 1},
+		{Name: "datasheet.pdf", Selector: "a[href$='.pdf']", Attribute: "href", Timeout: 500, Confidence: 0.6},
+	}
+
+	// Only checked when the description is missing, to tell an unknown code from a broken page
+	notFoundMarkers = []string{
+		"sie-ui-error-page",
+		".error-page",
+		".search-results__no-results",
+	}
+)
+
+// elementReader abstracts the page so extraction doesn't depend on a live browser
+type elementReader interface {
+	// ReadFirst returns the attribute (or text when attribute is empty) of the first element
+	// matching selector, waiting up to timeout ms for it to be visible
+	ReadFirst(selector, attribute string, timeout float64) (string, bool)
+	// ReadLabeled returns the text of value inside the first item whose label contains labelText
+	ReadLabeled(item, label, labelText, value string) (string, bool)
+}
+
+// extractField tries each candidate in order and returns the first non-empty value
+func extractField(page elementReader, candidates []SelectorCandidate) FieldMatch {
+	for i, candidate := range candidates {
+		value, ok := page.ReadFirst(candidate.Selector, candidate.Attribute, candidate.Timeout)
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+		return FieldMatch{
+			Value:      value,
+			Selector:   candidate.Name,
+			Confidence: candidate.Confidence,
+			Fallback:   i > 0,
+		}
+	}
+	return FieldMatch{}
+}
+
+// extractStatus tries each status candidate in order and returns the first non-empty status
+func extractStatus(page elementReader, candidates []StatusCandidate) FieldMatch {
+	for i, candidate := range candidates {
+		value, ok := page.ReadLabeled(candidate.Item, candidate.Label, candidate.LabelText, candidate.Value)
+		if !ok {
+			continue
+		}
+		// Clean the text (remove icon text and extra whitespace)
+		value = cleanLifecycleStatus(value)
+		if value == "" {
+			continue
+		}
+		return FieldMatch{
+			Value:      value,
+			Selector:   candidate.Name,
+			Confidence: candidate.Confidence,
+			Fallback:   i > 0,
+		}
+	}
+	return FieldMatch{}
+}
+
+// resolveLink makes a link read from the page absolute, relative to the page URL.
+// Returns the value unchanged when either can't be parsed.
+func resolveLink(pageURL, link string) string {
+	base, err := url.Parse(pageURL)
+	if err != nil {
+		return link
+	}
+	ref, err := url.Parse(link)
+	if err != nil {
+		return link
+	}
+	return base.ResolveReference(ref).String()
+}
+
+// boundedTimeout caps a wait of timeout ms to what is left until ctx's deadline.
+// Returns 0 when ctx is already done.
+func boundedTimeout(ctx context.Context, timeout float64) float64 {
+	if ctx.Err() != nil {
+		return 0
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		return max(min(timeout, float64(time.Until(deadline).Milliseconds())), 0)
+	}
+	return timeout
+}
+
+// playwrightReader implements elementReader on a playwright page; reads stop once ctx is done
+type playwrightReader struct {
+	ctx  context.Context
+	page playwright.Page
+}
+
+func (r playwrightReader) ReadFirst(selector, attribute string, timeout float64) (string, bool) {
+	timeout = boundedTimeout(r.ctx, timeout)
+	if timeout <= 0 {
+		return "", false
+	}
+
+	element := r.page.Locator(selector).First()
+	if err := element.WaitFor(playwright.LocatorWaitForOptions{
+		State:   playwright.WaitForSelectorStateVisible,
+		Timeout: playwright.Float(timeout),
+	}); err != nil {
+		return "", false
+	}
+
+	var value string
+	var err error
+	if attribute != "" {
+		value, err = element.GetAttribute(attribute)
+	} else {
+		value, err = element.TextContent()
+	}
+	if err != nil {
+		return "", false
+	}
+	return value, true
+}
+
+func (r playwrightReader) ReadLabeled(item, label, labelText, value string) (string, bool) {
+	items := r.page.Locator(item)
+	count, _ := items.Count()
+	for i := 0; i < count && r.ctx.Err() == nil; i++ {
+		labelElement := items.Nth(i).Locator(label)
+		if labelCount, _ := labelElement.Count(); labelCount == 0 {
+			continue
+		}
+		text, err := labelElement.First().TextContent()
+		if err != nil || !strings.Contains(strings.ToLower(text), strings.ToLower(labelText)) {
+			continue
+		}
+
+		// Found the labeled item, now get the value
+		valueElement := items.Nth(i).Locator(value)
+		if valueCount, _ := valueElement.Count(); valueCount == 0 {
+			return "", false
+		}
+		text, err = valueElement.First().TextContent()
+		if err != nil {
+			return "", false
+		}
+		return text, true
+	}
+	return "", false
+}
+
+// selectorStats counts which candidate matched each field, to spot selector drift
+type selectorStats struct {
+	mu     sync.Mutex
+	counts map[string]map[string]int64 // field -> candidate name ("" for no match) -> count
+}
+
+func (s *selectorStats) record(field string, match FieldMatch) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.counts == nil {
+		s.counts = make(map[string]map[string]int64)
+	}
+	if s.counts[field] == nil {
+		s.counts[field] = make(map[string]int64)
+	}
+	s.counts[field][match.Selector]++
+}
+
+func (s *selectorStats) snapshot() map[string]map[string]int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result := make(map[string]map[string]int64, len(s.counts))
+	for field, counts := range s.counts {
+		result[field] = make(map[string]int64, len(counts))
+		for name, count := range counts {
+			result[field][name] = count
+		}
+	}
+	return result
+}
@@ -0,0 +1,217 @@
+package products
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// mockPage serves a static page: elements by selector+attribute and metadata items by item selector.
+// Every selector read is recorded in tried.
+type mockPage struct {
+	elements map[string]string              // "selector@attribute" -> content
+	items    map[string][]map[string]string // item selector -> child selector -> text, per item
+	tried    []string
+}
+
+func (m *mockPage) ReadFirst(selector, attribute string, timeout float64) (string, bool) {
+	m.tried = append(m.tried, selector+"@"+attribute)
+	value, ok := m.elements[selector+"@"+attribute]
+	return value, ok
+}
+
+func (m *mockPage) ReadLabeled(item, label, labelText, value string) (string, bool) {
+	m.tried = append(m.tried, item)
+	for _, children := range m.items[item] {
+		if strings.Contains(strings.ToLower(children[label]), strings.ToLower(labelText)) {
+			text, ok := children[value]
+			return text, ok
+		}
+	}
+	return "", false
+}
+
+func TestExtractField_PrimaryMatch(t *testing.T) {
+	reader := &mockPage{elements: map[string]string{
+		"p.intro-section__content-headline-details--alternative@": "  CPU 1214C  ",
+		".intro-section p@": "other",
+	}}
+
+	match := extractField(reader, descriptionCandidates)
+
+	if match.Value != "CPU 1214C" {
+		t.Errorf("expected trimmed primary value, got %q", match.Value)
+	}
+	if match.Selector != "description.headline" || match.Fallback || match.Confidence != 1 {
+		t.Errorf("expected primary candidate match, got %+v", match)
+	}
+}
+
+func TestExtractField_SkipsEmptyAndUsesFallback(t *testing.T) {
+	reader := &mockPage{elements: map[string]string{
+		"sie-ui-richtext .primary-label@title": "   ",
+		"sie-ui-link .primary-label@":          "6ES7214-1AG40-0XB0",
+	}}
+
+	match := extractField(reader, replacementCandidates)
+
+	if match.Value != "6ES7214-1AG40-0XB0" {
+		t.Errorf("expected fallback value, got %q", match.Value)
+	}
+	if match.Selector != "replacement.link-text" || !match.Fallback {
+		t.Errorf("expected fallback candidate match, got %+v", match)
+	}
+}
+
+func TestExtractField_NoMatch(t *testing.T) {
+	match := extractField(&mockPage{}, descriptionCandidates)
+
+	if match != (FieldMatch{}) {
+		t.Errorf("expected empty match, got %+v", match)
+	}
+}
+
+func TestExtractField_TriesConfiguredCandidatesInOrder(t *testing.T) {
+	selectors := SelectorConfig{Description: []SelectorCandidate{
+		{Selector: "h1.product-title"},
+		{Selector: "meta[name=description]", Attribute: "content"},
+		{Selector: ".product-summary"},
+	}}.withDefaults()
+	page := &mockPage{elements: map[string]string{
+		"meta[name=description]@content": "CPU 1214C",
+		".product-summary@":              "not reached",
+	}}
+
+	match := extractField(page, selectors.Description)
+
+	if match.Value != "CPU 1214C" || match.Selector != "description.2" || !match.Fallback {
+		t.Errorf("expected the second configured candidate to match, got %+v", match)
+	}
+	expected := []string{"h1.product-title@", "meta[name=description]@content"}
+	if strings.Join(page.tried, ",") != strings.Join(expected, ",") {
+		t.Errorf("expected candidates %v to be tried, got %v", expected, page.tried)
+	}
+}
+
+func TestExtractStatus_FallsBackToNextCandidate(t *testing.T) {
+	candidates := []StatusCandidate{
+		statusCandidates[0],
+		{Name: "status.spec-table", Item: "tr.spec-row", Label: "th", LabelText: "lifecycle", Value: "td", Confidence: 0.7},
+	}
+	page := &mockPage{items: map[string][]map[string]string{
+		"tr.spec-row": {
+			{"th": "Weight", "td": "0.4 kg"},
+			{"th": "Product Lifecycle (PLM)", "td": "Prod. Discont.\n  info"},
+		},
+	}}
+
+	match := extractStatus(page, candidates)
+
+	if match.Value != StatusDiscontinued || match.Selector != "status.spec-table" || !match.Fallback {
+		t.Errorf("expected the spec table candidate to match, got %+v", match)
+	}
+	if len(page.tried) != 2 || page.tried[0] != "div.product-metadata-item" {
+		t.Errorf("expected both status candidates to be tried, got %v", page.tried)
+	}
+}
+
+func TestExtractStatus_DefaultMarkup(t *testing.T) {
+	page := &mockPage{items: map[string][]map[string]string{
+		"div.product-metadata-item": {
+			{"p.product-metadata-item__label": "Product Lifecycle (PLM)", "div.product-metadata-item__label-wrapper p": " Active Product "},
+		},
+	}}
+
+	match := extractStatus(page, DefaultSelectorConfig().Status)
+
+	if match.Value != StatusActive || match.Selector != "status.metadata-item" || match.Fallback {
+		t.Errorf("expected the default status candidate to match, got %+v", match)
+	}
+}
+
+func TestExtractField_DatasheetFallsBackToPDFLink(t *testing.T) {
+	reader := &mockPage{elements: map[string]string{
+		"a[href$='.pdf']@href": "/files/6ES7214.pdf",
+	}}
+
+	match := extractField(reader, datasheetCandidates)
+
+	if match.Selector != "datasheet.pdf" || !match.Fallback {
+		t.Fatalf("expected the pdf link candidate, got %+v", match)
+	}
+	if got := resolveLink("https://mall.industry.siemens.com/mall/en/br/Catalog/Product/6ES7214", match.Value); got != "https://mall.industry.siemens.com/files/6ES7214.pdf" {
+		t.Errorf("expected the link resolved against the page, got %q", got)
+	}
+	if got := resolveLink("https://mall.industry.siemens.com/a", "https://cdn.example.com/ds.pdf"); got != "https://cdn.example.com/ds.pdf" {
+		t.Errorf("expected an absolute link unchanged, got %q", got)
+	}
+}
+
+func TestLoadSelectorConfig(t *testing.T) {
+	config, err := LoadSelectorConfig("")
+	if err != nil || len(config.Description) != len(descriptionCandidates) {
+		t.Fatalf("expected defaults without a file, got %+v, %v", config, err)
+	}
+
+	path := filepath.Join(t.TempDir(), "selectors.json")
+	content := `{"replacement": [{"selector": "a.successor", "attribute": "data-code"}]}`
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	config, err = LoadSelectorConfig(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(config.Replacement) != 1 || config.Replacement[0].Name != "replacement_code.1" || config.Replacement[0].Timeout != defaultSelectorTimeout {
+		t.Errorf("expected the configured replacement candidate with defaults filled, got %+v", config.Replacement)
+	}
+	if len(config.Description) != len(descriptionCandidates) || len(config.Status) != len(statusCandidates) ||
+		len(config.Family) != len(familyCandidates) || len(config.Datasheet) != len(datasheetCandidates) {
+		t.Errorf("expected fields absent from the file to keep the defaults, got %+v", config)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"status": [{"item": "div.meta"}]}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadSelectorConfig(path); err == nil {
+		t.Error("expected an incomplete status candidate to be rejected")
+	}
+}
+
+func TestSelectorStats_Snapshot(t *testing.T) {
+	var stats selectorStats
+	stats.record(FieldDescription, FieldMatch{Selector: "description.headline"})
+	stats.record(FieldDescription, FieldMatch{Selector: "description.headline"})
+	stats.record(FieldDescription, FieldMatch{})
+
+	snapshot := stats.snapshot()
+	if snapshot[FieldDescription]["description.headline"] != 2 || snapshot[FieldDescription][""] != 1 {
+		t.Errorf("unexpected stats: %v", snapshot)
+	}
+
+	snapshot[FieldDescription]["description.headline"] = 100
+	if stats.snapshot()[FieldDescription]["description.headline"] != 2 {
+		t.Error("expected snapshot to be a copy")
+	}
+}
+
+func TestBoundedTimeout(t *testing.T) {
+	if got := boundedTimeout(context.Background(), 5000); got != 5000 {
+		t.Errorf("expected the timeout unchanged without deadline, got %v", got)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if got := boundedTimeout(ctx, 5000); got > 1000 || got <= 0 {
+		t.Errorf("expected the timeout capped to the deadline, got %v", got)
+	}
+
+	cancel()
+	if got := boundedTimeout(ctx, 5000); got != 0 {
+		t.Errorf("expected 0 once ctx is done, got %v", got)
+	}
+}
@@ -1,9 +1,15 @@
 package products
 
 import (
+	"encoding/csv"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"math"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/freitasmatheusrn/lifecycle-monitor/internal/user"
 	"github.com/freitasmatheusrn/lifecycle-monitor/pkg/htmx"
@@ -14,11 +20,23 @@ import (
 )
 
 type Handler struct {
-	service Service
+	service                 Service
+	batchGetMaxSize         int
+	canaryCode              string            // produto usado pelo self-test do crawler quando nenhum codigo e informado
+	strictPaginationDefault bool              // rejeita page/page_size invalidos em vez de corrigi-los (sobrescrito por X-Strict-Pagination)
+	imports                 *ImportArchive    // arquivos de importacao guardados para replay (nil desativa)
+	snapshotRetention       SnapshotRetention // limites usados pelo prune manual quando o corpo nao informa
 }
 
-func NewHandler(service Service) *Handler {
-	return &Handler{service: service}
+func NewHandler(service Service, batchGetMaxSize int, canaryCode string, strictPagination bool, imports *ImportArchive, snapshotRetention SnapshotRetention) *Handler {
+	return &Handler{
+		service:                 service,
+		batchGetMaxSize:         batchGetMaxSize,
+		canaryCode:              canaryCode,
+		strictPaginationDefault: strictPagination,
+		imports:                 imports,
+		snapshotRetention:       snapshotRetention,
+	}
 }
 
 // CreateProduct handles POST /products
@@ -78,7 +96,7 @@ func (h *Handler) UpdateProduct(c echo.Context) error {
 }
 
 // DeleteProduct handles DELETE /products/:id
-// Deletes a product from the system
+// Archives a product: it leaves the listings but its snapshot history is kept
 func (h *Handler) DeleteProduct(c echo.Context) error {
 	_, err := user.GetCurrentUser(c)
 	if err != nil {
@@ -106,13 +124,20 @@ func (h *Handler) DeleteProduct(c echo.Context) error {
 }
 
 // ListProducts handles GET /products
-// Returns paginated list of all products with optional search and area filter
+// Returns paginated list of all products with optional search, area and lifecycle_status filters
 func (h *Handler) ListProducts(c echo.Context) error {
 	_, err := user.GetCurrentUser(c)
 	if err != nil {
 		return rest.NewUnauthorizedRequestError("usuario nao autenticado")
 	}
 
+	// In strict mode bad pagination is an error instead of being coerced to the defaults
+	if h.strictPagination(c) {
+		if apiErr := validatePagination(c.QueryParam("page"), c.QueryParam("page_size")); apiErr != nil {
+			return apiErr
+		}
+	}
+
 	var input ListProductsInput
 	if err := c.Bind(&input); err != nil {
 		return rest.NewUnprocessableEntity("erro ao processar parametros")
@@ -161,6 +186,35 @@ func (h *Handler) GetProduct(c echo.Context) error {
 	return c.JSON(http.StatusOK, result)
 }
 
+// CompareProducts handles GET /products/compare?a=<id>&b=<id>
+// Returns both products side by side with their latest snapshots and the fields that differ
+func (h *Handler) CompareProducts(c echo.Context) error {
+	_, err := user.GetCurrentUser(c)
+	if err != nil {
+		return rest.NewUnauthorizedRequestError("usuario nao autenticado")
+	}
+
+	rawA, rawB := c.QueryParam("a"), c.QueryParam("b")
+	if rawA == "" || rawB == "" {
+		return rest.NewBadRequestError("parametros a e b sao obrigatorios")
+	}
+	idA, err := parser.PgUUIDFromString(rawA)
+	if err != nil {
+		return rest.NewBadRequestError(fmt.Sprintf("id do produto invalido: %s", rawA))
+	}
+	idB, err := parser.PgUUIDFromString(rawB)
+	if err != nil {
+		return rest.NewBadRequestError(fmt.Sprintf("id do produto invalido: %s", rawB))
+	}
+
+	result, apiErr := h.service.CompareProducts(c.Request().Context(), idA, idB)
+	if apiErr != nil {
+		return apiErr
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
 // GetProductSnapshots handles GET /products/:id/snapshots
 // Returns all historical snapshots for a product
 func (h *Handler) GetProductSnapshots(c echo.Context) error {
@@ -187,235 +241,1454 @@ func (h *Handler) GetProductSnapshots(c echo.Context) error {
 	return c.JSON(http.StatusOK, result)
 }
 
-// AddProductsSSE handles GET /products/add-stream
-// Adds products with real-time progress updates via Server-Sent Events
-func (h *Handler) AddProductsSSE(c echo.Context) error {
+// GetSnapshotHistory handles GET /products/:id/history
+// Returns the status and description changes between consecutive collections, newest first
+func (h *Handler) GetSnapshotHistory(c echo.Context) error {
 	_, err := user.GetCurrentUser(c)
 	if err != nil {
 		return rest.NewUnauthorizedRequestError("usuario nao autenticado")
 	}
 
-	// Get codes from query params
-	codes := c.QueryParams()["codes"]
-	if len(codes) == 0 {
-		return rest.NewBadRequestError("ao menos um codigo de produto e necessario")
+	pgUUID, err := parser.PgUUIDFromString(c.Param("id"))
+	if err != nil {
+		return rest.NewBadRequestError("id do produto invalido")
 	}
 
-	// Get optional area_id from query
-	var areaID pgtype.UUID
-	areaIDStr := c.QueryParam("area_id")
-	if areaIDStr != "" {
-		parsedUUID, err := parser.PgUUIDFromString(areaIDStr)
-		if err == nil {
-			areaID = parsedUUID
-		}
+	result, apiErr := h.service.GetSnapshotDiffs(c.Request().Context(), pgUUID)
+	if apiErr != nil {
+		return apiErr
 	}
 
-	// Set SSE headers
-	c.Response().Header().Set("Content-Type", "text/event-stream")
-	c.Response().Header().Set("Cache-Control", "no-cache")
-	c.Response().Header().Set("Connection", "keep-alive")
-	c.Response().Header().Set("X-Accel-Buffering", "no")
-	c.Response().WriteHeader(http.StatusOK)
+	return c.JSON(http.StatusOK, result)
+}
 
-	// Create a channel for progress events
-	eventChan := make(chan ProgressEvent, 10)
-	done := make(chan struct{})
+// GetReplacementChain handles GET /products/:id/replacement-chain
+// Follows the successors of a product until an Active one
+func (h *Handler) GetReplacementChain(c echo.Context) error {
+	_, err := user.GetCurrentUser(c)
+	if err != nil {
+		return rest.NewUnauthorizedRequestError("usuario nao autenticado")
+	}
 
-	// Progress callback that sends events to the channel
-	onProgress := func(event ProgressEvent) {
-		select {
-		case eventChan <- event:
-		case <-done:
-		}
+	pgUUID, err := parser.PgUUIDFromString(c.Param("id"))
+	if err != nil {
+		return rest.NewBadRequestError("id do produto invalido")
 	}
 
-	// Run product addition in a goroutine
-	go func() {
-		defer close(eventChan)
-		input := AddProductsInput{Codes: codes, AreaID: areaID}
-		h.service.AddProductsWithProgress(c.Request().Context(), input, onProgress)
-	}()
+	product, apiErr := h.service.GetProduct(c.Request().Context(), pgUUID)
+	if apiErr != nil {
+		return apiErr
+	}
 
-	// Stream events to client
-	flusher, ok := c.Response().Writer.(http.Flusher)
-	if !ok {
-		return rest.NewInternalServerError("streaming nao suportado")
+	chain, finalActive, apiErr := h.service.ResolveReplacementChain(c.Request().Context(), product.Product.Code)
+	if apiErr != nil {
+		return apiErr
 	}
 
-	for {
-		select {
-		case event, ok := <-eventChan:
-			if !ok {
-				// Channel closed, we're done
-				return nil
-			}
+	return c.JSON(http.StatusOK, ReplacementChainOutput{
+		Code:        product.Product.Code,
+		Chain:       chain,
+		FinalActive: finalActive,
+	})
+}
 
-			// Serialize event to JSON
-			data, err := json.Marshal(event)
-			if err != nil {
-				continue
-			}
+// ListBelowMinimum handles GET /products/low-stock
+// Returns the products whose quantity in their area is below the minimum
+func (h *Handler) ListBelowMinimum(c echo.Context) error {
+	_, err := user.GetCurrentUser(c)
+	if err != nil {
+		return rest.NewUnauthorizedRequestError("usuario nao autenticado")
+	}
 
-			// Write SSE format
-			fmt.Fprintf(c.Response(), "event: %s\n", event.Type)
-			fmt.Fprintf(c.Response(), "data: %s\n\n", data)
-			flusher.Flush()
+	result, apiErr := h.service.ListBelowMinimum(c.Request().Context())
+	if apiErr != nil {
+		return apiErr
+	}
 
-			// If complete, close the connection
-			if event.Type == ProgressEventComplete {
-				close(done)
-				return nil
-			}
+	return c.JSON(http.StatusOK, result)
+}
 
-		case <-c.Request().Context().Done():
-			// Client disconnected
-			close(done)
-			return nil
+// GetInventorySummary handles GET /products/summary
+// Returns the inventory totals, optionally filtered by area_id like the listing
+func (h *Handler) GetInventorySummary(c echo.Context) error {
+	_, err := user.GetCurrentUser(c)
+	if err != nil {
+		return rest.NewUnauthorizedRequestError("usuario nao autenticado")
+	}
+
+	var areaID pgtype.UUID
+	if areaIDStr := c.QueryParam("area_id"); areaIDStr != "" {
+		areaID, err = parser.PgUUIDFromString(areaIDStr)
+		if err != nil {
+			return rest.NewBadRequestError("id da area invalido")
 		}
 	}
+
+	result, apiErr := h.service.GetInventorySummary(c.Request().Context(), areaID)
+	if apiErr != nil {
+		return apiErr
+	}
+
+	return c.JSON(http.StatusOK, result)
 }
 
-// ImportSpreadsheet handles POST /products/import
-// Imports products from an Excel spreadsheet
-func (h *Handler) ImportSpreadsheet(c echo.Context) error {
+// DeleteProducts handles DELETE /products
+// Archives the products whose ids are sent as a JSON array and reports the ones not found
+func (h *Handler) DeleteProducts(c echo.Context) error {
 	_, err := user.GetCurrentUser(c)
 	if err != nil {
 		return rest.NewUnauthorizedRequestError("usuario nao autenticado")
 	}
 
-	// Get uploaded file
-	file, err := c.FormFile("file")
-	if err != nil {
-		return rest.NewBadRequestError("arquivo nao fornecido")
+	var rawIDs []string
+	if err := c.Bind(&rawIDs); err != nil {
+		return rest.NewUnprocessableEntity("erro ao processar dados")
 	}
 
-	// Get area_id from form
-	var areaID pgtype.UUID
-	areaIDStr := c.FormValue("area_id")
-	if areaIDStr != "" {
-		parsedUUID, err := parser.PgUUIDFromString(areaIDStr)
-		if err == nil {
-			areaID = parsedUUID
-		}
+	if len(rawIDs) == 0 {
+		return rest.NewBadRequestError("informe ao menos um id de produto")
+	}
+	if len(rawIDs) > h.batchGetMaxSize {
+		return rest.NewBadRequestError(fmt.Sprintf("maximo de %d produtos por requisicao", h.batchGetMaxSize))
 	}
 
-	src, err := file.Open()
-	if err != nil {
-		return rest.NewInternalServerError("erro ao abrir arquivo")
+	ids := make([]pgtype.UUID, 0, len(rawIDs))
+	for _, id := range rawIDs {
+		pgUUID, err := parser.PgUUIDFromString(id)
+		if err != nil {
+			return rest.NewBadRequestError(fmt.Sprintf("id do produto invalido: %s", id))
+		}
+		ids = append(ids, pgUUID)
 	}
-	defer src.Close()
 
-	result, apiErr := h.service.ImportFromSpreadsheet(c.Request().Context(), src, areaID)
+	deleted, failed, apiErr := h.service.DeleteProducts(c.Request().Context(), ids)
 	if apiErr != nil {
 		return apiErr
 	}
 
-	return c.JSON(http.StatusCreated, result)
+	htmx.TriggerToast(c, "success", fmt.Sprintf("%d produtos removidos", deleted))
+	return c.JSON(http.StatusOK, DeleteProductsResult{Deleted: deleted, Failed: failed})
 }
 
-// ImportSpreadsheetSSE handles POST /products/import-stream
-// Imports products from an Excel spreadsheet with real-time progress updates via SSE
-func (h *Handler) ImportSpreadsheetSSE(c echo.Context) error {
+// RestoreProduct handles POST /products/:id/restore
+// Brings an archived product back to the listings
+func (h *Handler) RestoreProduct(c echo.Context) error {
 	_, err := user.GetCurrentUser(c)
 	if err != nil {
 		return rest.NewUnauthorizedRequestError("usuario nao autenticado")
 	}
 
-	// Get uploaded file
-	file, err := c.FormFile("file")
+	pgUUID, err := parser.PgUUIDFromString(c.Param("id"))
 	if err != nil {
-		return rest.NewBadRequestError("arquivo nao fornecido")
+		return rest.NewBadRequestError("id do produto invalido")
 	}
 
-	// Get area_id from form
-	var areaID pgtype.UUID
-	areaIDStr := c.FormValue("area_id")
-	if areaIDStr != "" {
-		parsedUUID, err := parser.PgUUIDFromString(areaIDStr)
-		if err == nil {
-			areaID = parsedUUID
-		}
+	result, apiErr := h.service.RestoreProduct(c.Request().Context(), pgUUID)
+	if apiErr != nil {
+		return apiErr
 	}
 
-	src, err := file.Open()
+	htmx.TriggerToast(c, "success", "Produto restaurado com sucesso")
+	return c.JSON(http.StatusOK, result)
+}
+
+// ListArchivedProducts handles GET /products/archived
+// Lists the archived products, most recently archived first
+func (h *Handler) ListArchivedProducts(c echo.Context) error {
+	_, err := user.GetCurrentUser(c)
 	if err != nil {
-		return rest.NewInternalServerError("erro ao abrir arquivo")
+		return rest.NewUnauthorizedRequestError("usuario nao autenticado")
 	}
-	defer src.Close()
 
-	// Set SSE headers
-	c.Response().Header().Set("Content-Type", "text/event-stream")
-	c.Response().Header().Set("Cache-Control", "no-cache")
-	c.Response().Header().Set("Connection", "keep-alive")
-	c.Response().Header().Set("X-Accel-Buffering", "no")
-	c.Response().WriteHeader(http.StatusOK)
+	result, apiErr := h.service.ListArchivedProducts(c.Request().Context())
+	if apiErr != nil {
+		return apiErr
+	}
 
-	// Create a channel for progress events
-	eventChan := make(chan ImportProgressEvent, 10)
-	done := make(chan struct{})
+	return c.JSON(http.StatusOK, result)
+}
 
-	// Progress callback that sends events to the channel
-	onProgress := func(event ImportProgressEvent) {
-		select {
-		case eventChan <- event:
-		case <-done:
-		}
+// BatchGetProducts handles POST /products/batch-get
+// Returns the products matching a list of IDs or codes with their latest snapshot,
+// plus the identifiers that were not found
+func (h *Handler) BatchGetProducts(c echo.Context) error {
+	_, err := user.GetCurrentUser(c)
+	if err != nil {
+		return rest.NewUnauthorizedRequestError("usuario nao autenticado")
 	}
 
-	// Run import in a goroutine
-	go func() {
-		defer close(eventChan)
-		h.service.ImportFromSpreadsheetWithProgress(c.Request().Context(), src, areaID, onProgress)
-	}()
+	var input BatchGetProductsInput
+	if err := c.Bind(&input); err != nil {
+		return rest.NewUnprocessableEntity("erro ao processar dados")
+	}
 
-	// Stream events to client
-	flusher, ok := c.Response().Writer.(http.Flusher)
-	if !ok {
-		return rest.NewInternalServerError("streaming nao suportado")
+	total := len(input.IDs) + len(input.Codes)
+	if total == 0 {
+		return rest.NewBadRequestError("informe ao menos um id ou codigo de produto")
+	}
+	if total > h.batchGetMaxSize {
+		return rest.NewBadRequestError(fmt.Sprintf("maximo de %d produtos por requisicao", h.batchGetMaxSize))
 	}
 
-	for {
-		select {
-		case event, ok := <-eventChan:
-			if !ok {
-				return nil
-			}
+	result, apiErr := h.service.BatchGetProducts(c.Request().Context(), input)
+	if apiErr != nil {
+		return apiErr
+	}
 
-			data, err := json.Marshal(event)
-			if err != nil {
-				continue
-			}
+	return c.JSON(http.StatusOK, result)
+}
 
-			fmt.Fprintf(c.Response(), "event: %s\n", event.Type)
-			fmt.Fprintf(c.Response(), "data: %s\n\n", data)
-			flusher.Flush()
+// BulkOverrideStatus handles POST /products/bulk-status
+// Manually sets the lifecycle status of several codes, e.g. while the crawler extracts it wrong
+func (h *Handler) BulkOverrideStatus(c echo.Context) error {
+	currentUser, err := user.GetCurrentUser(c)
+	if err != nil {
+		return rest.NewUnauthorizedRequestError("usuario nao autenticado")
+	}
 
-			if event.Type == ImportEventComplete {
-				close(done)
-				return nil
-			}
+	var input BulkStatusInput
+	if err := c.Bind(&input); err != nil {
+		return rest.NewUnprocessableEntity("erro ao processar dados")
+	}
 
-		case <-c.Request().Context().Done():
-			close(done)
-			return nil
-		}
+	if len(input.Codes) == 0 {
+		return rest.NewBadRequestError("ao menos um codigo de produto e necessario")
 	}
+	if len(input.Codes) > h.batchGetMaxSize {
+		return rest.NewBadRequestError(fmt.Sprintf("maximo de %d produtos por requisicao", h.batchGetMaxSize))
+	}
+	input.UserID = currentUser.ID
+
+	result, apiErr := h.service.BulkOverrideStatus(c.Request().Context(), input)
+	if apiErr != nil {
+		return apiErr
+	}
+
+	return c.JSON(http.StatusOK, result)
 }
 
-// ExportSpreadsheet handles GET /products/export
-// Exports all products to an Excel spreadsheet for download
-func (h *Handler) ExportSpreadsheet(c echo.Context) error {
+// CollectProduct handles POST /products/:id/collect
+// Crawls the product right away. Recently collected products return the latest snapshot
+// instead, unless force=true is sent
+func (h *Handler) CollectProduct(c echo.Context) error {
 	_, err := user.GetCurrentUser(c)
 	if err != nil {
 		return rest.NewUnauthorizedRequestError("usuario nao autenticado")
 	}
 
-	buf, apiErr := h.service.ExportToSpreadsheet(c.Request().Context())
-	if apiErr != nil {
-		return apiErr
+	productID := c.Param("id")
+	if productID == "" {
+		return rest.NewBadRequestError("id do produto e obrigatorio")
+	}
+
+	pgUUID, err := parser.PgUUIDFromString(productID)
+	if err != nil {
+		return rest.NewBadRequestError("id do produto invalido")
+	}
+
+	force := false
+	if v := c.FormValue("force"); v != "" {
+		force, err = strconv.ParseBool(v)
+		if err != nil {
+			return rest.NewBadRequestError("valor invalido para force")
+		}
+	}
+
+	result, apiErr := h.service.CollectProduct(c.Request().Context(), pgUUID, force)
+	if apiErr != nil {
+		return apiErr
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+// RefreshProduct handles POST /products/:id/refresh
+// Crawls the product now and returns the new snapshot with the status change, if any.
+// Answers 429 while the latest snapshot is within the manual collect cooldown.
+func (h *Handler) RefreshProduct(c echo.Context) error {
+	_, err := user.GetCurrentUser(c)
+	if err != nil {
+		return rest.NewUnauthorizedRequestError("usuario nao autenticado")
+	}
+
+	pgUUID, err := parser.PgUUIDFromString(c.Param("id"))
+	if err != nil {
+		return rest.NewBadRequestError("id do produto invalido")
+	}
+
+	snapshot, change, apiErr := h.service.RefreshProduct(c.Request().Context(), pgUUID)
+	if apiErr != nil {
+		return apiErr
+	}
+
+	result := RefreshProductOutput{Snapshot: snapshot}
+	if change != nil {
+		result.StatusChanged = true
+		result.OldStatus = change.OldStatus
+		result.NewStatus = change.NewStatus
+		result.AlertSnoozed = change.Snoozed
+	}
+	return c.JSON(http.StatusOK, result)
+}
+
+// RebuildProductURLs handles POST /admin/products/rebuild-urls
+// Recomputes every product url from the configured base URL, reporting how many changed
+func (h *Handler) RebuildProductURLs(c echo.Context) error {
+	result, apiErr := h.service.RebuildProductURLs(c.Request().Context())
+	if apiErr != nil {
+		return apiErr
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+// RecrawlUnknownStatus handles POST /admin/products/recrawl-unknown
+// Recrawls products without lifecycle status, streaming progress via Server-Sent Events
+func (h *Handler) RecrawlUnknownStatus(c echo.Context) error {
+	flusher, ok := c.Response().Writer.(http.Flusher)
+	if !ok {
+		return rest.NewInternalServerError("streaming nao suportado")
+	}
+
+	// The stream outlives the server WriteTimeout, so lift the deadline for this response
+	http.NewResponseController(c.Response()).SetWriteDeadline(time.Time{})
+
+	// Set SSE headers
+	c.Response().Header().Set("Content-Type", "text/event-stream")
+	c.Response().Header().Set("Cache-Control", "no-cache")
+	c.Response().Header().Set("Connection", "keep-alive")
+	c.Response().Header().Set("X-Accel-Buffering", "no")
+	c.Response().WriteHeader(http.StatusOK)
+
+	eventChan := make(chan RecrawlProgressEvent, 10)
+	done := make(chan struct{})
+
+	onProgress := func(event RecrawlProgressEvent) {
+		select {
+		case eventChan <- event:
+		case <-done:
+		}
+	}
+
+	go func() {
+		defer close(eventChan)
+		if _, apiErr := h.service.RecrawlUnknownStatus(c.Request().Context(), onProgress); apiErr != nil {
+			onProgress(RecrawlProgressEvent{Type: ProgressEventError, Message: apiErr.Message})
+		}
+	}()
+
+	for {
+		select {
+		case event, ok := <-eventChan:
+			if !ok {
+				return nil
+			}
+
+			data, err := json.Marshal(event)
+			if err != nil {
+				continue
+			}
+
+			fmt.Fprintf(c.Response(), "event: %s\n", event.Type)
+			fmt.Fprintf(c.Response(), "data: %s\n\n", data)
+			flusher.Flush()
+
+			if event.Type == ProgressEventComplete {
+				close(done)
+				return nil
+			}
+
+		case <-c.Request().Context().Done():
+			// Client disconnected
+			close(done)
+			return nil
+		}
+	}
+}
+
+// SnoozeAlerts handles POST /products/:id/snooze
+// Silences lifecycle alerts for the product until the given date
+func (h *Handler) SnoozeAlerts(c echo.Context) error {
+	currentUser, err := user.GetCurrentUser(c)
+	if err != nil {
+		return rest.NewUnauthorizedRequestError("usuario nao autenticado")
+	}
+
+	productID := c.Param("id")
+	if productID == "" {
+		return rest.NewBadRequestError("id do produto e obrigatorio")
+	}
+
+	pgUUID, err := parser.PgUUIDFromString(productID)
+	if err != nil {
+		return rest.NewBadRequestError("id do produto invalido")
+	}
+
+	var input SnoozeAlertsInput
+	if err := c.Bind(&input); err != nil {
+		return rest.NewUnprocessableEntity("erro ao processar dados")
+	}
+	if input.Until == "" {
+		return rest.NewBadRequestError("data limite (until) e obrigatoria")
+	}
+	input.UserID = currentUser.ID
+
+	result, apiErr := h.service.SnoozeAlerts(c.Request().Context(), pgUUID, input)
+	if apiErr != nil {
+		return apiErr
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+// UnsnoozeAlerts handles DELETE /products/:id/snooze
+func (h *Handler) UnsnoozeAlerts(c echo.Context) error {
+	_, err := user.GetCurrentUser(c)
+	if err != nil {
+		return rest.NewUnauthorizedRequestError("usuario nao autenticado")
+	}
+
+	productID := c.Param("id")
+	if productID == "" {
+		return rest.NewBadRequestError("id do produto e obrigatorio")
+	}
+
+	pgUUID, err := parser.PgUUIDFromString(productID)
+	if err != nil {
+		return rest.NewBadRequestError("id do produto invalido")
+	}
+
+	if apiErr := h.service.UnsnoozeAlerts(c.Request().Context(), pgUUID); apiErr != nil {
+		return apiErr
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// SetLifecycleExpectation handles PUT /products/:id/expectation
+func (h *Handler) SetLifecycleExpectation(c echo.Context) error {
+	currentUser, err := user.GetCurrentUser(c)
+	if err != nil {
+		return rest.NewUnauthorizedRequestError("usuario nao autenticado")
+	}
+
+	productID := c.Param("id")
+	if productID == "" {
+		return rest.NewBadRequestError("id do produto e obrigatorio")
+	}
+
+	pgUUID, err := parser.PgUUIDFromString(productID)
+	if err != nil {
+		return rest.NewBadRequestError("id do produto invalido")
+	}
+
+	var input LifecycleExpectationInput
+	if err := c.Bind(&input); err != nil {
+		return rest.NewUnprocessableEntity("erro ao processar dados")
+	}
+	input.UserID = currentUser.ID
+
+	result, apiErr := h.service.SetLifecycleExpectation(c.Request().Context(), pgUUID, input)
+	if apiErr != nil {
+		return apiErr
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+// DeleteLifecycleExpectation handles DELETE /products/:id/expectation
+func (h *Handler) DeleteLifecycleExpectation(c echo.Context) error {
+	_, err := user.GetCurrentUser(c)
+	if err != nil {
+		return rest.NewUnauthorizedRequestError("usuario nao autenticado")
+	}
+
+	productID := c.Param("id")
+	if productID == "" {
+		return rest.NewBadRequestError("id do produto e obrigatorio")
+	}
+
+	pgUUID, err := parser.PgUUIDFromString(productID)
+	if err != nil {
+		return rest.NewBadRequestError("id do produto invalido")
+	}
+
+	if apiErr := h.service.DeleteLifecycleExpectation(c.Request().Context(), pgUUID); apiErr != nil {
+		return apiErr
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// CrawlerStats handles GET /admin/crawler/stats
+func (h *Handler) CrawlerStats(c echo.Context) error {
+	stats, apiErr := h.service.CrawlerStats(c.Request().Context())
+	if apiErr != nil {
+		return apiErr
+	}
+
+	return c.JSON(http.StatusOK, stats)
+}
+
+// RetryFailedCrawls handles POST /admin/crawl/retry
+// Crawls again the codes whose last crawl failed (e.g. after a site outage during the nightly run)
+func (h *Handler) RetryFailedCrawls(c echo.Context) error {
+	result, apiErr := h.service.RetryFailedCrawls(c.Request().Context())
+	if apiErr != nil {
+		return apiErr
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+// GetWorkers handles GET /admin/workers
+func (h *Handler) GetWorkers(c echo.Context) error {
+	return c.JSON(http.StatusOK, h.service.WorkerCount(c.Request().Context()))
+}
+
+// ResizeWorkers handles POST /admin/workers
+// Grows or shrinks the crawl workers without a restart; shrinking lets running crawls finish
+func (h *Handler) ResizeWorkers(c echo.Context) error {
+	var input WorkerCountInput
+	if err := c.Bind(&input); err != nil {
+		return rest.NewUnprocessableEntity("erro ao processar dados")
+	}
+
+	result, apiErr := h.service.ResizeWorkers(c.Request().Context(), input)
+	if apiErr != nil {
+		return apiErr
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+// IngestionStats handles GET /admin/ingestion/stats
+func (h *Handler) IngestionStats(c echo.Context) error {
+	return c.JSON(http.StatusOK, h.service.IngestionStats(c.Request().Context()))
+}
+
+// ListCollectionRuns handles GET /admin/lifecycle/runs and GET /admin/collection-runs
+// Latest scheduled runs with their status change counts, and the products left uncollected by runs that timed out
+func (h *Handler) ListCollectionRuns(c echo.Context) error {
+	limit := defaultCollectionRunsLimit
+	if value := c.QueryParam("limit"); value != "" {
+		if cause, ok := paginationCause("limit", value, maxCollectionRunsLimit); !ok {
+			return rest.NewBadRequestValidationError("parametro limit invalido", []rest.Causes{cause})
+		}
+		limit, _ = strconv.Atoi(value)
+	}
+
+	runs, apiErr := h.service.ListCollectionRuns(c.Request().Context(), limit)
+	if apiErr != nil {
+		return apiErr
+	}
+
+	return c.JSON(http.StatusOK, runs)
+}
+
+// CodeFilter handles GET /admin/crawler/code-filter
+func (h *Handler) CodeFilter(c echo.Context) error {
+	return c.JSON(http.StatusOK, h.service.CodeFilterRules(c.Request().Context()))
+}
+
+// UpdateCodeFilter handles PUT /admin/crawler/code-filter
+// Replaces the include/exclude patterns of the scheduled crawl; a restart goes back to the config
+func (h *Handler) UpdateCodeFilter(c echo.Context) error {
+	var rules CodeFilterRules
+	if err := c.Bind(&rules); err != nil {
+		return rest.NewUnprocessableEntity("erro ao processar dados")
+	}
+
+	result, apiErr := h.service.UpdateCodeFilter(c.Request().Context(), rules)
+	if apiErr != nil {
+		return apiErr
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+// PruneSnapshots handles POST /admin/snapshots/prune
+// Applies the snapshot retention now; the body may override the configured limits
+func (h *Handler) PruneSnapshots(c echo.Context) error {
+	var input PruneSnapshotsInput
+	if err := c.Bind(&input); err != nil {
+		return rest.NewUnprocessableEntity("erro ao processar dados")
+	}
+
+	keepPerProduct, rawHTMLAfter := h.snapshotRetention.KeepPerProduct, h.snapshotRetention.RawHTMLAfter
+	if input.KeepPerProduct != nil {
+		keepPerProduct = *input.KeepPerProduct
+	}
+	if input.RawHTMLRetentionDays != nil {
+		rawHTMLAfter = time.Duration(*input.RawHTMLRetentionDays) * 24 * time.Hour
+	}
+
+	result, apiErr := h.service.PruneSnapshots(c.Request().Context(), keepPerProduct, rawHTMLAfter)
+	if apiErr != nil {
+		return apiErr
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+// CrawlerSelfTest handles GET /admin/crawler/selftest
+// Crawls the canary product (or ?code=) without saving and answers 200 on PASS, 503 on FAIL
+func (h *Handler) CrawlerSelfTest(c echo.Context) error {
+	code := strings.TrimSpace(c.QueryParam("code"))
+	if code == "" {
+		code = h.canaryCode
+	}
+	if code == "" {
+		return rest.NewBadRequestError("codigo do produto e obrigatorio (nenhum produto canario configurado)")
+	}
+
+	result := h.service.CrawlerSelfTest(c.Request().Context(), code)
+	if result.Result != SelfTestPass {
+		return c.JSON(http.StatusServiceUnavailable, result)
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+// AddProductsSSE handles GET /products/add-stream
+// Adds products with real-time progress updates via Server-Sent Events
+func (h *Handler) AddProductsSSE(c echo.Context) error {
+	_, err := user.GetCurrentUser(c)
+	if err != nil {
+		return rest.NewUnauthorizedRequestError("usuario nao autenticado")
+	}
+
+	// Get codes from query params
+	codes := c.QueryParams()["codes"]
+	if len(codes) == 0 {
+		return rest.NewBadRequestError("ao menos um codigo de produto e necessario")
+	}
+
+	// Get optional area_id from query
+	var areaID pgtype.UUID
+	areaIDStr := c.QueryParam("area_id")
+	if areaIDStr != "" {
+		parsedUUID, err := parser.PgUUIDFromString(areaIDStr)
+		if err == nil {
+			areaID = parsedUUID
+		}
+	}
+
+	// The stream outlives the server WriteTimeout, so lift the deadline for this response
+	http.NewResponseController(c.Response()).SetWriteDeadline(time.Time{})
+
+	// Set SSE headers
+	c.Response().Header().Set("Content-Type", "text/event-stream")
+	c.Response().Header().Set("Cache-Control", "no-cache")
+	c.Response().Header().Set("Connection", "keep-alive")
+	c.Response().Header().Set("X-Accel-Buffering", "no")
+	c.Response().WriteHeader(http.StatusOK)
+
+	// Create a channel for progress events
+	eventChan := make(chan ProgressEvent, 10)
+	done := make(chan struct{})
+
+	// Progress callback that sends events to the channel
+	onProgress := func(event ProgressEvent) {
+		select {
+		case eventChan <- event:
+		case <-done:
+		}
+	}
+
+	// Run product addition in a goroutine
+	go func() {
+		defer close(eventChan)
+		input := AddProductsInput{Codes: codes, AreaID: areaID}
+		h.service.AddProductsWithProgress(c.Request().Context(), input, onProgress)
+	}()
+
+	// Stream events to client
+	flusher, ok := c.Response().Writer.(http.Flusher)
+	if !ok {
+		return rest.NewInternalServerError("streaming nao suportado")
+	}
+
+	for {
+		select {
+		case event, ok := <-eventChan:
+			if !ok {
+				// Channel closed, we're done
+				return nil
+			}
+
+			// Serialize event to JSON
+			data, err := json.Marshal(event)
+			if err != nil {
+				continue
+			}
+
+			// Write SSE format
+			fmt.Fprintf(c.Response(), "event: %s\n", event.Type)
+			fmt.Fprintf(c.Response(), "data: %s\n\n", data)
+			flusher.Flush()
+
+			// If complete, close the connection
+			if event.Type == ProgressEventComplete {
+				close(done)
+				return nil
+			}
+
+		case <-c.Request().Context().Done():
+			// Client disconnected
+			close(done)
+			return nil
+		}
+	}
+}
+
+// ImportSpreadsheet handles POST /products/import
+// Imports products from an Excel spreadsheet, or a CSV with the same columns (by .csv extension or text/csv)
+// Send diff=true to get the changed fields of each updated product and cross_area=create|warn|move
+// to choose what happens to codes that only exist in another area. With dry_run=true nothing is
+// written (nor archived) and the result has the counts the import would produce. With atomic=true
+// the rows are saved in one transaction: if any row fails nothing is saved and the response is 422
+// with rolled_back and the row's error.
+func (h *Handler) ImportSpreadsheet(c echo.Context) error {
+	currentUser, err := user.GetCurrentUser(c)
+	if err != nil {
+		return rest.NewUnauthorizedRequestError("usuario nao autenticado")
+	}
+
+	// Get uploaded file
+	file, err := c.FormFile("file")
+	if err != nil {
+		return rest.NewBadRequestError("arquivo nao fornecido")
+	}
+
+	// Get area_id from form
+	var areaID pgtype.UUID
+	areaIDStr := c.FormValue("area_id")
+	if areaIDStr != "" {
+		parsedUUID, err := parser.PgUUIDFromString(areaIDStr)
+		if err == nil {
+			areaID = parsedUUID
+		}
+	}
+
+	diff, apiErr := importDiffParam(c)
+	if apiErr != nil {
+		return apiErr
+	}
+
+	crossArea, apiErr := importCrossAreaParam(c)
+	if apiErr != nil {
+		return apiErr
+	}
+
+	columns, detectHeader, apiErr := importColumnsParams(c)
+	if apiErr != nil {
+		return apiErr
+	}
+
+	var dryRun bool
+	if dryRunStr := c.FormValue("dry_run"); dryRunStr != "" {
+		parsed, err := strconv.ParseBool(dryRunStr)
+		if err != nil {
+			return rest.NewBadRequestError("valor invalido para dry_run")
+		}
+		dryRun = parsed
+	}
+
+	var atomic bool
+	if atomicStr := c.FormValue("atomic"); atomicStr != "" {
+		parsed, err := strconv.ParseBool(atomicStr)
+		if err != nil {
+			return rest.NewBadRequestError("valor invalido para atomic")
+		}
+		atomic = parsed
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		return rest.NewInternalServerError("erro ao abrir arquivo")
+	}
+	defer src.Close()
+
+	// Dry runs write nothing, so there is nothing to replay later
+	format := importFormatOf(file)
+	if !dryRun {
+		importID := h.imports.Archive(file, ImportRecord{
+			Format:       format,
+			AreaID:       areaIDStr,
+			Diff:         diff,
+			CrossArea:    crossArea,
+			Columns:      columns,
+			DetectHeader: detectHeader,
+			UserID:       currentUser.ID.String(),
+		})
+		if importID != "" {
+			c.Response().Header().Set(ImportIDHeader, importID)
+		}
+	}
+
+	input := ImportInput{
+		File:         src,
+		Format:       format,
+		AreaID:       areaID,
+		Diff:         diff,
+		CrossArea:    crossArea,
+		Columns:      columns,
+		DetectHeader: detectHeader,
+		DryRun:       dryRun,
+		Atomic:       atomic,
+	}
+	result, apiErr := h.service.ImportFromSpreadsheet(c.Request().Context(), input)
+	if apiErr != nil {
+		return apiErr
+	}
+
+	if result.RolledBack {
+		return c.JSON(http.StatusUnprocessableEntity, result)
+	}
+	if dryRun {
+		return c.JSON(http.StatusOK, result)
+	}
+	return c.JSON(http.StatusCreated, result)
+}
+
+// importDiffParam reads the optional diff form value of the import endpoints
+func importDiffParam(c echo.Context) (bool, *rest.ApiErr) {
+	diffStr := c.FormValue("diff")
+	if diffStr == "" {
+		return false, nil
+	}
+	diff, err := strconv.ParseBool(diffStr)
+	if err != nil {
+		return false, rest.NewBadRequestError("valor invalido para diff")
+	}
+	return diff, nil
+}
+
+// importCrossAreaParam reads the optional cross_area form value of the import endpoints
+func importCrossAreaParam(c echo.Context) (string, *rest.ApiErr) {
+	switch policy := c.FormValue("cross_area"); policy {
+	case "":
+		return CrossAreaCreate, nil
+	case CrossAreaCreate, CrossAreaWarn, CrossAreaMove:
+		return policy, nil
+	default:
+		return "", rest.NewBadRequestError("valor invalido para cross_area")
+	}
+}
+
+// importColumnsParams reads the optional column layout of the import endpoints: columns, a JSON
+// ColumnMapping like {"code":"A","description":"B"}, or detect_header=true to map the columns by
+// the header names in rows 1/2. Without either the fixed layout is used.
+func importColumnsParams(c echo.Context) (*ColumnMapping, bool, *rest.ApiErr) {
+	var detectHeader bool
+	if detectStr := c.FormValue("detect_header"); detectStr != "" {
+		parsed, err := strconv.ParseBool(detectStr)
+		if err != nil {
+			return nil, false, rest.NewBadRequestError("valor invalido para detect_header")
+		}
+		detectHeader = parsed
+	}
+
+	columnsStr := c.FormValue("columns")
+	if columnsStr == "" {
+		return nil, detectHeader, nil
+	}
+
+	var columns ColumnMapping
+	if err := json.Unmarshal([]byte(columnsStr), &columns); err != nil {
+		return nil, false, rest.NewBadRequestError("valor invalido para columns")
+	}
+	if _, err := columns.layout(); err != nil {
+		return nil, false, rest.NewBadRequestError("mapeamento de colunas invalido: " + err.Error())
+	}
+	return &columns, detectHeader, nil
+}
+
+const (
+	defaultImportPreviewRows = 10
+	maxImportPreviewRows     = 50
+)
+
+// PreviewImport handles POST /products/import/preview
+// Parses the first rows of the spreadsheet (rows, default 10) and returns how each one would
+// be imported, including area resolution, without writing anything
+func (h *Handler) PreviewImport(c echo.Context) error {
+	_, err := user.GetCurrentUser(c)
+	if err != nil {
+		return rest.NewUnauthorizedRequestError("usuario nao autenticado")
+	}
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		return rest.NewBadRequestError("arquivo nao fornecido")
+	}
+
+	var areaID pgtype.UUID
+	if areaIDStr := c.FormValue("area_id"); areaIDStr != "" {
+		parsedUUID, err := parser.PgUUIDFromString(areaIDStr)
+		if err == nil {
+			areaID = parsedUUID
+		}
+	}
+
+	limit := defaultImportPreviewRows
+	if rowsStr := c.FormValue("rows"); rowsStr != "" {
+		parsed, err := strconv.Atoi(rowsStr)
+		if err != nil || parsed < 1 {
+			return rest.NewBadRequestError("valor invalido para rows")
+		}
+		limit = min(parsed, maxImportPreviewRows)
+	}
+
+	columns, detectHeader, apiErr := importColumnsParams(c)
+	if apiErr != nil {
+		return apiErr
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		return rest.NewInternalServerError("erro ao abrir arquivo")
+	}
+	defer src.Close()
+
+	result, apiErr := h.service.PreviewImport(c.Request().Context(), ImportPreviewInput{
+		File:         src,
+		Format:       importFormatOf(file),
+		AreaID:       areaID,
+		Limit:        limit,
+		Columns:      columns,
+		DetectHeader: detectHeader,
+	})
+	if apiErr != nil {
+		return apiErr
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+// ImportSpreadsheetSSE handles POST /products/import-stream
+// Imports products from an Excel spreadsheet (or CSV) with real-time progress updates via SSE
+// Send collect=false to skip the crawl phase and leave new products for the scheduler
+// and diff=true to get the changed fields of each updated product in the complete event.
+// cross_area=create|warn|move chooses what happens to codes that only exist in another area
+func (h *Handler) ImportSpreadsheetSSE(c echo.Context) error {
+	currentUser, err := user.GetCurrentUser(c)
+	if err != nil {
+		return rest.NewUnauthorizedRequestError("usuario nao autenticado")
+	}
+
+	// Get uploaded file
+	file, err := c.FormFile("file")
+	if err != nil {
+		return rest.NewBadRequestError("arquivo nao fornecido")
+	}
+
+	// Get area_id from form
+	var areaID pgtype.UUID
+	areaIDStr := c.FormValue("area_id")
+	if areaIDStr != "" {
+		parsedUUID, err := parser.PgUUIDFromString(areaIDStr)
+		if err == nil {
+			areaID = parsedUUID
+		}
+	}
+
+	// Crawl new products right away unless explicitly disabled
+	collect := true
+	if collectStr := c.FormValue("collect"); collectStr != "" {
+		parsed, err := strconv.ParseBool(collectStr)
+		if err != nil {
+			return rest.NewBadRequestError("valor invalido para collect")
+		}
+		collect = parsed
+	}
+
+	diff, apiErr := importDiffParam(c)
+	if apiErr != nil {
+		return apiErr
+	}
+
+	crossArea, apiErr := importCrossAreaParam(c)
+	if apiErr != nil {
+		return apiErr
+	}
+
+	columns, detectHeader, apiErr := importColumnsParams(c)
+	if apiErr != nil {
+		return apiErr
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		return rest.NewInternalServerError("erro ao abrir arquivo")
+	}
+	defer src.Close()
+
+	format := importFormatOf(file)
+	importID := h.imports.Archive(file, ImportRecord{
+		Format:       format,
+		AreaID:       areaIDStr,
+		Diff:         diff,
+		CrossArea:    crossArea,
+		Columns:      columns,
+		DetectHeader: detectHeader,
+		Collect:      collect,
+		Stream:       true,
+		UserID:       currentUser.ID.String(),
+	})
+	if importID != "" {
+		c.Response().Header().Set(ImportIDHeader, importID)
+	}
+
+	// The stream outlives the server WriteTimeout, so lift the deadline for this response
+	http.NewResponseController(c.Response()).SetWriteDeadline(time.Time{})
+
+	// Set SSE headers
+	c.Response().Header().Set("Content-Type", "text/event-stream")
+	c.Response().Header().Set("Cache-Control", "no-cache")
+	c.Response().Header().Set("Connection", "keep-alive")
+	c.Response().Header().Set("X-Accel-Buffering", "no")
+	c.Response().WriteHeader(http.StatusOK)
+
+	// Create a channel for progress events
+	eventChan := make(chan ImportProgressEvent, 10)
+	done := make(chan struct{})
+
+	// Progress callback that sends events to the channel
+	onProgress := func(event ImportProgressEvent) {
+		select {
+		case eventChan <- event:
+		case <-done:
+		}
+	}
+
+	// Run import in a goroutine
+	go func() {
+		defer close(eventChan)
+		input := ImportInput{
+			File:         src,
+			Format:       format,
+			AreaID:       areaID,
+			Collect:      collect,
+			Diff:         diff,
+			CrossArea:    crossArea,
+			Columns:      columns,
+			DetectHeader: detectHeader,
+		}
+		h.service.ImportFromSpreadsheetWithProgress(c.Request().Context(), input, onProgress)
+	}()
+
+	// Stream events to client
+	flusher, ok := c.Response().Writer.(http.Flusher)
+	if !ok {
+		return rest.NewInternalServerError("streaming nao suportado")
+	}
+
+	for {
+		select {
+		case event, ok := <-eventChan:
+			if !ok {
+				return nil
+			}
+
+			data, err := json.Marshal(event)
+			if err != nil {
+				continue
+			}
+
+			fmt.Fprintf(c.Response(), "event: %s\n", event.Type)
+			fmt.Fprintf(c.Response(), "data: %s\n\n", data)
+			flusher.Flush()
+
+			if event.Type == ImportEventComplete {
+				close(done)
+				return nil
+			}
+
+		case <-c.Request().Context().Done():
+			close(done)
+			return nil
+		}
+	}
+}
+
+// ImportIDHeader carries the id of an archived import, used to replay it
+const ImportIDHeader = "X-Import-ID"
+
+// ImportReplayResult is the archived import and how its rows would be imported today
+type ImportReplayResult struct {
+	Import  ImportRecord         `json:"import"`
+	Preview *ImportPreviewResult `json:"preview"`
+}
+
+// ReplayImport handles POST /admin/imports/:id/replay?dry=true
+// Runs an archived import file again in dry-run mode, with the area it was sent with, and
+// reports how every row would be imported. Nothing is written; dry=false is refused.
+func (h *Handler) ReplayImport(c echo.Context) error {
+	if dry := c.QueryParam("dry"); dry != "" {
+		parsed, err := strconv.ParseBool(dry)
+		if err != nil {
+			return rest.NewBadRequestError("valor invalido para dry")
+		}
+		if !parsed {
+			return rest.NewBadRequestError("o replay de importacao so e suportado com dry=true")
+		}
+	}
+
+	if h.imports == nil {
+		return rest.NewNotFoundError("arquivamento de importacoes desativado")
+	}
+
+	record, file, err := h.imports.Open(c.Param("id"))
+	if err != nil {
+		if errors.Is(err, ErrImportNotArchived) {
+			return rest.NewNotFoundError("importacao nao encontrada")
+		}
+		return rest.NewInternalServerError("erro ao abrir arquivo")
+	}
+	defer file.Close()
+
+	var areaID pgtype.UUID
+	if record.AreaID != "" {
+		if parsedUUID, err := parser.PgUUIDFromString(record.AreaID); err == nil {
+			areaID = parsedUUID
+		}
+	}
+
+	preview, apiErr := h.service.PreviewImport(c.Request().Context(), ImportPreviewInput{
+		File:         file,
+		Format:       record.Format,
+		AreaID:       areaID,
+		Limit:        math.MaxInt,
+		Columns:      record.Columns,
+		DetectHeader: record.DetectHeader,
+	})
+	if apiErr != nil {
+		return apiErr
+	}
+
+	return c.JSON(http.StatusOK, ImportReplayResult{Import: record, Preview: preview})
+}
+
+// ExportSpreadsheet handles GET /products/export
+// Exports all products to an Excel spreadsheet for download
+// format=csv redirects to GET /products/export.csv, which serves the CSV in the same layout
+func (h *Handler) ExportSpreadsheet(c echo.Context) error {
+	_, err := user.GetCurrentUser(c)
+	if err != nil {
+		return rest.NewUnauthorizedRequestError("usuario nao autenticado")
+	}
+
+	switch c.QueryParam("format") {
+	case "", "xlsx":
+	case "csv":
+		// Temporario, para nao ficar em cache; relativo, para valer atras de um prefixo no proxy
+		return c.Redirect(http.StatusTemporaryRedirect, "export.csv")
+	default:
+		return rest.NewBadRequestError("valor invalido para format")
+	}
+
+	// A planilha so e gerada quando o catalogo mudou desde o ultimo download do cliente
+	version, apiErr := h.service.ProductsExportVersion(c.Request().Context())
+	if apiErr != nil {
+		return apiErr
+	}
+	if setExportVersion(c, version) {
+		return c.NoContent(http.StatusNotModified)
+	}
+
+	buf, apiErr := h.service.ExportToSpreadsheet(c.Request().Context())
+	if apiErr != nil {
+		return apiErr
 	}
 
 	c.Response().Header().Set("Content-Disposition", "attachment; filename=produtos.xlsx")
 	return c.Blob(http.StatusOK, "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", buf.Bytes())
 }
+
+// setExportVersion sets the caching headers of a full catalog export and reports whether the
+// client's copy is still current
+func setExportVersion(c echo.Context, version *ExportVersion) bool {
+	header := c.Response().Header()
+	header.Set("ETag", version.ETag)
+	header.Set("Last-Modified", version.LastModified.UTC().Format(http.TimeFormat))
+	header.Set("Cache-Control", "private, no-cache")
+
+	return notModified(c.Request(), version)
+}
+
+// ImportTemplate handles GET /products/import/template
+// Returns an empty spreadsheet with the header row the import expects
+func (h *Handler) ImportTemplate(c echo.Context) error {
+	_, err := user.GetCurrentUser(c)
+	if err != nil {
+		return rest.NewUnauthorizedRequestError("usuario nao autenticado")
+	}
+
+	buf, apiErr := h.service.ImportTemplate()
+	if apiErr != nil {
+		return apiErr
+	}
+
+	c.Response().Header().Set("Content-Disposition", "attachment; filename=modelo-importacao.xlsx")
+	return c.Blob(http.StatusOK, "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", buf.Bytes())
+}
+
+// notModified reports whether the client's cached copy is still current.
+// If-None-Match takes precedence over If-Modified-Since, as in RFC 9110.
+func notModified(req *http.Request, version *ExportVersion) bool {
+	if ifNoneMatch := req.Header.Get("If-None-Match"); ifNoneMatch != "" {
+		for _, tag := range strings.Split(ifNoneMatch, ",") {
+			tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+			if tag == "*" || tag == version.ETag {
+				return true
+			}
+		}
+		return false
+	}
+
+	if ifModifiedSince := req.Header.Get("If-Modified-Since"); ifModifiedSince != "" {
+		since, err := http.ParseTime(ifModifiedSince)
+		if err != nil {
+			return false
+		}
+		// Last-Modified so tem precisao de segundos
+		return !version.LastModified.Truncate(time.Second).After(since)
+	}
+
+	return false
+}
+
+// ExportLifecycleChanges handles GET /lifecycle-changes/export?from=&to=
+// Downloads a spreadsheet with the lifecycle transitions between two dates (YYYY-MM-DD, both inclusive)
+func (h *Handler) ExportLifecycleChanges(c echo.Context) error {
+	_, err := user.GetCurrentUser(c)
+	if err != nil {
+		return rest.NewUnauthorizedRequestError("usuario nao autenticado")
+	}
+
+	fromStr, toStr := c.QueryParam("from"), c.QueryParam("to")
+	if fromStr == "" || toStr == "" {
+		return rest.NewBadRequestError("parametros from e to sao obrigatorios")
+	}
+
+	from, err := time.ParseInLocation("2006-01-02", fromStr, time.Local)
+	if err != nil {
+		return rest.NewBadRequestError("data inicial invalida, use o formato AAAA-MM-DD")
+	}
+	to, err := time.ParseInLocation("2006-01-02", toStr, time.Local)
+	if err != nil {
+		return rest.NewBadRequestError("data final invalida, use o formato AAAA-MM-DD")
+	}
+	if to.Before(from) {
+		return rest.NewBadRequestError("data final deve ser igual ou posterior a data inicial")
+	}
+
+	// to e inclusivo: busca ate o inicio do dia seguinte
+	buf, apiErr := h.service.ExportLifecycleChanges(c.Request().Context(), from, to.AddDate(0, 0, 1))
+	if apiErr != nil {
+		return apiErr
+	}
+
+	filename := fmt.Sprintf("mudancas_ciclo_de_vida_%s_%s.xlsx", fromStr, toStr)
+	c.Response().Header().Set("Content-Disposition", "attachment; filename="+filename)
+	return c.Blob(http.StatusOK, "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", buf.Bytes())
+}
+
+// exportFlushEvery is how many products are written between flushes of the streamed exports
+const exportFlushEvery = 100
+
+// ExportJSON handles GET /products/export.json
+// Streams the whole catalog as a JSON array with the latest snapshot of each product.
+// Accepts the same area_id and search filters as the product list
+func (h *Handler) ExportJSON(c echo.Context) error {
+	_, err := user.GetCurrentUser(c)
+	if err != nil {
+		return rest.NewUnauthorizedRequestError("usuario nao autenticado")
+	}
+
+	input, apiErr := exportFilters(c)
+	if apiErr != nil {
+		return apiErr
+	}
+
+	res := c.Response()
+	enc := json.NewEncoder(res)
+	written := 0
+
+	// Headers are only sent with the first product so errors before that still get a proper status
+	begin := func() error {
+		res.Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		res.Header().Set("Content-Disposition", "attachment; filename=produtos.json")
+		res.WriteHeader(http.StatusOK)
+		_, err := res.Write([]byte("["))
+		return err
+	}
+
+	apiErr = h.service.StreamProducts(c.Request().Context(), input, func(p ProductWithSnapshotOutput) error {
+		if written == 0 {
+			if err := begin(); err != nil {
+				return err
+			}
+		} else if _, err := res.Write([]byte(",")); err != nil {
+			return err
+		}
+
+		if err := enc.Encode(p); err != nil {
+			return err
+		}
+
+		written++
+		if written%exportFlushEvery == 0 {
+			res.Flush()
+		}
+		return nil
+	})
+	if apiErr != nil {
+		if written == 0 {
+			return apiErr
+		}
+		// A resposta ja foi iniciada; o array truncado sinaliza a falha ao cliente
+		return nil
+	}
+
+	if written == 0 {
+		if err := begin(); err != nil {
+			return err
+		}
+	}
+	if _, err := res.Write([]byte("]")); err != nil {
+		return err
+	}
+	res.Flush()
+	return nil
+}
+
+// ExportCSV handles GET /products/export.csv
+// Returns the catalog in the columns of the spreadsheet export (B-J), which the import reads back.
+// layout=full streams instead one row per product with every field, accepting the same filters as
+// the JSON export
+func (h *Handler) ExportCSV(c echo.Context) error {
+	_, err := user.GetCurrentUser(c)
+	if err != nil {
+		return rest.NewUnauthorizedRequestError("usuario nao autenticado")
+	}
+
+	switch c.QueryParam("layout") {
+	case "", "import":
+		return h.exportImportCSV(c)
+	case "full":
+		return h.exportFullCSV(c)
+	default:
+		return rest.NewBadRequestError("valor invalido para layout")
+	}
+}
+
+// exportFullCSV streams the catalog as CSV, one row per product with lifecycle and replacement
+// details. Rows are written as the products are paged, so the export doesn't grow in memory with
+// the catalog
+func (h *Handler) exportFullCSV(c echo.Context) error {
+	input, apiErr := exportFilters(c)
+	if apiErr != nil {
+		return apiErr
+	}
+
+	res := c.Response()
+	w := csv.NewWriter(res)
+	started := false
+	written := 0
+
+	// Headers are only sent with the first product so errors before that still get a proper status
+	begin := func() error {
+		started = true
+		res.Header().Set(echo.HeaderContentType, "text/csv; charset=utf-8")
+		res.Header().Set("Content-Disposition", "attachment; filename=produtos.csv")
+		res.WriteHeader(http.StatusOK)
+		if _, err := res.Write([]byte(utf8BOM)); err != nil {
+			return err
+		}
+		return w.Write(csvExportHeader)
+	}
+
+	apiErr = h.service.StreamProducts(c.Request().Context(), input, func(p ProductWithSnapshotOutput) error {
+		if !started {
+			if err := begin(); err != nil {
+				return err
+			}
+		}
+
+		if err := w.Write(csvExportRecord(p)); err != nil {
+			return err
+		}
+
+		written++
+		if written%exportFlushEvery == 0 {
+			w.Flush()
+			if err := w.Error(); err != nil {
+				return err
+			}
+			res.Flush()
+		}
+		return nil
+	})
+	if apiErr != nil {
+		if !started {
+			return apiErr
+		}
+		// A resposta ja foi iniciada; o arquivo truncado sinaliza a falha ao cliente
+		w.Flush()
+		return nil
+	}
+
+	if !started {
+		if err := begin(); err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return err
+	}
+	res.Flush()
+	return nil
+}
+
+// exportImportCSV writes the whole catalog as CSV in the spreadsheet layout. Like the .xlsx it is
+// only rebuilt when the catalog changed, and it takes no filters: the import expects every product
+func (h *Handler) exportImportCSV(c echo.Context) error {
+	if c.QueryParam("area_id") != "" || c.QueryParam("search") != "" {
+		return rest.NewBadRequestError("o layout de importacao nao aceita filtros, use layout=full")
+	}
+
+	version, apiErr := h.service.ProductsExportVersion(c.Request().Context())
+	if apiErr != nil {
+		return apiErr
+	}
+	// Cada formato tem sua propria representacao, entao sua propria ETag
+	version.ETag = strings.TrimSuffix(version.ETag, `"`) + `-csv"`
+	if setExportVersion(c, version) {
+		return c.NoContent(http.StatusNotModified)
+	}
+
+	buf, apiErr := h.service.ExportToCSV(c.Request().Context())
+	if apiErr != nil {
+		return apiErr
+	}
+
+	c.Response().Header().Set("Content-Disposition", "attachment; filename=produtos.csv")
+	return c.Blob(http.StatusOK, "text/csv; charset=utf-8", buf.Bytes())
+}
+
+// exportFilters reads the area_id and search filters of the streamed exports
+func exportFilters(c echo.Context) (ExportProductsInput, *rest.ApiErr) {
+	input := ExportProductsInput{Search: c.QueryParam("search")}
+	if areaIDStr := c.QueryParam("area_id"); areaIDStr != "" {
+		areaUUID, err := parser.PgUUIDFromString(areaIDStr)
+		if err != nil {
+			return input, rest.NewBadRequestError("id da area invalido")
+		}
+		input.AreaID = areaUUID
+	}
+	return input, nil
+}
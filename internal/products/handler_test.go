@@ -0,0 +1,82 @@
+package products
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/freitasmatheusrn/lifecycle-monitor/internal/user"
+	"github.com/freitasmatheusrn/lifecycle-monitor/pkg/rest"
+	"github.com/labstack/echo/v4"
+)
+
+func TestNotModified(t *testing.T) {
+	lastModified := time.Date(2026, 10, 1, 12, 30, 15, 500, time.UTC)
+	version := &ExportVersion{ETag: `"abc123"`, LastModified: lastModified}
+
+	tests := []struct {
+		name     string
+		headers  map[string]string
+		expected bool
+	}{
+		{"no conditional headers", nil, false},
+		{"matching etag", map[string]string{"If-None-Match": `"abc123"`}, true},
+		{"weak matching etag in a list", map[string]string{"If-None-Match": `"old", W/"abc123"`}, true},
+		{"stale etag wins over fresh date", map[string]string{
+			"If-None-Match":     `"old"`,
+			"If-Modified-Since": lastModified.Add(time.Hour).Format(http.TimeFormat),
+		}, false},
+		{"same second", map[string]string{"If-Modified-Since": lastModified.Format(http.TimeFormat)}, true},
+		{"older date", map[string]string{"If-Modified-Since": lastModified.Add(-time.Minute).Format(http.TimeFormat)}, false},
+		{"invalid date", map[string]string{"If-Modified-Since": "ontem"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/products/export", nil)
+			for key, value := range tt.headers {
+				req.Header.Set(key, value)
+			}
+
+			if got := notModified(req, version); got != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestExportCSVLayouts(t *testing.T) {
+	h := &Handler{}
+	e := echo.New()
+
+	request := func(target string, handle echo.HandlerFunc) (*httptest.ResponseRecorder, error) {
+		rec := httptest.NewRecorder()
+		c := e.NewContext(httptest.NewRequest(http.MethodGet, target, nil), rec)
+		c.Set("current_user", user.CurrentUser{Name: "tester"})
+		return rec, handle(c)
+	}
+
+	// The spreadsheet route hands the CSV over to the CSV route, without a cacheable redirect
+	rec, err := request("/products/export?format=csv", h.ExportSpreadsheet)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rec.Code != http.StatusTemporaryRedirect || rec.Header().Get("Location") != "export.csv" {
+		t.Errorf("expected a temporary redirect to export.csv, got %d %q", rec.Code, rec.Header().Get("Location"))
+	}
+
+	// Filters only apply to the full layout
+	for _, target := range []string{
+		"/products/export.csv?layout=xlsx",
+		"/products/export.csv?search=6ES7",
+		"/products/export.csv?layout=import&area_id=1",
+	} {
+		_, err := request(target, h.ExportCSV)
+		var apiErr *rest.ApiErr
+		if !errors.As(err, &apiErr) || apiErr.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected a bad request, got %v", target, err)
+		}
+	}
+}
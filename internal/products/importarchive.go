@@ -0,0 +1,209 @@
+package products
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// ErrImportNotArchived is returned for an import id without a stored file (never stored, or already expired)
+var ErrImportNotArchived = errors.New("import not archived")
+
+// ImportRecord describes an archived spreadsheet import and the options it ran with
+type ImportRecord struct {
+	ID           string         `json:"id"`
+	FileName     string         `json:"file_name"`
+	Format       ImportFormat   `json:"format,omitempty"` // vazio nos registros anteriores ao import CSV (Excel)
+	Size         int64          `json:"size"`
+	AreaID       string         `json:"area_id,omitempty"`
+	Diff         bool           `json:"diff"`
+	CrossArea    string         `json:"cross_area"`
+	Columns      *ColumnMapping `json:"columns,omitempty"`
+	DetectHeader bool           `json:"detect_header,omitempty"`
+	Collect      bool           `json:"collect"` // so no import com progresso (SSE)
+	Stream       bool           `json:"stream"`  // enviado por POST /products/import-stream
+	UserID       string         `json:"user_id,omitempty"`
+	UploadedAt   time.Time      `json:"uploaded_at"`
+}
+
+// ImportArchive keeps the uploaded import files on disk, so an import reported as wrong can be
+// replayed with the exact same file. Files are removed after the retention period, and files
+// larger than the size cap aren't stored. A nil *ImportArchive stores nothing.
+type ImportArchive struct {
+	dir       string
+	maxBytes  int64
+	retention time.Duration
+	now       func() time.Time
+	logger    *zap.Logger
+	mu        sync.Mutex
+}
+
+// NewImportArchive stores the import files in dir, skipping files over maxBytes and removing
+// them retention after upload. An empty dir disables the archive and returns nil.
+func NewImportArchive(dir string, maxBytes int64, retention time.Duration, logger *zap.Logger) (*ImportArchive, error) {
+	if dir == "" {
+		return nil, nil
+	}
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("could not create import archive dir: %w", err)
+	}
+	return &ImportArchive{dir: dir, maxBytes: maxBytes, retention: retention, now: time.Now, logger: logger}, nil
+}
+
+// Archive stores an uploaded import file and returns its import id, or "" when it wasn't stored.
+// The import goes on either way, so failures are only logged.
+func (a *ImportArchive) Archive(file *multipart.FileHeader, record ImportRecord) string {
+	if a == nil {
+		return ""
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		a.logger.Warn("failed to open import file for the archive", zap.Error(err))
+		return ""
+	}
+	defer src.Close()
+
+	record.FileName = file.Filename
+	record, stored, err := a.Save(src, record)
+	if err != nil {
+		a.logger.Warn("failed to archive import file", zap.String("file_name", file.Filename), zap.Error(err))
+		return ""
+	}
+	if !stored {
+		a.logger.Info("import file over the archive size cap, not stored",
+			zap.String("file_name", file.Filename),
+			zap.Int64("size", file.Size),
+		)
+		return ""
+	}
+	return record.ID
+}
+
+// Save stores the file read from src with its record and returns the record with its new id.
+// Returns ok=false without storing anything when the file is over the size cap.
+func (a *ImportArchive) Save(src io.Reader, record ImportRecord) (ImportRecord, bool, error) {
+	if a == nil {
+		return record, false, nil
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.prune()
+
+	record.ID = uuid.NewString()
+	record.UploadedAt = a.now().UTC()
+	base := filepath.Join(a.dir, record.ID)
+
+	file, err := os.OpenFile(base+".xlsx", os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+	if err != nil {
+		return record, false, fmt.Errorf("could not create archived import: %w", err)
+	}
+
+	// Le um byte a mais que o limite para saber se o arquivo passou dele
+	limit := a.maxBytes
+	if limit <= 0 {
+		limit = 1<<63 - 2
+	}
+	written, err := io.Copy(file, io.LimitReader(src, limit+1))
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil || written > limit {
+		os.Remove(base + ".xlsx")
+		if err != nil {
+			return record, false, fmt.Errorf("could not write archived import: %w", err)
+		}
+		return record, false, nil
+	}
+	record.Size = written
+
+	meta, err := json.Marshal(record)
+	if err == nil {
+		err = os.WriteFile(base+".json", meta, 0o600)
+	}
+	if err != nil {
+		os.Remove(base + ".xlsx")
+		return record, false, fmt.Errorf("could not write archived import record: %w", err)
+	}
+	return record, true, nil
+}
+
+// Open returns the record and the file of an archived import; the caller closes the file
+func (a *ImportArchive) Open(id string) (ImportRecord, io.ReadCloser, error) {
+	if a == nil {
+		return ImportRecord{}, nil, ErrImportNotArchived
+	}
+	// O id vira nome de arquivo: so aceita o formato gerado por Save
+	if _, err := uuid.Parse(id); err != nil {
+		return ImportRecord{}, nil, ErrImportNotArchived
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.prune()
+
+	base := filepath.Join(a.dir, id)
+	meta, err := os.ReadFile(base + ".json")
+	if errors.Is(err, os.ErrNotExist) {
+		return ImportRecord{}, nil, ErrImportNotArchived
+	}
+	if err != nil {
+		return ImportRecord{}, nil, err
+	}
+
+	var record ImportRecord
+	if err := json.Unmarshal(meta, &record); err != nil {
+		return ImportRecord{}, nil, fmt.Errorf("invalid archived import record %s: %w", id, err)
+	}
+
+	file, err := os.Open(base + ".xlsx")
+	if errors.Is(err, os.ErrNotExist) {
+		return ImportRecord{}, nil, ErrImportNotArchived
+	}
+	if err != nil {
+		return ImportRecord{}, nil, err
+	}
+	return record, file, nil
+}
+
+// prune removes the files stored longer than the retention; zero retention keeps them forever
+func (a *ImportArchive) prune() {
+	if a.retention <= 0 {
+		return
+	}
+	entries, err := os.ReadDir(a.dir)
+	if err != nil {
+		return
+	}
+
+	cutoff := a.now().Add(-a.retention)
+	for _, entry := range entries {
+		ext := filepath.Ext(entry.Name())
+		if entry.IsDir() || (ext != ".xlsx" && ext != ".json") {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil || !info.ModTime().Before(cutoff) {
+			continue
+		}
+		base := filepath.Join(a.dir, strings.TrimSuffix(entry.Name(), ext))
+		os.Remove(base + ".xlsx")
+		os.Remove(base + ".json")
+	}
+}
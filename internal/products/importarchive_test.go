@@ -0,0 +1,78 @@
+package products
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestImportArchive_SaveAndOpen(t *testing.T) {
+	archive, err := NewImportArchive(t.TempDir(), 16, 0, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	record, stored, err := archive.Save(strings.NewReader("planilha"), ImportRecord{FileName: "itens.xlsx", CrossArea: CrossAreaWarn})
+	if err != nil || !stored || record.ID == "" || record.Size != 8 {
+		t.Fatalf("expected the file stored, got %+v, %v, %v", record, stored, err)
+	}
+
+	opened, file, err := archive.Open(record.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer file.Close()
+	content, _ := io.ReadAll(file)
+	if string(content) != "planilha" || opened.FileName != "itens.xlsx" || opened.CrossArea != CrossAreaWarn {
+		t.Errorf("expected the stored file and record, got %q and %+v", content, opened)
+	}
+
+	if _, stored, err := archive.Save(strings.NewReader(strings.Repeat("x", 17)), ImportRecord{}); err != nil || stored {
+		t.Errorf("expected a file over the size cap to be skipped, got stored=%v, %v", stored, err)
+	}
+	if entries, _ := os.ReadDir(archive.dir); len(entries) != 2 {
+		t.Errorf("expected only the first import on disk, got %d files", len(entries))
+	}
+}
+
+func TestImportArchive_OpenRejectsUnknownIDs(t *testing.T) {
+	archive, err := NewImportArchive(t.TempDir(), 0, 0, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, id := range []string{"../../etc/passwd", "00000000-0000-0000-0000-000000000000"} {
+		if _, _, err := archive.Open(id); !errors.Is(err, ErrImportNotArchived) {
+			t.Errorf("%s: expected ErrImportNotArchived, got %v", id, err)
+		}
+	}
+
+	var disabled *ImportArchive
+	if _, _, err := disabled.Open("x"); !errors.Is(err, ErrImportNotArchived) {
+		t.Errorf("expected a disabled archive to have no imports, got %v", err)
+	}
+}
+
+func TestImportArchive_RemovesExpiredFiles(t *testing.T) {
+	archive, err := NewImportArchive(t.TempDir(), 0, 24*time.Hour, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	record, _, err := archive.Save(strings.NewReader("antiga"), ImportRecord{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().Add(-48 * time.Hour)
+	for _, ext := range []string{".xlsx", ".json"} {
+		os.Chtimes(filepath.Join(archive.dir, record.ID+ext), old, old)
+	}
+
+	if _, _, err := archive.Open(record.ID); !errors.Is(err, ErrImportNotArchived) {
+		t.Errorf("expected the expired import removed, got %v", err)
+	}
+}
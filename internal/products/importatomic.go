@@ -0,0 +1,48 @@
+package products
+
+import (
+	"context"
+
+	repo "github.com/freitasmatheusrn/lifecycle-monitor/internal/database/postgres/sqlc"
+	"github.com/freitasmatheusrn/lifecycle-monitor/pkg/rest"
+	"go.uber.org/zap"
+)
+
+// importAtomically imports the rows with every query bound to one transaction, committed only when
+// all rows were imported. The import stops at the first failed row and nothing is written: the
+// result has just that row's error, with RolledBack set.
+func (s *svc) importAtomically(ctx context.Context, input ImportInput) (*ImportResult, *rest.ApiErr) {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		s.logger.Error("failed to begin import transaction", zap.Error(err))
+		return nil, rest.NewInternalServerError("erro ao iniciar importacao")
+	}
+	defer tx.Rollback(ctx)
+
+	// Same service with the queries bound to the transaction
+	txService := *s
+	txService.repo = repo.New(tx)
+
+	result, apiErr := txService.importRows(ctx, input)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+
+	if result.Failed > 0 {
+		s.logger.Warn("atomic import rolled back", zap.Int("failed", result.Failed))
+		return &ImportResult{
+			Failed:     result.Failed,
+			Errors:     result.Errors,
+			Products:   make([]ProductOutput, 0),
+			RolledBack: true,
+		}, nil
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		s.logger.Error("failed to commit import transaction", zap.Error(err))
+		return nil, rest.NewInternalServerError("erro ao salvar importacao")
+	}
+
+	s.metrics.RecordImport(result)
+	return result, nil
+}
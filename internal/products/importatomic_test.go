@@ -0,0 +1,99 @@
+package products
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+// failingTx is an import transaction whose queries all fail, as after a failed statement in Postgres
+type failingTx struct {
+	pgx.Tx
+	committed  bool
+	rolledBack bool
+}
+
+type failingRow struct{}
+
+func (failingRow) Scan(dest ...any) error { return errors.New("current transaction is aborted") }
+
+func (tx *failingTx) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	return failingRow{}
+}
+
+func (tx *failingTx) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	return nil, errors.New("current transaction is aborted")
+}
+
+func (tx *failingTx) Commit(ctx context.Context) error {
+	tx.committed = true
+	return nil
+}
+
+func (tx *failingTx) Rollback(ctx context.Context) error {
+	tx.rolledBack = true
+	return nil
+}
+
+type fakeTxBeginner struct {
+	tx  pgx.Tx
+	err error
+}
+
+func (b fakeTxBeginner) Begin(ctx context.Context) (pgx.Tx, error) {
+	return b.tx, b.err
+}
+
+func TestImportFromSpreadsheet_AtomicRollsBack(t *testing.T) {
+	data := ";Área;Descrição;Código Fabricante;Qtd\n" +
+		"\n" +
+		";;CPU S7-1200;6ES7214-1AG40-0XB0;3\n" +
+		";;Contator;3RT2015-1BB41;1\n"
+
+	tx := &failingTx{}
+	querier := &csvImportQuerier{}
+	service := &svc{repo: querier, db: fakeTxBeginner{tx: tx}, logger: zap.NewNop(), baseURL: "https://example.com"}
+
+	result, apiErr := service.ImportFromSpreadsheet(t.Context(), ImportInput{
+		File:   strings.NewReader(data),
+		Format: ImportFormatCSV,
+		Atomic: true,
+	})
+	if apiErr != nil {
+		t.Fatalf("unexpected error: %v", apiErr.Message)
+	}
+
+	if !result.RolledBack || result.Failed != 1 || len(result.Errors) != 1 {
+		t.Fatalf("expected a rollback with the first failed row, got %+v", result)
+	}
+	if result.Errors[0].Row != 3 {
+		t.Errorf("expected the error on row 3, got %d", result.Errors[0].Row)
+	}
+	if result.Created != 0 || len(result.Products) != 0 {
+		t.Errorf("expected nothing reported as imported, got %+v", result)
+	}
+	if !tx.rolledBack || tx.committed {
+		t.Errorf("expected the transaction rolled back and not committed (rolled back %v, committed %v)", tx.rolledBack, tx.committed)
+	}
+	if len(querier.created) != 0 {
+		t.Errorf("expected the queries to go through the transaction, got %+v created outside it", querier.created)
+	}
+}
+
+func TestImportFromSpreadsheet_AtomicBeginFails(t *testing.T) {
+	service := &svc{db: fakeTxBeginner{err: errors.New("conn closed")}, logger: zap.NewNop()}
+
+	_, apiErr := service.ImportFromSpreadsheet(t.Context(), ImportInput{
+		File:   strings.NewReader(";a;b;c\n"),
+		Format: ImportFormatCSV,
+		Atomic: true,
+	})
+	if apiErr == nil || apiErr.Code != http.StatusInternalServerError {
+		t.Fatalf("expected a 500 error, got %+v", apiErr)
+	}
+}
@@ -0,0 +1,183 @@
+package products
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// ColumnMapping tells the import in which column (letter, as in Excel) each field is, for layouts
+// other than the fixed one (B=Área, C=Descrição, D=Código Fabricante, ...). Empty fields aren't read.
+type ColumnMapping struct {
+	Area             string `json:"area,omitempty"`
+	Description      string `json:"description,omitempty"`
+	ManufacturerCode string `json:"code"`
+	Quantity         string `json:"quantity,omitempty"`
+	SAPCode          string `json:"sap_code,omitempty"`
+	Observations     string `json:"observations,omitempty"`
+	MinQuantity      string `json:"min,omitempty"`
+	MaxQuantity      string `json:"max,omitempty"`
+	Status           string `json:"status,omitempty"`
+	HeaderRow        int    `json:"header_row,omitempty"` // linha do cabecalho; os dados comecam na seguinte (zero mantem a linha padrao)
+}
+
+// importLayout is where the import reads each field: 0-based column indexes, -1 when absent
+type importLayout struct {
+	area             int
+	description      int
+	manufacturerCode int
+	quantity         int
+	sapCode          int
+	observations     int
+	minQuantity      int
+	maxQuantity      int
+	status           int
+	firstRow         int // indice da primeira linha de dados; zero mantem o inicio padrao de cada import
+}
+
+// fixedImportLayout is the spreadsheet layout the import was built for, also written by the export
+var fixedImportLayout = importLayout{
+	area:             1, // B
+	description:      2, // C
+	manufacturerCode: 3, // D
+	quantity:         4, // E
+	sapCode:          5, // F
+	observations:     6, // G
+	minQuantity:      7, // H
+	maxQuantity:      8, // I
+	status:           9, // J
+}
+
+// start returns the index of the first data row, defaultRow unless the layout sets one
+func (l importLayout) start(defaultRow int) int {
+	if l.firstRow > 0 {
+		return l.firstRow
+	}
+	return defaultRow
+}
+
+// layout converts the column letters of the mapping; the manufacturer code column is required
+func (m ColumnMapping) layout() (importLayout, error) {
+	if strings.TrimSpace(m.ManufacturerCode) == "" {
+		return importLayout{}, fmt.Errorf("coluna do codigo do fabricante (code) e obrigatoria")
+	}
+	if m.HeaderRow < 0 {
+		return importLayout{}, fmt.Errorf("header_row invalido")
+	}
+
+	l := importLayout{firstRow: m.HeaderRow}
+	for _, field := range []struct {
+		letter string
+		index  *int
+	}{
+		{m.Area, &l.area},
+		{m.Description, &l.description},
+		{m.ManufacturerCode, &l.manufacturerCode},
+		{m.Quantity, &l.quantity},
+		{m.SAPCode, &l.sapCode},
+		{m.Observations, &l.observations},
+		{m.MinQuantity, &l.minQuantity},
+		{m.MaxQuantity, &l.maxQuantity},
+		{m.Status, &l.status},
+	} {
+		letter := strings.TrimSpace(field.letter)
+		if letter == "" {
+			*field.index = -1
+			continue
+		}
+		number, err := excelize.ColumnNameToNumber(letter)
+		if err != nil {
+			return importLayout{}, fmt.Errorf("coluna %q invalida", letter)
+		}
+		*field.index = number - 1
+	}
+	return l, nil
+}
+
+// importHeaderNames are the header texts recognized by the header detection, normalized by normalizeHeader
+var importHeaderNames = map[string]func(*importLayout) *int{
+	"area":              func(l *importLayout) *int { return &l.area },
+	"descricao":         func(l *importLayout) *int { return &l.description },
+	"description":       func(l *importLayout) *int { return &l.description },
+	"codigo fabricante": func(l *importLayout) *int { return &l.manufacturerCode },
+	"cod fabricante":    func(l *importLayout) *int { return &l.manufacturerCode },
+	"manufacturer code": func(l *importLayout) *int { return &l.manufacturerCode },
+	"qtd":               func(l *importLayout) *int { return &l.quantity },
+	"quantidade":        func(l *importLayout) *int { return &l.quantity },
+	"quantity":          func(l *importLayout) *int { return &l.quantity },
+	"codigo sap":        func(l *importLayout) *int { return &l.sapCode },
+	"cod sap":           func(l *importLayout) *int { return &l.sapCode },
+	"sap":               func(l *importLayout) *int { return &l.sapCode },
+	"obs":               func(l *importLayout) *int { return &l.observations },
+	"observacao":        func(l *importLayout) *int { return &l.observations },
+	"observacoes":       func(l *importLayout) *int { return &l.observations },
+	"min":               func(l *importLayout) *int { return &l.minQuantity },
+	"minimo":            func(l *importLayout) *int { return &l.minQuantity },
+	"max":               func(l *importLayout) *int { return &l.maxQuantity },
+	"maximo":            func(l *importLayout) *int { return &l.maxQuantity },
+	"status":            func(l *importLayout) *int { return &l.status },
+}
+
+// detectImportLayout looks for a header in rows 1 and 2 and maps the known names to their columns.
+// Returns false when neither row names the manufacturer code column; the caller keeps the fixed layout.
+func detectImportLayout(rows [][]string) (importLayout, bool) {
+	for rowIdx := 0; rowIdx < min(2, len(rows)); rowIdx++ {
+		l := importLayout{
+			area: -1, description: -1, manufacturerCode: -1, quantity: -1, sapCode: -1,
+			observations: -1, minQuantity: -1, maxQuantity: -1, status: -1,
+			firstRow: rowIdx + 1,
+		}
+		for col, cell := range rows[rowIdx] {
+			field, ok := importHeaderNames[normalizeHeader(cell)]
+			if !ok {
+				continue
+			}
+			// A primeira coluna com o nome vale; repetidas sao ignoradas
+			if index := field(&l); *index < 0 {
+				*index = col
+			}
+		}
+		if l.manufacturerCode >= 0 {
+			return l, true
+		}
+	}
+	return importLayout{}, false
+}
+
+// headerAccents are the accented letters found in Portuguese headers
+var headerAccents = strings.NewReplacer(
+	"á", "a", "à", "a", "â", "a", "ã", "a",
+	"é", "e", "ê", "e",
+	"í", "i",
+	"ó", "o", "ô", "o", "õ", "o",
+	"ú", "u",
+	"ç", "c",
+)
+
+// normalizeHeader lowercases a header cell, drops accents, units like "(un)" and punctuation
+func normalizeHeader(cell string) string {
+	cell = headerAccents.Replace(strings.ToLower(cell))
+	if i := strings.Index(cell, "("); i >= 0 {
+		cell = cell[:i]
+	}
+	cell = strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return ' '
+	}, cell)
+	return strings.Join(strings.Fields(cell), " ")
+}
+
+// importDataRows returns the indexes of the rows with a manufacturer code, from the layout's first
+// data row (row 4 by default, as the import with progress and the preview always read)
+func importDataRows(rows [][]string, layout importLayout) []int {
+	validRows := make([]int, 0)
+	for rowIdx := layout.start(3); rowIdx < len(rows); rowIdx++ {
+		if importRowValue(rows[rowIdx], layout.manufacturerCode) != "" {
+			validRows = append(validRows, rowIdx)
+		}
+	}
+	return validRows
+}
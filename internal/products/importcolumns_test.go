@@ -0,0 +1,120 @@
+package products
+
+import (
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestColumnMapping_Layout(t *testing.T) {
+	layout, err := ColumnMapping{ManufacturerCode: "a", Description: "B", Quantity: "AA", HeaderRow: 1}.layout()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if layout.manufacturerCode != 0 || layout.description != 1 || layout.quantity != 26 {
+		t.Errorf("unexpected columns %+v", layout)
+	}
+	if layout.area != -1 || layout.status != -1 {
+		t.Errorf("expected unmapped fields to be absent, got %+v", layout)
+	}
+	if layout.start(2) != 1 {
+		t.Errorf("expected data to start after header row 1, got index %d", layout.start(2))
+	}
+
+	for _, mapping := range []ColumnMapping{
+		{Description: "B"},                     // no code column
+		{ManufacturerCode: "1"},                // not a letter
+		{ManufacturerCode: "A", HeaderRow: -1}, // negative header row
+		{ManufacturerCode: "A", Status: "A-B"}, // invalid letter
+	} {
+		if _, err := mapping.layout(); err == nil {
+			t.Errorf("expected %+v to be rejected", mapping)
+		}
+	}
+}
+
+func TestDetectImportLayout(t *testing.T) {
+	tests := []struct {
+		name     string
+		rows     [][]string
+		ok       bool
+		code     int
+		quantity int
+		firstRow int
+	}{
+		{
+			name:     "export layout header in row 2",
+			rows:     [][]string{{}, {"", "Área", "Descrição", "Código Fabricante", "Qtd (un)", "Código SAP", "Obs.", "MIN (un)", "MAX (un)", "STATUS"}},
+			ok:       true,
+			code:     3,
+			quantity: 4,
+			firstRow: 2,
+		},
+		{
+			name:     "reordered header in row 1",
+			rows:     [][]string{{"Codigo fabricante", "QUANTIDADE", "Descricao"}},
+			ok:       true,
+			code:     0,
+			quantity: 1,
+			firstRow: 1,
+		},
+		{
+			name: "no code header",
+			rows: [][]string{{"Inventario 2026"}, {"Item", "Descricao"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			layout, ok := detectImportLayout(tt.rows)
+			if ok != tt.ok {
+				t.Fatalf("expected ok=%v, got %v", tt.ok, ok)
+			}
+			if !ok {
+				return
+			}
+			if layout.manufacturerCode != tt.code || layout.quantity != tt.quantity || layout.firstRow != tt.firstRow {
+				t.Errorf("unexpected layout %+v", layout)
+			}
+		})
+	}
+}
+
+func TestImportFromSpreadsheet_DetectHeader(t *testing.T) {
+	data := "Codigo Fabricante;Descricao;Qtd\n" +
+		"6ES7214-1AG40-0XB0;CPU S7-1200;3\n" +
+		"3RT2015-1BB41;Contator;1\n"
+
+	querier := &csvImportQuerier{failCode: "3RT2015-1BB41"}
+	service := &svc{repo: querier, logger: zap.NewNop()}
+
+	result, apiErr := service.ImportFromSpreadsheet(t.Context(), ImportInput{
+		File:         strings.NewReader(data),
+		Format:       ImportFormatCSV,
+		DetectHeader: true,
+	})
+	if apiErr != nil {
+		t.Fatalf("unexpected error: %v", apiErr.Message)
+	}
+
+	if len(querier.created) != 1 || querier.created[0].Description.String != "CPU S7-1200" || querier.created[0].Quantity.Int32 != 3 {
+		t.Errorf("expected the row after the header to be imported by header names, got %+v", querier.created)
+	}
+	if len(result.Errors) != 1 || result.Errors[0].Row != 3 {
+		t.Errorf("expected the failure reported on row 3 of the file, got %+v", result.Errors)
+	}
+}
+
+func TestImportFromSpreadsheet_InvalidColumns(t *testing.T) {
+	service := &svc{repo: &csvImportQuerier{}, logger: zap.NewNop()}
+
+	_, apiErr := service.ImportFromSpreadsheet(t.Context(), ImportInput{
+		File:    strings.NewReader("a;b\n"),
+		Format:  ImportFormatCSV,
+		Columns: &ColumnMapping{Description: "B"},
+	})
+	if apiErr == nil || !strings.HasPrefix(apiErr.Message, "mapeamento de colunas invalido") {
+		t.Errorf("expected an invalid mapping error, got %+v", apiErr)
+	}
+}
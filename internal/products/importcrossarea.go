@@ -0,0 +1,85 @@
+package products
+
+import (
+	"context"
+
+	repo "github.com/freitasmatheusrn/lifecycle-monitor/internal/database/postgres/sqlc"
+)
+
+// importCrossArea is called for a row whose code was not found in the target area. It looks the
+// code up in the other areas and applies policy, returning nil when the code exists nowhere else.
+// With CrossAreaActionCreated the caller creates the product as usual; with CrossAreaActionMoved
+// the moved product is returned (only as it would be moved when dryRun is set).
+func (s *svc) importCrossArea(ctx context.Context, policy string, rowNum int, params repo.CreateProductParams, dryRun bool) (*ImportCrossAreaMatch, *repo.Product, error) {
+	found, err := s.repo.FindProductsByCodes(ctx, []string{params.Code})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	match := &ImportCrossAreaMatch{
+		Row:          rowNum,
+		Code:         params.Code,
+		TargetAreaID: params.AreaID,
+	}
+	for _, p := range found {
+		if p.AreaID == params.AreaID {
+			continue
+		}
+		match.Matches = append(match.Matches, ImportAreaProduct{
+			ProductID: p.ID,
+			AreaID:    p.AreaID,
+			AreaName:  p.AreaName.String,
+		})
+	}
+	if len(match.Matches) == 0 {
+		return nil, nil, nil
+	}
+
+	switch policy {
+	case CrossAreaWarn:
+		match.Action = CrossAreaActionSkipped
+		return match, nil, nil
+
+	case CrossAreaMove:
+		// UpdateProduct can't clear area_id, and with several candidates we can't tell which one is the part
+		if !params.AreaID.Valid {
+			match.Action = CrossAreaActionSkipped
+			match.Reason = "area de destino nao informada"
+			return match, nil, nil
+		}
+		if len(match.Matches) > 1 {
+			match.Action = CrossAreaActionSkipped
+			match.Reason = "codigo existe em mais de uma area"
+			return match, nil, nil
+		}
+
+		match.Action = CrossAreaActionMoved
+		moveParams := repo.UpdateProductParams{
+			ID:               match.Matches[0].ProductID,
+			AreaID:           params.AreaID,
+			Description:      params.Description,
+			ManufacturerCode: params.ManufacturerCode,
+			Quantity:         params.Quantity,
+			SapCode:          params.SapCode,
+			Observations:     params.Observations,
+			MinQuantity:      params.MinQuantity,
+			MaxQuantity:      params.MaxQuantity,
+			InventoryStatus:  params.InventoryStatus,
+		}
+		if dryRun {
+			moved := dryRunCreate(params)
+			moved.ID = moveParams.ID
+			return match, &moved, nil
+		}
+
+		moved, err := s.repo.UpdateProduct(ctx, moveParams)
+		if err != nil {
+			return nil, nil, err
+		}
+		return match, &moved, nil
+
+	default:
+		match.Action = CrossAreaActionCreated
+		return match, nil, nil
+	}
+}
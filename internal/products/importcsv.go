@@ -0,0 +1,108 @@
+package products
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/freitasmatheusrn/lifecycle-monitor/pkg/rest"
+)
+
+// ImportFormat is the file format of an import; empty means an Excel spreadsheet
+type ImportFormat string
+
+const (
+	ImportFormatXLSX ImportFormat = "xlsx"
+	ImportFormatCSV  ImportFormat = "csv" // exportado do SAP, mesmo layout de colunas da planilha
+)
+
+// csvDelimiters are the separators accepted in CSV imports, in order of preference on a tie
+var csvDelimiters = []rune{';', ',', '\t'}
+
+// importFormatOf detects the format of an uploaded import file by its extension, then its content type
+func importFormatOf(file *multipart.FileHeader) ImportFormat {
+	if strings.EqualFold(filepath.Ext(file.Filename), ".csv") {
+		return ImportFormatCSV
+	}
+	if mediaType, _, err := mime.ParseMediaType(file.Header.Get("Content-Type")); err == nil && mediaType == "text/csv" {
+		return ImportFormatCSV
+	}
+	return ImportFormatXLSX
+}
+
+// readCSVRows reads a CSV import in the spreadsheet layout: rows[i] is line i+1 of the file, so
+// ImportError row numbers match the line the user sees. Blank lines are kept as empty rows.
+// The file must be UTF-8 (a leading BOM is dropped) separated by semicolons, commas or tabs.
+func readCSVRows(r io.Reader) ([][]string, *rest.ApiErr) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, rest.NewBadRequestError("erro ao ler arquivo CSV")
+	}
+	data = bytes.TrimPrefix(data, []byte(utf8BOM))
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil, rest.NewBadRequestError("arquivo CSV vazio")
+	}
+	if !utf8.Valid(data) {
+		return nil, rest.NewBadRequestError("codificacao do CSV invalida, salve o arquivo em UTF-8")
+	}
+
+	comma, ok := detectCSVDelimiter(data)
+	if !ok {
+		return nil, rest.NewBadRequestError("delimitador do CSV invalido, use ponto e virgula, virgula ou tab")
+	}
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.Comma = comma
+	reader.FieldsPerRecord = -1
+
+	rows := make([][]string, 0)
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) {
+				return nil, rest.NewBadRequestError(fmt.Sprintf("erro ao ler CSV: linha %d, %v", parseErr.StartLine, parseErr.Err))
+			}
+			return nil, rest.NewBadRequestError("erro ao ler arquivo CSV")
+		}
+
+		// O leitor pula linhas em branco; completa com linhas vazias para manter a numeracao
+		line, _ := reader.FieldPos(0)
+		for len(rows) < line-1 {
+			rows = append(rows, nil)
+		}
+		rows = append(rows, record)
+	}
+	return rows, nil
+}
+
+// detectCSVDelimiter picks the accepted separator that appears most in the first lines of data
+func detectCSVDelimiter(data []byte) (rune, bool) {
+	head := data
+	for i, lines := 0, 0; i < len(data); i++ {
+		if data[i] == '\n' {
+			if lines++; lines == 5 {
+				head = data[:i]
+				break
+			}
+		}
+	}
+
+	best, bestCount := rune(0), 0
+	for _, delimiter := range csvDelimiters {
+		if count := bytes.Count(head, []byte(string(delimiter))); count > bestCount {
+			best, bestCount = delimiter, count
+		}
+	}
+	return best, bestCount > 0
+}
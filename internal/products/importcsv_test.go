@@ -0,0 +1,165 @@
+package products
+
+import (
+	"context"
+	"errors"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"strings"
+	"testing"
+
+	repo "github.com/freitasmatheusrn/lifecycle-monitor/internal/database/postgres/sqlc"
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+func TestReadCSVRows(t *testing.T) {
+	data := utf8BOM + "Inventario;;;\n" +
+		"Item;Área;Descrição;Código Fabricante\n" +
+		"1;Linha 1;CPU;6ES7214-1AG40-0XB0\n" +
+		"\n" +
+		"2;Linha 1;\"Contator; 24V\";3RT2015-1BB41\n"
+
+	rows, apiErr := readCSVRows(strings.NewReader(data))
+	if apiErr != nil {
+		t.Fatalf("unexpected error: %v", apiErr.Message)
+	}
+
+	if len(rows) != 5 {
+		t.Fatalf("expected 5 rows (blank line kept), got %d: %q", len(rows), rows)
+	}
+	if rows[0][0] != "Inventario" {
+		t.Errorf("expected the BOM to be dropped, got %q", rows[0][0])
+	}
+	if rows[2][3] != "6ES7214-1AG40-0XB0" {
+		t.Errorf("expected row 3 column D to be the code, got %q", rows[2])
+	}
+	if len(rows[3]) != 0 {
+		t.Errorf("expected row 4 to be empty, got %q", rows[3])
+	}
+	if rows[4][2] != "Contator; 24V" || rows[4][3] != "3RT2015-1BB41" {
+		t.Errorf("expected quoted delimiter to stay in the field, got %q", rows[4])
+	}
+}
+
+func TestReadCSVRows_Delimiters(t *testing.T) {
+	for name, data := range map[string]string{
+		"comma": "a,b,c,d\n,Linha 1,CPU,6ES7214-1AG40-0XB0\n",
+		"tab":   "a\tb\tc\td\n\tLinha 1\tCPU\t6ES7214-1AG40-0XB0\n",
+	} {
+		t.Run(name, func(t *testing.T) {
+			rows, apiErr := readCSVRows(strings.NewReader(data))
+			if apiErr != nil {
+				t.Fatalf("unexpected error: %v", apiErr.Message)
+			}
+			if len(rows) != 2 || rows[1][3] != "6ES7214-1AG40-0XB0" {
+				t.Errorf("unexpected rows %q", rows)
+			}
+		})
+	}
+}
+
+func TestReadCSVRows_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		message string
+	}{
+		{"empty", utf8BOM + "\n\n", "arquivo CSV vazio"},
+		{"latin1", "Area;Descri\xe7\xe3o\n", "codificacao do CSV invalida, salve o arquivo em UTF-8"},
+		{"no delimiter", "Area|Descricao|Codigo\n", "delimitador do CSV invalido, use ponto e virgula, virgula ou tab"},
+		{"bad quote", "a;b\n1;\"aberto\n2;x\n", "erro ao ler CSV: linha 2, extraneous or missing \" in quoted-field"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, apiErr := readCSVRows(strings.NewReader(tt.data))
+			if apiErr == nil {
+				t.Fatal("expected an error")
+			}
+			if apiErr.Code != http.StatusBadRequest || apiErr.Message != tt.message {
+				t.Errorf("expected 400 %q, got %d %q", tt.message, apiErr.Code, apiErr.Message)
+			}
+		})
+	}
+}
+
+func TestImportFormatOf(t *testing.T) {
+	tests := []struct {
+		filename    string
+		contentType string
+		expected    ImportFormat
+	}{
+		{"estoque.xlsx", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", ImportFormatXLSX},
+		{"EXPORT.CSV", "application/vnd.ms-excel", ImportFormatCSV},
+		{"export", "text/csv; charset=utf-8", ImportFormatCSV},
+		{"export", "", ImportFormatXLSX},
+	}
+
+	for _, tt := range tests {
+		file := &multipart.FileHeader{Filename: tt.filename, Header: textproto.MIMEHeader{}}
+		if tt.contentType != "" {
+			file.Header.Set("Content-Type", tt.contentType)
+		}
+		if got := importFormatOf(file); got != tt.expected {
+			t.Errorf("%s (%s): expected %q, got %q", tt.filename, tt.contentType, tt.expected, got)
+		}
+	}
+}
+
+// csvImportQuerier has no products yet and records the ones created; creating failCode fails
+type csvImportQuerier struct {
+	repo.Querier
+	created  []repo.CreateProductParams
+	failCode string
+}
+
+func (q *csvImportQuerier) FindAreaByName(ctx context.Context, name string) (repo.Area, error) {
+	return repo.Area{}, pgx.ErrNoRows
+}
+
+func (q *csvImportQuerier) FindProductByCodeAndArea(ctx context.Context, arg repo.FindProductByCodeAndAreaParams) (repo.FindProductByCodeAndAreaRow, error) {
+	return repo.FindProductByCodeAndAreaRow{}, pgx.ErrNoRows
+}
+
+func (q *csvImportQuerier) FindProductsByCodes(ctx context.Context, codes []string) ([]repo.FindProductsByCodesRow, error) {
+	return nil, nil
+}
+
+func (q *csvImportQuerier) CreateProduct(ctx context.Context, arg repo.CreateProductParams) (repo.Product, error) {
+	if arg.Code == q.failCode {
+		return repo.Product{}, errors.New("insert failed")
+	}
+	q.created = append(q.created, arg)
+	return repo.Product{Code: arg.Code, Quantity: arg.Quantity}, nil
+}
+
+func TestImportFromSpreadsheet_CSV(t *testing.T) {
+	data := ";Área;Descrição;Código Fabricante;Qtd;Código SAP;Obs;MIN;MAX;STATUS\n" +
+		"\n" +
+		";Linha 1;CPU S7-1200;6ES7214-1AG40-0XB0;3;100200;;1;5;OK\n" +
+		";Linha 1;Sem codigo;;1\n"
+
+	querier := &csvImportQuerier{}
+	service := &svc{repo: querier, logger: zap.NewNop(), baseURL: "https://example.com"}
+
+	result, apiErr := service.ImportFromSpreadsheet(t.Context(), ImportInput{
+		File:   strings.NewReader(data),
+		Format: ImportFormatCSV,
+	})
+	if apiErr != nil {
+		t.Fatalf("unexpected error: %v", apiErr.Message)
+	}
+
+	if result.Created != 1 || len(querier.created) != 1 {
+		t.Fatalf("expected 1 product created, got %d (%+v)", result.Created, querier.created)
+	}
+	created := querier.created[0]
+	if created.Code != "6ES7214-1AG40-0XB0" || created.Quantity.Int32 != 3 || created.SapCode.String != "100200" {
+		t.Errorf("unexpected product %+v", created)
+	}
+	if created.Url != "https://example.com/6ES7214-1AG40-0XB0" {
+		t.Errorf("unexpected url %q", created.Url)
+	}
+}
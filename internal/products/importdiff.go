@@ -0,0 +1,41 @@
+package products
+
+import (
+	"strconv"
+
+	repo "github.com/freitasmatheusrn/lifecycle-monitor/internal/database/postgres/sqlc"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// importFieldChanges compares a spreadsheet row with the product it would update and returns
+// only the fields whose value differs. NULL reads as an empty value.
+func importFieldChanges(existing repo.FindProductByCodeAndAreaRow, update repo.UpdateProductParams) []ImportFieldChange {
+	fields := []struct {
+		name     string
+		old, new string
+	}{
+		{"description", existing.Description.String, update.Description.String},
+		{"manufacturer_code", existing.ManufacturerCode.String, update.ManufacturerCode.String},
+		{"quantity", int4Value(existing.Quantity), int4Value(update.Quantity)},
+		{"sap_code", existing.SapCode.String, update.SapCode.String},
+		{"observations", existing.Observations.String, update.Observations.String},
+		{"min_quantity", int4Value(existing.MinQuantity), int4Value(update.MinQuantity)},
+		{"max_quantity", int4Value(existing.MaxQuantity), int4Value(update.MaxQuantity)},
+		{"inventory_status", existing.InventoryStatus.String, update.InventoryStatus.String},
+	}
+
+	var changes []ImportFieldChange
+	for _, field := range fields {
+		if field.old != field.new {
+			changes = append(changes, ImportFieldChange{Field: field.name, Old: field.old, New: field.new})
+		}
+	}
+	return changes
+}
+
+func int4Value(i pgtype.Int4) string {
+	if !i.Valid {
+		return ""
+	}
+	return strconv.Itoa(int(i.Int32))
+}
@@ -0,0 +1,50 @@
+package products
+
+import (
+	"testing"
+
+	repo "github.com/freitasmatheusrn/lifecycle-monitor/internal/database/postgres/sqlc"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func TestImportFieldChanges(t *testing.T) {
+	existing := repo.FindProductByCodeAndAreaRow{
+		Code:             "6ES7 214-1AG40-0XB0",
+		Description:      pgtype.Text{String: "CPU 1214C", Valid: true},
+		ManufacturerCode: pgtype.Text{String: "6ES7 214-1AG40-0XB0", Valid: true},
+		Quantity:         pgtype.Int4{Int32: 2, Valid: true},
+		MinQuantity:      pgtype.Int4{Int32: 1, Valid: true},
+		MaxQuantity:      pgtype.Int4{Int32: 4, Valid: true},
+	}
+	update := repo.UpdateProductParams{
+		Description:      toPgText("CPU 1214C"),
+		ManufacturerCode: toPgText("6ES7 214-1AG40-0XB0"),
+		Quantity:         toPgInt4(2),
+		SapCode:          toPgText(""),
+		Observations:     toPgText(""),
+		MinQuantity:      toPgInt4(1),
+		MaxQuantity:      toPgInt4(4),
+		InventoryStatus:  toPgText(""),
+	}
+
+	if changes := importFieldChanges(existing, update); len(changes) != 0 {
+		t.Fatalf("expected identical row to have no changes, got %+v", changes)
+	}
+
+	update.Quantity = toPgInt4(3)
+	update.Observations = toPgText("painel 2")
+
+	changes := importFieldChanges(existing, update)
+	want := []ImportFieldChange{
+		{Field: "quantity", Old: "2", New: "3"},
+		{Field: "observations", Old: "", New: "painel 2"},
+	}
+	if len(changes) != len(want) {
+		t.Fatalf("expected %d changes, got %+v", len(want), changes)
+	}
+	for i := range want {
+		if changes[i] != want[i] {
+			t.Errorf("change %d: expected %+v, got %+v", i, want[i], changes[i])
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package products
+
+import (
+	repo "github.com/freitasmatheusrn/lifecycle-monitor/internal/database/postgres/sqlc"
+)
+
+// Uma importacao com DryRun faz as mesmas consultas e validacoes, mas nao chama CreateProduct nem
+// UpdateProduct (nem move produtos entre areas). Estas funcoes montam o produto que a escrita
+// devolveria, para o ImportResult listar os produtos como numa importacao real.
+
+// dryRunUpdate returns product as UpdateProduct would leave it: only the valid params replace fields
+func dryRunUpdate(product repo.Product, params repo.UpdateProductParams) repo.Product {
+	if params.Code.Valid {
+		product.Code = params.Code.String
+	}
+	if params.Url.Valid {
+		product.Url = params.Url.String
+	}
+	if params.AreaID.Valid {
+		product.AreaID = params.AreaID
+	}
+	if params.Description.Valid {
+		product.Description = params.Description
+	}
+	if params.ManufacturerCode.Valid {
+		product.ManufacturerCode = params.ManufacturerCode
+	}
+	if params.Quantity.Valid {
+		product.Quantity = params.Quantity
+	}
+	if params.SapCode.Valid {
+		product.SapCode = params.SapCode
+	}
+	if params.Observations.Valid {
+		product.Observations = params.Observations
+	}
+	if params.MinQuantity.Valid {
+		product.MinQuantity = params.MinQuantity
+	}
+	if params.MaxQuantity.Valid {
+		product.MaxQuantity = params.MaxQuantity
+	}
+	if params.InventoryStatus.Valid {
+		product.InventoryStatus = params.InventoryStatus
+	}
+	return product
+}
+
+// dryRunCreate returns the product CreateProduct would insert, without the id the database generates
+func dryRunCreate(params repo.CreateProductParams) repo.Product {
+	return repo.Product{
+		Code:             params.Code,
+		Url:              params.Url,
+		AreaID:           params.AreaID,
+		Description:      params.Description,
+		ManufacturerCode: params.ManufacturerCode,
+		Quantity:         params.Quantity,
+		SapCode:          params.SapCode,
+		Observations:     params.Observations,
+		MinQuantity:      params.MinQuantity,
+		MaxQuantity:      params.MaxQuantity,
+		InventoryStatus:  params.InventoryStatus,
+	}
+}
+
+// existingImportProduct is the product found by FindProductByCodeAndArea as a repo.Product
+func existingImportProduct(row repo.FindProductByCodeAndAreaRow) repo.Product {
+	return repo.Product{
+		ID:               row.ID,
+		Code:             row.Code,
+		Url:              row.Url,
+		AreaID:           row.AreaID,
+		Description:      row.Description,
+		ManufacturerCode: row.ManufacturerCode,
+		Quantity:         row.Quantity,
+		ReplacementUrl:   row.ReplacementUrl,
+		SapCode:          row.SapCode,
+		Observations:     row.Observations,
+		MinQuantity:      row.MinQuantity,
+		MaxQuantity:      row.MaxQuantity,
+		InventoryStatus:  row.InventoryStatus,
+		LifecycleStatus:  row.LifecycleStatus,
+		CreatedAt:        row.CreatedAt,
+		UpdatedAt:        row.UpdatedAt,
+		Family:           row.Family,
+		DatasheetUrl:     row.DatasheetUrl,
+	}
+}
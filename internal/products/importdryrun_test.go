@@ -0,0 +1,85 @@
+package products
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	repo "github.com/freitasmatheusrn/lifecycle-monitor/internal/database/postgres/sqlc"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+)
+
+// dryRunQuerier serves the existing products by code and counts any write
+type dryRunQuerier struct {
+	repo.Querier
+	existing map[string]repo.FindProductByCodeAndAreaRow
+	writes   int
+}
+
+func (q *dryRunQuerier) FindAreaByName(ctx context.Context, name string) (repo.Area, error) {
+	return repo.Area{}, pgx.ErrNoRows
+}
+
+func (q *dryRunQuerier) FindProductByCodeAndArea(ctx context.Context, arg repo.FindProductByCodeAndAreaParams) (repo.FindProductByCodeAndAreaRow, error) {
+	if product, ok := q.existing[arg.Code]; ok {
+		return product, nil
+	}
+	return repo.FindProductByCodeAndAreaRow{}, pgx.ErrNoRows
+}
+
+func (q *dryRunQuerier) FindProductsByCodes(ctx context.Context, codes []string) ([]repo.FindProductsByCodesRow, error) {
+	return nil, nil
+}
+
+func (q *dryRunQuerier) CreateProduct(ctx context.Context, arg repo.CreateProductParams) (repo.Product, error) {
+	q.writes++
+	return repo.Product{}, nil
+}
+
+func (q *dryRunQuerier) UpdateProduct(ctx context.Context, arg repo.UpdateProductParams) (repo.Product, error) {
+	q.writes++
+	return repo.Product{}, nil
+}
+
+func TestImportFromSpreadsheet_DryRun(t *testing.T) {
+	existingID := pgtype.UUID{Bytes: [16]byte{7}, Valid: true}
+	querier := &dryRunQuerier{existing: map[string]repo.FindProductByCodeAndAreaRow{
+		"6ES7214-1AG40-0XB0": {
+			ID:          existingID,
+			Code:        "6ES7214-1AG40-0XB0",
+			Description: pgtype.Text{String: "CPU", Valid: true},
+			Quantity:    pgtype.Int4{Int32: 1, Valid: true},
+		},
+	}}
+	service := &svc{repo: querier, logger: zap.NewNop()}
+
+	data := ";Área;Descrição;Código Fabricante;Qtd\n" +
+		"\n" +
+		";;CPU S7-1200;6ES7214-1AG40-0XB0;4\n" +
+		";;Contator;3RT2015-1BB41;2\n"
+
+	result, apiErr := service.ImportFromSpreadsheet(t.Context(), ImportInput{
+		File:   strings.NewReader(data),
+		Format: ImportFormatCSV,
+		DryRun: true,
+	})
+	if apiErr != nil {
+		t.Fatalf("unexpected error: %v", apiErr.Message)
+	}
+
+	if querier.writes != 0 {
+		t.Errorf("expected no writes in a dry run, got %d", querier.writes)
+	}
+	if !result.DryRun || result.Updated != 1 || result.Created != 1 || result.Failed != 0 {
+		t.Errorf("expected 1 updated and 1 created in a dry run, got %+v", result)
+	}
+	if len(result.Products) != 2 {
+		t.Fatalf("expected the 2 products in the result, got %+v", result.Products)
+	}
+	updated := result.Products[0]
+	if updated.ID != existingID || updated.Description != "CPU S7-1200" || updated.Quantity != 4 {
+		t.Errorf("expected the existing product with the row values, got %+v", updated)
+	}
+}
@@ -0,0 +1,51 @@
+package products
+
+import (
+	"context"
+
+	repo "github.com/freitasmatheusrn/lifecycle-monitor/internal/database/postgres/sqlc"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+)
+
+// copyExistingSnapshot gives a product just created by the import the latest snapshot collected for
+// its code in another area, so the code isn't crawled again. Returns false when there is nothing to
+// copy (or the copy failed), and the product is crawled as usual.
+func (s *svc) copyExistingSnapshot(ctx context.Context, productID pgtype.UUID, code string) bool {
+	hasSnapshot, err := s.repo.HasSnapshotForCode(ctx, code)
+	if err != nil {
+		s.logger.Warn("failed to check snapshots of code", zap.String("code", code), zap.Error(err))
+		return false
+	}
+	if !hasSnapshot {
+		return false
+	}
+
+	latest, err := s.repo.GetLatestSnapshotByCode(ctx, code)
+	if err != nil {
+		s.logger.Warn("failed to get latest snapshot of code", zap.String("code", code), zap.Error(err))
+		return false
+	}
+
+	if _, err := s.repo.CreateSnapshot(ctx, repo.CreateSnapshotParams{
+		ProductID:   productID,
+		Description: latest.Description,
+		Status:      latest.Status,
+		Source:      pgtype.Text{String: SnapshotSourceImport, Valid: true},
+	}); err != nil {
+		s.logger.Warn("failed to copy snapshot", zap.String("code", code), zap.Error(err))
+		return false
+	}
+
+	// Os demais produtos com o codigo ja tem o status; so o novo e atualizado
+	if latest.Status.Valid {
+		if err := s.repo.UpdateProductLifecycleStatus(ctx, repo.UpdateProductLifecycleStatusParams{
+			Code:            code,
+			LifecycleStatus: latest.Status,
+		}); err != nil {
+			s.logger.Warn("failed to update product lifecycle status", zap.String("code", code), zap.Error(err))
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,99 @@
+package products
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	repo "github.com/freitasmatheusrn/lifecycle-monitor/internal/database/postgres/sqlc"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+)
+
+// collectedCodeQuerier creates every product; codes in latest were already collected in another area
+type collectedCodeQuerier struct {
+	csvImportQuerier
+	latest    map[string]repo.ProductSnapshot
+	snapshots []repo.CreateSnapshotParams
+	statuses  []repo.UpdateProductLifecycleStatusParams
+}
+
+func (q *collectedCodeQuerier) HasSnapshotForCode(ctx context.Context, code string) (bool, error) {
+	_, ok := q.latest[code]
+	return ok, nil
+}
+
+func (q *collectedCodeQuerier) GetLatestSnapshotByCode(ctx context.Context, code string) (repo.ProductSnapshot, error) {
+	snapshot, ok := q.latest[code]
+	if !ok {
+		return repo.ProductSnapshot{}, pgx.ErrNoRows
+	}
+	return snapshot, nil
+}
+
+func (q *collectedCodeQuerier) CreateSnapshot(ctx context.Context, arg repo.CreateSnapshotParams) (repo.ProductSnapshot, error) {
+	q.snapshots = append(q.snapshots, arg)
+	return repo.ProductSnapshot{ProductID: arg.ProductID, Status: arg.Status}, nil
+}
+
+func (q *collectedCodeQuerier) UpdateProductLifecycleStatus(ctx context.Context, arg repo.UpdateProductLifecycleStatusParams) error {
+	q.statuses = append(q.statuses, arg)
+	return nil
+}
+
+func TestImportWithProgress_CopiesSnapshotOfCollectedCode(t *testing.T) {
+	data := "\n" +
+		";Área;Descrição;Código Fabricante;Qtd\n" +
+		"\n" +
+		";;CPU S7-1200;6ES7214-1AG40-0XB0;3\n" +
+		";;Contator;3RT2015-1BB41;1\n"
+
+	querier := &collectedCodeQuerier{latest: map[string]repo.ProductSnapshot{
+		"6ES7214-1AG40-0XB0": {
+			Description: "CPU 1214C",
+			Status:      pgtype.Text{String: StatusPhaseOut, Valid: true},
+		},
+	}}
+	service := &svc{repo: querier, logger: zap.NewNop(), baseURL: "https://example.com"}
+
+	messages := make(map[string]string)
+	var complete ImportProgressEvent
+	_, apiErr := service.ImportFromSpreadsheetWithProgress(t.Context(), ImportInput{
+		File:   strings.NewReader(data),
+		Format: ImportFormatCSV,
+	}, func(event ImportProgressEvent) {
+		switch event.Type {
+		case ImportEventImportSuccess:
+			messages[event.Code] = event.Message
+		case ImportEventComplete:
+			complete = event
+		}
+	})
+	if apiErr != nil {
+		t.Fatalf("unexpected error: %v", apiErr.Message)
+	}
+
+	if got := messages["6ES7214-1AG40-0XB0"]; got != "status copiado de area existente" {
+		t.Errorf("expected the collected code to get its status copied, got %q", got)
+	}
+	if got := messages["3RT2015-1BB41"]; got != "produto criado" {
+		t.Errorf("expected the new code to be created only, got %q", got)
+	}
+
+	if len(querier.snapshots) != 1 {
+		t.Fatalf("expected 1 snapshot copied, got %+v", querier.snapshots)
+	}
+	copied := querier.snapshots[0]
+	if copied.Description != "CPU 1214C" || copied.Status.String != StatusPhaseOut || copied.Source.String != SnapshotSourceImport {
+		t.Errorf("unexpected copied snapshot %+v", copied)
+	}
+	if len(querier.statuses) != 1 || querier.statuses[0].Code != "6ES7214-1AG40-0XB0" || querier.statuses[0].LifecycleStatus.String != StatusPhaseOut {
+		t.Errorf("expected the lifecycle status copied to the new product, got %+v", querier.statuses)
+	}
+
+	// Only the code never collected is left for the scheduler
+	if complete.Message == "" {
+		t.Error("expected the crawl of the uncollected code to be deferred")
+	}
+}
@@ -0,0 +1,36 @@
+package products
+
+import (
+	"context"
+
+	"github.com/freitasmatheusrn/lifecycle-monitor/pkg/rest"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// GetInventorySummary aggregates the inventory in the database, optionally restricted to one area
+// (an invalid areaID means all areas). Products without an area count in the totals but not in
+// any per-area entry.
+func (s *svc) GetInventorySummary(ctx context.Context, areaID pgtype.UUID) (*InventorySummary, *rest.ApiErr) {
+	totals, err := s.repo.GetInventorySummary(ctx, areaID)
+	if err != nil {
+		return nil, s.handleDBError(err)
+	}
+
+	areaRows, err := s.repo.ListInventoryByArea(ctx, areaID)
+	if err != nil {
+		return nil, s.handleDBError(err)
+	}
+
+	summary := &InventorySummary{
+		TotalProducts:     totals.TotalProducts,
+		TotalQuantity:     totals.TotalQuantity,
+		ActiveCount:       totals.ActiveCount,
+		DiscontinuedCount: totals.DiscontinuedCount,
+		PhaseOutCount:     totals.PhaseOutCount,
+		Areas:             make([]AreaInventory, 0, len(areaRows)),
+	}
+	for _, row := range areaRows {
+		summary.Areas = append(summary.Areas, AreaInventory(row))
+	}
+	return summary, nil
+}
@@ -0,0 +1,47 @@
+package products
+
+import (
+	"context"
+	"testing"
+
+	repo "github.com/freitasmatheusrn/lifecycle-monitor/internal/database/postgres/sqlc"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+)
+
+// inventoryQuerier returns fixed aggregates and records the area filter of each query
+type inventoryQuerier struct {
+	repo.Querier
+	summaryArea pgtype.UUID
+	byAreaArea  pgtype.UUID
+}
+
+func (q *inventoryQuerier) GetInventorySummary(ctx context.Context, areaID pgtype.UUID) (repo.GetInventorySummaryRow, error) {
+	q.summaryArea = areaID
+	return repo.GetInventorySummaryRow{TotalProducts: 3, TotalQuantity: 42, ActiveCount: 2, PhaseOutCount: 1}, nil
+}
+
+func (q *inventoryQuerier) ListInventoryByArea(ctx context.Context, areaID pgtype.UUID) ([]repo.ListInventoryByAreaRow, error) {
+	q.byAreaArea = areaID
+	return []repo.ListInventoryByAreaRow{{AreaID: areaID, AreaName: "Laminacao", TotalProducts: 3, TotalQuantity: 42}}, nil
+}
+
+func TestGetInventorySummary(t *testing.T) {
+	querier := &inventoryQuerier{}
+	service := &svc{repo: querier, logger: zap.NewNop()}
+	areaID := pgtype.UUID{Bytes: [16]byte{7}, Valid: true}
+
+	summary, apiErr := service.GetInventorySummary(t.Context(), areaID)
+	if apiErr != nil {
+		t.Fatalf("unexpected error: %v", apiErr.Message)
+	}
+	if querier.summaryArea != areaID || querier.byAreaArea != areaID {
+		t.Errorf("expected both aggregates filtered by the area, got %v and %v", querier.summaryArea, querier.byAreaArea)
+	}
+	if summary.TotalProducts != 3 || summary.TotalQuantity != 42 || summary.ActiveCount != 2 || summary.PhaseOutCount != 1 {
+		t.Errorf("unexpected totals: %+v", summary)
+	}
+	if len(summary.Areas) != 1 || summary.Areas[0].AreaName != "Laminacao" || summary.Areas[0].TotalQuantity != 42 {
+		t.Errorf("unexpected per-area totals: %+v", summary.Areas)
+	}
+}
@@ -0,0 +1,24 @@
+package products
+
+// Status de ciclo de vida exibidos no portal
+const (
+	StatusActive       = "Active Product"
+	StatusPhaseOut     = "Phase Out Announce"
+	StatusCancellation = "Prod. Cancellation"
+	StatusEndLifecycle = "End Prod.Lifecycl."
+	StatusDiscontinued = "Prod. Discont."
+)
+
+// IsTerminalStatus reports whether status means the product is on its way out: these statuses
+// trigger the replacement code extraction and the "terminal" alert route.
+// Phase out only counts when phaseOutTerminal is set; by default it is informational.
+func IsTerminalStatus(status string, phaseOutTerminal bool) bool {
+	switch status {
+	case StatusCancellation, StatusEndLifecycle, StatusDiscontinued:
+		return true
+	case StatusPhaseOut:
+		return phaseOutTerminal
+	default:
+		return false
+	}
+}
@@ -0,0 +1,52 @@
+package products
+
+import (
+	"context"
+
+	repo "github.com/freitasmatheusrn/lifecycle-monitor/internal/database/postgres/sqlc"
+	"go.uber.org/zap"
+)
+
+// attachLatestSnapshots fills LatestSnapshot of each listed product with a single query for the
+// whole page. The listing groups products by code, so the snapshot is the latest of the code
+// across all areas. A failed lookup does not fail the listing: each product carries SnapshotError,
+// as in GetProduct.
+func (s *svc) attachLatestSnapshots(ctx context.Context, products []ProductWithSnapshotOutput) {
+	if len(products) == 0 {
+		return
+	}
+
+	codes := make([]string, 0, len(products))
+	for _, p := range products {
+		codes = append(codes, p.Product.Code)
+	}
+
+	rows, err := s.repo.ListLatestSnapshotsByCodes(ctx, codes)
+	if err != nil {
+		s.logger.Warn("failed to get latest snapshots for listing", zap.Error(err))
+		for i := range products {
+			products[i].SnapshotError = "erro ao buscar ultima coleta do produto"
+		}
+		return
+	}
+
+	latest := make(map[string]*SnapshotOutput, len(rows))
+	for _, row := range rows {
+		latest[row.Code] = latestSnapshotByCodeToOutput(row)
+	}
+	for i := range products {
+		products[i].LatestSnapshot = latest[products[i].Product.Code]
+	}
+}
+
+func latestSnapshotByCodeToOutput(row repo.ListLatestSnapshotsByCodesRow) *SnapshotOutput {
+	return &SnapshotOutput{
+		ID:          row.ID,
+		ProductID:   row.ProductID,
+		Description: row.Description,
+		Status:      row.Status.String,
+		CollectedAt: row.CollectedAt.Time,
+		Source:      row.Source.String,
+		RunID:       row.RunID,
+	}
+}
@@ -0,0 +1,55 @@
+package products
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	repo "github.com/freitasmatheusrn/lifecycle-monitor/internal/database/postgres/sqlc"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+)
+
+// latestByCodeQuerier answers ListLatestSnapshotsByCodes and counts the calls
+type latestByCodeQuerier struct {
+	repo.Querier
+	rows  []repo.ListLatestSnapshotsByCodesRow
+	err   error
+	calls int
+}
+
+func (q *latestByCodeQuerier) ListLatestSnapshotsByCodes(ctx context.Context, codes []string) ([]repo.ListLatestSnapshotsByCodesRow, error) {
+	q.calls++
+	return q.rows, q.err
+}
+
+func TestAttachLatestSnapshots(t *testing.T) {
+	snapshotID := pgtype.UUID{Bytes: [16]byte{9}, Valid: true}
+	querier := &latestByCodeQuerier{rows: []repo.ListLatestSnapshotsByCodesRow{
+		{Code: "A", ID: snapshotID, Status: pgtype.Text{String: "Active Product", Valid: true}},
+	}}
+	service := &svc{repo: querier, logger: zap.NewNop()}
+
+	products := []ProductWithSnapshotOutput{
+		{Product: ProductOutput{Code: "A"}},
+		{Product: ProductOutput{Code: "B"}},
+	}
+	service.attachLatestSnapshots(t.Context(), products)
+
+	if querier.calls != 1 {
+		t.Errorf("expected a single query for the page, got %d", querier.calls)
+	}
+	if products[0].LatestSnapshot == nil || products[0].LatestSnapshot.ID != snapshotID || products[0].LatestSnapshot.Status != "Active Product" {
+		t.Errorf("unexpected snapshot for A: %+v", products[0].LatestSnapshot)
+	}
+	if products[1].LatestSnapshot != nil {
+		t.Errorf("expected no snapshot for a code never collected, got %+v", products[1].LatestSnapshot)
+	}
+
+	failing := &svc{repo: &latestByCodeQuerier{err: errors.New("connection reset")}, logger: zap.NewNop()}
+	products = []ProductWithSnapshotOutput{{Product: ProductOutput{Code: "A"}}}
+	failing.attachLatestSnapshots(t.Context(), products)
+	if products[0].SnapshotError == "" || products[0].LatestSnapshot != nil {
+		t.Errorf("expected SnapshotError on a failed lookup, got %+v", products[0])
+	}
+}
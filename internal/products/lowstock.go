@@ -0,0 +1,24 @@
+package products
+
+import (
+	"context"
+
+	repo "github.com/freitasmatheusrn/lifecycle-monitor/internal/database/postgres/sqlc"
+	"github.com/freitasmatheusrn/lifecycle-monitor/pkg/rest"
+)
+
+// ListBelowMinimum returns the products whose quantity is below their minimum, ordered by area.
+// Quantity and minimum are kept per area, so a code short in one area is listed only for that area
+// even when other areas hold enough. Products without a minimum (zero) are never listed.
+func (s *svc) ListBelowMinimum(ctx context.Context) ([]ProductOutput, *rest.ApiErr) {
+	rows, err := s.repo.ListProductsBelowMinimum(ctx)
+	if err != nil {
+		return nil, s.handleDBError(err)
+	}
+
+	products := make([]ProductOutput, 0, len(rows))
+	for _, row := range rows {
+		products = append(products, rowToProductOutputFromFindByCode(repo.FindProductByCodeRow(row)))
+	}
+	return products, nil
+}
@@ -0,0 +1,47 @@
+package products
+
+import (
+	"context"
+	"testing"
+
+	repo "github.com/freitasmatheusrn/lifecycle-monitor/internal/database/postgres/sqlc"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+)
+
+// lowStockQuerier serves a fixed list of products below their minimum
+type lowStockQuerier struct {
+	repo.Querier
+	rows []repo.ListProductsBelowMinimumRow
+}
+
+func (q *lowStockQuerier) ListProductsBelowMinimum(ctx context.Context) ([]repo.ListProductsBelowMinimumRow, error) {
+	return q.rows, nil
+}
+
+func TestListBelowMinimum(t *testing.T) {
+	querier := &lowStockQuerier{rows: []repo.ListProductsBelowMinimumRow{{
+		Code:        "6ES7214-1AG40-0XB0",
+		AreaName:    pgtype.Text{String: "Linha 1", Valid: true},
+		Quantity:    pgtype.Int4{Int32: 1, Valid: true},
+		MinQuantity: pgtype.Int4{Int32: 3, Valid: true},
+	}}}
+	service := &svc{repo: querier, logger: zap.NewNop()}
+
+	products, apiErr := service.ListBelowMinimum(t.Context())
+	if apiErr != nil {
+		t.Fatalf("unexpected error: %v", apiErr.Message)
+	}
+	if len(products) != 1 {
+		t.Fatalf("expected 1 product, got %+v", products)
+	}
+	p := products[0]
+	if p.Code != "6ES7214-1AG40-0XB0" || p.AreaName != "Linha 1" || p.Quantity != 1 || p.MinQuantity != 3 {
+		t.Errorf("unexpected product %+v", p)
+	}
+
+	querier.rows = nil
+	if products, _ := service.ListBelowMinimum(t.Context()); products == nil || len(products) != 0 {
+		t.Errorf("expected an empty list, got %#v", products)
+	}
+}
@@ -0,0 +1,91 @@
+package products
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// IngestionMetrics counts the products added and imported since the process started.
+// The zero value is ready to use and safe for concurrent requests.
+type IngestionMetrics struct {
+	added           atomic.Int64
+	existing        atomic.Int64
+	addFailed       atomic.Int64
+	importCreated   atomic.Int64
+	importUpdated   atomic.Int64
+	importUnchanged atomic.Int64
+	importSkipped   atomic.Int64
+	importFailed    atomic.Int64
+	startedAt       time.Time
+}
+
+// NewIngestionMetrics starts counting now
+func NewIngestionMetrics() *IngestionMetrics {
+	return &IngestionMetrics{startedAt: time.Now()}
+}
+
+// IngestionStats is a snapshot of the ingestion counters, returned by GET /admin/ingestion/stats
+type IngestionStats struct {
+	Since             time.Time `json:"since,omitzero"` // contadores sao zerados a cada reinicio
+	Added             int64     `json:"added"`
+	Existing          int64     `json:"existing"`
+	AddFailed         int64     `json:"add_failed"`
+	AddSuccessRate    float64   `json:"add_success_rate"` // (added + existing) / total; 0 sem tentativas
+	ImportCreated     int64     `json:"import_created"`
+	ImportUpdated     int64     `json:"import_updated"`
+	ImportUnchanged   int64     `json:"import_unchanged"`
+	ImportSkipped     int64     `json:"import_skipped"`
+	ImportFailed      int64     `json:"import_failed"`
+	ImportSuccessRate float64   `json:"import_success_rate"` // linhas sem falha / linhas processadas; 0 sem linhas
+}
+
+// RecordAdd counts the outcome of an add request
+func (m *IngestionMetrics) RecordAdd(result *AddProductsResult) {
+	if m == nil || result == nil {
+		return
+	}
+	m.added.Add(int64(len(result.Added)))
+	m.existing.Add(int64(len(result.Existing)))
+	m.addFailed.Add(int64(len(result.Failed)))
+}
+
+// RecordImport counts the outcome of a spreadsheet import
+func (m *IngestionMetrics) RecordImport(result *ImportResult) {
+	if m == nil || result == nil {
+		return
+	}
+	m.importCreated.Add(int64(result.Created))
+	m.importUpdated.Add(int64(result.Updated))
+	m.importUnchanged.Add(int64(result.Unchanged))
+	m.importSkipped.Add(int64(result.Skipped))
+	m.importFailed.Add(int64(result.Failed))
+}
+
+// Stats returns the current counters and success rates
+func (m *IngestionMetrics) Stats() IngestionStats {
+	if m == nil {
+		return IngestionStats{}
+	}
+
+	stats := IngestionStats{
+		Since:           m.startedAt,
+		Added:           m.added.Load(),
+		Existing:        m.existing.Load(),
+		AddFailed:       m.addFailed.Load(),
+		ImportCreated:   m.importCreated.Load(),
+		ImportUpdated:   m.importUpdated.Load(),
+		ImportUnchanged: m.importUnchanged.Load(),
+		ImportSkipped:   m.importSkipped.Load(),
+		ImportFailed:    m.importFailed.Load(),
+	}
+	stats.AddSuccessRate = successRate(stats.Added+stats.Existing, stats.AddFailed)
+	stats.ImportSuccessRate = successRate(stats.ImportCreated+stats.ImportUpdated+stats.ImportUnchanged+stats.ImportSkipped, stats.ImportFailed)
+	return stats
+}
+
+func successRate(succeeded, failed int64) float64 {
+	if succeeded+failed == 0 {
+		return 0
+	}
+	return float64(succeeded) / float64(succeeded+failed)
+}
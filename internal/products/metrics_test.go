@@ -0,0 +1,49 @@
+package products
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestIngestionMetrics_ConcurrentRecords(t *testing.T) {
+	metrics := NewIngestionMetrics()
+
+	var wg sync.WaitGroup
+	for range 50 {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			metrics.RecordAdd(&AddProductsResult{
+				Added:    make([]ProductOutput, 2),
+				Existing: make([]ProductOutput, 1),
+				Failed:   make([]FailedProduct, 1),
+			})
+		}()
+		go func() {
+			defer wg.Done()
+			metrics.RecordImport(&ImportResult{Created: 3, Updated: 1, Unchanged: 1, Skipped: 1, Failed: 2})
+		}()
+	}
+	wg.Wait()
+
+	stats := metrics.Stats()
+	if stats.Added != 100 || stats.Existing != 50 || stats.AddFailed != 50 {
+		t.Errorf("unexpected add counters: %+v", stats)
+	}
+	if stats.ImportCreated != 150 || stats.ImportUpdated != 50 || stats.ImportUnchanged != 50 || stats.ImportSkipped != 50 || stats.ImportFailed != 100 {
+		t.Errorf("unexpected import counters: %+v", stats)
+	}
+	if stats.AddSuccessRate != 0.75 || stats.ImportSuccessRate != 0.75 {
+		t.Errorf("expected success rates of 0.75, got add=%v import=%v", stats.AddSuccessRate, stats.ImportSuccessRate)
+	}
+}
+
+func TestIngestionMetrics_Empty(t *testing.T) {
+	var metrics *IngestionMetrics
+	metrics.RecordAdd(&AddProductsResult{})
+	metrics.RecordImport(nil)
+
+	if stats := NewIngestionMetrics().Stats(); stats.AddSuccessRate != 0 || stats.ImportSuccessRate != 0 {
+		t.Errorf("expected zero success rates without records, got %+v", stats)
+	}
+}
@@ -0,0 +1,67 @@
+package products
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/playwright-community/playwright-go"
+)
+
+// ErrProductNotFound is returned by Collect when the site says the product code doesn't exist,
+// so callers can tell a bad code from a transient crawl failure
+var ErrProductNotFound = errors.New("product not found on the site")
+
+// Failure reasons shown for the crawl errors with a known kind
+const (
+	reasonNotFoundOnSite = "produto nao encontrado no site"
+	reasonCrawlTimeout   = "tempo limite da coleta esgotado"
+	reasonNavigation     = "falha ao abrir a pagina do produto"
+)
+
+// crawlFailureReason returns the reason shown for a failed crawl: timeouts, navigation failures
+// and codes the site doesn't know get their own reason, anything else gets fallback
+func crawlFailureReason(err error, fallback string) string {
+	switch classifyCrawlError(err) {
+	case KindNotFound:
+		return reasonNotFoundOnSite
+	case KindTimeout:
+		return reasonCrawlTimeout
+	case KindNavigation:
+		return reasonNavigation
+	}
+	return fallback
+}
+
+// notFoundReason reports whether the page is the site's answer for an unknown code:
+// a redirect away from the product page or one of the not found markers in the DOM
+func (c *Crawler) notFoundReason(page playwright.Page, productCode string) (string, bool) {
+	if redirectedAway(page.URL(), productCode) {
+		return "redirected to " + page.URL(), true
+	}
+	for _, marker := range c.selectors.NotFound {
+		if count, _ := page.Locator(marker).Count(); count > 0 {
+			return "page shows " + marker, true
+		}
+	}
+	return "", false
+}
+
+// isNotFoundStatus reports the HTTP statuses the site uses for unknown products
+func isNotFoundStatus(status int) bool {
+	return status == http.StatusNotFound || status == http.StatusGone
+}
+
+// redirectedAway reports whether finalURL no longer points at productCode's page
+// (the site sends unknown codes to its search or home page)
+func redirectedAway(finalURL, productCode string) bool {
+	path := finalURL
+	if parsed, err := url.Parse(finalURL); err == nil {
+		path = parsed.Path
+	}
+	if unescaped, err := url.PathUnescape(path); err == nil {
+		path = unescaped
+	}
+	return !strings.Contains(strings.ToLower(path), strings.ToLower(productCode))
+}
@@ -0,0 +1,40 @@
+package products
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestRedirectedAway(t *testing.T) {
+	tests := []struct {
+		finalURL string
+		code     string
+		expected bool
+	}{
+		{finalURL: "https://sieportal.siemens.com/en-ww/products-services/detail/6ES7214-1AG40-0XB0", code: "6ES7214-1AG40-0XB0", expected: false},
+		{finalURL: "https://sieportal.siemens.com/en-ww/products-services/detail/6es7214-1ag40-0xb0?tree=x", code: "6ES7214-1AG40-0XB0", expected: false},
+		{finalURL: "https://sieportal.siemens.com/en-ww/products-services/detail/3RT2015%201AP01", code: "3RT2015 1AP01", expected: false},
+		{finalURL: "https://sieportal.siemens.com/en-ww/search?scope=all", code: "6ES7999-9ZZ99-9ZZ9", expected: true},
+		{finalURL: "https://sieportal.siemens.com/en-ww/products-services/detail/6ES7999", code: "6ES7999-9ZZ99-9ZZ9", expected: true},
+	}
+
+	for _, tt := range tests {
+		if got := redirectedAway(tt.finalURL, tt.code); got != tt.expected {
+			t.Errorf("redirectedAway(%q, %q) = %v, expected %v", tt.finalURL, tt.code, got, tt.expected)
+		}
+	}
+}
+
+func TestCrawlFailureReason(t *testing.T) {
+	notFound := fmt.Errorf("%w: 6ES7999 (status 404)", ErrProductNotFound)
+	if got := crawlFailureReason(notFound, "falha ao coletar dados"); got != "produto nao encontrado no site" {
+		t.Errorf("expected the not found reason, got %q", got)
+	}
+	if got := crawlFailureReason(errors.New("timeout waiting for page load"), "falha ao coletar dados"); got != "falha ao coletar dados" {
+		t.Errorf("expected the fallback reason, got %q", got)
+	}
+	if !isNotFoundStatus(404) || !isNotFoundStatus(410) || isNotFoundStatus(200) {
+		t.Error("expected 404 and 410 to mean not found")
+	}
+}
@@ -0,0 +1,59 @@
+package products
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// pageLimiter caps how many browser pages are open at the same time, independent of
+// the number of workers. Callers block until a slot frees up or their context is done.
+type pageLimiter struct {
+	slots   chan struct{}
+	waiting atomic.Int64
+}
+
+func newPageLimiter(maxPages int) *pageLimiter {
+	if maxPages <= 0 {
+		maxPages = 1
+	}
+	return &pageLimiter{slots: make(chan struct{}, maxPages)}
+}
+
+// Acquire waits for a free page slot. Every successful Acquire must be paired with Release.
+func (l *pageLimiter) Acquire(ctx context.Context) error {
+	// Caminho rapido: slot livre, sem contar como espera
+	select {
+	case l.slots <- struct{}{}:
+		return nil
+	default:
+	}
+
+	l.waiting.Add(1)
+	defer l.waiting.Add(-1)
+
+	select {
+	case l.slots <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (l *pageLimiter) Release() {
+	<-l.slots
+}
+
+// PageStats reports the browser page usage of the crawler
+type PageStats struct {
+	InUse   int `json:"in_use"`
+	Waiting int `json:"waiting"`
+	Max     int `json:"max"`
+}
+
+func (l *pageLimiter) Stats() PageStats {
+	return PageStats{
+		InUse:   len(l.slots),
+		Waiting: int(l.waiting.Load()),
+		Max:     cap(l.slots),
+	}
+}
@@ -0,0 +1,65 @@
+package products
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestPageLimiter_BlocksUntilRelease(t *testing.T) {
+	limiter := newPageLimiter(1)
+
+	if err := limiter.Acquire(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	acquired := make(chan error, 1)
+	go func() {
+		acquired <- limiter.Acquire(context.Background())
+	}()
+
+	select {
+	case <-acquired:
+		t.Fatal("second Acquire should block while the only slot is in use")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	if stats := limiter.Stats(); stats.InUse != 1 || stats.Waiting != 1 || stats.Max != 1 {
+		t.Errorf("unexpected stats while waiting: %+v", stats)
+	}
+
+	limiter.Release()
+
+	select {
+	case err := <-acquired:
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("second Acquire did not proceed after Release")
+	}
+
+	limiter.Release()
+	if stats := limiter.Stats(); stats.InUse != 0 || stats.Waiting != 0 {
+		t.Errorf("expected limiter to be idle, got %+v", stats)
+	}
+}
+
+func TestPageLimiter_AcquireCancelled(t *testing.T) {
+	limiter := newPageLimiter(1)
+	if err := limiter.Acquire(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer limiter.Release()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	if err := limiter.Acquire(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected deadline exceeded, got %v", err)
+	}
+	if stats := limiter.Stats(); stats.InUse != 1 || stats.Waiting != 0 {
+		t.Errorf("cancelled Acquire should not hold a slot, got %+v", stats)
+	}
+}
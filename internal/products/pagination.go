@@ -0,0 +1,62 @@
+package products
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/freitasmatheusrn/lifecycle-monitor/pkg/rest"
+	"github.com/labstack/echo/v4"
+)
+
+// Limites de paginacao de ListProducts (expostos em GET /admin/config)
+const (
+	DefaultPageSize = 10
+	MaxPageSize     = 100
+)
+
+// StrictPaginationHeader overrides the configured pagination mode for one request ("true" or "false")
+const StrictPaginationHeader = "X-Strict-Pagination"
+
+// strictPagination reports whether bad pagination must be rejected instead of coerced:
+// the request header wins, otherwise the configured default applies
+func (h *Handler) strictPagination(c echo.Context) bool {
+	if strict, err := strconv.ParseBool(c.Request().Header.Get(StrictPaginationHeader)); err == nil {
+		return strict
+	}
+	return h.strictPaginationDefault
+}
+
+// validatePagination rejects the page/page_size values ListProducts would otherwise coerce silently.
+// Absent values are fine and get the defaults.
+func validatePagination(page, pageSize string) *rest.ApiErr {
+	var causes []rest.Causes
+	if cause, ok := paginationCause("page", page, 0); !ok {
+		causes = append(causes, cause)
+	}
+	if cause, ok := paginationCause("page_size", pageSize, MaxPageSize); !ok {
+		causes = append(causes, cause)
+	}
+
+	if len(causes) > 0 {
+		return rest.NewBadRequestValidationError("parametros de paginacao invalidos", causes)
+	}
+	return nil
+}
+
+// paginationCause checks one parameter; limit <= 0 means no upper bound
+func paginationCause(field, value string, limit int) (rest.Causes, bool) {
+	if value == "" {
+		return rest.Causes{}, true
+	}
+
+	n, err := strconv.Atoi(value)
+	switch {
+	case err != nil:
+		return rest.Causes{Field: field, Message: "deve ser um numero inteiro"}, false
+	case n < 1:
+		return rest.Causes{Field: field, Message: "deve ser maior que zero"}, false
+	case limit > 0 && n > limit:
+		return rest.Causes{Field: field, Message: fmt.Sprintf("deve ser no maximo %d", limit)}, false
+	}
+	return rest.Causes{}, true
+}
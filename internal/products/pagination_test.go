@@ -0,0 +1,75 @@
+package products
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+func TestValidatePagination(t *testing.T) {
+	tests := []struct {
+		name     string
+		page     string
+		pageSize string
+		fields   []string // fields expected in the causes; none means valid
+	}{
+		{name: "absent values", page: "", pageSize: ""},
+		{name: "valid values", page: "3", pageSize: "100"},
+		{name: "negative page", page: "-1", pageSize: "10", fields: []string{"page"}},
+		{name: "non-numeric page size", page: "1", pageSize: "ten", fields: []string{"page_size"}},
+		{name: "oversized page size and zero page", page: "0", pageSize: "101", fields: []string{"page", "page_size"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			apiErr := validatePagination(tt.page, tt.pageSize)
+			if len(tt.fields) == 0 {
+				if apiErr != nil {
+					t.Fatalf("expected no error, got %+v", apiErr)
+				}
+				return
+			}
+
+			if apiErr == nil || apiErr.Code != http.StatusBadRequest {
+				t.Fatalf("expected 400, got %+v", apiErr)
+			}
+			if len(apiErr.Causes) != len(tt.fields) {
+				t.Fatalf("expected causes for %v, got %+v", tt.fields, apiErr.Causes)
+			}
+			for i, field := range tt.fields {
+				if apiErr.Causes[i].Field != field {
+					t.Errorf("cause %d: expected field %q, got %q", i, field, apiErr.Causes[i].Field)
+				}
+			}
+		})
+	}
+}
+
+func TestStrictPagination_HeaderOverridesDefault(t *testing.T) {
+	e := echo.New()
+	tests := []struct {
+		defaultStrict bool
+		header        string
+		expected      bool
+	}{
+		{defaultStrict: false, header: "", expected: false},
+		{defaultStrict: true, header: "", expected: true},
+		{defaultStrict: false, header: "true", expected: true},
+		{defaultStrict: true, header: "false", expected: false},
+		{defaultStrict: true, header: "talvez", expected: true},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/products", nil)
+		if tt.header != "" {
+			req.Header.Set(StrictPaginationHeader, tt.header)
+		}
+		h := &Handler{strictPaginationDefault: tt.defaultStrict}
+
+		if got := h.strictPagination(e.NewContext(req, httptest.NewRecorder())); got != tt.expected {
+			t.Errorf("default=%v header=%q: expected %v, got %v", tt.defaultStrict, tt.header, tt.expected, got)
+		}
+	}
+}
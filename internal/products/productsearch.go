@@ -0,0 +1,61 @@
+package products
+
+import (
+	"context"
+
+	repo "github.com/freitasmatheusrn/lifecycle-monitor/internal/database/postgres/sqlc"
+	"github.com/freitasmatheusrn/lifecycle-monitor/pkg/rest"
+)
+
+// searchProductsRanked runs the full-text search (?fuzzy=true) over code, description,
+// manufacturer code and SAP code, with or without the area filter. Results come ordered by
+// relevance, so sort_by and sort_order do not apply.
+func (s *svc) searchProductsRanked(ctx context.Context, input ListProductsInput, offset int) ([]ProductWithSnapshotOutput, int64, *rest.ApiErr) {
+	var products []ProductWithSnapshotOutput
+
+	if input.AreaID.Valid {
+		rows, err := s.repo.SearchUniqueProductsByAreaRankedPaginated(ctx, repo.SearchUniqueProductsByAreaRankedPaginatedParams{
+			Search: input.Search,
+			AreaID: input.AreaID,
+			Limit:  int32(input.PageSize),
+			Offset: int32(offset),
+		})
+		if err != nil {
+			return nil, 0, s.handleDBError(err)
+		}
+
+		total, err := s.repo.CountUniqueProductsByAreaAndFullText(ctx, repo.CountUniqueProductsByAreaAndFullTextParams{
+			AreaID: input.AreaID,
+			Search: input.Search,
+		})
+		if err != nil {
+			return nil, 0, s.handleDBError(err)
+		}
+
+		products = make([]ProductWithSnapshotOutput, 0, len(rows))
+		for _, r := range rows {
+			products = append(products, uniqueProductRowToOutput(repo.SearchUniqueProductsByAreaPaginatedRow(r)))
+		}
+		return products, total, nil
+	}
+
+	rows, err := s.repo.SearchUniqueProductsRankedPaginated(ctx, repo.SearchUniqueProductsRankedPaginatedParams{
+		Search: input.Search,
+		Limit:  int32(input.PageSize),
+		Offset: int32(offset),
+	})
+	if err != nil {
+		return nil, 0, s.handleDBError(err)
+	}
+
+	total, err := s.repo.CountUniqueProductsByFullText(ctx, input.Search)
+	if err != nil {
+		return nil, 0, s.handleDBError(err)
+	}
+
+	products = make([]ProductWithSnapshotOutput, 0, len(rows))
+	for _, r := range rows {
+		products = append(products, searchUniqueProductRowToOutput(repo.SearchUniqueProductsPaginatedRow(r)))
+	}
+	return products, total, nil
+}
@@ -0,0 +1,65 @@
+package products
+
+import (
+	"context"
+	"testing"
+
+	repo "github.com/freitasmatheusrn/lifecycle-monitor/internal/database/postgres/sqlc"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+)
+
+// rankedSearchQuerier answers only the full-text queries, so a fallback to the substring
+// search panics on the nil embedded Querier
+type rankedSearchQuerier struct {
+	repo.Querier
+	search string
+	areaID pgtype.UUID
+}
+
+func (q *rankedSearchQuerier) CountProductsByLifecycleStatus(ctx context.Context) (repo.CountProductsByLifecycleStatusRow, error) {
+	return repo.CountProductsByLifecycleStatusRow{}, nil
+}
+
+func (q *rankedSearchQuerier) SearchUniqueProductsRankedPaginated(ctx context.Context, arg repo.SearchUniqueProductsRankedPaginatedParams) ([]repo.SearchUniqueProductsRankedPaginatedRow, error) {
+	q.search = arg.Search
+	return []repo.SearchUniqueProductsRankedPaginatedRow{{Code: "6ES7214-1AG40-0XB0"}, {Code: "6ES7215-1AG40-0XB0"}}, nil
+}
+
+func (q *rankedSearchQuerier) CountUniqueProductsByFullText(ctx context.Context, search string) (int64, error) {
+	return 2, nil
+}
+
+func (q *rankedSearchQuerier) SearchUniqueProductsByAreaRankedPaginated(ctx context.Context, arg repo.SearchUniqueProductsByAreaRankedPaginatedParams) ([]repo.SearchUniqueProductsByAreaRankedPaginatedRow, error) {
+	q.search, q.areaID = arg.Search, arg.AreaID
+	return []repo.SearchUniqueProductsByAreaRankedPaginatedRow{{Code: "6ES7214-1AG40-0XB0"}}, nil
+}
+
+func (q *rankedSearchQuerier) CountUniqueProductsByAreaAndFullText(ctx context.Context, arg repo.CountUniqueProductsByAreaAndFullTextParams) (int64, error) {
+	return 1, nil
+}
+
+func TestListProducts_Fuzzy(t *testing.T) {
+	querier := &rankedSearchQuerier{}
+	service := &svc{repo: querier, logger: zap.NewNop()}
+
+	result, apiErr := service.ListProducts(t.Context(), ListProductsInput{Search: "cpu 1214c", Fuzzy: true})
+	if apiErr != nil {
+		t.Fatalf("unexpected error: %v", apiErr.Message)
+	}
+	if querier.search != "cpu 1214c" || result.Total != 2 || len(result.Products) != 2 {
+		t.Fatalf("expected the ranked search, got search %q and %+v", querier.search, result)
+	}
+	if result.Products[0].Product.Code != "6ES7214-1AG40-0XB0" {
+		t.Errorf("expected the rank order to be kept, got %s first", result.Products[0].Product.Code)
+	}
+
+	areaID := pgtype.UUID{Bytes: [16]byte{3}, Valid: true}
+	result, apiErr = service.ListProducts(t.Context(), ListProductsInput{Search: "1214", AreaID: areaID, Fuzzy: true})
+	if apiErr != nil {
+		t.Fatalf("unexpected error: %v", apiErr.Message)
+	}
+	if querier.areaID != areaID || result.Total != 1 {
+		t.Errorf("expected the ranked search in the area, got area %v and %+v", querier.areaID, result)
+	}
+}
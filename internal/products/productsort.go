@@ -0,0 +1,41 @@
+package products
+
+import (
+	"fmt"
+
+	"github.com/freitasmatheusrn/lifecycle-monitor/pkg/rest"
+)
+
+// Ordenacao padrao da listagem: produtos mais recentes primeiro
+const (
+	DefaultSortBy    = "created_at"
+	DefaultSortOrder = "desc"
+)
+
+// productSortKeys are the sort_by values of the listing; the queries only order by these columns
+var productSortKeys = map[string]bool{
+	"code":             true,
+	"description":      true,
+	"created_at":       true,
+	"total_quantity":   true,
+	"lifecycle_status": true,
+}
+
+// normalizeProductSort fills in the default sort of the listing and rejects unsupported keys or orders
+func normalizeProductSort(input *ListProductsInput) *rest.ApiErr {
+	if input.SortBy == "" {
+		input.SortBy = DefaultSortBy
+	}
+	if !productSortKeys[input.SortBy] {
+		return rest.NewBadRequestError(fmt.Sprintf("ordenacao nao suportada: %s", input.SortBy))
+	}
+
+	switch input.SortOrder {
+	case "":
+		input.SortOrder = DefaultSortOrder
+	case "asc", "desc":
+	default:
+		return rest.NewBadRequestError(fmt.Sprintf("direcao de ordenacao invalida: %s", input.SortOrder))
+	}
+	return nil
+}
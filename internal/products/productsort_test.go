@@ -0,0 +1,44 @@
+package products
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestNormalizeProductSort(t *testing.T) {
+	tests := []struct {
+		name          string
+		input         ListProductsInput
+		expectedBy    string
+		expectedOrder string
+		message       string
+	}{
+		{name: "defaults", expectedBy: "created_at", expectedOrder: "desc"},
+		{name: "order only", input: ListProductsInput{SortOrder: "asc"}, expectedBy: "created_at", expectedOrder: "asc"},
+		{name: "key only", input: ListProductsInput{SortBy: "total_quantity"}, expectedBy: "total_quantity", expectedOrder: "desc"},
+		{name: "both", input: ListProductsInput{SortBy: "code", SortOrder: "asc"}, expectedBy: "code", expectedOrder: "asc"},
+		{name: "unsupported key", input: ListProductsInput{SortBy: "sap_code"}, message: "ordenacao nao suportada: sap_code"},
+		{name: "column injection", input: ListProductsInput{SortBy: "code; DROP TABLE products"}, message: "ordenacao nao suportada: code; DROP TABLE products"},
+		{name: "invalid order", input: ListProductsInput{SortBy: "code", SortOrder: "up"}, message: "direcao de ordenacao invalida: up"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := tt.input
+			apiErr := normalizeProductSort(&input)
+
+			if tt.message != "" {
+				if apiErr == nil || apiErr.Code != http.StatusBadRequest || apiErr.Message != tt.message {
+					t.Fatalf("expected 400 %q, got %+v", tt.message, apiErr)
+				}
+				return
+			}
+			if apiErr != nil {
+				t.Fatalf("unexpected error: %v", apiErr.Message)
+			}
+			if input.SortBy != tt.expectedBy || input.SortOrder != tt.expectedOrder {
+				t.Errorf("expected %s %s, got %s %s", tt.expectedBy, tt.expectedOrder, input.SortBy, input.SortOrder)
+			}
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package products
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/freitasmatheusrn/lifecycle-monitor/pkg/rest"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// RefreshProduct crawls a product now instead of waiting for the scheduled run and returns the new
+// snapshot with the lifecycle status change it caused (nil when the status is the same). It runs
+// the CollectProduct path without force: within the manual collect cooldown of the latest snapshot
+// the refresh is refused with 429, so a refresh button can't be used to hammer the vendor site.
+func (s *svc) RefreshProduct(ctx context.Context, productID pgtype.UUID) (*SnapshotOutput, *LifecycleStatusChange, *rest.ApiErr) {
+	collected, apiErr := s.CollectProduct(ctx, productID, false)
+	if apiErr != nil {
+		return nil, nil, apiErr
+	}
+
+	if collected.Cached {
+		nextRefreshAt := collected.LatestSnapshot.CollectedAt.Add(s.collectCooldown)
+		return nil, nil, rest.NewTooManyRequestsError(fmt.Sprintf("produto atualizado recentemente, tente novamente apos: %s",
+			nextRefreshAt.Format("02/01/2006 15:04")))
+	}
+
+	var change *LifecycleStatusChange
+	if collected.StatusChange != nil {
+		change = &LifecycleStatusChange{
+			ProductCode: collected.Product.Code,
+			OldStatus:   collected.StatusChange.OldStatus,
+			NewStatus:   collected.StatusChange.NewStatus,
+			Snoozed:     collected.StatusChange.AlertSnoozed,
+		}
+	}
+	return collected.LatestSnapshot, change, nil
+}
@@ -0,0 +1,65 @@
+package products
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	repo "github.com/freitasmatheusrn/lifecycle-monitor/internal/database/postgres/sqlc"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+)
+
+func TestRefreshProduct_Cooldown(t *testing.T) {
+	productID := pgtype.UUID{Bytes: [16]byte{1}, Valid: true}
+	querier := &collectQuerier{
+		product:     repo.FindProductByIDRow{ID: productID, Code: "6ES7214-1AG40-0XB0"},
+		collectedAt: time.Now().Add(-time.Minute),
+	}
+	service := &svc{repo: querier, logger: zap.NewNop(), collectCooldown: 10 * time.Minute}
+
+	_, _, apiErr := service.RefreshProduct(t.Context(), productID)
+	if apiErr == nil || apiErr.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected 429 within the cooldown, got %+v", apiErr)
+	}
+	if !strings.HasPrefix(apiErr.Message, "produto atualizado recentemente, tente novamente apos: ") {
+		t.Errorf("unexpected message %q", apiErr.Message)
+	}
+
+	_, _, apiErr = service.RefreshProduct(t.Context(), pgtype.UUID{Bytes: [16]byte{2}, Valid: true})
+	if apiErr == nil || apiErr.Code != http.StatusNotFound {
+		t.Errorf("expected 404 for an unknown product, got %+v", apiErr)
+	}
+}
+
+func TestRefreshProduct_StatusChange(t *testing.T) {
+	productID := pgtype.UUID{Bytes: [16]byte{1}, Valid: true}
+	querier := &collectQuerier{
+		product: repo.FindProductByIDRow{
+			ID:              productID,
+			Code:            "6ES7214-1AG40-0XB0",
+			LifecycleStatus: pgtype.Text{String: "Active Product", Valid: true},
+		},
+		collectedAt: time.Now().Add(-time.Hour),
+	}
+	collector := &MockPageCollector{data: &CrawledData{Status: "Phase Out Announce"}}
+	service := &svc{
+		repo:            querier,
+		logger:          zap.NewNop(),
+		workerPool:      newTestWorkerPool(t, collector, querier),
+		collectCooldown: 10 * time.Minute,
+	}
+
+	snapshot, change, apiErr := service.RefreshProduct(t.Context(), productID)
+	if apiErr != nil {
+		t.Fatalf("unexpected error: %v", apiErr.Message)
+	}
+	if snapshot == nil || collector.calls.Load() != 1 {
+		t.Fatalf("expected a fresh crawl with its snapshot, got %+v", snapshot)
+	}
+	want := LifecycleStatusChange{ProductCode: "6ES7214-1AG40-0XB0", OldStatus: "Active Product", NewStatus: "Phase Out Announce"}
+	if change == nil || *change != want {
+		t.Errorf("expected status change %+v, got %+v", want, change)
+	}
+}
@@ -0,0 +1,147 @@
+package products
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/freitasmatheusrn/lifecycle-monitor/pkg/rest"
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+// maxReplacementHops bounds how far a chain of successors is followed, so a loop in the
+// stored replacement codes can't keep the resolver going
+const maxReplacementHops = 5
+
+// replacementCrawlTimeout bounds the crawl of a successor that isn't in the catalog
+const replacementCrawlTimeout = 60 * time.Second
+
+// ReplacementDetails describes the successor of a discontinued product
+type ReplacementDetails struct {
+	Code        string
+	Description string
+	Status      string
+	Crawled     bool   // o sucessor nao esta no catalogo e foi coletado na hora
+	FinalCode   string // ultimo sucessor da cadeia quando o sucessor tambem foi substituido
+}
+
+// ResolveReplacement returns the successor of code with its description and lifecycle status.
+// The successor comes from the catalog, or is crawled (without saving) when it isn't there.
+// When the successor is itself discontinued, its own successors are followed in the catalog
+// and the last one is reported in FinalCode. Returns nil when code has no successor.
+func (s *svc) ResolveReplacement(ctx context.Context, code string) (*ReplacementDetails, error) {
+	product, err := s.repo.FindProductByCode(ctx, code)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	successor := strings.TrimSpace(product.ReplacementUrl.String)
+	if successor == "" || successor == code {
+		return nil, nil
+	}
+
+	details := &ReplacementDetails{Code: successor}
+	next, err := s.lookupReplacement(ctx, details)
+	if err != nil {
+		return nil, err
+	}
+	if !IsTerminalStatus(details.Status, false) {
+		next = ""
+	}
+
+	seen := map[string]bool{code: true, successor: true}
+	for range maxReplacementHops {
+		if next == "" || seen[next] {
+			break
+		}
+		seen[next] = true
+		details.FinalCode = next
+
+		row, err := s.repo.FindProductByCode(ctx, next)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				break
+			}
+			return nil, err
+		}
+		if !IsTerminalStatus(row.LifecycleStatus.String, false) {
+			break
+		}
+		next = strings.TrimSpace(row.ReplacementUrl.String)
+	}
+
+	return details, nil
+}
+
+// lookupReplacement fills the description and status of the successor from the catalog, or crawls
+// it when it isn't there, and returns its own successor (empty when it has none or wasn't found)
+func (s *svc) lookupReplacement(ctx context.Context, details *ReplacementDetails) (string, error) {
+	row, err := s.repo.FindProductByCode(ctx, details.Code)
+	if err == nil {
+		details.Description = row.Description.String
+		details.Status = row.LifecycleStatus.String
+		return strings.TrimSpace(row.ReplacementUrl.String), nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return "", err
+	}
+
+	if s.workerPool == nil {
+		return "", nil
+	}
+
+	// Fora do catalogo: coleta a pagina do sucessor so para a notificacao
+	crawlCtx, cancel := context.WithTimeout(ctx, replacementCrawlTimeout)
+	defer cancel()
+
+	data, err := s.workerPool.CollectNow(crawlCtx, details.Code)
+	if err != nil {
+		// Sem os detalhes o alerta ainda mostra o codigo do sucessor
+		s.logger.Warn("failed to crawl replacement product",
+			zap.String("code", details.Code),
+			zap.Error(err),
+		)
+		return "", nil
+	}
+	details.Description = data.Description
+	details.Status = data.Status
+	details.Crawled = true
+	return strings.TrimSpace(data.ReplacementCode), nil
+}
+
+// ResolveReplacementChain follows the successors of code, from the catalog or crawling the ones
+// outside it, until one is Active. chain lists the successors in order; finalActive is the Active
+// one, empty when the chain ends first (no successor, unknown status, a loop or maxReplacementHops).
+func (s *svc) ResolveReplacementChain(ctx context.Context, code string) (chain []string, finalActive string, apiErr *rest.ApiErr) {
+	product, err := s.repo.FindProductByCode(ctx, code)
+	if err != nil {
+		return nil, "", s.handleDBError(err)
+	}
+
+	chain = make([]string, 0)
+	next := strings.TrimSpace(product.ReplacementUrl.String)
+	seen := map[string]bool{code: true}
+	for range maxReplacementHops {
+		if next == "" || seen[next] {
+			break
+		}
+		seen[next] = true
+		chain = append(chain, next)
+
+		details := &ReplacementDetails{Code: next}
+		following, err := s.lookupReplacement(ctx, details)
+		if err != nil {
+			return nil, "", s.handleDBError(err)
+		}
+		if details.Status == StatusActive {
+			return chain, next, nil
+		}
+		next = following
+	}
+
+	return chain, "", nil
+}
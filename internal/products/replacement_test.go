@@ -0,0 +1,126 @@
+package products
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"testing"
+
+	repo "github.com/freitasmatheusrn/lifecycle-monitor/internal/database/postgres/sqlc"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+)
+
+// catalogQuerier serves products by code from a fixed catalog
+type catalogQuerier struct {
+	repo.Querier
+	products map[string]repo.FindProductByCodeRow
+}
+
+func (q *catalogQuerier) FindProductByCode(ctx context.Context, code string) (repo.FindProductByCodeRow, error) {
+	product, ok := q.products[code]
+	if !ok {
+		return repo.FindProductByCodeRow{}, pgx.ErrNoRows
+	}
+	return product, nil
+}
+
+func catalogProduct(code, description, status, replacement string) repo.FindProductByCodeRow {
+	return repo.FindProductByCodeRow{
+		Code:            code,
+		Description:     pgtype.Text{String: description, Valid: description != ""},
+		LifecycleStatus: pgtype.Text{String: status, Valid: status != ""},
+		ReplacementUrl:  pgtype.Text{String: replacement, Valid: replacement != ""},
+	}
+}
+
+func TestResolveReplacement(t *testing.T) {
+	querier := &catalogQuerier{products: map[string]repo.FindProductByCodeRow{
+		"OLD":    catalogProduct("OLD", "CPU antiga", StatusDiscontinued, "NEW"),
+		"NEW":    catalogProduct("NEW", "CPU nova", StatusActive, ""),
+		"CHAIN":  catalogProduct("CHAIN", "CPU 1", StatusDiscontinued, "MID"),
+		"MID":    catalogProduct("MID", "CPU 2", StatusCancellation, "LAST"),
+		"LAST":   catalogProduct("LAST", "CPU 3", StatusActive, ""),
+		"LOOP":   catalogProduct("LOOP", "A", StatusDiscontinued, "LOOP2"),
+		"LOOP2":  catalogProduct("LOOP2", "B", StatusDiscontinued, "LOOP"),
+		"ALONE":  catalogProduct("ALONE", "Sem sucessor", StatusDiscontinued, ""),
+		"ABSENT": catalogProduct("ABSENT", "Sucessor fora", StatusDiscontinued, "UNKNOWN"),
+	}}
+	service := &svc{repo: querier, logger: zap.NewNop()}
+
+	tests := []struct {
+		code     string
+		expected *ReplacementDetails
+	}{
+		{code: "OLD", expected: &ReplacementDetails{Code: "NEW", Description: "CPU nova", Status: StatusActive}},
+		{code: "CHAIN", expected: &ReplacementDetails{Code: "MID", Description: "CPU 2", Status: StatusCancellation, FinalCode: "LAST"}},
+		{code: "LOOP", expected: &ReplacementDetails{Code: "LOOP2", Description: "B", Status: StatusDiscontinued}},
+		{code: "ALONE", expected: nil},
+		{code: "MISSING", expected: nil},
+		// Sem worker pool o sucessor fora do catalogo vai so com o codigo
+		{code: "ABSENT", expected: &ReplacementDetails{Code: "UNKNOWN"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			details, err := service.ResolveReplacement(context.Background(), tt.code)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if (details == nil) != (tt.expected == nil) || (details != nil && *details != *tt.expected) {
+				t.Errorf("expected %+v, got %+v", tt.expected, details)
+			}
+		})
+	}
+}
+
+func TestResolveReplacementChain(t *testing.T) {
+	querier := &catalogQuerier{products: map[string]repo.FindProductByCodeRow{
+		"OLD":    catalogProduct("OLD", "CPU antiga", StatusDiscontinued, "MID"),
+		"MID":    catalogProduct("MID", "CPU 2", StatusPhaseOut, "LAST"),
+		"LAST":   catalogProduct("LAST", "CPU 3", StatusActive, "NEXT"),
+		"LOOP":   catalogProduct("LOOP", "A", StatusDiscontinued, "LOOP2"),
+		"LOOP2":  catalogProduct("LOOP2", "B", StatusDiscontinued, "LOOP"),
+		"ALONE":  catalogProduct("ALONE", "Sem sucessor", StatusDiscontinued, ""),
+		"ABSENT": catalogProduct("ABSENT", "Sucessor fora", StatusDiscontinued, "UNKNOWN"),
+		"LONG":   catalogProduct("LONG", "", StatusDiscontinued, "L1"),
+		"L1":     catalogProduct("L1", "", StatusDiscontinued, "L2"),
+		"L2":     catalogProduct("L2", "", StatusDiscontinued, "L3"),
+		"L3":     catalogProduct("L3", "", StatusDiscontinued, "L4"),
+		"L4":     catalogProduct("L4", "", StatusDiscontinued, "L5"),
+		"L5":     catalogProduct("L5", "", StatusDiscontinued, "L6"),
+		"L6":     catalogProduct("L6", "", StatusActive, ""),
+	}}
+	service := &svc{repo: querier, logger: zap.NewNop()}
+
+	tests := []struct {
+		code          string
+		expectedChain []string
+		expectedFinal string
+	}{
+		// Phase out nao e o fim da cadeia: segue ate o ativo
+		{code: "OLD", expectedChain: []string{"MID", "LAST"}, expectedFinal: "LAST"},
+		{code: "LOOP", expectedChain: []string{"LOOP2"}},
+		{code: "ALONE", expectedChain: []string{}},
+		// Sem worker pool o sucessor fora do catalogo fica sem status
+		{code: "ABSENT", expectedChain: []string{"UNKNOWN"}},
+		{code: "LONG", expectedChain: []string{"L1", "L2", "L3", "L4", "L5"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			chain, final, apiErr := service.ResolveReplacementChain(context.Background(), tt.code)
+			if apiErr != nil {
+				t.Fatalf("unexpected error: %v", apiErr.Message)
+			}
+			if !slices.Equal(chain, tt.expectedChain) || final != tt.expectedFinal {
+				t.Errorf("expected %v -> %q, got %v -> %q", tt.expectedChain, tt.expectedFinal, chain, final)
+			}
+		})
+	}
+
+	if _, _, apiErr := service.ResolveReplacementChain(context.Background(), "MISSING"); apiErr == nil || apiErr.Code != http.StatusNotFound {
+		t.Errorf("expected 404 for an unknown code, got %+v", apiErr)
+	}
+}
@@ -0,0 +1,63 @@
+package products
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// transientError marks a crawl failure worth retrying (navigation errors, page load timeouts)
+type transientError struct {
+	err error
+}
+
+func (e transientError) Error() string { return e.err.Error() }
+
+func (e transientError) Unwrap() error { return e.err }
+
+// isTransientCrawlError reports whether a failed attempt may succeed if tried again.
+// A missing product never is; a timeout only counts while the caller's ctx is alive,
+// i.e. when it was the crawl timeout of the attempt that expired.
+func isTransientCrawlError(ctx context.Context, err error) bool {
+	if errors.Is(err, ErrProductNotFound) || ctx.Err() != nil {
+		return false
+	}
+	var transient transientError
+	return errors.As(err, &transient) || errors.Is(err, context.DeadlineExceeded)
+}
+
+// Collect crawls the product page, retrying up to MaxRetries times with exponential backoff
+// (BaseBackoff, doubled per attempt) when the failure is transient. Each attempt waits for its own
+// page slot and browser context and gets its own crawl timeout.
+func (c *Crawler) Collect(ctx context.Context, productCode string) (*CrawledData, error) {
+	data, err := c.collectWithRetry(ctx, productCode, c.collectOnce)
+	c.counters.record(err, time.Now())
+	return data, err
+}
+
+func (c *Crawler) collectWithRetry(ctx context.Context, productCode string, collect func(context.Context, string) (*CrawledData, error)) (*CrawledData, error) {
+	for attempt := 0; ; attempt++ {
+		data, err := collect(ctx, productCode)
+		if err == nil || attempt >= c.MaxRetries || !isTransientCrawlError(ctx, err) {
+			return data, err
+		}
+
+		wait := c.BaseBackoff << attempt
+		c.logger.Warn("crawl attempt failed, retrying",
+			zap.String("code", productCode),
+			zap.Int("attempt", attempt+1),
+			zap.Duration("backoff", wait),
+			zap.Error(err),
+		)
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, err
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package products
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// flakyCollect fails with each of errs in turn, then succeeds
+type flakyCollect struct {
+	errs  []error
+	calls int
+}
+
+func (f *flakyCollect) collect(ctx context.Context, code string) (*CrawledData, error) {
+	f.calls++
+	if f.calls <= len(f.errs) {
+		return nil, f.errs[f.calls-1]
+	}
+	return &CrawledData{Description: code}, nil
+}
+
+func TestCollectWithRetry(t *testing.T) {
+	navigation := transientError{errors.New("could not navigate to x: net::ERR_CONNECTION_RESET")}
+	notFound := fmt.Errorf("%w: X (status 404)", ErrProductNotFound)
+
+	tests := []struct {
+		name       string
+		maxRetries int
+		errs       []error
+		calls      int
+		succeeds   bool
+	}{
+		{name: "fails twice then succeeds", maxRetries: 2, errs: []error{navigation, fmt.Errorf("crawl canceled: %w", context.DeadlineExceeded)}, calls: 3, succeeds: true},
+		{name: "gives up after max retries", maxRetries: 1, errs: []error{navigation, navigation}, calls: 2},
+		{name: "product not found is not retried", maxRetries: 2, errs: []error{notFound}, calls: 1},
+		{name: "extraction error is not retried", maxRetries: 2, errs: []error{errors.New("could not extract product description")}, calls: 1},
+		{name: "retries disabled", maxRetries: 0, errs: []error{navigation}, calls: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Crawler{MaxRetries: tt.maxRetries, BaseBackoff: time.Millisecond, logger: zap.NewNop()}
+			flaky := &flakyCollect{errs: tt.errs}
+
+			data, err := c.collectWithRetry(context.Background(), "6ES7", flaky.collect)
+
+			if flaky.calls != tt.calls {
+				t.Errorf("expected %d attempts, got %d", tt.calls, flaky.calls)
+			}
+			if tt.succeeds && (err != nil || data == nil) {
+				t.Errorf("expected success, got %v", err)
+			}
+			if !tt.succeeds && err != tt.errs[len(tt.errs)-1] {
+				t.Errorf("expected the last attempt's error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestCollectWithRetry_StopsWhenContextDone(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	c := &Crawler{MaxRetries: 3, BaseBackoff: time.Hour, logger: zap.NewNop()}
+	flaky := &flakyCollect{errs: []error{transientError{errors.New("timeout waiting for page load")}}}
+
+	time.AfterFunc(10*time.Millisecond, cancel)
+	if _, err := c.collectWithRetry(ctx, "6ES7", flaky.collect); err == nil || flaky.calls != 1 {
+		t.Errorf("expected the backoff to end with ctx after 1 attempt, got %v after %d", err, flaky.calls)
+	}
+}
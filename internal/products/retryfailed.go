@@ -0,0 +1,101 @@
+package products
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	repo "github.com/freitasmatheusrn/lifecycle-monitor/internal/database/postgres/sqlc"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+)
+
+// RetryFailedResult summarizes a retry of the codes in the crawl_failures queue
+type RetryFailedResult struct {
+	Total        int             `json:"total"`
+	Succeeded    int             `json:"succeeded"`     // coletados e removidos da fila
+	Removed      int             `json:"removed"`       // codigos que nao estao mais no catalogo
+	StillFailing []FailedProduct `json:"still_failing"` // continuam na fila com o novo motivo
+}
+
+// RetryFailed crawls again every code in the crawl_failures queue (filled by the scheduler when a
+// crawl fails), saves the successful ones with save and removes them from the queue. Codes that
+// fail again stay queued with the new reason; codes no longer in the catalog are dropped.
+// If ctx ends first, the remaining results are saved by the async path and stay queued.
+func (wp *WorkerPool) RetryFailed(ctx context.Context, save func(context.Context, CrawlerJob, *CrawledData) error) (*RetryFailedResult, error) {
+	failures, err := wp.repo.ListCrawlFailures(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list crawl failures: %w", err)
+	}
+
+	result := &RetryFailedResult{Total: len(failures), StillFailing: make([]FailedProduct, 0)}
+	jobs := make([]CrawlerJob, 0, len(failures))
+	for _, failure := range failures {
+		product, err := wp.repo.FindProductByCode(ctx, failure.Code)
+		if errors.Is(err, pgx.ErrNoRows) {
+			wp.forgetCrawlFailure(ctx, failure.Code)
+			result.Removed++
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to find product %s: %w", failure.Code, err)
+		}
+		jobs = append(jobs, CrawlerJob{
+			ProductID:   product.ID,
+			ProductCode: product.Code,
+			ProductURL:  product.Url,
+			Source:      SnapshotSourceRetry,
+		})
+	}
+	if len(jobs) == 0 {
+		return result, nil
+	}
+
+	results, err := wp.SubmitBatch(ctx, jobs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to submit retry batch: %w", err)
+	}
+
+	for crawlResult := range results {
+		code := crawlResult.Job.ProductCode
+
+		err := crawlResult.Error
+		if err == nil {
+			err = save(ctx, crawlResult.Job, crawlResult.Data)
+		}
+		if err != nil {
+			wp.logger.Warn("retry of failed crawl failed again", zap.String("code", code), zap.Error(err))
+			_, recordErr := wp.repo.RecordCrawlFailure(ctx, repo.RecordCrawlFailureParams{
+				Code:      code,
+				LastError: pgtype.Text{String: err.Error(), Valid: true},
+			})
+			if recordErr != nil {
+				wp.logger.Warn("failed to record crawl failure", zap.String("code", code), zap.Error(recordErr))
+			}
+			result.StillFailing = append(result.StillFailing, FailedProduct{
+				Code:   code,
+				Reason: crawlFailureReason(err, "falha ao coletar dados"),
+			})
+			continue
+		}
+
+		wp.forgetCrawlFailure(ctx, code)
+		result.Succeeded++
+	}
+
+	wp.logger.Info("retry of failed crawls finished",
+		zap.Int("total", result.Total),
+		zap.Int("succeeded", result.Succeeded),
+		zap.Int("removed", result.Removed),
+		zap.Int("still_failing", len(result.StillFailing)),
+	)
+	return result, nil
+}
+
+// forgetCrawlFailure removes code from the crawl_failures queue, logging failures
+func (wp *WorkerPool) forgetCrawlFailure(ctx context.Context, code string) {
+	if err := wp.repo.DeleteCrawlFailure(ctx, code); err != nil {
+		wp.logger.Warn("failed to remove crawl failure", zap.String("code", code), zap.Error(err))
+	}
+}
@@ -0,0 +1,117 @@
+package products
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	repo "github.com/freitasmatheusrn/lifecycle-monitor/internal/database/postgres/sqlc"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+)
+
+// failureQueueQuerier serves the crawl_failures queue and the products of its codes
+type failureQueueQuerier struct {
+	MockQuerier
+	failures []repo.CrawlFailure
+	products map[string]repo.FindProductByCodeRow
+	deleted  []string
+	recorded []string
+}
+
+func (m *failureQueueQuerier) ListCrawlFailures(ctx context.Context) ([]repo.CrawlFailure, error) {
+	return m.failures, nil
+}
+
+func (m *failureQueueQuerier) FindProductByCode(ctx context.Context, code string) (repo.FindProductByCodeRow, error) {
+	product, ok := m.products[code]
+	if !ok {
+		return repo.FindProductByCodeRow{}, pgx.ErrNoRows
+	}
+	return product, nil
+}
+
+func (m *failureQueueQuerier) DeleteCrawlFailure(ctx context.Context, code string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.deleted = append(m.deleted, code)
+	return nil
+}
+
+func (m *failureQueueQuerier) RecordCrawlFailure(ctx context.Context, arg repo.RecordCrawlFailureParams) (repo.CrawlFailure, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.recorded = append(m.recorded, arg.Code)
+	return repo.CrawlFailure{Code: arg.Code}, nil
+}
+
+// codeCollector fails the codes in failing and crawls the others
+type codeCollector struct {
+	MockPageCollector
+	failing map[string]bool
+}
+
+func (c *codeCollector) Collect(ctx context.Context, productCode string) (*CrawledData, error) {
+	if c.failing[productCode] {
+		return nil, transientError{errors.New("could not navigate")}
+	}
+	return &CrawledData{Status: StatusActive}, nil
+}
+
+func TestWorkerPool_RetryFailed(t *testing.T) {
+	querier := &failureQueueQuerier{
+		failures: []repo.CrawlFailure{{Code: "PROD-OK"}, {Code: "PROD-DOWN"}, {Code: "PROD-GONE"}},
+		products: map[string]repo.FindProductByCodeRow{
+			"PROD-OK":   {ID: pgtype.UUID{Bytes: [16]byte{1}, Valid: true}, Code: "PROD-OK"},
+			"PROD-DOWN": {ID: pgtype.UUID{Bytes: [16]byte{2}, Valid: true}, Code: "PROD-DOWN"},
+		},
+	}
+	collector := &codeCollector{failing: map[string]bool{"PROD-DOWN": true}}
+	wp := NewWorkerPool(collector, querier, zap.NewNop(), WorkerPoolConfig{
+		NumWorkers: 2,
+		QueueSize:  10,
+		MinDelay:   time.Millisecond,
+		MaxDelay:   time.Millisecond,
+	})
+	if err := wp.Start(); err != nil {
+		t.Fatalf("failed to start worker pool: %v", err)
+	}
+	defer wp.Stop()
+
+	var saved []string
+	result, err := wp.RetryFailed(context.Background(), func(ctx context.Context, job CrawlerJob, data *CrawledData) error {
+		if job.Source != SnapshotSourceRetry {
+			t.Errorf("expected source %q, got %q", SnapshotSourceRetry, job.Source)
+		}
+		saved = append(saved, job.ProductCode)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("retry failed: %v", err)
+	}
+
+	if result.Total != 3 || result.Succeeded != 1 || result.Removed != 1 {
+		t.Errorf("unexpected summary: %+v", result)
+	}
+	if len(result.StillFailing) != 1 || result.StillFailing[0].Code != "PROD-DOWN" ||
+		result.StillFailing[0].Reason != reasonNavigation {
+		t.Errorf("expected PROD-DOWN to still fail with the navigation reason, got %+v", result.StillFailing)
+	}
+	if len(saved) != 1 || saved[0] != "PROD-OK" {
+		t.Errorf("expected only PROD-OK to be saved, got %v", saved)
+	}
+
+	// Sai da fila quem foi coletado ou saiu do catalogo; a falha nova atualiza o motivo
+	deleted := map[string]bool{}
+	for _, code := range querier.deleted {
+		deleted[code] = true
+	}
+	if len(deleted) != 2 || !deleted["PROD-OK"] || !deleted["PROD-GONE"] {
+		t.Errorf("expected PROD-OK and PROD-GONE to leave the queue, got %v", querier.deleted)
+	}
+	if len(querier.recorded) != 1 || querier.recorded[0] != "PROD-DOWN" {
+		t.Errorf("expected the PROD-DOWN failure to be recorded again, got %v", querier.recorded)
+	}
+}
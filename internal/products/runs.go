@@ -0,0 +1,113 @@
+package products
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	repo "github.com/freitasmatheusrn/lifecycle-monitor/internal/database/postgres/sqlc"
+	"github.com/freitasmatheusrn/lifecycle-monitor/pkg/rest"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+)
+
+// Limites de GET /admin/lifecycle/runs (e /admin/collection-runs)
+const (
+	defaultCollectionRunsLimit = 20
+	maxCollectionRunsLimit     = 100
+)
+
+// CollectionRun is the summary of one scheduled lifecycle update run
+type CollectionRun struct {
+	RunID            pgtype.UUID
+	StartedAt        time.Time
+	Total            int
+	Succeeded        int
+	Failed           int
+	UncollectedCodes []string // produtos que ficaram sem resultado quando a execucao estourou o tempo
+	TimedOut         bool
+	StatusChanges    int // mudancas de status de lifecycle encontradas
+}
+
+// CollectionRunOutput is one entry of GET /admin/collection-runs
+type CollectionRunOutput struct {
+	RunID            pgtype.UUID `json:"run_id"`
+	StartedAt        time.Time   `json:"started_at"`
+	FinishedAt       time.Time   `json:"finished_at"`
+	DurationSeconds  float64     `json:"duration_seconds"`
+	Total            int         `json:"total"`
+	Succeeded        int         `json:"succeeded"`
+	Failed           int         `json:"failed"`
+	Uncollected      int         `json:"uncollected"`
+	UncollectedCodes []string    `json:"uncollected_codes"`
+	TimedOut         bool        `json:"timed_out"`
+	StatusChanges    int         `json:"status_changes"`
+}
+
+// RecordCollectionRun stores the summary of a scheduled run in the run history
+func (s *svc) RecordCollectionRun(ctx context.Context, run CollectionRun) error {
+	codes := run.UncollectedCodes
+	if codes == nil {
+		codes = []string{}
+	}
+
+	return s.repo.CreateCollectionRun(ctx, repo.CreateCollectionRunParams{
+		ID:               run.RunID,
+		StartedAt:        pgtype.Timestamp{Time: run.StartedAt, Valid: true},
+		Total:            int32(run.Total),
+		Succeeded:        int32(run.Succeeded),
+		Failed:           int32(run.Failed),
+		UncollectedCodes: codes,
+		TimedOut:         run.TimedOut,
+		StatusChanges:    int32(run.StatusChanges),
+	})
+}
+
+// LatestCollectionRun returns the summary of the latest scheduled run, or nil before the first one
+func (s *svc) LatestCollectionRun(ctx context.Context) (*CollectionRun, error) {
+	run, err := s.repo.GetLatestCollectionRun(ctx)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &CollectionRun{
+		RunID:            run.ID,
+		StartedAt:        run.StartedAt.Time,
+		Total:            int(run.Total),
+		Succeeded:        int(run.Succeeded),
+		Failed:           int(run.Failed),
+		UncollectedCodes: run.UncollectedCodes,
+		TimedOut:         run.TimedOut,
+		StatusChanges:    int(run.StatusChanges),
+	}, nil
+}
+
+// ListCollectionRuns returns the latest scheduled runs, newest first
+func (s *svc) ListCollectionRuns(ctx context.Context, limit int) ([]CollectionRunOutput, *rest.ApiErr) {
+	runs, err := s.repo.ListCollectionRuns(ctx, int32(limit))
+	if err != nil {
+		s.logger.Error("failed to list collection runs", zap.Error(err))
+		return nil, rest.NewInternalServerError("erro ao listar execucoes de coleta")
+	}
+
+	output := make([]CollectionRunOutput, 0, len(runs))
+	for _, run := range runs {
+		output = append(output, CollectionRunOutput{
+			RunID:            run.ID,
+			StartedAt:        run.StartedAt.Time,
+			FinishedAt:       run.FinishedAt.Time,
+			DurationSeconds:  run.FinishedAt.Time.Sub(run.StartedAt.Time).Seconds(),
+			Total:            int(run.Total),
+			Succeeded:        int(run.Succeeded),
+			Failed:           int(run.Failed),
+			Uncollected:      len(run.UncollectedCodes),
+			UncollectedCodes: run.UncollectedCodes,
+			TimedOut:         run.TimedOut,
+			StatusChanges:    int(run.StatusChanges),
+		})
+	}
+	return output, nil
+}
@@ -0,0 +1,128 @@
+package products
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+)
+
+// defaultSelectorTimeout is used by configured candidates without timeout_ms
+const defaultSelectorTimeout = 5000
+
+// SelectorConfig holds the candidates tried, in order, for each field of the product page.
+// Fields left empty use the built-in candidates, so a file may override only what changed.
+type SelectorConfig struct {
+	Description []SelectorCandidate `json:"description"`
+	Status      []StatusCandidate   `json:"status"`
+	Replacement []SelectorCandidate `json:"replacement"`
+	Family      []SelectorCandidate `json:"family"`
+	Datasheet   []SelectorCandidate `json:"datasheet"`
+	NotFound    []string            `json:"not_found"` // CSS selectors present only on the "product not found" page
+}
+
+// DefaultSelectorConfig returns the built-in candidates for the current Siemens markup
+func DefaultSelectorConfig() SelectorConfig {
+	return SelectorConfig{
+		Description: slices.Clone(descriptionCandidates),
+		Status:      slices.Clone(statusCandidates),
+		Replacement: slices.Clone(replacementCandidates),
+		Family:      slices.Clone(familyCandidates),
+		Datasheet:   slices.Clone(datasheetCandidates),
+		NotFound:    slices.Clone(notFoundMarkers),
+	}
+}
+
+// LoadSelectorConfig reads the selectors from a JSON file shaped like SelectorConfig.
+// An empty path returns the defaults.
+func LoadSelectorConfig(path string) (SelectorConfig, error) {
+	if path == "" {
+		return DefaultSelectorConfig(), nil
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return SelectorConfig{}, fmt.Errorf("could not read selectors file: %w", err)
+	}
+
+	var config SelectorConfig
+	if err := json.Unmarshal(content, &config); err != nil {
+		return SelectorConfig{}, fmt.Errorf("invalid selectors file %s: %w", path, err)
+	}
+	if err := config.validate(); err != nil {
+		return SelectorConfig{}, fmt.Errorf("invalid selectors file %s: %w", path, err)
+	}
+
+	return config.withDefaults(), nil
+}
+
+// validate rejects candidates that can't match anything
+func (c SelectorConfig) validate() error {
+	for field, candidates := range map[string][]SelectorCandidate{
+		FieldDescription:     c.Description,
+		FieldReplacementCode: c.Replacement,
+		FieldFamily:          c.Family,
+		FieldDatasheetURL:    c.Datasheet,
+	} {
+		for i, candidate := range candidates {
+			if candidate.Selector == "" {
+				return fmt.Errorf("%s candidate %d has no selector", field, i+1)
+			}
+		}
+	}
+	for i, candidate := range c.Status {
+		if candidate.Item == "" || candidate.Label == "" || candidate.LabelText == "" || candidate.Value == "" {
+			return fmt.Errorf("status candidate %d needs item, label, label_text and value", i+1)
+		}
+	}
+	return nil
+}
+
+// withDefaults fills empty fields with the built-in candidates, and missing names
+// and timeouts of configured candidates
+func (c SelectorConfig) withDefaults() SelectorConfig {
+	defaults := DefaultSelectorConfig()
+	if len(c.Description) == 0 {
+		c.Description = defaults.Description
+	}
+	if len(c.Status) == 0 {
+		c.Status = defaults.Status
+	}
+	if len(c.Replacement) == 0 {
+		c.Replacement = defaults.Replacement
+	}
+	if len(c.Family) == 0 {
+		c.Family = defaults.Family
+	}
+	if len(c.Datasheet) == 0 {
+		c.Datasheet = defaults.Datasheet
+	}
+	if len(c.NotFound) == 0 {
+		c.NotFound = defaults.NotFound
+	}
+
+	c.Description = fillCandidates(FieldDescription, c.Description)
+	c.Replacement = fillCandidates(FieldReplacementCode, c.Replacement)
+	c.Family = fillCandidates(FieldFamily, c.Family)
+	c.Datasheet = fillCandidates(FieldDatasheetURL, c.Datasheet)
+	c.Status = slices.Clone(c.Status)
+	for i := range c.Status {
+		if c.Status[i].Name == "" {
+			c.Status[i].Name = fmt.Sprintf("%s.%d", FieldStatus, i+1)
+		}
+	}
+	return c
+}
+
+func fillCandidates(field string, candidates []SelectorCandidate) []SelectorCandidate {
+	candidates = slices.Clone(candidates)
+	for i := range candidates {
+		if candidates[i].Name == "" {
+			candidates[i].Name = fmt.Sprintf("%s.%d", field, i+1)
+		}
+		if candidates[i].Timeout <= 0 {
+			candidates[i].Timeout = defaultSelectorTimeout
+		}
+	}
+	return candidates
+}
@@ -3,6 +3,8 @@ package products
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -13,7 +15,9 @@ import (
 	"time"
 
 	"github.com/freitasmatheusrn/lifecycle-monitor/internal/database"
+	"github.com/freitasmatheusrn/lifecycle-monitor/internal/database/postgres"
 	repo "github.com/freitasmatheusrn/lifecycle-monitor/internal/database/postgres/sqlc"
+	"github.com/freitasmatheusrn/lifecycle-monitor/pkg/parser"
 	"github.com/freitasmatheusrn/lifecycle-monitor/pkg/rest"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
@@ -26,28 +30,78 @@ type Service interface {
 	CreateProduct(ctx context.Context, input CreateProductInput) (*ProductOutput, *rest.ApiErr)
 	UpdateProduct(ctx context.Context, productID pgtype.UUID, input UpdateProductInput) (*ProductOutput, *rest.ApiErr)
 	DeleteProduct(ctx context.Context, productID pgtype.UUID) *rest.ApiErr
+	DeleteProducts(ctx context.Context, ids []pgtype.UUID) (int, []FailedProduct, *rest.ApiErr)
+	RestoreProduct(ctx context.Context, productID pgtype.UUID) (*ProductOutput, *rest.ApiErr)
+	ListArchivedProducts(ctx context.Context) ([]ArchivedProductOutput, *rest.ApiErr)
+	ListBelowMinimum(ctx context.Context) ([]ProductOutput, *rest.ApiErr)
 	ListProducts(ctx context.Context, input ListProductsInput) (*PaginatedProductsOutput, *rest.ApiErr)
+	GetInventorySummary(ctx context.Context, areaID pgtype.UUID) (*InventorySummary, *rest.ApiErr)
 	GetProduct(ctx context.Context, productID pgtype.UUID) (*ProductWithSnapshotOutput, *rest.ApiErr)
+	CompareProducts(ctx context.Context, idA, idB pgtype.UUID) (*ProductComparison, *rest.ApiErr)
 	GetProductSnapshots(ctx context.Context, productID pgtype.UUID) ([]SnapshotOutput, *rest.ApiErr)
+	GetSnapshotDiffs(ctx context.Context, productID pgtype.UUID) ([]SnapshotDiff, *rest.ApiErr)
+	ResolveReplacementChain(ctx context.Context, code string) (chain []string, finalActive string, apiErr *rest.ApiErr)
+	BatchGetProducts(ctx context.Context, input BatchGetProductsInput) (*BatchGetProductsOutput, *rest.ApiErr)
+	CollectProduct(ctx context.Context, productID pgtype.UUID, force bool) (*CollectProductOutput, *rest.ApiErr)
+	RefreshProduct(ctx context.Context, productID pgtype.UUID) (*SnapshotOutput, *LifecycleStatusChange, *rest.ApiErr)
+	BulkOverrideStatus(ctx context.Context, input BulkStatusInput) (*BulkStatusResult, *rest.ApiErr)
+	SnoozeAlerts(ctx context.Context, productID pgtype.UUID, input SnoozeAlertsInput) (*SnoozeOutput, *rest.ApiErr)
+	UnsnoozeAlerts(ctx context.Context, productID pgtype.UUID) *rest.ApiErr
+	SetLifecycleExpectation(ctx context.Context, productID pgtype.UUID, input LifecycleExpectationInput) (*LifecycleExpectationOutput, *rest.ApiErr)
+	DeleteLifecycleExpectation(ctx context.Context, productID pgtype.UUID) *rest.ApiErr
+	RebuildProductURLs(ctx context.Context) (*RebuildURLsResult, *rest.ApiErr)
+	RecrawlUnknownStatus(ctx context.Context, onProgress func(RecrawlProgressEvent)) (*RecrawlResult, *rest.ApiErr)
+	CrawlerStats(ctx context.Context) (*WorkerPoolStats, *rest.ApiErr)
+	RetryFailedCrawls(ctx context.Context) (*RetryFailedResult, *rest.ApiErr)
+	PruneSnapshots(ctx context.Context, keepPerProduct int, olderThan time.Duration) (*PruneSnapshotsResult, *rest.ApiErr)
+	WorkerCount(ctx context.Context) *WorkerCountOutput
+	ResizeWorkers(ctx context.Context, input WorkerCountInput) (*WorkerCountOutput, *rest.ApiErr)
+	IngestionStats(ctx context.Context) *IngestionStats
+	ListCollectionRuns(ctx context.Context, limit int) ([]CollectionRunOutput, *rest.ApiErr)
+	CrawlerSelfTest(ctx context.Context, code string) *CrawlerSelfTestResult
+	CodeFilterRules(ctx context.Context) *CodeFilterRules
+	UpdateCodeFilter(ctx context.Context, rules CodeFilterRules) (*CodeFilterRules, *rest.ApiErr)
 	AddProductsWithProgress(ctx context.Context, input AddProductsInput, onProgress ProgressCallback) (*AddProductsResult, *rest.ApiErr)
-	ImportFromSpreadsheet(ctx context.Context, file io.Reader, areaID pgtype.UUID) (*ImportResult, *rest.ApiErr)
-	ImportFromSpreadsheetWithProgress(ctx context.Context, file io.Reader, areaID pgtype.UUID, onProgress ImportProgressCallback) (*ImportResult, *rest.ApiErr)
+	ImportFromSpreadsheet(ctx context.Context, input ImportInput) (*ImportResult, *rest.ApiErr)
+	ImportFromSpreadsheetWithProgress(ctx context.Context, input ImportInput, onProgress ImportProgressCallback) (*ImportResult, *rest.ApiErr)
+	PreviewImport(ctx context.Context, input ImportPreviewInput) (*ImportPreviewResult, *rest.ApiErr)
 	ExportToSpreadsheet(ctx context.Context) (*bytes.Buffer, *rest.ApiErr)
+	ExportToCSV(ctx context.Context) (*bytes.Buffer, *rest.ApiErr)
+	ImportTemplate() (*bytes.Buffer, *rest.ApiErr)
+	ProductsExportVersion(ctx context.Context) (*ExportVersion, *rest.ApiErr)
+	ExportLifecycleChanges(ctx context.Context, from, to time.Time) (*bytes.Buffer, *rest.ApiErr)
+	StreamProducts(ctx context.Context, input ExportProductsInput, onProduct func(ProductWithSnapshotOutput) error) *rest.ApiErr
+}
+
+// TxBeginner starts the transactions used by operations that must be atomic (e.g. *pgxpool.Pool)
+type TxBeginner interface {
+	Begin(ctx context.Context) (pgx.Tx, error)
 }
 
 type svc struct {
-	repo       repo.Querier
-	workerPool *WorkerPool
-	baseURL    string
-	logger     *zap.Logger
+	repo            repo.Querier
+	db              TxBeginner
+	workerPool      *WorkerPool
+	baseURL         string
+	logger          *zap.Logger
+	collectCooldown time.Duration     // intervalo minimo entre coletas manuais do mesmo produto
+	codeFilter      *CodeFilter       // codigos incluidos/excluidos da coleta agendada
+	metrics         *IngestionMetrics // contadores de produtos adicionados/importados (nil nao conta)
 }
 
-func NewService(repo repo.Querier, workerPool *WorkerPool, baseURL string, logger *zap.Logger) *svc {
+func NewService(repo repo.Querier, db TxBeginner, workerPool *WorkerPool, baseURL string, logger *zap.Logger, collectCooldown time.Duration, codeFilter *CodeFilter) *svc {
+	if codeFilter == nil {
+		codeFilter = &CodeFilter{}
+	}
 	return &svc{
-		repo:       repo,
-		workerPool: workerPool,
-		baseURL:    baseURL,
-		logger:     logger,
+		repo:            repo,
+		db:              db,
+		workerPool:      workerPool,
+		baseURL:         baseURL,
+		logger:          logger,
+		collectCooldown: collectCooldown,
+		codeFilter:      codeFilter,
+		metrics:         NewIngestionMetrics(),
 	}
 }
 
@@ -126,6 +180,7 @@ func (s *svc) UpdateProduct(ctx context.Context, productID pgtype.UUID, input Up
 	return toProductOutputFromModel(product), nil
 }
 
+// DeleteProduct archives the product: it leaves every listing but keeps its snapshot history
 func (s *svc) DeleteProduct(ctx context.Context, productID pgtype.UUID) *rest.ApiErr {
 	// Check if product exists
 	_, err := s.repo.FindProductByID(ctx, productID)
@@ -144,20 +199,92 @@ func (s *svc) DeleteProduct(ctx context.Context, productID pgtype.UUID) *rest.Ap
 	return nil
 }
 
+// DeleteProducts archives the products in a single statement and reports the ids that weren't found
+func (s *svc) DeleteProducts(ctx context.Context, ids []pgtype.UUID) (int, []FailedProduct, *rest.ApiErr) {
+	failed := make([]FailedProduct, 0)
+	if len(ids) == 0 {
+		return 0, failed, nil
+	}
+
+	deletedIDs, err := s.repo.DeleteProductsReturningIDs(ctx, ids)
+	if err != nil {
+		return 0, nil, s.handleDBError(err)
+	}
+
+	deleted := make(map[[16]byte]bool, len(deletedIDs))
+	for _, id := range deletedIDs {
+		deleted[id.Bytes] = true
+	}
+	for _, id := range ids {
+		if !deleted[id.Bytes] {
+			failed = append(failed, FailedProduct{
+				Code:   id.String(),
+				Reason: "produto nao encontrado",
+			})
+		}
+	}
+
+	return len(deletedIDs), failed, nil
+}
+
+// RestoreProduct brings an archived product back to the listings
+func (s *svc) RestoreProduct(ctx context.Context, productID pgtype.UUID) (*ProductOutput, *rest.ApiErr) {
+	restored, err := s.repo.RestoreProduct(ctx, productID)
+	if err != nil {
+		return nil, s.handleDBError(err)
+	}
+	if restored == 0 {
+		return nil, rest.NewNotFoundError("produto arquivado nao encontrado")
+	}
+
+	row, err := s.repo.FindProductByID(ctx, productID)
+	if err != nil {
+		return nil, s.handleDBError(err)
+	}
+
+	output := rowToProductOutputFromFindByCode(repo.FindProductByCodeRow(row))
+	return &output, nil
+}
+
+// ListArchivedProducts lists the archived products, most recently archived first
+func (s *svc) ListArchivedProducts(ctx context.Context) ([]ArchivedProductOutput, *rest.ApiErr) {
+	rows, err := s.repo.ListArchivedProducts(ctx)
+	if err != nil {
+		return nil, s.handleDBError(err)
+	}
+
+	products := make([]ArchivedProductOutput, 0, len(rows))
+	for _, row := range rows {
+		products = append(products, ArchivedProductOutput{
+			ProductOutput: rowToProductOutputFromFindByCode(repo.FindProductByCodeRow(row)),
+			ArchivedAt:    row.DeletedAt.Time,
+		})
+	}
+	return products, nil
+}
+
 func (s *svc) ListProducts(ctx context.Context, input ListProductsInput) (*PaginatedProductsOutput, *rest.ApiErr) {
 	// Set defaults
 	if input.Page <= 0 {
 		input.Page = 1
 	}
 	if input.PageSize <= 0 {
-		input.PageSize = 10
+		input.PageSize = DefaultPageSize
 	}
-	if input.PageSize > 100 {
-		input.PageSize = 100
+	if input.PageSize > MaxPageSize {
+		input.PageSize = MaxPageSize
 	}
 
 	offset := (input.Page - 1) * input.PageSize
 
+	statuses, apiErr := lifecycleStatusFilter(input.LifecycleStatus)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	if apiErr := normalizeProductSort(&input); apiErr != nil {
+		return nil, apiErr
+	}
+
 	// Get lifecycle status counts
 	statusCounts, err := s.repo.CountProductsByLifecycleStatus(ctx)
 	if err != nil {
@@ -170,13 +297,32 @@ func (s *svc) ListProducts(ctx context.Context, input ListProductsInput) (*Pagin
 	hasAreaFilter := input.AreaID.Valid
 	hasSearch := input.Search != ""
 
-	if hasAreaFilter && hasSearch {
+	// The status query filters with ILIKE; it has no relevance ranking to combine with fuzzy
+	if statuses != nil && hasSearch && input.Fuzzy {
+		return nil, rest.NewBadRequestError("filtro de ciclo de vida nao pode ser combinado com busca fuzzy")
+	}
+
+	if statuses != nil {
+		// Lifecycle status filter, with or without area and search - unique products
+		products, total, apiErr = s.listProductsByStatus(ctx, input, statuses, offset)
+		if apiErr != nil {
+			return nil, apiErr
+		}
+	} else if hasSearch && input.Fuzzy {
+		// Full-text search, with or without area - unique products ordered by relevance
+		products, total, apiErr = s.searchProductsRanked(ctx, input, offset)
+		if apiErr != nil {
+			return nil, apiErr
+		}
+	} else if hasAreaFilter && hasSearch {
 		// Search with area filter - unique products
 		searchRows, searchErr := s.repo.SearchUniqueProductsByAreaPaginated(ctx, repo.SearchUniqueProductsByAreaPaginatedParams{
-			AreaID: input.AreaID,
-			Search: input.Search,
-			Limit:  int32(input.PageSize),
-			Offset: int32(offset),
+			AreaID:    input.AreaID,
+			Search:    input.Search,
+			SortBy:    input.SortBy,
+			SortOrder: input.SortOrder,
+			Limit:     int32(input.PageSize),
+			Offset:    int32(offset),
 		})
 		if searchErr != nil {
 			return nil, s.handleDBError(searchErr)
@@ -197,9 +343,11 @@ func (s *svc) ListProducts(ctx context.Context, input ListProductsInput) (*Pagin
 	} else if hasAreaFilter {
 		// Filter by area only - unique products
 		areaRows, areaErr := s.repo.ListUniqueProductsByAreaPaginated(ctx, repo.ListUniqueProductsByAreaPaginatedParams{
-			AreaID: input.AreaID,
-			Limit:  int32(input.PageSize),
-			Offset: int32(offset),
+			AreaID:    input.AreaID,
+			SortBy:    input.SortBy,
+			SortOrder: input.SortOrder,
+			Limit:     int32(input.PageSize),
+			Offset:    int32(offset),
 		})
 		if areaErr != nil {
 			return nil, s.handleDBError(areaErr)
@@ -217,9 +365,11 @@ func (s *svc) ListProducts(ctx context.Context, input ListProductsInput) (*Pagin
 	} else if hasSearch {
 		// Search only (no area filter) - unique products
 		searchRows, searchErr := s.repo.SearchUniqueProductsPaginated(ctx, repo.SearchUniqueProductsPaginatedParams{
-			Search: input.Search,
-			Limit:  int32(input.PageSize),
-			Offset: int32(offset),
+			Search:    input.Search,
+			SortBy:    input.SortBy,
+			SortOrder: input.SortOrder,
+			Limit:     int32(input.PageSize),
+			Offset:    int32(offset),
 		})
 		if searchErr != nil {
 			return nil, s.handleDBError(searchErr)
@@ -237,8 +387,10 @@ func (s *svc) ListProducts(ctx context.Context, input ListProductsInput) (*Pagin
 	} else {
 		// No filters - list all unique products
 		productRows, listErr := s.repo.ListUniqueProductsPaginated(ctx, repo.ListUniqueProductsPaginatedParams{
-			Limit:  int32(input.PageSize),
-			Offset: int32(offset),
+			SortBy:    input.SortBy,
+			SortOrder: input.SortOrder,
+			Limit:     int32(input.PageSize),
+			Offset:    int32(offset),
 		})
 		if listErr != nil {
 			return nil, s.handleDBError(listErr)
@@ -255,6 +407,10 @@ func (s *svc) ListProducts(ctx context.Context, input ListProductsInput) (*Pagin
 		}
 	}
 
+	if input.IncludeSnapshot {
+		s.attachLatestSnapshots(ctx, products)
+	}
+
 	totalPages := int(math.Ceil(float64(total) / float64(input.PageSize)))
 
 	result := &PaginatedProductsOutput{
@@ -290,6 +446,8 @@ func (s *svc) GetProduct(ctx context.Context, productID pgtype.UUID) (*ProductWi
 		ManufacturerCode: product.ManufacturerCode.String,
 		Quantity:         int(product.Quantity.Int32),
 		ReplacementURL:   product.ReplacementUrl.String,
+		Family:           product.Family.String,
+		DatasheetURL:     product.DatasheetUrl.String,
 		SAPCode:          product.SapCode.String,
 		Observations:     product.Observations.String,
 		MinQuantity:      int(product.MinQuantity.Int32),
@@ -299,22 +457,30 @@ func (s *svc) GetProduct(ctx context.Context, productID pgtype.UUID) (*ProductWi
 		CreatedAt:        product.CreatedAt.Time,
 	}
 
-	var snapshotOutput *SnapshotOutput
+	output := &ProductWithSnapshotOutput{Product: productOutput}
+
+	// Sem linhas = produto ainda nao coletado; outros erros nao devem passar por "sem historico"
 	snapshot, err := s.repo.GetLatestSnapshot(ctx, productID)
-	if err == nil {
-		snapshotOutput = &SnapshotOutput{
+	switch {
+	case err == nil:
+		output.LatestSnapshot = &SnapshotOutput{
 			ID:          snapshot.ID,
 			ProductID:   snapshot.ProductID,
 			Description: snapshot.Description,
 			Status:      snapshot.Status.String,
 			CollectedAt: snapshot.CollectedAt.Time,
+			Source:      snapshot.Source.String,
+			RunID:       snapshot.RunID,
 		}
+	case !errors.Is(err, pgx.ErrNoRows):
+		s.logger.Error("failed to get latest snapshot",
+			zap.String("code", product.Code),
+			zap.Error(err),
+		)
+		output.SnapshotError = "erro ao buscar ultima coleta do produto"
 	}
 
-	return &ProductWithSnapshotOutput{
-		Product:        productOutput,
-		LatestSnapshot: snapshotOutput,
-	}, nil
+	return output, nil
 }
 
 func (s *svc) GetProductSnapshots(ctx context.Context, productID pgtype.UUID) ([]SnapshotOutput, *rest.ApiErr) {
@@ -331,12 +497,600 @@ func (s *svc) GetProductSnapshots(ctx context.Context, productID pgtype.UUID) ([
 			Description: snap.Description,
 			Status:      snap.Status.String,
 			CollectedAt: snap.CollectedAt.Time,
+			Source:      snap.Source.String,
+			RunID:       snap.RunID,
+		})
+	}
+
+	return result, nil
+}
+
+// BatchGetProducts returns the products matching the given IDs or codes with their latest snapshot.
+// Identifiers without a matching product are returned in NotFound.
+func (s *svc) BatchGetProducts(ctx context.Context, input BatchGetProductsInput) (*BatchGetProductsOutput, *rest.ApiErr) {
+	rawIDs := uniqueNonEmpty(input.IDs)
+	ids := make([]pgtype.UUID, 0, len(rawIDs))
+	for _, id := range rawIDs {
+		pgUUID, err := parser.PgUUIDFromString(id)
+		if err != nil {
+			return nil, rest.NewBadRequestError(fmt.Sprintf("id do produto invalido: %s", id))
+		}
+		ids = append(ids, pgUUID)
+	}
+	codes := uniqueNonEmpty(input.Codes)
+
+	result := &BatchGetProductsOutput{
+		Products: []ProductWithSnapshotOutput{},
+		NotFound: []string{},
+	}
+
+	seen := make(map[[16]byte]bool)
+	var rows []repo.FindProductByCodeRow
+
+	if len(ids) > 0 {
+		found, err := s.repo.FindProductsByIDs(ctx, ids)
+		if err != nil {
+			return nil, s.handleDBError(err)
+		}
+		for _, row := range found {
+			seen[row.ID.Bytes] = true
+			rows = append(rows, repo.FindProductByCodeRow(row))
+		}
+		for i, id := range ids {
+			if !seen[id.Bytes] {
+				result.NotFound = append(result.NotFound, rawIDs[i])
+			}
+		}
+	}
+
+	if len(codes) > 0 {
+		found, err := s.repo.FindProductsByCodes(ctx, codes)
+		if err != nil {
+			return nil, s.handleDBError(err)
+		}
+		foundCodes := make(map[string]bool)
+		for _, row := range found {
+			foundCodes[row.Code] = true
+			if seen[row.ID.Bytes] {
+				continue
+			}
+			seen[row.ID.Bytes] = true
+			rows = append(rows, repo.FindProductByCodeRow(row))
+		}
+		for _, code := range codes {
+			if !foundCodes[code] {
+				result.NotFound = append(result.NotFound, code)
+			}
+		}
+	}
+
+	if len(rows) == 0 {
+		return result, nil
+	}
+
+	productIDs := make([]pgtype.UUID, 0, len(rows))
+	for _, row := range rows {
+		productIDs = append(productIDs, row.ID)
+	}
+
+	snapshots, err := s.repo.ListLatestSnapshotsByProductIDs(ctx, productIDs)
+	if err != nil {
+		return nil, s.handleDBError(err)
+	}
+	latest := make(map[[16]byte]*SnapshotOutput, len(snapshots))
+	for _, snap := range snapshots {
+		latest[snap.ProductID.Bytes] = toSnapshotOutput(snap)
+	}
+
+	for _, row := range rows {
+		result.Products = append(result.Products, ProductWithSnapshotOutput{
+			Product:        rowToProductOutputFromFindByCode(row),
+			LatestSnapshot: latest[row.ID.Bytes],
 		})
 	}
 
 	return result, nil
 }
 
+// CollectProduct crawls a single product on demand and saves the result as a new snapshot,
+// reporting the lifecycle status change it caused. If the last snapshot was collected within
+// the cooldown, it is returned instead of crawling the vendor page again, unless force is set.
+func (s *svc) CollectProduct(ctx context.Context, productID pgtype.UUID, force bool) (*CollectProductOutput, *rest.ApiErr) {
+	product, err := s.repo.FindProductByID(ctx, productID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, rest.NewNotFoundError("produto nao encontrado")
+		}
+		return nil, s.handleDBError(err)
+	}
+
+	productOutput := rowToProductOutputFromFindByCode(repo.FindProductByCodeRow(product))
+
+	if !force && s.collectCooldown > 0 {
+		snapshot, err := s.repo.GetLatestSnapshot(ctx, productID)
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			return nil, s.handleDBError(err)
+		}
+		if err == nil && time.Since(snapshot.CollectedAt.Time) < s.collectCooldown {
+			nextCollectAt := snapshot.CollectedAt.Time.Add(s.collectCooldown)
+			return &CollectProductOutput{
+				Product:        productOutput,
+				LatestSnapshot: toSnapshotOutput(snapshot),
+				Cached:         true,
+				Message: fmt.Sprintf("produto coletado recentemente, nova coleta disponivel apos %s (use force para coletar agora)",
+					nextCollectAt.Format("02/01/2006 15:04")),
+			}, nil
+		}
+	}
+
+	change, apiErr := s.collectNow(ctx, product)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+
+	// Recarrega o produto para refletir o novo status de ciclo de vida
+	if updated, err := s.repo.FindProductByID(ctx, productID); err == nil {
+		productOutput = rowToProductOutputFromFindByCode(repo.FindProductByCodeRow(updated))
+	}
+
+	var snapshotOutput *SnapshotOutput
+	if snapshot, err := s.repo.GetLatestSnapshot(ctx, productID); err == nil {
+		snapshotOutput = toSnapshotOutput(snapshot)
+	}
+
+	output := &CollectProductOutput{
+		Product:        productOutput,
+		LatestSnapshot: snapshotOutput,
+	}
+	if change != nil {
+		output.StatusChange = &CollectStatusChange{
+			OldStatus:    change.OldStatus,
+			NewStatus:    change.NewStatus,
+			AlertSnoozed: change.Snoozed,
+		}
+	}
+	return output, nil
+}
+
+// collectNow crawls a product right away, skipping the queue, and saves the result as a manual
+// snapshot. Returns the lifecycle status change the crawl caused, nil when the status is the same.
+func (s *svc) collectNow(ctx context.Context, product repo.FindProductByIDRow) (*LifecycleStatusChange, *rest.ApiErr) {
+	job := CrawlerJob{
+		ProductID:   product.ID,
+		ProductCode: product.Code,
+		ProductURL:  product.Url,
+		Source:      SnapshotSourceManual,
+	}
+
+	data, err := s.workerPool.SubmitAndWait(ctx, job)
+	if err != nil {
+		s.logger.Warn("manual collect failed",
+			zap.String("code", product.Code),
+			zap.Error(err),
+		)
+		if errors.Is(err, ErrProductNotFound) {
+			return nil, rest.NewNotFoundError(reasonNotFoundOnSite)
+		}
+		return nil, rest.NewInternalServerError("erro ao coletar produto")
+	}
+
+	change, err := s.SaveCrawlResult(ctx, job, data)
+	if err != nil {
+		s.logger.Error("failed to save manual collect result",
+			zap.String("code", product.Code),
+			zap.Error(err),
+		)
+		return nil, rest.NewInternalServerError("erro ao salvar coleta do produto")
+	}
+	return change, nil
+}
+
+// RebuildProductURLs recomputes the url of every product from the current base URL and code.
+// Used after a vendor domain change, since urls are stored when the product is created.
+func (s *svc) RebuildProductURLs(ctx context.Context) (*RebuildURLsResult, *rest.ApiErr) {
+	updated, err := s.repo.RebuildProductURLs(ctx, s.baseURL)
+	if err != nil {
+		s.logger.Error("failed to rebuild product urls", zap.Error(err))
+		return nil, s.handleDBError(err)
+	}
+
+	s.logger.Info("product urls rebuilt",
+		zap.String("base_url", s.baseURL),
+		zap.Int64("updated", updated),
+	)
+
+	return &RebuildURLsResult{
+		BaseURL: s.baseURL,
+		Updated: updated,
+	}, nil
+}
+
+// RecrawlUnknownStatus crawls again every product code with no lifecycle status or never collected,
+// reporting progress for each code. Meant to fill the gaps left by failed initial crawls
+// without recrawling the whole catalog.
+func (s *svc) RecrawlUnknownStatus(ctx context.Context, onProgress func(RecrawlProgressEvent)) (*RecrawlResult, *rest.ApiErr) {
+	if onProgress == nil {
+		onProgress = func(RecrawlProgressEvent) {}
+	}
+
+	pending, err := s.repo.ListUniqueProductCodesWithUnknownStatus(ctx)
+	if err != nil {
+		s.logger.Error("failed to list products with unknown status", zap.Error(err))
+		return nil, s.handleDBError(err)
+	}
+
+	result := &RecrawlResult{Total: len(pending)}
+	onProgress(RecrawlProgressEvent{Type: ProgressEventStart, Total: result.Total})
+
+	if len(pending) > 0 {
+		jobs := make([]CrawlerJob, len(pending))
+		for i, p := range pending {
+			jobs[i] = CrawlerJob{
+				ProductID:   p.ID,
+				ProductCode: p.Code,
+				ProductURL:  p.Url,
+				Source:      SnapshotSourceRecrawl,
+			}
+		}
+
+		resultsChan, err := s.workerPool.SubmitBatch(ctx, jobs)
+		if err != nil {
+			s.logger.Error("failed to submit recrawl batch", zap.Error(err))
+			return nil, rest.NewInternalServerError("erro ao submeter coletas")
+		}
+
+		processed := 0
+		for crawlResult := range resultsChan {
+			processed++
+			code := crawlResult.Job.ProductCode
+
+			if crawlResult.Error == nil {
+				_, err = s.SaveCrawlResult(ctx, crawlResult.Job, crawlResult.Data)
+			} else {
+				err = crawlResult.Error
+			}
+
+			switch {
+			case err != nil:
+				result.Failed++
+				s.logger.Warn("recrawl failed", zap.String("code", code), zap.Error(err))
+				onProgress(RecrawlProgressEvent{Type: ProgressEventError, Code: code, Index: processed, Total: result.Total, Message: crawlFailureReason(err, "falha ao coletar dados")})
+			case crawlResult.Data.Status == "":
+				result.StillUnknown++
+				onProgress(RecrawlProgressEvent{Type: ProgressEventSuccess, Code: code, Index: processed, Total: result.Total, Message: "coletado sem status"})
+			default:
+				result.Updated++
+				onProgress(RecrawlProgressEvent{Type: ProgressEventSuccess, Code: code, Index: processed, Total: result.Total, Message: crawlResult.Data.Status})
+			}
+		}
+
+		// O canal fecha antes do fim se o contexto for cancelado; os jobs restantes
+		// continuam no worker pool e sao salvos pelo caminho assincrono
+		if processed < len(jobs) {
+			s.logger.Warn("recrawl stopped before all results arrived",
+				zap.Int("processed", processed),
+				zap.Int("total", len(jobs)),
+			)
+		}
+	}
+
+	s.logger.Info("recrawl of unknown status finished",
+		zap.Int("total", result.Total),
+		zap.Int("updated", result.Updated),
+		zap.Int("still_unknown", result.StillUnknown),
+		zap.Int("failed", result.Failed),
+	)
+
+	onProgress(RecrawlProgressEvent{Type: ProgressEventComplete, Index: result.Total, Total: result.Total, Result: result})
+	return result, nil
+}
+
+// SnoozeAlerts silences lifecycle alerts for the product's code until the end of input.Until.
+// The current lifecycle status is recorded as acknowledged: changes to or from it are not
+// notified, while a transition between two other statuses lifts the snooze.
+func (s *svc) SnoozeAlerts(ctx context.Context, productID pgtype.UUID, input SnoozeAlertsInput) (*SnoozeOutput, *rest.ApiErr) {
+	day, err := time.ParseInLocation("2006-01-02", input.Until, time.Local)
+	if err != nil {
+		return nil, rest.NewBadRequestError("data invalida, use o formato AAAA-MM-DD")
+	}
+	until := day.AddDate(0, 0, 1)
+	if !until.After(time.Now()) {
+		return nil, rest.NewBadRequestError("a data deve ser hoje ou no futuro")
+	}
+
+	product, err := s.repo.FindProductByID(ctx, productID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, rest.NewNotFoundError("produto nao encontrado")
+		}
+		return nil, s.handleDBError(err)
+	}
+
+	snooze, err := s.repo.UpsertLifecycleAlertSnooze(ctx, repo.UpsertLifecycleAlertSnoozeParams{
+		Code:          product.Code,
+		SnoozedUntil:  pgtype.Timestamp{Time: until, Valid: true},
+		SnoozedStatus: product.LifecycleStatus,
+		CreatedBy:     input.UserID,
+	})
+	if err != nil {
+		return nil, s.handleDBError(err)
+	}
+
+	s.logger.Info("lifecycle alerts snoozed",
+		zap.String("code", snooze.Code),
+		zap.Time("until", until),
+		zap.String("status", snooze.SnoozedStatus.String),
+	)
+
+	return &SnoozeOutput{
+		Code:          snooze.Code,
+		SnoozedUntil:  snooze.SnoozedUntil.Time,
+		SnoozedStatus: snooze.SnoozedStatus.String,
+	}, nil
+}
+
+// UnsnoozeAlerts removes the snooze of the product's code, if any
+func (s *svc) UnsnoozeAlerts(ctx context.Context, productID pgtype.UUID) *rest.ApiErr {
+	product, err := s.repo.FindProductByID(ctx, productID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return rest.NewNotFoundError("produto nao encontrado")
+		}
+		return s.handleDBError(err)
+	}
+
+	if _, err := s.repo.DeleteLifecycleAlertSnooze(ctx, product.Code); err != nil {
+		return s.handleDBError(err)
+	}
+	return nil
+}
+
+// BulkOverrideStatus sets the lifecycle status of each code by hand and records it as an override.
+// The override survives crawls that disagree with it and is removed once a crawl returns the same status.
+func (s *svc) BulkOverrideStatus(ctx context.Context, input BulkStatusInput) (*BulkStatusResult, *rest.ApiErr) {
+	status := strings.TrimSpace(input.Status)
+	if status == "" {
+		return nil, rest.NewBadRequestError("status e obrigatorio")
+	}
+
+	var reason pgtype.Text
+	if r := strings.TrimSpace(input.Reason); r != "" {
+		reason = pgtype.Text{String: r, Valid: true}
+	}
+
+	result := &BulkStatusResult{Status: status, Updated: []string{}}
+	seen := make(map[string]bool, len(input.Codes))
+
+	for _, code := range input.Codes {
+		code = strings.TrimSpace(code)
+		if code == "" || seen[code] {
+			continue
+		}
+		seen[code] = true
+
+		product, err := s.repo.FindProductByCode(ctx, code)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				result.NotFound = append(result.NotFound, code)
+				continue
+			}
+			return nil, s.handleDBError(err)
+		}
+
+		if _, err := s.repo.SetProductLifecycleStatus(ctx, repo.SetProductLifecycleStatusParams{
+			LifecycleStatus: pgtype.Text{String: status, Valid: true},
+			Code:            code,
+		}); err != nil {
+			return nil, s.handleDBError(err)
+		}
+
+		if _, err := s.repo.UpsertLifecycleStatusOverride(ctx, repo.UpsertLifecycleStatusOverrideParams{
+			Code:           code,
+			Status:         status,
+			PreviousStatus: product.LifecycleStatus,
+			Reason:         reason,
+			CreatedBy:      input.UserID,
+		}); err != nil {
+			return nil, s.handleDBError(err)
+		}
+
+		s.logger.Info("lifecycle status overridden",
+			zap.String("code", code),
+			zap.String("previous_status", product.LifecycleStatus.String),
+			zap.String("status", status),
+		)
+		result.Updated = append(result.Updated, code)
+	}
+
+	return result, nil
+}
+
+// applyStatusOverride returns the status to store for a crawl of code.
+// A crawl that disagrees with a manual override is logged and the override kept;
+// one that agrees confirms the correction and the override is removed.
+func (s *svc) applyStatusOverride(ctx context.Context, code, crawled string) string {
+	if crawled == "" {
+		return crawled
+	}
+
+	override, err := s.repo.FindLifecycleStatusOverride(ctx, code)
+	if err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			s.logger.Warn("failed to check lifecycle status override",
+				zap.String("code", code),
+				zap.Error(err),
+			)
+		}
+		return crawled
+	}
+
+	if override.Status != crawled {
+		s.logger.Warn("crawled lifecycle status disagrees with manual override, keeping override",
+			zap.String("code", code),
+			zap.String("crawled_status", crawled),
+			zap.String("override_status", override.Status),
+			zap.Time("overridden_at", override.CreatedAt.Time),
+		)
+		return override.Status
+	}
+
+	if _, err := s.repo.DeleteLifecycleStatusOverride(ctx, code); err != nil {
+		s.logger.Warn("failed to remove confirmed lifecycle status override",
+			zap.String("code", code),
+			zap.Error(err),
+		)
+	}
+	s.logger.Info("crawl confirmed manual lifecycle status override",
+		zap.String("code", code),
+		zap.String("status", crawled),
+	)
+	return crawled
+}
+
+// isAlertSnoozed reports whether change should be kept out of notifications.
+// Expired snoozes are removed, and so are snoozes hit by a transition that doesn't
+// involve the acknowledged status, since that is news the engineer hasn't seen.
+func (s *svc) isAlertSnoozed(ctx context.Context, change *LifecycleStatusChange) bool {
+	snooze, err := s.repo.FindActiveLifecycleAlertSnooze(ctx, change.ProductCode)
+	if err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			s.logger.Warn("failed to check lifecycle alert snooze",
+				zap.String("code", change.ProductCode),
+				zap.Error(err),
+			)
+			return false
+		}
+		// Sem snooze ativo: remove um eventual snooze vencido
+		if _, err := s.repo.DeleteLifecycleAlertSnooze(ctx, change.ProductCode); err != nil {
+			s.logger.Warn("failed to remove expired lifecycle alert snooze",
+				zap.String("code", change.ProductCode),
+				zap.Error(err),
+			)
+		}
+		return false
+	}
+
+	acknowledged := snooze.SnoozedStatus.String
+	if change.OldStatus == acknowledged || change.NewStatus == acknowledged {
+		return true
+	}
+
+	if _, err := s.repo.DeleteLifecycleAlertSnooze(ctx, change.ProductCode); err != nil {
+		s.logger.Warn("failed to lift lifecycle alert snooze",
+			zap.String("code", change.ProductCode),
+			zap.Error(err),
+		)
+	}
+	s.logger.Info("lifecycle alert snooze lifted by new transition",
+		zap.String("code", change.ProductCode),
+		zap.String("acknowledged_status", acknowledged),
+		zap.String("new_status", change.NewStatus),
+	)
+	return false
+}
+
+// selfTestTimeout bounds the self-test crawl so an uptime monitor gets an answer
+const selfTestTimeout = 60 * time.Second
+
+// CrawlerSelfTest crawls code synchronously and checks every field was extracted.
+// Nothing is saved, so it can run against a known-good product at any time.
+func (s *svc) CrawlerSelfTest(ctx context.Context, code string) *CrawlerSelfTestResult {
+	ctx, cancel := context.WithTimeout(ctx, selfTestTimeout)
+	defer cancel()
+
+	start := time.Now()
+	data, err := s.workerPool.CollectNow(ctx, code)
+	result := &CrawlerSelfTestResult{
+		Code:       code,
+		DurationMs: time.Since(start).Milliseconds(),
+	}
+
+	if err != nil {
+		result.Failures = append(result.Failures, fmt.Sprintf("crawl failed: %v", err))
+	} else {
+		result.Description = data.Description
+		result.LifecycleStatus = data.Status
+		result.ReplacementCode = data.ReplacementCode
+		result.Matches = data.Matches
+		result.Failures = selfTestFailures(data)
+		result.Warnings = selfTestWarnings(data)
+	}
+
+	result.Result = SelfTestPass
+	if len(result.Failures) > 0 {
+		result.Result = SelfTestFail
+		s.logger.Warn("crawler self-test failed",
+			zap.String("code", code),
+			zap.Strings("failures", result.Failures),
+			zap.Int64("duration_ms", result.DurationMs),
+		)
+	}
+
+	return result
+}
+
+// selfTestFailures lists what is wrong with the canary crawl (empty when it passed).
+// The replacement code is not checked since an active product doesn't have one.
+func selfTestFailures(data *CrawledData) []string {
+	var failures []string
+	if data.Description == "" {
+		failures = append(failures, "description not found")
+	}
+	if data.Status == "" {
+		failures = append(failures, "lifecycle status not found")
+	}
+	return failures
+}
+
+// selfTestWarnings lists fields that were only found by a fallback selector, an early sign of page changes
+func selfTestWarnings(data *CrawledData) []string {
+	var warnings []string
+	for _, field := range []string{FieldDescription, FieldStatus, FieldReplacementCode} {
+		if match, ok := data.Matches[field]; ok && match.Fallback {
+			warnings = append(warnings, fmt.Sprintf("%s matched fallback selector %s", field, match.Selector))
+		}
+	}
+	return warnings
+}
+
+// CrawlerStats reports worker queue and browser page usage, for diagnosing load
+func (s *svc) CrawlerStats(ctx context.Context) (*WorkerPoolStats, *rest.ApiErr) {
+	stats := s.workerPool.Stats()
+	return &stats, nil
+}
+
+// RetryFailedCrawls crawls again the codes whose last crawl failed, saving them like the scheduler does
+func (s *svc) RetryFailedCrawls(ctx context.Context) (*RetryFailedResult, *rest.ApiErr) {
+	result, err := s.workerPool.RetryFailed(ctx, func(ctx context.Context, job CrawlerJob, data *CrawledData) error {
+		_, err := s.SaveCrawlResult(ctx, job, data)
+		return err
+	})
+	if err != nil {
+		s.logger.Error("failed to retry failed crawls", zap.Error(err))
+		return nil, rest.NewInternalServerError("erro ao submeter coletas")
+	}
+	return result, nil
+}
+
+// WorkerCount returns the number of crawl workers
+func (s *svc) WorkerCount(ctx context.Context) *WorkerCountOutput {
+	return &WorkerCountOutput{Workers: s.workerPool.Workers(), MaxWorkers: MaxWorkers}
+}
+
+// ResizeWorkers changes the number of crawl workers until the next restart
+func (s *svc) ResizeWorkers(ctx context.Context, input WorkerCountInput) (*WorkerCountOutput, *rest.ApiErr) {
+	if err := s.workerPool.Resize(input.Workers); err != nil {
+		return nil, rest.NewBadRequestError("numero de workers invalido")
+	}
+	return s.WorkerCount(ctx), nil
+}
+
+// IngestionStats reports how many products were added and imported since the last restart
+func (s *svc) IngestionStats(ctx context.Context) *IngestionStats {
+	stats := s.metrics.Stats()
+	return &stats
+}
+
 func (s *svc) AddProductsWithProgress(ctx context.Context, input AddProductsInput, onProgress ProgressCallback) (*AddProductsResult, *rest.ApiErr) {
 	result := &AddProductsResult{
 		Added:    make([]ProductOutput, 0),
@@ -414,6 +1168,7 @@ func (s *svc) AddProductsWithProgress(ctx context.Context, input AddProductsInpu
 			jobs[i] = CrawlerJob{
 				ProductCode: p.code,
 				ProductURL:  p.url,
+				Source:      SnapshotSourceAdd,
 			}
 		}
 
@@ -449,9 +1204,10 @@ func (s *svc) AddProductsWithProgress(ctx context.Context, input AddProductsInpu
 						zap.String("code", code),
 						zap.Error(crawlResult.Error),
 					)
+					reason := crawlFailureReason(crawlResult.Error, "falha ao coletar dados do produto")
 					result.Failed = append(result.Failed, FailedProduct{
 						Code:   code,
-						Reason: "falha ao coletar dados do produto",
+						Reason: reason,
 					})
 					processed++
 					if onProgress != nil {
@@ -460,7 +1216,7 @@ func (s *svc) AddProductsWithProgress(ctx context.Context, input AddProductsInpu
 							Code:    code,
 							Index:   processed,
 							Total:   total,
-							Message: "falha ao coletar dados do produto",
+							Message: reason,
 						})
 					}
 					continue
@@ -529,6 +1285,7 @@ func (s *svc) AddProductsWithProgress(ctx context.Context, input AddProductsInpu
 					Description: crawledData.Description,
 					Status:      status,
 					RawHtml:     rawHTML,
+					Source:      pgtype.Text{String: SnapshotSourceAdd, Valid: true},
 				})
 
 				if snapshotErr != nil {
@@ -563,6 +1320,8 @@ func (s *svc) AddProductsWithProgress(ctx context.Context, input AddProductsInpu
 					}
 				}
 
+				saveProductDetails(ctx, s.repo, s.logger, code, crawledData)
+
 				result.Added = append(result.Added, *toProductOutputFromModel(newProduct))
 				processed++
 				if onProgress != nil {
@@ -588,21 +1347,42 @@ func (s *svc) AddProductsWithProgress(ctx context.Context, input AddProductsInpu
 		})
 	}
 
+	s.metrics.RecordAdd(result)
 	return result, nil
 }
 
-func (s *svc) ImportFromSpreadsheet(ctx context.Context, file io.Reader, areaID pgtype.UUID) (*ImportResult, *rest.ApiErr) {
-	f, err := excelize.OpenReader(file)
-	if err != nil {
-		s.logger.Error("failed to open spreadsheet", zap.Error(err))
-		return nil, rest.NewBadRequestError("erro ao abrir planilha: " + err.Error())
+// ImportFromSpreadsheet imports the spreadsheet (or CSV) rows without crawling; input.Collect is ignored.
+// With input.DryRun nothing is written and the result reports what the import would do.
+// With input.Atomic the rows are written in one transaction, rolled back when a row fails.
+func (s *svc) ImportFromSpreadsheet(ctx context.Context, input ImportInput) (*ImportResult, *rest.ApiErr) {
+	if input.Atomic && !input.DryRun {
+		return s.importAtomically(ctx, input)
 	}
-	defer f.Close()
 
-	// Get active sheet name
-	sheetName := f.GetSheetName(0)
-	if sheetName == "" {
-		return nil, rest.NewBadRequestError("planilha vazia")
+	result, apiErr := s.importRows(ctx, input)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+
+	// Nothing was written in a dry run, so it doesn't count in the ingestion metrics
+	if input.DryRun {
+		result.DryRun = true
+		return result, nil
+	}
+	s.metrics.RecordImport(result)
+	return result, nil
+}
+
+// importRows imports each row of the file with s.repo; with input.Atomic it stops at the first failed row
+func (s *svc) importRows(ctx context.Context, input ImportInput) (*ImportResult, *rest.ApiErr) {
+	// Columns: B=Área, C=Descrição, D=Código Fabricante, E=Qtd, F=Código SAP, G=Obs, H=MIN, I=MAX, J=STATUS
+	rows, apiErr := s.readImportRows(input.File, input.Format)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	layout, apiErr := s.importLayoutFor(rows, input.Columns, input.DetectHeader)
+	if apiErr != nil {
+		return nil, apiErr
 	}
 
 	result := &ImportResult{
@@ -616,180 +1396,268 @@ func (s *svc) ImportFromSpreadsheet(ctx context.Context, file io.Reader, areaID
 	// Cache for area lookups to avoid repeated queries
 	areaCache := make(map[string]pgtype.UUID)
 
-	// Start from row 3 (1-indexed in excelize)
-	// Columns: B=Área, C=Descrição, D=Código Fabricante, E=Qtd, F=Código SAP, G=Obs, H=MIN, I=MAX, J=STATUS
+	for rowIdx := layout.start(2); rowIdx < len(rows); rowIdx++ { // Start from index 2 (row 3) by default
+		row := rows[rowIdx]
+
+		// Skip empty rows and rows without manufacturer code (main identifier)
+		if len(row) < 2 || importRowValue(row, layout.manufacturerCode) == "" {
+			continue
+		}
+
+		outcome := s.importRow(ctx, input, layout, row, rowIdx+1, areaCache, result)
+
+		// Na transacao, o primeiro erro aborta as demais consultas; a importacao toda sera desfeita
+		if input.Atomic && outcome.Failed {
+			break
+		}
+	}
+	return result, nil
+}
+
+// readImportRows returns the rows of an import file, Excel (first sheet) or CSV, in the
+// spreadsheet layout: rows[i] is row i+1 of the file
+func (s *svc) readImportRows(file io.Reader, format ImportFormat) ([][]string, *rest.ApiErr) {
+	if format == ImportFormatCSV {
+		return readCSVRows(file)
+	}
+
+	f, err := excelize.OpenReader(file)
+	if err != nil {
+		s.logger.Error("failed to open spreadsheet", zap.Error(err))
+		return nil, rest.NewBadRequestError("erro ao abrir planilha: " + err.Error())
+	}
+	defer f.Close()
+
+	// Get active sheet name
+	sheetName := f.GetSheetName(0)
+	if sheetName == "" {
+		return nil, rest.NewBadRequestError("planilha vazia")
+	}
+
 	rows, err := f.GetRows(sheetName)
 	if err != nil {
 		return nil, rest.NewBadRequestError("erro ao ler linhas da planilha")
 	}
+	return rows, nil
+}
 
-	for rowIdx := 2; rowIdx < len(rows); rowIdx++ { // Start from index 2 (row 3)
-		row := rows[rowIdx]
-		rowNum := rowIdx + 1 // Human-readable row number
-
-		// Skip empty rows
-		if len(row) < 2 {
-			continue
+// importLayoutFor returns the layout of an import: the explicit column mapping, the header found in
+// rows 1/2 when detectHeader is set, or the fixed layout
+func (s *svc) importLayoutFor(rows [][]string, columns *ColumnMapping, detectHeader bool) (importLayout, *rest.ApiErr) {
+	if columns != nil {
+		layout, err := columns.layout()
+		if err != nil {
+			return importLayout{}, rest.NewBadRequestError("mapeamento de colunas invalido: " + err.Error())
 		}
+		return layout, nil
+	}
 
-		// Get values from columns (B=1, C=2, D=3, E=4, F=5, G=6, H=7, I=8, J=9)
-		getValue := func(col int) string {
-			if col < len(row) {
-				return strings.TrimSpace(row[col])
-			}
-			return ""
+	if detectHeader {
+		if layout, ok := detectImportLayout(rows); ok {
+			return layout, nil
 		}
+		s.logger.Info("import header not recognized, using the fixed column layout")
+	}
+	return fixedImportLayout, nil
+}
 
-		areaName := getValue(1)         // B
-		description := getValue(2)      // C
-		manufacturerCode := getValue(3) // D - main product code
-		qtyStr := getValue(4)           // E
-		sapCode := getValue(5)          // F
-		observations := getValue(6)     // G
-		minStr := getValue(7)           // H
-		maxStr := getValue(8)           // I
-		status := getValue(9)           // J
+// importRowValue returns the trimmed value of column col (A=0) of an import row; "" for col -1
+func importRowValue(row []string, col int) string {
+	if col >= 0 && col < len(row) {
+		return strings.TrimSpace(row[col])
+	}
+	return ""
+}
 
-		// Skip rows without manufacturer code (main identifier)
-		if manufacturerCode == "" {
-			continue
-		}
+// importRowOutcome is what importRow did with a row, for the progress events
+type importRowOutcome struct {
+	Failed  bool
+	Message string
+	Created *repo.Product // produto criado pela linha; a importacao com progresso coleta em seguida
+}
 
-		// Parse quantity values
-		qty, _ := strconv.Atoi(qtyStr)
-		minQty, _ := strconv.Atoi(minStr)
-		maxQty, _ := strconv.Atoi(maxStr)
-
-		// Resolve area ID
-		var productAreaID pgtype.UUID
-		if areaID.Valid {
-			// Use the area_id provided in the form
-			productAreaID = areaID
-		} else if areaName != "" {
-			// Try to find area by name from cache or database
-			if cachedAreaID, ok := areaCache[areaName]; ok {
-				productAreaID = cachedAreaID
-			} else {
-				area, err := s.repo.FindAreaByName(ctx, areaName)
-				if err == nil {
-					productAreaID = area.ID
-					areaCache[areaName] = area.ID
-				} else if !errors.Is(err, pgx.ErrNoRows) {
-					s.logger.Warn("failed to find area by name",
-						zap.String("area", areaName),
-						zap.Error(err),
-					)
-				}
+// importRow creates or updates the product of one import row and adds the outcome to result.
+// rowNum is the human-readable row number; the row must have a manufacturer code.
+func (s *svc) importRow(ctx context.Context, input ImportInput, layout importLayout, row []string, rowNum int, areaCache map[string]pgtype.UUID, result *ImportResult) importRowOutcome {
+	// Get values from the layout columns (fixed layout: B=Área, C=Descrição, D=Código Fabricante, ...)
+	areaName := importRowValue(row, layout.area)
+	description := importRowValue(row, layout.description)
+	manufacturerCode := importRowValue(row, layout.manufacturerCode) // main product code
+	qtyStr := importRowValue(row, layout.quantity)
+	sapCode := importRowValue(row, layout.sapCode)
+	observations := importRowValue(row, layout.observations)
+	minStr := importRowValue(row, layout.minQuantity)
+	maxStr := importRowValue(row, layout.maxQuantity)
+	status := importRowValue(row, layout.status)
+
+	// Parse quantity values
+	qty, _ := strconv.Atoi(qtyStr)
+	minQty, _ := strconv.Atoi(minStr)
+	maxQty, _ := strconv.Atoi(maxStr)
+
+	// Resolve area ID
+	var productAreaID pgtype.UUID
+	if input.AreaID.Valid {
+		// Use the area_id provided in the form
+		productAreaID = input.AreaID
+	} else if areaName != "" {
+		// Try to find area by name from cache or database
+		if cachedAreaID, ok := areaCache[areaName]; ok {
+			productAreaID = cachedAreaID
+		} else {
+			area, err := s.repo.FindAreaByName(ctx, areaName)
+			if err == nil {
+				productAreaID = area.ID
+				areaCache[areaName] = area.ID
+			} else if !errors.Is(err, pgx.ErrNoRows) {
+				s.logger.Warn("failed to find area by name",
+					zap.String("area", areaName),
+					zap.Error(err),
+				)
 			}
 		}
+	}
 
-		// Check if product already exists (by code AND area)
-		existingProduct, err := s.repo.FindProductByCodeAndArea(ctx, repo.FindProductByCodeAndAreaParams{
+	fail := func(reason string) importRowOutcome {
+		result.Failed++
+		result.Errors = append(result.Errors, ImportError{
+			Row:    rowNum,
 			Code:   manufacturerCode,
-			AreaID: productAreaID,
+			Reason: reason,
 		})
-		if err == nil {
-			// Product exists in this area - update it
-			updateParams := repo.UpdateProductParams{
-				ID:               existingProduct.ID,
-				Description:      toPgText(description),
-				ManufacturerCode: toPgText(manufacturerCode),
-				Quantity:         pgtype.Int4{Int32: int32(qty), Valid: true},
-				SapCode:          toPgText(sapCode),
-				Observations:     toPgText(observations),
-				MinQuantity:      pgtype.Int4{Int32: int32(minQty), Valid: true},
-				MaxQuantity:      pgtype.Int4{Int32: int32(maxQty), Valid: true},
-				InventoryStatus:  toPgText(status),
-			}
+		return importRowOutcome{Failed: true, Message: reason}
+	}
 
-			updatedProduct, err := s.repo.UpdateProduct(ctx, updateParams)
-			if err != nil {
-				s.logger.Warn("failed to update product from spreadsheet",
-					zap.String("code", manufacturerCode),
-					zap.Int("row", rowNum),
-					zap.Error(err),
-				)
-				result.Failed++
-				result.Errors = append(result.Errors, ImportError{
-					Row:    rowNum,
-					Code:   manufacturerCode,
-					Reason: "erro ao atualizar produto",
-				})
-				continue
-			}
+	// Check if product already exists (by code AND area)
+	existingProduct, err := s.repo.FindProductByCodeAndArea(ctx, repo.FindProductByCodeAndAreaParams{
+		Code:   manufacturerCode,
+		AreaID: productAreaID,
+	})
+	if err == nil {
+		// Product exists in this area - update it
+		updateParams := repo.UpdateProductParams{
+			ID:               existingProduct.ID,
+			Description:      toPgText(description),
+			ManufacturerCode: toPgText(manufacturerCode),
+			Quantity:         pgtype.Int4{Int32: int32(qty), Valid: true},
+			SapCode:          toPgText(sapCode),
+			Observations:     toPgText(observations),
+			MinQuantity:      pgtype.Int4{Int32: int32(minQty), Valid: true},
+			MaxQuantity:      pgtype.Int4{Int32: int32(maxQty), Valid: true},
+			InventoryStatus:  toPgText(status),
+		}
 
-			result.Updated++
-			result.Products = append(result.Products, *toProductOutputFromModel(updatedProduct))
-		} else if errors.Is(err, pgx.ErrNoRows) {
-			// Product doesn't exist - create it
-			productURL := fmt.Sprintf("%s/%s", s.baseURL, manufacturerCode)
-
-			newProduct, err := s.repo.CreateProduct(ctx, repo.CreateProductParams{
-				Code:             manufacturerCode,
-				Url:              productURL,
-				AreaID:           productAreaID,
-				Description:      toPgText(description),
-				ManufacturerCode: toPgText(manufacturerCode),
-				Quantity:         toPgInt4(qty),
-				SapCode:          toPgText(sapCode),
-				Observations:     toPgText(observations),
-				MinQuantity:      toPgInt4(minQty),
-				MaxQuantity:      toPgInt4(maxQty),
-				InventoryStatus:  toPgText(status),
-			})
+		// Rows with the same values are not rewritten nor counted as updated
+		changes := importFieldChanges(existingProduct, updateParams)
+		if len(changes) == 0 {
+			result.Unchanged++
+			return importRowOutcome{Message: "produto sem alteracoes"}
+		}
 
+		updatedProduct := dryRunUpdate(existingImportProduct(existingProduct), updateParams)
+		if !input.DryRun {
+			updatedProduct, err = s.repo.UpdateProduct(ctx, updateParams)
 			if err != nil {
-				s.logger.Warn("failed to create product from spreadsheet",
+				s.logger.Warn("failed to update product from spreadsheet",
 					zap.String("code", manufacturerCode),
 					zap.Int("row", rowNum),
 					zap.Error(err),
 				)
-				result.Failed++
-				result.Errors = append(result.Errors, ImportError{
-					Row:    rowNum,
-					Code:   manufacturerCode,
-					Reason: "erro ao criar produto",
-				})
-				continue
+				return fail("erro ao atualizar produto")
 			}
+		}
 
-			result.Created++
-			result.Products = append(result.Products, *toProductOutput(newProduct))
-		} else {
-			// Other database error
-			s.logger.Warn("failed to check product existence",
-				zap.String("code", manufacturerCode),
-				zap.Int("row", rowNum),
-				zap.Error(err),
-			)
-			result.Failed++
-			result.Errors = append(result.Errors, ImportError{
-				Row:    rowNum,
-				Code:   manufacturerCode,
-				Reason: "erro ao verificar produto",
+		result.Updated++
+		result.Products = append(result.Products, *toProductOutputFromModel(updatedProduct))
+		if input.Diff {
+			result.Changes = append(result.Changes, ImportChange{
+				Row:       rowNum,
+				Code:      manufacturerCode,
+				ProductID: updatedProduct.ID,
+				Fields:    changes,
 			})
 		}
+		return importRowOutcome{Message: "produto atualizado"}
 	}
 
-	return result, nil
-}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		// Other database error
+		s.logger.Warn("failed to check product existence",
+			zap.String("code", manufacturerCode),
+			zap.Int("row", rowNum),
+			zap.Error(err),
+		)
+		return fail("erro ao verificar produto")
+	}
 
-func (s *svc) ImportFromSpreadsheetWithProgress(ctx context.Context, file io.Reader, areaID pgtype.UUID, onProgress ImportProgressCallback) (*ImportResult, *rest.ApiErr) {
-	f, err := excelize.OpenReader(file)
+	productURL := fmt.Sprintf("%s/%s", s.baseURL, manufacturerCode)
+	createParams := repo.CreateProductParams{
+		Code:             manufacturerCode,
+		Url:              productURL,
+		AreaID:           productAreaID,
+		Description:      toPgText(description),
+		ManufacturerCode: toPgText(manufacturerCode),
+		Quantity:         toPgInt4(qty),
+		SapCode:          toPgText(sapCode),
+		Observations:     toPgText(observations),
+		MinQuantity:      toPgInt4(minQty),
+		MaxQuantity:      toPgInt4(maxQty),
+		InventoryStatus:  toPgText(status),
+	}
+
+	// The code may already be filed under another area
+	crossArea, movedProduct, err := s.importCrossArea(ctx, input.CrossArea, rowNum, createParams, input.DryRun)
 	if err != nil {
-		s.logger.Error("failed to open spreadsheet", zap.Error(err))
-		return nil, rest.NewBadRequestError("erro ao abrir planilha: " + err.Error())
+		s.logger.Warn("failed to check product in other areas",
+			zap.String("code", manufacturerCode),
+			zap.Int("row", rowNum),
+			zap.Error(err),
+		)
+		return fail("erro ao verificar produto")
+	}
+	if crossArea != nil {
+		result.CrossArea = append(result.CrossArea, *crossArea)
+		switch crossArea.Action {
+		case CrossAreaActionSkipped:
+			result.Skipped++
+			return importRowOutcome{Message: "produto existe em outra area, nao importado"}
+		case CrossAreaActionMoved:
+			result.Updated++
+			result.Products = append(result.Products, *toProductOutputFromModel(*movedProduct))
+			return importRowOutcome{Message: "produto movido de outra area"}
+		}
 	}
-	defer f.Close()
 
-	sheetName := f.GetSheetName(0)
-	if sheetName == "" {
-		return nil, rest.NewBadRequestError("planilha vazia")
+	// Product doesn't exist - create it
+	if input.DryRun {
+		result.Created++
+		result.Products = append(result.Products, *toProductOutput(dryRunCreate(createParams)))
+		return importRowOutcome{Message: "produto criado"}
 	}
 
-	rows, err := f.GetRows(sheetName)
+	newProduct, err := s.repo.CreateProduct(ctx, createParams)
 	if err != nil {
-		return nil, rest.NewBadRequestError("erro ao ler linhas da planilha")
+		s.logger.Warn("failed to create product from spreadsheet",
+			zap.String("code", manufacturerCode),
+			zap.Int("row", rowNum),
+			zap.Error(err),
+		)
+		return fail("erro ao criar produto")
+	}
+
+	result.Created++
+	result.Products = append(result.Products, *toProductOutput(newProduct))
+	return importRowOutcome{Message: "produto criado", Created: &newProduct}
+}
+
+func (s *svc) ImportFromSpreadsheetWithProgress(ctx context.Context, input ImportInput, onProgress ImportProgressCallback) (*ImportResult, *rest.ApiErr) {
+	rows, apiErr := s.readImportRows(input.File, input.Format)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	layout, apiErr := s.importLayoutFor(rows, input.Columns, input.DetectHeader)
+	if apiErr != nil {
+		return nil, apiErr
 	}
 
 	result := &ImportResult{
@@ -800,17 +1668,12 @@ func (s *svc) ImportFromSpreadsheetWithProgress(ctx context.Context, file io.Rea
 		Products: make([]ProductOutput, 0),
 	}
 
-	// Count valid rows (rows with manufacturer code starting from row 4)
-	validRows := make([]int, 0)
-	for rowIdx := 3; rowIdx < len(rows); rowIdx++ {
-		row := rows[rowIdx]
-		if len(row) > 3 && strings.TrimSpace(row[3]) != "" {
-			validRows = append(validRows, rowIdx)
-		}
-	}
+	// Count valid rows (rows with manufacturer code starting from row 4 by default)
+	validRows := importDataRows(rows, layout)
 
 	totalImport := len(validRows)
 	if totalImport == 0 {
+		s.metrics.RecordImport(result)
 		return result, nil
 	}
 
@@ -831,23 +1694,7 @@ func (s *svc) ImportFromSpreadsheetWithProgress(ctx context.Context, file io.Rea
 	for i, rowIdx := range validRows {
 		row := rows[rowIdx]
 		rowNum := rowIdx + 1
-
-		getValue := func(col int) string {
-			if col < len(row) {
-				return strings.TrimSpace(row[col])
-			}
-			return ""
-		}
-		
-		areaName := getValue(1)
-		description := getValue(2)
-		manufacturerCode := getValue(3)
-		qtyStr := getValue(4)
-		sapCode := getValue(5)
-		observations := getValue(6)
-		minStr := getValue(7)
-		maxStr := getValue(8)
-		status := getValue(9)
+		manufacturerCode := importRowValue(row, layout.manufacturerCode)
 
 		// Send processing event
 		if onProgress != nil {
@@ -861,182 +1708,49 @@ func (s *svc) ImportFromSpreadsheetWithProgress(ctx context.Context, file io.Rea
 			})
 		}
 
-		qty, _ := strconv.Atoi(qtyStr)
-		minQty, _ := strconv.Atoi(minStr)
-		maxQty, _ := strconv.Atoi(maxStr)
+		outcome := s.importRow(ctx, input, layout, row, rowNum, areaCache, result)
 
-		var productAreaID pgtype.UUID
-		if areaID.Valid {
-			productAreaID = areaID
-		} else if areaName != "" {
-			if cachedAreaID, ok := areaCache[areaName]; ok {
-				productAreaID = cachedAreaID
-			} else {
-				area, err := s.repo.FindAreaByName(ctx, areaName)
-				if err == nil {
-					productAreaID = area.ID
-					areaCache[areaName] = area.ID
-				} else if !errors.Is(err, pgx.ErrNoRows) {
-					s.logger.Warn("failed to find area by name",
-						zap.String("area", areaName),
-						zap.Error(err),
-					)
+		// Track new products for crawling (only unique codes); codes already collected in
+		// another area get a copy of the latest snapshot instead
+		if outcome.Created != nil {
+			if _, exists := newProductIDs[manufacturerCode]; !exists {
+				if s.copyExistingSnapshot(ctx, outcome.Created.ID, manufacturerCode) {
+					outcome.Message = "status copiado de area existente"
+				} else {
+					newProductCodes = append(newProductCodes, manufacturerCode)
+					newProductIDs[manufacturerCode] = outcome.Created.ID
 				}
 			}
 		}
 
-		existingProduct, err := s.repo.FindProductByCodeAndArea(ctx, repo.FindProductByCodeAndAreaParams{
-			Code:   manufacturerCode,
-			AreaID: productAreaID,
-		})
-
-		if err == nil {
-			// Product exists - update it
-			updateParams := repo.UpdateProductParams{
-				ID:               existingProduct.ID,
-				Description:      toPgText(description),
-				ManufacturerCode: toPgText(manufacturerCode),
-				Quantity:         pgtype.Int4{Int32: int32(qty), Valid: true},
-				SapCode:          toPgText(sapCode),
-				Observations:     toPgText(observations),
-				MinQuantity:      pgtype.Int4{Int32: int32(minQty), Valid: true},
-				MaxQuantity:      pgtype.Int4{Int32: int32(maxQty), Valid: true},
-				InventoryStatus:  toPgText(status),
-			}
-
-			updatedProduct, err := s.repo.UpdateProduct(ctx, updateParams)
-			if err != nil {
-				s.logger.Warn("failed to update product from spreadsheet",
-					zap.String("code", manufacturerCode),
-					zap.Int("row", rowNum),
-					zap.Error(err),
-				)
-				result.Failed++
-				result.Errors = append(result.Errors, ImportError{
-					Row:    rowNum,
-					Code:   manufacturerCode,
-					Reason: "erro ao atualizar produto",
-				})
-				if onProgress != nil {
-					onProgress(ImportProgressEvent{
-						Type:    ImportEventImportError,
-						Phase:   "import",
-						Code:    manufacturerCode,
-						Row:     rowNum,
-						Index:   i,
-						Total:   totalImport,
-						Message: "erro ao atualizar produto",
-					})
-				}
-				continue
-			}
-
-			result.Updated++
-			result.Products = append(result.Products, *toProductOutputFromModel(updatedProduct))
-			if onProgress != nil {
-				onProgress(ImportProgressEvent{
-					Type:    ImportEventImportSuccess,
-					Phase:   "import",
-					Code:    manufacturerCode,
-					Row:     rowNum,
-					Index:   i,
-					Total:   totalImport,
-					Message: "produto atualizado",
-				})
-			}
-		} else if errors.Is(err, pgx.ErrNoRows) {
-			// Product doesn't exist - create it
-			productURL := fmt.Sprintf("%s/%s", s.baseURL, manufacturerCode)
-
-			newProduct, err := s.repo.CreateProduct(ctx, repo.CreateProductParams{
-				Code:             manufacturerCode,
-				Url:              productURL,
-				AreaID:           productAreaID,
-				Description:      toPgText(description),
-				ManufacturerCode: toPgText(manufacturerCode),
-				Quantity:         toPgInt4(qty),
-				SapCode:          toPgText(sapCode),
-				Observations:     toPgText(observations),
-				MinQuantity:      toPgInt4(minQty),
-				MaxQuantity:      toPgInt4(maxQty),
-				InventoryStatus:  toPgText(status),
-			})
-
-			if err != nil {
-				s.logger.Warn("failed to create product from spreadsheet",
-					zap.String("code", manufacturerCode),
-					zap.Int("row", rowNum),
-					zap.Error(err),
-				)
-				result.Failed++
-				result.Errors = append(result.Errors, ImportError{
-					Row:    rowNum,
-					Code:   manufacturerCode,
-					Reason: "erro ao criar produto",
-				})
-				if onProgress != nil {
-					onProgress(ImportProgressEvent{
-						Type:    ImportEventImportError,
-						Phase:   "import",
-						Code:    manufacturerCode,
-						Row:     rowNum,
-						Index:   i,
-						Total:   totalImport,
-						Message: "erro ao criar produto",
-					})
-				}
-				continue
-			}
-
-			result.Created++
-			result.Products = append(result.Products, *toProductOutput(newProduct))
-
-			// Track new products for crawling (only unique codes)
-			if _, exists := newProductIDs[manufacturerCode]; !exists {
-				newProductCodes = append(newProductCodes, manufacturerCode)
-				newProductIDs[manufacturerCode] = newProduct.ID
-			}
-
-			if onProgress != nil {
-				onProgress(ImportProgressEvent{
-					Type:    ImportEventImportSuccess,
-					Phase:   "import",
-					Code:    manufacturerCode,
-					Row:     rowNum,
-					Index:   i,
-					Total:   totalImport,
-					Message: "produto criado",
-				})
-			}
-		} else {
-			s.logger.Warn("failed to check product existence",
-				zap.String("code", manufacturerCode),
-				zap.Int("row", rowNum),
-				zap.Error(err),
-			)
-			result.Failed++
-			result.Errors = append(result.Errors, ImportError{
-				Row:    rowNum,
-				Code:   manufacturerCode,
-				Reason: "erro ao verificar produto",
-			})
-			if onProgress != nil {
-				onProgress(ImportProgressEvent{
-					Type:    ImportEventImportError,
-					Phase:   "import",
-					Code:    manufacturerCode,
-					Row:     rowNum,
-					Index:   i,
-					Total:   totalImport,
-					Message: "erro ao verificar produto",
-				})
+		if onProgress != nil {
+			eventType := ImportEventImportSuccess
+			if outcome.Failed {
+				eventType = ImportEventImportError
 			}
+			onProgress(ImportProgressEvent{
+				Type:    eventType,
+				Phase:   "import",
+				Code:    manufacturerCode,
+				Row:     rowNum,
+				Index:   i,
+				Total:   totalImport,
+				Message: outcome.Message,
+			})
 		}
 	}
 
 	// Phase 2: Crawl new products (em paralelo)
+	// Quando a coleta está desativada, os produtos novos ficam para o job agendado
 	totalCrawl := len(newProductCodes)
-	if totalCrawl > 0 {
+	crawlDeferred := !input.Collect && totalCrawl > 0
+	if crawlDeferred {
+		s.logger.Info("crawl deferred to scheduler for imported products",
+			zap.Int("count", totalCrawl),
+		)
+	}
+
+	if input.Collect && totalCrawl > 0 {
 		if onProgress != nil {
 			onProgress(ImportProgressEvent{
 				Type:  ImportEventCrawlStart,
@@ -1053,6 +1767,7 @@ func (s *svc) ImportFromSpreadsheetWithProgress(ctx context.Context, file io.Rea
 				ProductID:   newProductIDs[code],
 				ProductCode: code,
 				ProductURL:  fmt.Sprintf("%s/%s", s.baseURL, code),
+				Source:      SnapshotSourceImport,
 			}
 		}
 
@@ -1093,7 +1808,7 @@ func (s *svc) ImportFromSpreadsheetWithProgress(ctx context.Context, file io.Rea
 							Code:    code,
 							Index:   crawlProcessed,
 							Total:   totalCrawl,
-							Message: "falha ao coletar dados",
+							Message: crawlFailureReason(crawlResult.Error, "falha ao coletar dados"),
 						})
 					}
 					continue
@@ -1111,6 +1826,7 @@ func (s *svc) ImportFromSpreadsheetWithProgress(ctx context.Context, file io.Rea
 					ProductID:   productID,
 					Description: crawledData.Description,
 					Status:      status,
+					Source:      pgtype.Text{String: SnapshotSourceImport, Valid: true},
 				})
 
 				if snapshotErr != nil {
@@ -1145,6 +1861,8 @@ func (s *svc) ImportFromSpreadsheetWithProgress(ctx context.Context, file io.Rea
 					}
 				}
 
+				saveProductDetails(ctx, s.repo, s.logger, code, crawledData)
+
 				crawlProcessed++
 				if onProgress != nil {
 					onProgress(ImportProgressEvent{
@@ -1162,30 +1880,287 @@ func (s *svc) ImportFromSpreadsheetWithProgress(ctx context.Context, file io.Rea
 
 	// Send complete event
 	if onProgress != nil {
-		onProgress(ImportProgressEvent{
+		event := ImportProgressEvent{
 			Type:         ImportEventComplete,
 			Phase:        "complete",
 			Index:        totalImport,
 			Total:        totalImport,
 			ImportResult: result,
+		}
+		if crawlDeferred {
+			event.Message = "coleta dos produtos novos sera feita na proxima execucao agendada"
+		}
+		onProgress(event)
+	}
+
+	s.metrics.RecordImport(result)
+	return result, nil
+}
+
+// PreviewImport parses the first rows of a spreadsheet (or CSV) the same way ImportFromSpreadsheetWithProgress
+// does and reports how each one would be imported, without writing anything
+func (s *svc) PreviewImport(ctx context.Context, input ImportPreviewInput) (*ImportPreviewResult, *rest.ApiErr) {
+	rows, apiErr := s.readImportRows(input.File, input.Format)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	layout, apiErr := s.importLayoutFor(rows, input.Columns, input.DetectHeader)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+
+	// Same row selection as the import: rows with manufacturer code starting from row 4 by default
+	validRows := importDataRows(rows, layout)
+
+	result := &ImportPreviewResult{
+		TotalRows: len(validRows),
+		Rows:      make([]ImportPreviewRow, 0, min(input.Limit, len(validRows))),
+	}
+
+	type areaLookup struct {
+		id    pgtype.UUID
+		found bool
+	}
+	areaCache := make(map[string]areaLookup)
+
+	for _, rowIdx := range validRows {
+		if len(result.Rows) >= input.Limit {
+			break
+		}
+
+		row := rows[rowIdx]
+		getValue := func(col int) string {
+			return importRowValue(row, col)
+		}
+
+		preview := ImportPreviewRow{
+			Row:              rowIdx + 1,
+			AreaName:         getValue(layout.area),
+			Description:      getValue(layout.description),
+			ManufacturerCode: getValue(layout.manufacturerCode),
+			SAPCode:          getValue(layout.sapCode),
+			Observations:     getValue(layout.observations),
+			InventoryStatus:  getValue(layout.status),
+		}
+
+		// Non-numeric quantities are imported as 0, so flag them here
+		parseInt := func(col int, field string) int {
+			value := getValue(col)
+			if value == "" {
+				return 0
+			}
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				preview.Warnings = append(preview.Warnings, fmt.Sprintf("%s invalido (%q), sera importado como 0", field, value))
+				return 0
+			}
+			return n
+		}
+		preview.Quantity = parseInt(layout.quantity, "quantidade")
+		preview.MinQuantity = parseInt(layout.minQuantity, "minimo")
+		preview.MaxQuantity = parseInt(layout.maxQuantity, "maximo")
+
+		switch {
+		case input.AreaID.Valid:
+			preview.AreaID = input.AreaID
+			preview.AreaResolution = "form"
+		case preview.AreaName != "":
+			lookup, ok := areaCache[preview.AreaName]
+			if !ok {
+				area, err := s.repo.FindAreaByName(ctx, preview.AreaName)
+				if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+					return nil, s.handleDBError(err)
+				}
+				lookup = areaLookup{id: area.ID, found: err == nil}
+				areaCache[preview.AreaName] = lookup
+			}
+			if lookup.found {
+				preview.AreaID = lookup.id
+				preview.AreaResolution = "found"
+			} else {
+				preview.AreaResolution = "not_found"
+				preview.Warnings = append(preview.Warnings, fmt.Sprintf("area %q nao encontrada, produto ficara sem area", preview.AreaName))
+			}
+		default:
+			preview.AreaResolution = "none"
+		}
+
+		_, err := s.repo.FindProductByCodeAndArea(ctx, repo.FindProductByCodeAndAreaParams{
+			Code:   preview.ManufacturerCode,
+			AreaID: preview.AreaID,
 		})
+		switch {
+		case err == nil:
+			preview.Action = "update"
+		case errors.Is(err, pgx.ErrNoRows):
+			preview.Action = "create"
+		default:
+			return nil, s.handleDBError(err)
+		}
+
+		result.Rows = append(result.Rows, preview)
 	}
 
 	return result, nil
 }
 
 func (s *svc) ExportToSpreadsheet(ctx context.Context) (*bytes.Buffer, *rest.ApiErr) {
+	// Exports read the whole catalog and are exempt from the per query timeout
+	ctx = postgres.WithoutQueryTimeout(ctx)
+	products, err := s.repo.ListProducts(ctx)
+	if err != nil {
+		s.logger.Error("failed to list products for export", zap.Error(err))
+		return nil, rest.NewInternalServerError("erro ao buscar produtos para exportacao")
+	}
+
+	f := buildProductsSpreadsheet(products)
+	defer f.Close()
+
+	buf := new(bytes.Buffer)
+	if err := f.Write(buf); err != nil {
+		s.logger.Error("failed to write spreadsheet to buffer", zap.Error(err))
+		return nil, rest.NewInternalServerError("erro ao gerar planilha")
+	}
+
+	return buf, nil
+}
+
+// ImportTemplate builds an empty spreadsheet with the header the import expects, styled as the export
+func (s *svc) ImportTemplate() (*bytes.Buffer, *rest.ApiErr) {
+	f := excelize.NewFile()
+	defer f.Close()
+
+	sheetName := "Sheet1"
+	autoFitColumns(f, sheetName, writeExportHeader(f, sheetName, newExportStyles(f)))
+
+	buf := new(bytes.Buffer)
+	if err := f.Write(buf); err != nil {
+		s.logger.Error("failed to write import template to buffer", zap.Error(err))
+		return nil, rest.NewInternalServerError("erro ao gerar planilha")
+	}
+
+	return buf, nil
+}
+
+// ExportToCSV exports the products as CSV in the same layout as ExportToSpreadsheet, so the file
+// can be imported back: an empty first row, the header in row 2 and columns B-J
+func (s *svc) ExportToCSV(ctx context.Context) (*bytes.Buffer, *rest.ApiErr) {
+	// Exports read the whole catalog and are exempt from the per query timeout
+	ctx = postgres.WithoutQueryTimeout(ctx)
 	products, err := s.repo.ListProducts(ctx)
 	if err != nil {
 		s.logger.Error("failed to list products for export", zap.Error(err))
 		return nil, rest.NewInternalServerError("erro ao buscar produtos para exportacao")
 	}
 
+	buf := new(bytes.Buffer)
+	if err := writeProductsCSV(buf, products); err != nil {
+		s.logger.Error("failed to write csv to buffer", zap.Error(err))
+		return nil, rest.NewInternalServerError("erro ao gerar CSV")
+	}
+
+	return buf, nil
+}
+
+// ProductsExportVersion returns the ETag and Last-Modified of the products export without building it
+func (s *svc) ProductsExportVersion(ctx context.Context) (*ExportVersion, *rest.ApiErr) {
+	version, err := s.repo.GetProductsExportVersion(ctx)
+	if err != nil {
+		s.logger.Error("failed to get export version", zap.Error(err))
+		return nil, rest.NewInternalServerError("erro ao buscar produtos para exportacao")
+	}
+
+	sum := sha256.Sum256(fmt.Appendf(nil, "%d|%d|%s",
+		version.LastUpdated.Time.UnixNano(),
+		version.ProductCount,
+		version.AreasHash,
+	))
+
+	return &ExportVersion{
+		ETag:         `"` + hex.EncodeToString(sum[:12]) + `"`,
+		LastModified: version.LastUpdated.Time,
+	}, nil
+}
+
+// buildProductsSpreadsheet lays out the products in the same columns the import reads
+func buildProductsSpreadsheet(products []repo.ListProductsRow) *excelize.File {
 	f := excelize.NewFile()
 	sheetName := "Sheet1"
 
+	styles := newExportStyles(f)
+
+	// Track max column widths for auto-sizing, starting from the headers
+	colMaxWidth := writeExportHeader(f, sheetName, styles)
+
+	// Data starts at row 3 (matching import's rowIdx=2)
+	for i, p := range products {
+		row := strconv.Itoa(i + 3)
+
+		trackWidth := func(col, value string) {
+			f.SetCellValue(sheetName, col+row, value)
+			if w := float64(len([]rune(value))); w > colMaxWidth[col] {
+				colMaxWidth[col] = w
+			}
+		}
+
+		trackWidth("B", p.AreaName.String)
+		trackWidth("C", p.Description.String)
+		trackWidth("D", p.Code)
+		trackWidth("F", p.SapCode.String)
+		trackWidth("G", computeExportObservations(p))
+		trackWidth("J", p.InventoryStatus.String)
+
+		f.SetCellStyle(sheetName, "B"+row, "J"+row, styles.data)
+
+		// Quantidades: numero (inclusive 0) quando definidas, celula vazia quando nunca informadas.
+		// O estilo numerico vale para a coluna toda, entao tabelas dinamicas tratam a coluna como numero
+		for col, qty := range map[string]pgtype.Int4{"E": p.Quantity, "H": p.MinQuantity, "I": p.MaxQuantity} {
+			if qty.Valid {
+				v := int(qty.Int32)
+				f.SetCellInt(sheetName, col+row, int64(v))
+				if w := float64(len(strconv.Itoa(v))); w > colMaxWidth[col] {
+					colMaxWidth[col] = w
+				}
+			}
+			f.SetCellStyle(sheetName, col+row, col+row, styles.quantity)
+		}
+	}
+
+	autoFitColumns(f, sheetName, colMaxWidth)
+
+	// Add autofilter on header row
+	lastRow := len(products) + 2
+	f.AutoFilter(sheetName, fmt.Sprintf("B2:J%d", lastRow), nil)
+
+	return f
+}
+
+// writeExportHeader writes the header row matching the import layout (row 2, columns B-J) and
+// returns the width of each header, to start the column auto-sizing
+func writeExportHeader(f *excelize.File, sheetName string, styles exportStyles) map[string]float64 {
+	colMaxWidth := make(map[string]float64, len(exportColumns))
+	for i, value := range exportColumns {
+		col := string(rune('B' + i))
+		f.SetCellValue(sheetName, col+"2", value)
+		colMaxWidth[col] = float64(len([]rune(value)))
+	}
+	f.SetCellStyle(sheetName, "B2", "J2", styles.header)
+	return colMaxWidth
+}
+
+// exportStyles are the cell styles shared by the exported spreadsheets
+type exportStyles struct {
+	header   int
+	data     int
+	quantity int // data style with integer number format
+}
+
+// newExportStyles creates the styles shared by the exported spreadsheets
+func newExportStyles(f *excelize.File) exportStyles {
+	var styles exportStyles
+
 	// Header style: green background, white bold text, thin borders
-	headerStyleID, _ := f.NewStyle(&excelize.Style{
+	styles.header, _ = f.NewStyle(&excelize.Style{
 		Font: &excelize.Font{
 			Bold:  true,
 			Color: "#FFFFFF",
@@ -1209,109 +2184,190 @@ func (s *svc) ExportToSpreadsheet(ctx context.Context) (*bytes.Buffer, *rest.Api
 	})
 
 	// Data style: thin borders
-	dataStyleID, _ := f.NewStyle(&excelize.Style{
-		Border: []excelize.Border{
-			{Type: "left", Color: "#000000", Style: 1},
-			{Type: "top", Color: "#000000", Style: 1},
-			{Type: "bottom", Color: "#000000", Style: 1},
-			{Type: "right", Color: "#000000", Style: 1},
-		},
+	dataBorder := []excelize.Border{
+		{Type: "left", Color: "#000000", Style: 1},
+		{Type: "top", Color: "#000000", Style: 1},
+		{Type: "bottom", Color: "#000000", Style: 1},
+		{Type: "right", Color: "#000000", Style: 1},
+	}
+	styles.data, _ = f.NewStyle(&excelize.Style{
+		Border: dataBorder,
 		Alignment: &excelize.Alignment{
 			Vertical: "center",
 		},
 	})
 
-	// Header row matching the import layout (columns B-J)
-	type header struct {
+	// Quantity style: thin borders, integer format (builtin 1 = "0") so the import reads the value back as is
+	styles.quantity, _ = f.NewStyle(&excelize.Style{
+		Border: dataBorder,
+		NumFmt: 1,
+		Alignment: &excelize.Alignment{
+			Horizontal: "right",
+			Vertical:   "center",
+		},
+	})
+
+	return styles
+}
+
+// autoFitColumns sets each column width from the longest value written to it, with padding
+func autoFitColumns(f *excelize.File, sheetName string, colMaxWidth map[string]float64) {
+	for col, maxW := range colMaxWidth {
+		width := maxW*1.2 + 4
+		if width < 8 {
+			width = 8
+		}
+		f.SetColWidth(sheetName, col, col, width)
+	}
+}
+
+// ExportLifecycleChanges builds a spreadsheet with every lifecycle transition collected in [from, to).
+// Transitions are derived from consecutive snapshots of each product, so they only go back as far
+// as the snapshot history kept in the database.
+func (s *svc) ExportLifecycleChanges(ctx context.Context, from, to time.Time) (*bytes.Buffer, *rest.ApiErr) {
+	// Exports read the whole catalog and are exempt from the per query timeout
+	ctx = postgres.WithoutQueryTimeout(ctx)
+	transitions, err := s.repo.ListLifecycleTransitions(ctx, repo.ListLifecycleTransitionsParams{
+		FromDate: pgtype.Timestamp{Time: from, Valid: true},
+		ToDate:   pgtype.Timestamp{Time: to, Valid: true},
+	})
+	if err != nil {
+		s.logger.Error("failed to list lifecycle transitions for export", zap.Error(err))
+		return nil, rest.NewInternalServerError("erro ao buscar mudancas de ciclo de vida para exportacao")
+	}
+
+	f := excelize.NewFile()
+	defer f.Close()
+	sheetName := "Sheet1"
+
+	styles := newExportStyles(f)
+
+	headers := []struct {
 		col   string
 		value string
-	}
-	headers := []header{
-		{"B", "Área"},
-		{"C", "Descrição"},
-		{"D", "Código Fabricante"},
-		{"E", "Qtd"},
-		{"F", "Código SAP"},
-		{"G", "Obs."},
-		{"H", "MIN"},
-		{"I", "MAX"},
-		{"J", "STATUS"},
+	}{
+		{"A", "Código"},
+		{"B", "Descrição"},
+		{"C", "Área"},
+		{"D", "Status Anterior"},
+		{"E", "Novo Status"},
+		{"F", "Data"},
+		{"G", "Substituto"},
 	}
 
-	// Track max column widths for auto-sizing
 	colMaxWidth := make(map[string]float64)
 	for _, h := range headers {
-		f.SetCellValue(sheetName, h.col+"2", h.value)
+		f.SetCellValue(sheetName, h.col+"1", h.value)
 		colMaxWidth[h.col] = float64(len([]rune(h.value)))
 	}
-	f.SetCellStyle(sheetName, "B2", "J2", headerStyleID)
+	f.SetCellStyle(sheetName, "A1", "G1", styles.header)
 
-	// Data starts at row 3 (matching import's rowIdx=2)
-	for i, p := range products {
-		row := strconv.Itoa(i + 3)
+	for i, t := range transitions {
+		row := strconv.Itoa(i + 2)
 
-		trackWidth := func(col, value string) {
+		values := map[string]string{
+			"A": t.Code,
+			"B": t.Description.String,
+			"C": t.AreaName.String,
+			"D": t.OldStatus,
+			"E": t.NewStatus.String,
+			"F": t.ChangedAt.Time.Format("02/01/2006 15:04"),
+			"G": t.ReplacementUrl.String,
+		}
+		for col, value := range values {
 			f.SetCellValue(sheetName, col+row, value)
 			if w := float64(len([]rune(value))); w > colMaxWidth[col] {
 				colMaxWidth[col] = w
 			}
 		}
 
-		trackWidth("B", p.AreaName.String)
-		trackWidth("C", p.Description.String)
-		trackWidth("D", p.Code)
-		trackWidth("F", p.SapCode.String)
-		trackWidth("G", computeExportObservations(p))
-		trackWidth("J", p.InventoryStatus.String)
+		f.SetCellStyle(sheetName, "A"+row, "G"+row, styles.data)
+	}
 
-		if p.Quantity.Valid {
-			v := int(p.Quantity.Int32)
-			f.SetCellValue(sheetName, "E"+row, v)
-			if w := float64(len(strconv.Itoa(v))); w > colMaxWidth["E"] {
-				colMaxWidth["E"] = w
-			}
-		}
+	autoFitColumns(f, sheetName, colMaxWidth)
+	f.AutoFilter(sheetName, fmt.Sprintf("A1:G%d", len(transitions)+1), nil)
 
-		if p.MinQuantity.Valid {
-			v := int(p.MinQuantity.Int32)
-			f.SetCellValue(sheetName, "H"+row, v)
-			if w := float64(len(strconv.Itoa(v))); w > colMaxWidth["H"] {
-				colMaxWidth["H"] = w
-			}
+	buf := new(bytes.Buffer)
+	if err := f.Write(buf); err != nil {
+		s.logger.Error("failed to write lifecycle changes spreadsheet to buffer", zap.Error(err))
+		return nil, rest.NewInternalServerError("erro ao gerar planilha")
+	}
+
+	return buf, nil
+}
+
+// exportPageSize is the number of products fetched per query while streaming the catalog
+const exportPageSize = 500
+
+// StreamProducts pages through the catalog (optionally filtered by area and search) and calls
+// onProduct for each product with its latest snapshot, so the caller can write it out without
+// holding the whole catalog in memory. Stops at the first error returned by onProduct.
+func (s *svc) StreamProducts(ctx context.Context, input ExportProductsInput, onProduct func(ProductWithSnapshotOutput) error) *rest.ApiErr {
+	// The export walks the whole catalog, so it is exempt from the per query timeout like the others
+	ctx = postgres.WithoutQueryTimeout(ctx)
+	afterID := pgtype.UUID{Valid: true}
+
+	for {
+		rows, err := s.repo.ListProductsForExport(ctx, repo.ListProductsForExportParams{
+			AreaID:  input.AreaID,
+			Search:  strings.TrimSpace(input.Search),
+			AfterID: afterID,
+			Limit:   exportPageSize,
+		})
+		if err != nil {
+			s.logger.Error("failed to list products for json export", zap.Error(err))
+			return rest.NewInternalServerError("erro ao buscar produtos para exportacao")
 		}
 
-		if p.MaxQuantity.Valid {
-			v := int(p.MaxQuantity.Int32)
-			f.SetCellValue(sheetName, "I"+row, v)
-			if w := float64(len(strconv.Itoa(v))); w > colMaxWidth["I"] {
-				colMaxWidth["I"] = w
+		for _, row := range rows {
+			if err := onProduct(exportRowToOutput(row)); err != nil {
+				s.logger.Warn("json export interrupted", zap.Error(err))
+				return rest.NewInternalServerError("erro ao exportar produtos")
 			}
 		}
 
-		f.SetCellStyle(sheetName, "B"+row, "J"+row, dataStyleID)
+		if len(rows) < exportPageSize {
+			return nil
+		}
+		afterID = rows[len(rows)-1].ID
 	}
+}
 
-	// Auto-fit column widths with padding
-	for col, maxW := range colMaxWidth {
-		width := maxW*1.2 + 4
-		if width < 8 {
-			width = 8
+func exportRowToOutput(row repo.ListProductsForExportRow) ProductWithSnapshotOutput {
+	var snapshot *SnapshotOutput
+	if row.SnapshotID.Valid {
+		snapshot = &SnapshotOutput{
+			ID:          row.SnapshotID,
+			ProductID:   row.ID,
+			Description: row.SnapshotDescription.String,
+			Status:      row.SnapshotStatus.String,
+			CollectedAt: row.SnapshotCollectedAt.Time,
 		}
-		f.SetColWidth(sheetName, col, col, width)
 	}
 
-	// Add autofilter on header row
-	lastRow := len(products) + 2
-	f.AutoFilter(sheetName, fmt.Sprintf("B2:J%d", lastRow), nil)
-
-	buf := new(bytes.Buffer)
-	if err := f.Write(buf); err != nil {
-		s.logger.Error("failed to write spreadsheet to buffer", zap.Error(err))
-		return nil, rest.NewInternalServerError("erro ao gerar planilha")
+	return ProductWithSnapshotOutput{
+		Product: ProductOutput{
+			ID:               row.ID,
+			Code:             row.Code,
+			URL:              row.Url,
+			AreaID:           row.AreaID,
+			AreaName:         row.AreaName.String,
+			Description:      row.Description.String,
+			ManufacturerCode: row.ManufacturerCode.String,
+			Quantity:         int(row.Quantity.Int32),
+			ReplacementURL:   row.ReplacementUrl.String,
+			Family:           row.Family.String,
+			DatasheetURL:     row.DatasheetUrl.String,
+			SAPCode:          row.SapCode.String,
+			Observations:     row.Observations.String,
+			MinQuantity:      int(row.MinQuantity.Int32),
+			MaxQuantity:      int(row.MaxQuantity.Int32),
+			InventoryStatus:  row.InventoryStatus.String,
+			LifeCycleStatus:  row.LifecycleStatus.String,
+			CreatedAt:        row.CreatedAt.Time,
+		},
+		LatestSnapshot: snapshot,
 	}
-	f.Close()
-
-	return buf, nil
 }
 
 func computeExportObservations(p repo.ListProductsRow) string {
@@ -1341,6 +2397,21 @@ func (s *svc) handleDBError(err error) *rest.ApiErr {
 
 // Helper functions
 
+// uniqueNonEmpty trims the values and drops blanks and duplicates, keeping the original order
+func uniqueNonEmpty(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	result := make([]string, 0, len(values))
+	for _, v := range values {
+		v = strings.TrimSpace(v)
+		if v == "" || seen[v] {
+			continue
+		}
+		seen[v] = true
+		result = append(result, v)
+	}
+	return result
+}
+
 func toPgText(s string) pgtype.Text {
 	if s == "" {
 		return pgtype.Text{}
@@ -1362,6 +2433,8 @@ func toProductOutput(p repo.Product) *ProductOutput {
 		ManufacturerCode: p.ManufacturerCode.String,
 		Quantity:         int(p.Quantity.Int32),
 		ReplacementURL:   p.ReplacementUrl.String,
+		Family:           p.Family.String,
+		DatasheetURL:     p.DatasheetUrl.String,
 		SAPCode:          p.SapCode.String,
 		Observations:     p.Observations.String,
 		MinQuantity:      int(p.MinQuantity.Int32),
@@ -1372,6 +2445,18 @@ func toProductOutput(p repo.Product) *ProductOutput {
 	}
 }
 
+func toSnapshotOutput(snap repo.ProductSnapshot) *SnapshotOutput {
+	return &SnapshotOutput{
+		ID:          snap.ID,
+		ProductID:   snap.ProductID,
+		Description: snap.Description,
+		Status:      snap.Status.String,
+		CollectedAt: snap.CollectedAt.Time,
+		Source:      snap.Source.String,
+		RunID:       snap.RunID,
+	}
+}
+
 func toProductOutputFromModel(p repo.Product) *ProductOutput {
 	return toProductOutput(p)
 }
@@ -1387,6 +2472,8 @@ func rowToProductOutputFromFindByCode(row repo.FindProductByCodeRow) ProductOutp
 		ManufacturerCode: row.ManufacturerCode.String,
 		Quantity:         int(row.Quantity.Int32),
 		ReplacementURL:   row.ReplacementUrl.String,
+		Family:           row.Family.String,
+		DatasheetURL:     row.DatasheetUrl.String,
 		SAPCode:          row.SapCode.String,
 		Observations:     row.Observations.String,
 		MinQuantity:      int(row.MinQuantity.Int32),
@@ -1457,6 +2544,8 @@ func listUniqueProductRowToOutput(row repo.ListUniqueProductsPaginatedRow) Produ
 			Description:      interfaceToString(row.Description),
 			LifeCycleStatus:  interfaceToString(row.LifecycleStatus),
 			ReplacementURL:   interfaceToString(row.ReplacementUrl),
+			Family:           interfaceToString(row.Family),
+			DatasheetURL:     interfaceToString(row.DatasheetUrl),
 			URL:              interfaceToString(row.Url),
 			ManufacturerCode: interfaceToString(row.ManufacturerCode),
 			SAPCode:          interfaceToString(row.SapCode),
@@ -1474,6 +2563,8 @@ func searchUniqueProductRowToOutput(row repo.SearchUniqueProductsPaginatedRow) P
 			Description:      interfaceToString(row.Description),
 			LifeCycleStatus:  interfaceToString(row.LifecycleStatus),
 			ReplacementURL:   interfaceToString(row.ReplacementUrl),
+			Family:           interfaceToString(row.Family),
+			DatasheetURL:     interfaceToString(row.DatasheetUrl),
 			URL:              interfaceToString(row.Url),
 			ManufacturerCode: interfaceToString(row.ManufacturerCode),
 			SAPCode:          interfaceToString(row.SapCode),
@@ -1491,6 +2582,8 @@ func uniqueProductByAreaRowToOutput(row repo.ListUniqueProductsByAreaPaginatedRo
 			Description:      interfaceToString(row.Description),
 			LifeCycleStatus:  interfaceToString(row.LifecycleStatus),
 			ReplacementURL:   interfaceToString(row.ReplacementUrl),
+			Family:           interfaceToString(row.Family),
+			DatasheetURL:     interfaceToString(row.DatasheetUrl),
 			URL:              interfaceToString(row.Url),
 			ManufacturerCode: interfaceToString(row.ManufacturerCode),
 			SAPCode:          interfaceToString(row.SapCode),
@@ -1508,6 +2601,8 @@ func uniqueProductRowToOutput(row repo.SearchUniqueProductsByAreaPaginatedRow) P
 			Description:      interfaceToString(row.Description),
 			LifeCycleStatus:  interfaceToString(row.LifecycleStatus),
 			ReplacementURL:   interfaceToString(row.ReplacementUrl),
+			Family:           interfaceToString(row.Family),
+			DatasheetURL:     interfaceToString(row.DatasheetUrl),
 			URL:              interfaceToString(row.Url),
 			ManufacturerCode: interfaceToString(row.ManufacturerCode),
 			SAPCode:          interfaceToString(row.SapCode),
@@ -1518,9 +2613,118 @@ func uniqueProductRowToOutput(row repo.SearchUniqueProductsByAreaPaginatedRow) P
 	}
 }
 
-// ListUniqueProductsToCollect returns all unique products (by code) for scheduled crawling
+// ListUniqueProductsToCollect returns all unique products (by code) for scheduled crawling,
+// leaving out codes rejected by the code filter
 func (s *svc) ListUniqueProductsToCollect(ctx context.Context) ([]repo.ListUniqueProductCodesToCollectRow, error) {
-	return s.repo.ListUniqueProductCodesToCollect(ctx)
+	rows, err := s.repo.ListUniqueProductCodesToCollect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.filterCodesToCollect(rows), nil
+}
+
+// ListStaleProductsToCollect returns the unique products (by code) whose latest snapshot is older
+// than olderThan, or that were never crawled, leaving out codes rejected by the code filter.
+// A zero olderThan returns every code, like ListUniqueProductsToCollect.
+func (s *svc) ListStaleProductsToCollect(ctx context.Context, olderThan time.Duration) ([]repo.ListUniqueProductCodesToCollectRow, error) {
+	if olderThan <= 0 {
+		return s.ListUniqueProductsToCollect(ctx)
+	}
+
+	stale, err := s.repo.ListStaleProductCodesToCollect(ctx, pgtype.Timestamp{Time: time.Now().Add(-olderThan), Valid: true})
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make([]repo.ListUniqueProductCodesToCollectRow, 0, len(stale))
+	for _, row := range stale {
+		rows = append(rows, repo.ListUniqueProductCodesToCollectRow{ID: row.ID, Code: row.Code, Url: row.Url})
+	}
+	return s.filterCodesToCollect(rows), nil
+}
+
+// filterCodesToCollect removes the codes rejected by the code filter, logging them
+func (s *svc) filterCodesToCollect(rows []repo.ListUniqueProductCodesToCollectRow) []repo.ListUniqueProductCodesToCollectRow {
+	allowed := rows[:0]
+	var skipped []string
+	for _, row := range rows {
+		if !s.codeFilter.Allows(row.Code) {
+			skipped = append(skipped, row.Code)
+			continue
+		}
+		allowed = append(allowed, row)
+	}
+
+	if len(skipped) > 0 {
+		s.logger.Info("products skipped by code filter",
+			zap.Int("count", len(skipped)),
+			zap.Strings("codes", skipped),
+		)
+	}
+	return allowed
+}
+
+// CodeFilterRules returns the include/exclude patterns applied to scheduled crawls
+func (s *svc) CodeFilterRules(ctx context.Context) *CodeFilterRules {
+	rules := s.codeFilter.Rules()
+	return &rules
+}
+
+// UpdateCodeFilter replaces the code filter patterns until the next restart, when the config applies again
+func (s *svc) UpdateCodeFilter(ctx context.Context, rules CodeFilterRules) (*CodeFilterRules, *rest.ApiErr) {
+	if err := s.codeFilter.SetRules(rules); err != nil {
+		return nil, rest.NewBadRequestError("padrao de codigo invalido: " + err.Error())
+	}
+
+	updated := s.codeFilter.Rules()
+	s.logger.Info("code filter updated",
+		zap.Strings("include", updated.Include),
+		zap.Strings("exclude", updated.Exclude),
+	)
+	return &updated, nil
+}
+
+// RecordCrawlFailure increments the consecutive failure count of code; the next successful save resets it
+func (s *svc) RecordCrawlFailure(ctx context.Context, code string, crawlErr error) (repo.CrawlFailure, error) {
+	var lastError pgtype.Text
+	if crawlErr != nil {
+		lastError = pgtype.Text{String: crawlErr.Error(), Valid: true}
+	}
+	return s.repo.RecordCrawlFailure(ctx, repo.RecordCrawlFailureParams{
+		Code:      code,
+		LastError: lastError,
+	})
+}
+
+// ListCrawlFailures returns the codes whose last crawls failed
+func (s *svc) ListCrawlFailures(ctx context.Context) ([]repo.CrawlFailure, error) {
+	return s.repo.ListCrawlFailures(ctx)
+}
+
+// QueueDigestChanges stores status changes until the next daily digest email
+func (s *svc) QueueDigestChanges(ctx context.Context, changes []LifecycleStatusChange) error {
+	for _, change := range changes {
+		err := s.repo.QueueDigestChange(ctx, repo.QueueDigestChangeParams{
+			Code:      change.ProductCode,
+			OldStatus: change.OldStatus,
+			NewStatus: change.NewStatus,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to queue %s for digest: %w", change.ProductCode, err)
+		}
+	}
+	return nil
+}
+
+// ListDigestChanges returns the status changes queued for the daily digest, oldest first
+func (s *svc) ListDigestChanges(ctx context.Context) ([]repo.LifecycleDigestChange, error) {
+	return s.repo.ListDigestChanges(ctx)
+}
+
+// ClearDigestChanges removes the queued changes up to lastID, once the digest with them was sent
+func (s *svc) ClearDigestChanges(ctx context.Context, lastID int64) error {
+	_, err := s.repo.DeleteDigestChangesThrough(ctx, lastID)
+	return err
 }
 
 // SaveCrawlResult saves the crawl result (snapshot) and updates the product lifecycle status.
@@ -1542,15 +2746,28 @@ func (s *svc) SaveCrawlResult(ctx context.Context, job CrawlerJob, data *Crawled
 		Description: data.Description,
 		Status:      status,
 		RawHtml:     rawHTML,
+		Source:      job.snapshotSource(),
+		RunID:       job.RunID,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to create snapshot: %w", err)
 	}
 
+	// Coleta bem-sucedida zera a sequencia de falhas do codigo
+	if err := s.repo.DeleteCrawlFailure(ctx, job.ProductCode); err != nil {
+		s.logger.Warn("failed to reset crawl failures",
+			zap.String("code", job.ProductCode),
+			zap.Error(err),
+		)
+	}
+
 	var statusChange *LifecycleStatusChange
 
+	// Manual overrides win over the crawled status until a crawl agrees with them
+	newStatus := s.applyStatusOverride(ctx, job.ProductCode, data.Status)
+
 	// Update product lifecycle status and replacement URL if applicable
-	if data.Status != "" || data.ReplacementCode != "" {
+	if newStatus != "" || data.ReplacementCode != "" {
 		// Get current lifecycle status before updating
 		currentProduct, err := s.repo.FindProductByCode(ctx, job.ProductCode)
 		if err != nil {
@@ -1566,8 +2783,8 @@ func (s *svc) SaveCrawlResult(ctx context.Context, job CrawlerJob, data *Crawled
 		}
 
 		var lifecycleStatus pgtype.Text
-		if data.Status != "" {
-			lifecycleStatus = pgtype.Text{String: data.Status, Valid: true}
+		if newStatus != "" {
+			lifecycleStatus = pgtype.Text{String: newStatus, Valid: true}
 		}
 
 		var replacementURL pgtype.Text
@@ -1588,14 +2805,17 @@ func (s *svc) SaveCrawlResult(ctx context.Context, job CrawlerJob, data *Crawled
 		}
 
 		// Check if lifecycle status changed
-		if data.Status != "" && oldStatus != data.Status {
+		if newStatus != "" && oldStatus != newStatus {
 			statusChange = &LifecycleStatusChange{
 				ProductCode: job.ProductCode,
 				OldStatus:   oldStatus,
-				NewStatus:   data.Status,
+				NewStatus:   newStatus,
 			}
+			statusChange.Snoozed = s.isAlertSnoozed(ctx, statusChange)
 		}
 	}
 
+	saveProductDetails(ctx, s.repo, s.logger, job.ProductCode, data)
+
 	return statusChange, nil
 }
@@ -0,0 +1,530 @@
+package products
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	repo "github.com/freitasmatheusrn/lifecycle-monitor/internal/database/postgres/sqlc"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/xuri/excelize/v2"
+	"go.uber.org/zap"
+)
+
+func TestBuildProductsSpreadsheet_QuantityCells(t *testing.T) {
+	f := buildProductsSpreadsheet([]repo.ListProductsRow{
+		{
+			Code:        "6ES7214-1AG40-0XB0",
+			Quantity:    pgtype.Int4{Int32: 0, Valid: true},
+			MinQuantity: pgtype.Int4{Int32: 2, Valid: true},
+			MaxQuantity: pgtype.Int4{},
+		},
+	})
+	defer f.Close()
+
+	const sheet = "Sheet1"
+	quantityStyle, err := f.GetCellStyle(sheet, "E3")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		cell  string
+		value string
+	}{
+		{"E3", "0"}, // zero is written, not left blank
+		{"H3", "2"},
+		{"I3", ""}, // unset stays blank
+	}
+
+	for _, tt := range tests {
+		value, err := f.GetCellValue(sheet, tt.cell, excelize.Options{RawCellValue: true})
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.cell, err)
+		}
+		if value != tt.value {
+			t.Errorf("%s: expected %q, got %q", tt.cell, tt.value, value)
+		}
+
+		cellType, err := f.GetCellType(sheet, tt.cell)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.cell, err)
+		}
+		if cellType == excelize.CellTypeSharedString || cellType == excelize.CellTypeInlineString {
+			t.Errorf("%s: expected a numeric cell, got string type %v", tt.cell, cellType)
+		}
+
+		style, err := f.GetCellStyle(sheet, tt.cell)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.cell, err)
+		}
+		if style != quantityStyle {
+			t.Errorf("%s: expected quantity style %d, got %d", tt.cell, quantityStyle, style)
+		}
+	}
+
+	if codeType, _ := f.GetCellType(sheet, "D3"); codeType != excelize.CellTypeSharedString {
+		t.Errorf("expected product code to be a string cell, got %v", codeType)
+	}
+}
+
+func TestImportTemplate(t *testing.T) {
+	service := &svc{logger: zap.NewNop()}
+
+	buf, apiErr := service.ImportTemplate()
+	if apiErr != nil {
+		t.Fatalf("unexpected error: %v", apiErr.Message)
+	}
+
+	f, err := excelize.OpenReader(buf)
+	if err != nil {
+		t.Fatalf("failed to open template: %v", err)
+	}
+	defer f.Close()
+
+	rows, err := f.GetRows("Sheet1")
+	if err != nil {
+		t.Fatalf("failed to read template: %v", err)
+	}
+	if len(rows) != 2 {
+		t.Fatalf("expected only the header in row 2, got %q", rows)
+	}
+	for i, header := range exportColumns {
+		if got := importRowValue(rows[1], i+1); got != header {
+			t.Errorf("column %c: expected %q, got %q", 'B'+i, header, got)
+		}
+	}
+
+	// A importacao reconhece o cabecalho do modelo
+	layout, ok := detectImportLayout(rows)
+	if !ok || layout.manufacturerCode != fixedImportLayout.manufacturerCode || layout.start(0) != 2 {
+		t.Errorf("expected the template header to be detected as the fixed layout, got %+v", layout)
+	}
+}
+
+func TestBuildProductsSpreadsheet_QuantityNumberFormat(t *testing.T) {
+	f := buildProductsSpreadsheet([]repo.ListProductsRow{
+		{Code: "A", Quantity: pgtype.Int4{Int32: 1500, Valid: true}},
+	})
+	defer f.Close()
+
+	// Formatted value must stay a plain integer so the import can read the export back
+	value, err := f.GetCellValue("Sheet1", "E3")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if value != "1500" {
+		t.Errorf("expected formatted quantity %q, got %q", "1500", value)
+	}
+}
+
+func TestCrawlerSelfTest(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     *CrawledData
+		err      error
+		result   string
+		failures int
+		warnings int
+	}{
+		{
+			name: "all fields extracted",
+			data: &CrawledData{
+				Description: "CPU 1214C",
+				Status:      "Active Product",
+				Matches: map[string]FieldMatch{
+					FieldDescription: {Value: "CPU 1214C", Selector: "description.headline", Confidence: 1},
+				},
+			},
+			result: SelfTestPass,
+		},
+		{
+			name: "fallback selector only warns",
+			data: &CrawledData{
+				Description: "CPU 1214C",
+				Status:      "Active Product",
+				Matches: map[string]FieldMatch{
+					FieldDescription: {Value: "CPU 1214C", Selector: "description.intro-section", Confidence: 0.5, Fallback: true},
+				},
+			},
+			result:   SelfTestPass,
+			warnings: 1,
+		},
+		{
+			name:     "missing status",
+			data:     &CrawledData{Description: "CPU 1214C"},
+			result:   SelfTestFail,
+			failures: 1,
+		},
+		{
+			name:     "crawl error",
+			err:      errors.New("could not launch browser"),
+			result:   SelfTestFail,
+			failures: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &svc{
+				workerPool: &WorkerPool{crawler: &MockPageCollector{data: tt.data, err: tt.err}},
+				logger:     zap.NewNop(),
+			}
+
+			result := service.CrawlerSelfTest(t.Context(), "6ES7214-1AG40-0XB0")
+
+			if result.Result != tt.result {
+				t.Errorf("expected %s, got %s (failures: %v)", tt.result, result.Result, result.Failures)
+			}
+			if len(result.Failures) != tt.failures {
+				t.Errorf("expected %d failures, got %v", tt.failures, result.Failures)
+			}
+			if len(result.Warnings) != tt.warnings {
+				t.Errorf("expected %d warnings, got %v", tt.warnings, result.Warnings)
+			}
+			if result.Code != "6ES7214-1AG40-0XB0" {
+				t.Errorf("expected code to be reported, got %q", result.Code)
+			}
+		})
+	}
+}
+
+// overrideQuerier serves a single lifecycle status override
+type overrideQuerier struct {
+	repo.Querier
+	override *repo.LifecycleStatusOverride
+	deleted  bool
+}
+
+func (q *overrideQuerier) FindLifecycleStatusOverride(ctx context.Context, code string) (repo.LifecycleStatusOverride, error) {
+	if q.override == nil || q.override.Code != code {
+		return repo.LifecycleStatusOverride{}, pgx.ErrNoRows
+	}
+	return *q.override, nil
+}
+
+func (q *overrideQuerier) DeleteLifecycleStatusOverride(ctx context.Context, code string) (int64, error) {
+	q.deleted = true
+	return 1, nil
+}
+
+func TestApplyStatusOverride(t *testing.T) {
+	tests := []struct {
+		name     string
+		override *repo.LifecycleStatusOverride
+		crawled  string
+		expected string
+		deleted  bool
+	}{
+		{
+			name:     "no override keeps crawled status",
+			crawled:  "Prod. Discont.",
+			expected: "Prod. Discont.",
+		},
+		{
+			name:     "disagreeing crawl keeps override",
+			override: &repo.LifecycleStatusOverride{Code: "6ES7214-1AG40-0XB0", Status: "Active Product"},
+			crawled:  "Prod. Discont.",
+			expected: "Active Product",
+		},
+		{
+			name:     "agreeing crawl removes override",
+			override: &repo.LifecycleStatusOverride{Code: "6ES7214-1AG40-0XB0", Status: "Active Product"},
+			crawled:  "Active Product",
+			expected: "Active Product",
+			deleted:  true,
+		},
+		{
+			name:     "empty crawl is not compared",
+			override: &repo.LifecycleStatusOverride{Code: "6ES7214-1AG40-0XB0", Status: "Active Product"},
+			crawled:  "",
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			querier := &overrideQuerier{override: tt.override}
+			service := &svc{repo: querier, logger: zap.NewNop()}
+
+			if got := service.applyStatusOverride(t.Context(), "6ES7214-1AG40-0XB0", tt.crawled); got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+			if querier.deleted != tt.deleted {
+				t.Errorf("expected deleted=%v, got %v", tt.deleted, querier.deleted)
+			}
+		})
+	}
+}
+
+// crossAreaQuerier serves products by code and records the product moved by UpdateProduct
+type crossAreaQuerier struct {
+	repo.Querier
+	products []repo.FindProductsByCodesRow
+	moved    *repo.UpdateProductParams
+}
+
+func (q *crossAreaQuerier) FindProductsByCodes(ctx context.Context, codes []string) ([]repo.FindProductsByCodesRow, error) {
+	return q.products, nil
+}
+
+func (q *crossAreaQuerier) UpdateProduct(ctx context.Context, arg repo.UpdateProductParams) (repo.Product, error) {
+	q.moved = &arg
+	return repo.Product{ID: arg.ID, AreaID: arg.AreaID}, nil
+}
+
+func TestImportCrossArea(t *testing.T) {
+	target := pgtype.UUID{Bytes: [16]byte{1}, Valid: true}
+	otherArea := repo.FindProductsByCodesRow{
+		ID:       pgtype.UUID{Bytes: [16]byte{10}, Valid: true},
+		AreaID:   pgtype.UUID{Bytes: [16]byte{2}, Valid: true},
+		AreaName: pgtype.Text{String: "Laminacao", Valid: true},
+	}
+	thirdArea := repo.FindProductsByCodesRow{
+		ID:     pgtype.UUID{Bytes: [16]byte{11}, Valid: true},
+		AreaID: pgtype.UUID{Bytes: [16]byte{3}, Valid: true},
+	}
+
+	tests := []struct {
+		name     string
+		policy   string
+		areaID   pgtype.UUID
+		products []repo.FindProductsByCodesRow
+		action   string // empty when no match is expected
+		moved    bool
+	}{
+		{name: "code in no other area", policy: CrossAreaMove, areaID: target},
+		{name: "create keeps creating", policy: CrossAreaCreate, areaID: target, products: []repo.FindProductsByCodesRow{otherArea}, action: CrossAreaActionCreated},
+		{name: "warn skips the row", policy: CrossAreaWarn, areaID: target, products: []repo.FindProductsByCodesRow{otherArea}, action: CrossAreaActionSkipped},
+		{name: "move reuses the product", policy: CrossAreaMove, areaID: target, products: []repo.FindProductsByCodesRow{otherArea}, action: CrossAreaActionMoved, moved: true},
+		{name: "move with several matches skips", policy: CrossAreaMove, areaID: target, products: []repo.FindProductsByCodesRow{otherArea, thirdArea}, action: CrossAreaActionSkipped},
+		{name: "move without target area skips", policy: CrossAreaMove, products: []repo.FindProductsByCodesRow{otherArea}, action: CrossAreaActionSkipped},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			querier := &crossAreaQuerier{products: tt.products}
+			service := &svc{repo: querier, logger: zap.NewNop()}
+
+			match, moved, err := service.importCrossArea(t.Context(), tt.policy, 4, repo.CreateProductParams{
+				Code:   "6ES7214-1AG40-0XB0",
+				AreaID: tt.areaID,
+			}, false)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if tt.action == "" {
+				if match != nil {
+					t.Fatalf("expected no cross-area match, got %+v", match)
+				}
+				return
+			}
+			if match == nil || match.Action != tt.action {
+				t.Fatalf("expected action %q, got %+v", tt.action, match)
+			}
+			if match.Row != 4 || len(match.Matches) != len(tt.products) {
+				t.Errorf("expected row 4 with %d matches, got %+v", len(tt.products), match)
+			}
+
+			if tt.moved {
+				if moved == nil || querier.moved == nil || querier.moved.ID != otherArea.ID || querier.moved.AreaID != target {
+					t.Errorf("expected product %v moved to target area, got %+v", otherArea.ID, querier.moved)
+				}
+			} else if moved != nil || querier.moved != nil {
+				t.Errorf("expected no product moved, got %+v", querier.moved)
+			}
+		})
+	}
+}
+
+// snapshotQuerier serves one product and a fixed GetLatestSnapshot outcome
+type snapshotQuerier struct {
+	repo.Querier
+	snapshot    repo.ProductSnapshot
+	snapshotErr error
+}
+
+func (q *snapshotQuerier) FindProductByID(ctx context.Context, id pgtype.UUID) (repo.FindProductByIDRow, error) {
+	return repo.FindProductByIDRow{ID: id, Code: "6ES7214-1AG40-0XB0"}, nil
+}
+
+func (q *snapshotQuerier) GetLatestSnapshot(ctx context.Context, productID pgtype.UUID) (repo.ProductSnapshot, error) {
+	return q.snapshot, q.snapshotErr
+}
+
+func TestGetProduct_LatestSnapshot(t *testing.T) {
+	productID := pgtype.UUID{Bytes: [16]byte{1}, Valid: true}
+
+	tests := []struct {
+		name         string
+		snapshotErr  error
+		wantSnapshot bool
+		wantError    bool
+	}{
+		{name: "snapshot found", wantSnapshot: true},
+		{name: "no snapshot yet", snapshotErr: pgx.ErrNoRows},
+		{name: "database error", snapshotErr: errors.New("connection reset"), wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			querier := &snapshotQuerier{
+				snapshot:    repo.ProductSnapshot{ProductID: productID, Description: "CPU 1214C"},
+				snapshotErr: tt.snapshotErr,
+			}
+			service := &svc{repo: querier, logger: zap.NewNop()}
+
+			result, apiErr := service.GetProduct(t.Context(), productID)
+			if apiErr != nil {
+				t.Fatalf("unexpected error: %v", apiErr)
+			}
+
+			if (result.LatestSnapshot != nil) != tt.wantSnapshot {
+				t.Errorf("expected snapshot=%v, got %+v", tt.wantSnapshot, result.LatestSnapshot)
+			}
+			if (result.SnapshotError != "") != tt.wantError {
+				t.Errorf("expected snapshot error=%v, got %q", tt.wantError, result.SnapshotError)
+			}
+		})
+	}
+}
+
+// staleQuerier returns its rows as the stale codes and records the cutoff it was asked for
+type staleQuerier struct {
+	repo.Querier
+	rows            []repo.ListStaleProductCodesToCollectRow
+	collectedBefore pgtype.Timestamp
+}
+
+func (q *staleQuerier) ListStaleProductCodesToCollect(ctx context.Context, collectedBefore pgtype.Timestamp) ([]repo.ListStaleProductCodesToCollectRow, error) {
+	q.collectedBefore = collectedBefore
+	return q.rows, nil
+}
+
+func TestListStaleProductsToCollect(t *testing.T) {
+	querier := &staleQuerier{rows: []repo.ListStaleProductCodesToCollectRow{
+		{Code: "6ES7214-1AG40-0XB0"},
+		{Code: "3RT2015-1BB41"},
+	}}
+	filter, err := NewCodeFilter(CodeFilterRules{Exclude: []string{"3RT"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	service := &svc{repo: querier, codeFilter: filter, logger: zap.NewNop()}
+
+	before := time.Now().Add(-6 * time.Hour)
+	rows, err := service.ListStaleProductsToCollect(t.Context(), 6*time.Hour)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(rows) != 1 || rows[0].Code != "6ES7214-1AG40-0XB0" {
+		t.Errorf("expected only the code allowed by the filter, got %+v", rows)
+	}
+	cutoff := querier.collectedBefore.Time
+	if !querier.collectedBefore.Valid || cutoff.Before(before) || cutoff.After(time.Now().Add(-6*time.Hour)) {
+		t.Errorf("expected a cutoff 6h ago, got %v", querier.collectedBefore)
+	}
+}
+
+// deleteQuerier deletes only the products in existing
+type deleteQuerier struct {
+	repo.Querier
+	existing map[[16]byte]bool
+}
+
+func (q *deleteQuerier) DeleteProductsReturningIDs(ctx context.Context, ids []pgtype.UUID) ([]pgtype.UUID, error) {
+	var deleted []pgtype.UUID
+	for _, id := range ids {
+		if q.existing[id.Bytes] {
+			delete(q.existing, id.Bytes)
+			deleted = append(deleted, id)
+		}
+	}
+	return deleted, nil
+}
+
+func TestDeleteProducts(t *testing.T) {
+	found := pgtype.UUID{Bytes: [16]byte{1}, Valid: true}
+	missing := pgtype.UUID{Bytes: [16]byte{2}, Valid: true}
+	querier := &deleteQuerier{existing: map[[16]byte]bool{found.Bytes: true}}
+	service := &svc{repo: querier, logger: zap.NewNop()}
+
+	deleted, failed, apiErr := service.DeleteProducts(t.Context(), []pgtype.UUID{found, missing})
+	if apiErr != nil {
+		t.Fatalf("unexpected error: %v", apiErr.Message)
+	}
+
+	if deleted != 1 {
+		t.Errorf("expected 1 product deleted, got %d", deleted)
+	}
+	if len(failed) != 1 || failed[0].Code != missing.String() || failed[0].Reason != "produto nao encontrado" {
+		t.Errorf("expected the missing id to be reported, got %+v", failed)
+	}
+}
+
+// archiveQuerier has one archived product, restored by RestoreProduct
+type archiveQuerier struct {
+	repo.Querier
+	archived repo.ListArchivedProductsRow
+	restored bool
+}
+
+func (q *archiveQuerier) ListArchivedProducts(ctx context.Context) ([]repo.ListArchivedProductsRow, error) {
+	if q.restored {
+		return nil, nil
+	}
+	return []repo.ListArchivedProductsRow{q.archived}, nil
+}
+
+func (q *archiveQuerier) RestoreProduct(ctx context.Context, id pgtype.UUID) (int64, error) {
+	if q.restored || id != q.archived.ID {
+		return 0, nil
+	}
+	q.restored = true
+	return 1, nil
+}
+
+func (q *archiveQuerier) FindProductByID(ctx context.Context, id pgtype.UUID) (repo.FindProductByIDRow, error) {
+	if !q.restored || id != q.archived.ID {
+		return repo.FindProductByIDRow{}, pgx.ErrNoRows
+	}
+	row := repo.FindProductByIDRow(q.archived)
+	row.DeletedAt = pgtype.Timestamp{}
+	return row, nil
+}
+
+func TestArchivedProducts(t *testing.T) {
+	archivedAt := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	querier := &archiveQuerier{archived: repo.ListArchivedProductsRow{
+		ID:        pgtype.UUID{Bytes: [16]byte{1}, Valid: true},
+		Code:      "6ES7214-1AG40-0XB0",
+		AreaName:  pgtype.Text{String: "Linha 1", Valid: true},
+		DeletedAt: pgtype.Timestamp{Time: archivedAt, Valid: true},
+	}}
+	service := &svc{repo: querier, logger: zap.NewNop()}
+
+	archived, apiErr := service.ListArchivedProducts(t.Context())
+	if apiErr != nil {
+		t.Fatalf("unexpected error: %v", apiErr.Message)
+	}
+	if len(archived) != 1 || archived[0].Code != "6ES7214-1AG40-0XB0" || archived[0].AreaName != "Linha 1" || !archived[0].ArchivedAt.Equal(archivedAt) {
+		t.Fatalf("unexpected archived products %+v", archived)
+	}
+
+	restored, apiErr := service.RestoreProduct(t.Context(), querier.archived.ID)
+	if apiErr != nil {
+		t.Fatalf("unexpected error: %v", apiErr.Message)
+	}
+	if restored.Code != "6ES7214-1AG40-0XB0" {
+		t.Errorf("unexpected restored product %+v", restored)
+	}
+
+	// Um produto ativo (ou ja restaurado) nao e encontrado entre os arquivados
+	if _, apiErr := service.RestoreProduct(t.Context(), querier.archived.ID); apiErr == nil || apiErr.Code != 404 {
+		t.Errorf("expected 404 restoring an active product, got %+v", apiErr)
+	}
+}
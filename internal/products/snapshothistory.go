@@ -0,0 +1,58 @@
+package products
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	repo "github.com/freitasmatheusrn/lifecycle-monitor/internal/database/postgres/sqlc"
+	"github.com/freitasmatheusrn/lifecycle-monitor/pkg/rest"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// GetSnapshotDiffs walks the snapshots of a product in collection order and returns, newest first,
+// the ones whose status or description differ from the previous collection
+func (s *svc) GetSnapshotDiffs(ctx context.Context, productID pgtype.UUID) ([]SnapshotDiff, *rest.ApiErr) {
+	if _, err := s.repo.FindProductByID(ctx, productID); err != nil {
+		return nil, s.handleDBError(err)
+	}
+
+	snapshots, err := s.repo.ListProductSnapshots(ctx, productID)
+	if err != nil {
+		return nil, s.handleDBError(err)
+	}
+
+	return snapshotDiffs(snapshots), nil
+}
+
+// snapshotDiffs expects the snapshots newest first, as ListProductSnapshots returns them
+func snapshotDiffs(snapshots []repo.ProductSnapshot) []SnapshotDiff {
+	diffs := make([]SnapshotDiff, 0)
+	var previous *repo.ProductSnapshot
+	for i := len(snapshots) - 1; i >= 0; i-- {
+		snap := &snapshots[i]
+		diff := SnapshotDiff{
+			SnapshotID:  snap.ID,
+			CollectedAt: snap.CollectedAt.Time,
+			Source:      snap.Source.String,
+			Status:      snap.Status.String,
+			Description: snap.Description,
+		}
+		if previous != nil {
+			diff.PreviousCollectedAt = &previous.CollectedAt.Time
+			diff.PreviousStatus = previous.Status.String
+			diff.PreviousDescription = previous.Description
+		}
+		diff.StatusChanged = diff.Status != diff.PreviousStatus
+		// Espacos extras na pagina do fabricante nao contam como mudanca
+		diff.DescriptionChanged = strings.TrimSpace(diff.Description) != strings.TrimSpace(diff.PreviousDescription)
+
+		if previous == nil || diff.StatusChanged || diff.DescriptionChanged {
+			diffs = append(diffs, diff)
+		}
+		previous = snap
+	}
+
+	slices.Reverse(diffs)
+	return diffs
+}
@@ -0,0 +1,62 @@
+package products
+
+import (
+	"testing"
+	"time"
+
+	repo "github.com/freitasmatheusrn/lifecycle-monitor/internal/database/postgres/sqlc"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func TestSnapshotDiffs(t *testing.T) {
+	start := time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC)
+	snapshot := func(day int, status, description string) repo.ProductSnapshot {
+		return repo.ProductSnapshot{
+			Description: description,
+			Status:      pgtype.Text{String: status, Valid: true},
+			CollectedAt: pgtype.Timestamp{Time: start.AddDate(0, 0, day), Valid: true},
+		}
+	}
+
+	// Mais recente primeiro, como ListProductSnapshots retorna
+	diffs := snapshotDiffs([]repo.ProductSnapshot{
+		snapshot(4, "Discontinued", "CPU 1214C"),
+		snapshot(3, "Phase Out", "CPU 1214C "),
+		snapshot(2, "Phase Out", "CPU 1214C"),
+		snapshot(1, "Active", "CPU 1214C"),
+		snapshot(0, "Active", "CPU 1214"),
+	})
+
+	if len(diffs) != 4 {
+		t.Fatalf("expected 4 diffs (unchanged and whitespace-only collections skipped), got %+v", diffs)
+	}
+
+	latest := diffs[0]
+	if !latest.StatusChanged || latest.DescriptionChanged || latest.PreviousStatus != "Phase Out" || latest.Status != "Discontinued" {
+		t.Errorf("unexpected latest diff %+v", latest)
+	}
+	if latest.PreviousCollectedAt == nil || !latest.PreviousCollectedAt.Equal(start.AddDate(0, 0, 3)) {
+		t.Errorf("expected the previous collection to be day 3, got %v", latest.PreviousCollectedAt)
+	}
+
+	phaseOut := diffs[1]
+	if !phaseOut.CollectedAt.Equal(start.AddDate(0, 0, 2)) || !phaseOut.StatusChanged {
+		t.Errorf("unexpected phase out diff %+v", phaseOut)
+	}
+
+	renamed := diffs[2]
+	if renamed.StatusChanged || !renamed.DescriptionChanged || renamed.PreviousDescription != "CPU 1214" {
+		t.Errorf("unexpected description diff %+v", renamed)
+	}
+
+	first := diffs[3]
+	if first.PreviousCollectedAt != nil || first.PreviousStatus != "" || first.Status != "Active" {
+		t.Errorf("expected the first collection to open the timeline, got %+v", first)
+	}
+}
+
+func TestSnapshotDiffs_Empty(t *testing.T) {
+	if diffs := snapshotDiffs(nil); diffs == nil || len(diffs) != 0 {
+		t.Errorf("expected an empty list, got %#v", diffs)
+	}
+}
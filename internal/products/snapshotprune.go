@@ -0,0 +1,59 @@
+package products
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/freitasmatheusrn/lifecycle-monitor/pkg/rest"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+)
+
+// SnapshotRetention bounds the growth of product_snapshots. Each crawl adds a row with the page HTML,
+// so the table grows with every run. Zero values disable the matching step.
+type SnapshotRetention struct {
+	KeepPerProduct int           // snapshots kept per product; older ones that repeat the previous collection are deleted
+	RawHTMLAfter   time.Duration // age after which the raw HTML of a snapshot is dropped, keeping the row
+}
+
+// PruneSnapshots deletes, for each product, the snapshots beyond the newest keepPerProduct whose status
+// and description repeat the collection before them, then drops the raw HTML of the snapshots collected
+// more than olderThan ago. Status transitions are never deleted, so the history stays readable.
+func (s *svc) PruneSnapshots(ctx context.Context, keepPerProduct int, olderThan time.Duration) (*PruneSnapshotsResult, *rest.ApiErr) {
+	if keepPerProduct < 0 {
+		return nil, rest.NewBadRequestError(fmt.Sprintf("keep_per_product invalido: %d", keepPerProduct))
+	}
+	if olderThan < 0 {
+		return nil, rest.NewBadRequestError("retencao do html invalida")
+	}
+
+	result := &PruneSnapshotsResult{KeepPerProduct: keepPerProduct}
+	if keepPerProduct > 0 {
+		deleted, err := s.repo.DeleteRedundantSnapshots(ctx, int32(keepPerProduct))
+		if err != nil {
+			s.logger.Error("failed to delete old snapshots", zap.Error(err))
+			return nil, s.handleDBError(err)
+		}
+		result.DeletedSnapshots = deleted
+	}
+
+	if olderThan > 0 {
+		cutoff := time.Now().Add(-olderThan)
+		cleared, err := s.repo.ClearSnapshotRawHTML(ctx, pgtype.Timestamp{Time: cutoff, Valid: true})
+		if err != nil {
+			s.logger.Error("failed to clear snapshot raw html", zap.Error(err))
+			return nil, s.handleDBError(err)
+		}
+		result.RawHTMLBefore = &cutoff
+		result.ClearedRawHTML = cleared
+	}
+
+	s.logger.Info("snapshots pruned",
+		zap.Int("keep_per_product", keepPerProduct),
+		zap.Duration("raw_html_after", olderThan),
+		zap.Int64("deleted", result.DeletedSnapshots),
+		zap.Int64("raw_html_cleared", result.ClearedRawHTML),
+	)
+	return result, nil
+}
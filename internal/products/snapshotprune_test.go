@@ -0,0 +1,75 @@
+package products
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	repo "github.com/freitasmatheusrn/lifecycle-monitor/internal/database/postgres/sqlc"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+)
+
+// pruneQuerier records the retention queries it receives
+type pruneQuerier struct {
+	repo.Querier
+	keepPerProduct  int32
+	deleteCalls     int
+	collectedBefore pgtype.Timestamp
+	clearCalls      int
+}
+
+func (q *pruneQuerier) DeleteRedundantSnapshots(ctx context.Context, keepPerProduct int32) (int64, error) {
+	q.deleteCalls++
+	q.keepPerProduct = keepPerProduct
+	return 7, nil
+}
+
+func (q *pruneQuerier) ClearSnapshotRawHTML(ctx context.Context, collectedBefore pgtype.Timestamp) (int64, error) {
+	q.clearCalls++
+	q.collectedBefore = collectedBefore
+	return 42, nil
+}
+
+func TestPruneSnapshots(t *testing.T) {
+	querier := &pruneQuerier{}
+	service := &svc{repo: querier, logger: zap.NewNop()}
+
+	before := time.Now()
+	result, apiErr := service.PruneSnapshots(t.Context(), 30, 90*24*time.Hour)
+	if apiErr != nil {
+		t.Fatalf("unexpected error: %v", apiErr.Message)
+	}
+
+	if querier.keepPerProduct != 30 || result.DeletedSnapshots != 7 {
+		t.Errorf("expected 7 snapshots deleted keeping 30, got %d keeping %d", result.DeletedSnapshots, querier.keepPerProduct)
+	}
+	cutoff, expected := querier.collectedBefore.Time, before.Add(-90*24*time.Hour)
+	if cutoff.Before(expected) || cutoff.Sub(expected) > time.Minute {
+		t.Errorf("expected the raw html cutoff 90 days ago, got %v", cutoff)
+	}
+	if result.ClearedRawHTML != 42 || result.RawHTMLBefore == nil || !result.RawHTMLBefore.Equal(cutoff) {
+		t.Errorf("unexpected result %+v", result)
+	}
+}
+
+func TestPruneSnapshots_Disabled(t *testing.T) {
+	querier := &pruneQuerier{}
+	service := &svc{repo: querier, logger: zap.NewNop()}
+
+	result, apiErr := service.PruneSnapshots(t.Context(), 0, 0)
+	if apiErr != nil {
+		t.Fatalf("unexpected error: %v", apiErr.Message)
+	}
+	if querier.deleteCalls != 0 || querier.clearCalls != 0 {
+		t.Errorf("expected zero limits to skip both steps, got %d deletes and %d clears", querier.deleteCalls, querier.clearCalls)
+	}
+	if result.RawHTMLBefore != nil {
+		t.Errorf("expected no raw html cutoff, got %v", result.RawHTMLBefore)
+	}
+
+	if _, apiErr := service.PruneSnapshots(t.Context(), -1, 0); apiErr == nil || apiErr.Code != http.StatusBadRequest {
+		t.Errorf("expected a negative limit to be rejected, got %+v", apiErr)
+	}
+}
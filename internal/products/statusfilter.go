@@ -0,0 +1,71 @@
+package products
+
+import (
+	"context"
+
+	repo "github.com/freitasmatheusrn/lifecycle-monitor/internal/database/postgres/sqlc"
+	"github.com/freitasmatheusrn/lifecycle-monitor/pkg/rest"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Valores do filtro lifecycle_status da listagem: os mesmos grupos das contagens exibidas
+const (
+	StatusFilterActive       = "active"
+	StatusFilterPhaseOut     = "phase_out"
+	StatusFilterDiscontinued = "discontinued"
+)
+
+// statusFilterGroups maps each filter value to the portal statuses it matches
+var statusFilterGroups = map[string][]string{
+	StatusFilterActive:       {StatusActive},
+	StatusFilterPhaseOut:     {StatusPhaseOut},
+	StatusFilterDiscontinued: {StatusCancellation, StatusEndLifecycle, StatusDiscontinued},
+}
+
+// lifecycleStatusFilter returns the statuses matched by a lifecycle_status filter value, nil when empty
+func lifecycleStatusFilter(value string) ([]string, *rest.ApiErr) {
+	if value == "" {
+		return nil, nil
+	}
+	statuses, ok := statusFilterGroups[value]
+	if !ok {
+		return nil, rest.NewBadRequestError("status de ciclo de vida invalido")
+	}
+	return statuses, nil
+}
+
+// listProductsByStatus is ListProducts with the lifecycle status filter; area and search are optional
+func (s *svc) listProductsByStatus(ctx context.Context, input ListProductsInput, statuses []string, offset int) ([]ProductWithSnapshotOutput, int64, *rest.ApiErr) {
+	var search pgtype.Text
+	if input.Search != "" {
+		search = pgtype.Text{String: input.Search, Valid: true}
+	}
+
+	rows, err := s.repo.ListUniqueProductsByStatusPaginated(ctx, repo.ListUniqueProductsByStatusPaginatedParams{
+		AreaID:    input.AreaID,
+		Statuses:  statuses,
+		Search:    search,
+		SortBy:    input.SortBy,
+		SortOrder: input.SortOrder,
+		Limit:     int32(input.PageSize),
+		Offset:    int32(offset),
+	})
+	if err != nil {
+		return nil, 0, s.handleDBError(err)
+	}
+
+	total, err := s.repo.CountUniqueProductsByStatus(ctx, repo.CountUniqueProductsByStatusParams{
+		Statuses: statuses,
+		AreaID:   input.AreaID,
+		Search:   search,
+	})
+	if err != nil {
+		return nil, 0, s.handleDBError(err)
+	}
+
+	products := make([]ProductWithSnapshotOutput, 0, len(rows))
+	for _, r := range rows {
+		products = append(products, listUniqueProductRowToOutput(repo.ListUniqueProductsPaginatedRow(r)))
+	}
+	return products, total, nil
+}
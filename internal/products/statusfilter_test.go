@@ -0,0 +1,100 @@
+package products
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"testing"
+
+	repo "github.com/freitasmatheusrn/lifecycle-monitor/internal/database/postgres/sqlc"
+	"go.uber.org/zap"
+)
+
+func TestLifecycleStatusFilter(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected []string
+	}{
+		{"", nil},
+		{StatusFilterActive, []string{StatusActive}},
+		{StatusFilterPhaseOut, []string{StatusPhaseOut}},
+		{StatusFilterDiscontinued, []string{StatusCancellation, StatusEndLifecycle, StatusDiscontinued}},
+	}
+
+	for _, tt := range tests {
+		statuses, apiErr := lifecycleStatusFilter(tt.value)
+		if apiErr != nil {
+			t.Fatalf("%q: unexpected error: %v", tt.value, apiErr.Message)
+		}
+		if !slices.Equal(statuses, tt.expected) {
+			t.Errorf("%q: expected %v, got %v", tt.value, tt.expected, statuses)
+		}
+	}
+
+	for _, value := range []string{"Active Product", "ACTIVE", "unknown"} {
+		if _, apiErr := lifecycleStatusFilter(value); apiErr == nil || apiErr.Code != http.StatusBadRequest {
+			t.Errorf("%q: expected a bad request, got %+v", value, apiErr)
+		}
+	}
+}
+
+// statusListQuerier serves the lifecycle status filtered listing
+type statusListQuerier struct {
+	repo.Querier
+	statuses []string
+	search   string
+}
+
+func (q *statusListQuerier) CountProductsByLifecycleStatus(ctx context.Context) (repo.CountProductsByLifecycleStatusRow, error) {
+	return repo.CountProductsByLifecycleStatusRow{}, nil
+}
+
+func (q *statusListQuerier) ListUniqueProductsByStatusPaginated(ctx context.Context, arg repo.ListUniqueProductsByStatusPaginatedParams) ([]repo.ListUniqueProductsByStatusPaginatedRow, error) {
+	q.statuses = arg.Statuses
+	q.search = arg.Search.String
+	return []repo.ListUniqueProductsByStatusPaginatedRow{
+		{Code: "6ES7214-1AG40-0XB0", LifecycleStatus: StatusDiscontinued},
+	}, nil
+}
+
+func (q *statusListQuerier) CountUniqueProductsByStatus(ctx context.Context, arg repo.CountUniqueProductsByStatusParams) (int64, error) {
+	return 1, nil
+}
+
+func TestListProducts_LifecycleStatus(t *testing.T) {
+	querier := &statusListQuerier{}
+	service := &svc{repo: querier, logger: zap.NewNop()}
+
+	result, apiErr := service.ListProducts(t.Context(), ListProductsInput{LifecycleStatus: StatusFilterDiscontinued})
+	if apiErr != nil {
+		t.Fatalf("unexpected error: %v", apiErr.Message)
+	}
+
+	if !slices.Equal(querier.statuses, statusFilterGroups[StatusFilterDiscontinued]) {
+		t.Errorf("expected the discontinued statuses to be queried, got %v", querier.statuses)
+	}
+	if result.Total != 1 || len(result.Products) != 1 || result.Products[0].Product.LifeCycleStatus != StatusDiscontinued {
+		t.Errorf("unexpected result %+v", result)
+	}
+
+	if _, apiErr := service.ListProducts(t.Context(), ListProductsInput{LifecycleStatus: "obsolete"}); apiErr == nil {
+		t.Error("expected an error for an unknown status")
+	}
+}
+
+func TestListProducts_LifecycleStatusWithSearch(t *testing.T) {
+	querier := &statusListQuerier{}
+	service := &svc{repo: querier, logger: zap.NewNop()}
+
+	if _, apiErr := service.ListProducts(t.Context(), ListProductsInput{LifecycleStatus: StatusFilterActive, Search: "1214c"}); apiErr != nil {
+		t.Fatalf("unexpected error: %v", apiErr.Message)
+	}
+	if querier.search != "1214c" {
+		t.Errorf("expected the search to reach the status query, got %q", querier.search)
+	}
+
+	_, apiErr := service.ListProducts(t.Context(), ListProductsInput{LifecycleStatus: StatusFilterActive, Search: "1214c", Fuzzy: true})
+	if apiErr == nil || apiErr.Code != http.StatusBadRequest {
+		t.Errorf("expected a bad request for status filter with fuzzy search, got %+v", apiErr)
+	}
+}
@@ -0,0 +1,72 @@
+package products
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"strings"
+
+	"github.com/playwright-community/playwright-go"
+)
+
+// DefaultUserAgents are realistic desktop browsers picked from when no list is configured
+var DefaultUserAgents = []string{
+	"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/129.0.0.0 Safari/537.36",
+	"Mozilla/5.0 (Windows NT 10.0; Win64; x64; rv:131.0) Gecko/20100101 Firefox/131.0",
+	"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.6 Safari/605.1.15",
+	"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/129.0.0.0 Safari/537.36 Edg/129.0.0.0",
+	"Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/129.0.0.0 Safari/537.36",
+}
+
+// configListSeparator splits the user agent and header lists of the config; commas and
+// semicolons can't be used because they appear inside user agents and header values
+const configListSeparator = "|"
+
+// ParseUserAgents reads the "|" separated CRAWLER_USER_AGENTS value.
+// Empty uses DefaultUserAgents and "none" keeps playwright's own user agent (returns nil).
+func ParseUserAgents(value string) []string {
+	value = strings.TrimSpace(value)
+	switch {
+	case value == "":
+		return DefaultUserAgents
+	case strings.EqualFold(value, "none"):
+		return nil
+	}
+
+	var userAgents []string
+	for _, ua := range strings.Split(value, configListSeparator) {
+		if ua = strings.TrimSpace(ua); ua != "" {
+			userAgents = append(userAgents, ua)
+		}
+	}
+	return userAgents
+}
+
+// ParseExtraHeaders reads the "|" separated "Name: value" pairs of CRAWLER_EXTRA_HEADERS
+func ParseExtraHeaders(value string) (map[string]string, error) {
+	headers := make(map[string]string)
+	for _, pair := range strings.Split(value, configListSeparator) {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		name, headerValue, ok := strings.Cut(pair, ":")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid header %q, expected \"Name: value\"", strings.TrimSpace(pair))
+		}
+		headers[name] = strings.TrimSpace(headerValue)
+	}
+	return headers, nil
+}
+
+// contextOptions returns the options of a new browser context: a random user agent from
+// UserAgents and the ExtraHeaders. Without them playwright's defaults are kept.
+func (c *Crawler) contextOptions() playwright.BrowserNewContextOptions {
+	var options playwright.BrowserNewContextOptions
+	if len(c.UserAgents) > 0 {
+		options.UserAgent = playwright.String(c.UserAgents[rand.IntN(len(c.UserAgents))])
+	}
+	if len(c.ExtraHeaders) > 0 {
+		options.ExtraHttpHeaders = c.ExtraHeaders
+	}
+	return options
+}
@@ -0,0 +1,45 @@
+package products
+
+import "testing"
+
+func TestParseUserAgents(t *testing.T) {
+	if got := ParseUserAgents(""); len(got) != len(DefaultUserAgents) {
+		t.Errorf("expected the built-in user agents, got %v", got)
+	}
+	if got := ParseUserAgents("None"); got != nil {
+		t.Errorf("expected playwright's user agent, got %v", got)
+	}
+
+	got := ParseUserAgents("Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) | | Custom/1.0")
+	if len(got) != 2 || got[0] != "Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko)" || got[1] != "Custom/1.0" {
+		t.Errorf("unexpected user agents %q", got)
+	}
+}
+
+func TestParseExtraHeaders(t *testing.T) {
+	headers, err := ParseExtraHeaders("Accept-Language: pt-BR,pt;q=0.9 | DNT: 1|")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(headers) != 2 || headers["Accept-Language"] != "pt-BR,pt;q=0.9" || headers["DNT"] != "1" {
+		t.Errorf("unexpected headers %v", headers)
+	}
+
+	if _, err := ParseExtraHeaders("DNT 1"); err == nil {
+		t.Error("expected an error for a header without colon")
+	}
+}
+
+func TestContextOptions(t *testing.T) {
+	c := &Crawler{}
+	if options := c.contextOptions(); options.UserAgent != nil || options.ExtraHttpHeaders != nil {
+		t.Errorf("expected playwright defaults, got %+v", options)
+	}
+
+	c.UserAgents = []string{"Custom/1.0"}
+	c.ExtraHeaders = map[string]string{"DNT": "1"}
+	options := c.contextOptions()
+	if options.UserAgent == nil || *options.UserAgent != "Custom/1.0" || options.ExtraHttpHeaders["DNT"] != "1" {
+		t.Errorf("unexpected options %+v", options)
+	}
+}
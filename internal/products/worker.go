@@ -2,50 +2,108 @@ package products
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math/rand"
 	"sync"
 	"time"
 
 	repo "github.com/freitasmatheusrn/lifecycle-monitor/internal/database/postgres/sqlc"
+	"github.com/freitasmatheusrn/lifecycle-monitor/pkg/requestid"
 	"github.com/jackc/pgx/v5/pgtype"
 	"go.uber.org/zap"
+	"golang.org/x/time/rate"
 )
 
+// PageCollector defines the interface used by the worker pool to crawl product pages
+type PageCollector interface {
+	Start() error
+	Stop() error
+	Collect(ctx context.Context, productCode string) (*CrawledData, error)
+}
+
+// crawlerStatsReporter is implemented by collectors that expose usage stats (the playwright Crawler)
+type crawlerStatsReporter interface {
+	PageStats() PageStats
+	SelectorStats() map[string]map[string]int64
+}
+
 type WorkerPool struct {
-	crawler     *Crawler
+	crawler     PageCollector
 	repo        repo.Querier
 	logger      *zap.Logger
 	jobs        chan CrawlerJob
 	results     chan WorkerResult
-	numWorkers  int
-	wg          sync.WaitGroup
+	numWorkers  int            // workers desejados; muda com Resize
+	nextWorker  int            // id do proximo worker iniciado
+	retire      chan struct{}  // cada sinal encerra um worker depois do job atual (Resize para baixo)
+	wg          sync.WaitGroup // workers em execucao
+	submitters  sync.WaitGroup // Submit em andamento; podem entregar resultados de jobs abandonados
+	resultsDone chan struct{}  // fechado quando processResults termina de drenar os resultados
 	ctx         context.Context
 	cancel      context.CancelFunc
 	isRunning   bool
 	mu          sync.Mutex
 	syncResults map[string]chan WorkerResult // canais para jobs síncronos
 	syncMu      sync.RWMutex                 // mutex para syncResults
+	minDelay    time.Duration                // espera minima antes de cada coleta em fila
+	maxDelay    time.Duration
+	limiter     *rate.Limiter // limite global de coletas por minuto entre todos os workers (nil desativa)
+	dedupe      bool          // junta jobs de um codigo que ja esta na fila ou sendo coletado
+	jobTimeout  time.Duration // limite de cada job no worker (0 desativa)
+	inFlightMu  sync.Mutex
+	inFlight    map[string][]CrawlerJob // codigo na fila/em coleta -> jobs que aguardam o mesmo resultado
 }
 
 type WorkerResult struct {
-	Job   CrawlerJob
-	Data  *CrawledData
-	Error error
+	Job       CrawlerJob
+	Data      *CrawledData
+	Error     error
+	ErrorKind ErrorKind // classificacao de Error, preenchida na entrega do resultado
 }
 
 type WorkerPoolConfig struct {
 	NumWorkers int
 	QueueSize  int
+
+	// Random wait before each queued crawl, so requests don't look automated. Both zero use
+	// 500ms-2.5s; SubmitAndWait jobs never wait, since a user is waiting for them.
+	MinDelay time.Duration
+	MaxDelay time.Duration
+
+	// Max crawls per minute across all workers; 0 disables the limit
+	RateLimitPerMinute int
+
+	// Coalesce jobs for a code already queued or being crawled: they get the result of that crawl
+	DedupeInFlight bool
+
+	// Max time a job holds its worker, counting the wait for a browser page and every retry;
+	// 0 leaves only the crawler's per-attempt timeout
+	JobTimeout time.Duration
 }
 
-func NewWorkerPool(crawler *Crawler, repo repo.Querier, logger *zap.Logger, config WorkerPoolConfig) *WorkerPool {
+// MaxWorkers is the largest worker count accepted by Resize
+const MaxWorkers = 50
+
+// Espera padrao antes de cada coleta
+const (
+	defaultMinCrawlDelay = 500 * time.Millisecond
+	defaultMaxCrawlDelay = 2500 * time.Millisecond
+)
+
+func NewWorkerPool(crawler PageCollector, repo repo.Querier, logger *zap.Logger, config WorkerPoolConfig) *WorkerPool {
 	if config.NumWorkers <= 0 {
 		config.NumWorkers = 3
 	}
+	config.NumWorkers = min(config.NumWorkers, MaxWorkers)
 	if config.QueueSize <= 0 {
 		config.QueueSize = 100
 	}
+	if config.MinDelay == 0 && config.MaxDelay == 0 {
+		config.MinDelay, config.MaxDelay = defaultMinCrawlDelay, defaultMaxCrawlDelay
+	}
+	config.MinDelay = max(config.MinDelay, 0)
+	config.MaxDelay = max(config.MaxDelay, config.MinDelay)
 
 	ctx, cancel := context.WithCancel(context.Background())
 
@@ -56,12 +114,29 @@ func NewWorkerPool(crawler *Crawler, repo repo.Querier, logger *zap.Logger, conf
 		jobs:        make(chan CrawlerJob, config.QueueSize),
 		results:     make(chan WorkerResult, config.QueueSize),
 		numWorkers:  config.NumWorkers,
+		retire:      make(chan struct{}, MaxWorkers),
 		ctx:         ctx,
 		cancel:      cancel,
 		syncResults: make(map[string]chan WorkerResult),
+		resultsDone: make(chan struct{}),
+		minDelay:    config.MinDelay,
+		maxDelay:    config.MaxDelay,
+		limiter:     newCrawlLimiter(config.RateLimitPerMinute),
+		dedupe:      config.DedupeInFlight,
+		jobTimeout:  max(config.JobTimeout, 0),
+		inFlight:    make(map[string][]CrawlerJob),
 	}
 }
 
+// newCrawlLimiter allows perMinute crawls a minute, evenly spaced, with a burst of one so the
+// workers can't fire together; returns nil when perMinute is 0 or less
+func newCrawlLimiter(perMinute int) *rate.Limiter {
+	if perMinute <= 0 {
+		return nil
+	}
+	return rate.NewLimiter(rate.Every(time.Minute/time.Duration(perMinute)), 1)
+}
+
 func (wp *WorkerPool) Start() error {
 	wp.mu.Lock()
 	defer wp.mu.Unlock()
@@ -75,13 +150,11 @@ func (wp *WorkerPool) Start() error {
 	}
 
 	// Start workers
-	for i := 0; i < wp.numWorkers; i++ {
-		wp.wg.Add(1)
-		go wp.worker(i)
+	for range wp.numWorkers {
+		wp.startWorker()
 	}
 
 	// Start result processor
-	wp.wg.Add(1)
 	go wp.processResults()
 
 	wp.isRunning = true
@@ -90,54 +163,233 @@ func (wp *WorkerPool) Start() error {
 	return nil
 }
 
+// startWorker runs one more worker goroutine; callers hold wp.mu
+func (wp *WorkerPool) startWorker() {
+	wp.wg.Add(1)
+	go wp.worker(wp.nextWorker)
+	wp.nextWorker++
+}
+
+// Resize sets the number of workers while the pool runs (or the number Start runs). Growing
+// starts new workers right away; shrinking lets the surplus workers finish their current job
+// before they exit, so no crawl is interrupted.
+func (wp *WorkerPool) Resize(n int) error {
+	if n < 1 || n > MaxWorkers {
+		return fmt.Errorf("worker count must be between 1 and %d, got %d", MaxWorkers, n)
+	}
+
+	wp.mu.Lock()
+	defer wp.mu.Unlock()
+
+	previous := wp.numWorkers
+	wp.numWorkers = n
+	if wp.isRunning {
+		for diff := n - previous; diff > 0; diff-- {
+			// Cancela primeiro os encerramentos ainda nao atendidos: esses workers continuam
+			select {
+			case <-wp.retire:
+			default:
+				wp.startWorker()
+			}
+		}
+		for diff := previous - n; diff > 0; diff-- {
+			wp.retire <- struct{}{}
+		}
+	}
+
+	wp.logger.Info("worker pool resized", zap.Int("from", previous), zap.Int("to", n))
+	return nil
+}
+
+// Workers returns the number of workers the pool runs, after pending Resize shrinks
+func (wp *WorkerPool) Workers() int {
+	wp.mu.Lock()
+	defer wp.mu.Unlock()
+	return wp.numWorkers
+}
+
+// Stop cancels the pool and waits for the workers, the submitters and the results already produced.
+// The job queue is never closed: a Submit racing with Stop sees the canceled context
+// (or the queue) instead of sending on a closed channel, and jobs left in it are dropped.
 func (wp *WorkerPool) Stop() error {
 	wp.mu.Lock()
 	if !wp.isRunning {
 		wp.mu.Unlock()
 		return nil
 	}
+	wp.isRunning = false
 	wp.mu.Unlock()
 
 	wp.cancel()
-	close(wp.jobs)
 	wp.wg.Wait()
+	// A submitter that gives up fails the jobs coalesced with its own through the results
+	// channel, so it must be done too; no new one starts once isRunning is false
+	wp.submitters.Wait()
+
+	// Only workers and submitters send results, so after they return the processor can drain and finish
 	close(wp.results)
+	<-wp.resultsDone
 
 	if err := wp.crawler.Stop(); err != nil {
 		return fmt.Errorf("failed to stop crawler: %w", err)
 	}
 
-	wp.mu.Lock()
-	wp.isRunning = false
-	wp.mu.Unlock()
-
 	wp.logger.Info("worker pool stopped")
 	return nil
 }
 
+// WorkerPoolStats is a point-in-time view of the worker pool and its crawler
+type WorkerPoolStats struct {
+	Workers    int                         `json:"workers"`
+	QueuedJobs int                         `json:"queued_jobs"`
+	QueueSize  int                         `json:"queue_size"`
+	Pages      *PageStats                  `json:"pages,omitempty"`
+	Selectors  map[string]map[string]int64 `json:"selectors,omitempty"`
+}
+
+// WorkerCountInput sets the number of crawl workers (POST /admin/workers)
+type WorkerCountInput struct {
+	Workers int `json:"workers"`
+}
+
+type WorkerCountOutput struct {
+	Workers    int `json:"workers"`
+	MaxWorkers int `json:"max_workers"`
+}
+
+// Stats reports queue usage and, when the crawler supports it, open pages and selector matches
+func (wp *WorkerPool) Stats() WorkerPoolStats {
+	stats := WorkerPoolStats{
+		Workers:    wp.Workers(),
+		QueuedJobs: len(wp.jobs),
+		QueueSize:  cap(wp.jobs),
+	}
+
+	if reporter, ok := wp.crawler.(crawlerStatsReporter); ok {
+		pages := reporter.PageStats()
+		stats.Pages = &pages
+		stats.Selectors = reporter.SelectorStats()
+	}
+
+	return stats
+}
+
 func (wp *WorkerPool) Submit(job CrawlerJob) error {
+	if !wp.beginSubmit() {
+		return fmt.Errorf("worker pool is not running")
+	}
+	defer wp.submitters.Done()
+
+	if wp.joinInFlight(job) {
+		return nil
+	}
+
+	var err error
+	select {
+	case wp.jobs <- job:
+		wp.logger.Debug("job submitted", zap.String("code", job.ProductCode))
+		return nil
+	case <-wp.ctx.Done():
+		err = fmt.Errorf("worker pool is shutting down")
+	default:
+		err = fmt.Errorf("job queue is full")
+	}
+	wp.abandonInFlight(job.ProductCode, err)
+	return err
+}
+
+// beginSubmit registers a submitter with the running pool, so Stop waits for it before closing
+// the results channel. Returns false once the pool is stopped; otherwise call submitters.Done.
+func (wp *WorkerPool) beginSubmit() bool {
 	wp.mu.Lock()
+	defer wp.mu.Unlock()
+
 	if !wp.isRunning {
-		wp.mu.Unlock()
+		return false
+	}
+	wp.submitters.Add(1)
+	return true
+}
+
+// submitWait is like Submit but waits for space in the queue instead of failing when it is full
+func (wp *WorkerPool) submitWait(ctx context.Context, job CrawlerJob) error {
+	if !wp.beginSubmit() {
 		return fmt.Errorf("worker pool is not running")
 	}
-	wp.mu.Unlock()
+	defer wp.submitters.Done()
+
+	if wp.joinInFlight(job) {
+		return nil
+	}
 
+	var err error
 	select {
 	case wp.jobs <- job:
 		wp.logger.Debug("job submitted", zap.String("code", job.ProductCode))
 		return nil
 	case <-wp.ctx.Done():
-		return fmt.Errorf("worker pool is shutting down")
-	default:
-		return fmt.Errorf("job queue is full")
+		err = fmt.Errorf("worker pool is shutting down")
+	case <-ctx.Done():
+		err = ctx.Err()
 	}
+	wp.abandonInFlight(job.ProductCode, err)
+	return err
+}
+
+// joinInFlight registers job as the crawl of its code, or, when deduplication is on and the code
+// is already queued or being crawled, adds it to the jobs waiting for that crawl and returns true
+func (wp *WorkerPool) joinInFlight(job CrawlerJob) bool {
+	if !wp.dedupe {
+		return false
+	}
+
+	wp.inFlightMu.Lock()
+	defer wp.inFlightMu.Unlock()
+
+	if followers, ok := wp.inFlight[job.ProductCode]; ok {
+		wp.inFlight[job.ProductCode] = append(followers, job)
+		wp.logger.Debug("job coalesced with in-flight crawl", zap.String("code", job.ProductCode))
+		return true
+	}
+	wp.inFlight[job.ProductCode] = nil
+	return false
+}
+
+// finishInFlight clears the in-flight entry of code and returns the jobs that joined it
+func (wp *WorkerPool) finishInFlight(code string) []CrawlerJob {
+	if !wp.dedupe {
+		return nil
+	}
+
+	wp.inFlightMu.Lock()
+	defer wp.inFlightMu.Unlock()
+
+	followers := wp.inFlight[code]
+	delete(wp.inFlight, code)
+	return followers
+}
+
+// abandonInFlight clears the entry of a job that couldn't be queued and fails the jobs that joined it
+func (wp *WorkerPool) abandonInFlight(code string, err error) {
+	for _, follower := range wp.finishInFlight(code) {
+		wp.deliverResult(WorkerResult{Job: follower, Error: err})
+	}
+}
+
+// CollectNow crawls a product right away on the calling goroutine, skipping the job queue.
+// Nothing is persisted; used by the crawler self-test.
+func (wp *WorkerPool) CollectNow(ctx context.Context, productCode string) (*CrawledData, error) {
+	return wp.crawler.Collect(ctx, productCode)
 }
 
 func (wp *WorkerPool) SubmitAndWait(ctx context.Context, job CrawlerJob) (*CrawledData, error) {
 	// Gera um ID único para este job
 	jobID := fmt.Sprintf("%s-%d", job.ProductCode, time.Now().UnixNano())
 	job.jobID = jobID
+	job.skipDelay = true
+	if job.RequestID == "" {
+		job.RequestID = requestid.FromContext(ctx)
+	}
 
 	// Cria e registra o canal de resultado
 	resultChan := make(chan WorkerResult, 1)
@@ -172,6 +424,7 @@ func (wp *WorkerPool) SubmitAndWait(ctx context.Context, job CrawlerJob) (*Crawl
 // SubmitBatch submete múltiplos jobs e retorna um canal que emite resultados conforme cada job termina.
 // O canal é fechado automaticamente quando todos os jobs forem processados.
 // Os resultados podem chegar fora de ordem.
+// Com a fila cheia, espera por espaco; so retorna erro se nenhum job puder ser submetido.
 func (wp *WorkerPool) SubmitBatch(ctx context.Context, jobs []CrawlerJob) (<-chan WorkerResult, error) {
 	if len(jobs) == 0 {
 		ch := make(chan WorkerResult)
@@ -190,52 +443,84 @@ func (wp *WorkerPool) SubmitBatch(ctx context.Context, jobs []CrawlerJob) (<-cha
 		jobID := fmt.Sprintf("%s-%d-%d", jobs[i].ProductCode, time.Now().UnixNano(), i)
 		jobs[i].jobID = jobID
 		jobIDs[i] = jobID
+		if jobs[i].RequestID == "" {
+			jobs[i].RequestID = requestid.FromContext(ctx)
+		}
 
 		wp.syncMu.Lock()
 		wp.syncResults[jobID] = internalChan
 		wp.syncMu.Unlock()
 	}
 
-	// Submete todos os jobs
-	submitted := 0
-	for _, job := range jobs {
-		if err := wp.Submit(job); err != nil {
-			// Limpa os jobs já registrados em caso de erro
-			wp.syncMu.Lock()
-			for _, id := range jobIDs[:submitted] {
-				delete(wp.syncResults, id)
-			}
-			wp.syncMu.Unlock()
+	// Submete todos os jobs, esperando espaco na fila quando ela estiver cheia.
+	// Se a submissao falhar no meio (contexto cancelado ou pool parando), os jobs ja
+	// submetidos seguem normalmente e os restantes viram resultados com erro.
+	for i, job := range jobs {
+		err := wp.submitWait(ctx, job)
+		if err == nil {
+			continue
+		}
+
+		wp.syncMu.Lock()
+		for _, id := range jobIDs[i:] {
+			delete(wp.syncResults, id)
+		}
+		wp.syncMu.Unlock()
+
+		if i == 0 {
 			close(outputChan)
 			return nil, fmt.Errorf("failed to submit job %s: %w", job.ProductCode, err)
 		}
-		submitted++
+
+		wp.logger.Warn("batch partially submitted",
+			zap.Int("submitted", i),
+			zap.Int("total", len(jobs)),
+			zap.Error(err),
+		)
+		for _, pending := range jobs[i:] {
+			internalChan <- WorkerResult{Job: pending, Error: fmt.Errorf("job not submitted: %w", err)}
+		}
+		break
 	}
 
 	// Goroutine para repassar resultados e limpar quando todos terminarem
-	go func() {
-		defer close(outputChan)
-		defer func() {
-			wp.syncMu.Lock()
-			for _, id := range jobIDs {
-				delete(wp.syncResults, id)
-			}
-			wp.syncMu.Unlock()
-		}()
+	go wp.forwardBatchResults(ctx, internalChan, outputChan, jobIDs)
 
-		received := 0
-		for received < len(jobs) {
-			select {
-			case result := <-internalChan:
-				outputChan <- result
-				received++
-			case <-ctx.Done():
-				return
-			}
+	return outputChan, nil
+}
+
+// forwardBatchResults passes the results of a batch to the caller and closes output once every
+// job answered or ctx is done. On ctx the jobs are unregistered first, so results finishing later
+// become orphans saved by the pool, and the ones that already arrived still reach the caller;
+// output has room for every job.
+func (wp *WorkerPool) forwardBatchResults(ctx context.Context, internal <-chan WorkerResult, output chan<- WorkerResult, jobIDs []string) {
+	defer close(output)
+
+	unregister := func() {
+		wp.syncMu.Lock()
+		for _, id := range jobIDs {
+			delete(wp.syncResults, id)
 		}
-	}()
+		wp.syncMu.Unlock()
+	}
 
-	return outputChan, nil
+	for received := 0; received < len(jobIDs); received++ {
+		select {
+		case result := <-internal:
+			output <- result
+		case <-ctx.Done():
+			unregister()
+			for {
+				select {
+				case result := <-internal:
+					output <- result
+				default:
+					return
+				}
+			}
+		}
+	}
+	unregister()
 }
 
 func (wp *WorkerPool) worker(id int) {
@@ -245,22 +530,39 @@ func (wp *WorkerPool) worker(id int) {
 
 	for {
 		select {
-		case job, ok := <-wp.jobs:
-			if !ok {
+		case job := <-wp.jobs:
+			if wp.ctx.Err() != nil {
+				// Stop em andamento: o job fica sem resultado, como os que restaram na fila
+				wp.finishInFlight(job.ProductCode)
 				wp.logger.Debug("worker stopping", zap.Int("worker_id", id))
 				return
 			}
 
 			// Add random delay to avoid detection
-			delay := time.Duration(rand.Intn(2000)+500) * time.Millisecond
-			time.Sleep(delay)
+			if delay := wp.crawlDelay(job); delay > 0 {
+				select {
+				case <-time.After(delay):
+				case <-wp.ctx.Done():
+				}
+			}
+
+			// Shared by every worker; a pool shutdown ends the wait
+			if wp.limiter != nil {
+				if err := wp.limiter.Wait(wp.ctx); err != nil {
+					err = fmt.Errorf("worker pool stopped: %w", err)
+					wp.deliverResult(WorkerResult{Job: job, Error: err})
+					wp.abandonInFlight(job.ProductCode, err)
+					continue
+				}
+			}
 
 			wp.logger.Debug("processing job",
 				zap.Int("worker_id", id),
 				zap.String("code", job.ProductCode),
+				requestid.Field(job.RequestID),
 			)
 
-			data, err := wp.crawler.Collect(job.ProductCode)
+			data, err := wp.collect(job)
 
 			result := WorkerResult{
 				Job:   job,
@@ -268,18 +570,17 @@ func (wp *WorkerPool) worker(id int) {
 				Error: err,
 			}
 
-			// Se é um job síncrono (tem jobID), envia para o canal específico
-			if job.jobID != "" {
-				wp.syncMu.RLock()
-				if ch, exists := wp.syncResults[job.jobID]; exists {
-					ch <- result
-				}
-				wp.syncMu.RUnlock()
-			} else {
-				// Job assíncrono normal, envia para o canal de resultados
-				wp.results <- result
+			wp.deliverResult(result)
+
+			// Jobs do mesmo codigo que chegaram durante a coleta recebem o mesmo resultado
+			for _, follower := range wp.finishInFlight(job.ProductCode) {
+				wp.deliverResult(WorkerResult{Job: follower, Data: data, Error: err})
 			}
 
+		case <-wp.retire:
+			wp.logger.Debug("worker retired", zap.Int("worker_id", id))
+			return
+
 		case <-wp.ctx.Done():
 			wp.logger.Debug("worker cancelled", zap.Int("worker_id", id))
 			return
@@ -287,8 +588,108 @@ func (wp *WorkerPool) worker(id int) {
 	}
 }
 
+// collect crawls the job's product within the pool's job timeout.
+// The job context is canceled instead of given a deadline: the crawler only applies its own
+// per-attempt timeout to contexts without one, and that must keep working inside the job.
+func (wp *WorkerPool) collect(job CrawlerJob) (*CrawledData, error) {
+	// The crawl runs on the pool context; the request id goes along for the crawler logs
+	jobCtx := requestid.WithContext(wp.ctx, job.RequestID)
+	if wp.jobTimeout <= 0 {
+		return wp.crawler.Collect(jobCtx, job.ProductCode)
+	}
+
+	ctx, cancel := context.WithCancelCause(jobCtx)
+	timer := time.AfterFunc(wp.jobTimeout, func() { cancel(ErrJobTimeout) })
+	defer func() {
+		timer.Stop()
+		cancel(nil)
+	}()
+
+	data, err := wp.crawler.Collect(ctx, job.ProductCode)
+	if err != nil && errors.Is(context.Cause(ctx), ErrJobTimeout) {
+		err = fmt.Errorf("%w after %s: %w", ErrJobTimeout, wp.jobTimeout, err)
+	}
+	return data, err
+}
+
+// deliverResult hands the result of a job to whoever is waiting for it
+func (wp *WorkerPool) deliverResult(result WorkerResult) {
+	result.ErrorKind = classifyCrawlError(result.Error)
+
+	// Se é um job síncrono (tem jobID), envia para o canal específico
+	if result.Job.jobID != "" {
+		if !wp.deliverSyncResult(result) {
+			wp.handleOrphanedResult(result)
+		}
+		return
+	}
+	// Job assíncrono normal, envia para o canal de resultados
+	wp.results <- result
+}
+
+// crawlDelay picks the wait before crawling job, between the configured min and max delays
+func (wp *WorkerPool) crawlDelay(job CrawlerJob) time.Duration {
+	if job.skipDelay {
+		return 0
+	}
+	if wp.maxDelay <= wp.minDelay {
+		return wp.minDelay
+	}
+	return wp.minDelay + time.Duration(rand.Int63n(int64(wp.maxDelay-wp.minDelay)))
+}
+
+// deliverSyncResult sends the result to the channel registered by SubmitAndWait/SubmitBatch.
+// Returns false if the caller already stopped waiting (timeout or cancellation).
+func (wp *WorkerPool) deliverSyncResult(result WorkerResult) bool {
+	wp.syncMu.RLock()
+	defer wp.syncMu.RUnlock()
+
+	ch, exists := wp.syncResults[result.Job.jobID]
+	if !exists {
+		return false
+	}
+
+	// Os canais síncronos têm buffer suficiente para todos os jobs registrados,
+	// então o envio nunca deveria bloquear enquanto seguramos o lock
+	select {
+	case ch <- result:
+		return true
+	default:
+		return false
+	}
+}
+
+// handleOrphanedResult handles results of sync jobs whose caller is no longer waiting.
+// Successful crawls for existing products are persisted through the async path so the
+// work isn't lost; everything else is logged explicitly.
+func (wp *WorkerPool) handleOrphanedResult(result WorkerResult) {
+	if result.Error != nil || !result.Job.ProductID.Valid {
+		wp.logger.Warn("dropping result of sync job after caller stopped waiting",
+			zap.String("code", result.Job.ProductCode),
+			requestid.Field(result.Job.RequestID),
+			zap.Bool("has_product_id", result.Job.ProductID.Valid),
+			zap.NamedError("crawl_error", result.Error),
+		)
+		return
+	}
+
+	wp.logger.Warn("sync job finished after caller stopped waiting, saving result asynchronously",
+		zap.String("code", result.Job.ProductCode),
+		requestid.Field(result.Job.RequestID),
+	)
+
+	select {
+	case wp.results <- result:
+	case <-wp.ctx.Done():
+		wp.logger.Warn("worker pool shutting down, orphaned result discarded",
+			zap.String("code", result.Job.ProductCode),
+			requestid.Field(result.Job.RequestID),
+		)
+	}
+}
+
 func (wp *WorkerPool) processResults() {
-	defer wp.wg.Done()
+	defer close(wp.resultsDone)
 
 	for {
 		select {
@@ -297,9 +698,19 @@ func (wp *WorkerPool) processResults() {
 				return
 			}
 
+			if errors.Is(result.Error, ErrProductNotFound) {
+				// Codigo invalido, nao adianta tentar de novo
+				wp.logger.Warn("product not found on the site",
+					zap.String("code", result.Job.ProductCode),
+					requestid.Field(result.Job.RequestID),
+					zap.Error(result.Error),
+				)
+				continue
+			}
 			if result.Error != nil {
 				wp.logger.Error("crawling failed",
 					zap.String("code", result.Job.ProductCode),
+					requestid.Field(result.Job.RequestID),
 					zap.Error(result.Error),
 				)
 				continue
@@ -309,6 +720,7 @@ func (wp *WorkerPool) processResults() {
 			if err := wp.saveSnapshot(result.Job, result.Data); err != nil {
 				wp.logger.Error("failed to save snapshot",
 					zap.String("code", result.Job.ProductCode),
+					requestid.Field(result.Job.RequestID),
 					zap.Error(err),
 				)
 				continue
@@ -316,6 +728,7 @@ func (wp *WorkerPool) processResults() {
 
 			wp.logger.Info("snapshot saved",
 				zap.String("code", result.Job.ProductCode),
+				requestid.Field(result.Job.RequestID),
 				zap.String("description", result.Data.Description),
 				zap.String("status", result.Data.Status),
 			)
@@ -352,6 +765,8 @@ func (wp *WorkerPool) saveSnapshot(job CrawlerJob, data *CrawledData) error {
 		Description: data.Description,
 		Status:      status,
 		RawHtml:     rawHTML,
+		Source:      job.snapshotSource(),
+		RunID:       job.RunID,
 	})
 	if err != nil {
 		return err
@@ -382,6 +797,8 @@ func (wp *WorkerPool) saveSnapshot(job CrawlerJob, data *CrawledData) error {
 		}
 	}
 
+	saveProductDetails(ctx, wp.repo, wp.logger, job.ProductCode, data)
+
 	return nil
 }
 
@@ -397,6 +814,7 @@ func (wp *WorkerPool) CollectAll(ctx context.Context) error {
 			ProductID:   product.ID,
 			ProductCode: product.Code,
 			ProductURL:  product.Url,
+			Source:      SnapshotSourceCollectAll,
 		}
 
 		if err := wp.Submit(job); err != nil {
@@ -0,0 +1,698 @@
+package products
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	repo "github.com/freitasmatheusrn/lifecycle-monitor/internal/database/postgres/sqlc"
+	"github.com/freitasmatheusrn/lifecycle-monitor/pkg/requestid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+)
+
+// MockPageCollector implements PageCollector for testing
+type MockPageCollector struct {
+	data    *CrawledData
+	err     error
+	release chan struct{} // when set, Collect blocks until it is closed
+	calls   atomic.Int32
+}
+
+func (m *MockPageCollector) Start() error { return nil }
+func (m *MockPageCollector) Stop() error  { return nil }
+
+func (m *MockPageCollector) Collect(ctx context.Context, productCode string) (*CrawledData, error) {
+	m.calls.Add(1)
+	if m.release != nil {
+		<-m.release
+	}
+	return m.data, m.err
+}
+
+// MockQuerier records the snapshots saved by the worker pool.
+// Only the methods used by saveSnapshot are implemented.
+type MockQuerier struct {
+	repo.Querier
+	snapshots []repo.CreateSnapshotParams
+	mu        sync.Mutex
+}
+
+func (m *MockQuerier) CreateSnapshot(ctx context.Context, arg repo.CreateSnapshotParams) (repo.ProductSnapshot, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.snapshots = append(m.snapshots, arg)
+	return repo.ProductSnapshot{ProductID: arg.ProductID}, nil
+}
+
+func (m *MockQuerier) UpdateProductLifecycleStatus(ctx context.Context, arg repo.UpdateProductLifecycleStatusParams) error {
+	return nil
+}
+
+func (m *MockQuerier) snapshotCount() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.snapshots)
+}
+
+func newTestWorkerPool(t *testing.T, collector PageCollector, querier repo.Querier) *WorkerPool {
+	t.Helper()
+
+	wp := NewWorkerPool(collector, querier, zap.NewNop(), WorkerPoolConfig{NumWorkers: 1, QueueSize: 10})
+	if err := wp.Start(); err != nil {
+		t.Fatalf("failed to start worker pool: %v", err)
+	}
+	t.Cleanup(wp.cancel)
+
+	return wp
+}
+
+func TestSubmitAndWait_TimeoutThenLateCompletion_SavesSnapshot(t *testing.T) {
+	release := make(chan struct{})
+	collector := &MockPageCollector{
+		data:    &CrawledData{Description: "Late Product", Status: "Active Product"},
+		release: release,
+	}
+	querier := &MockQuerier{}
+	wp := newTestWorkerPool(t, collector, querier)
+
+	productID := pgtype.UUID{Bytes: [16]byte{1}, Valid: true}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := wp.SubmitAndWait(ctx, CrawlerJob{ProductID: productID, ProductCode: "PROD-001"})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+
+	// The worker finishes after the caller gave up
+	close(release)
+
+	deadline := time.Now().Add(5 * time.Second)
+	for querier.snapshotCount() == 0 && time.Now().Before(deadline) {
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	if count := querier.snapshotCount(); count != 1 {
+		t.Fatalf("expected late result to be saved once, got %d snapshots", count)
+	}
+
+	querier.mu.Lock()
+	saved := querier.snapshots[0]
+	querier.mu.Unlock()
+
+	if saved.ProductID != productID {
+		t.Errorf("snapshot saved for wrong product")
+	}
+	if saved.Description != "Late Product" {
+		t.Errorf("unexpected snapshot description: %s", saved.Description)
+	}
+
+	wp.syncMu.RLock()
+	pending := len(wp.syncResults)
+	wp.syncMu.RUnlock()
+	if pending != 0 {
+		t.Errorf("expected sync results map to be empty, got %d entries", pending)
+	}
+}
+
+func TestSubmitAndWait_TimeoutThenLateCompletion_WithoutProductID(t *testing.T) {
+	release := make(chan struct{})
+	collector := &MockPageCollector{
+		data:    &CrawledData{Description: "New Product"},
+		release: release,
+	}
+	querier := &MockQuerier{}
+	wp := newTestWorkerPool(t, collector, querier)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	// Jobs for products that don't exist yet (add flow) have no product ID to save against
+	_, err := wp.SubmitAndWait(ctx, CrawlerJob{ProductCode: "PROD-002"})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+
+	close(release)
+
+	// Give the worker time to pick up and finish the job
+	time.Sleep(3 * time.Second)
+
+	if count := querier.snapshotCount(); count != 0 {
+		t.Errorf("expected no snapshot for job without product ID, got %d", count)
+	}
+}
+
+func TestSubmitBatch_QueueFullMidway_WaitsForSpace(t *testing.T) {
+	release := make(chan struct{})
+	collector := &MockPageCollector{
+		data:    &CrawledData{Description: "Batch Product", Status: "Active Product"},
+		release: release,
+	}
+
+	// One worker stuck on the first job and room for one more: the third job finds the queue full.
+	// Kept small because the worker waits between jobs.
+	wp := NewWorkerPool(collector, &MockQuerier{}, zap.NewNop(), WorkerPoolConfig{NumWorkers: 1, QueueSize: 1})
+	if err := wp.Start(); err != nil {
+		t.Fatalf("failed to start worker pool: %v", err)
+	}
+	t.Cleanup(wp.cancel)
+
+	jobs := make([]CrawlerJob, 3)
+	for i := range jobs {
+		jobs[i] = CrawlerJob{ProductCode: fmt.Sprintf("PROD-%03d", i)}
+	}
+
+	type submitResult struct {
+		results <-chan WorkerResult
+		err     error
+	}
+	submitted := make(chan submitResult, 1)
+	go func() {
+		results, err := wp.SubmitBatch(context.Background(), jobs)
+		submitted <- submitResult{results, err}
+	}()
+
+	select {
+	case res := <-submitted:
+		t.Fatalf("expected SubmitBatch to wait for queue space, returned early (err: %v)", res.err)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	close(release)
+
+	var res submitResult
+	select {
+	case res = <-submitted:
+	case <-time.After(5 * time.Second):
+		t.Fatal("SubmitBatch did not return after the queue drained")
+	}
+	if res.err != nil {
+		t.Fatalf("unexpected error: %v", res.err)
+	}
+
+	received := 0
+	for result := range res.results {
+		if result.Error != nil {
+			t.Errorf("unexpected error for %s: %v", result.Job.ProductCode, result.Error)
+		}
+		received++
+	}
+	if received != len(jobs) {
+		t.Errorf("expected %d results, got %d", len(jobs), received)
+	}
+
+	wp.syncMu.RLock()
+	pending := len(wp.syncResults)
+	wp.syncMu.RUnlock()
+	if pending != 0 {
+		t.Errorf("expected sync results map to be empty, got %d entries", pending)
+	}
+}
+
+func TestSubmitBatch_ContextCanceledMidway_KeepsSubmittedJobs(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	collector := &MockPageCollector{data: &CrawledData{}, release: release}
+
+	wp := NewWorkerPool(collector, &MockQuerier{}, zap.NewNop(), WorkerPoolConfig{NumWorkers: 1, QueueSize: 1})
+	if err := wp.Start(); err != nil {
+		t.Fatalf("failed to start worker pool: %v", err)
+	}
+	t.Cleanup(wp.cancel)
+
+	jobs := []CrawlerJob{{ProductCode: "PROD-A"}, {ProductCode: "PROD-B"}, {ProductCode: "PROD-C"}, {ProductCode: "PROD-D"}}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	results, err := wp.SubmitBatch(ctx, jobs)
+	if err != nil {
+		t.Fatalf("expected partial submission to return results, got %v", err)
+	}
+	for range results {
+		// O canal fecha com o contexto cancelado
+	}
+
+	// Neither the submitted jobs (cleaned up by the forwarder) nor the unsubmitted ones are left behind
+	wp.syncMu.RLock()
+	pending := len(wp.syncResults)
+	wp.syncMu.RUnlock()
+	if pending != 0 {
+		t.Errorf("expected sync results map to be empty, got %d entries", pending)
+	}
+}
+
+func TestForwardBatchResults_DeadlineWithBufferedResults(t *testing.T) {
+	wp := NewWorkerPool(&MockPageCollector{}, &MockQuerier{}, zap.NewNop(), WorkerPoolConfig{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	for range 20 {
+		jobIDs := []string{"PROD-A-1", "PROD-B-2", "PROD-C-3", "PROD-D-4"}
+		internal := make(chan WorkerResult, len(jobIDs))
+		output := make(chan WorkerResult, len(jobIDs))
+		wp.syncMu.Lock()
+		for _, id := range jobIDs {
+			wp.syncResults[id] = internal
+		}
+		wp.syncMu.Unlock()
+
+		// Three crawls finished just before the deadline; the fourth is still running
+		for _, code := range []string{"PROD-A", "PROD-B", "PROD-C"} {
+			internal <- WorkerResult{Job: CrawlerJob{ProductCode: code}, Data: &CrawledData{}}
+		}
+
+		wp.forwardBatchResults(ctx, internal, output, jobIDs)
+
+		forwarded := 0
+		for range output {
+			forwarded++
+		}
+		if forwarded != 3 {
+			t.Fatalf("expected the 3 buffered results to reach the caller, got %d", forwarded)
+		}
+
+		// The running crawl now finds no caller and goes to the orphan path
+		wp.syncMu.RLock()
+		pending := len(wp.syncResults)
+		wp.syncMu.RUnlock()
+		if pending != 0 {
+			t.Fatalf("expected the batch to be unregistered, got %d entries", pending)
+		}
+	}
+}
+
+func TestCrawlDelay(t *testing.T) {
+	defaults := NewWorkerPool(&MockPageCollector{}, &MockQuerier{}, zap.NewNop(), WorkerPoolConfig{})
+	for range 50 {
+		if delay := defaults.crawlDelay(CrawlerJob{}); delay < 500*time.Millisecond || delay >= 2500*time.Millisecond {
+			t.Fatalf("expected the default 500ms-2.5s range, got %v", delay)
+		}
+	}
+	if delay := defaults.crawlDelay(CrawlerJob{skipDelay: true}); delay != 0 {
+		t.Errorf("expected sync jobs not to wait, got %v", delay)
+	}
+
+	fixed := NewWorkerPool(&MockPageCollector{}, &MockQuerier{}, zap.NewNop(), WorkerPoolConfig{MinDelay: time.Second, MaxDelay: time.Millisecond})
+	if delay := fixed.crawlDelay(CrawlerJob{}); delay != time.Second {
+		t.Errorf("expected a max below the min to use the min, got %v", delay)
+	}
+}
+
+func TestWorkerPool_RateLimitStopsWithPool(t *testing.T) {
+	collector := &MockPageCollector{data: &CrawledData{Description: "Limited"}}
+	wp := NewWorkerPool(collector, &MockQuerier{}, zap.NewNop(), WorkerPoolConfig{
+		NumWorkers:         2,
+		MinDelay:           time.Nanosecond,
+		MaxDelay:           time.Nanosecond,
+		RateLimitPerMinute: 1,
+	})
+	if err := wp.Start(); err != nil {
+		t.Fatalf("failed to start worker pool: %v", err)
+	}
+	t.Cleanup(wp.cancel)
+
+	results, err := wp.SubmitBatch(context.Background(), []CrawlerJob{{ProductCode: "PROD-001"}, {ProductCode: "PROD-002"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	select {
+	case first := <-results:
+		if first.Error != nil {
+			t.Fatalf("expected the first crawl to run right away, got %v", first.Error)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("first crawl didn't run")
+	}
+
+	select {
+	case second := <-results:
+		t.Fatalf("expected the second crawl to wait for the limiter, got %+v", second)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	wp.cancel()
+	select {
+	case second := <-results:
+		if second.Error == nil {
+			t.Error("expected the waiting crawl to fail once the pool stops")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("limiter wait didn't end with the pool")
+	}
+}
+
+func TestWorkerPool_SubmitDuringStop(t *testing.T) {
+	for range 20 {
+		wp := NewWorkerPool(&MockPageCollector{data: &CrawledData{}}, &MockQuerier{}, zap.NewNop(), WorkerPoolConfig{
+			NumWorkers: 2,
+			QueueSize:  4,
+			MinDelay:   time.Nanosecond,
+			MaxDelay:   time.Nanosecond,
+		})
+		if err := wp.Start(); err != nil {
+			t.Fatalf("failed to start worker pool: %v", err)
+		}
+
+		var wg sync.WaitGroup
+		stop := make(chan struct{})
+		for i := range 8 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for {
+					select {
+					case <-stop:
+						return
+					default:
+						// Errors are expected once Stop runs; a send on the closed queue would panic
+						_ = wp.Submit(CrawlerJob{ProductCode: fmt.Sprintf("PROD-%03d", i)})
+						_, _ = wp.SubmitBatch(context.Background(), []CrawlerJob{{ProductCode: "BATCH"}})
+					}
+				}
+			}()
+		}
+
+		time.Sleep(5 * time.Millisecond)
+		stopped := make(chan error, 1)
+		go func() { stopped <- wp.Stop() }()
+
+		select {
+		case err := <-stopped:
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("Stop did not return while jobs were being submitted")
+		}
+		close(stop)
+		wg.Wait()
+	}
+}
+
+func TestWorkerPool_DedupeInFlight(t *testing.T) {
+	for _, dedupe := range []bool{true, false} {
+		release := make(chan struct{})
+		collector := &MockPageCollector{
+			data:    &CrawledData{Description: "Shared Product", Status: "Active Product"},
+			release: release,
+		}
+		querier := &MockQuerier{}
+		wp := NewWorkerPool(collector, querier, zap.NewNop(), WorkerPoolConfig{
+			NumWorkers:     1,
+			QueueSize:      10,
+			MinDelay:       time.Millisecond,
+			MaxDelay:       time.Millisecond,
+			DedupeInFlight: dedupe,
+		})
+		if err := wp.Start(); err != nil {
+			t.Fatalf("failed to start worker pool: %v", err)
+		}
+
+		// Mesmo codigo em duas areas (import e scheduler) e um codigo diferente
+		jobs := []CrawlerJob{
+			{ProductID: pgtype.UUID{Bytes: [16]byte{1}, Valid: true}, ProductCode: "PROD-001"},
+			{ProductID: pgtype.UUID{Bytes: [16]byte{2}, Valid: true}, ProductCode: "PROD-001"},
+			{ProductID: pgtype.UUID{Bytes: [16]byte{3}, Valid: true}, ProductCode: "PROD-002"},
+		}
+		results, err := wp.SubmitBatch(context.Background(), jobs)
+		if err != nil {
+			t.Fatalf("dedupe=%v: submit failed: %v", dedupe, err)
+		}
+		close(release)
+
+		received := map[[16]byte]bool{}
+		for result := range results {
+			if result.Error != nil || result.Data == nil || result.Data.Description != "Shared Product" {
+				t.Errorf("dedupe=%v: unexpected result for %s: %+v", dedupe, result.Job.ProductCode, result)
+			}
+			received[result.Job.ProductID.Bytes] = true
+		}
+		if len(received) != len(jobs) {
+			t.Errorf("dedupe=%v: expected a result per job, got %d", dedupe, len(received))
+		}
+
+		expectedCalls := int32(3)
+		if dedupe {
+			expectedCalls = 2
+		}
+		if calls := collector.calls.Load(); calls != expectedCalls {
+			t.Errorf("dedupe=%v: expected %d crawls, got %d", dedupe, expectedCalls, calls)
+		}
+
+		wp.inFlightMu.Lock()
+		if len(wp.inFlight) != 0 {
+			t.Errorf("dedupe=%v: in-flight entries not cleared: %v", dedupe, wp.inFlight)
+		}
+		wp.inFlightMu.Unlock()
+
+		wp.Stop()
+	}
+}
+
+// holdingQuerier blocks CreateSnapshot until hold is closed, backing up the results channel,
+// and then keeps each save slow so the results drain after the workers are gone
+type holdingQuerier struct {
+	MockQuerier
+	hold    chan struct{}
+	entered atomic.Int32
+}
+
+func (q *holdingQuerier) CreateSnapshot(ctx context.Context, arg repo.CreateSnapshotParams) (repo.ProductSnapshot, error) {
+	q.entered.Add(1)
+	<-q.hold
+	time.Sleep(20 * time.Millisecond)
+	return q.MockQuerier.CreateSnapshot(ctx, arg)
+}
+
+func TestWorkerPool_StopWaitsForAbandonedFollowers(t *testing.T) {
+	waitFor := func(cond func() bool) {
+		t.Helper()
+		deadline := time.Now().Add(5 * time.Second)
+		for !cond() {
+			if time.Now().After(deadline) {
+				t.Fatal("timed out waiting for the pool")
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+
+	for range 20 {
+		querier := &holdingQuerier{hold: make(chan struct{})}
+		wp := NewWorkerPool(&MockPageCollector{data: &CrawledData{}}, querier, zap.NewNop(), WorkerPoolConfig{
+			NumWorkers:     1,
+			QueueSize:      1,
+			MinDelay:       time.Nanosecond,
+			MaxDelay:       time.Nanosecond,
+			DedupeInFlight: true,
+		})
+		if err := wp.Start(); err != nil {
+			t.Fatalf("failed to start worker pool: %v", err)
+		}
+
+		// The processor is stuck saving the first result, the second fills the results channel,
+		// the worker blocks delivering the third and the fourth fills the job queue
+		for i, code := range []string{"PROD-A", "PROD-B", "PROD-C", "PROD-D"} {
+			if err := wp.Submit(CrawlerJob{ProductCode: code}); err != nil {
+				t.Fatalf("unexpected error submitting %s: %v", code, err)
+			}
+			switch i {
+			case 0:
+				waitFor(func() bool { return querier.entered.Load() == 1 })
+			case 1:
+				waitFor(func() bool { return len(wp.results) == 1 })
+			case 2:
+				waitFor(func() bool { return len(wp.jobs) == 0 })
+			}
+		}
+
+		// A submitter waits for queue space and an async job joins its crawl
+		go wp.submitWait(context.Background(), CrawlerJob{ProductCode: "PROD-X"})
+		waitFor(func() bool {
+			wp.inFlightMu.Lock()
+			defer wp.inFlightMu.Unlock()
+			_, waiting := wp.inFlight["PROD-X"]
+			return waiting
+		})
+		if err := wp.Submit(CrawlerJob{ProductCode: "PROD-X"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		// On Stop the submitter gives up and fails its follower through the results channel,
+		// blocked behind the worker's result: the channel must stay open until both are in
+		stopped := make(chan error, 1)
+		go func() { stopped <- wp.Stop() }()
+		waitFor(func() bool { return wp.ctx.Err() != nil })
+		close(querier.hold)
+
+		select {
+		case err := <-stopped:
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("Stop did not return")
+		}
+	}
+}
+
+func TestWorkerPool_Resize(t *testing.T) {
+	release := make(chan struct{})
+	collector := &MockPageCollector{data: &CrawledData{Status: "Active Product"}, release: release}
+	wp := NewWorkerPool(collector, &MockQuerier{}, zap.NewNop(), WorkerPoolConfig{
+		NumWorkers: 1,
+		QueueSize:  10,
+		MinDelay:   time.Millisecond,
+		MaxDelay:   time.Millisecond,
+	})
+	if err := wp.Start(); err != nil {
+		t.Fatalf("failed to start worker pool: %v", err)
+	}
+	defer wp.Stop()
+
+	for _, n := range []int{0, MaxWorkers + 1} {
+		if err := wp.Resize(n); err == nil {
+			t.Errorf("expected an error resizing to %d", n)
+		}
+	}
+
+	// Cresce para 3: tres coletas rodam ao mesmo tempo
+	if err := wp.Resize(3); err != nil {
+		t.Fatalf("resize failed: %v", err)
+	}
+	jobs := make([]CrawlerJob, 3)
+	for i := range jobs {
+		jobs[i] = CrawlerJob{ProductCode: fmt.Sprintf("PROD-%03d", i)}
+	}
+	results, err := wp.SubmitBatch(context.Background(), jobs)
+	if err != nil {
+		t.Fatalf("submit failed: %v", err)
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for collector.calls.Load() < 3 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if calls := collector.calls.Load(); calls != 3 {
+		t.Fatalf("expected 3 concurrent crawls after growing, got %d", calls)
+	}
+
+	// Encolhe com as coletas em andamento: nenhuma e interrompida
+	if err := wp.Resize(1); err != nil {
+		t.Fatalf("resize failed: %v", err)
+	}
+	if got := wp.Stats().Workers; got != 1 {
+		t.Errorf("expected 1 worker in stats, got %d", got)
+	}
+	close(release)
+	received := 0
+	for result := range results {
+		if result.Error != nil {
+			t.Errorf("crawl of %s interrupted: %v", result.Job.ProductCode, result.Error)
+		}
+		received++
+	}
+	if received != len(jobs) {
+		t.Fatalf("expected %d results, got %d", len(jobs), received)
+	}
+
+	// Os workers excedentes saem; o restante continua atendendo a fila
+	deadline = time.Now().Add(2 * time.Second)
+	for len(wp.retire) > 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if pending := len(wp.retire); pending != 0 {
+		t.Fatalf("expected surplus workers to exit, %d still pending", pending)
+	}
+	data, err := wp.SubmitAndWait(context.Background(), CrawlerJob{ProductCode: "PROD-AFTER"})
+	if err != nil || data == nil {
+		t.Fatalf("expected the remaining worker to crawl, got %v", err)
+	}
+}
+
+// ctxCollector blocks each Collect until its context is done
+type ctxCollector struct {
+	MockPageCollector
+	hadDeadline atomic.Bool
+}
+
+func (c *ctxCollector) Collect(ctx context.Context, productCode string) (*CrawledData, error) {
+	_, ok := ctx.Deadline()
+	c.hadDeadline.Store(ok)
+	<-ctx.Done()
+	return nil, fmt.Errorf("crawl of %s canceled: %w", productCode, ctx.Err())
+}
+
+func TestWorkerPool_JobTimeout(t *testing.T) {
+	collector := &ctxCollector{}
+	wp := NewWorkerPool(collector, &MockQuerier{}, zap.NewNop(), WorkerPoolConfig{
+		NumWorkers: 1,
+		QueueSize:  10,
+		MinDelay:   time.Millisecond,
+		MaxDelay:   time.Millisecond,
+		JobTimeout: 20 * time.Millisecond,
+	})
+	if err := wp.Start(); err != nil {
+		t.Fatalf("failed to start worker pool: %v", err)
+	}
+	defer wp.Stop()
+
+	results, err := wp.SubmitBatch(context.Background(), []CrawlerJob{{ProductCode: "PROD-SLOW"}})
+	if err != nil {
+		t.Fatalf("submit failed: %v", err)
+	}
+
+	select {
+	case result := <-results:
+		if !errors.Is(result.Error, ErrJobTimeout) || result.ErrorKind != KindTimeout {
+			t.Errorf("expected a job timeout, got kind %q: %v", result.ErrorKind, result.Error)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("job timeout didn't free the worker")
+	}
+
+	// O timeout do job nao vira deadline: o crawler ainda aplica o seu por tentativa
+	if collector.hadDeadline.Load() {
+		t.Error("expected the job context to have no deadline")
+	}
+}
+
+// requestIDCollector records the request id carried by the crawl context
+type requestIDCollector struct {
+	MockPageCollector
+	requestID atomic.Value
+}
+
+func (c *requestIDCollector) Collect(ctx context.Context, productCode string) (*CrawledData, error) {
+	c.requestID.Store(requestid.FromContext(ctx))
+	return &CrawledData{Description: productCode}, nil
+}
+
+func TestWorkerPool_PropagatesRequestID(t *testing.T) {
+	collector := &requestIDCollector{}
+	wp := newTestWorkerPool(t, collector, &MockQuerier{})
+
+	ctx := requestid.WithContext(context.Background(), "f3b1c2")
+	if _, err := wp.SubmitAndWait(ctx, CrawlerJob{ProductCode: "PROD-1"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := collector.requestID.Load(); got != "f3b1c2" {
+		t.Errorf("expected the crawl to carry the request id, got %v", got)
+	}
+
+	results, err := wp.SubmitBatch(ctx, []CrawlerJob{{ProductCode: "PROD-2"}})
+	if err != nil {
+		t.Fatalf("submit failed: %v", err)
+	}
+	if result := <-results; result.Job.RequestID != "f3b1c2" {
+		t.Errorf("expected the batch job to carry the request id, got %q", result.Job.RequestID)
+	}
+}
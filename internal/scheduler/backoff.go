@@ -0,0 +1,40 @@
+package scheduler
+
+import (
+	"context"
+	"time"
+
+	repo "github.com/freitasmatheusrn/lifecycle-monitor/internal/database/postgres/sqlc"
+)
+
+// CrawlFailureTracker is implemented by collectors that persist consecutive crawl failures (the products service)
+type CrawlFailureTracker interface {
+	RecordCrawlFailure(ctx context.Context, code string, crawlErr error) (repo.CrawlFailure, error)
+	ListCrawlFailures(ctx context.Context) ([]repo.CrawlFailure, error)
+}
+
+// BackoffConfig controls how long codes that keep failing are left out of the scheduled crawl.
+// After Threshold consecutive failures a code is skipped for Base, doubling with each further
+// failure up to Max. A Threshold <= 0 disables the backoff.
+type BackoffConfig struct {
+	Threshold int
+	Base      time.Duration
+	Max       time.Duration
+}
+
+// retryAt returns when a code with failure may be crawled again (zero when it isn't backed off)
+func (b BackoffConfig) retryAt(failure repo.CrawlFailure) time.Time {
+	if b.Threshold <= 0 || int(failure.ConsecutiveFailures) < b.Threshold || !failure.LastFailedAt.Valid {
+		return time.Time{}
+	}
+
+	wait := b.Base
+	for i := b.Threshold; i < int(failure.ConsecutiveFailures) && (b.Max <= 0 || wait < b.Max); i++ {
+		wait *= 2
+	}
+	if b.Max > 0 && wait > b.Max {
+		wait = b.Max
+	}
+
+	return failure.LastFailedAt.Time.Add(wait)
+}
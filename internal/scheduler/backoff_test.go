@@ -0,0 +1,147 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	repo "github.com/freitasmatheusrn/lifecycle-monitor/internal/database/postgres/sqlc"
+	"github.com/freitasmatheusrn/lifecycle-monitor/internal/products"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+)
+
+func TestBackoffConfig_RetryAt(t *testing.T) {
+	failedAt := time.Date(2026, 10, 1, 3, 0, 0, 0, time.UTC)
+	backoff := BackoffConfig{Threshold: 3, Base: 24 * time.Hour, Max: 72 * time.Hour}
+
+	tests := []struct {
+		failures int32
+		expected time.Duration // 0 means not backed off
+	}{
+		{2, 0},
+		{3, 24 * time.Hour},
+		{4, 48 * time.Hour},
+		{5, 72 * time.Hour}, // capped
+		{9, 72 * time.Hour},
+	}
+
+	for _, tt := range tests {
+		got := backoff.retryAt(repo.CrawlFailure{
+			ConsecutiveFailures: tt.failures,
+			LastFailedAt:        pgtype.Timestamp{Time: failedAt, Valid: true},
+		})
+
+		var expected time.Time
+		if tt.expected > 0 {
+			expected = failedAt.Add(tt.expected)
+		}
+		if !got.Equal(expected) {
+			t.Errorf("%d failures: expected %v, got %v", tt.failures, expected, got)
+		}
+	}
+}
+
+func TestBackoffConfig_Disabled(t *testing.T) {
+	var backoff BackoffConfig
+	got := backoff.retryAt(repo.CrawlFailure{
+		ConsecutiveFailures: 10,
+		LastFailedAt:        pgtype.Timestamp{Time: time.Now(), Valid: true},
+	})
+	if !got.IsZero() {
+		t.Errorf("expected no backoff when disabled, got %v", got)
+	}
+}
+
+// MockFailureTracker adds crawl failure tracking to MockProductCollector
+type MockFailureTracker struct {
+	MockProductCollector
+	failures []repo.CrawlFailure
+	recorded []string
+}
+
+func (m *MockFailureTracker) RecordCrawlFailure(ctx context.Context, code string, crawlErr error) (repo.CrawlFailure, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.recorded = append(m.recorded, code)
+	return repo.CrawlFailure{
+		Code:                code,
+		ConsecutiveFailures: 1,
+		LastFailedAt:        pgtype.Timestamp{Time: time.Now(), Valid: true},
+	}, nil
+}
+
+func (m *MockFailureTracker) ListCrawlFailures(ctx context.Context) ([]repo.CrawlFailure, error) {
+	return m.failures, nil
+}
+
+func TestRunLifecycleUpdateJob_SkipsBackedOffProducts(t *testing.T) {
+	recently := pgtype.Timestamp{Time: time.Now().Add(-time.Hour), Valid: true}
+	longAgo := pgtype.Timestamp{Time: time.Now().Add(-30 * 24 * time.Hour), Valid: true}
+
+	tracker := &MockFailureTracker{
+		MockProductCollector: MockProductCollector{
+			products: []repo.ListUniqueProductCodesToCollectRow{
+				{Code: "PROD-001"},
+				{Code: "PROD-002"},
+				{Code: "PROD-003"},
+			},
+		},
+		failures: []repo.CrawlFailure{
+			{Code: "PROD-001", ConsecutiveFailures: 3, LastFailedAt: recently}, // backed off
+			{Code: "PROD-002", ConsecutiveFailures: 3, LastFailedAt: longAgo},  // backoff expired
+		},
+	}
+
+	scheduler := &Scheduler{
+		workerPool: &MockBatchSubmitter{
+			results: map[string]products.WorkerResult{
+				"PROD-002": {Error: context.DeadlineExceeded},
+			},
+		},
+		service: tracker,
+		logger:  zap.NewNop(),
+		email:   &MockEmail{},
+		backoff: BackoffConfig{Threshold: 3, Base: 24 * time.Hour, Max: 7 * 24 * time.Hour},
+	}
+
+	scheduler.runLifecycleUpdateJob()
+
+	if len(tracker.savedResults) != 1 || tracker.savedResults[0].Job.ProductCode != "PROD-003" {
+		t.Errorf("expected only PROD-003 to be saved, got %+v", tracker.savedResults)
+	}
+	if len(tracker.recorded) != 1 || tracker.recorded[0] != "PROD-002" {
+		t.Errorf("expected the PROD-002 failure to be recorded, got %v", tracker.recorded)
+	}
+}
+
+func TestRunLifecycleUpdateJob_CanceledCrawlDoesNotCountAsFailure(t *testing.T) {
+	tracker := &MockFailureTracker{
+		MockProductCollector: MockProductCollector{
+			products: []repo.ListUniqueProductCodesToCollectRow{
+				{Code: "PROD-001"},
+				{Code: "PROD-002"},
+			},
+		},
+	}
+
+	scheduler := &Scheduler{
+		workerPool: &MockBatchSubmitter{
+			results: map[string]products.WorkerResult{
+				"PROD-001": {Error: context.Canceled, ErrorKind: products.KindCanceled},
+				"PROD-002": {Error: errors.New("navigation failed"), ErrorKind: products.KindNavigation},
+			},
+		},
+		service: tracker,
+		logger:  zap.NewNop(),
+		email:   &MockEmail{},
+	}
+
+	scheduler.runLifecycleUpdateJob()
+
+	if len(tracker.recorded) != 1 || tracker.recorded[0] != "PROD-002" {
+		t.Errorf("expected only the PROD-002 failure to be recorded, got %v", tracker.recorded)
+	}
+}
@@ -0,0 +1,131 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	repo "github.com/freitasmatheusrn/lifecycle-monitor/internal/database/postgres/sqlc"
+	"github.com/freitasmatheusrn/lifecycle-monitor/internal/products"
+	"go.uber.org/zap"
+)
+
+// DigestStore is implemented by collectors that persist status changes for the daily digest (the products service).
+// The changes are kept in Postgres rather than Redis: Redis here is a cache that may evict keys or
+// restart empty, which would silently lose a day of changes, and clearing up to the last sent ID
+// needs the ordered, durable rows a table gives.
+type DigestStore interface {
+	QueueDigestChanges(ctx context.Context, changes []products.LifecycleStatusChange) error
+	ListDigestChanges(ctx context.Context) ([]repo.LifecycleDigestChange, error)
+	ClearDigestChanges(ctx context.Context, lastID int64) error
+}
+
+// digestTimeout bounds the database work of queuing and sending the digest
+const digestTimeout = time.Minute
+
+// digestSchedule returns the cron spec of the digest: cronExpr when set, otherwise every interval.
+// Empty (no digest) when neither is set.
+func digestSchedule(cronExpr string, interval time.Duration) string {
+	if cronExpr != "" || interval <= 0 {
+		return cronExpr
+	}
+	return "@every " + interval.String()
+}
+
+// queueForDigest stores the changes for the next digest. When they can't be stored the email
+// is sent right away, so no change goes unnoticed.
+func (s *Scheduler) queueForDigest(changes []products.LifecycleStatusChange) {
+	store, ok := s.service.(DigestStore)
+	if !ok {
+		s.logger.Warn("digest mode enabled but changes can't be stored, sending email now")
+		s.sendUnqueuedChanges(changes)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), digestTimeout)
+	defer cancel()
+
+	if err := store.QueueDigestChanges(ctx, changes); err != nil {
+		s.logger.Error("failed to queue changes for digest, sending email now", zap.Error(err))
+		s.sendUnqueuedChanges(changes)
+		return
+	}
+
+	s.logger.Info("status changes queued for digest", zap.Int("changes_count", len(changes)))
+
+	// Muitas mudancas acumuladas: envia o resumo agora em vez de esperar o horario
+	if s.digestThreshold > 0 {
+		queued, err := store.ListDigestChanges(ctx)
+		if err != nil {
+			s.logger.Warn("failed to count digest changes", zap.Error(err))
+			return
+		}
+		if len(queued) >= s.digestThreshold {
+			s.logger.Info("digest threshold reached, sending digest now",
+				zap.Int("queued", len(queued)),
+				zap.Int("threshold", s.digestThreshold),
+			)
+			s.sendDigest()
+		}
+	}
+}
+
+// sendUnqueuedChanges emails the changes that couldn't be queued for the digest. They aren't
+// stored anywhere else, so a failed send is alerted with the codes that went unreported.
+func (s *Scheduler) sendUnqueuedChanges(changes []products.LifecycleStatusChange) {
+	if err := s.sendStatusChangeEmail(changes); err != nil && !errors.Is(err, errNotificationDropped) {
+		codes := make([]string, 0, len(changes))
+		for _, change := range changes {
+			codes = append(codes, change.ProductCode)
+		}
+		s.notifyError("status changes neither queued nor emailed", fmt.Errorf("%s: %w", strings.Join(codes, ", "), err))
+	}
+}
+
+// sendDigest emails every change queued since the last digest. The changes are only removed
+// after the email reaches someone, so a failed or dropped send retries them in the next digest.
+func (s *Scheduler) sendDigest() {
+	store, ok := s.service.(DigestStore)
+	if !ok {
+		return
+	}
+
+	// Cron and threshold may fire together; one digest at a time so no change goes out twice
+	s.digestMu.Lock()
+	defer s.digestMu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), digestTimeout)
+	defer cancel()
+
+	queued, err := store.ListDigestChanges(ctx)
+	if err != nil {
+		s.notifyError("failed to list digest changes", err)
+		return
+	}
+	if len(queued) == 0 {
+		s.logger.Info("no lifecycle changes for the digest")
+		return
+	}
+
+	changes := make([]products.LifecycleStatusChange, 0, len(queued))
+	for _, q := range queued {
+		changes = append(changes, products.LifecycleStatusChange{
+			ProductCode: q.Code,
+			OldStatus:   q.OldStatus,
+			NewStatus:   q.NewStatus,
+		})
+	}
+
+	if err := s.sendChangesEmail(s.t("Resumo diário de mudanças de Lifecycle"), changes); err != nil {
+		if errors.Is(err, errNotificationDropped) {
+			s.logger.Warn("digest had no recipients, keeping changes for the next digest", zap.Int("changes_count", len(changes)))
+		}
+		return
+	}
+
+	if err := store.ClearDigestChanges(ctx, queued[len(queued)-1].ID); err != nil {
+		s.logger.Error("failed to clear sent digest changes", zap.Error(err))
+	}
+}
@@ -0,0 +1,262 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	repo "github.com/freitasmatheusrn/lifecycle-monitor/internal/database/postgres/sqlc"
+	"github.com/freitasmatheusrn/lifecycle-monitor/internal/products"
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+)
+
+// MockDigestStore adds digest persistence to MockProductCollector
+type MockDigestStore struct {
+	MockProductCollector
+	queued   []repo.LifecycleDigestChange
+	queueErr error
+	cleared  int64
+}
+
+func (m *MockDigestStore) QueueDigestChanges(ctx context.Context, changes []products.LifecycleStatusChange) error {
+	if m.queueErr != nil {
+		return m.queueErr
+	}
+	for _, change := range changes {
+		m.queued = append(m.queued, repo.LifecycleDigestChange{
+			ID:        int64(len(m.queued) + 1),
+			Code:      change.ProductCode,
+			OldStatus: change.OldStatus,
+			NewStatus: change.NewStatus,
+		})
+	}
+	return nil
+}
+
+func (m *MockDigestStore) ListDigestChanges(ctx context.Context) ([]repo.LifecycleDigestChange, error) {
+	return m.queued, nil
+}
+
+func (m *MockDigestStore) ClearDigestChanges(ctx context.Context, lastID int64) error {
+	m.cleared = lastID
+	kept := m.queued[:0]
+	for _, q := range m.queued {
+		if q.ID > lastID {
+			kept = append(kept, q)
+		}
+	}
+	m.queued = kept
+	return nil
+}
+
+func TestDigest_QueuesRunsAndSendsOneEmail(t *testing.T) {
+	mockEmail := &MockEmail{}
+	store := &MockDigestStore{}
+	scheduler := &Scheduler{
+		service:          store,
+		logger:           zap.NewNop(),
+		email:            mockEmail,
+		statusRecipients: testRecipients,
+		digestCron:       "0 0 8 * * *",
+	}
+
+	// Two runs during the day: nothing is emailed yet
+	scheduler.dispatchStatusChanges([]products.LifecycleStatusChange{
+		{ProductCode: "PROD-001", OldStatus: "Active Product", NewStatus: "Phase Out Announce"},
+	}, nil)
+	scheduler.dispatchStatusChanges([]products.LifecycleStatusChange{
+		{ProductCode: "PROD-002", OldStatus: "Phase Out Announce", NewStatus: "Prod. Discont."},
+	}, nil)
+	if len(mockEmail.sentEmails) != 0 {
+		t.Fatalf("expected no email before the digest, got %d", len(mockEmail.sentEmails))
+	}
+	if len(store.queued) != 2 {
+		t.Fatalf("expected 2 queued changes, got %d", len(store.queued))
+	}
+
+	scheduler.sendDigest()
+
+	if len(mockEmail.sentEmails) != 1 {
+		t.Fatalf("expected 1 digest email, got %d", len(mockEmail.sentEmails))
+	}
+	if html := mockEmail.sentEmails[0].HTML; !containsString(html, "PROD-001") || !containsString(html, "PROD-002") {
+		t.Error("digest should contain the changes of every run")
+	}
+	if store.cleared != 2 || len(store.queued) != 0 {
+		t.Errorf("expected sent changes to be cleared, cleared through %d, %d left", store.cleared, len(store.queued))
+	}
+
+	// Nothing new: no empty digest
+	scheduler.sendDigest()
+	if len(mockEmail.sentEmails) != 1 {
+		t.Errorf("expected no email without queued changes, got %d", len(mockEmail.sentEmails))
+	}
+}
+
+func TestDigest_SendFailureKeepsChanges(t *testing.T) {
+	store := &MockDigestStore{queued: []repo.LifecycleDigestChange{
+		{ID: 1, Code: "PROD-001", OldStatus: "Active Product", NewStatus: "Prod. Discont."},
+	}}
+	scheduler := &Scheduler{
+		service:          store,
+		logger:           zap.NewNop(),
+		email:            &MockEmail{sendErr: errors.New("smtp down")},
+		statusRecipients: testRecipients,
+		digestCron:       "0 0 8 * * *",
+	}
+
+	scheduler.sendDigest()
+
+	if store.cleared != 0 || len(store.queued) != 1 {
+		t.Error("expected changes to be kept for the next digest after a failed send")
+	}
+}
+
+func TestDigest_DroppedSendKeepsChanges(t *testing.T) {
+	mockEmail := &MockEmail{}
+	store := &MockDigestStore{queued: []repo.LifecycleDigestChange{
+		{ID: 1, Code: "PROD-001", OldStatus: "Active Product", NewStatus: "Prod. Discont."},
+	}}
+	scheduler := &Scheduler{
+		service:               store,
+		logger:                zap.NewNop(),
+		email:                 mockEmail,
+		emptyRecipientsPolicy: EmptyRecipientsWarn,
+		digestCron:            "0 0 8 * * *",
+	}
+
+	scheduler.sendDigest()
+
+	if len(mockEmail.sentEmails) != 0 {
+		t.Fatalf("expected no email without recipients, got %d", len(mockEmail.sentEmails))
+	}
+	if store.cleared != 0 || len(store.queued) != 1 {
+		t.Error("expected changes to be kept when the digest reached no one")
+	}
+
+	// Once someone can receive it, the same changes go out and are cleared
+	scheduler.statusRecipients = testRecipients
+	scheduler.sendDigest()
+
+	if len(mockEmail.sentEmails) != 1 || store.cleared != 1 || len(store.queued) != 0 {
+		t.Errorf("expected the kept changes to be sent and cleared, sent %d, %d left", len(mockEmail.sentEmails), len(store.queued))
+	}
+}
+
+func TestDigest_QueueFailureSendsNow(t *testing.T) {
+	mockEmail := &MockEmail{}
+	scheduler := &Scheduler{
+		service:          &MockDigestStore{queueErr: errors.New("db down")},
+		logger:           zap.NewNop(),
+		email:            mockEmail,
+		statusRecipients: testRecipients,
+		digestCron:       "0 0 8 * * *",
+	}
+
+	scheduler.dispatchStatusChanges([]products.LifecycleStatusChange{
+		{ProductCode: "PROD-001", OldStatus: "Active Product", NewStatus: "Prod. Discont."},
+	}, nil)
+
+	if len(mockEmail.sentEmails) != 1 {
+		t.Errorf("expected the email to be sent right away when queuing fails, got %d", len(mockEmail.sentEmails))
+	}
+}
+
+// flakyEmail fails its first sends and then behaves like MockEmail
+type flakyEmail struct {
+	MockEmail
+	failures int
+}
+
+func (m *flakyEmail) Send(subject, text, html string, recipients []string) error {
+	if m.failures > 0 {
+		m.failures--
+		return errors.New("smtp down")
+	}
+	return m.MockEmail.Send(subject, text, html, recipients)
+}
+
+func TestDigest_QueueAndSendFailureAlerts(t *testing.T) {
+	mockEmail := &flakyEmail{failures: 1}
+	scheduler := &Scheduler{
+		service:          &MockDigestStore{queueErr: errors.New("db down")},
+		logger:           zap.NewNop(),
+		email:            mockEmail,
+		statusRecipients: testRecipients,
+		alertRecipients:  testRecipients,
+		digestCron:       "0 0 8 * * *",
+	}
+
+	scheduler.dispatchStatusChanges([]products.LifecycleStatusChange{
+		{ProductCode: "PROD-001", OldStatus: "Active Product", NewStatus: "Prod. Discont."},
+	}, nil)
+
+	// The changes are lost at this point, so the alert names them
+	if len(mockEmail.sentEmails) != 1 {
+		t.Fatalf("expected an error alert after the status email failed, got %d emails", len(mockEmail.sentEmails))
+	}
+	if alert := mockEmail.sentEmails[0]; !containsString(alert.Subject, "status changes neither queued nor emailed") || !containsString(alert.Text, "PROD-001") {
+		t.Errorf("expected the alert to name the unreported codes, got %q", alert.Subject)
+	}
+}
+
+func TestDigest_ThresholdSendsEarly(t *testing.T) {
+	mockEmail := &MockEmail{}
+	store := &MockDigestStore{}
+	scheduler := &Scheduler{
+		service:          store,
+		logger:           zap.NewNop(),
+		email:            mockEmail,
+		statusRecipients: testRecipients,
+		digestCron:       digestSchedule("", 24*time.Hour),
+		digestThreshold:  3,
+	}
+
+	scheduler.dispatchStatusChanges([]products.LifecycleStatusChange{
+		{ProductCode: "PROD-001", OldStatus: "Active Product", NewStatus: "Phase Out Announce"},
+		{ProductCode: "PROD-002", OldStatus: "Active Product", NewStatus: "Phase Out Announce"},
+	}, nil)
+	if len(mockEmail.sentEmails) != 0 {
+		t.Fatalf("expected no email below the threshold, got %d", len(mockEmail.sentEmails))
+	}
+
+	// A terceira mudanca acumulada atinge o limite: o resumo sai sem esperar o intervalo
+	scheduler.dispatchStatusChanges([]products.LifecycleStatusChange{
+		{ProductCode: "PROD-003", OldStatus: "Phase Out Announce", NewStatus: "Prod. Discont."},
+	}, nil)
+	if len(mockEmail.sentEmails) != 1 {
+		t.Fatalf("expected the digest once the threshold is reached, got %d emails", len(mockEmail.sentEmails))
+	}
+	if html := mockEmail.sentEmails[0].HTML; !containsString(html, "PROD-001") || !containsString(html, "PROD-003") {
+		t.Error("digest should contain every accumulated change")
+	}
+	if len(store.queued) != 0 {
+		t.Errorf("expected the sent changes to be cleared, %d left", len(store.queued))
+	}
+}
+
+func TestDigestSchedule(t *testing.T) {
+	tests := []struct {
+		cron     string
+		interval time.Duration
+		expected string
+	}{
+		{cron: "", interval: 0, expected: ""},
+		{cron: "0 0 8 * * *", interval: 6 * time.Hour, expected: "0 0 8 * * *"},
+		{cron: "", interval: 6 * time.Hour, expected: "@every 6h0m0s"},
+	}
+
+	for _, tt := range tests {
+		got := digestSchedule(tt.cron, tt.interval)
+		if got != tt.expected {
+			t.Errorf("digestSchedule(%q, %s) = %q, expected %q", tt.cron, tt.interval, got, tt.expected)
+		}
+		if got != "" {
+			if _, err := cron.New(cron.WithSeconds()).AddFunc(got, func() {}); err != nil {
+				t.Errorf("schedule %q rejected by the scheduler's cron: %v", got, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package scheduler
+
+import (
+	"bytes"
+	"embed"
+	htmltemplate "html/template"
+	"strings"
+	texttemplate "text/template"
+	"time"
+
+	"github.com/freitasmatheusrn/lifecycle-monitor/internal/products"
+)
+
+// Os emails sao renderizados a partir de templates: o HTML escapa os valores (codigos e mensagens
+// de erro vem de fora) e compartilha o estilo de layout.html; o texto puro usa os mesmos dados.
+//
+//go:embed templates
+var emailTemplatesFS embed.FS
+
+// emailFuncs are replaced per email with the scheduler's locale; parsing only needs the names
+var emailFuncs = map[string]any{"t": func(message string) string { return message }}
+
+// emailTemplate is the HTML and plain text versions of one email
+type emailTemplate struct {
+	html *htmltemplate.Template
+	text *texttemplate.Template
+}
+
+var (
+	statusChangesEmail = mustParseEmail("status_changes")
+	errorAlertEmail    = mustParseEmail("error_alert")
+	lowStockEmail      = mustParseEmail("low_stock")
+)
+
+// mustParseEmail parses templates/<name>.html inside the shared layout and templates/<name>.txt
+func mustParseEmail(name string) emailTemplate {
+	return emailTemplate{
+		html: htmltemplate.Must(htmltemplate.New(name).Funcs(emailFuncs).
+			ParseFS(emailTemplatesFS, "templates/layout.html", "templates/"+name+".html")),
+		text: texttemplate.Must(texttemplate.New(name+".txt").Funcs(emailFuncs).
+			ParseFS(emailTemplatesFS, "templates/"+name+".txt")),
+	}
+}
+
+// statusChangesData feeds the status change report (per run or daily digest)
+type statusChangesData struct {
+	Changes []products.LifecycleStatusChange
+	Drifts  []products.ExpectationDrift // divergencias do lifecycle esperado, so no relatorio da execucao
+}
+
+// errorAlertData feeds the job error alert
+type errorAlertData struct {
+	Context string
+	Error   string
+	Time    string
+}
+
+func newErrorAlertData(context string, err error) errorAlertData {
+	return errorAlertData{
+		Context: context,
+		Error:   err.Error(),
+		Time:    time.Now().Format("2006-01-02 15:04:05"),
+	}
+}
+
+// renderEmail returns the plain text and HTML bodies of tmpl in the scheduler's language
+func (s *Scheduler) renderEmail(tmpl emailTemplate, data any) (string, string, error) {
+	funcs := map[string]any{"t": s.t}
+
+	// Clones keep the parsed templates unexecuted, so each email can bind its own locale
+	html, err := tmpl.html.Clone()
+	if err != nil {
+		return "", "", err
+	}
+	var htmlBody bytes.Buffer
+	if err := html.Funcs(funcs).ExecuteTemplate(&htmlBody, "layout", data); err != nil {
+		return "", "", err
+	}
+
+	text, err := tmpl.text.Clone()
+	if err != nil {
+		return "", "", err
+	}
+	var textBody strings.Builder
+	if err := text.Funcs(funcs).Execute(&textBody, data); err != nil {
+		return "", "", err
+	}
+
+	return textBody.String(), htmlBody.String(), nil
+}
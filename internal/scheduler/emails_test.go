@@ -0,0 +1,63 @@
+package scheduler
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/freitasmatheusrn/lifecycle-monitor/internal/products"
+	"github.com/freitasmatheusrn/lifecycle-monitor/pkg/i18n"
+	"go.uber.org/zap"
+)
+
+func TestSendStatusChangeEmail_EscapesValues(t *testing.T) {
+	mockEmail := &MockEmail{}
+	scheduler := &Scheduler{
+		logger:           zap.NewNop(),
+		email:            mockEmail,
+		statusRecipients: testRecipients,
+	}
+
+	scheduler.sendStatusChangeEmail([]products.LifecycleStatusChange{
+		{ProductCode: `6ES7<img src=x onerror="alert(1)">`, OldStatus: "Active & Co", NewStatus: "Prod. Discont."},
+	})
+
+	if len(mockEmail.sentEmails) != 1 {
+		t.Fatalf("expected 1 email, got %d", len(mockEmail.sentEmails))
+	}
+	email := mockEmail.sentEmails[0]
+
+	if strings.Contains(email.HTML, "<img") {
+		t.Errorf("product code must be escaped in the HTML, got %s", email.HTML)
+	}
+	if !strings.Contains(email.HTML, "6ES7&lt;img") || !strings.Contains(email.HTML, "Active &amp; Co") {
+		t.Errorf("expected escaped values in the HTML, got %s", email.HTML)
+	}
+	if !strings.Contains(email.Text, `Product: 6ES7<img src=x onerror="alert(1)">`) {
+		t.Errorf("plain text should keep the raw value, got %q", email.Text)
+	}
+}
+
+func TestNotifyError_RendersEscapedAlert(t *testing.T) {
+	mockEmail := &MockEmail{}
+	scheduler := &Scheduler{
+		logger:          zap.NewNop(),
+		email:           mockEmail,
+		alertRecipients: testRecipients,
+		locale:          i18n.English,
+	}
+
+	scheduler.notifyError("failed to submit batch", errors.New("queue <full>"))
+
+	if len(mockEmail.sentEmails) != 1 {
+		t.Fatalf("expected 1 email, got %d", len(mockEmail.sentEmails))
+	}
+	email := mockEmail.sentEmails[0]
+
+	if !strings.Contains(email.HTML, "queue &lt;full&gt;") || !strings.Contains(email.HTML, "Scheduler Error") {
+		t.Errorf("expected escaped error and English title in the HTML, got %s", email.HTML)
+	}
+	if !strings.HasPrefix(email.Text, "Context: failed to submit batch\nError: queue <full>\nTime: ") {
+		t.Errorf("unexpected plain text %q", email.Text)
+	}
+}
@@ -0,0 +1,33 @@
+package scheduler
+
+import (
+	"context"
+
+	repo "github.com/freitasmatheusrn/lifecycle-monitor/internal/database/postgres/sqlc"
+	"go.uber.org/zap"
+)
+
+// ExpectationSource is implemented by collectors that keep the expected lifecycle of critical parts (the products service)
+type ExpectationSource interface {
+	ListLifecycleExpectations(ctx context.Context) ([]repo.LifecycleExpectation, error)
+}
+
+// loadExpectations returns the expectations by code. The run goes on without them when they can't be loaded.
+func (s *Scheduler) loadExpectations(ctx context.Context) map[string]repo.LifecycleExpectation {
+	source, ok := s.service.(ExpectationSource)
+	if !ok {
+		return nil
+	}
+
+	list, err := source.ListLifecycleExpectations(ctx)
+	if err != nil {
+		s.logger.Warn("failed to load lifecycle expectations, skipping drift check", zap.Error(err))
+		return nil
+	}
+
+	expectations := make(map[string]repo.LifecycleExpectation, len(list))
+	for _, expectation := range list {
+		expectations[expectation.Code] = expectation
+	}
+	return expectations
+}
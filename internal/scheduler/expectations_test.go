@@ -0,0 +1,110 @@
+package scheduler
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	repo "github.com/freitasmatheusrn/lifecycle-monitor/internal/database/postgres/sqlc"
+	"github.com/freitasmatheusrn/lifecycle-monitor/internal/products"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+)
+
+// MockExpectationSource adds lifecycle expectations to MockProductCollector
+type MockExpectationSource struct {
+	MockProductCollector
+	expectations []repo.LifecycleExpectation
+}
+
+func (m *MockExpectationSource) ListLifecycleExpectations(ctx context.Context) ([]repo.LifecycleExpectation, error) {
+	return m.expectations, nil
+}
+
+func TestRunLifecycleUpdateJob_ReportsExpectationDrift(t *testing.T) {
+	service := &MockExpectationSource{
+		MockProductCollector: MockProductCollector{
+			products: []repo.ListUniqueProductCodesToCollectRow{
+				{Code: "PROD-001"},
+				{Code: "PROD-002"},
+				{Code: "PROD-003"},
+			},
+		},
+		expectations: []repo.LifecycleExpectation{
+			{Code: "PROD-001", ExpectedStatus: pgtype.Text{String: products.StatusActive, Valid: true}},
+			{Code: "PROD-002", ExpectedStatus: pgtype.Text{String: products.StatusPhaseOut, Valid: true}},
+		},
+	}
+	submitter := &MockBatchSubmitter{results: map[string]products.WorkerResult{
+		"PROD-001": {Data: &products.CrawledData{Status: products.StatusDiscontinued}},
+		"PROD-002": {Data: &products.CrawledData{Status: products.StatusPhaseOut}},
+		"PROD-003": {Data: &products.CrawledData{Status: products.StatusDiscontinued}},
+	}}
+	mockEmail := &MockEmail{}
+
+	scheduler := &Scheduler{
+		workerPool:       submitter,
+		service:          service,
+		logger:           zap.NewNop(),
+		email:            mockEmail,
+		statusRecipients: testRecipients,
+	}
+
+	scheduler.runLifecycleUpdateJob()
+
+	if len(mockEmail.sentEmails) != 1 {
+		t.Fatalf("expected 1 drift report, got %d", len(mockEmail.sentEmails))
+	}
+	email := mockEmail.sentEmails[0]
+
+	if email.Subject != "Divergência do lifecycle esperado detectada" {
+		t.Errorf("unexpected subject %q", email.Subject)
+	}
+	if !strings.Contains(email.HTML, "Divergências do Lifecycle Esperado") || strings.Contains(email.HTML, "Mudanças no Lifecycle Detectadas") {
+		t.Errorf("expected only the drift section, got %s", email.HTML)
+	}
+	if !strings.Contains(email.Text, "Product: PROD-001\n  Status mais avançado que o esperado") {
+		t.Errorf("expected PROD-001 drift in the text, got %q", email.Text)
+	}
+	if strings.Contains(email.Text, "PROD-002") || strings.Contains(email.Text, "PROD-003") {
+		t.Errorf("only PROD-001 diverges from its expectation, got %q", email.Text)
+	}
+}
+
+func TestSendRunReport_ChangesAndDriftsInOneEmail(t *testing.T) {
+	mockEmail := &MockEmail{}
+	scheduler := &Scheduler{
+		logger:           zap.NewNop(),
+		email:            mockEmail,
+		statusRecipients: testRecipients,
+	}
+
+	scheduler.sendRunReport(
+		[]products.LifecycleStatusChange{{ProductCode: "PROD-001", OldStatus: "Active Product", NewStatus: "Prod. Discont."}},
+		[]products.ExpectationDrift{{ProductCode: "PROD-002", Kind: products.DriftActivePastEOL, Status: "Active Product", ExpectedEOL: "2026-01-31"}},
+	)
+
+	if len(mockEmail.sentEmails) != 1 {
+		t.Fatalf("expected 1 email, got %d", len(mockEmail.sentEmails))
+	}
+	email := mockEmail.sentEmails[0]
+	if email.Subject != "Mudança de Lifecycle de equipamentos detectada" {
+		t.Errorf("unexpected subject %q", email.Subject)
+	}
+	for _, want := range []string{"Mudanças no Lifecycle Detectadas", "Divergências do Lifecycle Esperado", "2026-01-31"} {
+		if !strings.Contains(email.HTML, want) {
+			t.Errorf("expected %q in the HTML", want)
+		}
+	}
+}
+
+func TestLoadExpectations_WithoutSource(t *testing.T) {
+	scheduler := &Scheduler{service: &MockProductCollector{}, logger: zap.NewNop()}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if expectations := scheduler.loadExpectations(ctx); expectations != nil {
+		t.Errorf("expected no expectations, got %v", expectations)
+	}
+}
@@ -0,0 +1,74 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/freitasmatheusrn/lifecycle-monitor/internal/products"
+	"github.com/freitasmatheusrn/lifecycle-monitor/pkg/rest"
+	"go.uber.org/zap"
+)
+
+// lowStockTimeout bounds the listing of the low stock report
+const lowStockTimeout = time.Minute
+
+// LowStockLister is implemented by collectors that can list the products below their minimum quantity (the products service)
+type LowStockLister interface {
+	ListBelowMinimum(ctx context.Context) ([]products.ProductOutput, *rest.ApiErr)
+}
+
+// lowStockData feeds the low stock report
+type lowStockData struct {
+	Products []products.ProductOutput
+}
+
+// lowStockRecipientsOrDefault returns who receives the low stock report; the status change
+// recipients when no list of its own is configured
+func (s *Scheduler) lowStockRecipientsOrDefault() []string {
+	if len(s.lowStockRecipients) > 0 {
+		return s.lowStockRecipients
+	}
+	return s.statusRecipients
+}
+
+// checkLowStock is the cron entry of the low stock report: it emails the products below their
+// minimum quantity, and sends nothing when every product has enough
+func (s *Scheduler) checkLowStock() {
+	lister, ok := s.service.(LowStockLister)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), lowStockTimeout)
+	defer cancel()
+
+	items, apiErr := lister.ListBelowMinimum(ctx)
+	if apiErr != nil {
+		s.notifyError("failed to list low stock products", apiErr)
+		return
+	}
+	if len(items) == 0 {
+		s.logger.Info("no products below their minimum quantity")
+		return
+	}
+
+	textBody, htmlBody, err := s.renderEmail(lowStockEmail, lowStockData{Products: items})
+	if err != nil {
+		s.logger.Error("failed to render low stock email", zap.Error(err))
+		return
+	}
+
+	recipients := s.resolveRecipients("low_stock", s.lowStockRecipientsOrDefault(), zap.Int("products_count", len(items)))
+	if len(recipients) == 0 {
+		return
+	}
+
+	subject := fmt.Sprintf(s.t("Estoque abaixo do mínimo: %d produto(s)"), len(items))
+	if err := s.email.Send(subject, textBody, htmlBody, recipients); err != nil {
+		s.logger.Error("failed to send low stock email", zap.Error(err), zap.Int("products_count", len(items)))
+		return
+	}
+
+	s.logger.Info("low stock email sent successfully", zap.Int("products_count", len(items)))
+}
@@ -0,0 +1,71 @@
+package scheduler
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/freitasmatheusrn/lifecycle-monitor/internal/products"
+	"github.com/freitasmatheusrn/lifecycle-monitor/pkg/i18n"
+	"github.com/freitasmatheusrn/lifecycle-monitor/pkg/rest"
+	"go.uber.org/zap"
+)
+
+// MockLowStockLister serves a fixed low stock list
+type MockLowStockLister struct {
+	MockProductCollector
+	belowMinimum []products.ProductOutput
+}
+
+func (m *MockLowStockLister) ListBelowMinimum(ctx context.Context) ([]products.ProductOutput, *rest.ApiErr) {
+	return m.belowMinimum, nil
+}
+
+func TestCheckLowStock(t *testing.T) {
+	lister := &MockLowStockLister{belowMinimum: []products.ProductOutput{
+		{Code: "6ES7214-1AG40-0XB0", AreaName: "Linha 1", Description: "CPU 1214C", Quantity: 1, MinQuantity: 3},
+		{Code: "3RT2015-1BB41", AreaName: "Linha 2", Quantity: 0, MinQuantity: 2},
+	}}
+	emailMock := &MockEmail{}
+	scheduler := &Scheduler{
+		service:          lister,
+		logger:           zap.NewNop(),
+		email:            emailMock,
+		statusRecipients: testRecipients,
+		locale:           i18n.English,
+	}
+
+	scheduler.checkLowStock()
+
+	if len(emailMock.sentEmails) != 1 {
+		t.Fatalf("expected 1 email, got %d", len(emailMock.sentEmails))
+	}
+	sent := emailMock.sentEmails[0]
+	if sent.Subject != "Stock below minimum: 2 product(s)" {
+		t.Errorf("unexpected subject %q", sent.Subject)
+	}
+	if len(sent.Recipients) != 1 || sent.Recipients[0] != testRecipients[0] {
+		t.Errorf("expected the status change recipients without a list of its own, got %v", sent.Recipients)
+	}
+	for _, want := range []string{"6ES7214-1AG40-0XB0", "Linha 1", "3RT2015-1BB41", "Minimum: 2"} {
+		if !strings.Contains(sent.Text, want) {
+			t.Errorf("expected text body to contain %q:\n%s", want, sent.Text)
+		}
+	}
+	if !strings.Contains(sent.HTML, "<td>CPU 1214C</td>") {
+		t.Errorf("expected html body to list the description:\n%s", sent.HTML)
+	}
+
+	// Lista propria de destinatarios e nada abaixo do minimo
+	scheduler.lowStockRecipients = []string{"estoque@example.com"}
+	scheduler.checkLowStock()
+	if got := emailMock.sentEmails[1].Recipients; len(got) != 1 || got[0] != "estoque@example.com" {
+		t.Errorf("expected the low stock recipients, got %v", got)
+	}
+
+	lister.belowMinimum = nil
+	scheduler.checkLowStock()
+	if len(emailMock.sentEmails) != 2 {
+		t.Errorf("expected no email without products below minimum, got %d", len(emailMock.sentEmails))
+	}
+}
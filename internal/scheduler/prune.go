@@ -0,0 +1,45 @@
+package scheduler
+
+import (
+	"context"
+	"time"
+
+	"github.com/freitasmatheusrn/lifecycle-monitor/internal/products"
+	"github.com/freitasmatheusrn/lifecycle-monitor/pkg/rest"
+)
+
+// pruneTimeout bounds one scheduled snapshot prune
+const pruneTimeout = 30 * time.Minute
+
+// SnapshotPruner is implemented by collectors that can apply the snapshot retention (the products service)
+type SnapshotPruner interface {
+	PruneSnapshots(ctx context.Context, keepPerProduct int, olderThan time.Duration) (*products.PruneSnapshotsResult, *rest.ApiErr)
+}
+
+// PruneConfig schedules the snapshot retention. The job only runs when Cron is set and at least one
+// of the retention limits is.
+type PruneConfig struct {
+	Cron      string
+	Retention products.SnapshotRetention
+}
+
+// enabled reports whether the prune job has anything to do
+func (p PruneConfig) enabled() bool {
+	return p.Cron != "" && (p.Retention.KeepPerProduct > 0 || p.Retention.RawHTMLAfter > 0)
+}
+
+// pruneSnapshots is the cron entry of the snapshot retention; a failure is alerted like a job error
+func (s *Scheduler) pruneSnapshots() {
+	pruner, ok := s.service.(SnapshotPruner)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), pruneTimeout)
+	defer cancel()
+
+	// O resultado ja e registrado pelo servico
+	if _, apiErr := pruner.PruneSnapshots(ctx, s.pruning.Retention.KeepPerProduct, s.pruning.Retention.RawHTMLAfter); apiErr != nil {
+		s.notifyError("failed to prune snapshots", apiErr)
+	}
+}
@@ -0,0 +1,79 @@
+package scheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/freitasmatheusrn/lifecycle-monitor/internal/products"
+	"github.com/freitasmatheusrn/lifecycle-monitor/pkg/rest"
+	"go.uber.org/zap"
+)
+
+// MockSnapshotPruner records the retention it was called with; err fails the prune
+type MockSnapshotPruner struct {
+	MockProductCollector
+	calls          int
+	keepPerProduct int
+	olderThan      time.Duration
+	err            *rest.ApiErr
+}
+
+func (m *MockSnapshotPruner) PruneSnapshots(ctx context.Context, keepPerProduct int, olderThan time.Duration) (*products.PruneSnapshotsResult, *rest.ApiErr) {
+	m.calls++
+	m.keepPerProduct, m.olderThan = keepPerProduct, olderThan
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &products.PruneSnapshotsResult{KeepPerProduct: keepPerProduct}, nil
+}
+
+func TestPruneConfig_Enabled(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   PruneConfig
+		expected bool
+	}{
+		{"no cron", PruneConfig{Retention: products.SnapshotRetention{KeepPerProduct: 10}}, false},
+		{"no limits", PruneConfig{Cron: "0 0 5 * * *"}, false},
+		{"rows", PruneConfig{Cron: "0 0 5 * * *", Retention: products.SnapshotRetention{KeepPerProduct: 10}}, true},
+		{"raw html", PruneConfig{Cron: "0 0 5 * * *", Retention: products.SnapshotRetention{RawHTMLAfter: time.Hour}}, true},
+	}
+
+	for _, tt := range tests {
+		if got := tt.config.enabled(); got != tt.expected {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.expected, got)
+		}
+	}
+}
+
+func TestPruneSnapshots(t *testing.T) {
+	pruner := &MockSnapshotPruner{}
+	emailMock := &MockEmail{}
+	scheduler := &Scheduler{
+		service:         pruner,
+		logger:          zap.NewNop(),
+		email:           emailMock,
+		alertRecipients: testRecipients,
+		pruning: PruneConfig{
+			Cron:      "0 0 5 * * *",
+			Retention: products.SnapshotRetention{KeepPerProduct: 30, RawHTMLAfter: 90 * 24 * time.Hour},
+		},
+	}
+
+	scheduler.pruneSnapshots()
+
+	if pruner.calls != 1 || pruner.keepPerProduct != 30 || pruner.olderThan != 90*24*time.Hour {
+		t.Fatalf("expected one prune with the configured retention, got %+v", pruner)
+	}
+	if len(emailMock.sentEmails) != 0 {
+		t.Errorf("expected no alert, got %d emails", len(emailMock.sentEmails))
+	}
+
+	pruner.err = rest.NewInternalServerError("erro interno do servidor")
+	scheduler.pruneSnapshots()
+
+	if len(emailMock.sentEmails) != 1 {
+		t.Errorf("expected a failed prune to alert, got %d emails", len(emailMock.sentEmails))
+	}
+}
@@ -0,0 +1,33 @@
+package scheduler
+
+import (
+	"context"
+	"time"
+
+	repo "github.com/freitasmatheusrn/lifecycle-monitor/internal/database/postgres/sqlc"
+	"github.com/freitasmatheusrn/lifecycle-monitor/internal/products"
+)
+
+// StaleProductLister is implemented by collectors that can leave out recently crawled codes (the products service)
+type StaleProductLister interface {
+	ListStaleProductsToCollect(ctx context.Context, olderThan time.Duration) ([]repo.ListUniqueProductCodesToCollectRow, error)
+}
+
+// listProductsToCollect returns the codes of this run: every code, or with recrawlAfter set only the
+// codes whose latest crawl is older than it, so large catalogs are spread across runs
+func (s *Scheduler) listProductsToCollect(ctx context.Context) ([]repo.ListUniqueProductCodesToCollectRow, error) {
+	lister, ok := s.service.(StaleProductLister)
+	if !ok || s.recrawlAfter <= 0 {
+		return s.service.ListUniqueProductsToCollect(ctx)
+	}
+	return lister.ListStaleProductsToCollect(ctx, s.recrawlAfter)
+}
+
+// healthBaseline is the previous run the health check compares with. With recrawlAfter set each run
+// only takes the stale codes, so a smaller list than last time is expected and isn't compared.
+func (s *Scheduler) healthBaseline(previous *products.CollectionRun) *products.CollectionRun {
+	if s.recrawlAfter > 0 {
+		return nil
+	}
+	return previous
+}
@@ -0,0 +1,107 @@
+package scheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	repo "github.com/freitasmatheusrn/lifecycle-monitor/internal/database/postgres/sqlc"
+	"github.com/freitasmatheusrn/lifecycle-monitor/internal/products"
+	"go.uber.org/zap"
+)
+
+// MockStaleLister adds stale product listing to MockRunHistory, whose last run collected every code
+type MockStaleLister struct {
+	MockRunHistory
+	stale     []repo.ListUniqueProductCodesToCollectRow
+	olderThan time.Duration
+}
+
+func (m *MockStaleLister) LatestCollectionRun(ctx context.Context) (*products.CollectionRun, error) {
+	return &products.CollectionRun{Total: 3, Succeeded: 3}, nil
+}
+
+func (m *MockStaleLister) ListStaleProductsToCollect(ctx context.Context, olderThan time.Duration) ([]repo.ListUniqueProductCodesToCollectRow, error) {
+	m.olderThan = olderThan
+	return m.stale, nil
+}
+
+func TestRunLifecycleUpdateJob_RecrawlAfter(t *testing.T) {
+	tests := []struct {
+		name         string
+		recrawlAfter time.Duration
+		expected     []string
+	}{
+		{
+			name:         "zero crawls every code",
+			recrawlAfter: 0,
+			expected:     []string{"PROD-001", "PROD-002", "PROD-003"},
+		},
+		{
+			name:         "set crawls only stale codes",
+			recrawlAfter: 12 * time.Hour,
+			expected:     []string{"PROD-002"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lister := &MockStaleLister{
+				MockRunHistory: MockRunHistory{
+					MockProductCollector: MockProductCollector{
+						products: []repo.ListUniqueProductCodesToCollectRow{
+							{Code: "PROD-001"},
+							{Code: "PROD-002"},
+							{Code: "PROD-003"},
+						},
+					},
+				},
+				stale: []repo.ListUniqueProductCodesToCollectRow{{Code: "PROD-002"}},
+			}
+			emailMock := &MockEmail{}
+
+			scheduler := &Scheduler{
+				workerPool:        &MockBatchSubmitter{},
+				service:           lister,
+				logger:            zap.NewNop(),
+				email:             emailMock,
+				alertRecipients:   testRecipients,
+				minSuccessPercent: 80,
+				recrawlAfter:      tt.recrawlAfter,
+			}
+
+			scheduler.runLifecycleUpdateJob()
+
+			var saved []string
+			for _, result := range lister.savedResults {
+				saved = append(saved, result.Job.ProductCode)
+			}
+			if !equalCodes(saved, tt.expected) {
+				t.Errorf("expected %v to be saved, got %v", tt.expected, saved)
+			}
+			if lister.olderThan != tt.recrawlAfter {
+				t.Errorf("expected stale threshold %v, got %v", tt.recrawlAfter, lister.olderThan)
+			}
+			if len(emailMock.sentEmails) != 0 {
+				t.Errorf("expected a smaller stale list not to alert, got %d emails", len(emailMock.sentEmails))
+			}
+		})
+	}
+}
+
+func equalCodes(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	seen := make(map[string]int)
+	for _, code := range a {
+		seen[code]++
+	}
+	for _, code := range b {
+		seen[code]--
+		if seen[code] < 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,50 @@
+package scheduler
+
+import (
+	"context"
+	"time"
+
+	"github.com/freitasmatheusrn/lifecycle-monitor/internal/products"
+	"go.uber.org/zap"
+)
+
+// replacementsTimeout bounds the lookups of every successor of one report
+const replacementsTimeout = 5 * time.Minute
+
+// ReplacementResolver is implemented by collectors that can look up the successor of a product (the products service)
+type ReplacementResolver interface {
+	ResolveReplacement(ctx context.Context, code string) (*products.ReplacementDetails, error)
+}
+
+// withReplacements returns a copy of changes where each change into a terminal status carries
+// the details of the product's successor. Changes whose successor can't be resolved go as they are.
+func (s *Scheduler) withReplacements(changes []products.LifecycleStatusChange) []products.LifecycleStatusChange {
+	if !s.replacementDetails || len(changes) == 0 {
+		return changes
+	}
+	resolver, ok := s.service.(ReplacementResolver)
+	if !ok {
+		return changes
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), replacementsTimeout)
+	defer cancel()
+
+	enriched := make([]products.LifecycleStatusChange, len(changes))
+	copy(enriched, changes)
+	for i, change := range enriched {
+		if !products.IsTerminalStatus(change.NewStatus, s.routes.phaseOutTerminal) {
+			continue
+		}
+		details, err := resolver.ResolveReplacement(ctx, change.ProductCode)
+		if err != nil {
+			s.logger.Warn("failed to resolve replacement product",
+				zap.String("code", change.ProductCode),
+				zap.Error(err),
+			)
+			continue
+		}
+		enriched[i].Replacement = details
+	}
+	return enriched
+}
@@ -0,0 +1,84 @@
+package scheduler
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/freitasmatheusrn/lifecycle-monitor/internal/products"
+	"go.uber.org/zap"
+)
+
+// MockReplacementResolver adds successor lookups to MockProductCollector
+type MockReplacementResolver struct {
+	MockProductCollector
+	replacements map[string]*products.ReplacementDetails
+	resolved     []string
+}
+
+func (m *MockReplacementResolver) ResolveReplacement(ctx context.Context, code string) (*products.ReplacementDetails, error) {
+	m.resolved = append(m.resolved, code)
+	return m.replacements[code], nil
+}
+
+func TestSendStatusChangeEmail_IncludesReplacementDetails(t *testing.T) {
+	service := &MockReplacementResolver{replacements: map[string]*products.ReplacementDetails{
+		"PROD-001": {Code: "PROD-NEW", Description: "CPU 1515", Status: products.StatusActive, Crawled: true},
+		"PROD-002": {Code: "PROD-MID", Status: products.StatusDiscontinued, FinalCode: "PROD-LAST"},
+	}}
+	mockEmail := &MockEmail{}
+	scheduler := &Scheduler{
+		service:            service,
+		logger:             zap.NewNop(),
+		email:              mockEmail,
+		statusRecipients:   testRecipients,
+		replacementDetails: true,
+	}
+
+	changes := []products.LifecycleStatusChange{
+		{ProductCode: "PROD-001", OldStatus: products.StatusActive, NewStatus: products.StatusDiscontinued},
+		{ProductCode: "PROD-002", OldStatus: products.StatusActive, NewStatus: products.StatusCancellation},
+		{ProductCode: "PROD-003", OldStatus: products.StatusActive, NewStatus: products.StatusPhaseOut},
+	}
+	scheduler.sendStatusChangeEmail(changes)
+
+	if strings.Join(service.resolved, ",") != "PROD-001,PROD-002" {
+		t.Errorf("expected only terminal changes resolved, got %v", service.resolved)
+	}
+	if changes[0].Replacement != nil {
+		t.Error("expected the caller's changes left untouched")
+	}
+	if len(mockEmail.sentEmails) != 1 {
+		t.Fatalf("expected 1 email, got %d", len(mockEmail.sentEmails))
+	}
+	email := mockEmail.sentEmails[0]
+	for _, want := range []string{"Substituto: PROD-NEW - CPU 1515 (Active Product) [fora do catálogo]", "substituto final: PROD-LAST"} {
+		if !strings.Contains(email.Text, want) || !strings.Contains(email.HTML, want) {
+			t.Errorf("expected %q in both bodies, got text %q", want, email.Text)
+		}
+	}
+}
+
+func TestSendStatusChangeEmail_ReplacementDetailsDisabled(t *testing.T) {
+	service := &MockReplacementResolver{replacements: map[string]*products.ReplacementDetails{
+		"PROD-001": {Code: "PROD-NEW"},
+	}}
+	mockEmail := &MockEmail{}
+	scheduler := &Scheduler{
+		service:          service,
+		logger:           zap.NewNop(),
+		email:            mockEmail,
+		statusRecipients: testRecipients,
+	}
+
+	scheduler.sendStatusChangeEmail([]products.LifecycleStatusChange{
+		{ProductCode: "PROD-001", OldStatus: products.StatusActive, NewStatus: products.StatusDiscontinued},
+	})
+
+	if len(service.resolved) != 0 {
+		t.Errorf("expected no lookups when disabled, got %v", service.resolved)
+	}
+	if len(mockEmail.sentEmails) != 1 || strings.Contains(mockEmail.sentEmails[0].Text, "Substituto") {
+		t.Errorf("expected the email without replacement details, got %+v", mockEmail.sentEmails)
+	}
+}
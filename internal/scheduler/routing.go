@@ -0,0 +1,119 @@
+package scheduler
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/freitasmatheusrn/lifecycle-monitor/internal/products"
+)
+
+// Channel is a way of delivering lifecycle status change alerts
+type Channel string
+
+const (
+	ChannelEmail   Channel = "email"
+	ChannelSMS     Channel = "sms"
+	ChannelWebhook Channel = "webhook"
+)
+
+// terminalRouteKey routes every change into a terminal status (see products.IsTerminalStatus)
+const terminalRouteKey = "terminal"
+
+// StatusRoutes maps lifecycle statuses (or transitions) to the channels their changes are sent to
+type StatusRoutes struct {
+	routes           map[string][]Channel // chave em minusculas: "novo status", "antigo>novo" ou "terminal"
+	defaults         []Channel
+	phaseOutTerminal bool // "Phase Out Announce" usa a rota terminal
+}
+
+// ParseStatusRoutes parses routes in the form
+//
+//	Prod. Discont.=email,sms,webhook;Active>Phase Out Announce=email;terminal=sms
+//
+// A key is the new status, an "old>new" transition or "terminal" for any change into a terminal
+// status (phase out included when phaseOutTerminal is set). Transitions take precedence over
+// statuses, and both over "terminal". Changes matching no route go to defaults (email when
+// defaults is empty).
+func ParseStatusRoutes(spec string, defaults []string, phaseOutTerminal bool) (StatusRoutes, error) {
+	r := StatusRoutes{routes: make(map[string][]Channel), phaseOutTerminal: phaseOutTerminal}
+
+	var err error
+	if r.defaults, err = parseChannels(defaults); err != nil {
+		return StatusRoutes{}, err
+	}
+
+	for _, route := range strings.Split(spec, ";") {
+		if strings.TrimSpace(route) == "" {
+			continue
+		}
+
+		key, channels, ok := strings.Cut(route, "=")
+		key = normalizeRouteKey(key)
+		if !ok || key == "" {
+			return StatusRoutes{}, fmt.Errorf("invalid route %q: expected status=channels", route)
+		}
+
+		parsed, err := parseChannels(strings.Split(channels, ","))
+		if err != nil {
+			return StatusRoutes{}, fmt.Errorf("invalid route %q: %w", route, err)
+		}
+		r.routes[key] = parsed
+	}
+
+	return r, nil
+}
+
+// TerminalSMSRoute returns the route spec that also sends changes into a terminal status by SMS,
+// on top of defaults (email when empty); other changes keep the defaults
+func TerminalSMSRoute(defaults []string) string {
+	channels := []string{string(ChannelEmail)}
+	if parsed, err := parseChannels(defaults); err == nil && len(parsed) > 0 {
+		channels = channels[:0]
+		for _, channel := range parsed {
+			if channel != ChannelSMS {
+				channels = append(channels, string(channel))
+			}
+		}
+	}
+	return terminalRouteKey + "=" + strings.Join(append(channels, string(ChannelSMS)), ",")
+}
+
+func parseChannels(values []string) ([]Channel, error) {
+	var channels []Channel
+	for _, v := range values {
+		v = strings.ToLower(strings.TrimSpace(v))
+		switch Channel(v) {
+		case "":
+			continue
+		case ChannelEmail, ChannelSMS, ChannelWebhook:
+			channels = append(channels, Channel(v))
+		default:
+			return nil, fmt.Errorf("unknown channel %q", v)
+		}
+	}
+	return channels, nil
+}
+
+func normalizeRouteKey(key string) string {
+	if old, updated, ok := strings.Cut(key, ">"); ok {
+		return strings.ToLower(strings.TrimSpace(old)) + ">" + strings.ToLower(strings.TrimSpace(updated))
+	}
+	return strings.ToLower(strings.TrimSpace(key))
+}
+
+// channelsFor returns the channels a status change must be sent to
+func (r StatusRoutes) channelsFor(change products.LifecycleStatusChange) []Channel {
+	if channels, ok := r.routes[normalizeRouteKey(change.OldStatus+">"+change.NewStatus)]; ok {
+		return channels
+	}
+	if channels, ok := r.routes[normalizeRouteKey(change.NewStatus)]; ok {
+		return channels
+	}
+	if channels, ok := r.routes[terminalRouteKey]; ok && products.IsTerminalStatus(change.NewStatus, r.phaseOutTerminal) {
+		return channels
+	}
+	if len(r.defaults) > 0 {
+		return r.defaults
+	}
+	return []Channel{ChannelEmail}
+}
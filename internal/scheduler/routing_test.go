@@ -0,0 +1,124 @@
+package scheduler
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/freitasmatheusrn/lifecycle-monitor/internal/products"
+)
+
+func TestParseStatusRoutes_Precedence(t *testing.T) {
+	routes, err := ParseStatusRoutes(" prod. discont. = email, SMS ; Active>Prod. Discont.=webhook;", []string{"email"}, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		change   products.LifecycleStatusChange
+		expected []Channel
+	}{
+		{
+			name:     "transition route wins over status route",
+			change:   products.LifecycleStatusChange{OldStatus: "Active", NewStatus: "Prod. Discont."},
+			expected: []Channel{ChannelWebhook},
+		},
+		{
+			name:     "status route is case-insensitive",
+			change:   products.LifecycleStatusChange{OldStatus: "Phase Out Announce", NewStatus: "Prod. Discont."},
+			expected: []Channel{ChannelEmail, ChannelSMS},
+		},
+		{
+			name:     "unrouted status uses defaults",
+			change:   products.LifecycleStatusChange{OldStatus: "", NewStatus: "Active"},
+			expected: []Channel{ChannelEmail},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := routes.channelsFor(tt.change); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestParseStatusRoutes_TerminalRoute(t *testing.T) {
+	spec := "terminal=sms;Prod. Discont.=webhook"
+	phaseOut := products.LifecycleStatusChange{OldStatus: "Active Product", NewStatus: "Phase Out Announce"}
+
+	routes, err := ParseStatusRoutes(spec, []string{"email"}, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := routes.channelsFor(products.LifecycleStatusChange{NewStatus: "End Prod.Lifecycl."}); !reflect.DeepEqual(got, []Channel{ChannelSMS}) {
+		t.Errorf("expected terminal status to use terminal route, got %v", got)
+	}
+	if got := routes.channelsFor(products.LifecycleStatusChange{NewStatus: "Prod. Discont."}); !reflect.DeepEqual(got, []Channel{ChannelWebhook}) {
+		t.Errorf("expected status route to win over terminal route, got %v", got)
+	}
+	if got := routes.channelsFor(phaseOut); !reflect.DeepEqual(got, []Channel{ChannelEmail}) {
+		t.Errorf("expected phase out to use defaults, got %v", got)
+	}
+
+	routes, err = ParseStatusRoutes(spec, []string{"email"}, true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := routes.channelsFor(phaseOut); !reflect.DeepEqual(got, []Channel{ChannelSMS}) {
+		t.Errorf("expected phase out to use terminal route when terminal, got %v", got)
+	}
+}
+
+func TestParseStatusRoutes_Invalid(t *testing.T) {
+	specs := []string{"Active", "=email", "Active=pager"}
+	for _, spec := range specs {
+		if _, err := ParseStatusRoutes(spec, nil, false); err == nil {
+			t.Errorf("expected error for %q", spec)
+		}
+	}
+
+	if _, err := ParseStatusRoutes("", []string{"fax"}, false); err == nil {
+		t.Error("expected error for unknown default channel")
+	}
+}
+
+func TestStatusRoutes_ZeroValueSendsEmail(t *testing.T) {
+	var routes StatusRoutes
+	got := routes.channelsFor(products.LifecycleStatusChange{OldStatus: "Active", NewStatus: "Prod. Discont."})
+	if !reflect.DeepEqual(got, []Channel{ChannelEmail}) {
+		t.Errorf("expected email, got %v", got)
+	}
+}
+
+func TestTerminalSMSRoute(t *testing.T) {
+	tests := []struct {
+		defaults []string
+		expected string
+	}{
+		{defaults: nil, expected: "terminal=email,sms"},
+		{defaults: []string{"email"}, expected: "terminal=email,sms"},
+		{defaults: []string{"webhook", "sms"}, expected: "terminal=webhook,sms"},
+	}
+
+	for _, tt := range tests {
+		if got := TerminalSMSRoute(tt.defaults); got != tt.expected {
+			t.Errorf("defaults %v: expected %q, got %q", tt.defaults, tt.expected, got)
+		}
+	}
+
+	// Only changes into a terminal status add SMS; the others keep the defaults
+	routes, err := ParseStatusRoutes(TerminalSMSRoute([]string{"email"}), []string{"email"}, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	discontinued := routes.channelsFor(products.LifecycleStatusChange{OldStatus: "Active", NewStatus: products.StatusDiscontinued})
+	if !reflect.DeepEqual(discontinued, []Channel{ChannelEmail, ChannelSMS}) {
+		t.Errorf("expected email and sms for a discontinuation, got %v", discontinued)
+	}
+	phaseOut := routes.channelsFor(products.LifecycleStatusChange{OldStatus: "Active", NewStatus: products.StatusPhaseOut})
+	if !reflect.DeepEqual(phaseOut, []Channel{ChannelEmail}) {
+		t.Errorf("expected only email for a phase out, got %v", phaseOut)
+	}
+}
@@ -0,0 +1,75 @@
+package scheduler
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/freitasmatheusrn/lifecycle-monitor/internal/products"
+	"go.uber.org/zap"
+)
+
+// runHealth compares what a run collected with what it was expected to collect and with the previous run
+type runHealth struct {
+	Total               int // produtos que a execucao tentou coletar
+	Succeeded           int
+	SuccessRate         float64 // porcentagem de Total coletada com sucesso
+	PreviousTotal       int     // zero sem execucao anterior
+	PreviousSuccessRate float64
+	Problems            []string // motivos para considerar a execucao degradada
+}
+
+func (h runHealth) Degraded() bool {
+	return len(h.Problems) > 0
+}
+
+// fields is the comparison included in the run summary log
+func (h runHealth) fields() []zap.Field {
+	return []zap.Field{
+		zap.Float64("success_rate", h.SuccessRate),
+		zap.Int("previous_total", h.PreviousTotal),
+		zap.Float64("previous_success_rate", h.PreviousSuccessRate),
+	}
+}
+
+// checkRunHealth flags a run whose success rate, or whose number of products to collect compared
+// with the previous run, falls below minPercent. A minPercent of zero never flags a run.
+func checkRunHealth(total, succeeded int, previous *products.CollectionRun, minPercent int) runHealth {
+	health := runHealth{Total: total, Succeeded: succeeded, SuccessRate: successRate(succeeded, total)}
+	if previous != nil {
+		health.PreviousTotal = previous.Total
+		health.PreviousSuccessRate = successRate(previous.Succeeded, previous.Total)
+	}
+	if minPercent <= 0 {
+		return health
+	}
+
+	if total > 0 && health.SuccessRate < float64(minPercent) {
+		health.Problems = append(health.Problems, fmt.Sprintf("%d de %d produtos coletados com sucesso (%.0f%%, minimo %d%%)",
+			succeeded, total, health.SuccessRate, minPercent))
+	}
+	// A consulta de produtos devolvendo bem menos que na ultima execucao tambem passa por sucesso
+	if health.PreviousTotal > 0 && successRate(total, health.PreviousTotal) < float64(minPercent) {
+		health.Problems = append(health.Problems, fmt.Sprintf("%d produtos a coletar contra %d na execucao anterior",
+			total, health.PreviousTotal))
+	}
+	if health.Degraded() && previous != nil {
+		health.Problems = append(health.Problems, fmt.Sprintf("execucao anterior: %d de %d coletados (%.0f%%)",
+			previous.Succeeded, previous.Total, health.PreviousSuccessRate))
+	}
+	return health
+}
+
+func successRate(succeeded, total int) float64 {
+	if total <= 0 {
+		return 0
+	}
+	return float64(succeeded) * 100 / float64(total)
+}
+
+// alertDegradedRun notifies the alert recipients of a run that collected far fewer products than expected
+func (s *Scheduler) alertDegradedRun(runID string, health runHealth) {
+	if !health.Degraded() {
+		return
+	}
+	s.notifyError("lifecycle update run degraded", fmt.Errorf("run %s: %s", runID, strings.Join(health.Problems, "; ")))
+}
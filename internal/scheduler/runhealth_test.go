@@ -0,0 +1,86 @@
+package scheduler
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	repo "github.com/freitasmatheusrn/lifecycle-monitor/internal/database/postgres/sqlc"
+	"github.com/freitasmatheusrn/lifecycle-monitor/internal/products"
+	"go.uber.org/zap"
+)
+
+func TestCheckRunHealth(t *testing.T) {
+	previous := &products.CollectionRun{Total: 100, Succeeded: 98}
+
+	tests := []struct {
+		name      string
+		total     int
+		succeeded int
+		previous  *products.CollectionRun
+		min       int
+		problems  int
+	}{
+		{name: "healthy run", total: 100, succeeded: 95, previous: previous, min: 80},
+		{name: "most crawls failed", total: 100, succeeded: 30, previous: previous, min: 80, problems: 2}, // taxa + execucao anterior
+		{name: "product query returned too few", total: 10, succeeded: 10, previous: previous, min: 80, problems: 2},
+		{name: "empty list after a full run", total: 0, succeeded: 0, previous: previous, min: 80, problems: 2},
+		{name: "first run below threshold", total: 10, succeeded: 5, min: 80, problems: 1},
+		{name: "disabled", total: 100, succeeded: 1, previous: previous, min: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			health := checkRunHealth(tt.total, tt.succeeded, tt.previous, tt.min)
+			if len(health.Problems) != tt.problems {
+				t.Errorf("expected %d problems, got %q", tt.problems, health.Problems)
+			}
+		})
+	}
+
+	health := checkRunHealth(100, 30, previous, 80)
+	if health.SuccessRate != 30 || health.PreviousSuccessRate != 98 || health.PreviousTotal != 100 {
+		t.Errorf("unexpected comparison %+v", health)
+	}
+	if health.Problems[0] != "30 de 100 produtos coletados com sucesso (30%, minimo 80%)" {
+		t.Errorf("unexpected problem %q", health.Problems[0])
+	}
+}
+
+func TestRunLifecycleUpdateJob_AlertsDegradedRun(t *testing.T) {
+	service := &MockRunHistory{
+		MockProductCollector: MockProductCollector{
+			products: []repo.ListUniqueProductCodesToCollectRow{
+				{Code: "PROD-001"},
+				{Code: "PROD-002"},
+				{Code: "PROD-003"},
+				{Code: "PROD-004"},
+			},
+		},
+	}
+	submitter := &MockBatchSubmitter{results: map[string]products.WorkerResult{
+		"PROD-002": {Error: errors.New("timeout waiting for page load")},
+		"PROD-003": {Error: errors.New("timeout waiting for page load")},
+		"PROD-004": {Error: errors.New("timeout waiting for page load")},
+	}}
+	mockEmail := &MockEmail{}
+
+	scheduler := &Scheduler{
+		workerPool:        submitter,
+		service:           service,
+		logger:            zap.NewNop(),
+		email:             mockEmail,
+		alertRecipients:   testRecipients,
+		minSuccessPercent: 80,
+	}
+
+	scheduler.runLifecycleUpdateJob()
+
+	if len(mockEmail.sentEmails) != 1 {
+		t.Fatalf("expected 1 degraded run alert, got %d", len(mockEmail.sentEmails))
+	}
+	if email := mockEmail.sentEmails[0]; !strings.Contains(email.Subject, "lifecycle update run degraded") ||
+		!strings.Contains(email.Text, "1 de 4 produtos coletados com sucesso (25%, minimo 80%)") {
+		t.Errorf("unexpected alert %q: %q", email.Subject, email.Text)
+	}
+}
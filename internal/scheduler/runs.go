@@ -0,0 +1,88 @@
+package scheduler
+
+import (
+	"context"
+	"time"
+
+	repo "github.com/freitasmatheusrn/lifecycle-monitor/internal/database/postgres/sqlc"
+	"github.com/freitasmatheusrn/lifecycle-monitor/internal/products"
+	"go.uber.org/zap"
+)
+
+// RunHistory is implemented by collectors that persist the summary of each run (the products service)
+type RunHistory interface {
+	RecordCollectionRun(ctx context.Context, run products.CollectionRun) error
+	LatestCollectionRun(ctx context.Context) (*products.CollectionRun, error)
+}
+
+// defaultRunTimeout bounds a lifecycle update run when no timeout is configured
+const defaultRunTimeout = 30 * time.Minute
+
+// runHistoryTimeout bounds recording the run, which happens after the run context may have expired
+const runHistoryTimeout = time.Minute
+
+// runTimeoutOrDefault returns how long a lifecycle update run may take
+func (s *Scheduler) runTimeoutOrDefault() time.Duration {
+	if s.runTimeout <= 0 {
+		return defaultRunTimeout
+	}
+	return s.runTimeout
+}
+
+// previousRun returns the summary of the last run, or nil when there is none or it can't be loaded
+func (s *Scheduler) previousRun(ctx context.Context) *products.CollectionRun {
+	history, ok := s.service.(RunHistory)
+	if !ok {
+		return nil
+	}
+
+	run, err := history.LatestCollectionRun(ctx)
+	if err != nil {
+		s.logger.Warn("failed to load the last collection run", zap.Error(err))
+		return nil
+	}
+	return run
+}
+
+// prioritizeUncollected moves the products the previous run didn't get to to the front,
+// so a run that keeps timing out doesn't always leave the same products behind
+func (s *Scheduler) prioritizeUncollected(toCollect []repo.ListUniqueProductCodesToCollectRow, previous *products.CollectionRun) []repo.ListUniqueProductCodesToCollectRow {
+	if previous == nil || len(previous.UncollectedCodes) == 0 {
+		return toCollect
+	}
+
+	pending := make(map[string]bool, len(previous.UncollectedCodes))
+	for _, code := range previous.UncollectedCodes {
+		pending[code] = true
+	}
+
+	prioritized := make([]repo.ListUniqueProductCodesToCollectRow, 0, len(toCollect))
+	var others []repo.ListUniqueProductCodesToCollectRow
+	for _, p := range toCollect {
+		if pending[p.Code] {
+			prioritized = append(prioritized, p)
+		} else {
+			others = append(others, p)
+		}
+	}
+
+	s.logger.Info("prioritizing products left uncollected by the last run",
+		zap.Int("count", len(prioritized)),
+	)
+	return append(prioritized, others...)
+}
+
+// recordRun stores the run summary in the run history, when the collector keeps one
+func (s *Scheduler) recordRun(run products.CollectionRun) {
+	history, ok := s.service.(RunHistory)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), runHistoryTimeout)
+	defer cancel()
+
+	if err := history.RecordCollectionRun(ctx, run); err != nil {
+		s.logger.Error("failed to record collection run", zap.Error(err))
+	}
+}
@@ -0,0 +1,139 @@
+package scheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	repo "github.com/freitasmatheusrn/lifecycle-monitor/internal/database/postgres/sqlc"
+	"github.com/freitasmatheusrn/lifecycle-monitor/internal/products"
+	"go.uber.org/zap"
+)
+
+// MockRunHistory adds the run history to MockProductCollector
+type MockRunHistory struct {
+	MockProductCollector
+	lastUncollected []string
+	runs            []products.CollectionRun
+}
+
+func (m *MockRunHistory) RecordCollectionRun(ctx context.Context, run products.CollectionRun) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.runs = append(m.runs, run)
+	return nil
+}
+
+func (m *MockRunHistory) LatestCollectionRun(ctx context.Context) (*products.CollectionRun, error) {
+	if m.lastUncollected == nil {
+		return nil, nil
+	}
+	return &products.CollectionRun{UncollectedCodes: m.lastUncollected}, nil
+}
+
+// stallingSubmitter returns results for the first `answered` jobs, then stalls until the run context
+// expires, like the worker pool does on a slow catalog
+type stallingSubmitter struct {
+	answered int
+	order    []string
+}
+
+func (m *stallingSubmitter) SubmitBatch(ctx context.Context, jobs []products.CrawlerJob) (<-chan products.WorkerResult, error) {
+	ch := make(chan products.WorkerResult, len(jobs))
+	for _, job := range jobs {
+		m.order = append(m.order, job.ProductCode)
+	}
+
+	go func() {
+		defer close(ch)
+		for _, job := range jobs[:m.answered] {
+			ch <- products.WorkerResult{Job: job, Data: &products.CrawledData{Status: "Active"}}
+		}
+		<-ctx.Done()
+	}()
+	return ch, nil
+}
+
+func TestRunLifecycleUpdateJob_TimeoutRecordsUncollected(t *testing.T) {
+	history := &MockRunHistory{
+		MockProductCollector: MockProductCollector{
+			products: []repo.ListUniqueProductCodesToCollectRow{
+				{Code: "PROD-001"},
+				{Code: "PROD-002"},
+				{Code: "PROD-003"},
+				{Code: "PROD-004"},
+			},
+		},
+		lastUncollected: []string{"PROD-004", "PROD-GONE"},
+	}
+	submitter := &stallingSubmitter{answered: 2}
+	mockEmail := &MockEmail{}
+
+	scheduler := &Scheduler{
+		workerPool:      submitter,
+		service:         history,
+		logger:          zap.NewNop(),
+		email:           mockEmail,
+		alertRecipients: testRecipients,
+		runTimeout:      50 * time.Millisecond,
+	}
+
+	scheduler.runLifecycleUpdateJob()
+
+	expectedOrder := []string{"PROD-004", "PROD-001", "PROD-002", "PROD-003"}
+	for i, code := range expectedOrder {
+		if submitter.order[i] != code {
+			t.Fatalf("expected last run's uncollected products first, got %v", submitter.order)
+		}
+	}
+
+	if len(history.runs) != 1 {
+		t.Fatalf("expected 1 recorded run, got %d", len(history.runs))
+	}
+	run := history.runs[0]
+	if !run.TimedOut || run.Total != 4 || run.Succeeded != 2 || run.Failed != 0 {
+		t.Errorf("unexpected run summary %+v", run)
+	}
+	if len(run.UncollectedCodes) != 2 || run.UncollectedCodes[0] != "PROD-002" || run.UncollectedCodes[1] != "PROD-003" {
+		t.Errorf("expected PROD-002 and PROD-003 uncollected, got %v", run.UncollectedCodes)
+	}
+	if !run.RunID.Valid {
+		t.Error("expected the run to keep its run ID")
+	}
+
+	// Os resultados parciais foram salvos e o alerta avisa do tempo esgotado
+	if len(history.savedResults) != 2 {
+		t.Errorf("expected the 2 partial results to be saved, got %d", len(history.savedResults))
+	}
+	if len(mockEmail.sentEmails) != 1 || !containsString(mockEmail.sentEmails[0].Text, "tempo limite de 50ms esgotado com 2 de 4 produtos sem coleta") {
+		t.Errorf("expected a timeout alert, got %+v", mockEmail.sentEmails)
+	}
+}
+
+func TestRunLifecycleUpdateJob_CompleteRunRecordsNothingUncollected(t *testing.T) {
+	history := &MockRunHistory{
+		MockProductCollector: MockProductCollector{
+			products: []repo.ListUniqueProductCodesToCollectRow{{Code: "PROD-001"}, {Code: "PROD-002"}},
+			statusChanges: map[string]*products.LifecycleStatusChange{
+				"PROD-001": {ProductCode: "PROD-001", OldStatus: "Active", NewStatus: "Phase Out"},
+			},
+		},
+	}
+
+	scheduler := &Scheduler{
+		workerPool: &MockBatchSubmitter{},
+		service:    history,
+		logger:     zap.NewNop(),
+		email:      &MockEmail{},
+	}
+
+	scheduler.runLifecycleUpdateJob()
+
+	if len(history.runs) != 1 {
+		t.Fatalf("expected 1 recorded run, got %d", len(history.runs))
+	}
+	if run := history.runs[0]; run.TimedOut || len(run.UncollectedCodes) != 0 || run.Succeeded != 2 || run.StatusChanges != 1 {
+		t.Errorf("unexpected run summary %+v", run)
+	}
+}
@@ -2,12 +2,21 @@ package scheduler
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	repo "github.com/freitasmatheusrn/lifecycle-monitor/internal/database/postgres/sqlc"
 	"github.com/freitasmatheusrn/lifecycle-monitor/internal/email"
 	"github.com/freitasmatheusrn/lifecycle-monitor/internal/products"
+	"github.com/freitasmatheusrn/lifecycle-monitor/pkg/i18n"
+	"github.com/freitasmatheusrn/lifecycle-monitor/pkg/notification"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/robfig/cron/v3"
 	"go.uber.org/zap"
 )
@@ -23,23 +32,107 @@ type BatchSubmitter interface {
 	SubmitBatch(ctx context.Context, jobs []products.CrawlerJob) (<-chan products.WorkerResult, error)
 }
 
+// EmptyRecipientsPolicy controls what happens to a notification whose recipient list is empty
+type EmptyRecipientsPolicy string
+
+const (
+	EmptyRecipientsWarn     EmptyRecipientsPolicy = "warn"     // Log the dropped notification at Warn with its summary
+	EmptyRecipientsFallback EmptyRecipientsPolicy = "fallback" // Send it to the fallback recipients instead
+)
+
+// NotificationConfig holds who receives the scheduler notifications and through which channels
+type NotificationConfig struct {
+	StatusRecipients      []string // Lifecycle status change reports
+	AlertRecipients       []string // Job error alerts
+	FallbackRecipients    []string // Used by EmptyRecipientsFallback when a list above is empty
+	EmptyRecipientsPolicy EmptyRecipientsPolicy
+	Routes                StatusRoutes              // Channels per lifecycle status; zero value sends everything by email
+	SMS                   notification.Notification // nil disables the sms channel
+	SMSRecipients         []string                  // Phone numbers for status change alerts
+	Webhook               notification.Notification // nil disables the webhook channel
+	WebhookURLs           []string                  // Webhook URLs for status change alerts
+	Locale                i18n.Locale               // Language of emails and alerts; empty means Portuguese
+	DigestCron            string                    // When set, status change emails are queued and sent once by this cron
+	DigestInterval        time.Duration             // Without DigestCron, queue the emails and send them every interval (0 disables)
+	DigestThreshold       int                       // Send the digest early once this many changes are queued (0 disables)
+	MinSuccessPercent     int                       // Alert when a run collects less than this share of its products (0 disables)
+	ReplacementDetails    bool                      // Include the successor's description and status in status change emails
+	LowStockCron          string                    // When set, products below their minimum quantity are emailed by this cron
+	LowStockRecipients    []string                  // Low stock report; empty uses StatusRecipients
+}
+
+// CollectionConfig holds how the lifecycle update runs collect products and keep their snapshots
+type CollectionConfig struct {
+	Backoff      BackoffConfig // Failure backoff per product code
+	RunTimeout   time.Duration // Maximum duration of each run; zero uses defaultRunTimeout
+	RecrawlAfter time.Duration // Only collect codes not collected for this long; zero collects all
+	Pruning      PruneConfig   // Snapshot retention; disabled without a cron or limits
+}
+
 type Scheduler struct {
-	cron            *cron.Cron
-	workerPool      BatchSubmitter
-	service         ProductCollector
-	logger          *zap.Logger
-	email           email.Email
-	alertRecipients []string
+	cron                  *cron.Cron
+	workerPool            BatchSubmitter
+	service               ProductCollector
+	logger                *zap.Logger
+	email                 email.Email
+	alertRecipients       []string
+	statusRecipients      []string
+	fallbackRecipients    []string
+	emptyRecipientsPolicy EmptyRecipientsPolicy
+	routes                StatusRoutes
+	sms                   notification.Notification
+	smsRecipients         []string
+	webhook               notification.Notification
+	webhookURLs           []string
+	locale                i18n.Locale
+	digestCron            string // vazio envia o email de mudancas ao fim de cada execucao
+	digestThreshold       int    // mudancas na fila que antecipam o resumo; zero so envia no horario
+	digestMu              sync.Mutex
+	backoff               BackoffConfig
+	runTimeout            time.Duration // tempo maximo de cada execucao; zero usa defaultRunTimeout
+	minSuccessPercent     int           // abaixo disso a execucao e considerada degradada; zero desativa
+	replacementDetails    bool          // busca o sucessor dos produtos descontinuados para o email
+	running               atomic.Bool   // uma execucao em andamento; cron e RunNow nao sobrepoem
+	recrawlAfter          time.Duration // so coleta codigos sem coleta ha esse tempo; zero coleta todos
+	pruning               PruneConfig   // retencao dos snapshots; desativada sem cron ou limites
+	lowStockCron          string        // horario do relatorio de estoque baixo; vazio desativa
+	lowStockRecipients    []string      // vazio usa statusRecipients
 }
 
-func NewScheduler(workerPool BatchSubmitter, service ProductCollector, logger *zap.Logger, e email.Email, alertRecipients []string) *Scheduler {
+// ErrRunInProgress is returned by RunNow while a lifecycle update run is still going
+var ErrRunInProgress = errors.New("lifecycle update run already in progress")
+
+// errNotificationDropped is returned by sendReport when the empty recipients policy dropped the
+// report, so callers that must know it reached someone can tell it apart from a send
+var errNotificationDropped = errors.New("notification dropped: no recipients")
+
+func NewScheduler(workerPool BatchSubmitter, service ProductCollector, logger *zap.Logger, e email.Email, notifications NotificationConfig, collection CollectionConfig) *Scheduler {
 	return &Scheduler{
-		cron:            cron.New(cron.WithSeconds()),
-		workerPool:      workerPool,
-		service:         service,
-		logger:          logger,
-		email:           e,
-		alertRecipients: alertRecipients,
+		cron:                  cron.New(cron.WithSeconds()),
+		workerPool:            workerPool,
+		service:               service,
+		logger:                logger,
+		email:                 e,
+		alertRecipients:       notifications.AlertRecipients,
+		statusRecipients:      notifications.StatusRecipients,
+		fallbackRecipients:    notifications.FallbackRecipients,
+		emptyRecipientsPolicy: notifications.EmptyRecipientsPolicy,
+		routes:                notifications.Routes,
+		sms:                   notifications.SMS,
+		smsRecipients:         notifications.SMSRecipients,
+		webhook:               notifications.Webhook,
+		webhookURLs:           notifications.WebhookURLs,
+		locale:                notifications.Locale,
+		digestCron:            digestSchedule(notifications.DigestCron, notifications.DigestInterval),
+		digestThreshold:       notifications.DigestThreshold,
+		backoff:               collection.Backoff,
+		runTimeout:            collection.RunTimeout,
+		minSuccessPercent:     notifications.MinSuccessPercent,
+		replacementDetails:    notifications.ReplacementDetails,
+		recrawlAfter:          collection.RecrawlAfter,
+		pruning:               collection.Pruning,
+		lowStockCron:          notifications.LowStockCron,
+		lowStockRecipients:    notifications.LowStockRecipients,
 	}
 }
 
@@ -52,8 +145,40 @@ func (s *Scheduler) Start(cronExpr string) error {
 		return err
 	}
 
+	// Digest mode: one consolidated status change email at the configured time
+	if s.digestCron != "" {
+		if _, err := s.cron.AddFunc(s.digestCron, s.sendDigest); err != nil {
+			return fmt.Errorf("invalid digest cron expression: %w", err)
+		}
+	}
+
+	// Snapshot retention: old repeated snapshots and page HTML are dropped off-peak
+	if s.pruning.enabled() {
+		if _, err := s.cron.AddFunc(s.pruning.Cron, s.pruneSnapshots); err != nil {
+			return fmt.Errorf("invalid snapshot prune cron expression: %w", err)
+		}
+	}
+
+	// Low stock report: products below their minimum quantity, by email
+	if s.lowStockCron != "" {
+		if _, err := s.cron.AddFunc(s.lowStockCron, s.checkLowStock); err != nil {
+			return fmt.Errorf("invalid low stock cron expression: %w", err)
+		}
+	}
+
 	s.cron.Start()
-	s.logger.Info("scheduler started", zap.String("cron_expression", cronExpr))
+	s.logger.Info("scheduler started",
+		zap.String("cron_expression", cronExpr),
+		zap.String("digest_cron_expression", s.digestCron),
+		zap.Bool("snapshot_prune", s.pruning.enabled()),
+		zap.String("low_stock_cron_expression", s.lowStockCron),
+	)
+
+	if missing := s.MissingRecipients(); len(missing) > 0 {
+		s.logger.Warn("scheduler notifications without recipients will be dropped",
+			zap.Strings("notifications", missing),
+		)
+	}
 
 	return nil
 }
@@ -64,23 +189,56 @@ func (s *Scheduler) Stop() context.Context {
 	return s.cron.Stop()
 }
 
-// runLifecycleUpdateJob fetches all unique product codes and submits crawling jobs
+// runLifecycleUpdateJob is the cron entry of the lifecycle update; skipped while a run is in progress
 func (s *Scheduler) runLifecycleUpdateJob() {
-	s.logger.Info("starting lifecycle update job")
+	runID, ok := s.beginRun()
+	if !ok {
+		s.logger.Warn("skipping lifecycle update job, previous run still in progress")
+		return
+	}
+	s.runLifecycleUpdate(runID)
+}
+
+// beginRun claims the run guard and returns the id of the new run; false when a run is in progress
+func (s *Scheduler) beginRun() (pgtype.UUID, bool) {
+	if !s.running.CompareAndSwap(false, true) {
+		return pgtype.UUID{}, false
+	}
+	// Cada execucao recebe um ID gravado nos snapshots, para rastrear qual coleta gerou cada dado
+	return pgtype.UUID{Bytes: uuid.New(), Valid: true}, true
+}
+
+// runLifecycleUpdate fetches all unique product codes and submits crawling jobs,
+// releasing the run guard claimed by beginRun when done
+func (s *Scheduler) runLifecycleUpdate(runID pgtype.UUID) {
+	defer s.running.Store(false)
+	runIDStr := uuid.UUID(runID.Bytes).String()
+
+	s.logger.Info("starting lifecycle update job", zap.String("run_id", runIDStr))
 	startTime := time.Now()
 
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+	runTimeout := s.runTimeoutOrDefault()
+	ctx, cancel := context.WithTimeout(context.Background(), runTimeout)
 	defer cancel()
 
-	// Get all unique product codes to collect
-	productsToCollect, err := s.service.ListUniqueProductsToCollect(ctx)
+	// Get the unique product codes to collect (only the stale ones with recrawlAfter set)
+	productsToCollect, err := s.listProductsToCollect(ctx)
 	if err != nil {
 		s.notifyError("failed to list products to collect", err)
 		return
 	}
 
+	// Codes that keep failing wait a few runs before being crawled again
+	productsToCollect = s.skipBackedOff(ctx, productsToCollect)
+
+	// Products a timed out run left behind go first
+	previous := s.previousRun(ctx)
+	productsToCollect = s.prioritizeUncollected(productsToCollect, previous)
+
 	if len(productsToCollect) == 0 {
 		s.logger.Info("no products to collect")
+		// An empty list after a run that had products is a degraded run, not a quiet night
+		s.alertDegradedRun(runIDStr, checkRunHealth(0, 0, s.healthBaseline(previous), s.minSuccessPercent))
 		return
 	}
 
@@ -95,6 +253,8 @@ func (s *Scheduler) runLifecycleUpdateJob() {
 			ProductID:   p.ID,
 			ProductCode: p.Code,
 			ProductURL:  p.Url,
+			Source:      products.SnapshotSourceScheduler,
+			RunID:       runID,
 		})
 	}
 
@@ -108,19 +268,33 @@ func (s *Scheduler) runLifecycleUpdateJob() {
 	// Process results and collect lifecycle status changes
 	var successCount, errorCount int
 	var statusChanges []products.LifecycleStatusChange
+	var drifts []products.ExpectationDrift
+	drift := make(selectorDrift)
+	processed := make(map[string]bool, len(jobs))
+	expectations := s.loadExpectations(ctx)
+
+	// Results crawled before the run timeout still arrive after it; their saves are bounded by
+	// the database query timeout instead, so finished crawls aren't thrown away
+	saveCtx := context.WithoutCancel(ctx)
 
 	for result := range resultsChan {
+		processed[result.Job.ProductCode] = true
 		if result.Error != nil {
 			errorCount++
 			s.logger.Warn("crawl failed",
 				zap.String("code", result.Job.ProductCode),
+				zap.String("kind", string(result.ErrorKind)),
 				zap.Error(result.Error),
 			)
+			// Uma coleta cancelada (pool parando) nao conta para o backoff do codigo
+			if result.ErrorKind != products.KindCanceled {
+				s.recordCrawlFailure(saveCtx, result.Job.ProductCode, result.Error)
+			}
 			continue
 		}
 
 		// Save snapshot and update lifecycle status
-		change, err := s.service.SaveCrawlResult(ctx, result.Job, result.Data)
+		change, err := s.service.SaveCrawlResult(saveCtx, result.Job, result.Data)
 		if err != nil {
 			errorCount++
 			s.logger.Error("failed to save crawl result",
@@ -131,7 +305,13 @@ func (s *Scheduler) runLifecycleUpdateJob() {
 		}
 
 		// Collect status change if lifecycle status changed
-		if change != nil {
+		if change != nil && change.Snoozed {
+			s.logger.Info("lifecycle status changed, alert snoozed",
+				zap.String("code", change.ProductCode),
+				zap.String("old_status", change.OldStatus),
+				zap.String("new_status", change.NewStatus),
+			)
+		} else if change != nil {
 			statusChanges = append(statusChanges, *change)
 			s.logger.Info("lifecycle status changed",
 				zap.String("code", change.ProductCode),
@@ -140,6 +320,21 @@ func (s *Scheduler) runLifecycleUpdateJob() {
 			)
 		}
 
+		// Critical parts with a supplier expectation are checked against what was crawled
+		if expectation, ok := expectations[result.Job.ProductCode]; ok {
+			if d := products.CheckExpectation(expectation, result.Data.Status, time.Now()); d != nil {
+				drifts = append(drifts, *d)
+				s.logger.Warn("lifecycle diverges from expectation",
+					zap.String("code", d.ProductCode),
+					zap.String("kind", d.Kind),
+					zap.String("status", d.Status),
+					zap.String("expected_status", d.ExpectedStatus),
+					zap.String("expected_eol", d.ExpectedEOL),
+				)
+			}
+		}
+
+		drift.record(result.Data)
 		successCount++
 		s.logger.Debug("crawl succeeded",
 			zap.String("code", result.Job.ProductCode),
@@ -147,104 +342,341 @@ func (s *Scheduler) runLifecycleUpdateJob() {
 		)
 	}
 
+	// The results channel closes early when the run context expires; whatever
+	// wasn't processed by then is recorded so the next run starts with it
+	var uncollected []string
+	for _, job := range jobs {
+		if !processed[job.ProductCode] {
+			uncollected = append(uncollected, job.ProductCode)
+		}
+	}
+	timedOut := ctx.Err() != nil
+
+	health := checkRunHealth(len(productsToCollect), successCount, s.healthBaseline(previous), s.minSuccessPercent)
+	// Estourar o tempo sempre gera alerta: os resultados parciais ja foram salvos, mas a execucao ficou incompleta
+	if timedOut {
+		health.Problems = append(health.Problems, fmt.Sprintf("tempo limite de %s esgotado com %d de %d produtos sem coleta",
+			runTimeout, len(uncollected), len(productsToCollect)))
+	}
+
 	duration := time.Since(startTime)
-	s.logger.Info("lifecycle update job completed",
+	summary := []zap.Field{
+		zap.String("run_id", runIDStr),
 		zap.Int("total", len(productsToCollect)),
 		zap.Int("success", successCount),
 		zap.Int("errors", errorCount),
+		zap.Int("uncollected", len(uncollected)),
 		zap.Int("status_changes", len(statusChanges)),
+		zap.Int("expectation_drifts", len(drifts)),
 		zap.Duration("duration", duration),
-	)
+	}
+	summary = append(summary, health.fields()...)
+	if health.Degraded() {
+		s.logger.Warn("lifecycle update job completed degraded", append(summary, zap.Strings("problems", health.Problems))...)
+	} else {
+		s.logger.Info("lifecycle update job completed", summary...)
+	}
+	if timedOut {
+		s.logger.Warn("lifecycle update job timed out before collecting every product",
+			zap.String("run_id", runIDStr),
+			zap.Duration("timeout", runTimeout),
+			zap.Int("uncollected", len(uncollected)),
+		)
+	}
+
+	s.recordRun(products.CollectionRun{
+		RunID:            runID,
+		StartedAt:        startTime,
+		Total:            len(productsToCollect),
+		Succeeded:        successCount,
+		Failed:           errorCount,
+		UncollectedCodes: uncollected,
+		TimedOut:         timedOut,
+		StatusChanges:    len(statusChanges),
+	})
+
+	// Send lifecycle status changes to the channels routed for each status
+	s.dispatchStatusChanges(statusChanges, drifts)
+
+	s.checkSelectorDrift(drift)
+
+	s.alertDegradedRun(runIDStr, health)
+}
+
+// selectorDriftAlertRatio is the share of crawls using fallback selectors for a field
+// that triggers an alert, warning us before the primary selector breaks completely
+const selectorDriftAlertRatio = 0.5
+
+// selectorDrift counts, per field, how many crawls matched and how many needed a fallback selector
+type selectorDrift map[string]*struct{ matched, fallback int }
+
+func (d selectorDrift) record(data *products.CrawledData) {
+	if data == nil {
+		return
+	}
+	for field, match := range data.Matches {
+		if match.Selector == "" {
+			continue
+		}
+		counts, ok := d[field]
+		if !ok {
+			counts = &struct{ matched, fallback int }{}
+			d[field] = counts
+		}
+		counts.matched++
+		if match.Fallback {
+			counts.fallback++
+		}
+	}
+}
+
+// checkSelectorDrift logs fallback selector usage and alerts when it dominates a field
+func (s *Scheduler) checkSelectorDrift(drift selectorDrift) {
+	var alerts []string
+	for field, counts := range drift {
+		if counts.fallback == 0 {
+			continue
+		}
+
+		ratio := float64(counts.fallback) / float64(counts.matched)
+		s.logger.Warn("fallback selectors in use",
+			zap.String("field", field),
+			zap.Int("fallback", counts.fallback),
+			zap.Int("matched", counts.matched),
+			zap.Float64("ratio", ratio),
+		)
 
-	// Send email notification if there are lifecycle status changes
-	if len(statusChanges) > 0 {
-		s.sendStatusChangeEmail(statusChanges)
+		if ratio >= selectorDriftAlertRatio {
+			alerts = append(alerts, fmt.Sprintf("%s: %d de %d coletas usaram seletores alternativos", field, counts.fallback, counts.matched))
+		}
 	}
+
+	if len(alerts) > 0 {
+		sort.Strings(alerts)
+		s.notifyError("selector drift detected", fmt.Errorf("%s", strings.Join(alerts, "; ")))
+	}
+}
+
+// RunNow starts the lifecycle update job in the background (for manual triggers) and returns
+// the id of the run, or ErrRunInProgress when a run hasn't finished yet
+func (s *Scheduler) RunNow() (string, error) {
+	runID, ok := s.beginRun()
+	if !ok {
+		return "", ErrRunInProgress
+	}
+	go s.runLifecycleUpdate(runID)
+	return uuid.UUID(runID.Bytes).String(), nil
+}
+
+// sendStatusChangeEmail sends an email notification with all lifecycle status changes,
+// returning the error of a failed send
+func (s *Scheduler) sendStatusChangeEmail(changes []products.LifecycleStatusChange) error {
+	return s.sendChangesEmail(s.t("Mudança de Lifecycle de equipamentos detectada"), changes)
 }
 
-// RunNow executes the lifecycle update job immediately (for manual triggers)
-func (s *Scheduler) RunNow() {
-	go s.runLifecycleUpdateJob()
+// sendChangesEmail sends the status change report with subject, returning the error of a failed
+// send or errNotificationDropped when the empty recipients policy dropped it
+func (s *Scheduler) sendChangesEmail(subject string, changes []products.LifecycleStatusChange) error {
+	return s.sendReport(subject, statusChangesData{Changes: changes})
 }
 
-// sendStatusChangeEmail sends an email notification with all lifecycle status changes
-func (s *Scheduler) sendStatusChangeEmail(changes []products.LifecycleStatusChange) {
+// sendRunReport sends the report of a run: its status changes and the expectation drifts it found
+func (s *Scheduler) sendRunReport(changes []products.LifecycleStatusChange, drifts []products.ExpectationDrift) {
+	subject := s.t("Mudança de Lifecycle de equipamentos detectada")
 	if len(changes) == 0 {
-		return
+		subject = s.t("Divergência do lifecycle esperado detectada")
 	}
+	s.sendReport(subject, statusChangesData{Changes: changes, Drifts: drifts})
+}
 
-	subject := "Mudança de Lifecycle de equipamentos detectada"
+// sendReport renders and sends the status change report, skipping it when there is nothing to report.
+// Returns errNotificationDropped when there is no one to send it to.
+func (s *Scheduler) sendReport(subject string, data statusChangesData) error {
+	if len(data.Changes) == 0 && len(data.Drifts) == 0 {
+		return nil
+	}
 
-	// Build plain text version
-	var textBuilder string
-	textBuilder = "Os seguintes equipamentos mudaram o  lifecycle status:\n\n"
-	for _, change := range changes {
-		textBuilder += "Product: " + change.ProductCode + "\n"
-		textBuilder += "  Old Status: " + change.OldStatus + "\n"
-		textBuilder += "  New Status: " + change.NewStatus + "\n\n"
-	}
-
-	// Build HTML version
-	var htmlBuilder string
-	htmlBuilder = `<!DOCTYPE html>
-<html>
-<head>
-	<style>
-		body { font-family: Arial, sans-serif; }
-		table { border-collapse: collapse; width: 100%; margin-top: 20px; }
-		th, td { border: 1px solid #ddd; padding: 12px; text-align: left; }
-		th { background-color: #4CAF50; color: white; }
-		tr:nth-child(even) { background-color: #f2f2f2; }
-		h2 { color: #333; }
-	</style>
-</head>
-<body>
-	<h2>Mudanças no Lifecycle Detectadas</h2>
-	<p>Os seguintes produtos tiveram mudanças no lifecycle:</p>
-	<table>
-		<tr>
-			<th>Product Code</th>
-			<th>Status Antigo</th>
-			<th>Novo Status</th>
-		</tr>`
+	data.Changes = s.withReplacements(data.Changes)
 
-	for _, change := range changes {
-		htmlBuilder += "<tr>"
-		htmlBuilder += "<td>" + change.ProductCode + "</td>"
-		htmlBuilder += "<td>" + change.OldStatus + "</td>"
-		htmlBuilder += "<td>" + change.NewStatus + "</td>"
-		htmlBuilder += "</tr>"
+	textBody, htmlBody, err := s.renderEmail(statusChangesEmail, data)
+	if err != nil {
+		s.logger.Error("failed to render status change email", zap.Error(err))
+		return err
 	}
 
-	htmlBuilder += `
-	</table>
-</body>
-</html>`
-
-	// TODO: Configure recipient email addresses
-	recipients := []string{
-		"bruno.rc@outlook.com.br",
-		"freitasmatheusrn@gmail.com",
+	summary := make([]string, 0, len(data.Changes)+len(data.Drifts))
+	for _, change := range data.Changes {
+		summary = append(summary, fmt.Sprintf("%s: %s -> %s", change.ProductCode, change.OldStatus, change.NewStatus))
+	}
+	for _, drift := range data.Drifts {
+		summary = append(summary, fmt.Sprintf("%s: %s (%s)", drift.ProductCode, drift.Kind, drift.Status))
 	}
 
+	recipients := s.resolveRecipients("status_change", s.statusRecipients, zap.Strings("changes", summary))
 	if len(recipients) == 0 {
-		s.logger.Warn("no email recipients configured, skipping status change notification",
-			zap.Int("changes_count", len(changes)),
+		return errNotificationDropped
+	}
+
+	if err := s.email.Send(subject, textBody, htmlBody, recipients); err != nil {
+		s.logger.Error("failed to send status change email",
+			zap.Error(err),
+			zap.Int("changes_count", len(data.Changes)),
+			zap.Int("drifts_count", len(data.Drifts)),
+		)
+		return err
+	}
+
+	s.logger.Info("status change email sent successfully",
+		zap.Int("changes_count", len(data.Changes)),
+		zap.Int("drifts_count", len(data.Drifts)),
+		zap.Int("recipients_count", len(recipients)),
+	)
+	return nil
+}
+
+// skipBackedOff removes the codes still inside their failure backoff window
+func (s *Scheduler) skipBackedOff(ctx context.Context, toCollect []repo.ListUniqueProductCodesToCollectRow) []repo.ListUniqueProductCodesToCollectRow {
+	tracker, ok := s.service.(CrawlFailureTracker)
+	if !ok || s.backoff.Threshold <= 0 {
+		return toCollect
+	}
+
+	failures, err := tracker.ListCrawlFailures(ctx)
+	if err != nil {
+		s.logger.Warn("failed to list crawl failures, crawling every product", zap.Error(err))
+		return toCollect
+	}
+
+	now := time.Now()
+	backedOff := make(map[string]bool)
+	for _, failure := range failures {
+		if retryAt := s.backoff.retryAt(failure); retryAt.After(now) {
+			backedOff[failure.Code] = true
+		}
+	}
+	if len(backedOff) == 0 {
+		return toCollect
+	}
+
+	kept := make([]repo.ListUniqueProductCodesToCollectRow, 0, len(toCollect))
+	var skipped []string
+	for _, p := range toCollect {
+		if backedOff[p.Code] {
+			skipped = append(skipped, p.Code)
+			continue
+		}
+		kept = append(kept, p)
+	}
+
+	if len(skipped) > 0 {
+		s.logger.Info("skipping products in crawl backoff",
+			zap.Int("count", len(skipped)),
+			zap.Strings("codes", skipped),
 		)
+	}
+	return kept
+}
+
+// recordCrawlFailure persists the failure so repeated ones push the code into backoff
+func (s *Scheduler) recordCrawlFailure(ctx context.Context, code string, crawlErr error) {
+	tracker, ok := s.service.(CrawlFailureTracker)
+	if !ok {
 		return
 	}
 
-	if err := s.email.Send(subject, textBuilder, htmlBuilder, recipients); err != nil {
-		s.logger.Error("failed to send status change email",
+	failure, err := tracker.RecordCrawlFailure(ctx, code, crawlErr)
+	if err != nil {
+		s.logger.Warn("failed to record crawl failure",
+			zap.String("code", code),
 			zap.Error(err),
-			zap.Int("changes_count", len(changes)),
 		)
 		return
 	}
 
-	s.logger.Info("status change email sent successfully",
+	if retryAt := s.backoff.retryAt(failure); !retryAt.IsZero() {
+		s.logger.Info("product entered crawl backoff",
+			zap.String("code", code),
+			zap.Int32("consecutive_failures", failure.ConsecutiveFailures),
+			zap.Time("retry_at", retryAt),
+		)
+	}
+}
+
+// t translates a notification message to the configured locale
+func (s *Scheduler) t(message string) string {
+	return i18n.T(s.locale, message)
+}
+
+// dispatchStatusChanges sends each status change to the channels configured for its status.
+// Expectation drifts only go by email, in their own section of the run report.
+func (s *Scheduler) dispatchStatusChanges(changes []products.LifecycleStatusChange, drifts []products.ExpectationDrift) {
+	if len(changes) == 0 && len(drifts) == 0 {
+		return
+	}
+
+	byChannel := make(map[Channel][]products.LifecycleStatusChange)
+	for _, change := range changes {
+		for _, channel := range s.routes.channelsFor(change) {
+			byChannel[channel] = append(byChannel[channel], change)
+		}
+	}
+
+	emailChanges := byChannel[ChannelEmail]
+	if len(emailChanges) > 0 && s.digestCron != "" {
+		s.queueForDigest(emailChanges)
+		emailChanges = nil
+	}
+	// Drifts are checked again in every run, so they aren't queued for the digest
+	s.sendRunReport(emailChanges, drifts)
+
+	if routed := byChannel[ChannelSMS]; len(routed) > 0 {
+		s.sendStatusChangeMessage(ChannelSMS, s.sms, s.smsRecipients, routed)
+	}
+	if routed := byChannel[ChannelWebhook]; len(routed) > 0 {
+		s.sendStatusChangeMessage(ChannelWebhook, s.webhook, s.webhookURLs, routed)
+	}
+}
+
+// maxMessageChanges is how many changes are listed in a sms/webhook message before summarizing the rest
+const maxMessageChanges = 5
+
+// sendStatusChangeMessage sends a short text with the changes to every recipient of a sms/webhook channel
+func (s *Scheduler) sendStatusChangeMessage(channel Channel, sender notification.Notification, recipients []string, changes []products.LifecycleStatusChange) {
+	summary := make([]string, 0, len(changes))
+	for _, change := range changes {
+		summary = append(summary, fmt.Sprintf("%s: %s -> %s", change.ProductCode, change.OldStatus, change.NewStatus))
+	}
+
+	if sender == nil || len(recipients) == 0 {
+		s.logger.Warn("status change channel not configured, notification dropped",
+			zap.String("channel", string(channel)),
+			zap.Strings("changes", summary),
+		)
+		return
+	}
+
+	lines := summary
+	if len(lines) > maxMessageChanges {
+		lines = append(lines[:maxMessageChanges:maxMessageChanges], fmt.Sprintf(s.t("... e mais %d"), len(summary)-maxMessageChanges))
+	}
+	msg := fmt.Sprintf(s.t("Lifecycle: %d mudanca(s)"), len(changes)) + "\n" + strings.Join(lines, "\n")
+
+	sent := 0
+	for _, to := range recipients {
+		if err := sender.Send(to, msg); err != nil {
+			s.logger.Error("failed to send status change notification",
+				zap.String("channel", string(channel)),
+				zap.Error(err),
+			)
+			continue
+		}
+		sent++
+	}
+
+	s.logger.Info("status change notification sent",
+		zap.String("channel", string(channel)),
 		zap.Int("changes_count", len(changes)),
-		zap.Int("recipients_count", len(recipients)),
+		zap.Int("recipients_count", sent),
 	)
 }
 
@@ -252,39 +684,63 @@ func (s *Scheduler) sendStatusChangeEmail(changes []products.LifecycleStatusChan
 func (s *Scheduler) notifyError(context string, err error) {
 	s.logger.Error(context, zap.Error(err))
 
-	if len(s.alertRecipients) == 0 {
+	recipients := s.resolveRecipients("error_alert", s.alertRecipients,
+		zap.String("error_context", context),
+		zap.Error(err),
+	)
+	if len(recipients) == 0 {
 		return
 	}
 
-	subject := "⚠️ Erro no Scheduler - " + context
-	timestamp := time.Now().Format("2006-01-02 15:04:05")
-
-	textBody := fmt.Sprintf("Contexto: %s\nErro: %v\nHorário: %s", context, err, timestamp)
-
-	htmlBody := fmt.Sprintf(`<!DOCTYPE html>
-<html>
-<head>
-	<style>
-		body { font-family: Arial, sans-serif; }
-		.error-box { background-color: #ffebee; border-left: 4px solid #f44336; padding: 16px; margin: 20px 0; }
-		.label { font-weight: bold; color: #333; }
-		.value { color: #666; }
-	</style>
-</head>
-<body>
-	<h2 style="color: #f44336;">⚠️ Erro no Scheduler</h2>
-	<div class="error-box">
-		<p><span class="label">Contexto:</span> <span class="value">%s</span></p>
-		<p><span class="label">Erro:</span> <span class="value">%v</span></p>
-		<p><span class="label">Horário:</span> <span class="value">%s</span></p>
-	</div>
-</body>
-</html>`, context, err, timestamp)
-
-	if sendErr := s.email.Send(subject, textBody, htmlBody, s.alertRecipients); sendErr != nil {
+	subject := "⚠️ " + s.t("Erro no Scheduler") + " - " + context
+
+	textBody, htmlBody, renderErr := s.renderEmail(errorAlertEmail, newErrorAlertData(context, err))
+	if renderErr != nil {
+		s.logger.Error("failed to render error notification email", zap.Error(renderErr))
+		return
+	}
+
+	if sendErr := s.email.Send(subject, textBody, htmlBody, recipients); sendErr != nil {
 		s.logger.Error("failed to send error notification email",
 			zap.Error(sendErr),
 			zap.String("original_error_context", context),
 		)
 	}
 }
+
+// resolveRecipients returns who should receive a notification, applying the empty recipients
+// policy when the list is empty. Returns nil (after logging the summary) when it must be dropped.
+func (s *Scheduler) resolveRecipients(notification string, recipients []string, summary ...zap.Field) []string {
+	if len(recipients) > 0 {
+		return recipients
+	}
+
+	if s.emptyRecipientsPolicy == EmptyRecipientsFallback && len(s.fallbackRecipients) > 0 {
+		s.logger.Warn("no recipients configured, sending notification to fallback recipients",
+			zap.String("notification", notification),
+		)
+		return s.fallbackRecipients
+	}
+
+	fields := append([]zap.Field{zap.String("notification", notification)}, summary...)
+	s.logger.Warn("no recipients configured, notification dropped", fields...)
+	return nil
+}
+
+// MissingRecipients lists the notifications that would be dropped because no recipient
+// (or fallback) is configured for them
+func (s *Scheduler) MissingRecipients() []string {
+	hasFallback := s.emptyRecipientsPolicy == EmptyRecipientsFallback && len(s.fallbackRecipients) > 0
+
+	var missing []string
+	if len(s.statusRecipients) == 0 && !hasFallback {
+		missing = append(missing, "status_change")
+	}
+	if len(s.alertRecipients) == 0 && !hasFallback {
+		missing = append(missing, "error_alert")
+	}
+	if s.lowStockCron != "" && len(s.lowStockRecipientsOrDefault()) == 0 && !hasFallback {
+		missing = append(missing, "low_stock")
+	}
+	return missing
+}
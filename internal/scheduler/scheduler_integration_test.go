@@ -45,8 +45,9 @@ func TestSendRealEmail_Integration(t *testing.T) {
 
 	// Create scheduler with real email client
 	scheduler := &Scheduler{
-		logger: logger,
-		email:  emailClient,
+		logger:           logger,
+		email:            emailClient,
+		statusRecipients: recipients,
 	}
 
 	// Simulate status changes
@@ -71,57 +72,8 @@ func TestSendRealEmail_Integration(t *testing.T) {
 	// Send the email
 	t.Log("Sending test email to:", recipients)
 
-	// We need to temporarily override the recipients in sendStatusChangeEmail
-	// Since we can't modify it, let's send directly using the same format
-
-	subject := "Mudança de Lifecycle de equipamentos detectada"
-
-	// Build plain text version (same as scheduler)
-	var textBuilder string
-	textBuilder = "Os seguintes equipamentos mudaram o  lifecycle status:\n\n"
-	for _, change := range changes {
-		textBuilder += "Product: " + change.ProductCode + "\n"
-		textBuilder += "  Old Status: " + change.OldStatus + "\n"
-		textBuilder += "  New Status: " + change.NewStatus + "\n\n"
-	}
-
-	// Build HTML version (same as scheduler)
-	htmlBuilder := `<!DOCTYPE html>
-<html>
-<head>
-	<style>
-		body { font-family: Arial, sans-serif; }
-		table { border-collapse: collapse; width: 100%; margin-top: 20px; }
-		th, td { border: 1px solid #ddd; padding: 12px; text-align: left; }
-		th { background-color: #4CAF50; color: white; }
-		tr:nth-child(even) { background-color: #f2f2f2; }
-		h2 { color: #333; }
-	</style>
-</head>
-<body>
-	<h2>Lifecycle Status Changes Detected</h2>
-	<p>The following products have changed their lifecycle status:</p>
-	<table>
-		<tr>
-			<th>Product Code</th>
-			<th>Status Antigo</th>
-			<th>Novo Status</th>
-		</tr>`
-
-	for _, change := range changes {
-		htmlBuilder += "<tr>"
-		htmlBuilder += "<td>" + change.ProductCode + "</td>"
-		htmlBuilder += "<td>" + change.OldStatus + "</td>"
-		htmlBuilder += "<td>" + change.NewStatus + "</td>"
-		htmlBuilder += "</tr>"
-	}
-
-	htmlBuilder += `
-	</table>
-</body>
-</html>`
-
-	err = scheduler.email.Send(subject, textBuilder, htmlBuilder, recipients)
+	// Same rendering as the scheduled run
+	err = scheduler.sendChangesEmail("Mudança de Lifecycle de equipamentos detectada", changes)
 	if err != nil {
 		t.Fatalf("Failed to send email: %v", err)
 	}
@@ -5,9 +5,11 @@ import (
 	"errors"
 	"sync"
 	"testing"
+	"time"
 
 	repo "github.com/freitasmatheusrn/lifecycle-monitor/internal/database/postgres/sqlc"
 	"github.com/freitasmatheusrn/lifecycle-monitor/internal/products"
+	"github.com/freitasmatheusrn/lifecycle-monitor/pkg/i18n"
 	"github.com/jackc/pgx/v5/pgtype"
 	"go.uber.org/zap"
 )
@@ -59,6 +61,9 @@ func (m *MockProductCollector) SaveCrawlResult(ctx context.Context, job products
 	return nil, nil
 }
 
+// testRecipients is the status change recipient list used by the tests that expect an email
+var testRecipients = []string{"ops@example.com"}
+
 // MockEmail implements email.Email for testing
 type MockEmail struct {
 	sentEmails []SentEmail
@@ -176,10 +181,11 @@ func TestRunLifecycleUpdateJob_WithStatusChange(t *testing.T) {
 	}
 
 	scheduler := &Scheduler{
-		workerPool: mockWorkerPool,
-		service:    mockCollector,
-		logger:     logger,
-		email:      mockEmail,
+		workerPool:       mockWorkerPool,
+		service:          mockCollector,
+		logger:           logger,
+		email:            mockEmail,
+		statusRecipients: testRecipients,
 	}
 
 	// Run the job directly (not through cron)
@@ -252,10 +258,11 @@ func TestRunLifecycleUpdateJob_NoStatusChange(t *testing.T) {
 	}
 
 	scheduler := &Scheduler{
-		workerPool: mockWorkerPool,
-		service:    mockCollector,
-		logger:     logger,
-		email:      mockEmail,
+		workerPool:       mockWorkerPool,
+		service:          mockCollector,
+		logger:           logger,
+		email:            mockEmail,
+		statusRecipients: testRecipients,
 	}
 
 	scheduler.runLifecycleUpdateJob()
@@ -315,10 +322,11 @@ func TestRunLifecycleUpdateJob_MultipleStatusChanges(t *testing.T) {
 	}
 
 	scheduler := &Scheduler{
-		workerPool: mockWorkerPool,
-		service:    mockCollector,
-		logger:     logger,
-		email:      mockEmail,
+		workerPool:       mockWorkerPool,
+		service:          mockCollector,
+		logger:           logger,
+		email:            mockEmail,
+		statusRecipients: testRecipients,
 	}
 
 	scheduler.runLifecycleUpdateJob()
@@ -383,10 +391,11 @@ func TestRunLifecycleUpdateJob_CrawlError(t *testing.T) {
 	}
 
 	scheduler := &Scheduler{
-		workerPool: mockWorkerPool,
-		service:    mockCollector,
-		logger:     logger,
-		email:      mockEmail,
+		workerPool:       mockWorkerPool,
+		service:          mockCollector,
+		logger:           logger,
+		email:            mockEmail,
+		statusRecipients: testRecipients,
 	}
 
 	scheduler.runLifecycleUpdateJob()
@@ -420,10 +429,11 @@ func TestRunLifecycleUpdateJob_NoProducts(t *testing.T) {
 	mockEmail := &MockEmail{}
 
 	scheduler := &Scheduler{
-		workerPool: &MockBatchSubmitter{},
-		service:    mockCollector,
-		logger:     logger,
-		email:      mockEmail,
+		workerPool:       &MockBatchSubmitter{},
+		service:          mockCollector,
+		logger:           logger,
+		email:            mockEmail,
+		statusRecipients: testRecipients,
 	}
 
 	scheduler.runLifecycleUpdateJob()
@@ -448,10 +458,11 @@ func TestRunLifecycleUpdateJob_ListProductsError(t *testing.T) {
 	mockEmail := &MockEmail{}
 
 	scheduler := &Scheduler{
-		workerPool: &MockBatchSubmitter{},
-		service:    mockCollector,
-		logger:     logger,
-		email:      mockEmail,
+		workerPool:       &MockBatchSubmitter{},
+		service:          mockCollector,
+		logger:           logger,
+		email:            mockEmail,
+		statusRecipients: testRecipients,
 	}
 
 	// Should not panic and should not send email
@@ -484,10 +495,11 @@ func TestRunLifecycleUpdateJob_SubmitBatchError(t *testing.T) {
 	}
 
 	scheduler := &Scheduler{
-		workerPool: mockWorkerPool,
-		service:    mockCollector,
-		logger:     logger,
-		email:      mockEmail,
+		workerPool:       mockWorkerPool,
+		service:          mockCollector,
+		logger:           logger,
+		email:            mockEmail,
+		statusRecipients: testRecipients,
 	}
 
 	// Should not panic and should not send email
@@ -547,10 +559,11 @@ func TestRunLifecycleUpdateJob_SaveCrawlResultError(t *testing.T) {
 	}
 
 	scheduler := &Scheduler{
-		workerPool: mockWorkerPool,
-		service:    mockCollector,
-		logger:     logger,
-		email:      mockEmail,
+		workerPool:       mockWorkerPool,
+		service:          mockCollector,
+		logger:           logger,
+		email:            mockEmail,
+		statusRecipients: testRecipients,
 	}
 
 	scheduler.runLifecycleUpdateJob()
@@ -570,8 +583,9 @@ func TestSendStatusChangeEmail_HTMLContent(t *testing.T) {
 	mockEmail := &MockEmail{}
 
 	scheduler := &Scheduler{
-		logger: logger,
-		email:  mockEmail,
+		logger:           logger,
+		email:            mockEmail,
+		statusRecipients: testRecipients,
 	}
 
 	changes := []products.LifecycleStatusChange{
@@ -612,8 +626,9 @@ func TestSendStatusChangeEmail_EmptyChanges(t *testing.T) {
 	mockEmail := &MockEmail{}
 
 	scheduler := &Scheduler{
-		logger: logger,
-		email:  mockEmail,
+		logger:           logger,
+		email:            mockEmail,
+		statusRecipients: testRecipients,
 	}
 
 	// Should not send email for empty changes
@@ -635,8 +650,9 @@ func TestSendStatusChangeEmail_EmailError(t *testing.T) {
 	}
 
 	scheduler := &Scheduler{
-		logger: logger,
-		email:  mockEmail,
+		logger:           logger,
+		email:            mockEmail,
+		statusRecipients: testRecipients,
 	}
 
 	changes := []products.LifecycleStatusChange{
@@ -656,6 +672,101 @@ func TestSendStatusChangeEmail_EmailError(t *testing.T) {
 	}
 }
 
+func TestSendStatusChangeEmail_NoRecipients_Dropped(t *testing.T) {
+	mockEmail := &MockEmail{}
+
+	scheduler := &Scheduler{
+		logger:                zap.NewNop(),
+		email:                 mockEmail,
+		emptyRecipientsPolicy: EmptyRecipientsWarn,
+	}
+
+	changes := []products.LifecycleStatusChange{
+		{ProductCode: "PROD-001", OldStatus: "Active", NewStatus: "Discontinued"},
+	}
+
+	scheduler.sendStatusChangeEmail(changes)
+
+	mockEmail.mu.Lock()
+	defer mockEmail.mu.Unlock()
+
+	if len(mockEmail.sentEmails) != 0 {
+		t.Errorf("expected no email without recipients, got %d", len(mockEmail.sentEmails))
+	}
+}
+
+func TestSendStatusChangeEmail_NoRecipients_Fallback(t *testing.T) {
+	mockEmail := &MockEmail{}
+
+	scheduler := &Scheduler{
+		logger:                zap.NewNop(),
+		email:                 mockEmail,
+		fallbackRecipients:    []string{"fallback@example.com"},
+		emptyRecipientsPolicy: EmptyRecipientsFallback,
+	}
+
+	changes := []products.LifecycleStatusChange{
+		{ProductCode: "PROD-001", OldStatus: "Active", NewStatus: "Discontinued"},
+	}
+
+	scheduler.sendStatusChangeEmail(changes)
+
+	mockEmail.mu.Lock()
+	defer mockEmail.mu.Unlock()
+
+	if len(mockEmail.sentEmails) != 1 {
+		t.Fatalf("expected 1 email sent to fallback, got %d", len(mockEmail.sentEmails))
+	}
+	if got := mockEmail.sentEmails[0].Recipients; len(got) != 1 || got[0] != "fallback@example.com" {
+		t.Errorf("expected email to fallback recipients, got %v", got)
+	}
+}
+
+func TestNotifyError_NoRecipients_Fallback(t *testing.T) {
+	mockEmail := &MockEmail{}
+
+	scheduler := &Scheduler{
+		logger:                zap.NewNop(),
+		email:                 mockEmail,
+		fallbackRecipients:    []string{"fallback@example.com"},
+		emptyRecipientsPolicy: EmptyRecipientsFallback,
+	}
+
+	scheduler.notifyError("failed to list products to collect", errors.New("database error"))
+
+	mockEmail.mu.Lock()
+	defer mockEmail.mu.Unlock()
+
+	if len(mockEmail.sentEmails) != 1 {
+		t.Fatalf("expected 1 alert sent to fallback, got %d", len(mockEmail.sentEmails))
+	}
+}
+
+func TestMissingRecipients(t *testing.T) {
+	scheduler := &Scheduler{
+		statusRecipients: testRecipients,
+	}
+	if missing := scheduler.MissingRecipients(); len(missing) != 1 || missing[0] != "error_alert" {
+		t.Errorf("expected only error_alert to be missing, got %v", missing)
+	}
+
+	// O relatorio de estoque baixo usa os destinatarios de mudanca de status
+	scheduler.lowStockCron = "0 0 7 * * *"
+	if missing := scheduler.MissingRecipients(); len(missing) != 1 || missing[0] != "error_alert" {
+		t.Errorf("expected low_stock to use the status change recipients, got %v", missing)
+	}
+	scheduler.statusRecipients = nil
+	if missing := scheduler.MissingRecipients(); len(missing) != 3 || missing[2] != "low_stock" {
+		t.Errorf("expected low_stock to be missing, got %v", missing)
+	}
+
+	scheduler.fallbackRecipients = []string{"fallback@example.com"}
+	scheduler.emptyRecipientsPolicy = EmptyRecipientsFallback
+	if missing := scheduler.MissingRecipients(); len(missing) != 0 {
+		t.Errorf("expected no missing recipients with fallback, got %v", missing)
+	}
+}
+
 // containsString checks if s contains substr
 func containsString(s, substr string) bool {
 	for i := 0; i <= len(s)-len(substr); i++ {
@@ -665,3 +776,289 @@ func containsString(s, substr string) bool {
 	}
 	return false
 }
+
+func TestCheckSelectorDrift_AlertsWhenFallbackDominates(t *testing.T) {
+	mockEmail := &MockEmail{}
+
+	scheduler := &Scheduler{
+		logger:          zap.NewNop(),
+		email:           mockEmail,
+		alertRecipients: []string{"alerts@example.com"},
+	}
+
+	drift := make(selectorDrift)
+	drift.record(&products.CrawledData{Matches: map[string]products.FieldMatch{
+		products.FieldDescription: {Selector: "description.intro-section", Fallback: true},
+	}})
+	drift.record(&products.CrawledData{Matches: map[string]products.FieldMatch{
+		products.FieldDescription: {Selector: "description.headline"},
+		products.FieldStatus:      {Selector: "status.metadata-item"},
+	}})
+
+	scheduler.checkSelectorDrift(drift)
+
+	mockEmail.mu.Lock()
+	defer mockEmail.mu.Unlock()
+
+	if len(mockEmail.sentEmails) != 1 {
+		t.Fatalf("expected 1 drift alert, got %d", len(mockEmail.sentEmails))
+	}
+	if !containsString(mockEmail.sentEmails[0].Text, "description: 1 de 2") {
+		t.Errorf("expected alert to mention the drifting field, got %q", mockEmail.sentEmails[0].Text)
+	}
+}
+
+func TestCheckSelectorDrift_NoAlertBelowRatio(t *testing.T) {
+	mockEmail := &MockEmail{}
+
+	scheduler := &Scheduler{
+		logger:          zap.NewNop(),
+		email:           mockEmail,
+		alertRecipients: []string{"alerts@example.com"},
+	}
+
+	drift := make(selectorDrift)
+	for i := 0; i < 3; i++ {
+		drift.record(&products.CrawledData{Matches: map[string]products.FieldMatch{
+			products.FieldDescription: {Selector: "description.headline"},
+		}})
+	}
+	drift.record(&products.CrawledData{Matches: map[string]products.FieldMatch{
+		products.FieldDescription: {Selector: "description.intro-section", Fallback: true},
+	}})
+
+	scheduler.checkSelectorDrift(drift)
+
+	if len(mockEmail.sentEmails) != 0 {
+		t.Errorf("expected no alert below the drift ratio, got %d", len(mockEmail.sentEmails))
+	}
+}
+
+func TestRunLifecycleUpdateJob_SnoozedStatusChange(t *testing.T) {
+	mockService := &MockProductCollector{
+		products: []repo.ListUniqueProductCodesToCollectRow{
+			{Code: "PROD-001", ID: pgtype.UUID{Valid: true}},
+		},
+		statusChanges: map[string]*products.LifecycleStatusChange{
+			"PROD-001": {ProductCode: "PROD-001", OldStatus: "Active", NewStatus: "Phase Out", Snoozed: true},
+		},
+	}
+	mockEmail := &MockEmail{}
+
+	scheduler := &Scheduler{
+		workerPool:       &MockBatchSubmitter{},
+		service:          mockService,
+		logger:           zap.NewNop(),
+		email:            mockEmail,
+		statusRecipients: testRecipients,
+	}
+
+	scheduler.runLifecycleUpdateJob()
+
+	if len(mockService.savedResults) != 1 {
+		t.Errorf("expected snoozed product to still be saved, got %d results", len(mockService.savedResults))
+	}
+	if len(mockEmail.sentEmails) != 0 {
+		t.Errorf("expected no email for snoozed status change, got %d", len(mockEmail.sentEmails))
+	}
+}
+
+func TestRunLifecycleUpdateJob_TagsJobsWithRun(t *testing.T) {
+	mockService := &MockProductCollector{
+		products: []repo.ListUniqueProductCodesToCollectRow{
+			{Code: "PROD-001", ID: pgtype.UUID{Bytes: [16]byte{1}, Valid: true}},
+			{Code: "PROD-002", ID: pgtype.UUID{Bytes: [16]byte{2}, Valid: true}},
+		},
+	}
+
+	scheduler := &Scheduler{
+		workerPool: &MockBatchSubmitter{},
+		service:    mockService,
+		logger:     zap.NewNop(),
+		email:      &MockEmail{},
+	}
+
+	scheduler.runLifecycleUpdateJob()
+
+	if len(mockService.savedResults) != 2 {
+		t.Fatalf("expected 2 saved results, got %d", len(mockService.savedResults))
+	}
+
+	runID := mockService.savedResults[0].Job.RunID
+	if !runID.Valid {
+		t.Fatal("expected jobs to carry the scheduler run id")
+	}
+	for _, saved := range mockService.savedResults {
+		if saved.Job.Source != products.SnapshotSourceScheduler {
+			t.Errorf("expected source %q, got %q", products.SnapshotSourceScheduler, saved.Job.Source)
+		}
+		if saved.Job.RunID != runID {
+			t.Errorf("expected all jobs of a run to share the run id")
+		}
+	}
+}
+
+// MockNotification implements notification.Notification for testing
+type MockNotification struct {
+	sent []SentNotification
+	mu   sync.Mutex
+}
+
+type SentNotification struct {
+	To  string
+	Msg string
+}
+
+func (m *MockNotification) Send(to, msg string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.sent = append(m.sent, SentNotification{To: to, Msg: msg})
+	return nil
+}
+
+func TestDispatchStatusChanges_RoutesByStatus(t *testing.T) {
+	mockEmail := &MockEmail{}
+	mockSMS := &MockNotification{}
+	mockWebhook := &MockNotification{}
+
+	routes, err := ParseStatusRoutes("Prod. Discont.=sms,webhook;Active>Phase Out Announce=email,webhook", []string{"email"}, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	scheduler := &Scheduler{
+		logger:           zap.NewNop(),
+		email:            mockEmail,
+		statusRecipients: testRecipients,
+		routes:           routes,
+		sms:              mockSMS,
+		smsRecipients:    []string{"84999990000"},
+		webhook:          mockWebhook,
+		webhookURLs:      []string{"https://hooks.example.com/a", "https://hooks.example.com/b"},
+	}
+
+	scheduler.dispatchStatusChanges([]products.LifecycleStatusChange{
+		{ProductCode: "PROD-001", OldStatus: "Active", NewStatus: "Prod. Discont."},
+		{ProductCode: "PROD-002", OldStatus: "Active", NewStatus: "Phase Out Announce"},
+		{ProductCode: "PROD-003", OldStatus: "Active", NewStatus: "Prod. Cancellation"},
+	}, nil)
+
+	if len(mockEmail.sentEmails) != 1 {
+		t.Fatalf("expected 1 email, got %d", len(mockEmail.sentEmails))
+	}
+	if html := mockEmail.sentEmails[0].HTML; containsString(html, "PROD-001") || !containsString(html, "PROD-002") || !containsString(html, "PROD-003") {
+		t.Error("email should contain only PROD-002 and PROD-003")
+	}
+
+	if len(mockSMS.sent) != 1 {
+		t.Fatalf("expected 1 sms, got %d", len(mockSMS.sent))
+	}
+	if msg := mockSMS.sent[0].Msg; !containsString(msg, "PROD-001") || containsString(msg, "PROD-002") {
+		t.Errorf("sms should contain only PROD-001, got %q", msg)
+	}
+
+	if len(mockWebhook.sent) != 2 {
+		t.Fatalf("expected 2 webhook calls, got %d", len(mockWebhook.sent))
+	}
+	if msg := mockWebhook.sent[0].Msg; !containsString(msg, "PROD-001") || !containsString(msg, "PROD-002") || containsString(msg, "PROD-003") {
+		t.Errorf("webhook should contain PROD-001 and PROD-002, got %q", msg)
+	}
+}
+
+func TestDispatchStatusChanges_UnconfiguredChannelDropped(t *testing.T) {
+	mockEmail := &MockEmail{}
+
+	routes, err := ParseStatusRoutes("Prod. Discont.=sms", nil, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	scheduler := &Scheduler{
+		logger:           zap.NewNop(),
+		email:            mockEmail,
+		statusRecipients: testRecipients,
+		routes:           routes,
+	}
+
+	scheduler.dispatchStatusChanges([]products.LifecycleStatusChange{
+		{ProductCode: "PROD-001", OldStatus: "Active", NewStatus: "Prod. Discont."},
+	}, nil)
+
+	if len(mockEmail.sentEmails) != 0 {
+		t.Errorf("expected no email for a change routed to sms only, got %d", len(mockEmail.sentEmails))
+	}
+}
+
+func TestSendStatusChangeEmail_EnglishLocale(t *testing.T) {
+	mockEmail := &MockEmail{}
+
+	scheduler := &Scheduler{
+		logger:           zap.NewNop(),
+		email:            mockEmail,
+		statusRecipients: testRecipients,
+		locale:           i18n.English,
+	}
+
+	scheduler.sendStatusChangeEmail([]products.LifecycleStatusChange{
+		{ProductCode: "PROD-001", OldStatus: "Active", NewStatus: "Phase Out"},
+	})
+
+	if len(mockEmail.sentEmails) != 1 {
+		t.Fatalf("expected 1 email, got %d", len(mockEmail.sentEmails))
+	}
+
+	email := mockEmail.sentEmails[0]
+	if email.Subject != "Equipment lifecycle change detected" {
+		t.Errorf("unexpected subject: %q", email.Subject)
+	}
+	if !containsString(email.HTML, "<th>Old Status</th>") || containsString(email.HTML, "Status Antigo") {
+		t.Error("HTML should use the English table headers")
+	}
+}
+
+func TestRunNow_RejectsOverlappingRuns(t *testing.T) {
+	release := make(chan struct{})
+	saved := make(chan struct{}, 10)
+	mockCollector := &MockProductCollector{
+		products: []repo.ListUniqueProductCodesToCollectRow{{Code: "PROD-001"}},
+		customSaveFunc: func(ctx context.Context, job products.CrawlerJob, data *products.CrawledData) (*products.LifecycleStatusChange, error) {
+			saved <- struct{}{}
+			<-release
+			return nil, nil
+		},
+	}
+
+	scheduler := &Scheduler{
+		workerPool: &MockBatchSubmitter{},
+		service:    mockCollector,
+		logger:     zap.NewNop(),
+		email:      &MockEmail{},
+	}
+
+	runID, err := scheduler.RunNow()
+	if err != nil || runID == "" {
+		t.Fatalf("expected the run to start, got %q, %v", runID, err)
+	}
+	<-saved
+
+	// Um segundo disparo (manual ou do cron) nao sobrepoe a execucao em andamento
+	if _, err := scheduler.RunNow(); !errors.Is(err, ErrRunInProgress) {
+		t.Errorf("expected ErrRunInProgress, got %v", err)
+	}
+	scheduler.runLifecycleUpdateJob()
+	if len(saved) != 0 {
+		t.Error("expected the cron run to be skipped while a run is in progress")
+	}
+
+	close(release)
+	deadline := time.Now().Add(2 * time.Second)
+	for scheduler.running.Load() && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	if _, err := scheduler.RunNow(); err != nil {
+		t.Errorf("expected a new run after the previous one finished, got %v", err)
+	}
+	<-saved
+}
@@ -0,0 +1,35 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/freitasmatheusrn/lifecycle-monitor/internal/products"
+	"go.uber.org/zap"
+)
+
+// CrawlerSelfTester crawls a known-good product without saving it
+type CrawlerSelfTester interface {
+	CrawlerSelfTest(ctx context.Context, code string) *products.CrawlerSelfTestResult
+}
+
+// WarmUp crawls the canary product once after startup. The browser is then warm for the first
+// real crawl, and a broken environment or selector shows up at deploy time instead of in the
+// nightly run. A failure is alerted like a job error.
+func (s *Scheduler) WarmUp(ctx context.Context, tester CrawlerSelfTester, code string) {
+	result := tester.CrawlerSelfTest(ctx, code)
+
+	if result.Result == products.SelfTestPass {
+		s.logger.Info("crawler warm-up passed",
+			zap.String("code", result.Code),
+			zap.Int64("duration_ms", result.DurationMs),
+			zap.String("lifecycle_status", result.LifecycleStatus),
+			zap.Strings("warnings", result.Warnings),
+		)
+		return
+	}
+
+	s.notifyError("crawler warm-up failed", fmt.Errorf("%s (code %s, %d ms)",
+		strings.Join(result.Failures, "; "), result.Code, result.DurationMs))
+}
@@ -0,0 +1,62 @@
+package scheduler
+
+import (
+	"context"
+	"testing"
+
+	"github.com/freitasmatheusrn/lifecycle-monitor/internal/products"
+	"go.uber.org/zap"
+)
+
+// mockSelfTester returns a fixed self-test result
+type mockSelfTester struct {
+	result *products.CrawlerSelfTestResult
+}
+
+func (m mockSelfTester) CrawlerSelfTest(ctx context.Context, code string) *products.CrawlerSelfTestResult {
+	return m.result
+}
+
+func TestWarmUp(t *testing.T) {
+	tests := []struct {
+		name       string
+		result     *products.CrawlerSelfTestResult
+		wantAlerts int
+	}{
+		{
+			name:   "pass does not alert",
+			result: &products.CrawlerSelfTestResult{Result: products.SelfTestPass, Code: "6ES7214-1AG40-0XB0", DurationMs: 4200},
+		},
+		{
+			name: "fail alerts",
+			result: &products.CrawlerSelfTestResult{
+				Result:   products.SelfTestFail,
+				Code:     "6ES7214-1AG40-0XB0",
+				Failures: []string{"description: nenhum seletor encontrou valor"},
+			},
+			wantAlerts: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockEmail := &MockEmail{}
+			scheduler := &Scheduler{
+				logger:          zap.NewNop(),
+				email:           mockEmail,
+				alertRecipients: testRecipients,
+			}
+
+			scheduler.WarmUp(t.Context(), mockSelfTester{result: tt.result}, tt.result.Code)
+
+			mockEmail.mu.Lock()
+			defer mockEmail.mu.Unlock()
+			if len(mockEmail.sentEmails) != tt.wantAlerts {
+				t.Fatalf("expected %d alerts, got %d", tt.wantAlerts, len(mockEmail.sentEmails))
+			}
+			if tt.wantAlerts > 0 && !containsString(mockEmail.sentEmails[0].Text, "nenhum seletor encontrou valor") {
+				t.Errorf("expected failure reason in alert, got %q", mockEmail.sentEmails[0].Text)
+			}
+		})
+	}
+}
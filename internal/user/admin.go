@@ -0,0 +1,39 @@
+package user
+
+import (
+	"strings"
+
+	"github.com/freitasmatheusrn/lifecycle-monitor/pkg/rest"
+	"github.com/labstack/echo/v4"
+)
+
+// RequireAdmin only lets through users whose email is in adminEmails (case-insensitive).
+// Must run after the JWT middleware that sets the current user.
+// With an empty list every request is rejected, and so are requests authenticated by an API key.
+func RequireAdmin(adminEmails []string) echo.MiddlewareFunc {
+	admins := make(map[string]bool, len(adminEmails))
+	for _, email := range adminEmails {
+		if email = strings.ToLower(strings.TrimSpace(email)); email != "" {
+			admins[email] = true
+		}
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			currentUser, err := GetCurrentUser(c)
+			if err != nil {
+				return rest.NewUnauthorizedRequestError("usuario nao autenticado")
+			}
+
+			if currentUser.APIKeyID != "" {
+				return rest.NewForbiddenError("chaves de API nao acessam rotas administrativas")
+			}
+
+			if !admins[strings.ToLower(currentUser.Email)] {
+				return rest.NewForbiddenError("acesso restrito a administradores")
+			}
+
+			return next(c)
+		}
+	}
+}
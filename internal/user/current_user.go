@@ -8,9 +8,10 @@ import (
 )
 
 type CurrentUser struct {
-	ID    pgtype.UUID
-	Name  string
-	Email string
+	ID       pgtype.UUID
+	Name     string
+	Email    string
+	APIKeyID string // set when the request was authenticated by an API key instead of a session
 }
 
 func SetCurrentUser(c echo.Context, user CurrentUser) {
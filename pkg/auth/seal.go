@@ -0,0 +1,53 @@
+package auth
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+)
+
+// sealKey derives the AES-256 key for data sealed with a token. It differs from HashToken,
+// so the hash used as the storage key can't be used to open what is stored under it.
+func sealKey(token string) []byte {
+	mac := hmac.New(sha256.New, []byte(token))
+	mac.Write([]byte("lifecycle-monitor seal"))
+	return mac.Sum(nil)
+}
+
+// SealWithToken encrypts data with a key derived from the plain token (AES-GCM).
+// Only whoever holds the token can open it again, the stored hash alone is not enough.
+func SealWithToken(token string, data []byte) ([]byte, error) {
+	gcm, err := tokenCipher(token)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return gcm.Seal(nonce, nonce, data, nil), nil
+}
+
+// OpenWithToken decrypts data sealed by SealWithToken with the same token
+func OpenWithToken(token string, sealed []byte) ([]byte, error) {
+	gcm, err := tokenCipher(token)
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < gcm.NonceSize() {
+		return nil, errors.New("sealed data too short")
+	}
+	nonce, ciphertext := sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():]
+	return gcm.Open(nil, nonce, ciphertext, nil)
+}
+
+func tokenCipher(token string) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(sealKey(token))
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
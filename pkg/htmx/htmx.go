@@ -1,7 +1,7 @@
 package htmx
 
 import (
-	"fmt"
+	"encoding/json"
 	"net/http"
 
 	"github.com/a-h/templ"
@@ -67,17 +67,35 @@ func Refresh(c echo.Context) error {
 	return c.NoContent(http.StatusOK)
 }
 
+// toastTrigger is the HX-Trigger payload handled by the makeToast listener in toast.js
+type toastTrigger struct {
+	MakeToast struct {
+		Level   string `json:"level"`
+		Message string `json:"message"`
+	} `json:"makeToast"`
+}
+
+// ToastTrigger returns the HX-Trigger value of a toast. The message is JSON-encoded, so quotes,
+// backslashes and line breaks coming from errors or product codes can't break the payload.
+func ToastTrigger(level, message string) string {
+	var trigger toastTrigger
+	trigger.MakeToast.Level = level
+	trigger.MakeToast.Message = message
+
+	// Marshal only fails for unsupported types; two strings always encode
+	payload, _ := json.Marshal(trigger)
+	return string(payload)
+}
+
 // TriggerToast sends a toast notification via HX-Trigger header
 // level can be: "success", "danger", "warning", "info"
 func TriggerToast(c echo.Context, level, message string) {
-	trigger := fmt.Sprintf(`{"makeToast": {"level": "%s", "message": "%s"}}`, level, message)
-	c.Response().Header().Set(HXTrigger, trigger)
+	c.Response().Header().Set(HXTrigger, ToastTrigger(level, message))
 }
 
 // TriggerToastAfterSettle sends a toast notification after HTMX settles the DOM
 func TriggerToastAfterSettle(c echo.Context, level, message string) {
-	trigger := fmt.Sprintf(`{"makeToast": {"level": "%s", "message": "%s"}}`, level, message)
-	c.Response().Header().Set(HXTriggerAfter, trigger)
+	c.Response().Header().Set(HXTriggerAfter, ToastTrigger(level, message))
 }
 
 // RedirectWithToast performs a redirect and shows a toast on the target page
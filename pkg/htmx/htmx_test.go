@@ -0,0 +1,26 @@
+package htmx
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+func TestTriggerToast_EncodesMessage(t *testing.T) {
+	message := `produto "6ES7\214" nao encontrado` + "\n" + `"}, "evil": {"x": "`
+
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec)
+	TriggerToast(c, "danger", message)
+
+	var payload map[string]map[string]string
+	if err := json.Unmarshal([]byte(rec.Header().Get(HXTrigger)), &payload); err != nil {
+		t.Fatalf("expected valid JSON, got %q: %v", rec.Header().Get(HXTrigger), err)
+	}
+	if len(payload) != 1 || payload["makeToast"]["message"] != message || payload["makeToast"]["level"] != "danger" {
+		t.Errorf("expected only the makeToast event with the original message, got %v", payload)
+	}
+}
@@ -0,0 +1,173 @@
+package i18n
+
+// english holds the English translations, keyed by the Portuguese message
+var english = map[string]string{
+	// Erros da API
+	"a data deve ser hoje ou no futuro":                                    "the date must be today or in the future",
+	"acesso restrito a administradores":                                    "access restricted to administrators",
+	"ao menos um codigo de produto e necessario":                           "at least one product code is required",
+	"area de destino nao encontrada":                                       "target area not found",
+	"area de origem e destino devem ser diferentes":                        "source and target areas must be different",
+	"area nao encontrada":                                                  "area not found",
+	"area repetida no documento":                                           "area repeated in the document",
+	"arquivamento de importacoes desativado":                               "import archiving is disabled",
+	"arquivo CSV vazio":                                                    "empty CSV file",
+	"arquivo nao fornecido":                                                "file not provided",
+	"chave de API invalida ou revogada":                                    "invalid or revoked API key",
+	"chave de API nao encontrada":                                          "API key not found",
+	"chave de API somente leitura":                                         "read-only API key",
+	"chaves de API nao acessam rotas administrativas":                      "API keys can't access admin routes",
+	"claims inválidas":                                                     "invalid claims",
+	"codificacao do CSV invalida, salve o arquivo em UTF-8":                "invalid CSV encoding, save the file as UTF-8",
+	"codigo do produto e obrigatorio":                                      "product code is required",
+	"codigo do produto e obrigatorio (nenhum produto canario configurado)": "product code is required (no canary product configured)",
+	"codigos existem nas duas areas":                                       "codes exist in both areas",
+	"credenciais inválidas":                                                "invalid credentials",
+	"data final deve ser igual ou posterior a data inicial":                "end date must be on or after the start date",
+	"data final invalida, use o formato AAAA-MM-DD":                        "invalid end date, use the YYYY-MM-DD format",
+	"data inicial invalida, use o formato AAAA-MM-DD":                      "invalid start date, use the YYYY-MM-DD format",
+	"data invalida, use o formato AAAA-MM-DD":                              "invalid date, use the YYYY-MM-DD format",
+	"data limite (until) e obrigatoria":                                    "end date (until) is required",
+	"delimitador do CSV invalido, use ponto e virgula, virgula ou tab":     "invalid CSV delimiter, use semicolon, comma or tab",
+	"deve ser maior que zero":                                              "must be greater than zero",
+	"deve ser no maximo 100":                                               "must be at most 100",
+	"deve ser um numero inteiro":                                           "must be an integer",
+	"direcao de ordenacao invalida":                                        "invalid sort order",
+	"documento de configuracao invalido":                                   "invalid configuration document",
+	"email não encontrado":                                                 "email not found",
+	"erro ao abrir arquivo":                                                "error opening file",
+	"erro ao abrir planilha":                                               "error opening spreadsheet",
+	"erro ao armazenar novo token":                                         "error storing new token",
+	"erro ao buscar mudancas de ciclo de vida para exportacao":             "error fetching lifecycle changes for export",
+	"erro ao buscar produtos para exportacao":                              "error fetching products for export",
+	"erro ao coletar produto":                                              "error collecting product",
+	"erro ao criar usuário":                                                "error creating user",
+	"erro ao exportar produtos":                                            "error exporting products",
+	"erro ao gerar CSV":                                                    "error generating CSV",
+	"erro ao gerar access token":                                           "error generating access token",
+	"erro ao gerar chave de API":                                           "error generating API key",
+	"erro ao gerar novo token":                                             "error generating new token",
+	"erro ao gerar planilha":                                               "error generating spreadsheet",
+	"erro ao gerar tokens":                                                 "error generating tokens",
+	"erro ao iniciar a coleta do lifecycle":                                "error starting the lifecycle update run",
+	"erro ao iniciar importacao":                                           "error starting the import",
+	"erro ao inserir dados":                                                "error inserting data",
+	"erro ao ler CSV":                                                      "error reading CSV",
+	"erro ao ler arquivo CSV":                                              "error reading CSV file",
+	"erro ao ler linhas da planilha":                                       "error reading spreadsheet rows",
+	"erro ao listar chaves de API":                                         "error listing API keys",
+	"erro ao listar execucoes de coleta":                                   "error listing collection runs",
+	"erro ao processar ID do usuário":                                      "error processing user ID",
+	"erro ao processar dados":                                              "error processing data",
+	"erro ao processar parametros":                                         "error processing parameters",
+	"erro ao processar senha":                                              "error processing password",
+	"erro ao revogar chave de API":                                         "error revoking API key",
+	"erro ao revogar token":                                                "error revoking token",
+	"erro ao revogar token antigo":                                         "error revoking old token",
+	"erro ao revogar tokens":                                               "error revoking tokens",
+	"erro ao salvar coleta do produto":                                     "error saving product collection",
+	"erro ao salvar importacao":                                            "error saving the import",
+	"erro ao submeter coletas":                                             "error submitting collections",
+	"erro ao validar chave de API":                                         "error validating API key",
+	"erro ao validar token":                                                "error validating token",
+	"erro interno do servidor":                                             "internal server error",
+	"escopo de chave de API invalido, use read ou write":                   "invalid API key scope, use read or write",
+	"falha ao abrir a pagina do produto":                                   "failed to open the product page",
+	"filtro de ciclo de vida nao pode ser combinado com busca fuzzy":       "lifecycle status filter cannot be combined with fuzzy search",
+	"id da area de destino invalido":                                       "invalid target area id",
+	"id da area e obrigatorio":                                             "area id is required",
+	"id da area invalido":                                                  "invalid area id",
+	"id do produto e obrigatorio":                                          "product id is required",
+	"id do produto invalido":                                               "invalid product id",
+	"importacao nao encontrada":                                            "import not found",
+	"informe ao menos um id de produto":                                    "provide at least one product id",
+	"informe ao menos um id ou codigo de produto":                          "provide at least one product id or code",
+	"informe o status ou a data de fim de vida esperados":                  "provide the expected status or end-of-life date",
+	"ja existe uma coleta do lifecycle em andamento":                       "a lifecycle update run is already in progress",
+	"keep_per_product invalido":                                            "invalid keep_per_product",
+	"mapeamento de colunas invalido":                                       "invalid column mapping",
+	"muitas tentativas, tente novamente mais tarde":                        "too many attempts, try again later",
+	"nao e possivel remover uma area que possui produtos":                  "cannot remove an area that has products",
+	"nome da area e obrigatorio":                                           "area name is required",
+	"nome da chave de API e obrigatorio":                                   "API key name is required",
+	"numero de workers invalido":                                           "invalid worker count",
+	"o layout de importacao nao aceita filtros, use layout=full":           "the import layout does not accept filters, use layout=full",
+	"o replay de importacao so e suportado com dry=true":                   "import replay is only supported with dry=true",
+	"ordenacao nao suportada":                                              "unsupported sort",
+	"padrao de codigo invalido":                                            "invalid code pattern",
+	"parametro limit invalido":                                             "invalid limit parameter",
+	"parametros a e b sao obrigatorios":                                    "a and b parameters are required",
+	"parametros de paginacao invalidos":                                    "invalid pagination parameters",
+	"parametros from e to sao obrigatorios":                                "from and to parameters are required",
+	"planilha vazia":                                                       "empty spreadsheet",
+	"produto arquivado nao encontrado":                                     "archived product not found",
+	"produto atualizado recentemente, tente novamente apos":                "product refreshed recently, try again after",
+	"produto nao encontrado":                                               "product not found",
+	"produto nao encontrado no site":                                       "product not found on the site",
+	"recurso nao encontrado":                                               "resource not found",
+	"refresh token inválido":                                               "invalid refresh token",
+	"refresh token inválido ou expirado":                                   "invalid or expired refresh token",
+	"refresh token não encontrado":                                         "refresh token not found",
+	"refresh token reutilizado, faça login novamente":                      "refresh token reused, please log in again",
+	"retencao do html invalida":                                            "invalid html retention",
+	"sessão iniciada em outro dispositivo, faça login novamente":           "session started on another device, please log in again",
+	"status de ciclo de vida invalido":                                     "invalid lifecycle status",
+	"status e obrigatorio":                                                 "status is required",
+	"status esperado invalido":                                             "invalid expected status",
+	"streaming nao suportado":                                              "streaming not supported",
+	"tempo limite da coleta esgotado":                                      "crawl timed out",
+	"token inválido":                                                       "invalid token",
+	"usuario nao autenticado":                                              "user not authenticated",
+	"usuário não autenticado":                                              "user not authenticated",
+	"usuário não encontrado":                                               "user not found",
+	"valor invalido para atomic":                                           "invalid value for atomic",
+	"valor invalido para collect":                                          "invalid value for collect",
+	"valor invalido para columns":                                          "invalid value for columns",
+	"valor invalido para cross_area":                                       "invalid value for cross_area",
+	"valor invalido para detect_header":                                    "invalid value for detect_header",
+	"valor invalido para diff":                                             "invalid value for diff",
+	"valor invalido para dry":                                              "invalid value for dry",
+	"valor invalido para dry_run":                                          "invalid value for dry_run",
+	"valor invalido para duplicates":                                       "invalid value for duplicates",
+	"valor invalido para force":                                            "invalid value for force",
+	"valor invalido para format":                                           "invalid value for format",
+	"valor invalido para layout":                                           "invalid value for layout",
+	"valor invalido para rows":                                             "invalid value for rows",
+	"versao do documento de configuracao nao suportada":                    "unsupported configuration document version",
+	"Erro interno do servidor":                                             "Internal server error",
+
+	// Emails e alertas do scheduler
+	"Mudança de Lifecycle de equipamentos detectada":        "Equipment lifecycle change detected",
+	"Mudanças no Lifecycle Detectadas":                      "Lifecycle Changes Detected",
+	"Os seguintes equipamentos mudaram o lifecycle status:": "The following equipment changed lifecycle status:",
+	"Os seguintes produtos tiveram mudanças no lifecycle:":  "The following products had lifecycle changes:",
+	"Status Antigo":            "Old Status",
+	"Novo Status":              "New Status",
+	"Erro no Scheduler":        "Scheduler Error",
+	"Contexto":                 "Context",
+	"Erro":                     "Error",
+	"Horário":                  "Time",
+	"Lifecycle: %d mudanca(s)": "Lifecycle: %d change(s)",
+	"... e mais %d":            "... and %d more",
+	"Resumo diário de mudanças de Lifecycle":                                "Daily lifecycle changes digest",
+	"Divergência do lifecycle esperado detectada":                           "Expected lifecycle drift detected",
+	"Divergências do Lifecycle Esperado":                                    "Expected Lifecycle Drifts",
+	"Os seguintes produtos divergem do lifecycle esperado pelo fornecedor:": "The following products diverge from the lifecycle expected by the supplier:",
+	"Divergência":                                     "Drift",
+	"Status Coletado":                                 "Crawled Status",
+	"Status Esperado":                                 "Expected Status",
+	"Fim de Vida Esperado":                            "Expected End of Life",
+	"Status mais avançado que o esperado":             "Status further along than expected",
+	"Descontinuado antes da data esperada":            "Discontinued before the expected date",
+	"Ainda ativo após a data esperada de fim de vida": "Still active after the expected end-of-life date",
+	"Substituto":                                      "Replacement",
+	"substituto final":                                "final replacement",
+	"fora do catálogo":                                "not in catalog",
+	"Estoque abaixo do mínimo: %d produto(s)":         "Stock below minimum: %d product(s)",
+	"Estoque Abaixo do Mínimo":                        "Stock Below Minimum",
+	"Os seguintes produtos estão abaixo da quantidade mínima da sua área:": "The following products are below the minimum quantity of their area:",
+	"Área":       "Area",
+	"Descrição":  "Description",
+	"Quantidade": "Quantity",
+	"Mínimo":     "Minimum",
+}
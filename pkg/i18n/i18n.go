@@ -0,0 +1,100 @@
+package i18n
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Locale identifies the language of user-facing messages
+type Locale string
+
+const (
+	Portuguese Locale = "pt"
+	English    Locale = "en"
+
+	// Default is the language messages are written in; its catalog is the message itself
+	Default = Portuguese
+)
+
+// catalogs maps each translated locale to its messages, keyed by the Portuguese text
+var catalogs = map[Locale]map[string]string{
+	English: english,
+}
+
+// ParseLocale converts a config value or language tag ("en", "en-US", "pt_BR") to a supported Locale,
+// defaulting to Portuguese
+func ParseLocale(value string) Locale {
+	if locale, ok := supported(value); ok {
+		return locale
+	}
+	return Default
+}
+
+// FromAcceptLanguage picks the supported locale with the highest q-value in an Accept-Language header,
+// defaulting to Portuguese
+func FromAcceptLanguage(header string) Locale {
+	type candidate struct {
+		locale Locale
+		q      float64
+	}
+
+	var candidates []candidate
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(part, ";")
+		locale, ok := supported(tag)
+		if !ok {
+			continue
+		}
+
+		q := 1.0
+		if value, found := strings.CutPrefix(strings.TrimSpace(params), "q="); found {
+			if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+				q = parsed
+			}
+		}
+		if q > 0 {
+			candidates = append(candidates, candidate{locale: locale, q: q})
+		}
+	}
+
+	if len(candidates) == 0 {
+		return Default
+	}
+
+	// Stable keeps header order for equal q-values
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].q > candidates[j].q })
+	return candidates[0].locale
+}
+
+// supported returns the locale for the primary subtag of tag, if we have messages for it
+func supported(tag string) (Locale, bool) {
+	primary, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(tag)), "-")
+	primary, _, _ = strings.Cut(primary, "_")
+
+	switch locale := Locale(primary); locale {
+	case Portuguese, English:
+		return locale, true
+	default:
+		return "", false
+	}
+}
+
+// T translates a Portuguese message to locale. Messages built as "<message>: <detail>"
+// have only the message part translated. Unknown messages are returned unchanged.
+func T(locale Locale, message string) string {
+	catalog, ok := catalogs[locale]
+	if !ok {
+		return message
+	}
+
+	if translated, ok := catalog[message]; ok {
+		return translated
+	}
+	if prefix, detail, found := strings.Cut(message, ": "); found {
+		if translated, ok := catalog[prefix]; ok {
+			return translated + ": " + detail
+		}
+	}
+	return message
+}
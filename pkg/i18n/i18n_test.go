@@ -0,0 +1,44 @@
+package i18n
+
+import "testing"
+
+func TestFromAcceptLanguage(t *testing.T) {
+	tests := []struct {
+		header   string
+		expected Locale
+	}{
+		{"", Portuguese},
+		{"en-US,en;q=0.9", English},
+		{"pt-BR,pt;q=0.9,en;q=0.8", Portuguese},
+		{"fr-FR,en;q=0.5,pt;q=0.7", Portuguese},
+		{"de-DE,fr;q=0.8", Portuguese},
+		{"pt;q=0,en", English},
+		{"EN_gb", English},
+	}
+
+	for _, tt := range tests {
+		if got := FromAcceptLanguage(tt.header); got != tt.expected {
+			t.Errorf("FromAcceptLanguage(%q) = %q, expected %q", tt.header, got, tt.expected)
+		}
+	}
+}
+
+func TestT(t *testing.T) {
+	tests := []struct {
+		locale   Locale
+		message  string
+		expected string
+	}{
+		{English, "produto nao encontrado", "product not found"},
+		{Portuguese, "produto nao encontrado", "produto nao encontrado"},
+		{English, "erro ao abrir planilha: zip: not a valid zip file", "error opening spreadsheet: zip: not a valid zip file"},
+		{English, "mensagem sem traducao", "mensagem sem traducao"},
+		{"", "produto nao encontrado", "produto nao encontrado"},
+	}
+
+	for _, tt := range tests {
+		if got := T(tt.locale, tt.message); got != tt.expected {
+			t.Errorf("T(%q, %q) = %q, expected %q", tt.locale, tt.message, got, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Webhook posts messages as JSON ({"text": msg}) to a URL.
+// The payload format is accepted by Slack and Teams incoming webhooks.
+type Webhook struct {
+	kind   string
+	client *http.Client
+}
+
+func NewWebhook(timeout time.Duration) *Webhook {
+	return &Webhook{
+		kind:   "webhook",
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+// Send posts msg to the webhook URL in to
+func (w *Webhook) Send(to, msg string) error {
+	body, err := json.Marshal(map[string]string{"text": msg})
+	if err != nil {
+		return err
+	}
+
+	resp, err := w.client.Post(to, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
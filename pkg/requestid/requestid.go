@@ -0,0 +1,33 @@
+// Package requestid carries the X-Request-ID of an HTTP request into the work it starts
+// (worker pool jobs, SSE streams), so their logs can be correlated with the request.
+package requestid
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+)
+
+type ctxKey struct{}
+
+// WithContext returns a copy of ctx carrying id; an empty id returns ctx unchanged
+func WithContext(ctx context.Context, id string) context.Context {
+	if id == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, ctxKey{}, id)
+}
+
+// FromContext returns the request id carried by ctx, or "" when there is none
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(ctxKey{}).(string)
+	return id
+}
+
+// Field is the request_id log field; skipped for work not started by a request (e.g. cron jobs)
+func Field(id string) zap.Field {
+	if id == "" {
+		return zap.Skip()
+	}
+	return zap.String("request_id", id)
+}
@@ -0,0 +1,30 @@
+package requestid
+
+import (
+	"context"
+	"testing"
+
+	"go.uber.org/zap/zapcore"
+)
+
+func TestContext(t *testing.T) {
+	ctx := WithContext(context.Background(), "f3b1c2")
+	if got := FromContext(ctx); got != "f3b1c2" {
+		t.Errorf("expected f3b1c2, got %q", got)
+	}
+	if got := FromContext(context.Background()); got != "" {
+		t.Errorf("expected no id, got %q", got)
+	}
+	if WithContext(context.Background(), "") != context.Background() {
+		t.Errorf("expected an empty id to keep the context")
+	}
+}
+
+func TestField(t *testing.T) {
+	if f := Field(""); f.Type != zapcore.SkipType {
+		t.Errorf("expected an empty id to be skipped, got %v", f.Type)
+	}
+	if f := Field("f3b1c2"); f.Key != "request_id" || f.String != "f3b1c2" {
+		t.Errorf("unexpected field %+v", f)
+	}
+}
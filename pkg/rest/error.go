@@ -1,6 +1,10 @@
 package rest
 
-import "net/http"
+import (
+	"net/http"
+
+	"github.com/freitasmatheusrn/lifecycle-monitor/pkg/i18n"
+)
 
 type ApiErr struct {
 	Message string `json:"message" example:"error trying to process request"`
@@ -10,6 +14,8 @@ type ApiErr struct {
 	Code int `json:"code" example:"500"`
 
 	Causes []Causes `json:"causes"`
+
+	RequestID string `json:"request_id,omitempty"` // X-Request-ID of the failed request, set by the error handler
 }
 
 type Causes struct {
@@ -24,6 +30,20 @@ func (r ApiErr) Error() string {
 	return r.Message
 }
 
+// Localize returns a copy of the error with its messages translated to locale.
+// Constructors take the Portuguese text, which doubles as the translation key.
+func (r *ApiErr) Localize(locale i18n.Locale) *ApiErr {
+	localized := *r
+	localized.Message = i18n.T(locale, r.Message)
+	if len(r.Causes) > 0 {
+		localized.Causes = make([]Causes, len(r.Causes))
+		for i, cause := range r.Causes {
+			localized.Causes[i] = Causes{Field: cause.Field, Message: i18n.T(locale, cause.Message)}
+		}
+	}
+	return &localized
+}
+
 func NewBadRequestError(message string) *ApiErr {
 	return &ApiErr{
 		Message: message,
@@ -79,3 +99,19 @@ func NewForbiddenError(message string) *ApiErr {
 		Code:    http.StatusForbidden,
 	}
 }
+
+func NewConflictError(message string) *ApiErr {
+	return &ApiErr{
+		Message: message,
+		Err:     "conflict",
+		Code:    http.StatusConflict,
+	}
+}
+
+func NewTooManyRequestsError(message string) *ApiErr {
+	return &ApiErr{
+		Message: message,
+		Err:     "too_many_requests",
+		Code:    http.StatusTooManyRequests,
+	}
+}